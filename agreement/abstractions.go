@@ -277,6 +277,10 @@ type Network interface {
 	// associated with the given MessageHandle.
 	Disconnect(MessageHandle)
 
+	// Penalize sends the Network a hint that the peer associated with the
+	// given MessageHandle sent an invalid message, without disconnecting it.
+	Penalize(MessageHandle)
+
 	// Start notifies the network that the agreement service is ready
 	// to start receiving messages.
 	Start()
@@ -58,6 +58,9 @@ const (
 
 	// disk
 	checkpoint
+
+	// network; appended after the other types since the action types are persisted.
+	penalize
 )
 
 type action interface {
@@ -91,7 +94,7 @@ func (a noopAction) String() string {
 type networkAction struct {
 	nonpersistent
 
-	// ignore, broadcast, broadcastVotes, relay, disconnect
+	// ignore, broadcast, broadcastVotes, relay, disconnect, penalize
 	T actionType
 
 	Tag protocol.Tag
@@ -111,7 +114,7 @@ func (a networkAction) t() actionType {
 }
 
 func (a networkAction) String() string {
-	if a.t() == ignore || a.t() == disconnect {
+	if a.t() == ignore || a.t() == disconnect || a.t() == penalize {
 		return fmt.Sprintf("%s: %5v", a.t().String(), a.Err)
 	}
 	if a.Tag == protocol.ProposalPayloadTag {
@@ -164,6 +167,8 @@ func (a networkAction) do(ctx context.Context, s *Service) {
 		s.Network.Relay(a.h, a.Tag, data)
 	case disconnect:
 		s.Network.Disconnect(a.h)
+	case penalize:
+		s.Network.Penalize(a.h)
 	case ignore:
 		// pass
 	}
@@ -401,6 +406,10 @@ func disconnectAction(e messageEvent, err serializableError) action {
 	return networkAction{T: disconnect, Err: err, h: e.Input.MessageHandle}
 }
 
+func penalizeAction(e messageEvent, err serializableError) action {
+	return networkAction{T: penalize, Err: err, h: e.Input.MessageHandle}
+}
+
 func broadcastAction(tag protocol.Tag, o interface{}) action {
 	a := networkAction{T: broadcast, Tag: tag}
 	// TODO would be good to have compiler check this (and related) type switch
@@ -447,7 +456,7 @@ func zeroAction(t actionType) action {
 	switch t {
 	case noop:
 		return noopAction{}
-	case ignore, broadcast, relay, disconnect, broadcastVotes:
+	case ignore, broadcast, relay, disconnect, broadcastVotes, penalize:
 		return networkAction{}
 	case verifyVote, verifyPayload, verifyBundle:
 		return cryptoAction{}
@@ -345,6 +345,9 @@ func (n *NetworkFacade) Disconnect(sender network.Peer) {
 	n.fuzzer.Disconnect(n.nodeID, sourceNode)
 }
 
+func (n *NetworkFacade) Penalize(sender network.Peer) {
+}
+
 func (n *NetworkFacade) Zero() timers.Clock {
 	n.clockSync.Lock()
 	defer n.clockSync.Unlock()
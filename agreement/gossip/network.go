@@ -161,6 +161,16 @@ func (i *networkImpl) Disconnect(h agreement.MessageHandle) {
 	i.net.Disconnect(metadata.raw.Sender)
 }
 
+func (i *networkImpl) Penalize(h agreement.MessageHandle) {
+	metadata := messageMetadataFromHandle(h)
+
+	if metadata == nil { // synthentic loopback
+		return
+	}
+
+	i.net.Penalize(metadata.raw.Sender)
+}
+
 // broadcastTimeout is currently only used by test code.
 // In test code we want to queue up a bunch of outbound packets and then see that they got through, so we need to wait at least a little bit for them to all go out.
 // Normal agreement state machine code uses GossipNode.Broadcast non-blocking and may drop outbound packets.
@@ -136,6 +136,9 @@ func (w *whiteholeNetwork) BroadcastSimple(tag protocol.Tag, data []byte) error
 func (w *whiteholeNetwork) Disconnect(badnode network.Peer) {
 	return
 }
+func (w *whiteholeNetwork) Penalize(badnode network.Peer) {
+	return
+}
 func (w *whiteholeNetwork) DisconnectPeers() {
 	return
 }
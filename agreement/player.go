@@ -541,7 +541,7 @@ func (p *player) handleMessageEvent(r routerHandle, e messageEvent) (actions []a
 		switch ef.t() {
 		case payloadMalformed:
 			err := makeSerErrf("rejected message since it was invalid: %v", ef.(filteredEvent).Err)
-			return append(actions, ignoreAction(e, err))
+			return append(actions, penalizeAction(e, err))
 		case payloadRejected:
 			return append(actions, ignoreAction(e, ef.(payloadProcessedEvent).Err))
 		case payloadPipelined:
@@ -2027,14 +2027,14 @@ func TestPlayerDisconectsFromMalformedProposalVote(t *testing.T) {
 	}), "Player should disconnect due to malformed proposal")
 }
 
-func TestPlayerIgnoresMalformedPayload(t *testing.T) {
+func TestPlayerPenalizesMalformedPayload(t *testing.T) {
 	const r = round(201221)
 	const p = period(0)
 	_, pM, _ := setupP(t, r, p, cert)
 
 	verifyError := makeSerErrStr("test error")
 
-	// check ignore on malformed payloads
+	// check penalize on malformed payloads
 	m := message{
 		MessageHandle:           "uniquemessage",
 		Proposal:                proposal{},
@@ -2053,15 +2053,15 @@ func TestPlayerIgnoresMalformedPayload(t *testing.T) {
 			return false
 		}
 		wrapper := b.(wrappedActionEvent)
-		if wrapper.action.t() != ignore {
+		if wrapper.action.t() != penalize {
 			return false
 		}
 		act := wrapper.action.(networkAction)
-		if act.T == ignore && act.h == m.MessageHandle && act.Err != nil {
+		if act.T == penalize && act.h == m.MessageHandle && act.Err != nil {
 			return true
 		}
 		return false
-	}), "Player should ignore malformed payload")
+	}), "Player should penalize malformed payload")
 }
 
 func TestPlayerDisconectsFromMalformedVotes(t *testing.T) {
@@ -0,0 +1,639 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	generatedV2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/libgoal"
+)
+
+var (
+	appIdx     uint64
+	appCreator string
+
+	approvalProgFile string
+	clearProgFile    string
+
+	approvalProgRawFile string
+	clearProgRawFile    string
+
+	createOnCompletion string
+
+	localSchemaUints      uint64
+	localSchemaByteSlices uint64
+
+	globalSchemaUints      uint64
+	globalSchemaByteSlices uint64
+
+	appArgs     []string
+	appAccounts []string
+	foreignApps []string
+	fetchLocal  bool
+	fetchGlobal bool
+	guessFormat bool
+
+	listAppsLimit uint64
+)
+
+func init() {
+	appCmd.AddCommand(createAppCmd)
+	appCmd.AddCommand(deleteAppCmd)
+	appCmd.AddCommand(updateAppCmd)
+	appCmd.AddCommand(callAppCmd)
+	appCmd.AddCommand(optInAppCmd)
+	appCmd.AddCommand(closeOutAppCmd)
+	appCmd.AddCommand(clearAppCmd)
+	appCmd.AddCommand(readStateAppCmd)
+	appCmd.AddCommand(infoAppCmd)
+	appCmd.AddCommand(listAppCmd)
+
+	appCmd.PersistentFlags().StringVarP(&walletName, "wallet", "w", "", "Set the wallet to be used for the selected operation")
+	appCmd.PersistentFlags().StringArrayVar(&appArgs, "app-arg", nil, "Args to encode for application transactions (all will be encoded to a byte slice). For ints, use the form 'int:1234'. For raw bytes, use the form 'b64:A=='. For printable strings, use the form 'str:hello'. For addresses, use the form 'addr:XYZ...'.")
+	appCmd.PersistentFlags().StringSliceVar(&foreignApps, "foreign-app", nil, "Indexes of other apps whose global state is read in this transaction")
+	appCmd.PersistentFlags().StringSliceVar(&appAccounts, "app-account", nil, "Accounts that may be accessed from application logic")
+
+	// Flags shared by all transaction-issuing subcommands
+	for _, txCmd := range []*cobra.Command{createAppCmd, deleteAppCmd, updateAppCmd, callAppCmd, optInAppCmd, closeOutAppCmd, clearAppCmd} {
+		txCmd.Flags().Uint64Var(&fee, "fee", 0, "The transaction fee (automatically determined by default), in microAlgos")
+		txCmd.Flags().Uint64Var(&firstValid, "firstvalid", 0, "The first round where the transaction may be committed to the ledger")
+		txCmd.Flags().Uint64Var(&numValidRounds, "validrounds", 0, "The number of rounds for which the transaction will be valid")
+		txCmd.Flags().Uint64Var(&lastValid, "lastvalid", 0, "The last round where the transaction may be committed to the ledger")
+		txCmd.Flags().StringVarP(&outFilename, "out", "o", "", "Write transaction to this file")
+		txCmd.Flags().BoolVarP(&sign, "sign", "s", false, "Use with -o to indicate that the dumped transaction should be signed")
+		txCmd.Flags().StringVar(&noteBase64, "noteb64", "", "Note (URL-base64 encoded)")
+		txCmd.Flags().StringVarP(&noteText, "note", "n", "", "Note text (ignored if --noteb64 used also)")
+		txCmd.Flags().StringVarP(&lease, "lease", "x", "", "Lease value (base64, optional): no transaction may also acquire this lease until lastvalid")
+		txCmd.Flags().BoolVarP(&noWaitAfterSend, "no-wait", "N", false, "Don't wait for transaction to commit")
+	}
+
+	// Subcommands that act on an existing application
+	for _, appIDCmd := range []*cobra.Command{deleteAppCmd, updateAppCmd, callAppCmd, optInAppCmd, closeOutAppCmd, clearAppCmd, readStateAppCmd, infoAppCmd} {
+		appIDCmd.Flags().Uint64Var(&appIdx, "app-id", 0, "Application ID")
+		appIDCmd.MarkFlagRequired("app-id")
+	}
+
+	// Subcommands issued by an arbitrary account
+	for _, fromCmd := range []*cobra.Command{deleteAppCmd, updateAppCmd, callAppCmd, optInAppCmd, closeOutAppCmd, clearAppCmd, readStateAppCmd} {
+		fromCmd.Flags().StringVarP(&account, "from", "f", "", "Account to issue the transaction from (if not specified, uses default account)")
+	}
+
+	createAppCmd.Flags().StringVar(&appCreator, "creator", "", "Account to create the application")
+	createAppCmd.Flags().StringVar(&createOnCompletion, "on-completion", "NoOp", "OnCompletion action for application transaction (NoOp or OptIn)")
+	createAppCmd.Flags().Uint64Var(&globalSchemaUints, "global-ints", 0, "Maximum number of integer values that may be stored in the global key/value store. Immutable.")
+	createAppCmd.Flags().Uint64Var(&globalSchemaByteSlices, "global-byteslices", 0, "Maximum number of byte slices that may be stored in the global key/value store. Immutable.")
+	createAppCmd.Flags().Uint64Var(&localSchemaUints, "local-ints", 0, "Maximum number of integer values that may be stored in local (per-account) key/value stores for this app. Immutable.")
+	createAppCmd.Flags().Uint64Var(&localSchemaByteSlices, "local-byteslices", 0, "Maximum number of byte slices that may be stored in local (per-account) key/value stores for this app. Immutable.")
+	createAppCmd.MarkFlagRequired("creator")
+	createAppCmd.MarkFlagRequired("global-ints")
+	createAppCmd.MarkFlagRequired("global-byteslices")
+	createAppCmd.MarkFlagRequired("local-ints")
+	createAppCmd.MarkFlagRequired("local-byteslices")
+
+	for _, progCmd := range []*cobra.Command{createAppCmd, updateAppCmd} {
+		progCmd.Flags().StringVar(&approvalProgFile, "approval-prog", "", "(Uncompiled) TEAL assembly program filename for approving/rejecting transactions")
+		progCmd.Flags().StringVar(&clearProgFile, "clear-prog", "", "(Uncompiled) TEAL assembly program filename for updating application state when a user clears their local state")
+		progCmd.Flags().StringVar(&approvalProgRawFile, "approval-prog-raw", "", "Compiled TEAL program filename for approving/rejecting transactions")
+		progCmd.Flags().StringVar(&clearProgRawFile, "clear-prog-raw", "", "Compiled TEAL program filename for updating application state when a user clears their local state")
+	}
+
+	readStateAppCmd.Flags().BoolVar(&fetchLocal, "local", false, "Fetch account-specific state for this application. `--from` address is required when using this flag")
+	readStateAppCmd.Flags().BoolVar(&fetchGlobal, "global", false, "Fetch global state for this application.")
+	readStateAppCmd.Flags().BoolVar(&guessFormat, "guess-format", false, "Format application state using heuristics to guess data encoding.")
+
+	listAppCmd.Flags().Uint64Var(&listAppsLimit, "limit", 0, "Maximum number of applications to list (all of them by default)")
+}
+
+var appCmd = &cobra.Command{
+	Use:   "app",
+	Short: "Manage applications",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		// If no arguments passed, we should fallback to help
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+// parseAppArg decodes a single typed application argument of the form
+// "type:value" into the bytes passed to the application.
+func parseAppArg(arg string) ([]byte, error) {
+	parts := strings.SplitN(arg, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("app arg %q is not of the form type:value", arg)
+	}
+
+	encoding, value := parts[0], parts[1]
+	switch encoding {
+	case "str":
+		return []byte(value), nil
+	case "int":
+		num, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse uint64 from %q: %v", value, err)
+		}
+		encoded := make([]byte, 8)
+		binary.BigEndian.PutUint64(encoded, num)
+		return encoded, nil
+	case "addr":
+		addr, err := basics.UnmarshalChecksumAddress(value)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse address %q: %v", value, err)
+		}
+		return addr[:], nil
+	case "b64":
+		return base64.StdEncoding.DecodeString(value)
+	default:
+		return nil, fmt.Errorf("unknown encoding %q in app arg %q (expected str, int, addr or b64)", encoding, arg)
+	}
+}
+
+func getAppArgs() [][]byte {
+	if len(appArgs) == 0 {
+		return nil
+	}
+
+	parsed := make([][]byte, len(appArgs))
+	for i, arg := range appArgs {
+		var err error
+		parsed[i], err = parseAppArg(arg)
+		if err != nil {
+			reportErrorf("app-arg[%d]: %v", i, err)
+		}
+	}
+	return parsed
+}
+
+func getForeignApps() []uint64 {
+	var parsed []uint64
+	for _, idx := range foreignApps {
+		aidx, err := strconv.ParseUint(idx, 10, 64)
+		if err != nil {
+			reportErrorf("Could not parse foreign app id %q: %v", idx, err)
+		}
+		parsed = append(parsed, aidx)
+	}
+	return parsed
+}
+
+func getAppAccounts(accountList *AccountsList) []string {
+	var resolved []string
+	for _, acct := range appAccounts {
+		resolved = append(resolved, accountList.getAddressByName(acct))
+	}
+	return resolved
+}
+
+func getAppSchemas() (globalSchema basics.StateSchema, localSchema basics.StateSchema) {
+	globalSchema = basics.StateSchema{
+		NumUint:      globalSchemaUints,
+		NumByteSlice: globalSchemaByteSlices,
+	}
+	localSchema = basics.StateSchema{
+		NumUint:      localSchemaUints,
+		NumByteSlice: localSchemaByteSlices,
+	}
+	return
+}
+
+func mustParseOnCompletion(oc string) transactions.OnCompletion {
+	switch strings.ToLower(oc) {
+	case "noop":
+		return transactions.NoOpOC
+	case "optin":
+		return transactions.OptInOC
+	default:
+		reportErrorf("Unsupported on-completion %q for application creation (expected NoOp or OptIn)", oc)
+	}
+	return transactions.NoOpOC
+}
+
+func readProgram(sourceFile, rawFile, name string) []byte {
+	if sourceFile != "" && rawFile != "" {
+		reportErrorf("Cannot specify both --%s-prog and --%s-prog-raw", name, name)
+	}
+	if sourceFile != "" {
+		return assembleFile(sourceFile)
+	}
+	if rawFile != "" {
+		program, err := readFile(rawFile)
+		if err != nil {
+			reportErrorf(fileReadError, rawFile, err)
+		}
+		return program
+	}
+	reportErrorf("Must specify one of --%s-prog or --%s-prog-raw", name, name)
+	return nil
+}
+
+func getApprovalAndClearPrograms() (approval []byte, clear []byte) {
+	approval = readProgram(approvalProgFile, approvalProgRawFile, "approval")
+	clear = readProgram(clearProgFile, clearProgRawFile, "clear")
+	return
+}
+
+// issueAppTxn fills in the header fields of an application transaction and
+// either broadcasts it, or writes it to outFilename for later grouping or
+// signing.
+func issueAppTxn(cmd *cobra.Command, client libgoal.Client, dataDir string, sender string, tx transactions.Transaction, reportCreated bool) {
+	tx.Note = parseNoteField(cmd)
+	tx.Lease = parseLease(cmd)
+
+	fv, lv, err := client.ComputeValidityRounds(firstValid, lastValid, numValidRounds)
+	if err != nil {
+		reportErrorf("Cannot determine last valid round: %s", err)
+	}
+	tx, err = client.FillUnsignedTxTemplate(sender, fv, lv, fee, tx)
+	if err != nil {
+		reportErrorf("Cannot construct transaction: %s", err)
+	}
+
+	if outFilename != "" {
+		err = writeTxnToFile(client, sign, dataDir, walletName, tx, outFilename)
+		if err != nil {
+			reportErrorf(err.Error())
+		}
+		return
+	}
+
+	wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+	signedTxn, err := client.SignTransactionWithWallet(wh, pw, tx)
+	if err != nil {
+		reportErrorf(errorSigningTX, err)
+	}
+
+	txid, err := client.BroadcastTransaction(signedTxn)
+	if err != nil {
+		reportErrorf(errorBroadcastingTX, err)
+	}
+
+	// Report tx details to user
+	reportInfof("Issued transaction from account %s, txid %s (fee %d)", tx.Sender, txid, tx.Fee.Raw)
+
+	if !noWaitAfterSend {
+		err = waitForCommit(client, txid)
+		if err != nil {
+			reportErrorf(err.Error())
+		}
+
+		if reportCreated {
+			txn, err := client.PendingTransactionInformation(txid)
+			if err != nil {
+				reportErrorf(err.Error())
+			}
+			if txn.TransactionResults != nil && txn.TransactionResults.CreatedAppIndex != 0 {
+				reportInfof("Created app with app index %d", txn.TransactionResults.CreatedAppIndex)
+			}
+		}
+	}
+}
+
+var createAppCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an application",
+	Long:  `Issue a transaction that creates an application`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		checkTxValidityPeriodCmdFlags(cmd)
+
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+		accountList := makeAccountsList(dataDir)
+		creator := accountList.getAddressByName(appCreator)
+
+		onCompletion := mustParseOnCompletion(createOnCompletion)
+		approvalProg, clearProg := getApprovalAndClearPrograms()
+		globalSchema, localSchema := getAppSchemas()
+
+		tx, err := client.MakeUnsignedAppCreateTx(onCompletion, approvalProg, clearProg, globalSchema, localSchema, getAppArgs(), getAppAccounts(accountList), getForeignApps())
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+
+		issueAppTxn(cmd, client, dataDir, creator, tx, true)
+	},
+}
+
+var updateAppCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update an application's programs",
+	Long:  `Issue a transaction that updates an application's ApprovalProgram and ClearStateProgram`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		checkTxValidityPeriodCmdFlags(cmd)
+
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+		accountList := makeAccountsList(dataDir)
+		sender := getAppSender(accountList)
+
+		approvalProg, clearProg := getApprovalAndClearPrograms()
+
+		tx, err := client.MakeUnsignedAppUpdateTx(appIdx, getAppArgs(), getAppAccounts(accountList), getForeignApps(), approvalProg, clearProg)
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+
+		issueAppTxn(cmd, client, dataDir, sender, tx, false)
+	},
+}
+
+var optInAppCmd = &cobra.Command{
+	Use:   "optin",
+	Short: "Opt in to an application",
+	Long:  `Opt an account in to an application, allocating local state in your account`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		runSimpleAppCallCmd(cmd, (*libgoal.Client).MakeUnsignedAppOptInTx)
+	},
+}
+
+var closeOutAppCmd = &cobra.Command{
+	Use:   "closeout",
+	Short: "Close out of an application",
+	Long:  `Close an account out of an application, removing local state from your account. The application must still exist. If it doesn't, use 'goal app clear'.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		runSimpleAppCallCmd(cmd, (*libgoal.Client).MakeUnsignedAppCloseOutTx)
+	},
+}
+
+var clearAppCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear out an application's state in your account",
+	Long:  `Remove any local state from your account associated with an application. The application does not need to exist anymore.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		runSimpleAppCallCmd(cmd, (*libgoal.Client).MakeUnsignedAppClearStateTx)
+	},
+}
+
+var callAppCmd = &cobra.Command{
+	Use:   "call",
+	Short: "Call an application",
+	Long:  `Call an application, invoking application-specific functionality`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		runSimpleAppCallCmd(cmd, (*libgoal.Client).MakeUnsignedAppNoOpTx)
+	},
+}
+
+var deleteAppCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete an application",
+	Long:  `Delete an application, removing the global state and other application parameters from the creator's account`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		runSimpleAppCallCmd(cmd, (*libgoal.Client).MakeUnsignedAppDeleteTx)
+	},
+}
+
+// simpleAppTxnMaker is the signature shared by the libgoal constructors for
+// application calls that do not set programs or schemas
+type simpleAppTxnMaker func(c *libgoal.Client, appIdx uint64, appArgs [][]byte, accounts []string, foreignApps []uint64) (transactions.Transaction, error)
+
+func runSimpleAppCallCmd(cmd *cobra.Command, makeTx simpleAppTxnMaker) {
+	checkTxValidityPeriodCmdFlags(cmd)
+
+	dataDir := ensureSingleDataDir()
+	client := ensureFullClient(dataDir)
+	accountList := makeAccountsList(dataDir)
+	sender := getAppSender(accountList)
+
+	tx, err := makeTx(&client, appIdx, getAppArgs(), getAppAccounts(accountList), getForeignApps())
+	if err != nil {
+		reportErrorf(errorConstructingTX, err)
+	}
+
+	issueAppTxn(cmd, client, dataDir, sender, tx, false)
+}
+
+func getAppSender(accountList *AccountsList) string {
+	// Check if from was specified, else use default
+	if account == "" {
+		account = accountList.getDefaultAccount()
+	}
+	return accountList.getAddressByName(account)
+}
+
+// lookupAppParams fetches the parameters of the given application.
+func lookupAppParams(client libgoal.Client, aidx uint64) generatedV2.ApplicationParams {
+	app, err := client.ApplicationInformation(aidx)
+	if err != nil {
+		reportErrorf(errorRequestFail, err)
+	}
+	return app.Params
+}
+
+// appStateValue is the user-facing representation of a single TEAL value
+type appStateValue struct {
+	Type  string `json:"tt"`
+	Bytes string `json:"tb,omitempty"`
+	Uint  uint64 `json:"ui,omitempty"`
+}
+
+// decodeAppState converts a key/value store returned by the REST API into a
+// map keyed by the decoded key. If guess is set, byte values are rendered as
+// addresses or printable strings where that seems likely to be the intent.
+func decodeAppState(kv *generatedV2.TealKeyValueStore, guess bool) (map[string]appStateValue, error) {
+	state := make(map[string]appStateValue)
+	if kv == nil {
+		return state, nil
+	}
+
+	for _, pair := range *kv {
+		key, err := base64.StdEncoding.DecodeString(pair.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		value := appStateValue{Type: basics.TealType(pair.Value.Type).String()}
+		switch basics.TealType(pair.Value.Type) {
+		case basics.TealUintType:
+			value.Uint = pair.Value.Uint
+		case basics.TealBytesType:
+			raw, err := base64.StdEncoding.DecodeString(pair.Value.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			value.Bytes = formatAppStateBytes(raw, guess)
+		}
+
+		name := string(key)
+		if !guess || !isPrintable(key) {
+			name = base64.StdEncoding.EncodeToString(key)
+		}
+		state[name] = value
+	}
+	return state, nil
+}
+
+func formatAppStateBytes(raw []byte, guess bool) string {
+	if guess {
+		if len(raw) == len(basics.Address{}) {
+			var addr basics.Address
+			copy(addr[:], raw)
+			return addr.String()
+		}
+		if isPrintable(raw) {
+			return string(raw)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func isPrintable(data []byte) bool {
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+var readStateAppCmd = &cobra.Command{
+	Use:   "read",
+	Short: "Read local or global state for an application",
+	Long:  `Read global or local (account-specific) state for an application`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+		accountList := makeAccountsList(dataDir)
+
+		if fetchLocal == fetchGlobal {
+			reportErrorf("Exactly one of --local or --global is required")
+		}
+
+		var kv *generatedV2.TealKeyValueStore
+		if fetchLocal {
+			// Need to know which account to fetch local state from
+			if account == "" {
+				reportErrorf("--from is required when reading local state")
+			}
+			addr := accountList.getAddressByName(account)
+
+			ls, err := client.AccountApplicationInformation(addr, appIdx)
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+			kv = ls.KeyValue
+		} else {
+			params := lookupAppParams(client, appIdx)
+			kv = params.GlobalState
+		}
+
+		state, err := decodeAppState(kv, guessFormat)
+		if err != nil {
+			reportErrorf("Could not decode application state: %v", err)
+		}
+
+		enc, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			reportErrorf("Could not encode application state: %v", err)
+		}
+		fmt.Println(string(enc))
+	},
+}
+
+var infoAppCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Look up current parameters for an application",
+	Long:  `Look up application information stored on the network, such as program hash.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+
+		params := lookupAppParams(client, appIdx)
+		printAppInfo(appIdx, params)
+	},
+}
+
+var listAppCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the applications of the network",
+	Long:  `List the ID and the creator of the applications that currently exist, the most recently created ones first.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+
+		// the applications are fetched a page at a time, until there are no
+		// more of them or the limit is reached.
+		listed := uint64(0)
+		next := ""
+		for listAppsLimit == 0 || listed < listAppsLimit {
+			pageSize := uint64(0)
+			if listAppsLimit != 0 {
+				pageSize = listAppsLimit - listed
+				if pageSize > 100 {
+					pageSize = 100
+				}
+			}
+			resp, err := client.Applications(pageSize, next)
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+			for _, app := range resp.Applications {
+				fmt.Printf("%d\t%s\n", app.Id, app.Params.Creator)
+			}
+			listed += uint64(len(resp.Applications))
+			if resp.NextToken == nil {
+				break
+			}
+			next = *resp.NextToken
+		}
+
+		if listed == 0 {
+			reportInfoln(infoNoApplications)
+		}
+	},
+}
+
+func printAppInfo(aidx uint64, params generatedV2.ApplicationParams) {
+	approvalHash := basics.Address(logic.HashProgram(params.ApprovalProgram))
+	clearHash := basics.Address(logic.HashProgram(params.ClearStateProgram))
+
+	var gsch, lsch basics.StateSchema
+	if params.GlobalStateSchema != nil {
+		gsch = basics.StateSchema{NumUint: params.GlobalStateSchema.NumUint, NumByteSlice: params.GlobalStateSchema.NumByteSlice}
+	}
+	if params.LocalStateSchema != nil {
+		lsch = basics.StateSchema{NumUint: params.LocalStateSchema.NumUint, NumByteSlice: params.LocalStateSchema.NumByteSlice}
+	}
+
+	fmt.Printf("Application ID:        %d\n", aidx)
+	fmt.Printf("Creator:               %s\n", params.Creator)
+	fmt.Printf("Approval hash:         %s\n", approvalHash)
+	fmt.Printf("Clear hash:            %s\n", clearHash)
+	fmt.Printf("Max global byteslices: %d\n", gsch.NumByteSlice)
+	fmt.Printf("Max global integers:   %d\n", gsch.NumUint)
+	fmt.Printf("Max local byteslices:  %d\n", lsch.NumByteSlice)
+	fmt.Printf("Max local integers:    %d\n", lsch.NumUint)
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	generatedV2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+func TestParseAppArg(t *testing.T) {
+	arg, err := parseAppArg("str:hello")
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), arg)
+
+	arg, err = parseAppArg("str:a:b")
+	require.NoError(t, err)
+	require.Equal(t, []byte("a:b"), arg)
+
+	arg, err = parseAppArg("int:258")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 1, 2}, arg)
+
+	var addr basics.Address
+	crypto.RandBytes(addr[:])
+	arg, err = parseAppArg("addr:" + addr.String())
+	require.NoError(t, err)
+	require.Equal(t, addr[:], arg)
+
+	arg, err = parseAppArg("b64:" + base64.StdEncoding.EncodeToString([]byte{1, 2, 3}))
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, arg)
+
+	_, err = parseAppArg("hello")
+	require.Error(t, err)
+
+	_, err = parseAppArg("int:-1")
+	require.Error(t, err)
+
+	_, err = parseAppArg("addr:notanaddress")
+	require.Error(t, err)
+
+	_, err = parseAppArg("hex:0102")
+	require.Error(t, err)
+}
+
+func TestDecodeAppState(t *testing.T) {
+	var addr basics.Address
+	crypto.RandBytes(addr[:])
+
+	b64 := base64.StdEncoding.EncodeToString
+	kv := generatedV2.TealKeyValueStore{
+		{Key: b64([]byte("counter")), Value: generatedV2.TealValue{Type: uint64(basics.TealUintType), Uint: 7}},
+		{Key: b64([]byte("owner")), Value: generatedV2.TealValue{Type: uint64(basics.TealBytesType), Bytes: b64(addr[:])}},
+	}
+
+	state, err := decodeAppState(&kv, false)
+	require.NoError(t, err)
+	require.Equal(t, appStateValue{Type: "u", Uint: 7}, state[b64([]byte("counter"))])
+	require.Equal(t, appStateValue{Type: "b", Bytes: b64(addr[:])}, state[b64([]byte("owner"))])
+
+	state, err = decodeAppState(&kv, true)
+	require.NoError(t, err)
+	require.Equal(t, appStateValue{Type: "u", Uint: 7}, state["counter"])
+	require.Equal(t, appStateValue{Type: "b", Bytes: addr.String()}, state["owner"])
+
+	state, err = decodeAppState(nil, true)
+	require.NoError(t, err)
+	require.Empty(t, state)
+}
@@ -27,6 +27,8 @@ import (
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	v2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2"
+	generatedV2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
@@ -57,12 +59,18 @@ var (
 	programSource   string
 	argB64Strings   []string
 	disassemble     bool
+	writeSourceMap  bool
+	analyzeRaw      bool
+	analyzeApp      bool
 	progByteFile    string
 	logicSigFile    string
-	timeStamp       int64
 	protoVersion    string
 	rekeyToAddress  string
 	signerAddress   string
+
+	dryrunStateFile  string
+	dryrunDump       bool
+	dryrunDumpFormat string
 )
 
 func init() {
@@ -73,7 +81,9 @@ func init() {
 	clerkCmd.AddCommand(groupCmd)
 	clerkCmd.AddCommand(splitCmd)
 	clerkCmd.AddCommand(compileCmd)
+	clerkCmd.AddCommand(analyzeCmd)
 	clerkCmd.AddCommand(dryrunCmd)
+	clerkCmd.AddCommand(dryrunRemoteCmd)
 
 	// Wallet to be used for the clerk operation
 	clerkCmd.PersistentFlags().StringVarP(&walletName, "wallet", "w", "", "Set the wallet to be used for the selected operation")
@@ -130,13 +140,28 @@ func init() {
 
 	compileCmd.Flags().BoolVarP(&disassemble, "disassemble", "D", false, "disassemble a compiled program")
 	compileCmd.Flags().BoolVarP(&noProgramOutput, "no-out", "n", false, "don't write contract program binary")
+	compileCmd.Flags().BoolVarP(&writeSourceMap, "map", "m", false, "write a source map of the program to the output filename with a .map suffix")
 	compileCmd.Flags().BoolVarP(&signProgram, "sign", "s", false, "sign program, output is a binary signed LogicSig record")
 	compileCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Filename to write program bytes or signed LogicSig to")
 	compileCmd.Flags().StringVarP(&account, "account", "a", "", "Account address to sign the program (If not specified, uses default account)")
 
+	analyzeCmd.Flags().BoolVar(&analyzeRaw, "raw", false, "programs are compiled bytecode instead of TEAL source")
+	analyzeCmd.Flags().BoolVar(&analyzeApp, "app", false, "programs are application ApprovalPrograms or ClearStatePrograms instead of LogicSigs")
+	analyzeCmd.Flags().StringVarP(&protoVersion, "proto", "P", "", "consensus protocol version id string")
+	analyzeCmd.Flags().Uint64Var(&globalSchemaUints, "global-ints", 0, "Number of integer values the application may store in its global key/value store")
+	analyzeCmd.Flags().Uint64Var(&globalSchemaByteSlices, "global-byteslices", 0, "Number of byte slices the application may store in its global key/value store")
+	analyzeCmd.Flags().Uint64Var(&localSchemaUints, "local-ints", 0, "Number of integer values the application may store in local (per-account) key/value stores")
+	analyzeCmd.Flags().Uint64Var(&localSchemaByteSlices, "local-byteslices", 0, "Number of byte slices the application may store in local (per-account) key/value stores")
+
 	dryrunCmd.Flags().StringVarP(&txFilename, "txfile", "t", "", "transaction or transaction-group to test")
 	dryrunCmd.Flags().StringVarP(&protoVersion, "proto", "P", "", "consensus protocol version id string")
-	dryrunCmd.MarkFlagRequired("txfile")
+	dryrunCmd.Flags().StringVar(&dryrunStateFile, "dryrun-state", "", "dryrun request file supplying the ledger state for application calls (as written by --dryrun-dump)")
+	dryrunCmd.Flags().BoolVar(&dryrunDump, "dryrun-dump", false, "write the dryrun request, including the ledger state fetched from the node, to --outfile instead of evaluating it")
+	dryrunCmd.Flags().StringVar(&dryrunDumpFormat, "dryrun-dump-format", "json", "dryrun request format: json or msgp")
+	dryrunCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Filename for writing the dryrun request")
+
+	dryrunRemoteCmd.Flags().StringVarP(&dryrunStateFile, "dryrun-state", "D", "", "dryrun request file to send to the node, json or msgp encoded")
+	dryrunRemoteCmd.MarkFlagRequired("dryrun-state")
 }
 
 var clerkCmd = &cobra.Command{
@@ -784,15 +809,21 @@ var splitCmd = &cobra.Command{
 }
 
 func assembleFile(fname string) (program []byte) {
+	program, _ = assembleFileWithMap(fname)
+	return program
+}
+
+func assembleFileWithMap(fname string) (program []byte, sm logic.SourceMap) {
 	text, err := readFile(fname)
 	if err != nil {
 		reportErrorf("%s: %s\n", fname, err)
 	}
-	program, err = logic.AssembleString(string(text))
+	program, sm, err = logic.AssembleStringWithSourceMap(string(text))
 	if err != nil {
 		reportErrorf("%s: %s\n", fname, err)
 	}
-	return program
+	sm.Sources = []string{fname}
+	return program, sm
 }
 
 func disassembleFile(fname, outname string) {
@@ -841,7 +872,7 @@ var compileCmd = &cobra.Command{
 				disassembleFile(fname, outFilename)
 				continue
 			}
-			program := assembleFile(fname)
+			program, sm := assembleFileWithMap(fname)
 			outblob := program
 			outname := outFilename
 			if outname == "" {
@@ -851,6 +882,9 @@ var compileCmd = &cobra.Command{
 					outname = fmt.Sprintf("%s.tok", fname)
 				}
 			}
+			if writeSourceMap && outname == stdoutFilenameValue {
+				reportErrorln("--map requires an output filename to derive the map filename from")
+			}
 			if signProgram {
 				dataDir := ensureSingleDataDir()
 				accountList := makeAccountsList(dataDir)
@@ -880,6 +914,14 @@ var compileCmd = &cobra.Command{
 					reportErrorf("%s: %s\n", outname, err)
 				}
 			}
+			if writeSourceMap {
+				sm.File = filepath.Base(outname)
+				mapname := fmt.Sprintf("%s.map", outname)
+				err := writeFile(mapname, protocol.EncodeJSON(&sm), 0666)
+				if err != nil {
+					reportErrorf("%s: %s\n", mapname, err)
+				}
+			}
 			if !signProgram && outname != stdoutFilenameValue {
 				pd := logic.HashProgram(program)
 				addr := basics.Address(pd)
@@ -892,62 +934,370 @@ var compileCmd = &cobra.Command{
 var dryrunCmd = &cobra.Command{
 	Use:   "dryrun",
 	Short: "test a program offline",
-	Long:  "Test a TEAL program offline under various conditions and verbosity.",
+	Long:  "Test a TEAL program offline under various conditions and verbosity. Application calls are evaluated against the ledger state supplied with --dryrun-state or, if none is given, fetched from the node.",
 	Run: func(cmd *cobra.Command, args []string) {
-		data, err := readFile(txFilename)
-		if err != nil {
-			reportErrorf(fileReadError, txFilename, err)
+		if txFilename == "" && dryrunStateFile == "" {
+			reportErrorf("At least one of --txfile or --dryrun-state is required")
 		}
-		dec := protocol.NewDecoderBytes(data)
-		stxns := make([]transactions.SignedTxn, 0, 10)
-		for {
-			var txn transactions.SignedTxn
-			err = dec.Decode(&txn)
-			if err == io.EOF {
-				break
+
+		var dr v2.DryrunRequest
+		if dryrunStateFile != "" {
+			dr = readDryrunRequest(dryrunStateFile)
+		}
+		if txFilename != "" {
+			dr.Txns = readTxnGroup(txFilename)
+		}
+
+		if protoVersion == "" {
+			protoVersion = dr.ProtocolVersion
+		}
+		cvers, params := getProto(protoVersion)
+		dr.ProtocolVersion = string(cvers)
+
+		if dryrunStateFile == "" && hasAppCall(dr.Txns) {
+			fetchDryrunState(&dr)
+		}
+		if dr.LatestTimestamp <= 0 {
+			dr.LatestTimestamp = time.Now().Unix()
+		}
+
+		if dryrunDump {
+			if outFilename == "" {
+				reportErrorf("--outfile is required with --dryrun-dump")
+			}
+			var data []byte
+			switch dryrunDumpFormat {
+			case "json":
+				data = protocol.EncodeJSON(&dr)
+			case "msgp":
+				data = protocol.EncodeReflect(&dr)
+			default:
+				reportErrorf("Unsupported dryrun dump format: %s", dryrunDumpFormat)
 			}
+			err := writeFile(outFilename, data, 0600)
 			if err != nil {
-				reportErrorf(txDecodeError, txFilename, err)
+				reportErrorf(fileWriteError, outFilename, err)
 			}
-			stxns = append(stxns, txn)
+			return
 		}
-		txgroup := make([]transactions.SignedTxn, len(stxns))
-		for i, st := range stxns {
-			txgroup[i] = st
+
+		response := v2.RunDryrunRequest(&dr, &params)
+		if response.Error != "" {
+			reportErrorf("dryrun failed: %s", response.Error)
 		}
-		if timeStamp <= 0 {
-			timeStamp = time.Now().Unix()
+		printDryrunResponse(response)
+	},
+}
+
+var dryrunRemoteCmd = &cobra.Command{
+	Use:   "dryrun-remote",
+	Short: "test a program with algod's dryrun REST endpoint",
+	Long:  "Test a TEAL program with algod's dryrun REST endpoint under various conditions and verbosity. The node must have EnableDeveloperAPI set.",
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := readFile(dryrunStateFile)
+		if err != nil {
+			reportErrorf(fileReadError, dryrunStateFile, err)
 		}
-		_, params := getProto(protoVersion)
-		for i, txn := range txgroup {
-			if txn.Lsig.Blank() {
-				continue
+
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+		response, err := client.Dryrun(data)
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		if response.Error != "" {
+			reportErrorf("dryrun failed: %s", response.Error)
+		}
+		printDryrunResponse(response)
+	},
+}
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze [program files]",
+	Short: "statically analyze contract programs",
+	Long:  "Reads TEAL contract programs and reports the cost of their paths and problems found without running them: unreachable code, stack underflows and overflows, unused scratch slots, state keys exceeding the schema, and LogicSigs not checking the RekeyTo and close-to fields. Exits with an error if any problem is found.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, proto := getProto(protoVersion)
+		params := logic.AnalyzeParams{Proto: &proto, Stateful: analyzeApp}
+		if cmd.Flags().Changed("global-ints") || cmd.Flags().Changed("global-byteslices") {
+			params.GlobalSchema = &basics.StateSchema{NumUint: globalSchemaUints, NumByteSlice: globalSchemaByteSlices}
+		}
+		if cmd.Flags().Changed("local-ints") || cmd.Flags().Changed("local-byteslices") {
+			params.LocalSchema = &basics.StateSchema{NumUint: localSchemaUints, NumByteSlice: localSchemaByteSlices}
+		}
+
+		findings := 0
+		for _, fname := range args {
+			findings += analyzeFile(fname, params)
+		}
+		if findings > 0 {
+			reportErrorf("%d problems found", findings)
+		}
+	},
+}
+
+// analyzeFile prints the analysis of a program and returns the number of problems found
+func analyzeFile(fname string, params logic.AnalyzeParams) int {
+	var program []byte
+	var pcToLocation map[int]logic.SourceLocation
+	if analyzeRaw {
+		var err error
+		program, err = readFile(fname)
+		if err != nil {
+			reportErrorf(fileReadError, fname, err)
+		}
+	} else {
+		var sm logic.SourceMap
+		program, sm = assembleFileWithMap(fname)
+		pcToLocation, _ = sm.PCToLocation()
+	}
+
+	analysis, err := logic.Analyze(program, params)
+	if err != nil {
+		reportErrorf("%s: %s", fname, err)
+	}
+
+	where := func(pc int) string {
+		if pc < 0 {
+			return fname
+		}
+		if loc, ok := pcToLocation[pc]; ok {
+			return fmt.Sprintf("%s:%d", fname, loc.Line+1)
+		}
+		return fmt.Sprintf("%s pc %d", fname, pc)
+	}
+
+	maxCost := fmt.Sprintf("%d", analysis.MaxCost)
+	if analysis.HasLoops {
+		maxCost = fmt.Sprintf("at least %d plus loops", analysis.MaxCost)
+	}
+	fmt.Printf("%s: version %d, %d blocks, max cost %s, max stack depth %d\n", fname, analysis.Version, len(analysis.Blocks), maxCost, analysis.MaxStackDepth)
+	for _, path := range analysis.Paths {
+		exit := fmt.Sprintf("%s at %s", path.Op, where(path.Exit))
+		if path.Op == "end" {
+			exit = "end of program"
+		}
+		fmt.Printf("  %s: worst case cost %d\n", exit, path.Cost)
+	}
+	for _, finding := range analysis.Findings {
+		fmt.Printf("%s: %s: %s\n", where(finding.PC), finding.Kind, finding.Message)
+	}
+	return len(analysis.Findings)
+}
+
+func readTxnGroup(filename string) []transactions.SignedTxn {
+	data, err := readFile(filename)
+	if err != nil {
+		reportErrorf(fileReadError, filename, err)
+	}
+	dec := protocol.NewDecoderBytes(data)
+	stxns := make([]transactions.SignedTxn, 0, 10)
+	for {
+		var txn transactions.SignedTxn
+		err = dec.Decode(&txn)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			reportErrorf(txDecodeError, filename, err)
+		}
+		stxns = append(stxns, txn)
+	}
+	return stxns
+}
+
+func readDryrunRequest(filename string) v2.DryrunRequest {
+	data, err := readFile(filename)
+	if err != nil {
+		reportErrorf(fileReadError, filename, err)
+	}
+	dr, err := v2.DecodeDryrunRequest(data)
+	if err != nil {
+		reportErrorf("Cannot decode dryrun request from %s: %s", filename, err)
+	}
+	return dr
+}
+
+func hasAppCall(stxns []transactions.SignedTxn) bool {
+	for _, stxn := range stxns {
+		if stxn.Txn.Type == protocol.ApplicationCallTx {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchDryrunState fills in the accounts, round and timestamp that the
+// application calls in dr may read, as currently seen by the node
+func fetchDryrunState(dr *v2.DryrunRequest) {
+	dataDir := ensureSingleDataDir()
+	client := ensureAlgodClient(dataDir)
+
+	var addrs []basics.Address
+	seen := make(map[basics.Address]bool)
+	addAddr := func(addr basics.Address) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	apps := make(map[uint64]bool)
+	for _, stxn := range dr.Txns {
+		if stxn.Txn.Type != protocol.ApplicationCallTx {
+			continue
+		}
+		addAddr(stxn.Txn.Sender)
+		for _, addr := range stxn.Txn.Accounts {
+			addAddr(addr)
+		}
+		if stxn.Txn.ApplicationID != 0 {
+			apps[uint64(stxn.Txn.ApplicationID)] = true
+		}
+		for _, aidx := range stxn.Txn.ForeignApps {
+			apps[uint64(aidx)] = true
+		}
+	}
+
+	for _, addr := range addrs {
+		info, err := client.AccountInformationV2(addr.String())
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		dr.Accounts = append(dr.Accounts, info)
+		if info.CreatedApps != nil {
+			for _, app := range *info.CreatedApps {
+				delete(apps, app.Id)
 			}
-			ep := logic.EvalParams{Txn: &txn, Proto: &params}
-			cost, err := logic.Check(txn.Lsig.Logic, ep)
-			if err != nil {
-				reportErrorf("program failed Check: %s", err)
-			}
-			sb := strings.Builder{}
-			ep = logic.EvalParams{
-				Txn:        &txn,
-				Proto:      &params,
-				Trace:      &sb,
-				TxnGroup:   txgroup,
-				GroupIndex: i,
-			}
-			pass, err := logic.Eval(txn.Lsig.Logic, ep)
-			// TODO: optionally include `inspect` output here?
-			fmt.Fprintf(os.Stdout, "tx[%d] cost=%d trace:\n%s\n", i, cost, sb.String())
-			if pass {
-				fmt.Fprintf(os.Stdout, " - pass -\n")
-			} else {
-				fmt.Fprintf(os.Stdout, "REJECT\n")
+		}
+	}
+	for aidx := range apps {
+		app, err := client.ApplicationInformation(aidx)
+		if err != nil {
+			reportWarnf("Could not fetch app %d: %v; supply its state with --dryrun-state", aidx, err)
+			continue
+		}
+		dr.Apps = append(dr.Apps, app)
+	}
+
+	stat, err := client.Status()
+	if err != nil {
+		reportErrorf(errorRequestFail, err)
+	}
+	dr.Round = stat.LastRound + 1
+	block, err := client.Block(stat.LastRound)
+	if err == nil {
+		dr.LatestTimestamp = block.Timestamp
+	}
+}
+
+func printDryrunResponse(response generatedV2.DryrunResponse) {
+	for i, result := range response.Txns {
+		if result.LogicSigMessages != nil {
+			disassembly := result.Disassembly
+			if result.LogicSigDisassembly != nil {
+				disassembly = *result.LogicSigDisassembly
 			}
-			if err != nil {
-				fmt.Fprintf(os.Stdout, "ERROR: %s\n", err.Error())
+			fmt.Fprintf(os.Stdout, "tx[%d] LogicSig cost=%s trace:\n", i, dryrunCost(result.LogicSigCost))
+			printDryrunTrace(disassembly, result.LogicSigTrace)
+			printDryrunMessages(*result.LogicSigMessages)
+		}
+		if result.AppCallMessages != nil {
+			messages := *result.AppCallMessages
+			program := "App"
+			if result.AppCallTrace != nil && len(messages) > 0 {
+				program, messages = messages[0], messages[1:]
+			}
+			fmt.Fprintf(os.Stdout, "tx[%d] %s cost=%s trace:\n", i, program, dryrunCost(result.AppCallCost))
+			printDryrunTrace(result.Disassembly, result.AppCallTrace)
+			if result.GlobalDelta != nil {
+				fmt.Fprintf(os.Stdout, "global delta:\n")
+				printDryrunStateDelta(*result.GlobalDelta)
+			}
+			if result.LocalDeltas != nil {
+				for _, ld := range *result.LocalDeltas {
+					fmt.Fprintf(os.Stdout, "local delta for %s:\n", ld.Address)
+					printDryrunStateDelta(ld.Delta)
+				}
 			}
+			printDryrunMessages(messages)
 		}
+	}
+}
 
-	},
+func dryrunCost(cost *uint64) string {
+	if cost == nil {
+		return "?"
+	}
+	return fmt.Sprintf("%d", *cost)
+}
+
+func printDryrunTrace(disassembly []string, trace *[]generatedV2.DryrunState) {
+	if trace == nil {
+		return
+	}
+	for _, state := range *trace {
+		var line string
+		if state.Line < uint64(len(disassembly)) {
+			line = disassembly[state.Line]
+		}
+		stack := make([]string, len(state.Stack))
+		for i, tv := range state.Stack {
+			stack[i] = formatDryrunTealValue(tv)
+		}
+		fmt.Fprintf(os.Stdout, "%4d %-30s [%s]\n", state.Pc, line, strings.Join(stack, ", "))
+		if state.Error != nil {
+			fmt.Fprintf(os.Stdout, "%4d %s\n", state.Pc, *state.Error)
+		}
+	}
+}
+
+func formatDryrunTealValue(tv generatedV2.TealValue) string {
+	if basics.TealType(tv.Type) == basics.TealBytesType {
+		raw, err := base64.StdEncoding.DecodeString(tv.Bytes)
+		if err != nil {
+			return tv.Bytes
+		}
+		return fmt.Sprintf("0x%x", raw)
+	}
+	return fmt.Sprintf("%d", tv.Uint)
+}
+
+func printDryrunStateDelta(delta generatedV2.StateDelta) {
+	for _, kv := range delta {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			key = []byte(kv.Key)
+		}
+		var value string
+		switch basics.DeltaAction(kv.Value.Action) {
+		case basics.SetUintAction:
+			if kv.Value.Uint != nil {
+				value = fmt.Sprintf("%d", *kv.Value.Uint)
+			}
+		case basics.SetBytesAction:
+			if kv.Value.Bytes != nil {
+				raw, err := base64.StdEncoding.DecodeString(*kv.Value.Bytes)
+				if err == nil {
+					value = formatAppStateBytes(raw, true)
+				}
+			}
+		case basics.DeleteAction:
+			value = "(deleted)"
+		}
+		fmt.Fprintf(os.Stdout, "  %s: %s\n", formatAppStateBytes(key, true), value)
+	}
+}
+
+func printDryrunMessages(messages []string) {
+	for _, msg := range messages {
+		switch msg {
+		case "PASS":
+			fmt.Fprintf(os.Stdout, " - pass -\n")
+		case "REJECT":
+			fmt.Fprintf(os.Stdout, "REJECT\n")
+		default:
+			fmt.Fprintf(os.Stdout, "ERROR: %s\n", msg)
+		}
+	}
 }
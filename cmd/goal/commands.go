@@ -74,6 +74,9 @@ func init() {
 	// asset.go
 	rootCmd.AddCommand(assetCmd)
 
+	// application.go
+	rootCmd.AddCommand(appCmd)
+
 	// node.go
 	rootCmd.AddCommand(nodeCmd)
 
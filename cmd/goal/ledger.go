@@ -19,10 +19,19 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/spf13/cobra"
 
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/snapshot"
+	"github.com/algorand/go-algorand/libgoal"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/node"
 	"github.com/algorand/go-algorand/protocol/transcode"
 )
 
@@ -31,11 +40,24 @@ var (
 	rawBlock       bool
 	base32Encoding bool
 	strictJSON     bool
+	fixLedger      bool
+	exportRound    uint64
+	exportFormat   string
+	exportFilename string
 )
 
 func init() {
 	ledgerCmd.AddCommand(supplyCmd)
 	ledgerCmd.AddCommand(blockCmd)
+	ledgerCmd.AddCommand(verifyCmd)
+	ledgerCmd.AddCommand(exportLedgerCmd)
+
+	verifyCmd.Flags().BoolVar(&fixLedger, "fix", false, "Repair the tracker database when it doesn't match the blocks")
+
+	exportLedgerCmd.Flags().Uint64VarP(&exportRound, "round", "r", 0, "The round to export the accounts at (if not set, use the latest round of the tracker database)")
+	exportLedgerCmd.Flags().StringVarP(&exportFormat, "format", "f", string(snapshot.FormatJSONL), "The format of the snapshot: jsonl, csv or columnar")
+	exportLedgerCmd.Flags().StringVarP(&exportFilename, "out", "o", "", "The file to write the snapshot to, or the directory for the csv format")
+	exportLedgerCmd.MarkFlagRequired("out")
 
 	blockCmd.Flags().StringVarP(&blockFilename, "out", "o", stdoutFilenameValue, "The filename to dump the block to (if not set, use stdout)")
 	blockCmd.Flags().BoolVarP(&rawBlock, "raw", "r", false, "Format block as msgpack")
@@ -110,3 +132,89 @@ var blockCmd = &cobra.Command{
 		}
 	},
 }
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the ledger of a stopped node",
+	Long:  "Verify the ledger of a stopped node, without network access. The blocks are replayed from the genesis when the node holds them all, and the account totals, the accounts merkle trie root and the catchpoint labels are recomputed and compared with the tracker database. With --fix, the tracker database is repaired, and rebuilt from the blocks if needed; the original one is kept with a .bak suffix.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+
+		// Ensure the node is stopped -- HealthCheck should fail
+		clientConfig := libgoal.ClientConfig{
+			AlgodDataDir: dataDir,
+			KMDDataDir:   resolveKmdDataDir(dataDir),
+			CacheDir:     ensureCacheDir(dataDir),
+		}
+		client, err := libgoal.MakeClientFromConfig(clientConfig, libgoal.AlgodClient)
+		if err == nil && client.HealthCheck() == nil {
+			reportErrorln(errLedgerNodeRunning)
+		}
+
+		cfg, err := config.LoadConfigFromDisk(dataDir)
+		if err != nil && !os.IsNotExist(err) {
+			reportErrorf(errLoadingConfig, dataDir, err)
+		}
+		genesisFile := filepath.Join(dataDir, config.GenesisJSONFile)
+		genesis, err := bookkeeping.LoadGenesisFromFile(genesisFile)
+		if err != nil {
+			reportErrorf(errLoadingGenesis, genesisFile, err)
+		}
+
+		log := logging.NewLogger()
+		log.SetOutput(os.Stderr)
+		log.SetLevel(logging.Warn)
+		report, err := node.VerifyLedger(log, dataDir, cfg, genesis, fixLedger)
+		if err != nil {
+			reportErrorf(errVerifyingLedger, err)
+		}
+
+		reportInfof(infoLedgerBlocks, report.EarliestBlock, report.LatestBlock)
+		reportInfof(infoLedgerTracker, report.Accounts, report.TrackerRound)
+		if report.EarliestBlock == 0 {
+			reportInfof(infoLedgerReplayed, report.Replayed)
+		} else {
+			reportInfoln(infoLedgerNotReplayed)
+		}
+		reportInfof(infoLedgerCatchpoints, report.CatchpointLabels)
+		for _, mismatch := range report.Mismatches {
+			reportWarnln(mismatch)
+		}
+
+		switch {
+		case report.Repaired:
+			reportInfoln(infoLedgerRepaired)
+		case len(report.Mismatches) > 0:
+			reportErrorln(errLedgerMismatches)
+		default:
+			reportInfoln(infoLedgerVerified)
+		}
+	},
+}
+
+var exportLedgerCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the state of the accounts at a round",
+	Long:  "Export the state of the accounts held by the ledger of the node at a committed round: their balances and participation keys, their asset holdings, the parameters of the assets and applications they created, and their local states in applications. The node may be running. Rounds older than the latest one of the tracker database are only available on archival nodes that have EnableAccountHistory set. The jsonl format is a single file holding a JSON record per line, the csv format a directory holding a CSV file per kind of record, and the columnar format a single msgpack file holding the columns of each kind of record. A snapshot can seed the genesis of a private network, see the Snapshot field of the network templates.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+
+		format, err := snapshot.ParseFormat(exportFormat)
+		if err != nil {
+			reportErrorln(err)
+		}
+		genesisFile := filepath.Join(dataDir, config.GenesisJSONFile)
+		genesis, err := bookkeeping.LoadGenesisFromFile(genesisFile)
+		if err != nil {
+			reportErrorf(errLoadingGenesis, genesisFile, err)
+		}
+
+		hdr, accounts, err := node.ExportLedger(dataDir, genesis, basics.Round(exportRound), exportFilename, format)
+		if err != nil {
+			reportErrorf(errExportingLedger, err)
+		}
+		reportInfof(infoLedgerExported, accounts, hdr.Round, exportFilename)
+	},
+}
@@ -181,4 +181,20 @@ const (
 	errParsingRoundNumber  = "Error parsing round number: %s"
 	errBadBlockArgs        = "Cannot combine --b32=true or --strict=true with --raw"
 	errEncodingBlockAsJSON = "Error encoding block as json: %s"
+	errLedgerNodeRunning   = "Node must be stopped before verifying its ledger"
+	errLoadingGenesis      = "Error loading the genesis file from '%s': %v"
+	errVerifyingLedger     = "Error verifying the ledger: %v"
+	errLedgerMismatches    = "The ledger doesn't match its blocks; rerun with --fix to repair it"
+	infoLedgerBlocks       = "Blocks: %d to %d"
+	infoLedgerTracker      = "Accounts: %d at round %d"
+	infoLedgerReplayed     = "Blocks replayed from the genesis up to round %d"
+	infoLedgerNotReplayed  = "Blocks not replayed: the ledger doesn't hold the blocks from the genesis"
+	infoLedgerCatchpoints  = "Catchpoint labels checked: %d"
+	infoLedgerVerified     = "The ledger is consistent"
+	infoLedgerRepaired     = "The ledger was repaired"
+	errExportingLedger     = "Error exporting the ledger: %v"
+	infoLedgerExported     = "Exported %d accounts at round %d to %s"
+
+	// Application
+	infoNoApplications = "No applications found."
 )
@@ -100,6 +100,16 @@ func assetParamsFieldsMarkdown(out io.Writer) {
 	fieldTableMarkdown(out, logic.AssetParamsFieldNames, logic.AssetParamsFieldTypes, logic.AssetParamsFieldDocs)
 }
 
+func appParamsFieldsMarkdown(out io.Writer) {
+	fmt.Fprintf(out, "\n`app_params_get` Fields:\n\n")
+	fieldTableMarkdown(out, logic.AppParamsFieldNames, logic.AppParamsFieldTypes, logic.AppParamsFieldDocs)
+}
+
+func acctParamsFieldsMarkdown(out io.Writer) {
+	fmt.Fprintf(out, "\n`acct_params_get` Fields:\n\n")
+	fieldTableMarkdown(out, logic.AcctParamsFieldNames, logic.AcctParamsFieldTypes, logic.AcctParamsFieldDocs)
+}
+
 func opToMarkdown(out io.Writer, op *logic.OpSpec) (err error) {
 	ws := ""
 	opextra := logic.OpImmediateNote(op.Name)
@@ -157,6 +167,10 @@ func opToMarkdown(out io.Writer, op *logic.OpSpec) (err error) {
 		assetHoldingFieldsMarkdown(out)
 	} else if op.Name == "asset_params_get" {
 		assetParamsFieldsMarkdown(out)
+	} else if op.Name == "app_params_get" {
+		appParamsFieldsMarkdown(out)
+	} else if op.Name == "acct_params_get" {
+		acctParamsFieldsMarkdown(out)
 	}
 	ode := logic.OpDocExtra(op.Name)
 	if ode != "" {
@@ -303,6 +317,14 @@ func main() {
 	fieldTableMarkdown(assetparams, logic.AssetParamsFieldNames, logic.AssetParamsFieldTypes, logic.AssetParamsFieldDocs)
 	assetparams.Close()
 
+	appparams, _ := os.Create("app_params_fields.md")
+	fieldTableMarkdown(appparams, logic.AppParamsFieldNames, logic.AppParamsFieldTypes, logic.AppParamsFieldDocs)
+	appparams.Close()
+
+	acctparams, _ := os.Create("acct_params_fields.md")
+	fieldTableMarkdown(acctparams, logic.AcctParamsFieldNames, logic.AcctParamsFieldTypes, logic.AcctParamsFieldDocs)
+	acctparams.Close()
+
 	langspecjs, _ := os.Create("langspec.json")
 	enc := json.NewEncoder(langspecjs)
 	enc.Encode(buildLanguageSpec(opGroups))
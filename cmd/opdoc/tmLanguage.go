@@ -126,6 +126,8 @@ func buildSyntaxHighlight() *tmLanguage {
 	allNamedFields = append(allNamedFields, logic.GlobalFieldNames...)
 	allNamedFields = append(allNamedFields, logic.AssetHoldingFieldNames...)
 	allNamedFields = append(allNamedFields, logic.AssetParamsFieldNames...)
+	allNamedFields = append(allNamedFields, logic.AppParamsFieldNames...)
+	allNamedFields = append(allNamedFields, logic.AcctParamsFieldNames...)
 	allNamedFields = append(allNamedFields, logic.OnCompletionNames...)
 
 	literals.Patterns = append(literals.Patterns, pattern{
@@ -166,12 +168,12 @@ func buildSyntaxHighlight() *tmLanguage {
 				Name:  "keyword.other.teal",
 				Match: fmt.Sprintf("^(%s)\\b", strings.Join(loading, "|")),
 			})
-		case "State Access":
+		case "State Access", "Inner Transactions":
 			keywords.Patterns = append(keywords.Patterns, pattern{
 				Name:  "keyword.other.unit.teal",
 				Match: fmt.Sprintf("^(%s)\\b", strings.Join(opgroup.Ops, "|")),
 			})
-		case "Arithmetic":
+		case "Arithmetic", "Byte Array Arithmetic":
 			escape := map[rune]bool{
 				'*': true,
 				'+': true,
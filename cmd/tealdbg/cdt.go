@@ -0,0 +1,166 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/algorand/go-deadlock"
+	"github.com/algorand/websocket"
+	"github.com/gorilla/mux"
+)
+
+// CdtFrontend is a DebugAdapter speaking the Chrome DevTools Protocol.
+// Every debugging session is exposed as a separate target that can be
+// opened from chrome://inspect or the devtools URL printed on start.
+type CdtFrontend struct {
+	mu       deadlock.Mutex
+	sessions map[string]*cdtSession
+
+	router     *mux.Router
+	apiAddress string
+	verbose    bool
+
+	// done tracks the sessions not yet closed by the frontend
+	done sync.WaitGroup
+}
+
+// MakeCdtFrontend creates a CdtFrontend and registers its HTTP handlers
+func MakeCdtFrontend(router *mux.Router, apiAddress string, verbose bool) *CdtFrontend {
+	cf := &CdtFrontend{
+		sessions:   make(map[string]*cdtSession),
+		router:     router,
+		apiAddress: apiAddress,
+		verbose:    verbose,
+	}
+	router.HandleFunc("/json/version", cf.versionHandler).Methods("GET")
+	router.HandleFunc("/json", cf.targetsHandler).Methods("GET")
+	router.HandleFunc("/json/list", cf.targetsHandler).Methods("GET")
+	router.HandleFunc("/ws/{sid}", cf.websocketHandler)
+	return cf
+}
+
+func (cf *CdtFrontend) devtoolsURL(sid string) string {
+	return fmt.Sprintf("devtools://devtools/bundled/js_app.html?experiments=true&v8only=true&ws=%s/ws/%s", cf.apiAddress, sid)
+}
+
+// SessionStarted implements DebugAdapter
+func (cf *CdtFrontend) SessionStarted(sid string, debugger Control, ch chan Notification) {
+	s := makeCdtSession(sid, debugger, ch, cf.verbose)
+
+	cf.mu.Lock()
+	cf.sessions[sid] = s
+	cf.mu.Unlock()
+	cf.done.Add(1)
+
+	log.Printf("%s is waiting for the debugger, open chrome://inspect or %s", debugger.GetSourceName(), cf.devtoolsURL(sid))
+}
+
+// SessionEnded implements DebugAdapter
+func (cf *CdtFrontend) SessionEnded(sid string) {
+	cf.mu.Lock()
+	s, ok := cf.sessions[sid]
+	delete(cf.sessions, sid)
+	cf.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	if !s.end() {
+		// no client to say goodbye to
+		cf.done.Done()
+	}
+}
+
+// WaitForCompletion implements DebugAdapter
+func (cf *CdtFrontend) WaitForCompletion() {
+	cf.done.Wait()
+}
+
+func writeJSONResponse(w http.ResponseWriter, v interface{}) {
+	enc, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(enc)
+}
+
+func (cf *CdtFrontend) versionHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, map[string]string{
+		"Browser":          "Algorand TEAL Debugger",
+		"Protocol-Version": "1.3",
+	})
+}
+
+func (cf *CdtFrontend) targetsHandler(w http.ResponseWriter, r *http.Request) {
+	cf.mu.Lock()
+	targets := make([]cdtTarget, 0, len(cf.sessions))
+	for sid, s := range cf.sessions {
+		targets = append(targets, cdtTarget{
+			Description:          "TEAL program",
+			DevtoolsFrontendURL:  cf.devtoolsURL(sid),
+			ID:                   sid,
+			Title:                s.debugger.GetSourceName(),
+			Type:                 "node",
+			URL:                  "https://algorand.com/",
+			WebSocketDebuggerURL: fmt.Sprintf("ws://%s/ws/%s", cf.apiAddress, sid),
+		})
+	}
+	cf.mu.Unlock()
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Title < targets[j].Title })
+	writeJSONResponse(w, targets)
+}
+
+func (cf *CdtFrontend) websocketHandler(w http.ResponseWriter, r *http.Request) {
+	sid := mux.Vars(r)["sid"]
+	cf.mu.Lock()
+	s, ok := cf.sessions[sid]
+	cf.mu.Unlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("session %s not found", sid), http.StatusNotFound)
+		return
+	}
+
+	// DevTools connects from a devtools:// origin
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	if !s.connect() {
+		http.Error(w, fmt.Sprintf("session %s already has a client", sid), http.StatusConflict)
+		return
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.disconnect()
+		log.Printf("websocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	if s.serve(conn) {
+		cf.done.Done()
+	}
+}
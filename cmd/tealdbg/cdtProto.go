@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+// Chrome DevTools Protocol messages used by the debugger, see
+// https://chromedevtools.github.io/devtools-protocol/v8/
+
+// cdtRequest is a command sent by the DevTools client
+type cdtRequest struct {
+	ID     int                    `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// cdtResponse is a reply to a cdtRequest
+type cdtResponse struct {
+	ID     int         `json:"id"`
+	Result interface{} `json:"result"`
+}
+
+// cdtErrorResponse is a reply to a cdtRequest that could not be served
+type cdtErrorResponse struct {
+	ID    int      `json:"id"`
+	Error cdtError `json:"error"`
+}
+
+type cdtError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// cdtEvent is a notification sent to the DevTools client
+type cdtEvent struct {
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+// cdtTarget describes a debuggable target in the /json list
+type cdtTarget struct {
+	Description          string `json:"description"`
+	DevtoolsFrontendURL  string `json:"devtoolsFrontendUrl"`
+	ID                   string `json:"id"`
+	Title                string `json:"title"`
+	Type                 string `json:"type"`
+	URL                  string `json:"url"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+type cdtLocation struct {
+	ScriptID     string `json:"scriptId"`
+	LineNumber   int    `json:"lineNumber"`
+	ColumnNumber int    `json:"columnNumber"`
+}
+
+type cdtRemoteObject struct {
+	Type        string      `json:"type"`
+	Subtype     string      `json:"subtype,omitempty"`
+	ClassName   string      `json:"className,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+	Description string      `json:"description,omitempty"`
+	ObjectID    string      `json:"objectId,omitempty"`
+}
+
+type cdtScope struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"`
+	Object cdtRemoteObject `json:"object"`
+}
+
+type cdtCallFrame struct {
+	CallFrameID  string          `json:"callFrameId"`
+	FunctionName string          `json:"functionName"`
+	Location     cdtLocation     `json:"location"`
+	URL          string          `json:"url"`
+	ScopeChain   []cdtScope      `json:"scopeChain"`
+	This         cdtRemoteObject `json:"this"`
+}
+
+type cdtPropertyDescriptor struct {
+	Name         string           `json:"name"`
+	Value        *cdtRemoteObject `json:"value,omitempty"`
+	Writable     bool             `json:"writable"`
+	Configurable bool             `json:"configurable"`
+	Enumerable   bool             `json:"enumerable"`
+	IsOwn        bool             `json:"isOwn"`
+}
+
+type cdtScriptParsedParams struct {
+	ScriptID           string `json:"scriptId"`
+	URL                string `json:"url"`
+	StartLine          int    `json:"startLine"`
+	StartColumn        int    `json:"startColumn"`
+	EndLine            int    `json:"endLine"`
+	EndColumn          int    `json:"endColumn"`
+	ExecutionContextID int    `json:"executionContextId"`
+	Hash               string `json:"hash"`
+	Length             int    `json:"length"`
+}
+
+type cdtDebuggerPausedParams struct {
+	CallFrames     []cdtCallFrame         `json:"callFrames"`
+	Reason         string                 `json:"reason"`
+	Data           map[string]interface{} `json:"data,omitempty"`
+	HitBreakpoints []string               `json:"hitBreakpoints,omitempty"`
+}
+
+type cdtExecutionContext struct {
+	ID     int    `json:"id"`
+	Origin string `json:"origin"`
+	Name   string `json:"name"`
+}
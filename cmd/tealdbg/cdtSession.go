@@ -0,0 +1,352 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-deadlock"
+	"github.com/algorand/websocket"
+)
+
+const cdtContextID = 1
+
+// cdtSession serves a single debugging session to a DevTools client
+type cdtSession struct {
+	sid           string
+	debugger      Control
+	notifications chan Notification
+	verbose       bool
+
+	mu        deadlock.Mutex
+	connected bool
+	ended     bool
+	endedCh   chan struct{}
+
+	// state is the last state reported by the Debugger, nil until the
+	// program is paused for the first time
+	state     *cdtState
+	completed bool
+}
+
+func makeCdtSession(sid string, debugger Control, ch chan Notification, verbose bool) *cdtSession {
+	return &cdtSession{
+		sid:           sid,
+		debugger:      debugger,
+		notifications: ch,
+		verbose:       verbose,
+		endedCh:       make(chan struct{}),
+	}
+}
+
+// connect reserves the session for a client. Only one client at a time is
+// allowed.
+func (s *cdtSession) connect() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.connected || s.ended {
+		return false
+	}
+	s.connected = true
+	return true
+}
+
+// disconnect releases the session and reports whether it has ended while
+// the client was connected
+func (s *cdtSession) disconnect() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connected = false
+	return s.ended
+}
+
+// end marks the session as ended and reports whether a client is
+// connected and will be notified
+func (s *cdtSession) end() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ended = true
+	close(s.endedCh)
+	return s.connected
+}
+
+// serve runs the session over a websocket connection until either the
+// client disconnects or the session ends. It returns true if the session
+// ended.
+func (s *cdtSession) serve(conn *websocket.Conn) bool {
+	requests := make(chan cdtRequest)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var req cdtRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				if s.verbose {
+					log.Printf("%s: connection closed: %v", s.sid, err)
+				}
+				return
+			}
+			select {
+			case requests <- req:
+			case <-s.endedCh:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case req := <-requests:
+			if s.verbose {
+				log.Printf("%s: request %d %s %v", s.sid, req.ID, req.Method, req.Params)
+			}
+			err := s.handleRequest(conn, &req)
+			if err != nil {
+				log.Printf("%s: %v", s.sid, err)
+			}
+		case n := <-s.notifications:
+			err := s.handleNotification(conn, &n)
+			if err != nil {
+				log.Printf("%s: %v", s.sid, err)
+			}
+		case <-s.endedCh:
+			s.sendEvent(conn, "Runtime.executionContextDestroyed", map[string]interface{}{
+				"executionContextId": cdtContextID,
+			})
+			s.disconnect()
+			return true
+		case <-closed:
+			return s.disconnect()
+		}
+	}
+}
+
+func (s *cdtSession) sendEvent(conn *websocket.Conn, method string, params interface{}) error {
+	return conn.WriteJSON(cdtEvent{Method: method, Params: params})
+}
+
+func (s *cdtSession) sendResponse(conn *websocket.Conn, id int, result interface{}) error {
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+	return conn.WriteJSON(cdtResponse{ID: id, Result: result})
+}
+
+func (s *cdtSession) sendError(conn *websocket.Conn, id int, err error) error {
+	return conn.WriteJSON(cdtErrorResponse{ID: id, Error: cdtError{Code: -32000, Message: err.Error()}})
+}
+
+func (s *cdtSession) scriptURL() string {
+	return "file:///" + s.debugger.GetSourceName()
+}
+
+func (s *cdtSession) breakpointID(line int) string {
+	return fmt.Sprintf("%s:%d", s.sid, line)
+}
+
+func (s *cdtSession) parseBreakpointID(id string) (int, error) {
+	idx := strings.LastIndex(id, ":")
+	if idx < 0 || id[:idx] != s.sid {
+		return 0, fmt.Errorf("unknown breakpoint %s", id)
+	}
+	return strconv.Atoi(id[idx+1:])
+}
+
+func intParam(params map[string]interface{}, name string) (int, error) {
+	v, ok := params[name].(float64)
+	if !ok {
+		return 0, fmt.Errorf("missing %s parameter", name)
+	}
+	return int(v), nil
+}
+
+func (s *cdtSession) location(line int) cdtLocation {
+	return cdtLocation{ScriptID: s.sid, LineNumber: line}
+}
+
+func (s *cdtSession) handleRequest(conn *websocket.Conn, req *cdtRequest) error {
+	switch req.Method {
+	case "Runtime.enable":
+		err := s.sendResponse(conn, req.ID, nil)
+		if err != nil {
+			return err
+		}
+		return s.sendEvent(conn, "Runtime.executionContextCreated", map[string]interface{}{
+			"context": cdtExecutionContext{ID: cdtContextID, Origin: "", Name: "TEAL"},
+		})
+	case "Debugger.enable":
+		err := s.sendResponse(conn, req.ID, map[string]interface{}{"debuggerId": s.sid})
+		if err != nil {
+			return err
+		}
+		source := s.debugger.GetSource()
+		lines := strings.Split(source, "\n")
+		err = s.sendEvent(conn, "Debugger.scriptParsed", cdtScriptParsedParams{
+			ScriptID:           s.sid,
+			URL:                s.scriptURL(),
+			EndLine:            len(lines) - 1,
+			EndColumn:          len(lines[len(lines)-1]),
+			ExecutionContextID: cdtContextID,
+			Hash:               s.sid,
+			Length:             len(source),
+		})
+		if err != nil {
+			return err
+		}
+		if s.state != nil {
+			// a reconnected client, show where the program is paused
+			return s.sendPaused(conn)
+		}
+		return nil
+	case "Runtime.runIfWaitingForDebugger":
+		s.debugger.Step()
+		return s.sendResponse(conn, req.ID, nil)
+	case "Debugger.getScriptSource":
+		return s.sendResponse(conn, req.ID, map[string]interface{}{"scriptSource": s.debugger.GetSource()})
+	case "Debugger.getPossibleBreakpoints":
+		start, _ := req.Params["start"].(map[string]interface{})
+		end, _ := req.Params["end"].(map[string]interface{})
+		first, err := intParam(start, "lineNumber")
+		if err != nil {
+			return s.sendError(conn, req.ID, err)
+		}
+		last := first
+		if end != nil {
+			if last, err = intParam(end, "lineNumber"); err != nil {
+				return s.sendError(conn, req.ID, err)
+			}
+		}
+		locations := make([]cdtLocation, 0, last-first+1)
+		for line := first; line <= last; line++ {
+			locations = append(locations, s.location(line))
+		}
+		return s.sendResponse(conn, req.ID, map[string]interface{}{"locations": locations})
+	case "Debugger.setBreakpointByUrl", "Debugger.setBreakpoint":
+		params := req.Params
+		if loc, ok := params["location"].(map[string]interface{}); ok {
+			params = loc
+		}
+		line, err := intParam(params, "lineNumber")
+		if err != nil {
+			return s.sendError(conn, req.ID, err)
+		}
+		if err = s.debugger.SetBreakpoint(line); err != nil {
+			return s.sendError(conn, req.ID, err)
+		}
+		result := map[string]interface{}{"breakpointId": s.breakpointID(line)}
+		if req.Method == "Debugger.setBreakpoint" {
+			result["actualLocation"] = s.location(line)
+		} else {
+			result["locations"] = []cdtLocation{s.location(line)}
+		}
+		return s.sendResponse(conn, req.ID, result)
+	case "Debugger.removeBreakpoint":
+		id, _ := req.Params["breakpointId"].(string)
+		line, err := s.parseBreakpointID(id)
+		if err != nil {
+			return s.sendError(conn, req.ID, err)
+		}
+		if err = s.debugger.RemoveBreakpoint(line); err != nil {
+			return s.sendError(conn, req.ID, err)
+		}
+		return s.sendResponse(conn, req.ID, nil)
+	case "Debugger.setBreakpointsActive":
+		active, _ := req.Params["active"].(bool)
+		s.debugger.SetBreakpointsActive(active)
+		return s.sendResponse(conn, req.ID, nil)
+	case "Debugger.resume":
+		s.debugger.Resume()
+		return s.sendResumed(conn, req.ID)
+	case "Debugger.stepOver", "Debugger.stepInto", "Debugger.stepOut":
+		if s.completed {
+			// nothing left to step through
+			s.debugger.Resume()
+		} else {
+			s.debugger.Step()
+		}
+		return s.sendResumed(conn, req.ID)
+	case "Debugger.pause":
+		s.debugger.Pause()
+		return s.sendResponse(conn, req.ID, nil)
+	case "Runtime.getProperties":
+		objID, _ := req.Params["objectId"].(string)
+		if s.state == nil {
+			return s.sendError(conn, req.ID, fmt.Errorf("program is not paused"))
+		}
+		props, err := s.state.getProperties(objID)
+		if err != nil {
+			return s.sendError(conn, req.ID, err)
+		}
+		return s.sendResponse(conn, req.ID, map[string]interface{}{"result": props})
+	default:
+		// acknowledge the commands not relevant to TEAL
+		return s.sendResponse(conn, req.ID, nil)
+	}
+}
+
+func (s *cdtSession) sendResumed(conn *websocket.Conn, id int) error {
+	err := s.sendResponse(conn, id, nil)
+	if err != nil {
+		return err
+	}
+	return s.sendEvent(conn, "Debugger.resumed", map[string]interface{}{})
+}
+
+func (s *cdtSession) sendPaused(conn *websocket.Conn) error {
+	functionName := "main"
+	reason := "other"
+	var data map[string]interface{}
+	if s.completed {
+		functionName = "completed"
+		data = map[string]interface{}{"description": "program completed"}
+		if len(s.state.Error) > 0 {
+			reason = "exception"
+			data["description"] = s.state.Error
+		}
+	}
+	return s.sendEvent(conn, "Debugger.paused", cdtDebuggerPausedParams{
+		CallFrames: []cdtCallFrame{{
+			CallFrameID:  "mainframe",
+			FunctionName: functionName,
+			Location:     s.location(s.state.Line),
+			URL:          s.scriptURL(),
+			ScopeChain:   s.state.scopeChain(),
+			This:         cdtRemoteObject{Type: "undefined"},
+		}},
+		Reason: reason,
+		Data:   data,
+	})
+}
+
+func (s *cdtSession) handleNotification(conn *websocket.Conn, n *Notification) error {
+	switch n.Event {
+	case "registered":
+		// the program waits for Runtime.runIfWaitingForDebugger
+		return nil
+	case "updated":
+		s.state = makeCdtState(&n.DebugState)
+		return s.sendPaused(conn)
+	case "completed":
+		s.state = makeCdtState(&n.DebugState)
+		s.completed = true
+		return s.sendPaused(conn)
+	}
+	return fmt.Errorf("unknown event %s", n.Event)
+}
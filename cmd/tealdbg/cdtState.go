@@ -0,0 +1,321 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+)
+
+// Object IDs of the values exposed to the DevTools client. Indexed objects
+// use a "/" separator, e.g. "gtxn/1" or "appLocals/<address>".
+const (
+	localScopeObjID  = "localScope"
+	globalScopeObjID = "globalScope"
+	stackObjID       = "stack"
+	scratchObjID     = "scratch"
+	globalsObjID     = "globals"
+	txnObjID         = "txn"
+	gtxnObjID        = "gtxn"
+	appGlobalObjID   = "appGlobal"
+	appLocalsObjID   = "appLocals"
+)
+
+// cdtState is a snapshot of the program state shown by the DevTools client
+type cdtState struct {
+	logic.DebugState
+}
+
+func makeCdtState(state *logic.DebugState) *cdtState {
+	return &cdtState{DebugState: *state}
+}
+
+// tealBytesToObject formats raw bytes as a string if printable and as
+// hex otherwise
+func tealBytesToObject(b []byte) cdtRemoteObject {
+	printable := true
+	for _, r := range string(b) {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			printable = false
+			break
+		}
+	}
+	if printable {
+		return cdtRemoteObject{Type: "string", Value: string(b)}
+	}
+	return cdtRemoteObject{Type: "string", Value: "0x" + hex.EncodeToString(b)}
+}
+
+func uintToObject(v uint64) cdtRemoteObject {
+	return cdtRemoteObject{Type: "number", Value: v, Description: strconv.FormatUint(v, 10)}
+}
+
+// tealValueToObject converts a TealValue. Bytes of the values reported by
+// the evaluator are base64 encoded.
+func tealValueToObject(tv *basics.TealValue, encoded bool) cdtRemoteObject {
+	if tv.Type == basics.TealUintType {
+		return uintToObject(tv.Uint)
+	}
+	b := []byte(tv.Bytes)
+	if encoded {
+		if decoded, err := base64.StdEncoding.DecodeString(tv.Bytes); err == nil {
+			b = decoded
+		}
+	}
+	return tealBytesToObject(b)
+}
+
+func valueDeltaToObject(vd *basics.ValueDelta) cdtRemoteObject {
+	switch vd.Action {
+	case basics.SetUintAction:
+		return uintToObject(vd.Uint)
+	case basics.SetBytesAction:
+		return tealBytesToObject([]byte(vd.Bytes))
+	}
+	return cdtRemoteObject{Type: "undefined", Description: "deleted"}
+}
+
+func arrayObject(objID string, length int) cdtRemoteObject {
+	return cdtRemoteObject{
+		Type:        "object",
+		Subtype:     "array",
+		ClassName:   "Array",
+		Description: fmt.Sprintf("Array(%d)", length),
+		ObjectID:    objID,
+	}
+}
+
+func plainObject(objID string, description string) cdtRemoteObject {
+	return cdtRemoteObject{
+		Type:        "object",
+		ClassName:   "Object",
+		Description: description,
+		ObjectID:    objID,
+	}
+}
+
+func makeProperty(name string, value cdtRemoteObject) cdtPropertyDescriptor {
+	return cdtPropertyDescriptor{
+		Name:         name,
+		Value:        &value,
+		Configurable: false,
+		Enumerable:   true,
+		IsOwn:        true,
+	}
+}
+
+// scopeChain returns the scopes shown in the call frame
+func (s *cdtState) scopeChain() []cdtScope {
+	return []cdtScope{
+		{Type: "local", Object: plainObject(localScopeObjID, "Local")},
+		{Type: "global", Object: plainObject(globalScopeObjID, "Global")},
+	}
+}
+
+// getProperties returns the properties of an object referenced by the client
+func (s *cdtState) getProperties(objID string) ([]cdtPropertyDescriptor, error) {
+	parts := strings.SplitN(objID, "/", 2)
+	switch parts[0] {
+	case localScopeObjID:
+		props := []cdtPropertyDescriptor{
+			makeProperty("pc", uintToObject(uint64(s.PC))),
+			makeProperty("stack", arrayObject(stackObjID, len(s.Stack))),
+			makeProperty("scratch", arrayObject(scratchObjID, len(s.Scratch))),
+		}
+		if len(s.Error) > 0 {
+			props = append(props, makeProperty("error", cdtRemoteObject{Type: "string", Value: s.Error}))
+		}
+		return props, nil
+	case globalScopeObjID:
+		props := []cdtPropertyDescriptor{
+			makeProperty("globals", plainObject(globalsObjID, "Globals")),
+			makeProperty("txn", plainObject(txnObjID, "Txn")),
+			makeProperty("gtxn", arrayObject(gtxnObjID, len(s.TxnGroup))),
+		}
+		if s.GlobalStateChanges != nil {
+			props = append(props,
+				makeProperty("appGlobal", plainObject(appGlobalObjID, "AppGlobal")),
+				makeProperty("appLocals", plainObject(appLocalsObjID, "AppLocals")),
+			)
+		}
+		return props, nil
+	case stackObjID:
+		return tealValuesToProperties(s.Stack), nil
+	case scratchObjID:
+		return tealValuesToProperties(s.Scratch), nil
+	case globalsObjID:
+		props := make([]cdtPropertyDescriptor, 0, len(s.Globals))
+		for i, tv := range s.Globals {
+			if i >= len(logic.GlobalFieldNames) {
+				break
+			}
+			props = append(props, makeProperty(logic.GlobalFieldNames[i], tealValueToObject(&tv, true)))
+		}
+		return props, nil
+	case txnObjID:
+		if len(parts) > 1 {
+			return s.txnArrayProperties(txnObjID, parts[1])
+		}
+		return s.txnProperties(s.GroupIndex, txnObjID), nil
+	case gtxnObjID:
+		if len(parts) == 1 {
+			props := make([]cdtPropertyDescriptor, len(s.TxnGroup))
+			for i := range s.TxnGroup {
+				props[i] = makeProperty(strconv.Itoa(i), plainObject(fmt.Sprintf("%s/%d", gtxnObjID, i), "Txn"))
+			}
+			return props, nil
+		}
+		// gtxn/<index> or gtxn/<index>/<array field>
+		sub := strings.SplitN(parts[1], "/", 2)
+		if len(sub) > 1 {
+			return s.txnArrayProperties(gtxnObjID+"/"+sub[0], sub[1])
+		}
+		gi, err := strconv.Atoi(sub[0])
+		if err != nil || gi < 0 || gi >= len(s.TxnGroup) {
+			return nil, fmt.Errorf("invalid object id %s", objID)
+		}
+		return s.txnProperties(gi, objID), nil
+	case appGlobalObjID:
+		return stateDeltaToProperties(s.GlobalStateChanges), nil
+	case appLocalsObjID:
+		if len(parts) == 1 {
+			addrs := make([]string, 0, len(s.LocalStateChanges))
+			for addr := range s.LocalStateChanges {
+				addrs = append(addrs, addr.String())
+			}
+			sort.Strings(addrs)
+			props := make([]cdtPropertyDescriptor, len(addrs))
+			for i, addr := range addrs {
+				props[i] = makeProperty(addr, plainObject(appLocalsObjID+"/"+addr, "AppLocal"))
+			}
+			return props, nil
+		}
+		addr, err := basics.UnmarshalChecksumAddress(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid object id %s: %v", objID, err)
+		}
+		return stateDeltaToProperties(s.LocalStateChanges[addr]), nil
+	}
+	return nil, fmt.Errorf("unknown object id %s", objID)
+}
+
+func tealValuesToProperties(values []basics.TealValue) []cdtPropertyDescriptor {
+	props := make([]cdtPropertyDescriptor, len(values))
+	for i := range values {
+		props[i] = makeProperty(strconv.Itoa(i), tealValueToObject(&values[i], true))
+	}
+	return props
+}
+
+func stateDeltaToProperties(delta basics.StateDelta) []cdtPropertyDescriptor {
+	keys := make([]string, 0, len(delta))
+	for key := range delta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	props := make([]cdtPropertyDescriptor, len(keys))
+	for i, key := range keys {
+		vd := delta[key]
+		name, _ := tealBytesToObject([]byte(key)).Value.(string)
+		props[i] = makeProperty(name, valueDeltaToObject(&vd))
+	}
+	return props
+}
+
+// addressTxnFields are shown as checksummed addresses rather than bytes
+var addressTxnFields = map[logic.TxnField]bool{
+	logic.Sender:           true,
+	logic.Receiver:         true,
+	logic.CloseRemainderTo: true,
+	logic.AssetSender:      true,
+	logic.AssetReceiver:    true,
+	logic.AssetCloseTo:     true,
+}
+
+// txnProperties lists the fields of a transaction of the group. Array
+// fields are exposed as nested objects.
+func (s *cdtState) txnProperties(groupIndex int, objID string) []cdtPropertyDescriptor {
+	if groupIndex < 0 || groupIndex >= len(s.TxnGroup) {
+		return nil
+	}
+	txn := &s.TxnGroup[groupIndex].Txn
+	props := make([]cdtPropertyDescriptor, 0, len(logic.TxnFieldNames))
+	for i, name := range logic.TxnFieldNames {
+		field := logic.TxnField(i)
+		switch field {
+		case logic.ApplicationArgs:
+			props = append(props, makeProperty(name, arrayObject(objID+"/"+name, len(txn.ApplicationArgs))))
+			continue
+		case logic.Accounts:
+			props = append(props, makeProperty(name, arrayObject(objID+"/"+name, len(txn.Accounts))))
+			continue
+		case logic.NumAppArgs, logic.NumAccounts:
+			// shown as array lengths
+			continue
+		}
+		tv, err := logic.TxnFieldToTealValue(txn, groupIndex, field)
+		if err != nil {
+			continue
+		}
+		if addressTxnFields[field] && len(tv.Bytes) == len(basics.Address{}) {
+			var addr basics.Address
+			copy(addr[:], tv.Bytes)
+			props = append(props, makeProperty(name, cdtRemoteObject{Type: "string", Value: addr.String()}))
+			continue
+		}
+		props = append(props, makeProperty(name, tealValueToObject(&tv, false)))
+	}
+	return props
+}
+
+func (s *cdtState) txnArrayProperties(parentID string, field string) ([]cdtPropertyDescriptor, error) {
+	groupIndex := s.GroupIndex
+	if parentID != txnObjID {
+		gi, err := strconv.Atoi(strings.TrimPrefix(parentID, gtxnObjID+"/"))
+		if err != nil || !strings.HasPrefix(parentID, gtxnObjID+"/") {
+			return nil, fmt.Errorf("unknown object id %s/%s", parentID, field)
+		}
+		groupIndex = gi
+	}
+	if groupIndex < 0 || groupIndex >= len(s.TxnGroup) {
+		return nil, fmt.Errorf("unknown object id %s/%s", parentID, field)
+	}
+	txn := &s.TxnGroup[groupIndex].Txn
+
+	var props []cdtPropertyDescriptor
+	switch field {
+	case logic.ApplicationArgs.String():
+		for i, arg := range txn.ApplicationArgs {
+			props = append(props, makeProperty(strconv.Itoa(i), tealBytesToObject(arg)))
+		}
+	case logic.Accounts.String():
+		for i, addr := range txn.Accounts {
+			props = append(props, makeProperty(strconv.Itoa(i), cdtRemoteObject{Type: "string", Value: addr.String()}))
+		}
+	default:
+		return nil, fmt.Errorf("unknown object id %s/%s", parentID, field)
+	}
+	return props, nil
+}
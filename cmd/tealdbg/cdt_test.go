@@ -0,0 +1,223 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algorand/websocket"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+)
+
+type cdtTestClient struct {
+	t    *testing.T
+	conn *websocket.Conn
+	id   int
+}
+
+// message is either a response or an event
+type cdtTestMessage struct {
+	ID     int                    `json:"id"`
+	Method string                 `json:"method"`
+	Result map[string]interface{} `json:"result"`
+	Params json.RawMessage        `json:"params"`
+	Error  *cdtError              `json:"error"`
+}
+
+func (c *cdtTestClient) call(method string, params map[string]interface{}) {
+	c.id++
+	err := c.conn.WriteJSON(cdtRequest{ID: c.id, Method: method, Params: params})
+	require.NoError(c.t, err)
+}
+
+// waitFor reads messages until the response to the last call, or the given
+// event if not empty, arrives
+func (c *cdtTestClient) waitFor(event string) cdtTestMessage {
+	for {
+		var msg cdtTestMessage
+		c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+		require.NoError(c.t, c.conn.ReadJSON(&msg))
+		if len(event) > 0 && msg.Method == event {
+			return msg
+		}
+		if len(event) == 0 && len(msg.Method) == 0 && msg.ID == c.id {
+			return msg
+		}
+	}
+}
+
+func TestCdtFrontend(t *testing.T) {
+	a := require.New(t)
+
+	router := mux.NewRouter()
+	server := httptest.NewServer(router)
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	debugger := MakeDebugger()
+	frontend := MakeCdtFrontend(router, address, false)
+	debugger.AddAdapter(frontend)
+
+	results := make(chan bool)
+	go func() {
+		results <- evalWithDebugger(t, debugger, testProgram)
+	}()
+
+	// the program waits for the debugger and shows up in the targets list
+	var targets []cdtTarget
+	for i := 0; i < 100 && len(targets) == 0; i++ {
+		resp, err := http.Get(server.URL + "/json/list")
+		a.NoError(err)
+		a.NoError(json.NewDecoder(resp.Body).Decode(&targets))
+		resp.Body.Close()
+		time.Sleep(10 * time.Millisecond)
+	}
+	a.Equal(1, len(targets))
+	a.Equal("test.teal", targets[0].Title)
+	a.Contains(targets[0].DevtoolsFrontendURL, "ws="+address+"/ws/")
+
+	conn, _, err := websocket.DefaultDialer.Dial(targets[0].WebSocketDebuggerURL, nil)
+	a.NoError(err)
+	defer conn.Close()
+	c := cdtTestClient{t: t, conn: conn}
+
+	// only a single client per session
+	_, _, err = websocket.DefaultDialer.Dial(targets[0].WebSocketDebuggerURL, nil)
+	a.Error(err)
+
+	c.call("Runtime.enable", nil)
+	c.waitFor("Runtime.executionContextCreated")
+	c.call("Debugger.enable", nil)
+	msg := c.waitFor("Debugger.scriptParsed")
+	var script cdtScriptParsedParams
+	a.NoError(json.Unmarshal(msg.Params, &script))
+	a.Equal(targets[0].ID, script.ScriptID)
+
+	c.call("Debugger.getScriptSource", map[string]interface{}{"scriptId": script.ScriptID})
+	msg = c.waitFor("")
+	a.Contains(msg.Result["scriptSource"], "intc_0")
+
+	c.call("Runtime.runIfWaitingForDebugger", nil)
+	msg = c.waitFor("Debugger.paused")
+	var paused cdtDebuggerPausedParams
+	a.NoError(json.Unmarshal(msg.Params, &paused))
+	first := paused.CallFrames[0].Location.LineNumber
+
+	// break on "+", the fourth instruction
+	c.call("Debugger.setBreakpointByUrl", map[string]interface{}{"lineNumber": first + 3, "url": script.URL})
+	msg = c.waitFor("")
+	a.Nil(msg.Error)
+	c.call("Debugger.resume", nil)
+	msg = c.waitFor("Debugger.paused")
+	a.NoError(json.Unmarshal(msg.Params, &paused))
+	a.Equal(first+3, paused.CallFrames[0].Location.LineNumber)
+
+	c.call("Runtime.getProperties", map[string]interface{}{"objectId": stackObjID})
+	msg = c.waitFor("")
+	props := msg.Result["result"].([]interface{})
+	a.Equal(2, len(props))
+	a.Equal(float64(2), props[1].(map[string]interface{})["value"].(map[string]interface{})["value"])
+
+	c.call("Debugger.resume", nil)
+	msg = c.waitFor("Debugger.paused")
+	a.NoError(json.Unmarshal(msg.Params, &paused))
+	a.Equal("completed", paused.CallFrames[0].FunctionName)
+
+	c.call("Debugger.resume", nil)
+	c.waitFor("Runtime.executionContextDestroyed")
+	frontend.WaitForCompletion()
+	a.True(<-results)
+}
+
+func TestCdtStateProperties(t *testing.T) {
+	a := require.New(t)
+
+	var sender basics.Address
+	crypto.RandBytes(sender[:])
+	var txn transactions.SignedTxn
+	txn.Txn.Sender = sender
+	txn.Txn.ApplicationArgs = [][]byte{[]byte("hello"), {0, 1}}
+	txn.Txn.Accounts = []basics.Address{sender}
+
+	state := makeCdtState(&logic.DebugState{
+		TxnGroup: []transactions.SignedTxn{txn, txn},
+		Stack: []basics.TealValue{
+			{Type: basics.TealUintType, Uint: 5},
+			{Type: basics.TealBytesType, Bytes: base64.StdEncoding.EncodeToString([]byte("abc"))},
+		},
+		Globals: []basics.TealValue{{Type: basics.TealUintType, Uint: 1000}},
+		Error:   "assert failed",
+		AppStateChage: logic.AppStateChage{
+			GlobalStateChanges: basics.StateDelta{"key": {Action: basics.SetUintAction, Uint: 7}},
+			LocalStateChanges: map[basics.Address]basics.StateDelta{
+				sender: {"lkey": {Action: basics.DeleteAction}},
+			},
+		},
+	})
+
+	propsMap := func(objID string) map[string]*cdtRemoteObject {
+		props, err := state.getProperties(objID)
+		a.NoError(err)
+		m := make(map[string]*cdtRemoteObject, len(props))
+		for _, p := range props {
+			m[p.Name] = p.Value
+		}
+		return m
+	}
+
+	local := propsMap(localScopeObjID)
+	a.Equal("assert failed", local["error"].Value)
+	a.Equal(stackObjID, local["stack"].ObjectID)
+
+	stack := propsMap(stackObjID)
+	a.Equal(uint64(5), stack["0"].Value)
+	a.Equal("abc", stack["1"].Value)
+
+	global := propsMap(globalScopeObjID)
+	a.Contains(global, "appLocals")
+	a.Equal(uint64(1000), propsMap(globalsObjID)[logic.GlobalFieldNames[0]].Value)
+
+	txnProps := propsMap(txnObjID)
+	a.Equal(sender.String(), txnProps["Sender"].Value)
+	a.Equal("txn/ApplicationArgs", txnProps["ApplicationArgs"].ObjectID)
+	args := propsMap("txn/ApplicationArgs")
+	a.Equal("hello", args["0"].Value)
+	a.Equal("0x0001", args["1"].Value)
+
+	a.Equal(2, len(propsMap(gtxnObjID)))
+	a.Equal(sender.String(), propsMap("gtxn/1/Accounts")["0"].Value)
+
+	a.Equal(uint64(7), propsMap(appGlobalObjID)["key"].Value)
+	a.Equal("undefined", propsMap(appLocalsObjID + "/" + sender.String())["lkey"].Type)
+
+	_, err := state.getProperties("gtxn/5")
+	a.Error(err)
+	_, err = state.getProperties("unknown")
+	a.Error(err)
+}
@@ -0,0 +1,307 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/data/transactions/logic"
+)
+
+// Notification is sent to a frontend when the state of a debugging session
+// changes. Event is one of "registered", "updated" or "completed".
+type Notification struct {
+	Event      string
+	DebugState logic.DebugState
+}
+
+// DebugAdapter is the interface implemented by debugger frontends
+type DebugAdapter interface {
+	// SessionStarted is called when a new program starts evaluation.
+	// Notifications about the session are delivered over ch.
+	SessionStarted(sid string, debugger Control, ch chan Notification)
+	// SessionEnded is called once the program evaluation is complete
+	SessionEnded(sid string)
+	// WaitForCompletion blocks until all the sessions were ended and
+	// the frontend is done with them
+	WaitForCompletion()
+}
+
+// Control is the interface used by frontends to drive a debugging session
+type Control interface {
+	// Step resumes the execution and pauses before the next instruction
+	Step()
+	// Resume continues the execution until the next breakpoint
+	Resume()
+	// Pause stops a running program before its next instruction
+	Pause()
+	// SetBreakpoint sets a breakpoint on a disassembly line
+	SetBreakpoint(line int) error
+	// RemoveBreakpoint removes a breakpoint from a disassembly line
+	RemoveBreakpoint(line int) error
+	// SetBreakpointsActive enables or disables all the breakpoints
+	SetBreakpointsActive(active bool)
+	// GetSourceName returns a name identifying the program
+	GetSourceName() string
+	// GetSource returns the disassembled program
+	GetSource() string
+}
+
+// Debugger is a TEAL debugger. It implements logic.DebuggerHook and
+// suspends the program evaluation while a frontend inspects it.
+type Debugger struct {
+	mus      deadlock.Mutex
+	sessions map[string]*session
+	programs map[string]string
+	sources  map[string]programSource
+
+	// the frontend the sessions are reported to
+	mud deadlock.Mutex
+	da  DebugAdapter
+}
+
+// MakeDebugger creates a Debugger instance
+func MakeDebugger() *Debugger {
+	return &Debugger{
+		sessions: make(map[string]*session),
+		programs: make(map[string]string),
+		sources:  make(map[string]programSource),
+	}
+}
+
+// programSource is the TEAL source a program was assembled from
+type programSource struct {
+	source    string
+	sourceMap logic.SourceMap
+}
+
+// AddAdapter sets the frontend receiving the debugging sessions
+func (d *Debugger) AddAdapter(da DebugAdapter) {
+	d.mud.Lock()
+	defer d.mud.Unlock()
+	d.da = da
+}
+
+func (d *Debugger) adapter() DebugAdapter {
+	d.mud.Lock()
+	defer d.mud.Unlock()
+	return d.da
+}
+
+// SaveProgram associates a human readable name with a program so that the
+// sessions evaluating it can be told apart in the frontend
+func (d *Debugger) SaveProgram(name string, program []byte) {
+	d.mus.Lock()
+	defer d.mus.Unlock()
+	d.programs[logic.GetProgramID(program)] = name
+}
+
+// SaveSource associates the TEAL source and source map of a program with it
+// so that the sessions evaluating it show the source instead of the disassembly
+func (d *Debugger) SaveSource(program []byte, source string, sm logic.SourceMap) {
+	d.mus.Lock()
+	defer d.mus.Unlock()
+	d.sources[logic.GetProgramID(program)] = programSource{source, sm}
+}
+
+func (d *Debugger) getSession(sid string) (*session, error) {
+	d.mus.Lock()
+	defer d.mus.Unlock()
+	s, ok := d.sessions[sid]
+	if !ok {
+		return nil, fmt.Errorf("session %s not found", sid)
+	}
+	return s, nil
+}
+
+// Register implements logic.DebuggerHook. It starts a new session and
+// waits until the frontend lets the program run.
+func (d *Debugger) Register(state *logic.DebugState) error {
+	da := d.adapter()
+	if da == nil {
+		return fmt.Errorf("no debugger frontend configured")
+	}
+
+	sid := state.ExecID
+	d.mus.Lock()
+	if _, ok := d.sessions[sid]; ok {
+		d.mus.Unlock()
+		return fmt.Errorf("program %s is already being debugged", sid)
+	}
+	name, ok := d.programs[sid]
+	if !ok {
+		name = sid
+	}
+	if ps, ok := d.sources[sid]; ok {
+		err := state.SetSource(ps.source, ps.sourceMap)
+		if err != nil {
+			d.mus.Unlock()
+			return err
+		}
+	}
+	s := makeSession(name, state)
+	d.sessions[sid] = s
+	d.mus.Unlock()
+
+	da.SessionStarted(sid, s, s.notifications)
+	s.notifyAndWait("registered", state)
+	return nil
+}
+
+// Update implements logic.DebuggerHook. It pauses the program if the
+// current line has a breakpoint or the frontend is stepping.
+func (d *Debugger) Update(state *logic.DebugState) error {
+	s, err := d.getSession(state.ExecID)
+	if err != nil {
+		return err
+	}
+	if s.shouldBreak(state.Line) {
+		s.notifyAndWait("updated", state)
+	}
+	return nil
+}
+
+// Complete implements logic.DebuggerHook. It reports the final state of
+// the program and ends the session once the frontend is done with it.
+func (d *Debugger) Complete(state *logic.DebugState) error {
+	s, err := d.getSession(state.ExecID)
+	if err != nil {
+		return err
+	}
+	s.notifyAndWait("completed", state)
+
+	d.mus.Lock()
+	delete(d.sessions, state.ExecID)
+	d.mus.Unlock()
+
+	if da := d.adapter(); da != nil {
+		da.SessionEnded(state.ExecID)
+	}
+	return nil
+}
+
+// session tracks a single program evaluation
+type session struct {
+	mu deadlock.Mutex
+
+	// notifications are read by the frontend
+	notifications chan Notification
+	// acknowledged is written by the frontend to let a paused program go on
+	acknowledged chan bool
+	// paused is set while the program waits for an acknowledgement
+	paused bool
+
+	name        string
+	disassembly string
+	lines       int
+
+	breakpoints       map[int]bool
+	breakpointsActive bool
+	stepping          bool
+}
+
+func makeSession(name string, state *logic.DebugState) *session {
+	return &session{
+		notifications:     make(chan Notification),
+		acknowledged:      make(chan bool, 1),
+		name:              name,
+		disassembly:       state.Disassembly,
+		lines:             len(strings.Split(state.Disassembly, "\n")),
+		breakpoints:       make(map[int]bool),
+		breakpointsActive: true,
+		// pause before the first instruction
+		stepping: true,
+	}
+}
+
+func (s *session) notifyAndWait(event string, state *logic.DebugState) {
+	s.mu.Lock()
+	s.paused = true
+	s.mu.Unlock()
+
+	s.notifications <- Notification{Event: event, DebugState: *state}
+	<-s.acknowledged
+}
+
+func (s *session) shouldBreak(line int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stepping || (s.breakpointsActive && s.breakpoints[line])
+}
+
+// acknowledge lets the program go on if it is paused; it is a no-op
+// for a running program
+func (s *session) acknowledge(stepping bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stepping = stepping
+	if s.paused {
+		s.paused = false
+		s.acknowledged <- true
+	}
+}
+
+func (s *session) Step() {
+	s.acknowledge(true)
+}
+
+func (s *session) Resume() {
+	s.acknowledge(false)
+}
+
+func (s *session) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stepping = true
+}
+
+func (s *session) SetBreakpoint(line int) error {
+	if line < 0 || line >= s.lines {
+		return fmt.Errorf("line %d is out of range", line)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breakpoints[line] = true
+	return nil
+}
+
+func (s *session) RemoveBreakpoint(line int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.breakpoints[line] {
+		return fmt.Errorf("no breakpoint at line %d", line)
+	}
+	delete(s.breakpoints, line)
+	return nil
+}
+
+func (s *session) SetBreakpointsActive(active bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breakpointsActive = active
+}
+
+func (s *session) GetSourceName() string {
+	return s.name
+}
+
+func (s *session) GetSource() string {
+	return s.disassembly
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// testDbgAdapter drives sessions the way a frontend would: it steps
+// through the program or resumes it until the next breakpoint
+type testDbgAdapter struct {
+	t           *testing.T
+	breakpoints []int
+	stepping    bool
+
+	name    string
+	source  string
+	lines   []int
+	stack   []int
+	started bool
+	ended   bool
+	done    sync.WaitGroup
+}
+
+func makeTestDbgAdapter(t *testing.T, stepping bool, breakpoints ...int) *testDbgAdapter {
+	return &testDbgAdapter{
+		t:           t,
+		breakpoints: breakpoints,
+		stepping:    stepping,
+	}
+}
+
+func (a *testDbgAdapter) SessionStarted(sid string, debugger Control, ch chan Notification) {
+	a.started = true
+	a.done.Add(1)
+	a.name = debugger.GetSourceName()
+	a.source = debugger.GetSource()
+	for _, line := range a.breakpoints {
+		require.NoError(a.t, debugger.SetBreakpoint(line))
+	}
+
+	go func() {
+		for n := range ch {
+			switch n.Event {
+			case "updated":
+				a.lines = append(a.lines, n.DebugState.Line)
+				a.stack = append(a.stack, len(n.DebugState.Stack))
+			case "completed":
+				debugger.Resume()
+				return
+			}
+			if a.stepping {
+				debugger.Step()
+			} else {
+				debugger.Resume()
+			}
+		}
+	}()
+}
+
+func (a *testDbgAdapter) SessionEnded(sid string) {
+	a.ended = true
+	a.done.Done()
+}
+
+func (a *testDbgAdapter) WaitForCompletion() {
+	a.done.Wait()
+}
+
+func evalWithDebugger(t *testing.T, debugger *Debugger, source string) bool {
+	program, err := logic.AssembleString(source)
+	require.NoError(t, err)
+	debugger.SaveProgram("test.teal", program)
+
+	proto := config.Consensus[protocol.ConsensusFuture]
+	txn := transactions.SignedTxn{}
+	ep := logic.EvalParams{
+		Txn:      &txn,
+		Proto:    &proto,
+		TxnGroup: []transactions.SignedTxn{txn},
+		Debugger: debugger,
+	}
+	pass, err := logic.Eval(program, ep)
+	require.NoError(t, err)
+	return pass
+}
+
+const testProgram = `int 1
+int 2
++
+int 3
+==`
+
+func TestDebuggerStepping(t *testing.T) {
+	debugger := MakeDebugger()
+	da := makeTestDbgAdapter(t, true)
+	debugger.AddAdapter(da)
+
+	pass := evalWithDebugger(t, debugger, testProgram)
+	da.WaitForCompletion()
+
+	require.True(t, pass)
+	require.True(t, da.started)
+	require.True(t, da.ended)
+	require.Equal(t, "test.teal", da.name)
+	require.Contains(t, da.source, "intc_0")
+	// paused before every instruction
+	// intcblock, intc_0, intc_1, +, intc_2, ==
+	require.Equal(t, []int{0, 0, 1, 2, 1, 2}, da.stack)
+	require.Equal(t, 6, len(da.lines))
+	for i := 1; i < len(da.lines); i++ {
+		require.Greater(t, da.lines[i], da.lines[i-1])
+	}
+
+	// the session is gone
+	_, err := debugger.getSession(sessionID(t, testProgram))
+	require.Error(t, err)
+}
+
+func TestDebuggerSource(t *testing.T) {
+	debugger := MakeDebugger()
+	da := makeTestDbgAdapter(t, true)
+	debugger.AddAdapter(da)
+
+	program, sm, err := loadProgram("test.teal", []byte(testProgram))
+	require.NoError(t, err)
+	require.NotNil(t, sm)
+	debugger.SaveProgram("test.teal", program)
+	debugger.SaveSource(program, testProgram, *sm)
+
+	proto := config.Consensus[protocol.ConsensusFuture]
+	txn := transactions.SignedTxn{}
+	ep := logic.EvalParams{
+		Txn:      &txn,
+		Proto:    &proto,
+		TxnGroup: []transactions.SignedTxn{txn},
+		Debugger: debugger,
+	}
+	pass, err := logic.Eval(program, ep)
+	require.NoError(t, err)
+	da.WaitForCompletion()
+
+	require.True(t, pass)
+	require.Equal(t, testProgram, da.source)
+	// the intcblock has no source line and reports the first instruction
+	require.Equal(t, []int{0, 0, 1, 2, 3, 4}, da.lines)
+}
+
+func TestDebuggerBreakpoint(t *testing.T) {
+	// find the line of "+" in the disassembly
+	debugger := MakeDebugger()
+	stepper := makeTestDbgAdapter(t, true)
+	debugger.AddAdapter(stepper)
+	evalWithDebugger(t, debugger, testProgram)
+	stepper.WaitForCompletion()
+	plusLine := stepper.lines[3]
+
+	debugger = MakeDebugger()
+	da := makeTestDbgAdapter(t, false, plusLine)
+	debugger.AddAdapter(da)
+	pass := evalWithDebugger(t, debugger, testProgram)
+	da.WaitForCompletion()
+
+	require.True(t, pass)
+	// resumed on registration, so the only pause is on the breakpoint
+	require.Equal(t, []int{plusLine}, da.lines)
+	require.Equal(t, []int{2}, da.stack)
+}
+
+func TestDebuggerBreakpointsInactive(t *testing.T) {
+	s := makeSession("test", &logic.DebugState{Disassembly: "int 1\nint 2\n+"})
+	require.Error(t, s.SetBreakpoint(5))
+	require.Error(t, s.RemoveBreakpoint(1))
+	require.NoError(t, s.SetBreakpoint(1))
+
+	s.Resume()
+	require.False(t, s.shouldBreak(0))
+	require.True(t, s.shouldBreak(1))
+	s.SetBreakpointsActive(false)
+	require.False(t, s.shouldBreak(1))
+	s.SetBreakpointsActive(true)
+	require.NoError(t, s.RemoveBreakpoint(1))
+	require.False(t, s.shouldBreak(1))
+
+	s.Pause()
+	require.True(t, s.shouldBreak(0))
+}
+
+func TestDebuggerNoAdapter(t *testing.T) {
+	debugger := MakeDebugger()
+	err := debugger.Register(&logic.DebugState{ExecID: "abc"})
+	require.Error(t, err)
+	err = debugger.Update(&logic.DebugState{ExecID: "abc"})
+	require.Error(t, err)
+}
+
+func sessionID(t *testing.T, source string) string {
+	program, err := logic.AssembleString(source)
+	require.NoError(t, err)
+	var ds logic.DebugState
+	proto := config.Consensus[protocol.ConsensusFuture]
+	txn := transactions.SignedTxn{}
+	hook := &stateRecorder{state: &ds}
+	_, err = logic.Eval(program, logic.EvalParams{Txn: &txn, Proto: &proto, Debugger: hook})
+	require.NoError(t, err)
+	return ds.ExecID
+}
+
+// stateRecorder is a DebuggerHook remembering the registered state
+type stateRecorder struct {
+	state *logic.DebugState
+}
+
+func (r *stateRecorder) Register(state *logic.DebugState) error {
+	*r.state = *state
+	return nil
+}
+
+func (r *stateRecorder) Update(state *logic.DebugState) error {
+	return nil
+}
+
+func (r *stateRecorder) Complete(state *logic.DebugState) error {
+	return nil
+}
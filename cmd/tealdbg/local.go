@@ -0,0 +1,342 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// DebugParams is a container for the parameters of a local debugging run
+type DebugParams struct {
+	ProgramNames    []string
+	ProgramBlobs    [][]byte
+	Proto           string
+	TxnBlob         []byte
+	GroupIndex      int
+	BalanceBlob     []byte
+	Round           uint64
+	LatestTimestamp int64
+	RunMode         string
+	AppID           uint64
+}
+
+type evalMode int
+
+const (
+	modeSignature evalMode = iota
+	modeApplication
+)
+
+// evaluation is a single program to run against a transaction of the group
+type evaluation struct {
+	name       string
+	program    []byte
+	groupIndex int
+	mode       evalMode
+	appIdx     basics.AppIndex
+
+	// source and sourceMap are set when the program was given as TEAL source
+	source    string
+	sourceMap *logic.SourceMap
+}
+
+// evalResult is the outcome of an evaluation
+type evalResult struct {
+	pass bool
+	err  error
+}
+
+// LocalRunner runs programs locally against a transaction group and a
+// balance records snapshot, reporting every step to the Debugger
+type LocalRunner struct {
+	debugger *Debugger
+	proto    config.ConsensusParams
+	txnGroup []transactions.SignedTxn
+	ledger   *localLedger
+	runs     []evaluation
+}
+
+// MakeLocalRunner creates LocalRunner
+func MakeLocalRunner(debugger *Debugger) *LocalRunner {
+	return &LocalRunner{debugger: debugger}
+}
+
+func decodeTxnGroup(data []byte) ([]transactions.SignedTxn, error) {
+	var txnGroup []transactions.SignedTxn
+	if err := protocol.DecodeJSON(data, &txnGroup); err == nil {
+		return txnGroup, nil
+	}
+	var txn transactions.SignedTxn
+	if err := protocol.DecodeJSON(data, &txn); err == nil {
+		return []transactions.SignedTxn{txn}, nil
+	}
+
+	// msgpack encoded transactions are concatenated, as written by goal
+	dec := protocol.NewDecoderBytes(data)
+	for {
+		var txn transactions.SignedTxn
+		err := dec.Decode(&txn)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode transactions as json or msgpack: %v", err)
+		}
+		txnGroup = append(txnGroup, txn)
+	}
+	return txnGroup, nil
+}
+
+func decodeBalanceRecords(data []byte) ([]basics.BalanceRecord, error) {
+	var records []basics.BalanceRecord
+	if err := protocol.DecodeJSON(data, &records); err == nil {
+		return records, nil
+	}
+	var record basics.BalanceRecord
+	if err := protocol.DecodeJSON(data, &record); err == nil {
+		return []basics.BalanceRecord{record}, nil
+	}
+	if err := protocol.DecodeReflect(data, &records); err == nil {
+		return records, nil
+	}
+
+	dec := protocol.NewDecoderBytes(data)
+	for {
+		var record basics.BalanceRecord
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode balance records as json or msgpack: %v", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// isText reports whether data looks like TEAL source. Compiled programs
+// start with a non-printable version byte.
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadProgram accepts either TEAL source or compiled bytecode.
+// The source map is only returned for TEAL source.
+func loadProgram(name string, data []byte) ([]byte, *logic.SourceMap, error) {
+	if isText(data) {
+		program, sm, err := logic.AssembleStringWithSourceMap(string(data))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", name, err)
+		}
+		sm.Sources = []string{name}
+		return program, &sm, nil
+	}
+	_, err := logic.Disassemble(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return data, nil, nil
+}
+
+// Setup validates the parameters and prepares the evaluations to run
+func (r *LocalRunner) Setup(dp *DebugParams) (err error) {
+	ver := protocol.ConsensusCurrentVersion
+	if len(dp.Proto) > 0 {
+		ver = protocol.ConsensusVersion(dp.Proto)
+	}
+	proto, ok := config.Consensus[ver]
+	if !ok {
+		return fmt.Errorf("unknown protocol version %s", ver)
+	}
+	r.proto = proto
+
+	if len(dp.TxnBlob) > 0 {
+		r.txnGroup, err = decodeTxnGroup(dp.TxnBlob)
+		if err != nil {
+			return
+		}
+	}
+	if len(r.txnGroup) == 0 {
+		// evaluate against an empty transaction
+		r.txnGroup = []transactions.SignedTxn{{}}
+	}
+
+	var records []basics.BalanceRecord
+	if len(dp.BalanceBlob) > 0 {
+		records, err = decodeBalanceRecords(dp.BalanceBlob)
+		if err != nil {
+			return
+		}
+	}
+	r.ledger = makeLocalLedger(records, basics.Round(dp.Round), dp.LatestTimestamp)
+
+	if len(dp.ProgramBlobs) > 0 {
+		if dp.GroupIndex < 0 || dp.GroupIndex >= len(r.txnGroup) {
+			return fmt.Errorf("invalid group index %d for a txn group of %d", dp.GroupIndex, len(r.txnGroup))
+		}
+		txn := &r.txnGroup[dp.GroupIndex].Txn
+
+		var mode evalMode
+		switch dp.RunMode {
+		case "signature":
+			mode = modeSignature
+		case "application":
+			mode = modeApplication
+		case "auto", "":
+			mode = modeSignature
+			if txn.Type == protocol.ApplicationCallTx {
+				mode = modeApplication
+			}
+		default:
+			return fmt.Errorf("unknown run mode %s", dp.RunMode)
+		}
+
+		for i, data := range dp.ProgramBlobs {
+			name := dp.ProgramNames[i]
+			program, sm, err := loadProgram(name, data)
+			if err != nil {
+				return err
+			}
+			r.runs = append(r.runs, evaluation{
+				name:       name,
+				program:    program,
+				groupIndex: dp.GroupIndex,
+				mode:       mode,
+				appIdx:     r.appIndex(txn, dp.AppID),
+				source:     string(data),
+				sourceMap:  sm,
+			})
+		}
+		return nil
+	}
+
+	// no programs given, take them from the transaction group
+	for gi := range r.txnGroup {
+		stxn := &r.txnGroup[gi]
+		if len(stxn.Lsig.Logic) > 0 {
+			r.runs = append(r.runs, evaluation{
+				name:       fmt.Sprintf("txn[%d].lsig", gi),
+				program:    stxn.Lsig.Logic,
+				groupIndex: gi,
+				mode:       modeSignature,
+			})
+		}
+		if stxn.Txn.Type != protocol.ApplicationCallTx {
+			continue
+		}
+
+		appIdx := r.appIndex(&stxn.Txn, dp.AppID)
+		program := stxn.Txn.ApprovalProgram
+		field := "approv"
+		if stxn.Txn.OnCompletion == transactions.ClearStateOC {
+			program = stxn.Txn.ClearStateProgram
+			field = "clearp"
+		}
+		if stxn.Txn.ApplicationID != 0 {
+			params, ok := r.ledger.appParams(appIdx)
+			if !ok {
+				return fmt.Errorf("txn[%d]: no creator balance record for app %d", gi, appIdx)
+			}
+			program = params.ApprovalProgram
+			if stxn.Txn.OnCompletion == transactions.ClearStateOC {
+				program = params.ClearStateProgram
+			}
+		}
+		if len(program) == 0 {
+			continue
+		}
+		r.runs = append(r.runs, evaluation{
+			name:       fmt.Sprintf("txn[%d].%s", gi, field),
+			program:    program,
+			groupIndex: gi,
+			mode:       modeApplication,
+			appIdx:     appIdx,
+		})
+	}
+	if len(r.runs) == 0 {
+		return fmt.Errorf("no programs found in the transaction group")
+	}
+	return nil
+}
+
+// appIndex returns the application the transaction calls. A created
+// application gets the next free index and its parameters are added to
+// the sender's balance record.
+func (r *LocalRunner) appIndex(txn *transactions.Transaction, appID uint64) basics.AppIndex {
+	if txn.ApplicationID != 0 {
+		return txn.ApplicationID
+	}
+	if appID != 0 {
+		return basics.AppIndex(appID)
+	}
+	if txn.Type != protocol.ApplicationCallTx {
+		return 0
+	}
+	appIdx := basics.AppIndex(r.ledger.maxIndex() + 1)
+	r.ledger.createApp(txn.Sender, appIdx, basics.AppParams{
+		ApprovalProgram:   txn.ApprovalProgram,
+		ClearStateProgram: txn.ClearStateProgram,
+		LocalStateSchema:  txn.LocalStateSchema,
+		GlobalStateSchema: txn.GlobalStateSchema,
+	})
+	return appIdx
+}
+
+// RunAll evaluates all the programs one by one
+func (r *LocalRunner) RunAll() []evalResult {
+	results := make([]evalResult, len(r.runs))
+	for i, run := range r.runs {
+		r.debugger.SaveProgram(run.name, run.program)
+		if run.sourceMap != nil {
+			r.debugger.SaveSource(run.program, run.source, *run.sourceMap)
+		}
+		r.ledger.appIdx = run.appIdx
+
+		ep := logic.EvalParams{
+			Txn:        &r.txnGroup[run.groupIndex],
+			Proto:      &r.proto,
+			TxnGroup:   r.txnGroup,
+			GroupIndex: run.groupIndex,
+			Debugger:   r.debugger,
+		}
+		if run.mode == modeApplication {
+			ep.Ledger = r.ledger
+			results[i].pass, _, results[i].err = logic.EvalStateful(run.program, ep)
+		} else {
+			results[i].pass, results[i].err = logic.Eval(run.program, ep)
+		}
+	}
+	return results
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// localLedger implements logic.LedgerForLogic on top of a balance records
+// snapshot supplied by the user
+type localLedger struct {
+	round           basics.Round
+	latestTimestamp int64
+	balances        map[basics.Address]basics.AccountData
+
+	// appIdx is the application currently being evaluated
+	appIdx basics.AppIndex
+}
+
+func makeLocalLedger(records []basics.BalanceRecord, round basics.Round, latestTimestamp int64) *localLedger {
+	balances := make(map[basics.Address]basics.AccountData, len(records))
+	for _, record := range records {
+		balances[record.Addr] = record.AccountData
+	}
+	return &localLedger{
+		round:           round,
+		latestTimestamp: latestTimestamp,
+		balances:        balances,
+	}
+}
+
+// appParams finds the parameters of an application in its creator's record
+func (l *localLedger) appParams(appIdx basics.AppIndex) (basics.AppParams, bool) {
+	params, _, ok := l.appParamsAndCreator(appIdx)
+	return params, ok
+}
+
+// appParamsAndCreator is like appParams but also returns the creator address
+func (l *localLedger) appParamsAndCreator(appIdx basics.AppIndex) (basics.AppParams, basics.Address, bool) {
+	for addr, ad := range l.balances {
+		if params, ok := ad.AppParams[appIdx]; ok {
+			return params, addr, true
+		}
+	}
+	return basics.AppParams{}, basics.Address{}, false
+}
+
+// maxIndex returns the largest application or asset index in the snapshot
+func (l *localLedger) maxIndex() uint64 {
+	var max uint64
+	for _, ad := range l.balances {
+		for appIdx := range ad.AppParams {
+			if uint64(appIdx) > max {
+				max = uint64(appIdx)
+			}
+		}
+		for assetIdx := range ad.AssetParams {
+			if uint64(assetIdx) > max {
+				max = uint64(assetIdx)
+			}
+		}
+	}
+	return max
+}
+
+// createApp adds the parameters of an application created by the evaluated
+// transaction, so that its global state can be read
+func (l *localLedger) createApp(creator basics.Address, appIdx basics.AppIndex, params basics.AppParams) {
+	ad := l.balances[creator]
+	apps := make(map[basics.AppIndex]basics.AppParams, len(ad.AppParams)+1)
+	for k, v := range ad.AppParams {
+		apps[k] = v
+	}
+	apps[appIdx] = params
+	ad.AppParams = apps
+	l.balances[creator] = ad
+}
+
+func (l *localLedger) Balance(addr basics.Address) (basics.MicroAlgos, error) {
+	ad, ok := l.balances[addr]
+	if !ok {
+		return basics.MicroAlgos{}, fmt.Errorf("no balance record for %s", addr.String())
+	}
+	return ad.MicroAlgos, nil
+}
+
+func (l *localLedger) Round() basics.Round {
+	return l.round
+}
+
+func (l *localLedger) LatestTimestamp() int64 {
+	return l.latestTimestamp
+}
+
+func (l *localLedger) AppGlobalState(appIdx basics.AppIndex) (basics.TealKeyValue, error) {
+	if appIdx == 0 {
+		appIdx = l.appIdx
+	}
+	params, ok := l.appParams(appIdx)
+	if !ok {
+		return nil, fmt.Errorf("no creator balance record for app %d", appIdx)
+	}
+	return params.GlobalState, nil
+}
+
+func (l *localLedger) AppLocalState(addr basics.Address, appIdx basics.AppIndex) (basics.TealKeyValue, error) {
+	if appIdx == 0 {
+		appIdx = l.appIdx
+	}
+	ad, ok := l.balances[addr]
+	if !ok {
+		return nil, fmt.Errorf("no balance record for %s", addr.String())
+	}
+	state, ok := ad.AppLocalStates[appIdx]
+	if !ok {
+		return nil, fmt.Errorf("%s has not opted in to app %d", addr.String(), appIdx)
+	}
+	return state.KeyValue, nil
+}
+
+func (l *localLedger) AssetHolding(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetHolding, error) {
+	ad, ok := l.balances[addr]
+	if !ok {
+		return basics.AssetHolding{}, fmt.Errorf("no balance record for %s", addr.String())
+	}
+	holding, ok := ad.Assets[assetIdx]
+	if !ok {
+		return basics.AssetHolding{}, fmt.Errorf("%s has not opted in to asset %d", addr.String(), assetIdx)
+	}
+	return holding, nil
+}
+
+func (l *localLedger) AssetParams(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetParams, error) {
+	ad, ok := l.balances[addr]
+	if !ok {
+		return basics.AssetParams{}, fmt.Errorf("no balance record for %s", addr.String())
+	}
+	params, ok := ad.AssetParams[assetIdx]
+	if !ok {
+		return basics.AssetParams{}, fmt.Errorf("%s has not created asset %d", addr.String(), assetIdx)
+	}
+	return params, nil
+}
+
+func (l *localLedger) AppParams(appIdx basics.AppIndex) (basics.AppParams, basics.Address, error) {
+	if appIdx == 0 {
+		appIdx = l.appIdx
+	}
+	params, creator, ok := l.appParamsAndCreator(appIdx)
+	if !ok {
+		return basics.AppParams{}, basics.Address{}, fmt.Errorf("no creator balance record for app %d", appIdx)
+	}
+	return params, creator, nil
+}
+
+func (l *localLedger) MinBalance(addr basics.Address, proto *config.ConsensusParams) (basics.MicroAlgos, error) {
+	ad, ok := l.balances[addr]
+	if !ok {
+		return basics.MicroAlgos{}, fmt.Errorf("no balance record for %s", addr.String())
+	}
+	return ad.MinBalance(proto), nil
+}
+
+func (l *localLedger) Authorizer(addr basics.Address) (basics.Address, error) {
+	ad, ok := l.balances[addr]
+	if !ok {
+		return basics.Address{}, fmt.Errorf("no balance record for %s", addr.String())
+	}
+	return ad.AuthAddr, nil
+}
+
+func (l *localLedger) ApplicationID() basics.AppIndex {
+	return l.appIdx
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestDecodeTxnGroup(t *testing.T) {
+	var txn transactions.SignedTxn
+	txn.Txn.Type = protocol.PaymentTx
+	crypto.RandBytes(txn.Txn.Sender[:])
+	txns := []transactions.SignedTxn{txn, txn}
+
+	decoded, err := decodeTxnGroup(protocol.EncodeJSON(&txns))
+	require.NoError(t, err)
+	require.Equal(t, txns, decoded)
+
+	decoded, err = decodeTxnGroup(protocol.EncodeJSON(&txn))
+	require.NoError(t, err)
+	require.Equal(t, txns[:1], decoded)
+
+	var blob []byte
+	for _, stxn := range txns {
+		blob = append(blob, protocol.Encode(&stxn)...)
+	}
+	decoded, err = decodeTxnGroup(blob)
+	require.NoError(t, err)
+	require.Equal(t, txns, decoded)
+
+	_, err = decodeTxnGroup([]byte{0xff, 0x00})
+	require.Error(t, err)
+}
+
+func TestDecodeBalanceRecords(t *testing.T) {
+	var record basics.BalanceRecord
+	crypto.RandBytes(record.Addr[:])
+	record.MicroAlgos.Raw = 1000000
+	records := []basics.BalanceRecord{record}
+
+	decoded, err := decodeBalanceRecords(protocol.EncodeJSON(&records))
+	require.NoError(t, err)
+	require.Equal(t, records, decoded)
+
+	decoded, err = decodeBalanceRecords(protocol.EncodeJSON(&record))
+	require.NoError(t, err)
+	require.Equal(t, records, decoded)
+
+	decoded, err = decodeBalanceRecords(protocol.EncodeReflect(&records))
+	require.NoError(t, err)
+	require.Equal(t, records, decoded)
+
+	decoded, err = decodeBalanceRecords(protocol.Encode(&record))
+	require.NoError(t, err)
+	require.Equal(t, records, decoded)
+}
+
+func TestLoadProgram(t *testing.T) {
+	program, sm, err := loadProgram("test", []byte("int 1"))
+	require.NoError(t, err)
+	require.NotNil(t, sm)
+	require.Equal(t, []string{"test"}, sm.Sources)
+
+	loaded, sm, err := loadProgram("test", program)
+	require.NoError(t, err)
+	require.Equal(t, program, loaded)
+	require.Nil(t, sm)
+
+	_, _, err = loadProgram("test", []byte("not a teal opcode"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "test")
+}
+
+func TestLocalRunnerSetup(t *testing.T) {
+	a := require.New(t)
+
+	approval, err := logic.AssembleString("int 1")
+	a.NoError(err)
+	lsig, err := logic.AssembleString("int 0")
+	a.NoError(err)
+
+	var sender basics.Address
+	crypto.RandBytes(sender[:])
+
+	// an app create call signed by a logic sig
+	var txn transactions.SignedTxn
+	txn.Lsig.Logic = lsig
+	txn.Txn.Type = protocol.ApplicationCallTx
+	txn.Txn.Sender = sender
+	txn.Txn.ApprovalProgram = approval
+	txn.Txn.ClearStateProgram = approval
+
+	record := basics.BalanceRecord{Addr: sender}
+	record.MicroAlgos.Raw = 1000000
+	record.AssetParams = map[basics.AssetIndex]basics.AssetParams{10: {Total: 1}}
+
+	dp := DebugParams{
+		Proto:       string(protocol.ConsensusFuture),
+		TxnBlob:     protocol.EncodeJSON(&txn),
+		BalanceBlob: protocol.EncodeJSON(&record),
+	}
+	local := MakeLocalRunner(MakeDebugger())
+	a.NoError(local.Setup(&dp))
+	a.Equal(2, len(local.runs))
+	a.Equal("txn[0].lsig", local.runs[0].name)
+	a.Equal(modeSignature, local.runs[0].mode)
+	a.Equal("txn[0].approv", local.runs[1].name)
+	a.Equal(modeApplication, local.runs[1].mode)
+	// the created app gets the next free index
+	a.Equal(basics.AppIndex(11), local.runs[1].appIdx)
+	params, ok := local.ledger.appParams(11)
+	a.True(ok)
+	a.Equal(approval, params.ApprovalProgram)
+
+	// explicit programs use the mode of the transaction
+	dp.ProgramNames = []string{"test.teal"}
+	dp.ProgramBlobs = [][]byte{[]byte("int 1")}
+	dp.AppID = 5
+	local = MakeLocalRunner(MakeDebugger())
+	a.NoError(local.Setup(&dp))
+	a.Equal(1, len(local.runs))
+	a.Equal(modeApplication, local.runs[0].mode)
+	a.Equal(basics.AppIndex(5), local.runs[0].appIdx)
+
+	dp.RunMode = "signature"
+	local = MakeLocalRunner(MakeDebugger())
+	a.NoError(local.Setup(&dp))
+	a.Equal(modeSignature, local.runs[0].mode)
+
+	dp.RunMode = "unknown"
+	local = MakeLocalRunner(MakeDebugger())
+	a.Error(local.Setup(&dp))
+
+	dp.RunMode = ""
+	dp.GroupIndex = 1
+	local = MakeLocalRunner(MakeDebugger())
+	a.Error(local.Setup(&dp))
+
+	dp.GroupIndex = 0
+	dp.Proto = "unknown"
+	local = MakeLocalRunner(MakeDebugger())
+	a.Error(local.Setup(&dp))
+}
+
+func TestLocalRunnerRunAll(t *testing.T) {
+	a := require.New(t)
+
+	var sender basics.Address
+	crypto.RandBytes(sender[:])
+	record := basics.BalanceRecord{Addr: sender}
+	record.MicroAlgos.Raw = 1000000
+
+	var txn transactions.SignedTxn
+	txn.Txn.Type = protocol.ApplicationCallTx
+	txn.Txn.Sender = sender
+
+	dp := DebugParams{
+		ProgramNames: []string{"approval.teal", "logic.teal"},
+		ProgramBlobs: [][]byte{
+			[]byte("byte \"key\"\nint 1\napp_global_put\nint 0\nbalance\nint 1000000\n=="),
+			[]byte("int 1\nint 2\n=="),
+		},
+		Proto:       string(protocol.ConsensusFuture),
+		TxnBlob:     protocol.EncodeJSON(&txn),
+		BalanceBlob: protocol.EncodeJSON(&record),
+		RunMode:     "application",
+	}
+
+	debugger := MakeDebugger()
+	da := makeTestDbgAdapter(t, false)
+	debugger.AddAdapter(da)
+
+	local := MakeLocalRunner(debugger)
+	a.NoError(local.Setup(&dp))
+	results := local.RunAll()
+	a.Equal(2, len(results))
+	a.NoError(results[0].err)
+	a.True(results[0].pass)
+	a.NoError(results[1].err)
+	a.False(results[1].pass)
+}
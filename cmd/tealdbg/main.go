@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "tealdbg",
+	Short: "Algorand TEAL Debugger",
+	Long: `Debug a local or remote TEAL program
+in a Chrome DevTools compatible debugger`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// If no arguments passed, we should fallback to help
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var debugCmd = &cobra.Command{
+	Use:   "debug [program.tok or program.teal ...]",
+	Short: "Debug TEAL program(s) locally",
+	Long: `Debug TEAL program(s) in a controlled environment.
+Programs are taken from the command line or, if none are given, from the
+logic signatures and application calls of the transaction group.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		debugLocal(args)
+	},
+}
+
+var remoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Debug TEAL program on-chain",
+	Long:  `Start the server and wait for debugging events posted by algod or goal (WebDebuggerHook)`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		debugRemote()
+	},
+}
+
+// cobra command parameters
+var proto string
+var txnFile string
+var groupIndex int
+var balanceFile string
+var roundNumber uint64
+var latestTimestamp int64
+var runMode string
+var appID uint64
+var listenAddress string
+var port int
+var verbose bool
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&listenAddress, "listen", "127.0.0.1", "Address to listen on for debugger connections")
+	rootCmd.PersistentFlags().IntVar(&port, "remote-debugging-port", 9392, "Port to listen on")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print the Chrome DevTools Protocol messages")
+
+	debugCmd.Flags().StringVarP(&proto, "proto", "p", "", "Consensus protocol version for TEAL evaluation")
+	debugCmd.Flags().StringVarP(&txnFile, "txn", "t", "", "Transaction(s) to evaluate TEAL on in form of json or msgpack file")
+	debugCmd.Flags().IntVarP(&groupIndex, "group-index", "g", 0, "Transaction index in a txn group")
+	debugCmd.Flags().StringVarP(&balanceFile, "balance", "b", "", "Balance records to evaluate stateful TEAL on in form of json or msgpack file")
+	debugCmd.Flags().Uint64VarP(&roundNumber, "round", "r", 0, "Ledger round number to evaluate stateful TEAL on")
+	debugCmd.Flags().Int64VarP(&latestTimestamp, "latest-timestamp", "l", 0, "Latest confirmed timestamp to evaluate stateful TEAL on")
+	debugCmd.Flags().StringVarP(&runMode, "mode", "m", "auto", "TEAL evaluation mode: signature, application or auto")
+	debugCmd.Flags().Uint64VarP(&appID, "app-id", "a", 0, "Application ID for stateful TEAL if not set in transaction(s)")
+
+	rootCmd.AddCommand(debugCmd)
+	rootCmd.AddCommand(remoteCmd)
+}
+
+func main() {
+	// Hidden command to generate docs in a given directory
+	// tealdbg generate-docs [path]
+	if len(os.Args) == 3 && os.Args[1] == "generate-docs" {
+		err := doc.GenMarkdownTree(rootCmd, os.Args[2])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func debugRemote() {
+	ds := makeDebugServer(listenAddress, port, verbose, nil)
+	err := ds.startRemote()
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+}
+
+func debugLocal(args []string) {
+	programNames := args
+	programBlobs := make([][]byte, len(args))
+	for i, file := range args {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatalf("Error reading program %s: %s", file, err)
+		}
+		programBlobs[i] = data
+	}
+
+	var txnBlob []byte
+	var err error
+	if len(txnFile) > 0 {
+		txnBlob, err = ioutil.ReadFile(txnFile)
+		if err != nil {
+			log.Fatalf("Error reading txn %s: %s", txnFile, err)
+		}
+	}
+
+	var balanceBlob []byte
+	if len(balanceFile) > 0 {
+		balanceBlob, err = ioutil.ReadFile(balanceFile)
+		if err != nil {
+			log.Fatalf("Error reading balance records %s: %s", balanceFile, err)
+		}
+	}
+
+	dp := DebugParams{
+		ProgramNames:    programNames,
+		ProgramBlobs:    programBlobs,
+		Proto:           proto,
+		TxnBlob:         txnBlob,
+		GroupIndex:      groupIndex,
+		BalanceBlob:     balanceBlob,
+		Round:           roundNumber,
+		LatestTimestamp: latestTimestamp,
+		RunMode:         runMode,
+		AppID:           appID,
+	}
+
+	ds := makeDebugServer(listenAddress, port, verbose, &dp)
+	err = ds.startDebug()
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+}
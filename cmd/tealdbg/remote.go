@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// RemoteHookAdapter receives the events posted by logic.WebDebuggerHook
+// and forwards them to the Debugger
+type RemoteHookAdapter struct {
+	debugger *Debugger
+}
+
+// MakeRemoteHook creates new RemoteHookAdapter
+func MakeRemoteHook(debugger *Debugger) *RemoteHookAdapter {
+	return &RemoteHookAdapter{debugger: debugger}
+}
+
+// Setup adds HTTP handlers for the remote WebDebuggerHook
+func (rha *RemoteHookAdapter) Setup(router *mux.Router) {
+	router.HandleFunc("/exec/register", rha.registerHandler).Methods("POST")
+	router.HandleFunc("/exec/update", rha.updateHandler).Methods("POST")
+	router.HandleFunc("/exec/complete", rha.completeHandler).Methods("POST")
+}
+
+func (rha *RemoteHookAdapter) decodeState(w http.ResponseWriter, r *http.Request) (state logic.DebugState, ok bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = protocol.DecodeJSON(body, &state)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(state.ExecID) == 0 {
+		http.Error(w, "missing execution id", http.StatusBadRequest)
+		return
+	}
+	return state, true
+}
+
+func (rha *RemoteHookAdapter) handle(w http.ResponseWriter, r *http.Request, hook func(*logic.DebugState) error) {
+	state, ok := rha.decodeState(w, r)
+	if !ok {
+		return
+	}
+	err := hook(&state)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rha *RemoteHookAdapter) registerHandler(w http.ResponseWriter, r *http.Request) {
+	rha.handle(w, r, rha.debugger.Register)
+}
+
+func (rha *RemoteHookAdapter) updateHandler(w http.ResponseWriter, r *http.Request) {
+	rha.handle(w, r, rha.debugger.Update)
+}
+
+func (rha *RemoteHookAdapter) completeHandler(w http.ResponseWriter, r *http.Request) {
+	rha.handle(w, r, rha.debugger.Complete)
+}
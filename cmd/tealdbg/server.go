@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// DebugServer is the HTTP server hosting the debugger frontend and,
+// in remote mode, the WebDebuggerHook endpoints
+type DebugServer struct {
+	debugger *Debugger
+	frontend DebugAdapter
+	router   *mux.Router
+	server   *http.Server
+	params   *DebugParams
+}
+
+func makeDebugServer(address string, port int, verbose bool, dp *DebugParams) DebugServer {
+	debugger := MakeDebugger()
+	router := mux.NewRouter()
+	appAddress := fmt.Sprintf("%s:%d", address, port)
+
+	frontend := MakeCdtFrontend(router, appAddress, verbose)
+	debugger.AddAdapter(frontend)
+
+	server := &http.Server{
+		Handler: router,
+		Addr:    appAddress,
+	}
+
+	return DebugServer{
+		debugger: debugger,
+		frontend: frontend,
+		router:   router,
+		server:   server,
+		params:   dp,
+	}
+}
+
+// startRemote serves the WebDebuggerHook endpoints until the process is stopped
+func (ds *DebugServer) startRemote() error {
+	remote := MakeRemoteHook(ds.debugger)
+	remote.Setup(ds.router)
+
+	log.Printf("starting server on %s", ds.server.Addr)
+	return ds.server.ListenAndServe()
+}
+
+// startDebug runs the local programs and waits until the frontend is done
+// with all of them
+func (ds *DebugServer) startDebug() error {
+	local := MakeLocalRunner(ds.debugger)
+	err := local.Setup(ds.params)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", ds.server.Addr)
+	if err != nil {
+		return err
+	}
+	go ds.server.Serve(listener)
+	defer ds.server.Shutdown(context.Background())
+
+	results := local.RunAll()
+	ds.frontend.WaitForCompletion()
+
+	for i, res := range results {
+		status := "REJECT"
+		if res.pass {
+			status = "PASS"
+		}
+		if res.err != nil {
+			status = fmt.Sprintf("ERROR: %v", res.err)
+		}
+		fmt.Printf("%s: %s\n", local.runs[i].name, status)
+	}
+	return nil
+}
@@ -60,6 +60,10 @@ func (network *MockNetwork) RequestConnectOutgoing(replace bool, quit <-chan str
 func (network *MockNetwork) Disconnect(badpeer network.Peer) {
 }
 
+// Penalize - unused function
+func (network *MockNetwork) Penalize(badpeer network.Peer) {
+}
+
 // DisconnectPeers - unused function
 func (network *MockNetwork) DisconnectPeers() {
 }
@@ -60,7 +60,7 @@ type Local struct {
 	// Version tracks the current version of the defaults so we can migrate old -> new
 	// This is specifically important whenever we decide to change the default value
 	// for an existing parameter. This field tag must be updated any time we add a new version.
-	Version uint32 `version[0]:"0" version[1]:"1" version[2]:"2" version[3]:"3" version[4]:"4" version[5]:"5" version[6]:"6" version[7]:"7" version[8]:"8" version[9]:"9"`
+	Version uint32 `version[0]:"0" version[1]:"1" version[2]:"2" version[3]:"3" version[4]:"4" version[5]:"5" version[6]:"6" version[7]:"7" version[8]:"8" version[9]:"9" version[10]:"10"`
 
 	// environmental (may be overridden)
 	// When enabled, stores blocks indefinitally, otherwise, only the most recents blocks
@@ -321,6 +321,86 @@ type Local struct {
 	// EnableDeveloperAPI enables teal/compile, teal/dryrun API endpoints.
 	// This functionlity is disabled by default.
 	EnableDeveloperAPI bool `version[9]:"false"`
+
+	// EnableAccountHistory makes an archival node keep the state of the accounts at every round it commits, so
+	// that the ledger can answer account and totals lookups for any of these rounds. It requires Archival to be set.
+	// The history starts at the round the node was at when it got enabled, and disabling it deletes the stored history.
+	EnableAccountHistory bool `version[10]:"false"`
+
+	// BlockRetentionRounds sets the number of most recent rounds whose blocks are kept by a non-archival node,
+	// on top of the ones the ledger needs. 0 keeps only the blocks the ledger needs.
+	BlockRetentionRounds uint64 `version[10]:"0"`
+
+	// BlockRetentionDays makes a non-archival node keep the blocks of the given number of most recent days,
+	// on top of the ones the ledger needs. 0 disables the time based retention.
+	BlockRetentionDays uint64 `version[10]:"0"`
+
+	// BlockRetentionKeepCatchpoints makes a non-archival node keep the blocks of the catchpoint rounds, which
+	// the nodes catching up from these catchpoints request.
+	BlockRetentionKeepCatchpoints bool `version[10]:"false"`
+
+	// BlockStorageBackend selects the storage engine of the blocks database: "sqlite", or "kv" for the pure Go
+	// log-structured key-value store. The blocks of an existing ledger are not converted, so the backend can only be
+	// changed on a node whose ledger gets created from scratch.
+	BlockStorageBackend string `version[10]:"sqlite"`
+
+	// TrackerStorageBackend selects the storage engine of the tracker database, which holds the accounts and the
+	// catchpoints: "sqlite", or "kv" for the pure Go log-structured key-value store. The accounts of an existing
+	// ledger are not converted, so the backend can only be changed on a node whose ledger gets created from scratch.
+	TrackerStorageBackend string `version[10]:"sqlite"`
+
+	// EnablePeerIdentity makes the node prove its identity to its gossip peers, and ask them to prove theirs, with an
+	// ed25519 key kept in the PeerIdentityFilename file of the data directory. The peers that prove an identity are
+	// keyed by it, so that a second connection with the same identity is rejected.
+	EnablePeerIdentity bool `version[10]:"false"`
+
+	// PeerIdentityAllowlist lists the identities, written as addresses, of the only gossip peers the node connects
+	// with when EnablePeerIdentity is set; the peers that don't prove an identity are rejected. An empty list
+	// allows any peer.
+	PeerIdentityAllowlist map[string]bool `version[10]:""`
+
+	// PeerIdentityDenylist lists the identities, written as addresses, of the gossip peers the node refuses to
+	// connect with when EnablePeerIdentity is set.
+	PeerIdentityDenylist map[string]bool `version[10]:""`
+
+	// EnableMessageCompression lets the node negotiate a compression scheme with its gossip peers, with which the
+	// biggest proposal payloads and transaction messages are compressed. Peers that don't support it get all the
+	// messages as is.
+	EnableMessageCompression bool `version[10]:"true"`
+
+	// EnableTxnAnnouncements makes the node gossip the transaction groups it relays by announcing their txid, and
+	// request the groups announced by its peers that it doesn't have yet, instead of flooding them in full. It's
+	// negotiated with each peer when connecting; the peers that don't enable it keep getting the groups in full.
+	EnableTxnAnnouncements bool `version[10]:"false"`
+
+	// EnablePeerScoring makes the node score its gossip peers by the penalties of the messages its handlers reject,
+	// keyed by host and by identity. A peer whose score reaches PeerBanScore is banned for PeerBanDurationSeconds,
+	// and one whose score is above half of it can't connect until its score decays below it. The bans are
+	// persisted in the PeerBansFilename file of the data directory.
+	EnablePeerScoring bool `version[10]:"false"`
+
+	// PeerBanScore is the score at which a peer gets banned; a message for which a peer gets disconnected costs 50.
+	PeerBanScore uint64 `version[10]:"100"`
+
+	// PeerScoreHalfLifeSeconds is the time it takes for the score of a peer to decay by half.
+	PeerScoreHalfLifeSeconds int `version[10]:"600"`
+
+	// PeerBanDurationSeconds is how long a peer stays banned.
+	PeerBanDurationSeconds int `version[10]:"3600"`
+
+	// IncomingMessageRateLimits caps, by message tag, the number of messages per second each gossip peer can send,
+	// e.g. {"TX": 500}. The messages over the limit are dropped before they are read; the tags that aren't listed
+	// aren't limited. Once the node announced the tags it is interested in, the messages of the listed tags outside
+	// of them are dropped too. Compressed messages are limited by the tag of the message they hold.
+	IncomingMessageRateLimits map[string]uint64 `version[10]:""`
+
+	// IncomingMessageRateBurstSeconds is the number of seconds of its IncomingMessageRateLimits rate a peer can
+	// send at once, after being quiet.
+	IncomingMessageRateBurstSeconds int `version[10]:"2"`
+
+	// EnableLedgerPrefetch makes the ledger load the accounts and creators accessed by a block concurrently before
+	// evaluating it, rather than one at a time as the evaluation reaches them.
+	EnableLedgerPrefetch bool `version[10]:"true"`
 }
 
 // Filenames of config files within the configdir (e.g. ~/.algorand)
@@ -338,6 +418,12 @@ const LedgerFilenamePrefix = "ledger"
 // It is used to recover from node crashes.
 const CrashFilename = "crash.sqlite"
 
+// PeerBansFilename is the name of the file, in the data directory, the bans of misbehaving peers are persisted to.
+const PeerBansFilename = "peerbans.json"
+
+// PeerIdentityFilename is the name of the file holding the seed of the key the node proves its gossip identity with
+const PeerIdentityFilename = "peeridentity.seed"
+
 // ConfigurableConsensusProtocolsFilename defines a set of consensus prototocols that
 // are to be loaded from the data directory ( if present ), to override the
 // built-in supported consensus protocols.
@@ -287,6 +287,10 @@ type ConsensusParams struct {
 	// maximum total minimum balance requirement for an account, used
 	// to limit the maximum size of a single balance record
 	MaximumMinimumBalance uint64
+
+	// maximum number of inner transactions that a single application call
+	// may issue from its application account
+	MaxInnerTransactions int
 }
 
 // ConsensusProtocols defines a set of supported protocol versions and their
@@ -325,6 +329,11 @@ var MaxTxGroupSize int
 // of the consensus protocols. used for decoding purposes.
 var MaxAppProgramLen int
 
+// MaxInnerTransactions is the largest number of inner transactions a single
+// application call may issue in any of the consensus protocols. used for
+// decoding purposes.
+var MaxInnerTransactions int
+
 func checkSetMax(value int, curMax *int) {
 	if value > *curMax {
 		*curMax = value
@@ -352,6 +361,7 @@ func checkSetAllocBounds(p ConsensusParams) {
 	checkSetMax(int(p.LogicSigMaxSize), &MaxLogicSigMaxSize)
 	checkSetMax(p.MaxTxnNoteBytes, &MaxTxnNoteBytes)
 	checkSetMax(p.MaxTxGroupSize, &MaxTxGroupSize)
+	checkSetMax(p.MaxInnerTransactions, &MaxInnerTransactions)
 }
 
 // SaveConfigurableConsensus saves the configurable protocols file to the provided data directory.
@@ -691,7 +701,7 @@ func initConsensusProtocols() {
 	// but not yet released in a production protocol version.
 	vFuture := v23
 	vFuture.ApprovedUpgrades = map[protocol.ConsensusVersion]uint64{}
-	vFuture.LogicSigVersion = 2
+	vFuture.LogicSigVersion = 4
 
 	// Enable application support
 	vFuture.Application = true
@@ -741,6 +751,9 @@ func initConsensusProtocols() {
 
 	// Maximum number of apps a single account can opt into
 	vFuture.MaxAppsOptedIn = 10
+
+	// Maximum number of inner transactions a single app call can issue
+	vFuture.MaxInnerTransactions = 16
 	Consensus[protocol.ConsensusFuture] = vFuture
 }
 
@@ -20,10 +20,14 @@
 package config
 
 var defaultLocal = Local{
-	Version:                               9,
+	Version:                               10,
 	AnnounceParticipationKey:              true,
 	Archival:                              false,
 	BaseLoggerDebugLevel:                  4,
+	BlockRetentionDays:                    0,
+	BlockRetentionKeepCatchpoints:         false,
+	BlockRetentionRounds:                  0,
+	BlockStorageBackend:                   "sqlite",
 	BroadcastConnectionsLimit:             -1,
 	CadaverSizeTarget:                     1073741824,
 	CatchpointFileHistoryLength:           365,
@@ -40,6 +44,7 @@ var defaultLocal = Local{
 	DNSSecurityFlags:                      1,
 	DeadlockDetection:                     0,
 	DisableOutgoingConnectionThrottling:   false,
+	EnableAccountHistory:                  false,
 	EnableAgreementReporting:              false,
 	EnableAgreementTimeMetrics:            false,
 	EnableAssembleStats:                   false,
@@ -47,14 +52,19 @@ var defaultLocal = Local{
 	EnableDeveloperAPI:                    false,
 	EnableGossipBlockService:              true,
 	EnableIncomingMessageFilter:           false,
+	EnableLedgerPrefetch:                  true,
 	EnableLedgerService:                   false,
+	EnableMessageCompression:              true,
 	EnableMetricReporting:                 false,
 	EnableOutgoingNetworkMessageFiltering: true,
+	EnablePeerIdentity:                    false,
+	EnablePeerScoring:                     false,
 	EnablePingHandler:                     true,
 	EnableProcessBlockStats:               false,
 	EnableProfiler:                        false,
 	EnableRequestLogger:                   false,
 	EnableTopAccountsReporting:            false,
+	EnableTxnAnnouncements:                false,
 	EndpointAddress:                       "127.0.0.1:0",
 	FallbackDNSResolverAddress:            "",
 	ForceRelayMessages:                    false,
@@ -62,6 +72,8 @@ var defaultLocal = Local{
 	IncomingConnectionsLimit:              10000,
 	IncomingMessageFilterBucketCount:      5,
 	IncomingMessageFilterBucketSize:       512,
+	IncomingMessageRateBurstSeconds:       2,
+	IncomingMessageRateLimits:             map[string]uint64{},
 	IsIndexerActive:                       false,
 	LogArchiveMaxAge:                      "",
 	LogArchiveName:                        "node.archive.log",
@@ -73,8 +85,13 @@ var defaultLocal = Local{
 	NodeExporterPath:                      "./node_exporter",
 	OutgoingMessageFilterBucketCount:      3,
 	OutgoingMessageFilterBucketSize:       128,
+	PeerBanDurationSeconds:                3600,
+	PeerBanScore:                          100,
 	PeerConnectionsUpdateInterval:         3600,
+	PeerIdentityAllowlist:                 map[string]bool{},
+	PeerIdentityDenylist:                  map[string]bool{},
 	PeerPingPeriodSeconds:                 0,
+	PeerScoreHalfLifeSeconds:              600,
 	PriorityPeers:                         map[string]bool{},
 	PublicAddress:                         "",
 	ReconnectTime:                         60000000000,
@@ -87,6 +104,7 @@ var defaultLocal = Local{
 	TLSCertFile:                           "",
 	TLSKeyFile:                            "",
 	TelemetryToLog:                        true,
+	TrackerStorageBackend:                 "sqlite",
 	TxPoolExponentialIncreaseFactor:       2,
 	TxPoolSize:                            15000,
 	TxSyncIntervalSeconds:                 60,
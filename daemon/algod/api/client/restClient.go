@@ -56,7 +56,9 @@ const (
 
 // rawRequestPaths is a set of paths where the body should not be urlencoded
 var rawRequestPaths = map[string]bool{
-	"/v1/transactions": true,
+	"/v1/transactions":          true,
+	"/v2/teal/dryrun":           true,
+	"/v2/transactions/simulate": true,
 }
 
 // RestClient manages the REST interface for a calling user.
@@ -314,6 +316,52 @@ func (client RestClient) AccountInformation(address string) (response v1.Account
 	return
 }
 
+// AccountInformationV2 gets the AccountData associated with the passed address
+func (client RestClient) AccountInformationV2(address string) (response generatedV2.Account, err error) {
+	err = client.get(&response, fmt.Sprintf("/v2/accounts/%s", address), nil)
+	return
+}
+
+type accountInformationParams struct {
+	Round uint64 `url:"round"`
+}
+
+// AccountInformationAtRoundV2 gets the AccountData associated with the passed address at the given round
+func (client RestClient) AccountInformationAtRoundV2(address string, round uint64) (response generatedV2.Account, err error) {
+	err = client.get(&response, fmt.Sprintf("/v2/accounts/%s", address), accountInformationParams{round})
+	return
+}
+
+// AccountApplicationInformation gets the local state of an account for a given application
+func (client RestClient) AccountApplicationInformation(address string, applicationID uint64) (response generatedV2.ApplicationLocalState, err error) {
+	err = client.get(&response, fmt.Sprintf("/v2/accounts/%s/applications/%d", address, applicationID), nil)
+	return
+}
+
+// ApplicationInformation gets information about an application, including its creator and global state
+func (client RestClient) ApplicationInformation(index uint64) (response generatedV2.Application, err error) {
+	err = client.get(&response, fmt.Sprintf("/v2/applications/%d", index), nil)
+	return
+}
+
+type applicationsParams struct {
+	Limit uint64 `url:"limit,omitempty"`
+	Next  string `url:"next,omitempty"`
+}
+
+// Applications gets up to limit applications, starting after the ones of the
+// next token if it isn't empty
+func (client RestClient) Applications(limit uint64, next string) (response generatedV2.ApplicationsResponse, err error) {
+	err = client.get(&response, "/v2/applications", applicationsParams{limit, next})
+	return
+}
+
+// AssetInformationV2 gets the AssetInformationResponse associated with the passed asset index
+func (client RestClient) AssetInformationV2(index uint64) (response generatedV2.Asset, err error) {
+	err = client.get(&response, fmt.Sprintf("/v2/assets/%d", index), nil)
+	return
+}
+
 // TransactionInformation gets information about a specific transaction involving a specific account
 func (client RestClient) TransactionInformation(accountAddress, transactionID string) (response v1.Transaction, err error) {
 	transactionID = stripTransaction(transactionID)
@@ -367,6 +415,18 @@ func (client RestClient) SendRawTransactionGroup(txgroup []transactions.SignedTx
 	return client.post(&response, "/v1/transactions", enc)
 }
 
+// RawDryrun gets a json or msgpack encoded DryrunRequest and returns the per-transaction debugging information
+func (client RestClient) RawDryrun(data []byte) (response generatedV2.DryrunResponse, err error) {
+	err = client.post(&response, "/v2/teal/dryrun", data)
+	return
+}
+
+// RawSimulate gets a json or msgpack encoded SimulateRequest and returns what its transaction groups would apply
+func (client RestClient) RawSimulate(data []byte) (response generatedV2.SimulateResponse, err error) {
+	err = client.post(&response, "/v2/transactions/simulate", data)
+	return
+}
+
 // Block gets the block info for the given round
 func (client RestClient) Block(round uint64) (response v1.Block, err error) {
 	err = client.get(&response, fmt.Sprintf("/v1/block/%d", round), nil)
@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package lib
+
+import (
+	"github.com/algorand/go-algorand/data"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/node"
+)
+
+// CreatableIndexInPayset returns the index of the asset or application created by a transaction of the payset
+// of a block, or 0 if the transaction isn't in the payset. Each transaction of the payset advances the transaction
+// counter by one, plus one for each inner transaction it issued.
+func CreatableIndexInPayset(tx node.TxnWithStatus, txnCounter uint64, payset []transactions.SignedTxnWithAD) uint64 {
+	// Compute transaction index in block, and count the inner
+	// transactions issued before it and in the whole block
+	offset := -1
+	var innerBefore, innerTotal uint64
+	for idx, stxnib := range payset {
+		if offset < 0 && tx.Txn.Txn.ID() == stxnib.Txn.ID() {
+			offset = idx
+			innerBefore = innerTotal
+		}
+		innerTotal += uint64(len(stxnib.ApplyData.InnerTxns))
+	}
+
+	// Sanity check that txn was in fetched block
+	if offset < 0 {
+		return 0
+	}
+
+	// Count into block to get created index
+	return txnCounter - uint64(len(payset)) - innerTotal + uint64(offset) + innerBefore + 1
+}
+
+// ComputeAppIndexFromTxn returns the created app index given a confirmed
+// transaction whose confirmation block is available in the ledger. Note that
+// 0 is an invalid app index (they start at 1).
+func ComputeAppIndexFromTxn(tx node.TxnWithStatus, l *data.Ledger) uint64 {
+	// Must have ledger
+	if l == nil {
+		return 0
+	}
+	// Transaction must be confirmed
+	if tx.ConfirmedRound == 0 {
+		return 0
+	}
+	// Transaction must be ApplicationCall transaction
+	if tx.Txn.Txn.ApplicationCallTxnFields.Empty() {
+		return 0
+	}
+	// Transaction must be creating an application
+	if tx.Txn.Txn.ApplicationCallTxnFields.ApplicationID != 0 {
+		return 0
+	}
+
+	// Look up block where transaction was confirmed
+	blk, err := l.Block(tx.ConfirmedRound)
+	if err != nil {
+		return 0
+	}
+
+	payset, err := blk.DecodePaysetFlat()
+	if err != nil {
+		return 0
+	}
+
+	// Applications and assets share the same index space
+	return CreatableIndexInPayset(tx, blk.BlockHeader.TxnCounter, payset)
+}
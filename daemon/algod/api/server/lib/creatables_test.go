@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/node"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestCreatableIndexInPayset(t *testing.T) {
+	payset := make([]transactions.SignedTxnWithAD, 3)
+	for i := range payset {
+		payset[i].Txn.Type = protocol.AssetConfigTx
+		payset[i].Txn.Note = []byte{byte(i)}
+	}
+	// the first transaction issued two inner transactions.
+	payset[0].ApplyData.InnerTxns = make([]transactions.SignedTxnWithAD, 2)
+
+	// the block starts after transaction 10, and ends with transaction 15.
+	var txnCounter uint64 = 15
+	require.Equal(t, uint64(11), CreatableIndexInPayset(node.TxnWithStatus{Txn: payset[0].SignedTxn}, txnCounter, payset))
+	require.Equal(t, uint64(14), CreatableIndexInPayset(node.TxnWithStatus{Txn: payset[1].SignedTxn}, txnCounter, payset))
+	require.Equal(t, uint64(15), CreatableIndexInPayset(node.TxnWithStatus{Txn: payset[2].SignedTxn}, txnCounter, payset))
+
+	var other transactions.SignedTxn
+	other.Txn.Note = []byte("other")
+	require.Equal(t, uint64(0), CreatableIndexInPayset(node.TxnWithStatus{Txn: other}, txnCounter, payset))
+}
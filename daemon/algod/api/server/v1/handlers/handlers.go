@@ -250,25 +250,6 @@ func txWithStatusEncode(tr node.TxnWithStatus) (v1.Transaction, error) {
 	return s, nil
 }
 
-func computeAssetIndexInPayset(tx node.TxnWithStatus, txnCounter uint64, payset []transactions.SignedTxnWithAD) (aidx uint64) {
-	// Compute transaction index in block
-	offset := -1
-	for idx, stxnib := range payset {
-		if tx.Txn.Txn.ID() == stxnib.Txn.ID() {
-			offset = idx
-			break
-		}
-	}
-
-	// Sanity check that txn was in fetched block
-	if offset < 0 {
-		return 0
-	}
-
-	// Count into block to get created asset index
-	return txnCounter - uint64(len(payset)) + uint64(offset) + 1
-}
-
 // computeAssetIndexFromTxn returns the created asset index given a confirmed
 // transaction whose confirmation block is available in the ledger. Note that
 // 0 is an invalid asset index (they start at 1).
@@ -301,7 +282,7 @@ func computeAssetIndexFromTxn(tx node.TxnWithStatus, l *data.Ledger) (aidx uint6
 		return 0
 	}
 
-	return computeAssetIndexInPayset(tx, blk.BlockHeader.TxnCounter, payset)
+	return lib.CreatableIndexInPayset(tx, blk.BlockHeader.TxnCounter, payset)
 }
 
 func blockEncode(b bookkeeping.Block, c agreement.Certificate) (v1.Block, error) {
@@ -851,11 +832,12 @@ func PendingTransactionInformation(ctx lib.ReqContext, context echo.Context) {
 		}
 
 		responseTxs.TransactionResults = &v1.TransactionResults{
-			// This field will be omitted for transactions that did not
-			// create an asset (or for which we could not look up the block
-			// it was created in), because computeAssetIndexFromTxn will
-			// return 0 in that case.
+			// These fields will be omitted for transactions that did not
+			// create an asset or app (or for which we could not look up the
+			// block it was created in), because computeAssetIndexFromTxn and
+			// lib.ComputeAppIndexFromTxn will return 0 in that case.
 			CreatedAssetIndex: computeAssetIndexFromTxn(txn, ledger),
+			CreatedAppIndex:   lib.ComputeAppIndexFromTxn(txn, ledger),
 		}
 
 		response := TransactionResponse{
@@ -0,0 +1,760 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// DryrunRequest is the internal form of generated.DryrunRequest. It holds the
+// transactions to evaluate together with a snapshot of the ledger state the
+// programs may read.
+type DryrunRequest struct {
+	// Txns is the transaction group to evaluate
+	Txns []transactions.SignedTxn `codec:"txns"`
+
+	// Accounts and Apps make up the simulated ledger state
+	Accounts []generated.Account     `codec:"accounts"`
+	Apps     []generated.Application `codec:"apps"`
+
+	// ProtocolVersion, Round and LatestTimestamp default to the state of the
+	// node the request is evaluated on when left empty
+	ProtocolVersion string `codec:"protocol-version"`
+	Round           uint64 `codec:"round"`
+	LatestTimestamp int64  `codec:"latest-timestamp"`
+
+	// Sources are compiled and inserted into Txns or Apps before evaluation
+	Sources []generated.DryrunSource `codec:"sources"`
+}
+
+// DecodeDryrunRequest decodes a DryrunRequest encoded either as JSON or as
+// msgpack.
+func DecodeDryrunRequest(data []byte) (dr DryrunRequest, err error) {
+	err = protocol.DecodeJSON(data, &dr)
+	if err == nil {
+		return
+	}
+	jsonErr := err
+	dr = DryrunRequest{}
+	err = protocol.DecodeReflect(data, &dr)
+	if err != nil {
+		err = fmt.Errorf("could not decode dryrun request as json (%v) or msgpack (%v)", jsonErr, err)
+	}
+	return
+}
+
+// dryrunDebugReceiver records the state of every evaluation step
+type dryrunDebugReceiver struct {
+	disassembly []string
+	history     []generated.DryrunState
+}
+
+func (ddr *dryrunDebugReceiver) Register(state *logic.DebugState) error {
+	ddr.disassembly = strings.Split(state.Disassembly, "\n")
+	return nil
+}
+
+func (ddr *dryrunDebugReceiver) Update(state *logic.DebugState) error {
+	ds := generated.DryrunState{
+		Line:  uint64(state.Line),
+		Pc:    uint64(state.PC),
+		Stack: convertTealValues(state.Stack),
+		Error: strOrNil(state.Error),
+	}
+
+	// trim trailing unused scratch slots
+	last := len(state.Scratch) - 1
+	for last >= 0 && state.Scratch[last] == (basics.TealValue{}) {
+		last--
+	}
+	if last >= 0 {
+		scratch := convertTealValues(state.Scratch[:last+1])
+		ds.Scratch = &scratch
+	}
+
+	ddr.history = append(ddr.history, ds)
+	return nil
+}
+
+func (ddr *dryrunDebugReceiver) Complete(state *logic.DebugState) error {
+	return ddr.Update(state)
+}
+
+// convertTealValues converts debugger values, whose bytes are already base64
+// encoded, into their REST API representation
+func convertTealValues(values []basics.TealValue) []generated.TealValue {
+	converted := make([]generated.TealValue, len(values))
+	for i, tv := range values {
+		converted[i] = generated.TealValue{
+			Type:  uint64(tv.Type),
+			Bytes: tv.Bytes,
+			Uint:  tv.Uint,
+		}
+	}
+	return converted
+}
+
+// dryrunLedger implements logic.LedgerForLogic on top of the account and
+// application snapshot supplied in a DryrunRequest
+type dryrunLedger struct {
+	round           basics.Round
+	latestTimestamp int64
+
+	accounts    map[basics.Address]basics.AccountData
+	appCreators map[basics.AppIndex]basics.Address
+
+	// maxIndex is the largest app or asset index in the snapshot, used to
+	// assign an index to applications created during the dryrun
+	maxIndex uint64
+
+	// appIdx is the application currently being evaluated
+	appIdx basics.AppIndex
+}
+
+func makeDryrunLedger(dr *DryrunRequest) (*dryrunLedger, error) {
+	l := &dryrunLedger{
+		round:           basics.Round(dr.Round),
+		latestTimestamp: dr.LatestTimestamp,
+		accounts:        make(map[basics.Address]basics.AccountData, len(dr.Accounts)),
+		appCreators:     make(map[basics.AppIndex]basics.Address),
+	}
+
+	for i := range dr.Accounts {
+		addr, ad, err := accountToAccountData(&dr.Accounts[i])
+		if err != nil {
+			return nil, err
+		}
+		l.accounts[addr] = ad
+		for appIdx := range ad.AppParams {
+			l.appCreators[appIdx] = addr
+			l.bumpMaxIndex(uint64(appIdx))
+		}
+		for assetIdx := range ad.AssetParams {
+			l.bumpMaxIndex(uint64(assetIdx))
+		}
+	}
+
+	// Apps listed separately take precedence over the ones found in the
+	// creator's account
+	for i := range dr.Apps {
+		app := &dr.Apps[i]
+		creator, err := basics.UnmarshalChecksumAddress(app.Params.Creator)
+		if err != nil {
+			return nil, fmt.Errorf("app %d: bad creator address: %v", app.Id, err)
+		}
+		params, err := applicationParamsToAppParams(&app.Params)
+		if err != nil {
+			return nil, fmt.Errorf("app %d: %v", app.Id, err)
+		}
+		l.setAppParams(creator, basics.AppIndex(app.Id), params)
+	}
+
+	return l, nil
+}
+
+func (l *dryrunLedger) bumpMaxIndex(idx uint64) {
+	if idx > l.maxIndex {
+		l.maxIndex = idx
+	}
+}
+
+func (l *dryrunLedger) setAppParams(creator basics.Address, appIdx basics.AppIndex, params basics.AppParams) {
+	ad := l.accounts[creator]
+	apps := make(map[basics.AppIndex]basics.AppParams, len(ad.AppParams)+1)
+	for k, v := range ad.AppParams {
+		apps[k] = v
+	}
+	apps[appIdx] = params
+	ad.AppParams = apps
+	l.accounts[creator] = ad
+	l.appCreators[appIdx] = creator
+	l.bumpMaxIndex(uint64(appIdx))
+}
+
+func (l *dryrunLedger) appParams(appIdx basics.AppIndex) (basics.AppParams, basics.Address, bool) {
+	creator, ok := l.appCreators[appIdx]
+	if !ok {
+		return basics.AppParams{}, basics.Address{}, false
+	}
+	params, ok := l.accounts[creator].AppParams[appIdx]
+	return params, creator, ok
+}
+
+// optIn allocates empty local state for addr, as the ledger does before
+// running the approval program of an OptIn transaction
+func (l *dryrunLedger) optIn(addr basics.Address, appIdx basics.AppIndex, schema basics.StateSchema) {
+	ad := l.accounts[addr]
+	if _, ok := ad.AppLocalStates[appIdx]; ok {
+		return
+	}
+	states := make(map[basics.AppIndex]basics.AppLocalState, len(ad.AppLocalStates)+1)
+	for k, v := range ad.AppLocalStates {
+		states[k] = v
+	}
+	states[appIdx] = basics.AppLocalState{Schema: schema}
+	ad.AppLocalStates = states
+	l.accounts[addr] = ad
+}
+
+// applyDelta updates the snapshot with the state changes of a successful
+// evaluation so that later transactions in the group observe them
+func (l *dryrunLedger) applyDelta(txn *transactions.Transaction, appIdx basics.AppIndex, delta basics.EvalDelta) error {
+	if len(delta.GlobalDelta) > 0 {
+		params, creator, ok := l.appParams(appIdx)
+		if !ok {
+			return fmt.Errorf("app %d does not exist", appIdx)
+		}
+		params = params.Clone()
+		if params.GlobalState == nil {
+			params.GlobalState = make(basics.TealKeyValue)
+		}
+		applyStateDelta(params.GlobalState, delta.GlobalDelta)
+		l.setAppParams(creator, appIdx, params)
+	}
+
+	for idx, sd := range delta.LocalDeltas {
+		addr, err := txn.AddressByIndex(idx, txn.Sender)
+		if err != nil {
+			return err
+		}
+		ad := l.accounts[addr]
+		states := make(map[basics.AppIndex]basics.AppLocalState, len(ad.AppLocalStates))
+		for k, v := range ad.AppLocalStates {
+			states[k] = v
+		}
+		state := states[appIdx]
+		state.KeyValue = state.KeyValue.Clone()
+		if state.KeyValue == nil {
+			state.KeyValue = make(basics.TealKeyValue)
+		}
+		applyStateDelta(state.KeyValue, sd)
+		states[appIdx] = state
+		ad.AppLocalStates = states
+		l.accounts[addr] = ad
+	}
+	return nil
+}
+
+func applyStateDelta(kv basics.TealKeyValue, sd basics.StateDelta) {
+	for key, vd := range sd {
+		if tv, ok := vd.ToTealValue(); ok {
+			kv[key] = tv
+		} else {
+			delete(kv, key)
+		}
+	}
+}
+
+func (l *dryrunLedger) Balance(addr basics.Address) (basics.MicroAlgos, error) {
+	ad, ok := l.accounts[addr]
+	if !ok {
+		return basics.MicroAlgos{}, fmt.Errorf("account %s not in dryrun state", addr.String())
+	}
+	return ad.MicroAlgos, nil
+}
+
+func (l *dryrunLedger) Round() basics.Round {
+	return l.round
+}
+
+func (l *dryrunLedger) LatestTimestamp() int64 {
+	return l.latestTimestamp
+}
+
+func (l *dryrunLedger) AppGlobalState(appIdx basics.AppIndex) (basics.TealKeyValue, error) {
+	if appIdx == 0 {
+		appIdx = l.appIdx
+	}
+	params, _, ok := l.appParams(appIdx)
+	if !ok {
+		return nil, fmt.Errorf("app %d not in dryrun state", appIdx)
+	}
+	return params.GlobalState, nil
+}
+
+func (l *dryrunLedger) AppLocalState(addr basics.Address, appIdx basics.AppIndex) (basics.TealKeyValue, error) {
+	if appIdx == 0 {
+		appIdx = l.appIdx
+	}
+	ad, ok := l.accounts[addr]
+	if !ok {
+		return nil, fmt.Errorf("account %s not in dryrun state", addr.String())
+	}
+	state, ok := ad.AppLocalStates[appIdx]
+	if !ok {
+		return nil, fmt.Errorf("account %s is not opted in to app %d", addr.String(), appIdx)
+	}
+	return state.KeyValue, nil
+}
+
+func (l *dryrunLedger) AssetHolding(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetHolding, error) {
+	ad, ok := l.accounts[addr]
+	if !ok {
+		return basics.AssetHolding{}, fmt.Errorf("account %s not in dryrun state", addr.String())
+	}
+	holding, ok := ad.Assets[assetIdx]
+	if !ok {
+		return basics.AssetHolding{}, fmt.Errorf("account %s has not opted in to asset %d", addr.String(), assetIdx)
+	}
+	return holding, nil
+}
+
+func (l *dryrunLedger) AssetParams(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetParams, error) {
+	ad, ok := l.accounts[addr]
+	if !ok {
+		return basics.AssetParams{}, fmt.Errorf("account %s not in dryrun state", addr.String())
+	}
+	params, ok := ad.AssetParams[assetIdx]
+	if !ok {
+		return basics.AssetParams{}, fmt.Errorf("account %s has not created asset %d", addr.String(), assetIdx)
+	}
+	return params, nil
+}
+
+func (l *dryrunLedger) AppParams(appIdx basics.AppIndex) (basics.AppParams, basics.Address, error) {
+	if appIdx == 0 {
+		appIdx = l.appIdx
+	}
+	params, creator, ok := l.appParams(appIdx)
+	if !ok {
+		return basics.AppParams{}, basics.Address{}, fmt.Errorf("app %d not in dryrun state", appIdx)
+	}
+	return params, creator, nil
+}
+
+func (l *dryrunLedger) MinBalance(addr basics.Address, proto *config.ConsensusParams) (basics.MicroAlgos, error) {
+	ad, ok := l.accounts[addr]
+	if !ok {
+		return basics.MicroAlgos{}, fmt.Errorf("account %s not in dryrun state", addr.String())
+	}
+	return ad.MinBalance(proto), nil
+}
+
+func (l *dryrunLedger) Authorizer(addr basics.Address) (basics.Address, error) {
+	ad, ok := l.accounts[addr]
+	if !ok {
+		return basics.Address{}, fmt.Errorf("account %s not in dryrun state", addr.String())
+	}
+	return ad.AuthAddr, nil
+}
+
+func (l *dryrunLedger) ApplicationID() basics.AppIndex {
+	return l.appIdx
+}
+
+// accountToAccountData converts the REST API representation of an account
+// back into the fields of basics.AccountData that TEAL programs may read
+func accountToAccountData(a *generated.Account) (addr basics.Address, ad basics.AccountData, err error) {
+	addr, err = basics.UnmarshalChecksumAddress(a.Address)
+	if err != nil {
+		return
+	}
+
+	ad.MicroAlgos = basics.MicroAlgos{Raw: a.Amount}
+
+	if a.Assets != nil && len(*a.Assets) > 0 {
+		ad.Assets = make(map[basics.AssetIndex]basics.AssetHolding, len(*a.Assets))
+		for _, holding := range *a.Assets {
+			ad.Assets[basics.AssetIndex(holding.AssetId)] = basics.AssetHolding{
+				Amount: holding.Amount,
+				Frozen: holding.IsFrozen,
+			}
+		}
+	}
+
+	if a.CreatedAssets != nil && len(*a.CreatedAssets) > 0 {
+		ad.AssetParams = make(map[basics.AssetIndex]basics.AssetParams, len(*a.CreatedAssets))
+		for _, asset := range *a.CreatedAssets {
+			var params basics.AssetParams
+			params, err = assetParamsToAssetParams(&asset.Params)
+			if err != nil {
+				err = fmt.Errorf("asset %d: %v", asset.Index, err)
+				return
+			}
+			ad.AssetParams[basics.AssetIndex(asset.Index)] = params
+		}
+	}
+
+	if a.AppsLocalState != nil && len(*a.AppsLocalState) > 0 {
+		ad.AppLocalStates = make(map[basics.AppIndex]basics.AppLocalState, len(*a.AppsLocalState))
+		for _, ls := range *a.AppsLocalState {
+			var kv basics.TealKeyValue
+			kv, err = convertGeneratedTKV(ls.KeyValue)
+			if err != nil {
+				err = fmt.Errorf("app %d local state: %v", ls.Id, err)
+				return
+			}
+			ad.AppLocalStates[basics.AppIndex(ls.Id)] = basics.AppLocalState{
+				Schema: basics.StateSchema{
+					NumUint:      ls.Schema.NumUint,
+					NumByteSlice: ls.Schema.NumByteSlice,
+				},
+				KeyValue: kv,
+			}
+		}
+	}
+
+	if a.CreatedApps != nil && len(*a.CreatedApps) > 0 {
+		ad.AppParams = make(map[basics.AppIndex]basics.AppParams, len(*a.CreatedApps))
+		for _, app := range *a.CreatedApps {
+			var params basics.AppParams
+			params, err = applicationParamsToAppParams(&app.Params)
+			if err != nil {
+				err = fmt.Errorf("app %d: %v", app.Id, err)
+				return
+			}
+			ad.AppParams[basics.AppIndex(app.Id)] = params
+		}
+	}
+
+	if a.AppsTotalSchema != nil {
+		ad.TotalAppSchema = basics.StateSchema{
+			NumUint:      a.AppsTotalSchema.NumUint,
+			NumByteSlice: a.AppsTotalSchema.NumByteSlice,
+		}
+	}
+
+	return
+}
+
+func assetParamsToAssetParams(p *generated.AssetParams) (params basics.AssetParams, err error) {
+	params = basics.AssetParams{
+		Total:    p.Total,
+		Decimals: uint32(p.Decimals),
+	}
+	if p.DefaultFrozen != nil {
+		params.DefaultFrozen = *p.DefaultFrozen
+	}
+	if p.UnitName != nil {
+		params.UnitName = *p.UnitName
+	}
+	if p.Name != nil {
+		params.AssetName = *p.Name
+	}
+	if p.Url != nil {
+		params.URL = *p.Url
+	}
+	if p.MetadataHash != nil {
+		copy(params.MetadataHash[:], *p.MetadataHash)
+	}
+	for _, role := range []struct {
+		src *string
+		dst *basics.Address
+	}{
+		{p.Manager, &params.Manager},
+		{p.Reserve, &params.Reserve},
+		{p.Freeze, &params.Freeze},
+		{p.Clawback, &params.Clawback},
+	} {
+		if role.src == nil {
+			continue
+		}
+		*role.dst, err = basics.UnmarshalChecksumAddress(*role.src)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func applicationParamsToAppParams(p *generated.ApplicationParams) (params basics.AppParams, err error) {
+	params.ApprovalProgram = p.ApprovalProgram
+	params.ClearStateProgram = p.ClearStateProgram
+	if p.LocalStateSchema != nil {
+		params.LocalStateSchema = basics.StateSchema{
+			NumUint:      p.LocalStateSchema.NumUint,
+			NumByteSlice: p.LocalStateSchema.NumByteSlice,
+		}
+	}
+	if p.GlobalStateSchema != nil {
+		params.GlobalStateSchema = basics.StateSchema{
+			NumUint:      p.GlobalStateSchema.NumUint,
+			NumByteSlice: p.GlobalStateSchema.NumByteSlice,
+		}
+	}
+	params.GlobalState, err = convertGeneratedTKV(p.GlobalState)
+	return
+}
+
+// convertGeneratedTKV is the inverse of convertTKVToGenerated
+func convertGeneratedTKV(tkv *generated.TealKeyValueStore) (basics.TealKeyValue, error) {
+	if tkv == nil || len(*tkv) == 0 {
+		return nil, nil
+	}
+
+	converted := make(basics.TealKeyValue, len(*tkv))
+	for _, kv := range *tkv {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, fmt.Errorf("bad key %s: %v", kv.Key, err)
+		}
+		value, err := base64.StdEncoding.DecodeString(kv.Value.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("bad value for key %s: %v", kv.Key, err)
+		}
+		converted[string(key)] = basics.TealValue{
+			Type:  basics.TealType(kv.Value.Type),
+			Bytes: string(value),
+			Uint:  kv.Value.Uint,
+		}
+	}
+	return converted, nil
+}
+
+// stateDeltaToGenerated converts a TEAL state delta into its REST API
+// representation. Keys and byte values are base64 encoded.
+func stateDeltaToGenerated(sd basics.StateDelta) *generated.StateDelta {
+	if len(sd) == 0 {
+		return nil
+	}
+
+	converted := make(generated.StateDelta, 0, len(sd))
+	for k, v := range sd {
+		value := generated.EvalDelta{Action: uint64(v.Action)}
+		switch v.Action {
+		case basics.SetBytesAction:
+			value.Bytes = strOrNil(base64.StdEncoding.EncodeToString([]byte(v.Bytes)))
+		case basics.SetUintAction:
+			uintValue := v.Uint
+			value.Uint = &uintValue
+		}
+		converted = append(converted, generated.EvalDeltaKeyValue{
+			Key:   base64.StdEncoding.EncodeToString([]byte(k)),
+			Value: value,
+		})
+	}
+	return &converted
+}
+
+// insertSources compiles the request sources and places the programs into
+// the transactions or applications they are meant for
+func (dr *DryrunRequest) insertSources() error {
+	for _, s := range dr.Sources {
+		program, err := logic.AssembleString(s.Source)
+		if err != nil {
+			return fmt.Errorf("%s source for txn %d, app %d: %v", s.FieldName, s.TxnIndex, s.AppIndex, err)
+		}
+		switch s.FieldName {
+		case "lsig":
+			if s.TxnIndex >= uint64(len(dr.Txns)) {
+				return fmt.Errorf("lsig source refers to txn %d but only %d txns were given", s.TxnIndex, len(dr.Txns))
+			}
+			dr.Txns[s.TxnIndex].Lsig.Logic = program
+		case "approv", "clearp":
+			var app *generated.Application
+			for i := range dr.Apps {
+				if dr.Apps[i].Id == s.AppIndex {
+					app = &dr.Apps[i]
+					break
+				}
+			}
+			if app == nil {
+				// the source introduces a new app: its creator is the
+				// sender of the referenced transaction, if any
+				var creator string
+				if s.TxnIndex < uint64(len(dr.Txns)) {
+					creator = dr.Txns[s.TxnIndex].Txn.Sender.String()
+				}
+				dr.Apps = append(dr.Apps, generated.Application{
+					Id:     s.AppIndex,
+					Params: generated.ApplicationParams{Creator: creator},
+				})
+				app = &dr.Apps[len(dr.Apps)-1]
+			}
+			if s.FieldName == "approv" {
+				app.Params.ApprovalProgram = program
+			} else {
+				app.Params.ClearStateProgram = program
+			}
+		default:
+			return fmt.Errorf("unknown source field name %#v", s.FieldName)
+		}
+	}
+	return nil
+}
+
+// RunDryrunRequest evaluates the logic signatures and application programs
+// of every transaction in dr against the state snapshot it carries.
+// Request-level problems are reported in the Error field of the response.
+func RunDryrunRequest(dr *DryrunRequest, proto *config.ConsensusParams) (response generated.DryrunResponse) {
+	response.Txns = make([]generated.DryrunTxnResult, 0, len(dr.Txns))
+
+	err := dr.insertSources()
+	if err != nil {
+		response.Error = err.Error()
+		return
+	}
+
+	ledger, err := makeDryrunLedger(dr)
+	if err != nil {
+		response.Error = err.Error()
+		return
+	}
+
+	for ti, stxn := range dr.Txns {
+		result := generated.DryrunTxnResult{Disassembly: []string{}}
+		if !stxn.Lsig.Blank() {
+			dryrunLogicSig(dr, ti, proto, &result)
+		}
+		if stxn.Txn.Type == protocol.ApplicationCallTx {
+			dryrunAppCall(dr, ti, proto, ledger, &result)
+		}
+		response.Txns = append(response.Txns, result)
+	}
+	return
+}
+
+func dryrunLogicSig(dr *DryrunRequest, ti int, proto *config.ConsensusParams, result *generated.DryrunTxnResult) {
+	stxn := &dr.Txns[ti]
+	ep := logic.EvalParams{
+		Txn:        stxn,
+		Proto:      proto,
+		TxnGroup:   dr.Txns,
+		GroupIndex: ti,
+	}
+
+	var messages []string
+	cost, err := logic.Check(stxn.Lsig.Logic, ep)
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("check failed: %v", err))
+	} else {
+		result.LogicSigCost = numOrNil(uint64(cost))
+		if uint64(cost) > proto.LogicSigMaxCost {
+			messages = append(messages, fmt.Sprintf("cost %d exceeds the LogicSig limit of %d", cost, proto.LogicSigMaxCost))
+		}
+	}
+
+	var debug dryrunDebugReceiver
+	ep.Debugger = &debug
+	pass, err := logic.Eval(stxn.Lsig.Logic, ep)
+
+	result.Disassembly = debug.disassembly
+	result.LogicSigDisassembly = &debug.disassembly
+	result.LogicSigTrace = &debug.history
+	if pass {
+		messages = append(messages, "PASS")
+	} else {
+		messages = append(messages, "REJECT")
+	}
+	if err != nil {
+		messages = append(messages, err.Error())
+	}
+	result.LogicSigMessages = &messages
+}
+
+func dryrunAppCall(dr *DryrunRequest, ti int, proto *config.ConsensusParams, ledger *dryrunLedger, result *generated.DryrunTxnResult) {
+	stxn := &dr.Txns[ti]
+	txn := &stxn.Txn
+
+	appIdx := txn.ApplicationID
+	if appIdx == 0 {
+		// Application creation: install the programs from the transaction
+		// under the next free index, as the ledger would
+		appIdx = basics.AppIndex(ledger.maxIndex + 1)
+		ledger.setAppParams(txn.Sender, appIdx, basics.AppParams{
+			ApprovalProgram:   txn.ApprovalProgram,
+			ClearStateProgram: txn.ClearStateProgram,
+			LocalStateSchema:  txn.LocalStateSchema,
+			GlobalStateSchema: txn.GlobalStateSchema,
+		})
+	}
+	ledger.appIdx = appIdx
+
+	var messages []string
+	params, _, ok := ledger.appParams(appIdx)
+	if !ok {
+		messages = append(messages, fmt.Sprintf("uploaded state did not include app id %d referenced in txn[%d]", appIdx, ti))
+		result.AppCallMessages = &messages
+		return
+	}
+
+	var program []byte
+	if txn.OnCompletion == transactions.ClearStateOC {
+		program = params.ClearStateProgram
+		messages = append(messages, "ClearStateProgram")
+	} else {
+		program = params.ApprovalProgram
+		messages = append(messages, "ApprovalProgram")
+	}
+
+	if txn.OnCompletion == transactions.OptInOC {
+		ledger.optIn(txn.Sender, appIdx, params.LocalStateSchema)
+	}
+
+	ep := logic.EvalParams{
+		Txn:        stxn,
+		Proto:      proto,
+		TxnGroup:   dr.Txns,
+		GroupIndex: ti,
+		Ledger:     ledger,
+	}
+
+	cost, err := logic.CheckStateful(program, ep)
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("check failed: %v", err))
+	} else {
+		result.AppCallCost = numOrNil(uint64(cost))
+		if cost > proto.MaxAppProgramCost {
+			messages = append(messages, fmt.Sprintf("cost %d exceeds the app program limit of %d", cost, proto.MaxAppProgramCost))
+		}
+	}
+
+	var debug dryrunDebugReceiver
+	ep.Debugger = &debug
+	pass, delta, err := logic.EvalStateful(program, ep)
+
+	result.Disassembly = debug.disassembly
+	result.AppCallTrace = &debug.history
+	result.GlobalDelta = stateDeltaToGenerated(delta.GlobalDelta)
+	if len(delta.LocalDeltas) > 0 {
+		localDeltas := make([]generated.AccountStateDelta, 0, len(delta.LocalDeltas))
+		for idx, sd := range delta.LocalDeltas {
+			addr, addrErr := txn.AddressByIndex(idx, txn.Sender)
+			if addrErr != nil {
+				messages = append(messages, addrErr.Error())
+				continue
+			}
+			localDeltas = append(localDeltas, generated.AccountStateDelta{
+				Address: addr.String(),
+				Delta:   *stateDeltaToGenerated(sd),
+			})
+		}
+		result.LocalDeltas = &localDeltas
+	}
+
+	if pass {
+		messages = append(messages, "PASS")
+		if applyErr := ledger.applyDelta(txn, appIdx, delta); applyErr != nil {
+			messages = append(messages, applyErr.Error())
+		}
+	} else {
+		messages = append(messages, "REJECT")
+	}
+	if err != nil {
+		messages = append(messages, err.Error())
+	}
+	result.AppCallMessages = &messages
+}
@@ -0,0 +1,226 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func dryrunProto() *config.ConsensusParams {
+	proto := config.Consensus[protocol.ConsensusFuture]
+	return &proto
+}
+
+func lastMessage(t *testing.T, messages *[]string) string {
+	require.NotNil(t, messages)
+	require.NotEmpty(t, *messages)
+	return (*messages)[len(*messages)-1]
+}
+
+func TestDryrunLogicSig(t *testing.T) {
+	dr := DryrunRequest{
+		Txns: []transactions.SignedTxn{{}, {}},
+		Sources: []generated.DryrunSource{
+			{FieldName: "lsig", Source: "int 1", TxnIndex: 0},
+			{FieldName: "lsig", Source: "int 0", TxnIndex: 1},
+		},
+	}
+	response := RunDryrunRequest(&dr, dryrunProto())
+	require.Empty(t, response.Error)
+	require.Len(t, response.Txns, 2)
+
+	pass := response.Txns[0]
+	require.Equal(t, "PASS", lastMessage(t, pass.LogicSigMessages))
+	require.NotNil(t, pass.LogicSigTrace)
+	require.NotEmpty(t, *pass.LogicSigTrace)
+	require.NotNil(t, pass.LogicSigCost)
+	require.NotEmpty(t, pass.Disassembly)
+	require.Nil(t, pass.AppCallMessages)
+
+	reject := response.Txns[1]
+	require.Equal(t, "REJECT", lastMessage(t, reject.LogicSigMessages))
+}
+
+func TestDryrunBadSource(t *testing.T) {
+	dr := DryrunRequest{
+		Txns:    []transactions.SignedTxn{{}},
+		Sources: []generated.DryrunSource{{FieldName: "lsig", Source: "not an opcode"}},
+	}
+	response := RunDryrunRequest(&dr, dryrunProto())
+	require.NotEmpty(t, response.Error)
+	require.Empty(t, response.Txns)
+
+	dr.Sources = []generated.DryrunSource{{FieldName: "bogus", Source: "int 1"}}
+	response = RunDryrunRequest(&dr, dryrunProto())
+	require.Contains(t, response.Error, "bogus")
+}
+
+func TestDryrunAppCallDeltas(t *testing.T) {
+	var creator, user basics.Address
+	creator[0] = 1
+	user[0] = 2
+
+	source := `byte "counter"
+byte "counter"
+app_global_get
+int 1
++
+app_global_put
+int 0
+byte "seen"
+byte "yes"
+app_local_put
+int 1`
+	program, err := logic.AssembleString(source)
+	require.NoError(t, err)
+
+	appIdx := uint64(7)
+	localState := []generated.ApplicationLocalState{{Id: appIdx}}
+	call := transactions.SignedTxn{Txn: transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: transactions.Header{Sender: user},
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApplicationID: basics.AppIndex(appIdx),
+		},
+	}}
+	dr := DryrunRequest{
+		Txns: []transactions.SignedTxn{call, call},
+		Accounts: []generated.Account{
+			{Address: user.String(), Amount: 1000000, AppsLocalState: &localState},
+		},
+		Apps: []generated.Application{{
+			Id: appIdx,
+			Params: generated.ApplicationParams{
+				Creator:           creator.String(),
+				ApprovalProgram:   program,
+				GlobalStateSchema: &generated.ApplicationStateSchema{NumUint: 1},
+				LocalStateSchema:  &generated.ApplicationStateSchema{NumByteSlice: 1},
+			},
+		}},
+	}
+	response := RunDryrunRequest(&dr, dryrunProto())
+	require.Empty(t, response.Error)
+	require.Len(t, response.Txns, 2)
+
+	for i, result := range response.Txns {
+		messages := *result.AppCallMessages
+		require.Equal(t, "ApprovalProgram", messages[0])
+		require.Equal(t, "PASS", lastMessage(t, result.AppCallMessages))
+		require.NotNil(t, result.AppCallTrace)
+		require.NotNil(t, result.AppCallCost)
+
+		// the second call observes the counter written by the first one
+		require.NotNil(t, result.GlobalDelta)
+		require.Len(t, *result.GlobalDelta, 1)
+		gd := (*result.GlobalDelta)[0]
+		require.Equal(t, base64.StdEncoding.EncodeToString([]byte("counter")), gd.Key)
+		require.Equal(t, uint64(basics.SetUintAction), gd.Value.Action)
+		require.Equal(t, uint64(i+1), *gd.Value.Uint)
+
+		// writing the value already stored by the first call is not a change
+		if i > 0 {
+			require.Nil(t, result.LocalDeltas)
+			continue
+		}
+		require.NotNil(t, result.LocalDeltas)
+		require.Len(t, *result.LocalDeltas, 1)
+		ld := (*result.LocalDeltas)[0]
+		require.Equal(t, user.String(), ld.Address)
+		require.Len(t, ld.Delta, 1)
+		require.Equal(t, base64.StdEncoding.EncodeToString([]byte("yes")), *ld.Delta[0].Value.Bytes)
+	}
+}
+
+func TestDryrunAppCallOptInAndCreate(t *testing.T) {
+	var user basics.Address
+	user[0] = 3
+
+	program, err := logic.AssembleString(`int 0
+byte "k"
+int 5
+app_local_put
+int 1`)
+	require.NoError(t, err)
+	clear, err := logic.AssembleString("int 1")
+	require.NoError(t, err)
+
+	create := transactions.SignedTxn{Txn: transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: transactions.Header{Sender: user},
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			OnCompletion:      transactions.OptInOC,
+			ApprovalProgram:   program,
+			ClearStateProgram: clear,
+			LocalStateSchema:  basics.StateSchema{NumUint: 1},
+		},
+	}}
+	dr := DryrunRequest{
+		Txns:     []transactions.SignedTxn{create},
+		Accounts: []generated.Account{{Address: user.String(), Amount: 1000000}},
+	}
+	response := RunDryrunRequest(&dr, dryrunProto())
+	require.Empty(t, response.Error)
+	require.Len(t, response.Txns, 1)
+	result := response.Txns[0]
+	require.Equal(t, "PASS", lastMessage(t, result.AppCallMessages))
+	require.NotNil(t, result.LocalDeltas)
+	require.Equal(t, user.String(), (*result.LocalDeltas)[0].Address)
+
+	// calling an app missing from the snapshot is reported, not fatal
+	create.Txn.ApplicationID = 42
+	dr = DryrunRequest{Txns: []transactions.SignedTxn{create}}
+	response = RunDryrunRequest(&dr, dryrunProto())
+	require.Empty(t, response.Error)
+	require.Contains(t, lastMessage(t, response.Txns[0].AppCallMessages), "did not include app id 42")
+}
+
+func TestDryrunRequestDecode(t *testing.T) {
+	var sender basics.Address
+	sender[0] = 4
+	dr := DryrunRequest{
+		Txns: []transactions.SignedTxn{{Txn: transactions.Transaction{
+			Type:   protocol.ApplicationCallTx,
+			Header: transactions.Header{Sender: sender},
+		}}},
+		Accounts:        []generated.Account{{Address: sender.String(), Amount: 17}},
+		ProtocolVersion: string(protocol.ConsensusFuture),
+		Round:           5,
+		LatestTimestamp: 1234,
+	}
+
+	for _, encoded := range [][]byte{protocol.EncodeJSON(&dr), protocol.EncodeReflect(&dr)} {
+		decoded, err := DecodeDryrunRequest(encoded)
+		require.NoError(t, err)
+		require.Equal(t, dr.Txns, decoded.Txns)
+		require.Equal(t, dr.Accounts[0].Amount, decoded.Accounts[0].Amount)
+		require.Equal(t, dr.Round, decoded.Round)
+		require.Equal(t, dr.LatestTimestamp, decoded.LatestTimestamp)
+	}
+
+	_, err := DecodeDryrunRequest([]byte("garbage"))
+	require.Error(t, err)
+}
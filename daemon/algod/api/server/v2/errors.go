@@ -36,4 +36,13 @@ var (
 	errFailedToAbortCatchup                    = "failed to abort catchup : %v"
 	errFailedToStartCatchup                    = "failed to start catchup : %v"
 	errOperationNotAvailableDuringCatchup      = "operation not available during catchup"
+	errRoundNotCommitted                       = "the round was not committed while waiting for it"
+	errRoundDeltaNotAvailable                  = "the changes of the round are no longer held by the node"
+	errRoundNotYetCommitted                    = "the round has not been committed yet"
+	errRoundHistoryNotAvailable                = "the account state of the round is not held by the node"
+	errAppDoesNotExist                         = "application does not exist"
+	errAssetDoesNotExist                       = "asset does not exist"
+	errAccountAppDoesNotExist                  = "account application info not found"
+	errFailedParsingLimit                      = "failed to parse the limit, it must be between 1 and %d"
+	errFailedParsingNextToken                  = "failed to parse the next token"
 )
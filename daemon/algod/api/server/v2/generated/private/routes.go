@@ -29,6 +29,9 @@ type ServerInterface interface {
 
 	// (POST /v2/shutdown)
 	ShutdownNode(ctx echo.Context, params ShutdownNodeParams) error
+	// Gets the scores of the gossip peers.
+	// (GET /v2/status/peers)
+	GetPeerScores(ctx echo.Context) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -208,6 +211,29 @@ func (w *ServerInterfaceWrapper) ShutdownNode(ctx echo.Context) error {
 	return err
 }
 
+// GetPeerScores converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPeerScores(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetPeerScores(ctx)
+	return err
+}
+
 // RegisterHandlers adds each server route to the EchoRouter.
 func RegisterHandlers(router interface {
 	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
@@ -229,111 +255,157 @@ func RegisterHandlers(router interface {
 	router.POST("/v2/catchup/:catchpoint", wrapper.StartCatchup, m...)
 	router.POST("/v2/register-participation-keys/:address", wrapper.RegisterParticipationKeys, m...)
 	router.POST("/v2/shutdown", wrapper.ShutdownNode, m...)
+	router.GET("/v2/status/peers", wrapper.GetPeerScores, m...)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+w8a3MbN5J/Bcfdqtg+DinZcnatKteeYueh28RxWcre3Vq+DTjTJBHNABMAI4rx6b9f",
-	"dQOYJ4akYq/3UrefbHGA7ka/0N1o4P0kVUWpJEhrJqfvJyXXvAALmv7iaaoqaROR4V8ZmFSL0golJ6fh",
-	"GzNWC7maTCcCfy25XU+mE8kLaMbg/OlEw8+V0JBNTq2uYDox6RoKjoDttsTRNaTbZKUSD+LMgTh/Obnb",
-	"8YFnmQZjhlR+L/MtEzLNqwyY1VwanuInwzbCrpldC8P8ZCYkUxKYWjK77gxmSwF5ZmZhkT9XoLetVXrk",
-	"40u6a0hMtMphSOcLVSyEhEAV1ETVAmFWsQyWNGjNLUMMSGsYaBUzwHW6Zkul95DqiGjTC7IqJqdvJwZk",
-	"BpqklYK4of8uNcAvkFiuV2An76axxS0t6MSKIrK0c899DabKrWE0lta4EjcgGc6ase8qY9kCGJfszVcv",
-	"2JMnT57hQgpuLWReyUZX1WBvr8lNn5xOMm4hfB7qGs9XSnOZJfX4N1+9IPwXfoGHjuLGQNxYzvALO385",
-	"toAwMaJCQlpYkRw62o8zIkbR/LyApdJwoEzc4I8qlDb+f6hUUm7TdamEtBG5MPrK3OeoD2tN3+XDagI6",
-	"40vklEagb4+SZ+/eH0+Pj+5+9/Ys+av/8+mTuwOX/6KGu4cD0YFppTXIdJusNHCyljWXQ3688fpg1qrK",
-	"M7bmNyR8XpCr93MZznWu84bnFeqJSLU6y1fKMO7VKIMlr3LLAmJWyRzdFELz2s6EYaVWNyKDbIred7MW",
-	"6Zql3DgQNI5tRJ6jDlYGsjFdi69uhzHdtVmCdP0qftCC/u8yo1nXHk7ALXmDJM2VgcSqPdtT2HG4zFh7",
-	"Q2n2KnO/zYpdroERcvzgNlvinUSdzvMtsyTXjHHDOAtb05SJJduqim1IOLm4pvl+Nci1giHTSDidfRSN",
-	"d4x9A2ZEmLdQKgcuiXnB7oYsk0uxqjQYtlmDXfs9T4MplTTA1OInSC2K/d8vvn/FlGbfgTF8Ba95es1A",
-	"piobl7FHGtvBfzIKBV6YVcnT6/h2nYtCREj+jt+KoiqYrIoFaJRX2B+sYhpspeUYQQ7iHj0r+O0Q6aWu",
-	"ZErCbdB2AjVUJWHKnG9n7HzJCn77/GjqyTGM5zkrQWZCrpi9laNBGuLeT16iVSWzA2IYiwJr7ZqmhFQs",
-	"BWSshrKDEo9mHz1C3o+eJrJqkROAjJJTY9lDjoTbiM6g6eIXVvIVtFRmxn7wnou+WnUNsnZwbLGlT6WG",
-	"G6EqU08aoZFQ7w6vpbKQlBqWIqJjF54d6D3cGO9eCx/gpEpaLiRk6HmJaGXBeaJRmloIdyczwy16wQ18",
-	"fjK2gTdfD5T+UvWlvlPiB0mbBiXOJCP7In71BhsPmzrzD0j+2riNWCXu54EgxeoSt5KlyGmb+QnlF9hQ",
-	"GXICHUaEjceIleS20nB6JR/hXyxhF5bLjOsMfyncT99VuRUXYoU/5e6nb9VKpBdiNcLMmtZoNkXTCvcP",
-	"wou7Y3sbTRq+Veq6KtsLSjtZ6WLLzl+OCdnBvK9intWpbDuruLwNmcZ9Z9jbWpAjRI7yruQ48Bq2GpBa",
-	"ni7pn9sl6RNf6l9izETN9TssVQN8leCN/w1/QlsHlwzwssxFypGbc9o3T9+3KPm9huXkdPK7eVMimbuv",
-	"Zu7hOoxdsT2AorTbh7j8L3KVXv8q3KVWJWgr3CoWCGeoIASerYFnoFnGLZ81uYQLL0bETBO/oXmUHICO",
-	"ePbv6T88Z/gZlY/bELVgxCYMxi6qVV/JMNBx7tNhwgEUgClWuNiGYUxyLypfNMidX6odyVvPlnd9aBGZ",
-	"fOnCKUYzwiJw6U2ydLZQ+tfpSS+llKxJARlHqHXQhyvvSpaGVmXi+RMJI92AHqCm6jb0Jm0O9cEfwquW",
-	"/jbcubD878Adg1A/Bne6gD4Rd16pDC4st5X5CIxpgIVgxJAlCensAR0+X6jKMs6kynCNODjOspFqB6VZ",
-	"lB3athTs2pnqAnD/THm1WluGG48acrBdTkl46niZkFmZkeCwjurdKIfOZdK5Bp5t2QJAMrXwEZiPDWmR",
-	"nBI3G2qyXmANWXXU0KGr1CoFYyBLfAF6L2mhmL3UqnCYRthEdBO9NRJmFFty/StptcryfA+dNGZIrWkc",
-	"r49ah1Qfhn6X/PrI21LEHD0YFHp53ChzsDDGwr08qcqRgqU39EtRoEkwyaUykCqZmSiwnBub7DMFHNTx",
-	"RijWlvbFtJ8Aj4Tl33JjXWAsZEY7ljNhwkNzCMU4wTegjVAyDvkv7mMMdoq+R5rKMA+BmaoslbaQxdaA",
-	"2dQ4rldwW+NSyxbsUiurUpWjoCsD+yCPcakF3zPLrcQxiFufmdWZ43BxVARD37qNsrJDRMOIXYRchFEt",
-	"7raLNiOEYHhTzyTFEaanOXWlaDoxVpUl+iSbVLKeN8amCzf6zP7QjB0qF7eNr8wUIHYbaPKUbxxnXblu",
-	"zQ3zdLCCX6O/L7Va+Qh+SDMaY2KETCHZpflolhc4qm0Ce4x0ZC/2BwItbD3j6OlvVOlGlWCPFMYWfM/A",
-	"4LWrR102udpHCBBeguUiN3UQUBe9GixUH+ufXW64oYqptPkWdXgpdOFKzLR3mPCbCzEyj8UVUxuzlBnT",
-	"sOE6CyOGwZqvZMsMbuP+1pWwaQATcUKXNTZhWRqKvr5KPovvG1SndcSZWAWfPqA+FiLVirvCPDLe7Vm2",
-	"rj1rKDhSRyViv8eO4xRylbhzgMhu5b6Hc4JQn2mLKg43iGfU0GqJbNZApUf0nj0mtoW8ZKUGA2MLKZXK",
-	"E9Ba6ViVaeBn+piuRXoNGUOFpKjHu7/PujQhEvYAhWrqOtxmvQ0BVVmChOzhjLEzyciIfPze2+p6yOVn",
-	"dhf+W8KaVXQkwCWjRc6uZGzbCgcKH6hFAcxu3XEn7B+IygHZjcjeyhEF4huqhyG4qEbuzMovaGbLtw1c",
-	"eUupHBWHuM+v6diZd6QsMop2G/dlqkUh6Oy5NWyKviIcBwzTJWFnjF2StWC4auAGNM/pYM2EgoUwrBCY",
-	"9ZgqTQGy0yuZdChJVeERP2j+6wzxqjo6egLs6GF/jrEYp/jI3NlAf+5zdjR1n4hd7Dm7mlxNBpA0FOoG",
-	"MpedtPXazdoL9l9quFfy+4ErYgXfurwm2CIz1XIpUuGYniv0ZCvVCzekoi+gkTzA7MAwYafkvImjFKY5",
-	"uTQGGN8eP0YCHYGKARpuHlrzbSgCd3XHMLjlKa6Sk5PZsg0qSq1nw13OqjJpA4iWOHZg9MUnd9RhoTCt",
-	"0ux97a42K8JHf1M6t5u+y15C12FHS11n+4O2ATOiFBxi/mesVCh14Y97w5lgLowdEOkzS6o81goZ2XRm",
-	"7L9UxVJO9ltWFuqgXmmKlCmDQgy0iwacPjZpOAQ5FODybfry6FF/4Y8eeZkLw5awCT0SOLDPjkePnBEo",
-	"Y1+oohQ5fIQC8Zqb9VDSC27gyWN28c3Z0+PHf3v89HNcDMX7vGCLLW6sD3z9nhm7zeFhfHc0VW7j0D8/",
-	"CSfVXbh7S29EcA37EA25BPTajmPM9WUEPn6wJ+mZ+O15JPSidWJUEukPxNXM9q6Z4B601Bbo85cBITkl",
-	"Y2irvptOMGfNtx/BcTpATIOPFE2nemPcV7Vs97V4OzBbY6EYliDd1L+NxLBvQqo1iFiUzIWEpFASttFW",
-	"TiHhO/oYjXfI1EYmk9Mbm9tPRTv098jq4jlEmh/KX5J2SyVe1102H0H4fbi96nO7o4eidchLxlmaC6rs",
-	"KWmsrlJ7JTlVGnrhZE8tQv1kvPb0IgyJF7sitSgP6kpygzys6w+zmCdbQqSy+BVAKEGZarUC0wsv2RLg",
-	"SvpRQrJKCku4KDpPnMBK0OT4Zm4kRlRLnlOp7BfQii0q293CqPHARYiuFI5omFpeSW5ZDtxY9p2Ql7cE",
-	"LuSPQWck2I3S1zUX4vH/CiQYYZL43vC1+/oNN+uwfBwYnI2f7Kq9CL/pTtha6HQ2/veDP52+PUv+ypNf",
-	"jpJn/zp/9/7k7uGjwY+P754//5/uT0/unj/80+9jkgq0x47FPeXnL314d/6S9vCmCj6g/ZNVcQshk6iS",
-	"YdpVCEndVT3dYg8wEgkK9LCpp3upX0l7K1GRbnguMm5/nTr0XdzAFp119LSmI4heUS6s9V0sbVyppOTp",
-	"NZ3ZTVbCrqvFLFXFPIS185WqQ9x5xqFQkr5lc16KuSkhnd8c79kaP8BfsYi7osYTd7rfahyIhPf+qKiT",
-	"aSJE1zjtOm8w03oJSyEFfj+9khm3fL7gRqRmXhnQX/CcyxRmK8VOmQf5kltOBYpeXW3sbgO1hXpqymqR",
-	"i5Rdt/e3Rt/H6lRXV2+R61dX7wbHPMPdyKOKKr5DkGyEXavKJr42OV7kaApBBNmVyXZhnTIP24nZ1z49",
-	"/Lj/o5qhiS8aP+Gq3RhUk6aAH4oqKMNXyh9mab4JHZuVAcN+LHj5Vkj7jiW+AECt99+oHAn70dsoOtZt",
-	"CZ1cb2dXSQtGLL3jlV0nqA/RVRlkC8mydX+Er9A4wqkK5qPION/PvACWriG9hoxKx1R8m3amh8NM72qC",
-	"ugnjWpBdHwj1yVGetQBWlRn3zpjLbb9hyYC1oUvrDVzD9lI1bXb36VC6m058fTjZJeiSa+RIyy+oZZB6",
-	"qC+PCf60lnxY9i7Rf5DMY8IuubYiFSW3PlY6oB/pdWcOAtlniVHbwzSxa2LOHFtMipqcG5xgZhgVB+AX",
-	"lAcqT//APGByuTp3Bxp0f8sHtIscWpV541Waa3L7YdnuQsoYaXEtAS0bFxjI6HKk7WvX/kRF3DTnKHSS",
-	"dohX2lvYRy0KR6CiW9AUiDeHGz5aWx5tnDxvnWu2+vHrtshg0X1jmNYtsu5qXGifDD2ToVFyMr1X0+N0",
-	"4ttXYuJQMkdxZJDDivtSKjXGeEXxpH1mWgJCOr5fLjFRY0nsiJQbo1LhzpMaJ+ZxAO7YjxhzKSY7GEJM",
-	"jVtkUw2KALNXqm2bcnUfIiUIKlrxAJuqV62/YX/tobmj6GOBvXv20Hc0RjRteoidGId58HQSdUlj4VRn",
-	"FHNDFjAI6mIqiq5pmBkO808DOdA+lHQ8a3IdqxfgdgqkhhdhWivGYg/EEne3h61SpIYVZiFN5I7WGlLR",
-	"T5s93SgLyVJoYxNKGqLLw0FfGYqCvsKhcffTYRVzl5xEFvc+hPYatkkm8ioubY/3zy8R7as62DTV4hq2",
-	"tMkAT9dsQZfycBfqoMcxO1C7NoGdC/7WLfhb/tHWe5gu4VBErBXmeh0cvxGt6vmTXcYUUcCYcgylNsrS",
-	"qHuhuGnHVY+F8lepKyl+roCJDKTFT9qfUXU8C3I3NBoMXMdIU4MH7PsaavDxk3bKVg8KBl1iO2C5I6KG",
-	"NMqTkD9EOkiCVw0LrRMf/KEV/d4jdW1jHGSuO9JOtIYm23RFtXU3D2jffB4mApWQ1t2S2X/tOuzNa0fo",
-	"CI7oNWpKEmLtEeHghDbvkEq4fYmaVeoW+XY6Fbo2BqrXTAx5FLXCuONUnhsVAVPJDZfuViTOczz0sw24",
-	"jRFnbZSmRkcD0WKYMMlSq18g7q6XKKjIsZlnJR140exZpIGsH4TUoUdz3z3wt03HqGq/ro0oImdfD+qW",
-	"FkYsnLS8lR9SH0CI4rh0au1ucHaqRHHjaFd25w5+Yxye5kE1POebBY/d6ri6epsiTWdNCt6JN61iYXKQ",
-	"gqnbX7zutbL5eqxw3YEl6OZse9jdPabuly31+82rfAapKHgeTz8y4n63PzwTK+GuwVYGWvcsPSD3foDT",
-	"In9X1RU5GtacL9nRtHWT20sjEzfCiEUONOLYjcAsmdZWZzxhCi4PpF0bGv74gOHrSmYaMrs2jrFGMcyr",
-	"L+sL63WCtwC7AZDsiMYdP2MPKLU14gYeIhcLdzt4cnr8jKq/7o+j2Gbn77vv8isZOZb/8I4lrseU2zsY",
-	"uEl5qLNop6p7pGTche2wJjf1EFuikd7r7belgku+gnitrthDk5tL0qTIuMcXmbkb9sZqtWXCxvGD5eif",
-	"Rk6A0P05Mnx7U4EGZBUzqkB9ai5ROqQBnLuu7294BbrCR6ojlKFNrXUS+emzILeXx1ZN1Z5XvIAuW6eY",
-	"ytN5rGiuoXiHOBvpjAB9E0eiRwQc9k0/lz2QSiYF2k72sDlbbOlfDDFVqqJobfBd/Xr+btCHhloIJRll",
-	"bNVhLG/5pF/N4krH18krRPXDm2/9xlAoHbsr1XhDv0losFrATdRi+2dkdWRSbxeB87EA5UutlW6fyA+6",
-	"wlwzXn1Jjd7SUOGSJRlPXQnvxgr4LXLrHS28vhe3ey3jN9ymk7+M3gRxhx/csg0wLqWy3EIQJuOsUBnk",
-	"zPjGwBxWPN36ozZzJZHhmdBA3XWioBsJnJkNX61A0xmtpvghHPUTtOHaF5XIs31pk4fxBY2NHH3/Iw+v",
-	"h9UZR6xLLHsdgC0T75wG9C++0kJ3H9bWaP5eB7S4abhDhg77o8eU9fkRgmBEfnOLprHaiPg1l+k6yiGC",
-	"0nqhINJOv+ZSQh6d7ba8f5CGFPwnNUJzIWT8U18FHGN6bGjW3F1hQBngR/qWphMDaaWF3V6gVfkMvhR/",
-	"i9a0vq7t118/r4N7H1u6Bz+8122svXmj4Wvl+vkKDGao7G6pZfPLW16UOfjg9Plniz/Akz+eZEdPjv+w",
-	"+OPR06MUTp4+Ozriz0748bMnx/D4j09PjuB4+fmzxePs8cnjxcnjk8+fPkufnBwvTj5/9ofPwgMJjtDm",
-	"8YH/pN6d5Oz1eXKJxDaC4qX4M2xd+wFqZ+iv4ikVM6DgIp+chp/+LdgJGlDrTTf/68RvYpO1taU5nc83",
-	"m82sPWW+omsziVVVup4HPMMO2dfnDGTmMg3KZcmW0FjIdlxlVNicChj07c2XF5fs7PX5rHEHk9PJ0exo",
-	"dkztdiVIXorJ6eQJ/URavya5z9fAc4uWcTedzAvcNFPj//IufOZby/Cnm8fzcCo3f+8ztrtd37opsz9o",
-	"aCa4O6bz91TpbAHyl8Tm75tbm3dON3OwkU03XCpohtNlAbpLb9yvqI4hYBWme3O25u15hjzFWS/qG6zt",
-	"lzLf/j99V+5d77WNx0dH/3w5ga4AntyTE7uim25kGcH7Bc/YG/i5AmMd7uNPh/tcUu0e3QxzbvRuOnn6",
-	"KVd/LtEUeM5oZKv8MFSJH+S1VBsZRuKeVxUF19tg3qbjLMJ9dfKsfGXocpMWN9zC5B3dnjP2YKdDT1Tc",
-	"2+nQuxv/dDqfyun8th8k+afT+a05nQvnFA53Oj4QyiFbgZ67ywNNfBROi4dHqN24bMxz+TCdPaA6hYTN",
-	"Q3/z24GNHMcz1dzAclGp74MN9S6PdTbwbG880E7nx59ha/a5ucs1sB+bl8V/pKo8NR1OmdLsR57nrd/o",
-	"gcgQgM5Gnimvm2MOfaP87m4aI2sJEM4I6CzA3yNEd38N4TDf8aBzHWDGXjrtMfUF3/oqwhJGnyp1Hdtt",
-	"z+ZV8Pjo6CjWA96n2eVYnmI6k9moJIcbyIeiHiOid6a/62G/0UdYhq0Y7ZwxonXhHdy6O2P0ncNuf8F9",
-	"qHup5GeWbbjwF/hbjazuqZtC2PAEqLvm6uu69d4RfzYyQZC7X5X90C3ut3ef7W6HszPrymZqI8cdFzV9",
-	"8NyfmtA5Rp0qW8UCgNpTzVh43C7fhkdJGadHz1Vlu28Fhza93vXnuoN6JSQhICsnLO54kLeK7/4VlKET",
-	"vPCUvXKPxvT8XvTJREdj3O5jRv+hunR4ALJThqHds/P3HE0Bgz33AlVCnBum/RZ4PvdXP1u/dm85R36d",
-	"10030Y/9qkPs6/y9vRWOllaFjKRT18bevkMm03GOF1xT8Dmdz3OV8nytjJ1P0Ml0i0Htj+9q/r0P0g58",
-	"vHt3978BAAD//9RHW+GqYwAA",
+	"H4sIAAAAAAAC/+x9/XPcuLHgv4KbvKrYvqEkf21iXW29U+zdjW+9uy5Lybs7y5fFkD0ziEiAAUBJsz79",
+	"76+6AZAgCc6MPtZ5W5WfbA0BdKPRaDT6C59nuapqJUFaMzv+PKu55hVY0PQXz3PVSJuJAv8qwORa1FYo",
+	"OTsO35ixWsjVbD4T+GvN7Xo2n0lewew47j+fafhHIzQUs2OrG5jPTL6GiuPAdlNj63ak62ylMj/EiRvi",
+	"7ZvZzZYPvCg0GDPG8idZbpiQedkUwKzm0vAcPxl2Jeya2bUwzHdmQjIlgakls+teY7YUUBbmIEzyHw3o",
+	"TTRLD3x6SjcdiplWJYzxfK2qhZAQsIIWqXZBmFWsgCU1WnPLEALiGhpaxQxwna/ZUukdqDokYnxBNtXs",
+	"+OPMgCxA02rlIC7pv0sN8AtklusV2NmneWpySws6s6JKTO2tp74G05TWMGpLc1yJS5AMex2wHxpj2QIY",
+	"l+zDt6/Z8+fPX+FEKm4tFJ7JJmfVQY/n5LrPjmcFtxA+j3mNlyuluSyytv2Hb18T/FM/wX1bcWMgvVlO",
+	"8At7+2ZqAqFjgoWEtLCidehxP/ZIbIru5wUslYY918Q1ftBFieH/U1cl5zZf10pIm1gXRl+Z+5yUYVH3",
+	"bTKsRaDXvkZKaRz041H26tPnp/OnRze/+3iS/V//58vnN3tO/3U77g4KJBvmjdYg80220sBpt6y5HNPj",
+	"g+cHs1ZNWbA1v6TF5xWJet+XYV8nOi952SCfiFyrk3KlDOOejQpY8qa0LABmjSzBGBrNczsThtVaXYoC",
+	"ijkTkl2tRb5mOTduCGrHrkRZIg82BoopXkvPbstmuolJgnjdiR40of+6xOjmtYMScE3SIMtLZSCzasfx",
+	"FE4cLgsWHyjdWWVud1ixszUwAo4f3GFLtJPI02W5YZbWtWDcMM7C0TRnYsk2qmFXtDiluKD+fjZItYoh",
+	"0Whxeucobt4p8o2IkSDeQqkSuCTihX03JplcilWjwbCrNdi1P/M0mFpJA0wt/g65xWX/X6c//ciUZj+A",
+	"MXwF73l+wUDmqpheYw80dYL/3Shc8Mqsap5fpI/rUlQigfIP/FpUTcVkUy1A43qF88EqpsE2Wk4h5Ebc",
+	"wWcVvx4DPdONzGlxO7A9RQ1ZSZi65JsD9nbJKn799dHco2MYL0tWgyyEXDF7LSeVNIS9G71Mq0YWe+gw",
+	"FhcsOjVNDblYCihYO8oWTDyYXfgIeTt8Os0qQkfIHegIuR86Eq4TPINbF7+wmq8gYpkD9hcvueirVRcg",
+	"WwHHFhv6VGu4FKoxbacJHAn0dvVaKgtZrWEpEjx26slhGGeujRevlVdwciUtFxIKJqRDWllwkmgSpwjg",
+	"9svM+IhecANfvZjd7Pq65+ov1XDVt674XqtNjTK3JRPnIn71GzatNvX673H5i2Ebscrcz6OFFKszPEqW",
+	"oqRj5u+4foEMjSEh0CNEOHiMWEluGw3H5/IJ/sUydmq5LLgu8JfK/fRDU1pxKlb4U+l+eqdWIj8Vqwli",
+	"trgmb1PUrXL/4HhpcWyvk5eGd0pdNHU8obx3K11s2Ns3U4vsxrwtY560V9n4VnF2HW4at+1hr9uFnEBy",
+	"knY1x4YXsNGA2PJ8Sf9cL4mf+FL/kiImcq4/Ycka4K0EJ3Vdipwj2T74z/gVtz24ewHvWhzSEXr8OULq",
+	"3zQsZ8ez3x121pJD99UcRmO/UzkvTy234FDpr+cjqGq7eYx08Wg9PC5u3F3Qvww1UlhEn5mQjouoaQ8r",
+	"cye0aq1q0FaAGbSlv4WFytwK+5a7uNZ8M/NHYEZH2Xi3/sVAQZK45ishaYg5an6SVfwCBROXirRAFIVg",
+	"bDgMnXpKg3bWH3+iepX1YJaSGp1I/difbLctnI6ZXAdWCmNJJY+74jIYA78CY+KoSUTww5AV/lSq/OIN",
+	"lJY/ACMscLDxehEMVnDLD2ZDgqVFHfWY0QxKyxMa/5rLFRhW8QKChkPA3QXHG0MN8+htaAlMz9rH8VSF",
+	"q9CW0MNFgkvQm/bXdmBWqYLOuf/hrk9FB4SuqrTle4Pdaq60AiNec/QMVNiH2b5xlxmPs2vHeKnkquP4",
+	"FnFh21kdtLzwa7PBGngB+vYU+jP1QzRz0Ant+Cf6Dy8ZfsYDnNtw80OmEIYJw1Rkoy6cyECKOEjYgC6x",
+	"ilXufsjwXncrLF93wCdW866rSCvUGZxOFkrfTXYMhIJknRmNcRy1vTjjzPsrS02bOvP0SWxM12AwUOe5",
+	"2C5bh8PvQ6vosO2oc2r5r0AdY3k0qXtQpz/QF6LOG73RjXyA7Q1aK524GhI5rMpVmV2CNkIlju73vgXz",
+	"LXDPuevp4HeHLbvihiFsslo0skie0Kj83kLzcEOfXUtnchxrHwO6u/kmZufh7rMOfeKHS7BhNVpQryUr",
+	"YNGs4rOZLbWqGGcFdaTN/6MqADXexjwAZ3eDdcjgQsQo8IVqLONMqgKZFBuPeJ5EVFbrRga31ZQhBQ9m",
+	"+hSMOc4u4EwABSAnFFCCDTdLGpkOKakYnmCg2QVAbWbz0U12u+sBITtTrY23s107mb8ABJnzZrW2DG+B",
+	"KsViXceM5445ModiGmBnYvMTIXDOrF1q4MWGLQAkUwtvDllsOlpwsqLa4CD1O3/HxLNaqxyMgSILR/wu",
+	"1Hw7x2x2C5kIb8K3BcKMYkuu74irVZaXO/CkNmNsTXeCCzmB9X7gt63fEHi8ilwDCxKCWcVQyJRgYYqE",
+	"O2nS1BPeQ39inIkKtyaTXCoDuZJFehcA16UAY93Mtu1EVRZgrN+QtPxuOyrZceFakXegLKP9mARbcmOz",
+	"XTsQG/VOU+SmiOlTm44GnpjFO26sM44JWZDG5SQYwXHzQhDTCE8eUjjyX8P5NB47V9KANI1pDyvT1LXS",
+	"ForUHOgmOwnrR7huYallNHZ7IlrFGgO7Rp6iUjS+J5abiSMQt7EUxpvweHLkCMOjZZMkZQ+JjhDbEDkN",
+	"rSLqxo6bCUSE6QjtGEeYAee03qL5zFhV1ygKbdbItt8UmU5d6xP7l67tmLm47TZHoQCh24CTx/zKUdbd",
+	"L9fcMI9HME3UWq28FW+MM8qAzAiZQ7aN81EanGKreAvskA0TuqQPCoigDTbHgH+TTDfJBDtWYWrCt1Rs",
+	"3wPo01xpeAhbVg2g91clW9A7lUg37j4zc1MJx+lKGSNq5rrTZMkBd9YZpx9AGXwDlovStApf6+XroJBD",
+	"cBishdq5hhykLTe4YZdCV86nTuezCb8RFqzwUJz3uJNBsmAarrguQovxzSqaTCZkAdfpI4b3TJ4FXKPb",
+	"OoX0soUsLMuDx1vGAxwkpZ2PIdiCgjey3QU4dk2DdR5yRyWTip2gD0xIVolcK+5CInAyTkGxrddfQ8UR",
+	"O3LOe4VqGqaQq8xFYCRUE/c9RGgEz1jMM+lxA5/svihcrUEHq+2AiDG3LVmtwcDURGqlyqy9tA79eyPp",
+	"PoR0IfILKBjuDLXsDp3f93FCIOwRLqppPaBX603QnusaJBSPDxg7kd5W6M7xgYIxAC5/b7fBvyaoRUPB",
+	"GFwymuTBuUwpCyGU455cFIbZzjsutvGeoNwg2wHZaznBQPyKPJFQxDTd15Z3Sj0jITuW5x1TOSz2Ee3f",
+	"UcAf762yKOhq08lR0ywqQVF/UbM5E7YNxBjf0YU9YGj51kB3E4NGbDSFcuNUKx82VQm84pomzwGK43OZ",
+	"9TDJVeUBP+r+6zbieXN09BzY0eNhH2NRO/TXMLcHhn2/Zkdz94nIxb5m57Pz2WgkDZW6BH8Xifna9do5",
+	"7H9rxz2XP41EEav4xl1iw15kplkuRS4c0clCzldqoOR1lgcNFeBV0DBh5yS8iaKkHLt16TZg+px+CKtN",
+	"YlQmXHAbah3B/d7nHcPgmuc4S05CZsOukFFaPhsft1bVWTxA0jC6BaI3Wbsgk6BH3XHfjV2D7u6+Hb+z",
+	"we29R46IXQ92q8ojYiQx2M8TWCtcdeED7UI0VvAP9pD0ZoRyE9CdOHQO2P9RDcs57d+6sdBepZSm+wn2",
+	"JQjCRDC9btJRCEqowBlX6MuTJ8OJP3ni11wYtoSrEJ365MmYHE+euE2gjH2tqlqU8ACq+Zqb9XilF9zA",
+	"82fs9M8nL58++9uzl1/hZOiWxSu22Fgw7JGPnGDGbkp4nD4dyRScHP2rFyFGsD9uahyjGp1DxevxUC72",
+	"0JHdNWPYbsw3ffajWbcI7sNmZ4Ci35GddTZuXIx7i6OBnLh+m9DfiFio2iTSO3A2u33sNO5eU42Gfvum",
+	"pS5KNmPovL+Zz05F1ZTcPgQL3sIdHc96pVVTexXTRdD6M/hXcFdvPATy7G5+VW/1dvtcp8ZPkINOIcBQ",
+	"bieR5KSKl7keEzCIw4kWPF+PAc2DRFO68IfBGkJoSO+E2nbTD0wUcdx3OPqUD2k+o2XI/EKPUf8PL6WD",
+	"eTVFoB6rJCxGg23juHNgzYnIN8Rpnx32oaUu7SxHBW+sHKPs9luDxsIH0HbcQEyDv96Znn3duK9qGacB",
+	"+KU2G2OhGjtoXde/TXDsh0CyEQ8qWQoJWaUkbJKZb0LCD/Qx1dudjxOdSVOZ6ju02vXwH6DVh7OXpeme",
+	"9KXVjjbE+zYp4QEWfzjuwE8ZJ0DQFRvKmnGWlwKkMx5b3eT2XHISm4M74IAtgql52kz/OjRJ+wUSZns/",
+	"1LnkZMtrTbVJ//USEr6fbwGCtd40qxWYwZ2QLQHOpW8lJGuksASLrtSZW7AaNGkrB64lXoOWJHIU+wW0",
+	"YovG9vVOitN21zrnrEQwTC3PJbesBG4s+0Gg9xyHC0afwDMS7JXSFy0V0hJ9BRKMMFlaofvOff0zN+sw",
+	"fWzYGkTdZx/uNeuyQmY4zV4i2P979O/HmADGs1+Oslf//fDT5xc3j5+Mfnx28/XX/7//0/Obrx//+7+l",
+	"VirgLopJzN++8Xeyt2/oIO/8lCPcv5jDC1MPkkyGJ2glJCWjDHiLPZLKtgz0uPN4+lU/lxi5YBVmY4mC",
+	"27uxw1DEjfai2x0DruktxODEC3P9lNJoVirDoC4Kz5mthF03i4NcVYdB0zlcqVbrOSw4VErSt+KQ1+LQ",
+	"1JAfXj7doYreQ16xhLhCWF4NieKsE3dy96FvHsIRXZ6pS1RA88gbWAop8PvxuSy45YcLbkRuDhsD+k+8",
+	"5DKHg5Vix8wP+YZbfi5HcnMyFTyKMmR1syhFzi5gk+L3KePy+flHpPr5+aeRI358GnlQaYM9Acgw+FE1",
+	"NvOejWnLZGe9pZGp91aoc+bHdsvsxvcODTPhRKhrk5Uq52VmLLeQnn5dlzj9OGiYUSen+xurdJAswrRW",
+	"UlzfH5UPRUAjqON91hgw7OeK1x+FtJ9Y5i16J3XdRdL/7DewMJTMsbduPBGWP1aJaeJOS7l1iDsNeup6",
+	"BUeMSVMOPxHpqA1utc5ffFc64VB/ViUu7p3JFI2RpE5j1xnuqeSsDLIW7Yf4VrjiQprgxDdiJZH5fAot",
+	"JlutAZ0H5LyjO9+8110te+I6bFlhXNarC5ul1CwyMGE2bF34yxrjcjPMkTFg2/CtD3ABmzPVZXbdJikG",
+	"3UTOMZYhz0xtkBrpEUnWQYx961wbLL73TyKmvK7ZqlQLv6tatjhu+SL0md5ATtw/wOZJMUVLhi38XnOd",
+	"IAR1mCLBHSaK492L9VPTq7m2Ihe1m/9+KTfve31wkF1CPSnG0UzYl9YjYZqU3q5xhpbB5HIAfsH1aIxL",
+	"o46j4wIkZ6t1jmZGlVM84y5KiDyzxu9srkmDCNOWq22opbkEtOxO04BGnyLxsb32rn1x2Tn0kVR7HXA7",
+	"LULIRSHwSPQdWgLhlnDJp+g/nbL4NoomijLh24TEINiGm2HeJqe6ojQhcTFkK4YUxdn8VumG85mPmU0t",
+	"h5J0uhdQwop7Vxo2DoziUfu9iRYI8fhpucQ7P8tSgUncGJUL2u+RLPcwAJW/J4w5awXbe4QUG0dokw+C",
+	"BmY/qnhvytVtkJQgnDksjE3ei+hv2CM1q02Q9GrlTvVvLDu6TTTvsnfdMo5NKm124fuhGEtq5r1WzDVZ",
+	"wOh+kGJRJmTCyDA2ZRgogY7jrCdZswvYpLUKIDY8Dd0idZ09Eks85B9HrigNK2EsdJdA3K3BqvFlL+KX",
+	"ykK2FBpj1fD+mZweNvrWkDL4LTZNi58eqZgrLyKKtPQhsBewyQpRNunV9nC/f4Ngf2zvLaZZXMCGDhky",
+	"VC+oHI5aDsBjmy2gXXDe1gm/cxN+xx9svvvxEjZFwFopO4DxG+GqgTzZtpkSDJhijvGqTZJ0i3ihu8+b",
+	"tO8pTvOl2yQjN8DBttv6aDO1fq2tLpAOi2nJO5Wq2Es63j4LFzzn4uOiajLj3JOJPcDrWhTXg7uzG3Ui",
+	"QAxB3EZRdxr/iAq0un6wHRSI7smpWGQN4a7vljQ6M11doFGo4m7KDAMkI4EQgxImVLUbEwpZm0ov7aIV",
+	"uqC/h81fsS1NZ3Yzn93vyp+itR9xB63ft8ubpDMZZt0VsGc5uyXJeY1Z5rzMfIzAFGtqdelZk5qHkIIv",
+	"LOrS1++zb07evffoUwQocO1MVFtnRe3oLk7/84z0X3liGrhVemKPhMJZqLCG67PTxaL1b1OKY3tKiFft",
+	"qXMoyDx/OcK0Z1y8G719ZZl2Ee20ljgAnTnx1pszHuDexrnItpk96K4fbbI0k3YrvEM0xLC2lDKqXLUu",
+	"02ZHdVFTFHKAF01iF3SvLcDbZscyQjZVhlsgM6XI09YDuTC4kWRT4fDYmFHjCZ0QR2zEhAVdNiIaC5uZ",
+	"PTwwAyQjGElikmVnC+0WypcdaKT4RwNMFCAtftI+irK3WXBvhFD48amWDrv3A1OfaPj7HPU41NQhT0hs",
+	"P+djQ28i2SLc+8JEWws1/hDZ527hp4khjk6mLT4Wzx+em50Hed032MZVUccyCBnDVdDaXZI1WA/WDtEJ",
+	"GMkSq5MS+2RaWmPvW8jpTiwTurFAdgG/vDQqMUwjr7i0UPh+joa+twF3dcdeV0pTAqSBpOdXmGyp1S+Q",
+	"vlAucaESgZ2elKS1Ue89woQ640hXCzfQN8ZjkrWnFKroI+v70SZ2OHF5ZMGmSPVgZ+LSsbWr7thziaY3",
+	"R9TCHLrxu83hcR6FfpT8asHzi7RegziddL6SnkXMKhY6h1UwbYKG573I7dK2FS5rsAbdRV+Pk83vqKD8",
+	"tli+gFxUvEwbSAuifj9dvRAr4UpkNgaiGox+IFdb2HGRr2PpvFEdad4uMW2gq/LqV6MQl8KIRQnU4qlr",
+	"gXZ8mltrkw1dcHog7dpQ82d7NF83stBQ2LVxhDWKtUok3ahaE/QC7BWAZEfU7ukr9oiM70ZcwmOkotdF",
+	"ZsdPX1Gog/vjKHXY+Vq42+RKQYIlxCKm+Zi8D24MPKT8qAfJDFZXwHxahG3ZTa7rPnuJWnqpt3svVVzy",
+	"FaSdqtUOnFxfWk2y3Q3oIqlRAcZqtcEknCR8sBzl00S4E4o/h4ZPwKlwA1nFjKqQn7oCiw5oGM6FDrtz",
+	"uMUrfCRPRx0SqQb31i9rp3VneWrW5I/6kVfQJ+uccZfoXYpgBwfmBeLBROw+6Ms0ED2xwOHc9H0x1Elm",
+	"Fe6d4nEXSBfxXwow+dKSYG2QXcPgle1D76tq4SjZJGGbHmF5JJPuTOJGp+fJGwT1lw/v/MFQKZ0qIdNJ",
+	"Q39IaLBawGVyxw4DwlrNpD0uAuVTCkootEPR3anEQ/rgQmgs1TdV2hfZYSALOkEOmEvUQ7R7qVYkuX0g",
+	"eMFKKFYQrB1NXSpezBmOg9YG5qAan95MCWJU5Gflkj5bEiUsSVFRlP2866EUZTri5qFKMuKsjaWSAcby",
+	"qk5FKGKLs9CAwiAvuSiDV5tEWkydA/bGnSYmyCoHpEvvZS04z78Yi0cDW8vzNTZQB7NdJ+H+halCfK+J",
+	"6hj7/+ddvQ9iVUTZ16ZypanmjMpOXgnjarFjbkYvwCagETSEEB/Zn5lupHRMkhZ3W4LX70LxgByN21o4",
+	"kpgNaH5LqeWSnm5bp+uUeqX4cVT0a1TAGHMJr2VbBTC8sZFzqaTIKQsvqv7eouzruu9jgtsjYXF4+wq7",
+	"22/OxL5KlhprndGeipPFx+azHuHG9ofoKy6q4w73p6UC4nivWIE1XqhBMQ/pZP5aIKQBX78FmSgWkUr3",
+	"zJokHJPG8q6Iwi3ZiALKJk6/b/EbnXzCB4FcCEkJ1p5sjqGFU9yp7LTF24KwbKXA+Pn0s3HMR+xzcHYt",
+	"3yLGnw5CmWoaw1kkcdrOCj4e6iRY+r0BGtu+xraMrI/dz73gNQf0pK490Olkw6RDD7N+pgicMKpmwaoV",
+	"EbcdPx5tC7ttdWbRUYqMhvlezFio6QgeMcZEmYZvXJYYchS1YM6JnIygFzKBxjshoSuinjgg8uSRQAtD",
+	"+3Win8k1uvH3lmloeyfDe0qgGestEfcdarDARBKaY4AxvYxdWcQJwdE26OLbudy0tduRuyM94jU9GuEJ",
+	"OS5ySAqV158KChMalD1MCQ4U3FmuUurdN9eQN76Ygeku4h0+ARfSgH3CdKsBIyYid0Gzk5HbDryvwdk/",
+	"f8a7cKyNue5W8xx6ffc4CKeiqgthuDFQLcpEXMab9mOUvowMgZPGf1M5+tMz8G6iW8cLBJ8Qdby1Ztsf",
+	"aaSXIutlGBZ4G6ZoGfZ+HNEB338ZStOBvcdadKDvxo1d/wdkx2FKakSUlND5BqV5nC04KjPh5H1bK5d8",
+	"8SrUeqZrXBthPsjV5pYn6RCV591+9ZwutDunE2kiIudDl0/J3aHnLHxTcTn5ZBgZtz5G1HK2raSSK0eQ",
+	"GsF5E+m7fzQreb2f8iA6ByJ+HvXeT10bKb809laCBtf0GKHvQ/gJq7nw5utONIwp6wPVxqGD+4SwdAs8",
+	"nIQP/6JBUjPpisIlzfcGPznLTFTcbd55Lkn6kCOOxJUOwsg1sPiNXjRIeZgXXEooskZaUabB4zWjX/dx",
+	"wWUQiogJA1kYSpr31QQZFeij71CrfE0Vt4QrnUvgJqKqACYcGOtIDLu5Kz0xwzm70sJakIw7f6kz5aXV",
+	"4S00dy5wB1DyEsnVx6CA3HkLFF3a8U3G3a5ymmIAnGKFrvTErexRUao9dFap0WKbC1FnbXR7QgC8UVhA",
+	"jE4vP2ZoS5pX1ZRWRL+N63WYOTNdTaQNK2hACc46vgBf++dgdJq6R+W64g4aVlwXZX/p4sqXO4o8pKpp",
+	"qJZGcwrc7/xBZIa5dWkHvxbDCg87z7gI9z14YDT8+NAbzzae7JgPfpuGkEnzxY5SGxMvR6ZLVPAxLcf0",
+	"W3I0bWR8onCxL+boU7hpOcY7JS7uUvELYMIyHHceHqDsOl+F7DwNOOXw1uLVWpWQFqU4UKNh+mGB/1hv",
+	"RgAc9P7zlsIl9kjFYLlEVpgopp/5h75uXXFFLTs87lNbZc/S/DGqe7LSvbkofSltVehRVnjevnMNnRWj",
+	"u5k621CwH/VjJxN8Fh2+/YhUhts4uZ7YaJMFdXjIOKmql8Q/1M2DMz3OCgUE6UPeKwM6D4lh87EZ0Cmy",
+	"xlsQJegYqNm3ABEaFzaYkN5dYe5G++GRtYXYuGeUhKndMqFz/IrVJ6Mlnao+OZ/dMWtgry071tYTF8w4",
+	"0HTHNemip9q79OIBBykND6ziRxa0W6r44xDafadH8yAlrzEwnufeC9Cj7QTt9yF8dz9NXCYmr5V2sc+1",
+	"Mp2lid3pXusIEvKIx6feF7uV9qr5ebipVf/rZBF/V0gAFQBgXEpFO8r72xlnlSqgbDVpTGfMN770hzmX",
+	"OZesEBqoRKeoqJg8Z+aKr9C9vALpn7/x4N1oidVqRFnsYhs/xp+obaIUzz+zmM54Eztkb2XWGi4tTXR7",
+	"8ZgWzK9VMAbjepxC3CN/smxKW4sBh2CEfvcAwraogYXm0jkiRhSiUaIHZsdbLV9zKaFM9nZRSf8kDqn4",
+	"39UEzpWQ6U9DFnCEGZChm3N/hgFkGP9Tqj6ngbzRwm4ocyDogOJvycTI79r961++696aZGfde5Q+MKbb",
+	"7d0Tu98pV8+z4rJw5mJLdV+/ueb47IyXo1//fvEHeP7HF8XR86d/WPzx6OVRDi9evjo64q9e8Kevnj+F",
+	"Z398+eIIni6/erV4Vjx78Wzx4tmLr16+yp+/eLp48dWrP/w+vG/rEO3ejv3fVEssO3n/NjtDZLuF4rX4",
+	"HjauHBJyZ6j3xnOS3FBxUc6Ow0//M+wT3EDd8OHXmY8zmq2trc3x4eHV1dVB3OVwRbX3M6uafH0Y4IzL",
+	"7L5/2xpNXLgx7SUXqtGqmsKWFGNO3z58c3rGTt6/PejEwex4dnRwdPAUx1c1SF6L2fHsOf1EXL+mdT9c",
+	"Ay8t7oyb+eywAqtFbvxfXoQf+FJ3+NPls8MQAHD42RuzbrZ9O4wLmhx+jv7KRLG9Zy8e2ue5Rx36j7Ym",
+	"ft0GzRggWD6CPPrkngk6/EzxCtHv/sWRw8/dE0A3vqQqpBzHoVZ615xqoNPDgsb9ihskRDkK03/9qV1t",
+	"rFA7o9cSX7evMEU5pccfx+YXGoiFkRIvbvcgTb+33YrKXvtOYH48yl59+vx0/vTo5ncoEP2fL5/f7Gl0",
+	"6R48ZKetyNyz4afB883Pjo7+9YwkvSfz4paU2HpP6vm3EnD/xAsWrMME++mXg/1WUko6Cj7mBPvNfPby",
+	"S87+rcStwEtGLaOY9cSjz/JCqisZWuIp3FQV15uwvU1PWDDPBCTr+cqQuV6LS25h9okeBTF2b6FD73Xe",
+	"WujQI6T/EjpfSuj8tl9n/ZfQ+a0JnVMnFPYXOl4RclbQsYLkwskPXdnl7udQHGVcMaSvQU5JNH+hYI8o",
+	"ikLC1WMfku6GTVSf6b0BSfqzryAakiciJ2xf4n3wg/YKHX0PG7NL/KG99Gc/fCaKnynFiyLCyDf8My/L",
+	"6Deqme5bm4O0tOwqkkyLytGGTqG1BAgJZ5RY5p9NwWMAy9k4Ojoa9O3Zo0DrrojzEqBF+x8N6E2Ht6t1",
+	"G0s8z5pPj46OUg7pIc7equYwxtWzVyor4RLK8VJPITEoYTOi2BbwZ/2CxHHlofh2m+A6ekpnAV0xohRm",
+	"NGq/nM5tsHMO8Ssu/Htlsf9O+fyr8DyxS9rwSULtmZJCSqoMh0zh0uXg3vfo++29vHGzRQiadWMLdSWn",
+	"BRdVEOClT8GjpLj2Um8VCwO0kuqA/eSjwsoNBY+IAhinSA7V2M7qgp1DVbrBa09t3dSVkASAdjlBcbEF",
+	"PMrk8sExYyF46jH70b1MOpB7Kf7xOKb3fWrT35eX9ldMtq5hqG7Y+/uwfUFzBcnzyD8zYCafuXSrUgmz",
+	"AKxAX7RPo7nkg+il2A1FmfggJMq3Cw8dEf7B9f6NxHSYEJblE0d95l1/7b4DG5qBmd2Tyr+th0X/+Urg",
+	"nbSwPfhpqzbmmRblN95c/MPZtN3HKpoFXh76VJjBry5gPfqx/zxZ4tfDthZJ8uPQXpf6evjZXveMb71G",
+	"IWAIP0dGaxJDrbn64yeUJpQE6yVUZ4M9PjykqO21MvZwdjOPv5nBx08tjT8HsRZoffPp5j8HAGz4PRr8",
+	"nAAA",
 }
 
 // GetSwagger returns the Swagger specification corresponding to the generated code
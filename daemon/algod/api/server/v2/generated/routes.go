@@ -19,13 +19,28 @@ import (
 type ServerInterface interface {
 	// Get account information.
 	// (GET /v2/accounts/{address})
-	AccountInformation(ctx echo.Context, address string) error
+	AccountInformation(ctx echo.Context, address string, params AccountInformationParams) error
+	// Get account information about a given application.
+	// (GET /v2/accounts/{address}/applications/{application-id})
+	AccountApplicationInformation(ctx echo.Context, address string, applicationId uint64) error
 	// Get a list of unconfirmed transactions currently in the transaction pool by address.
 	// (GET /v2/accounts/{address}/transactions/pending)
 	GetPendingTransactionsByAddress(ctx echo.Context, address string, params GetPendingTransactionsByAddressParams) error
+	// List applications.
+	// (GET /v2/applications)
+	GetApplications(ctx echo.Context, params GetApplicationsParams) error
+	// Get application information.
+	// (GET /v2/applications/{application-id})
+	GetApplicationByID(ctx echo.Context, applicationId uint64) error
+	// Get asset information.
+	// (GET /v2/assets/{asset-id})
+	GetAssetByID(ctx echo.Context, assetId uint64) error
 	// Get the block for the given round.
 	// (GET /v2/blocks/{round})
 	GetBlock(ctx echo.Context, round uint64, params GetBlockParams) error
+	// Get the block and account changes of the given round, waiting for it to be committed.
+	// (GET /v2/deltas/{round})
+	GetBlockDelta(ctx echo.Context, round uint64, params GetBlockDeltaParams) error
 	// Get the current supply reported by the ledger.
 	// (GET /v2/ledger/supply)
 	GetSupply(ctx echo.Context) error
@@ -37,7 +52,10 @@ type ServerInterface interface {
 	WaitForBlock(ctx echo.Context, round uint64) error
 	// Compile TEAL source code to binary, produce its hash
 	// (POST /v2/teal/compile)
-	TealCompile(ctx echo.Context) error
+	TealCompile(ctx echo.Context, params TealCompileParams) error
+	// Provide debugging information for a transaction (or group).
+	// (POST /v2/teal/dryrun)
+	TealDryrun(ctx echo.Context) error
 	// Broadcasts a raw transaction to the network.
 	// (POST /v2/transactions)
 	RawTransaction(ctx echo.Context) error
@@ -50,6 +68,9 @@ type ServerInterface interface {
 	// Get a specific pending transaction.
 	// (GET /v2/transactions/pending/{txid})
 	PendingTransactionInformation(ctx echo.Context, txid string, params PendingTransactionInformationParams) error
+	// Simulates the evaluation of transaction groups.
+	// (POST /v2/transactions/simulate)
+	SimulateTransaction(ctx echo.Context, params SimulateTransactionParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -62,6 +83,7 @@ func (w *ServerInterfaceWrapper) AccountInformation(ctx echo.Context) error {
 
 	validQueryParams := map[string]bool{
 		"pretty": true,
+		"round":  true,
 	}
 
 	// Check for unknown query parameters.
@@ -82,8 +104,58 @@ func (w *ServerInterfaceWrapper) AccountInformation(ctx echo.Context) error {
 
 	ctx.Set("api_key.Scopes", []string{""})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AccountInformationParams
+	// ------------- Optional query parameter "round" -------------
+	if paramValue := ctx.QueryParam("round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "round", ctx.QueryParams(), &params.Round)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter round: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.AccountInformation(ctx, address, params)
+	return err
+}
+
+// AccountApplicationInformation converts echo context to params.
+func (w *ServerInterfaceWrapper) AccountApplicationInformation(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "address" -------------
+	var address string
+
+	err = runtime.BindStyledParameter("simple", false, "address", ctx.Param("address"), &address)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter address: %s", err))
+	}
+
+	// ------------- Path parameter "application-id" -------------
+	var applicationId uint64
+
+	err = runtime.BindStyledParameter("simple", false, "application-id", ctx.Param("application-id"), &applicationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter application-id: %s", err))
+	}
+
+	ctx.Set("api_key.Scopes", []string{""})
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.AccountInformation(ctx, address)
+	err = w.Handler.AccountApplicationInformation(ctx, address, applicationId)
 	return err
 }
 
@@ -141,6 +213,113 @@ func (w *ServerInterfaceWrapper) GetPendingTransactionsByAddress(ctx echo.Contex
 	return err
 }
 
+// GetApplications converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApplications(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+		"limit":  true,
+		"next":   true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApplicationsParams
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := ctx.QueryParam("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "next" -------------
+	if paramValue := ctx.QueryParam("next"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "next", ctx.QueryParams(), &params.Next)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter next: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetApplications(ctx, params)
+	return err
+}
+
+// GetApplicationByID converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApplicationByID(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "application-id" -------------
+	var applicationId uint64
+
+	err = runtime.BindStyledParameter("simple", false, "application-id", ctx.Param("application-id"), &applicationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter application-id: %s", err))
+	}
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetApplicationByID(ctx, applicationId)
+	return err
+}
+
+// GetAssetByID converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAssetByID(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "asset-id" -------------
+	var assetId uint64
+
+	err = runtime.BindStyledParameter("simple", false, "asset-id", ctx.Param("asset-id"), &assetId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter asset-id: %s", err))
+	}
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetAssetByID(ctx, assetId)
+	return err
+}
+
 // GetBlock converts echo context to params.
 func (w *ServerInterfaceWrapper) GetBlock(ctx echo.Context) error {
 
@@ -184,6 +363,49 @@ func (w *ServerInterfaceWrapper) GetBlock(ctx echo.Context) error {
 	return err
 }
 
+// GetBlockDelta converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBlockDelta(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+		"format": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "round" -------------
+	var round uint64
+
+	err = runtime.BindStyledParameter("simple", false, "round", ctx.Param("round"), &round)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter round: %s", err))
+	}
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBlockDeltaParams
+	// ------------- Optional query parameter "format" -------------
+	if paramValue := ctx.QueryParam("format"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBlockDelta(ctx, round, params)
+	return err
+}
+
 // GetSupply converts echo context to params.
 func (w *ServerInterfaceWrapper) GetSupply(ctx echo.Context) error {
 
@@ -263,6 +485,42 @@ func (w *ServerInterfaceWrapper) WaitForBlock(ctx echo.Context) error {
 // TealCompile converts echo context to params.
 func (w *ServerInterfaceWrapper) TealCompile(ctx echo.Context) error {
 
+	validQueryParams := map[string]bool{
+		"pretty":    true,
+		"sourcemap": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TealCompileParams
+	// ------------- Optional query parameter "sourcemap" -------------
+	if paramValue := ctx.QueryParam("sourcemap"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "sourcemap", ctx.QueryParams(), &params.Sourcemap)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sourcemap: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.TealCompile(ctx, params)
+	return err
+}
+
+// TealDryrun converts echo context to params.
+func (w *ServerInterfaceWrapper) TealDryrun(ctx echo.Context) error {
+
 	validQueryParams := map[string]bool{
 		"pretty": true,
 	}
@@ -279,7 +537,7 @@ func (w *ServerInterfaceWrapper) TealCompile(ctx echo.Context) error {
 	ctx.Set("api_key.Scopes", []string{""})
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.TealCompile(ctx)
+	err = w.Handler.TealDryrun(ctx)
 	return err
 }
 
@@ -419,6 +677,42 @@ func (w *ServerInterfaceWrapper) PendingTransactionInformation(ctx echo.Context)
 	return err
 }
 
+// SimulateTransaction converts echo context to params.
+func (w *ServerInterfaceWrapper) SimulateTransaction(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+		"format": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SimulateTransactionParams
+	// ------------- Optional query parameter "format" -------------
+	if paramValue := ctx.QueryParam("format"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SimulateTransaction(ctx, params)
+	return err
+}
+
 // RegisterHandlers adds each server route to the EchoRouter.
 func RegisterHandlers(router interface {
 	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
@@ -437,136 +731,232 @@ func RegisterHandlers(router interface {
 	}
 
 	router.GET("/v2/accounts/:address", wrapper.AccountInformation, m...)
+	router.GET("/v2/accounts/:address/applications/:application-id", wrapper.AccountApplicationInformation, m...)
 	router.GET("/v2/accounts/:address/transactions/pending", wrapper.GetPendingTransactionsByAddress, m...)
+	router.GET("/v2/applications", wrapper.GetApplications, m...)
+	router.GET("/v2/applications/:application-id", wrapper.GetApplicationByID, m...)
+	router.GET("/v2/assets/:asset-id", wrapper.GetAssetByID, m...)
 	router.GET("/v2/blocks/:round", wrapper.GetBlock, m...)
+	router.GET("/v2/deltas/:round", wrapper.GetBlockDelta, m...)
 	router.GET("/v2/ledger/supply", wrapper.GetSupply, m...)
 	router.GET("/v2/status", wrapper.GetStatus, m...)
 	router.GET("/v2/status/wait-for-block-after/:round", wrapper.WaitForBlock, m...)
 	router.POST("/v2/teal/compile", wrapper.TealCompile, m...)
+	router.POST("/v2/teal/dryrun", wrapper.TealDryrun, m...)
 	router.POST("/v2/transactions", wrapper.RawTransaction, m...)
 	router.GET("/v2/transactions/params", wrapper.TransactionParams, m...)
 	router.GET("/v2/transactions/pending", wrapper.GetPendingTransactions, m...)
 	router.GET("/v2/transactions/pending/:txid", wrapper.PendingTransactionInformation, m...)
+	router.POST("/v2/transactions/simulate", wrapper.SimulateTransaction, m...)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+x9e3cbt7H4V8GP7TmxXS4pWXIa6xyf/mQ7TnRrOz6W0vbW8m3B3SGJaBfYAFiRjK++",
-	"+z0YAPvEkpQl+ZHyL1tcLGYwL8wMBrMfBrHIcsGBazU4+jDIqaQZaJD4F41jUXAdscT8lYCKJcs1E3xw",
-	"5J8RpSXjs8FwwMyvOdXzwXDAaQbVGPP+cCDh14JJSAZHWhYwHKh4Dhk1E+tVbkaXMy2jmYjcFMd2ipPn",
-	"g6s1D2iSSFCqi+VPPF0RxuO0SIBoSbmisXmkyILpOdFzpoh7mTBOBAcipkTPG4PJlEGaqJFf5K8FyFVt",
-	"lQ54/5KuKhQjKVLo4vlMZBPGwWMFJVIlQ4gWJIEpDppTTQwEg6sfqAVRQGU8J1MhN6BqkajjC7zIBkfv",
-	"Bgp4AhK5FQO7xP9OJcBvEGkqZ6AH74ehxU01yEizLLC0E0d9CapItSI4Ftc4Y5fAiXlrRF4VSpMJEMrJ",
-	"2xfPyMHBwWOzkIxqDYkTst5VVdDra7KvD44GCdXgH3dljaYzISlPonL82xfPEP6pW+C2o6hSEFaWY/OE",
-	"nDzvW4B/MSBCjGuYIR8a0m/eCChF9fMEpkLCljyxg2+VKXX4n5UrMdXxPBeM6wBfCD4l9nHQhtVeX2fD",
-	"SgQa43NDKWkmfbcXPX7/YX+4v3f1h3fH0T/dn48OrrZc/rNy3g0UCA6MCymBx6toJoGitswp79LjrZMH",
-	"NRdFmpA5vUTm0wxNvXuXmHet6bykaWHkhMVSHKczoQh1YpTAlBapJh4wKXhqzJSZzUk7YYrkUlyyBJKh",
-	"sb6LOYvnJKbKToHjyIKlqZHBQkHSJ2vh1a1Rpqs6SQxeH0UPXNCXS4xqXRsoAUu0BlGcCgWRFhu2J7/j",
-	"UJ6Q+oZS7VXqepsVOZsDQeDmgd1skXbcyHSarohGviaEKkKJ35qGhE3JShRkgcxJ2QW+71ZjqJYRQzRk",
-	"TmMfNcrbR74OMQLEmwiRAuVIPK93XZLxKZsVEhRZzEHP3Z4nQeWCKyBi8gvE2rD9v05/ek2EJK9AKTqD",
-	"NzS+IMBjkfTz2AEN7eC/KGEYnqlZTuOL8HadsowFUH5FlywrMsKLbALS8MvvD1oQCbqQvA8hO+MGOcvo",
-	"sgv0TBY8RuZWYBuOmhElpvKUrkbkZEoyunyyN3ToKELTlOTAE8ZnRC95r5NmYG9GL5Ki4MkWPow2DKvt",
-	"miqHmE0ZJKScZQ0mDswmfBi/Hj6VZ1VDx0/Si04JZQM6HJYBmTGqa56QnM6gJjIj8rOzXPhUiwvgpYEj",
-	"kxU+yiVcMlGo8qUeHBH0eveaCw1RLmHKAjJ26shhrIcd48xr5hycWHBNGYfEWF5EWmiwlqgXpxrA9cFM",
-	"d4ueUAXfHvZt4NXTLbk/FW2ur+X4VtzGQZFVycC+aJ46hQ27TY33twj+6rAVm0X25w4j2ezMbCVTluI2",
-	"84vhnydDodAINAjhNx7FZpzqQsLROX9g/iIROdWUJ1Qm5pfM/vSqSDU7ZTPzU2p/eilmLD5lsx5ilrgG",
-	"oyl8LbP/mPnC5lgvg0HDSyEuiry+oLgRlU5W5OR5H5PtnNcVzOMylK1HFWdLH2lc9w29LBnZg2Qv7XJq",
-	"Bl7ASoLBlsZT/Gc5RXmiU/lbiJhGct0Oi9kAlyV4634zPxldBxsM0DxPWUwNNce4bx59qGHyRwnTwdHg",
-	"D+MqRTK2T9XYzWshNtl2D7Jcr+6b5T9NRXzxUbBzKXKQmtlVTMw8XQHB6ckcaAKSJFTTURVLWPeih834",
-	"4o/4HgYHIAOW/Sf8D02JeWyEj2rvtRiPjSnju4hafiUxjo41nxaSGYAOmCCZ9W2I8UmuheWzCri1S6Uh",
-	"eefI8r49W4An31t3iuAbfhFm6VWwdDwR8uPkpBVSclKFgISaWUunz6y8yVkcWuSRo0/AjbQDWhNVWbeu",
-	"NalTqD39NrSqyW9FnVNN74A6ysx6G9RpTvSJqPNaJHCqqS7ULRCmmsw7Iwo1iXGrD8bg04koNKGEi8Ss",
-	"0QwOk6wn24FhFkaHus4FPbeqOgGzf8a0mM01MRuP6FKwnk6JaGxpGaFaqR7nsPTq7SgLzkbSqQSarMgE",
-	"gBMxcR6Y8w1xkRQDN+1zso5hFVql19DAK5ciBqUgiVwCeiNqPpk9lSKzkHrIhHgjviUQogSZUvmRuGqh",
-	"aboBTxzTxVZVhtd5rV2stwO/jn9t4HUumhjdK5Sx8majTEFDHwk30qTIexKWTtHPWGZUgnDKhYJY8EQF",
-	"J0up0tEmVTCDGtbIsLUmfSHpx4l73PKXVGnrGDOe4I5lVRjh4DsIoh/hS5CKCR6e+W/2YWju2NgergpF",
-	"3AxEFXkupIYktAYTTfXDeg3LEpaY1ubOpdAiFqlhdKFg08x9VKrN74hlV2IJRLWLzMrIsbs4TIIZ27oK",
-	"krKBREWIdYic+lE16taTNj2IGPemfBMFh6mW5JSZouFAaZHnxibpqODle31kOrWjj/XP1diucFFd2cpE",
-	"gIGuPU4O84WlrE3XzakiDg+S0Qtj73MpZs6D7+JslDFSjMcQrZN8o5anZlRdBTYoac9e7A4EatBaytGS",
-	"36DQ9QrBBi70LfiajsEbm486q2K1W3AQnoOmLFWlE1AmvSoomB9rn10uqMKMKdfpysjwlMnMpphx71D+",
-	"N+tiJA6KTaZWaskTImFBZeJHdJ01l8nmCSzD9tamsHEAYWFEpyU0pknsk74uSz4K7xuYp7XIqVAGHx8Y",
-	"ecxYLAW1iXlDeLtn6TL3LCGjBjtMEbs9th8m47PIngMEdiv73J8T+PxMnVXheT17ehWt5MhiDph6NNaz",
-	"RcQ6k6ckl6CgbyG5EGkEUgoZyjJ17Ewb0gWLLyAhRiDR63Hm75smTgYIuWeYqso83GK+8g5VngOH5P6I",
-	"kGNOUImc/97a6lrA+Td6HfwlQk0KPBKgnOAiR+c8tG35A4UbSpGfZr3s2BP2G4Kyk6wHpJe8R4DoAvNh",
-	"ZrqgRK6Nyk/xzZpt65jymlBZLLYxnz/gsTNtcJkl6O1W5ksVk4zh2XNt2NDYCn8c0A2XmB4RcobaYtxV",
-	"BZcgaYoHa8onLJgiGTNRjyriGCA5OudRA5NYZA7wveq/VhHPi729AyB799vvKG38FOeZWx1ov/uE7A3t",
-	"IyQXeULOB+eDzkwSMnEJiY1O6nJt39o47f8r5z3nP3VMEcnoysY1XheJKqZTFjNL9FQYSzYTLXeDC3wC",
-	"0qAHJjpQhOkhGm+kKLppli+VAoa3x9sIoAOzGgfNbB5S0pVPAjdlRxFY0tiskqKRWZGFEZRSzrq7nBZ5",
-	"VJ8gmOJYA9Eln+xRh4ZM1VKz19W7Uq0QHv6N4dx6/M5aAV2DHDVxHW122jrECGKwjfofk1wYrjN33OvP",
-	"BFOmdAdJF1li5rEUyMCmMyL/LQoSU9TfvNBQOvVCoqeMEZSBgLuoh+l8k4pCkEIGNt7GJw8etBf+4IHj",
-	"OVNkCgtfI2EGtsnx4IFVAqH0M5HlLIVbSBDPqZp3OT2hCg4ektMfjx/tP/zXw0ffmsWgv08zMlmZjfWe",
-	"y98TpVcp3A/vjqpIdXj2bw/9SXVz3o2pN0S4nHsbCTkDY7UtxYity/B0vLElaan48iTgeuE6jVcSqA80",
-	"qxltXDPOu9VSa1OfPPcA0SgphVv11XBgYtZ0dQuG005EJDhPUTWyN8o+FdN6XYvTA7VSGrJuCtK++q8e",
-	"H/atD7U6HovgKeMQZYLDKljKyTi8wodBfwdVredlNHp977ZD0Qb+LbSacLbh5k3pi9yuicSbssrmFpjf",
-	"nreVfa5X9KC3DmlOKIlThpk9wZWWRazPOcVMQ8udbImFz5/0556e+SHhZFcgF+WmOudUGRqW+YdRyJJN",
-	"IZBZfAHgU1CqmM1AtdxLMgU4524U46TgTCMs9M4jy7AcJBq+kR1pPKopTTFV9htIQSaFbm5hWHhgPUSb",
-	"CjdgiJiec6pJClRp8orxsyVO5+NHLzMc9ELIi5IKYf9/BhwUU1F4b/jBPv2RqrlfvhnojY172WZ7zfxV",
-	"dcJKQ6Oy8X/u/eXo3XH0Txr9thc9/tP4/YfDq/sPOj8+vHry5H+bPx1cPbn/lz+GOOVxDx2LO8xPnjv3",
-	"7uQ57uFVFryD+yfL4maMR0EhM2FXxjhWV7Vki9wznogXoPtVPt1x/ZzrJTeCdElTllD9ceLQNnEdXbTa",
-	"0ZKaBiNaSTm/1vehsHEmopzGF3hmN5gxPS8mo1hkY+/WjmeidHHHCYVMcHyWjGnOxiqHeHy5v2FrvIG9",
-	"IgFzhYUn9nS/VjgQcO/dUVEj0jQz2sJpW3ljIq3nMGWcmedH5zyhmo4nVLFYjQsF8ilNKY9hNBPkiLgp",
-	"n1NNMUHRyqv13W3AslCHTV5MUhaTi/r+Vsl7X57q/Pydofr5+fvOMU93N3KggoJvAUQLpuei0JHLTfYn",
-	"OapEEM5s02TroA6Jm9uy2eU+3fxh+4c5QxVetHlkVm3HGDGpEvg+qWJ4+Fq4wyxJF75is1CgyL8zmr9j",
-	"XL8nkUsAYOn9jyI1iP3b6agxrKscGrHe2qqS2hyh8I4Weh4ZeQiuShmyIC9r90fozCiHP1Ux8aghnKtn",
-	"ngCJ5xBfQIKpY0y+DRuv+8NMZ2q8uDFlS5BtHQjWyWGcNQFS5Al1xpjyVbtgSYHWvkrrLVzA6kxUZXbX",
-	"qVC6Gg5cfjhax+icSkORml0QU891n1/uY/xRyXm/7HWsvxHPQ8zOqdQsZjnVzlfaoh7pTeMdM8kmTQzq",
-	"ngkTmypm1bFGpKDK2cGRiQyD7ADzxPDDCE/7wNxDsrE6tQcaeH/LObSTFGqZeeVEmko0+37Z9kJKH2ph",
-	"KQHJKxPo0WhSpG5r5+5EhV1W5yh4kraNVdqY2DdS5I9AWTOhyQzcFC5pb265t3DypHauWavHL8sivUa3",
-	"lWFYlsjaq3G+fNLXTPpCycHwWkWPw4ErXwmxQ/DUsCOBFGbUpVKxMMYJikPtG1VjkMHjp+nUBGokCh2R",
-	"UqVEzOx5UmXEHAwwO/YDQmyISbaeISTGNbQxB4UTk9eirpt8dh0kOTBMWlE/N2avan/D5txDdUfR+QIb",
-	"9+yu7aiUaFjVEFs2duPg4SBokvrcqcYoYodMoOPUhUTUmKZuZNiNPxWkgPtQ1LCs0UUoX2C2U0AxPPWv",
-	"1Xwsco9Nze52v5aKlDAzUUjluRtt9aHop42eLoWGaMqk0hEGDcHlmUEvFHpBL8zQsPlpkIrYS04sCVsf",
-	"BHsBqyhhaRHmtoP71+cG7OvS2VTF5AJWuMkAjedkgpfyzC7UAG/GrAFtywTWLvilXfBLemvr3U6WzFAD",
-	"WAoT6zVgfCVS1bIn65QpIIAh4ehyrZekQfOCftOaqx4T4a5SF5z9WgBhCXBtHkl3RtWwLIa6vtCgYzp6",
-	"ihrcxK6uoZw+fNKO0epWzqANbDskt0iUM/XSxMcPgQoSb1X9QsvAx/xQ836vEbrWIXYi1zVhp9GGKtq0",
-	"SbV5Mw6o33zuBgIF49rektl87drvzXOLaA+M4DVqDBJC5RH+4AQ3bx9K2H0Ji1XKEvl6OOWrNjqiV73o",
-	"4ygshbHHqTRVIjBNwReU21uR5j1LQ/e2ArsxmrcWQmKho4JgMoypaCrFbxA211PDqMCxmSMlHnjh26NA",
-	"AVnbCSldj+q+u6dvHY9e0X5TKlGAzy4f1Ewt9Gg4SnktPsQ6AO/FUW7F2t7gbGSJwspRz+yO7fyVcjic",
-	"O9nwlC4mNHSr4/z8XWxwOq5C8Ia/qQXxL3suqLL8xcleLZovxzJbHZiDrM62u9XdfeJ+VhO/r17kE4hZ",
-	"RtNw+JEg9Zv14QmbMXsNtlBQu2fpJrL9A6wUubuqNslRkeZkSvaGtZvcjhsJu2SKTVLAEft2hImScW1l",
-	"xONfMcsDrucKhz/cYvi84ImERM+VJawSxMTVZ+WF9TLAm4BeAHCyh+P2H5N7GNoqdgn3DRUzezt4cLT/",
-	"GLO/9o+90Gbn7ruvsysJGpa/O8MSlmOM7e0cZpNys46Claq2SUm/CVujTfbVbXQJRzqrt1mXMsrpDMK5",
-	"umwDTvZd5CZ6xi268MTesFdaihVhOgwfNDX2qecEyJg/i4Yrb8qMAmlBlMiMPFWXKC1QP529ru9ueHm8",
-	"/EPMI+S+TK12EvnpoyC7l4dWjdme1zSDJlmHJpTH81hWXUNxBnHUUxkB8jIMRPYw2O+b7l1yjwseZUZ3",
-	"kvvV2WJN/kKAMVMVBKu97Wrn89dPva2rZWaJeglbNAhLazbpo0lcyPA6aWFA/fz2pdsYMiFDd6Uqa+g2",
-	"CQlaMrgMamz7jKz0TMrtwlM+5KB8L6WQ9RP5TlWYLcYrL6lhLw3hL1mi8pSZ8KavYJ4Fbr0bDS/vxa1f",
-	"S/8Nt+Hgb703QezhB9VkAYRyLjTV4JlJKMlEAilRrjAwhRmNV+6oTZ1zQ/CEScDqOpbhjQRK1ILOZiDx",
-	"jFai/+CP+nG27tonBUuTTWGTm+Mpjg0cfX/Ow+tudsYiawPLVgVgTcUbpwHti6+40PWHtSWYuzqgNZuG",
-	"PWRokD94TFmeH5kpCKJf3aKptDbAfkl5PA9SCGepdSgIlNPPKeeQBt+2W95nkpCM/iJ6cM4YDz9qi4Al",
-	"TIsM1ZqbK/Qg/fyBuqXhQEFcSKZXp0arXASfs38Fc1o/lPrrrp+Xzr3zLW3DD2d1K22vejT8IGw9X2ac",
-	"GUy7ayzZ/H5JszwF55w++WbyZzj47jDZO9j/8+S7vUd7MRw+ery3Rx8f0v3HB/vw8LtHh3uwP/328eRh",
-	"8vDw4eTw4eG3jx7HB4f7k8NvH//5G98gwSJaNR/4B9buRMdvTqIzg2zFKJqzv8LKlh8Y6fT1VTTGZAZk",
-	"lKWDI//T//d6YhSo1tPN/Tpwm9hgrnWujsbjxWIxqr8ynuG1mUiLIp6PPZxuheybEwI8sZEGxrKoS0ZZ",
-	"UHdsZpTpFBMY+Ozt96dn5PjNyagyB4Ojwd5ob7SP5XY5cJqzwdHgAH9CqZ8j38dzoKk2mnE1HIwzs2nG",
-	"yv3lTPjIlZaZny4fjv2p3PiDi9iuzDyzUIrOl/qXTS26RQxDu83EtCwhbxzBKXciNCQTm4wi7nYJT/CU",
-	"0CYazH5dkuckqfWMrCyOz6e5lpfvQsXcoRKLULPL8oilv9lJrR+c7wH36LurgCvyvtXH4uHe3ifuXXF4",
-	"ixCbvlEA7iuaGpZA2VDMYrD/6TA44ZiDNupCrDm4Gg4efUoanHAjGjQlOLIWRnc16Gd+wcWC+5HGdhdZ",
-	"RuUKLXOtTKO+tV71amozgeWO/frVF2qF87VKgUZ942TlOTkkqrwgm0smzA6DDe4SMB427gdCJiCHtRJ8",
-	"dx4K9kbwq+N/YL7j1fE/7N2WYPOvGnh7z6up+z+ADlwRebqqGth8kYZg+MX2S/t6Gt7d1JjuLhrtLhp9",
-	"tReNPvE+vizzyZRwwSOOxTCXQGoxzn/8xv5o7+DTgT8FecliIGeQ5UJSydIV+ZnTS8pS4yzfzNEo9abg",
-	"5bXzDTrUuRpe+QqVk2K7zIw/YK1DPZTobOrYJWzT7v0FN2NdUwkoReZrUwSZgo7nroFZK2XS135xrQey",
-	"7gTnxjvmrn3dTdrXDRvU9cKzI/Bn6A94l7vnFmy+keF/ShPyFn4tQGkSkdeYakUF941b73grvuv1BXf2",
-	"w73Dr3ZBrwUHAkumsELYyuJdeyt3z6Rby2pgrQMSxd+Aq1+5Kl0H10lq/KFq7XZV5SlTSGYgx/ba7Tq/",
-	"wl7bHdxq6Li7av0VXLX+/NHJjTSktVoJ9f50QKz8V9riC4y7VbfNVL4bruaFTsSilvivLnL0apLvVHqL",
-	"mrRrl7prl7prl7prl7prl7prl7prl/p1t0v9+tLBga9O3FXU03Rha65M5cLZv8cLynQ0FdJuTxEWqQcS",
-	"qE3of6fMfUCGuthKC2MsgPrPCVlD4+ZxjWCrggx3IOIu6Po2oSzDuvumJ2hAvRByq3xtlQTVgpiFkYJr",
-	"5mtBsIOB9+e+vOTnzlPdeao7T3Xnqe481Z2nuvNUf1+e6icsG2gc30TeUPvqilBtBdkVV/yOiisqB7t0",
-	"r9EhN+6w0e+1hyAaaDp23XLxvFio3krss++PXxIlChkDiQ04xkmeUrxrutS+dJCs6/eLNqjbbth3lFzb",
-	"c7gbKZwBTV1vZOfFg9JPRbJq8dWgN0ZMmxytLnMwTmWgI16gr2CbBlrY74IiFt1g4upWCyT+c9s3fz6L",
-	"ShAjJ2aV9dgVnX+MufJkDKoRKuHQSFhSxECw04eVn2VkBs2AR07Jo4lIVr4Lh52nMmmtYlNv0pq24y1d",
-	"1EtX15mPOlmXkbMVNzYkc0AdKhUrUJlriCIFTWLj42vhG7jesZH5Otqaf0aDUF1COXa1Rg1q7KzD78W5",
-	"euqVTxGK/T1bymnjK9TJ0UYrJelCL3nQSo2rpk/Bs/FOM+PbPSPf9Xbf9Xbf9Xbf9Xbf9Xbf7dy/ozsn",
-	"rTZiJePxg4tt3vfsy7dwxfXLvte68YB1d4t0d4t0d4t0y1ukWxTJ77i7uyP8Fd8R/p3dAvp93Zi5S9ft",
-	"rlfzpd8+Hq31EMcf9JIlm1sZfb1fLSa39dFiclffLP7MXywOuNzd3f06HaVawhIuYTRid80GMn/apnvM",
-	"f4p7/Rw0ZakqKysD0RTGNaGv2peKW/+Qvi3IKz+u779TYqGk7ALqtU5YJLugMvEjuq6ba4Ee/rDAWdXE",
-	"2QzwTkAb0WkJjVX9uMsW5+FivFQouOHH53EOo0OU4Vfoq28y9cNkfBb1fQ7gmX3uPwPnU2Dhb9PX5/Xs",
-	"iTZ+VMk3IWeqQ8Q6k6fEXRwMA6x92H7NB5ZKo9OGdMHiC0iIEUjfFLfHVyT3XD8718l+MV/5KlVr7+6P",
-	"CDnmti01sSrUSmm2gPNv9Dr4y7qFbpq+QEEBfvpK3lCK/DTrZUeBEbEbgrKTrAekl7xHgOgiEDlt26Mg",
-	"ECi1wpaaUFkstolQvn6/o/3Oxzse7Zluz/P47L7H5zwQ/zKS5nfZzmFtgcJrockL3FZuFqGUTU5DHohF",
-	"wvfdRWex7Lj77r1xibBJvPMjqzayR+NxKmKazoXS44Hx8potZusPjTmhMzuD89NyyS6xX8r7q/8LAAD/",
-	"/19yhfQAqAAA",
+	"H4sIAAAAAAAC/+y9eXfcNrIo/lVw+95zYuc2JXnLjDUn5/4UK4t+Yzs+ljMz70V+CZqs7saIBDgEKKmT",
+	"5+/+ThUAEiTBXrQ4cUZ/2WpiKRQKhUKtv05SVZRKgjR6cvjrpOQVL8BARX/xNFW1NInI8K8MdFqJ0ggl",
+	"J4f+G9OmEnIxmU4E/lpys5xMJ5IXMDkM+08nFfyrFhVkk0NT1TCd6HQJBceBzarE1s1IV8lCJW6IIzvE",
+	"yfHkw5oPPMsq0HoI5fcyXzEh07zOgJmKS81T/KTZpTBLZpZCM9eZCcmUBKbmzCw7jdlcQJ7pPb/If9VQ",
+	"rYJVusnHl/ShBTGpVA5DOF+oYiYkeKigAarZEGYUy2BOjZbcMJwBYfUNjWIaeJUu2VxVG0C1QITwgqyL",
+	"yeGPEw0yg4p2KwVxQf+dVwC/QGJ4tQAzeT+NLW5uoEqMKCJLO3HYr0DXudGM2tIaF+ICJMNee+xVrQ2b",
+	"AeOSvf3mBXvy5MlzXEjBjYHMEdnoqtrZwzXZ7pPDScYN+M9DWuP5QlVcZknT/u03L2j+U7fAbVtxrSF+",
+	"WI7wCzs5HluA7xghISENLGgfOtSPPSKHov15BnNVwZZ7Yhvf6qaE8/+mu5Jyky5LJaSJ7Aujr8x+jvKw",
+	"oPs6HtYA0GlfIqYqHPTHg+T5+18fTR8dfPjPH4+S/+3+fPbkw5bLf9GMuwED0YZpXVUg01WyqIDTaVly",
+	"OcTHW0cPeqnqPGNLfkGbzwti9a4vw76WdV7wvEY6EWmljvKF0ow7MspgzuvcMD8xq2UOWtNojtqZ0Kys",
+	"1IXIIJsyIdnlUqRLlnJth6B27FLkOdJgrSEbo7X46tYcpg8hShCua+GDFvT7RUa7rg2YgCviBkmaKw2J",
+	"URuuJ3/jcJmx8EJp7yq922XF3i2B0eT4wV62hDuJNJ3nK2ZoXzPGNePMX01TJuZspWp2SZuTi3Pq71aD",
+	"WCsYIo02p3OP4uEdQ98AGRHkzZTKgUtCnj93Q5TJuVjUFWh2uQSzdHdeBbpUUgNTs39CanDb///T718z",
+	"VbFXoDVfwBuenjOQqcrG99hNGrvB/6kVbnihFyVPz+PXdS4KEQH5Fb8SRV0wWRczqHC//P1gFKvA1JUc",
+	"A8iOuIHOCn41nPRdVcuUNredtiOoISkJXeZ8tcdO5qzgV18eTB04mvE8ZyXITMgFM1dyVEjDuTeDl1Sq",
+	"ltkWMozBDQtuTV1CKuYCMtaMsgYSN80meITcDZ5WsgrAEXIDOEJuB46EqwjN4NHFL6zkCwhIZo/94DgX",
+	"fTXqHGTD4NhsRZ/KCi6EqnXTaQRGmnq9eC2VgaSsYC4iNHbq0KEZZ7aNY6+FE3BSJQ0XEjImpAVaGbCc",
+	"aBSmYML1j5nhFT3jGr54Ovmw6euWuz9X/V1fu+Nb7TY1SuyRjNyL+NUd2LjY1Om/xeMvnFuLRWJ/Hmyk",
+	"WLzDq2Qucrpm/on759FQa2ICHUT4i0eLheSmruDwTH6Of7GEnRouM15l+Ethf3pV50acigX+lNufXqqF",
+	"SE/FYgSZDazR1xR1K+w/OF6cHZur6KPhpVLndRkuKO28SmcrdnI8tsl2zF0J86h5yoavindX/qWxaw9z",
+	"1WzkCJCjuCs5NjyHVQUILU/n9M/VnOiJz6tfYshEynU3LGkDnJbgqCxzkXJE21v3Gb/isQf7LuBti326",
+	"Qg9/DYD6rwrmk8PJf+632pJ9+1XvB2O/VCnPTw03YEHp7ucDKEqzeoh4cWDdPix23E2zfxxsxKAIPjMh",
+	"LRVR0w5U+lpglZUqoTICdK8t/S0MFHon6Bvq4lXFVxN3BSZ0lQ1P6w8aMuLEJV8ISUNMUfKTrODnyJi4",
+	"VCQFIisEbfxlaMVTGrTV/rgb1Ymse5MY12hZ6o/dxbbHwsqY0X1gudCGRPKwK26D1nAHhImjRgHBD31S",
+	"+CpX6fkx5IbfAiHMcLDhftEcLOOG7036CIuzOuoxoRXkhkck/iWXC9Cs4Bl4CYcmtw8cpwzVzIG3oi3Q",
+	"HW0fx1sVLn1bAg83CS6gWjW/NgOzQmV0z/3FPp+ydhJ6qtKR7wy201ppBwa0ZvHpsbANsX1tHzMOZtuO",
+	"8VzJRUvxDeDCNKvaa2jhrslgCTyDancMfUf9EMwUqoh0/D39h+cMP+MFzo1/+SFRCM2EZirQUWeWZSBG",
+	"7EzYgB6xihX2fcjwXbcTlC/ayUd287q7SDvUKpyOZqq6Hu/oMQXJWjUa4zhq83DGlXd3lprWZeLwEzmY",
+	"tkFvoNZysZ639offBlfBZdti59TwO8CONjxY1A2w0x3oI2HnuFpVtbyF4w1VparI05DQYVSq8uQCKi1U",
+	"5Op+41ow1wLPnH2e9n630LJLrhnOTVqLWmbRGxqF3x0kDzv0uytpVY5D6aOHd7veyOrcvNvsQxf5/hGs",
+	"WYka1CvJMpjVi/BuZvNKFYyzjDrS4X+tMkCJt9a3QNntYC0wuBEhCHymasM4kypDIsXGA5onFpWUVS29",
+	"2WpMkYIXM33yyhyrF7AqgAyQEjLIwfiXJY1Ml5RUDG8wqNg5QKkn08FLdr3pAWe2qloTHmeztDx/Bjhl",
+	"yuvF0jB8BaoYibUdE55a4kgsiPEJWxWbWwhNZ9XaeQU8W7EZgGRq5tQhs1WLC05aVOMNpO7kb1h4UlYq",
+	"Ba0hS/wVvwk0184Sm1mDJoKb4G0mYVqxOa+uCatRhucb4KQ2Q2h1e4MLOQL1dtOv27/+5OEu8gqY5xDM",
+	"KIZMJgcDYyjciJO6HLEeuhvjnSjwaDLJpdKQKpnFTwHwKhegjV3ZupOo8gy0cQeStt8eRyVbKlwqsg7k",
+	"eXAeo9PmXJtk0wnERp3bFKkpIPrYoaOBR1bxkmtjlWNCZiRxWQ5G89h14RTjAI9eUjjy3/z9NBw7VVKD",
+	"1LVuLitdl6WqDGSxNdBLdnSu13DVzKXmwdjNjWgUqzVsGnkMS8H4Dll2JRZB3IRcGF/Cw8WRIQyvllUU",
+	"lR0gWkSsA+TUtwqwGxpuRgARukW0JRyhe5TTWIumE21UWSIrNEktm35jaDq1rY/MD23bIXFx0x6OTAHO",
+	"bjxMDvJLi1n7vlxyzRwcXjVRVmrhtHhDmJEHJFrIFJJ1lI/c4BRbhUdgA28YkSWdU0AwW+9w9Og3SnSj",
+	"RLBhF8YWvKNg+wagOk1VBbehyyoBqu1FyWbqjUKkHXebldml+Ot0obQWJbPdabFkgHvXKqdvQRg8BsNF",
+	"rhuBr7HytbOQQbDvrIXSeQUpSJOv8MDORVVYmzrdz9r/RlCwzM1ircctD5IZq+CSV5lvMXxZBYtJhMzg",
+	"Kn7F8I7KM4MrNFvHgJ43MwvDUm/xluEAe1Fu53wI1oDglGzXmRy7xqe1FnKLJR3znaAPTEhWiLRS3LpE",
+	"4GKsgGIaq38FBUfoyDjvBKrxOYVcJNYDIyKa2O/eQ8NbxkKaiY/r6WTzQ+FyCZXX2vaQGFLbnJUVaBhb",
+	"SKlUnjSP1r59b8Dd+zOdi/QcMoYnQ83bS+ezLkw4CXuAm6obC+jlcuWl57IECdnDPcaOpNMV2nu8J2D0",
+	"JpefmXXzX9GsWU3OGFwyWuTemYwJC96V44ZU5IdZTzvWt/GGU9lB1k9kruQIAfFLskRCFuJ0W13eKfUM",
+	"mOyQn7dEZaHYhrV/Sw5/vLPLIqOnTctHdT0rBHn9Bc2mTJjGEWP4Rhdmj6HmuwJ6m2hUYqMqlGsrWjm3",
+	"qULgE1fXaQqQHZ7JpANJqgo38YP2v/YgntUHB0+AHTzs99EGpUP3DLNnoN/3S3YwtZ8IXexLdjY5mwxG",
+	"qqBQF+DeIiFd214bh/2PZtwz+f2AFbGCr+wj1p9Fpuv5XKTCIp005HyhekJeq3mooAB8CmomzJSYN2GU",
+	"hGO7L+0BjN/Tt6G1iYzKhHVuQ6nDm9+7tKMZXPEUV8mJyazYJRJKQ2fD69aoMgkHiCpG18zoVNbWycTL",
+	"Udc8d0PToH27r4fvXe/13kFHQK57m0XlATKiEGxnCSwV7rpwjnbeG8vbBztAOjVCvvLgjlw6e+x/qZql",
+	"nM5vWRtonlKqovcJ9qUZhA7mdLJJiyHIoQCrXKEvn3/eX/jnn7s9F5rN4dJ7p37++RAdn39uD4HS5oUq",
+	"SpHDLYjmS66Xw52ecQ1PHrPT746ePXr80+NnX+Bi6JXFCzZbGdDsgfOcYNqscngYvx1JFRwd/Yun3kew",
+	"O25sHK3qKoWCl8OhrO+hRbttxrDdkG665EerbgDchszeAbJ+i3bW6rhxM27Mjnp84uokIr8RslC0iYR3",
+	"4Go229hp3K2WGgx9ctxgFzmb1nTff5hOTkVR59zcBgnuYI4OV72oVF06EdN60Lo7+A7M1Ss3A1l2V3dq",
+	"rV6vn2vF+BF00C0E6MptOZIcFfES22NkDqJwwgVPl8OJpp6jqSpzl8ESvGtI54Za99L3RBRQ3Lc4+pgN",
+	"aTqhbUjcRg9B/7vj0l69GkNQh1QiGqPesbHU2dPmBOjrw7TNCXvbYJdOlsWCU1YOQbbnrUZl4S1IO3Yg",
+	"VoF73umOfl3br2oehgG4rdYrbaAYGmht159GKPatR9mABpXMhYSkUBJW0cg3IeEVfYz1tvfjSGeSVMb6",
+	"9rV2Hfh7YHXn2UrTdEP80m4HB+JNE5RwC5vfH7dnpwwDIOiJDXnJOEtzAdIqj01Vp+ZMcmKbvTdgjyy8",
+	"qnlcTf/CN4nbBSJqezfUmeSky2tUtVH79Rwitp9vALy2XteLBejem5DNAc6kayUkq6UwNBc9qRO7YSVU",
+	"JK3s2Zb4DJoTy1HsF6gUm9WmK3eSn7Z91lljJU7D1PxMcsNy4NqwVwKt5zicV/p4mpFgLlV13mAhztEX",
+	"IEELncQFum/t1++4XvrlY8NGIWo/O3evSRsVMsFldgLB/s+D/znEADCe/HKQPP/v/fe/Pv3w8PPBj48/",
+	"fPnl/+3+9OTDlw//579iO+VhF9ko5CfH7k12ckwXeWunHMD+0QxeGHoQJTK8QQshKRilR1vsgVSmIaCH",
+	"rcXT7fqZRM8FozAaS2TcXI8c+ixucBbt6ehRTWcjejeeX+v7mESzUAk6dZF7zmQhzLKe7aWq2PeSzv5C",
+	"NVLPfsahUJK+Zfu8FPu6hHT/4tEGUfQG/IpF2BXO5cSQwM868ia3H7rqIRzRxpnaQAVUjxzDXEiB3w/P",
+	"ZMYN359xLVK9X2uovuI5lynsLRQ7ZG7IY274mRzwzdFQ8MDLkJX1LBcpO4dVjN7HlMtnZz8i1s/O3g8M",
+	"8cPbyE0VV9jTBAk6P6raJM6yMa6ZbLW3NDL1XjvrlLmx7Tbb8Z1BQ48YEcpSJ7lKeZ5oww3El1+WOS4/",
+	"dBpm1MnK/tqoynMWoRstKe7va+VcEVAJammf1Ro0+7ng5Y9CmvcscRq9o7JsPel/dgdYaArm2Fo2HnHL",
+	"H4rEtHArpezs4k6Dntpe3hCj45jDT4Q6aoNHrbUXXxdPONR3KsfNvTaagjGi2KnNMsEzFV2VRtKi8xC+",
+	"ChdcSO2N+FosJBKfC6HFYKsloPGAjHf05pt2uqt5h137Iyu0jXq1brMUmkUKJoyGLTP3WGNcrvoxMhpM",
+	"4771Fs5h9U61kV27BMWgmcgaxhKkmbEDUiI+As7a87FvjGu9zXf2SYSUlyVb5GrmTlVDFocNXfg+4wfI",
+	"svtbODwxomjQsIbeS15FEEEdxlBwjYXieDci/djySl4ZkYrSrn+7kJs3nT44yCamHmXjqCbscusBM41y",
+	"b9s4Qc1gdDsAv+B+1NqGUYfecX4mq6u1hmZGmVMc4c5yCCyz2p1sXpEE4ZctF+tAi1MJVLK9TT0YXYyE",
+	"1/bSmfbFRWvQR1RtdcFt1AghFXnHI9E1aAmcN4cLPob/8ZDFk8CbKIiEbwISPWPrH4ZpE5xqk9L4wEUf",
+	"rehDFCfTncINpxPnMxvbDiXpds8ghwV3pjRs7AnFgfaZDjYI4fh+Psc3P0tijklca5UKOu8BL3dzAAp/",
+	"nzNmtRVs6xFiZByATTYIGpi9VuHZlItdgJQgrDrMj03Wi+Bv2CI0qwmQdGLlRvFvyDvaQzRto3ftNg5V",
+	"Kk104Zs+G4tK5p1WzDaZweB9ECNRJmREyTBUZWjIga7jpMNZk3NYxaUKIDI89d0CcZ09EHO85B8GpqgK",
+	"FkIbaB+BeFq9VuPjPsQvlIFkLir0VcP3Z3R52OgbTcLgN9g0zn46qGI2vYjI4tyHpj2HVZKJvI7vtpv3",
+	"r8c47evm3aLr2Tms6JIhRfWM0uGoeW96bLNmauuct3bBL+2CX/JbW+92tIRNceJKKdOb4xOhqh4/WXeY",
+	"IgQYI47hro2idA17obfPcdz2FIb50muSkRlgb91rfXCYGrvWWhNIC8U45x0LVewEHa9fhXWes/5xQTaZ",
+	"YezJyBngZSmyq97b2Y464iCGU+wiqFuJf4AF2l032AYMBO/kmC9yBf6tb7c0uDNtXqCBq+JmzPQdJAOG",
+	"EE4ltM9qN0QUkjalXtqEKzRB/xVWf8O2tJzJh+nkZk/+GK7diBtw/abZ3iieSTFrn4AdzdmOKOclRpnz",
+	"PHE+AmOkWakLR5rU3LsUfGRWF39+v/v66OUbBz55gAKvrIpq7aqoHb3F6X+OkH7PC6uAG1WNnBGfOAsF",
+	"Vv98trJYsP9NSHGoT/H+qh1xDhmZoy+LmOaOC0+j06/M4yaijdoSO0GrTtz5cIYD3Fg5F+g2k1s99YND",
+	"FifSdoc3sIZwrjWpjAqbrUs30VGt1xS5HOBDk8gFzWszcLrZIY+QdZHgEUh0LtK49kDONB4kWRc4PDZm",
+	"1HhEJsQRazGiQZe1CMbCZnoLC0wPyGCOKDJJs7MGdzPl0g7UUvyrBiYykAY/Vc6LsnNY8Gx4V/jhrRZ3",
+	"u3cDU59g+Jtc9TjU2CVPQKy/50NFbyTYwr/7/EIbDTX+EOjndrDThDMObqY1NhZHH46arQV52VXYhllR",
+	"hzwICcNm0NqcktVrD5YW0JE5oilWRzn20Ti3xt478OmWLRO4IUO2Dr881yoyTC0vuTSQuX4Wh663Bvt0",
+	"x16XqqIASA1Ry6/QybxSv0D8QTnHjYo4djpUktRGvbdwE2qVI20uXI/fEI5R0h4TqIKPrGtHGznhROWB",
+	"Bps81b2eiUtL1ja7Y8ckGj8cQQu9b8dvD4eDeeD6kfPLGU/P43INwnTU2ko6GjGjmO/sd0E3ARqO9gKz",
+	"S9NW2KjBEqrW+3oYbH5NAeXTIvkMUlHwPK4gzQj73XD1TCyETZFZawhyMLqBbG5hS0Uuj6W1RrWoOZlj",
+	"2ECb5dXtRiYuhBazHKjFI9sC9fi0tkYn67vg8kCapabmj7dovqxlVkFmltoiVivWCJH0ompU0DMwlwCS",
+	"HVC7R8/ZA1K+a3EBDxGLThaZHD56Tq4O9o+D2GXncuGu4ysZMRbvixinY7I+2DHwknKj7kUjWG0C83EW",
+	"tuY02a7bnCVq6bje5rNUcMkXEDeqFhtgsn1pN0l318OLpEYZaFOpFQbhROcHw5E/jbg7IfuzYLgAnAIP",
+	"kFFMqwLpqU2waCf1w1nXYXsPN3D5j2TpKH0gVe/d+nH1tPYuj62a7FGveQFdtE4Zt4HeufB6cGCOIe6N",
+	"+O5DdRGfpBrZYH9vur7o6iSTAs9O9rB1pAvoLzYx2dKi0xrPu/rOK+uH3lbUwlGSUcTWHcTygCddG8V1",
+	"FV8nr3GqH96+dBdDoapYCpmWG7pLogJTCbiInti+Q1gjmTTXhcd8TEDxiXbIuzsWeEgfrAuNofymqnJJ",
+	"dhjIjG6QPWYD9RDsTqgVcW7nCJ6xHLIFeG1HXeaKZ1OG46C2gdlZtQtvpgAxSvKzsEGfDYoimqQgKcp2",
+	"1nWfijLucXNbKRlx1dpQygBteFHGPBSxxTvfgNwgL7jIvVWbWFqInT12bG8T7XmVnaQN72XNdI5+0ReP",
+	"BjaGp0tsoPYmm27C7RNTef9eHeQxdv9P23wfRKoIsstNZVNTTRmlnbwU2uZix9iMjoONB8NLCN4/sruy",
+	"qpbSEkmc3a1xXr8Oxj1wNG6j4YhC1sP5jlzLBj3tmqfrlHrF6HGQ9GuQwBhjCa9kkwXQ19hIuVRSpBSF",
+	"F2R/b0B2ed23UcFtEbDYf3350+0OZ+RcRVONNcZoh8XR5GPTSQdxQ/1D8BU31VKH/dNQAnF8VyzAaMfU",
+	"IJv6cDL3LBBSg8vfgkQUskhVddSaxByjyvI2icKOZEQOZSO33zf4jW4+4ZxAzoWkAGuHNkvQwgrulHba",
+	"4GtBGLZQoN16utE4+kfss/fuSp4gxO/3fJpqGsNqJHHZVgs+HOrIa/qdAhrbvsC2jLSP7c8d5zU76VFZ",
+	"uknHgw2jBj2M+hlDcESpmnitVoDcZvxwtDXkttaYRVcpEhrGezFtoKQreEAYI2kavrZRYkhR1IJZI3LU",
+	"g17ICBgvhYQ2iXrkgkijVwJtDJ3XkX46rdCMvzVPQ907Kd5jDE0bp4m46VC9DSaU0Br9HOPb2KZFHGEc",
+	"TYPWv53LVZO7Hak7kCNeUNEIh8hhkkMSqJz8lJGbUC/tYYxxIONOUhUT776+grR2yQx0+xBv4fGwkATs",
+	"AqYbCRghEal1mh313LbTuxyc3ftneAqH0pjtbiqeQqfvFhfhmFd1JjTXGopZHvHLOG4+BuHLSBC4aPw3",
+	"FqM/vgJnJtrZX8DbhKjjzpJtd6SBXIqkl6Bb4C5E0RDszSiinXz7bch1O+0N9qKd+nrU2Pa/RXLsh6QG",
+	"SIkxna+Rm4fRgoM0E5bfN7lyyRavfK5nesY1Hua9WG1ueBQPQXre9U/P8US7U7qRRjxy3rbxlNxeelbD",
+	"N+aXk466kXHjfEQNZ+tSKtl0BLERrDWRvruiWdHn/ZgF0RoQ8fOg93bi2kD4pbHXItSbpocA/dW7n7CS",
+	"C6e+blnDELPOUW3oOriNC0u7wf1FOPcvGiS2kjYpXFR9r/GT1cwEyd2mreWSuA8Z4ohdVZ4Z2QYGv1FF",
+	"g5iFecalhCyppRF5fHp8ZnTzPs649EwRIWEgM01B8y6bIKMEffQdSpUuKeOWsKlzaboRryqAEQPGMmDD",
+	"du2qGlnhlF1WwhiQjFt7qVXlxcXhNTi3JnA7oeQ5oqsLQQaptRYoerRjTcbNpnJaop84Rgpt6omd9FFB",
+	"qD20WqnBZutzUSaNd3uEARwrTCBGt5cb07clyauocyOC34b5OvSU6TYn0oplNKAEqx2fgcv9sze4TW1R",
+	"uTa5QwULXmV5d+vCzJcbkjzEsmmoBkdTctxv7UGkhtk5tYPbi36Gh413XAD7FjQwGH546Q1XGy52SAef",
+	"piJkVH2xIdXGSOXIeIoKPsTlEH9zjqqNhI8kLnbJHF0IN23H8KSEyV0Kfg5MGIbjTn0ByrbzpY/OqwCX",
+	"7GstXi5VDnFWigPVFYwXFvj7cjWYwM7eLW8pbGCPVAzmcySFkWT6iSv0tXPGFTVv4bhJbpUtU/OHoG5J",
+	"SjemovijtBGhB1HhaVPnGlotRvsytbohrz/q+k5G6Cy4fLseqQyPcXQ/sdEq8eJwn3BiWS+Jfqibm053",
+	"KMsnEKQPaScN6NQHhk2HakAryGqnQZRQhZPqbRMQoXJhhQHp7RPmerjvX1lrkI1nRkkYOy0jMscdZp8M",
+	"tnQs++R0cs2oga2O7FBajzwwQ0fTDc+k845ob8OLexSkKrhlET/QoO0o4g9daLddHq2DhLxaw3CdW29A",
+	"B7cjuN8G8e37NPKYGH1Wmtk2z8p4lCZ2p3etRYiPIx7eeh/tVdrJ5ufmje3630aT+NtEAigAAONSKjpR",
+	"zt7OOCtUBnkjSWM4Y7pyqT/0mUy5ZJmogFJ0ioKSyXOmL/kCzcsLkK78jZvejhbZrVrk2SaycWN8RW0j",
+	"qXh+y2Q6w0Nsgd1JrdXfWlro+uQxzTR3lTAG/XqsQNxBfzRtSpOLAYdgBH5bAGGd18Cs4tIaIgYYolGC",
+	"ArPDo5YuuZSQR3tbr6TfiEIK/k81AnMhZPxTnwQsYnpoaNfcXaGf0o//PpafU0NaV8KsKHLAy4Dip2hg",
+	"5LfN+XWV79pak+xdW4/SOca0p70tsfutsvk8Cy4zqy42lPf16yuOZWccH/3ys9mf4Mmfn2YHTx79afbn",
+	"g2cHKTx99vzggD9/yh89f/IIHv/52dMDeDT/4vnscfb46ePZ08dPv3j2PH3y9NHs6RfP//SZr29rAW1r",
+	"x/6DcoklR29OkncIbLtRvBR/hZVNh4TU6fO98ZQ4NxRc5JND/9P/588JHqB2eP/rxPkZTZbGlPpwf//y",
+	"8nIv7LK/oNz7iVF1utz38wzT7L45aZQm1t2YzpJ11WhETWFy8jGnb2+/Pn3Hjt6c7LXsYHI4Odg72HuE",
+	"46sSJC/F5HDyhH4iql/Svu8vgecGT8aH6WS/AFOJVLu/HAvfc6nu8KeLx/veAWD/V6fM+oDjLGJRFD5f",
+	"eOOAMkyqNLXXDAr/TX7wTpFHG7w+ZTMbL8BcinqZkYuIldr1ZDpp0IMpXX3g6UnLcXzIgw3FPPwxlhE6",
+	"lvIpUrC6jRYdr1XdshVkFQfJ8/e/Pvvzh5i32HiWiVbLqViu1DlzJZ59jAUjn5mgplOb7psiMoRkBRSq",
+	"WtFHqh3ZOtdYQ2WVLgW+36TKfI4DrFHWTaUhtFHV6gY1wt/3Si0/Pjj4yHWMn97ijF2bT2TeVzxHsoPM",
+	"q3stBI8+HgQnkoK1kSUwy/IIgqcfD4IgL7B7oPkMMt7PSyrTkiPC9+xj7tGJxOPJc0YtA2/zSLlmeS7V",
+	"pfQt8f6si4JXK7odg7RToXjzYZRb7gerwp/bvxKR3YiX2pCHdjx2cryBvX7WjZ22untu+i+6KG8NnuCf",
+	"Ips96r7PO3F3MWA627QWpo/L/q5RUv63ZYZ9F7uT439D9ngUpKmSyjBV+jSrg5CHPxhrbIp02ayeHU6z",
+	"hml2guNc0qNxXglB2YggT1o4CMWr2tGnTDdF+cpKKHwakeUhg7QCTg8ZSr4+DQpQOL0zWDnt1dE/yJ3y",
+	"1dE/bGUXz2zJfywyva1y1GWs34KJFEj5anXUMMJPRIJtkDRSwMQoH99GSCv41ZdjKLuyz52Y4Fnwq/Vi",
+	"5zSSBXsuFmQrDus1Ns45Loem0MwWu6jYK2uxeoORj4GFMQaOpfAORD7bmrNDFnpRdr0XGxze9Iq4L7Nz",
+	"X2bnky2z85HlkasmVpUzqWQiKRXgBbBAOffbiyO/8ZX/7ODJx5v+FKoLkQJ7B2g94JXIV+wH2XkcXl8E",
+	"ac5NLYOorLVnqH94AlkhEFJafOhRSeRlWwgnaO5C35tp4UpoE5U4elEVTGRTpg2vKD0l+ZLSueXVAnCR",
+	"EvQeOzKsUNqwXBTCdOe1hb/xmvWhME29YqvGbdK82s2aukCFaVDRZ67yXF0ShNKmdRlIMUchajZILa9s",
+	"PHjAYpxDBDPKwTp249ICdxQB3vn1lnwBwWx77AcNfWygc4PIWnfqsoILoWrddBoBDIeIwXVr1/1oddlb",
+	"C5bEJSSEhciR0y7TYckXQnLraUBZC1yRaC7Jg80XKfJ4dImXCbUN7brN8DaFzUlSw8Vud516BtDp+rHv",
+	"na94xrwb5f0Fc02Gjuy0s41xdry7Vq3PY4NqpZ1PYaLnJqe0C/Getu5QyFmtP5T3J536HGr4yencLPKm",
+	"gwxrm3jqV6uT420eg38s9Vb0bMf35v5wf1xlVrALr5Vh35BF6FNWW8XJKmA2WgOpp1wGqi0YjMvu1mUt",
+	"9sf1TAVP6NQl4nA1zZoy3Tz3cumYJIYzbMsvhgnoYpyiTbr1e+ERtjhDhC776L3nCx+XLxD+/xgcoU9K",
+	"LS+gYmh6/1cybIaMYHAYqfbopoP4+9VRrvFUIGeCxldhDgbzw+Nq+z5fEYbi/QfGucm63Ci3/JQioIfk",
+	"QTvnHaB2Ljf7HfUjXzSoIsT3vQ8Hxc9o2yVzuYt18cnwyH3DXg+Q2deWaRNoCc2QQI1iLsaC4S7uBOWL",
+	"dvKhD1quOjQR+JKF2PXEc4/gW0PwgKl9bU+4O15uEXd9sW2xzbd1T7KEvSZBiA64z3lxx5fnXa/vo9/F",
+	"d72g10qCVVuirGpp8a7v97vfpFsTFygsmZDiw2HDGpaN6JByky7rcv9X+g85fH5oHS1tuFFEsOiFQHFh",
+	"dGweF+JqvYMNKnyxkQ3G5KwQsjYwDfK7aYqHsmBz9CMMlXSXXS+uxj2zXSkVbBcYTIvFhuvCJmhFXTE1",
+	"SpdcUP5NpxREuqHwu8qVhGUIw5RUqwV+tA6KcuUaXC6VplGoUr23dPlKvyrP9CFrKvw2ZWVf8auveP5S",
+	"qXNKXktDaZ8bNm8d0vp1q7zTI/4cc5IESU6UX9M/zpnjO9vFJrb0gKo5Uzleam4aXgGGIDe2jeirLShS",
+	"v0FaXC+CtZvjq5E4sO5GFPs3srfvINTsLM10Ctr0EOqoquAZeKMETW6JrjmVDrxVYLZpSm7KjI6za0vg",
+	"kWH3AqpV8+vwWP+FIiXbomfaltcmr7LOYDutNZ6hYuYeSmPFeG5Z7Lzfodvfoa3k1v410wAuTHud3Mu2",
+	"97LtR11Q7/pundYjIRbuhEuVgV32nz/pZTtRyzmlNpIjyhU5sEsuSGxDMVKYP564f5c+L3e9mrt0oemK",
+	"kf4m6R2R4OEx7VFK/yHSPoBsCup9a9ZYpzo9tS1u1UvSjsmqNpw8DGC1MMVL5+uVNlAMi3HYrj+ty3Ac",
+	"jRRXVNI2KZSMRWDagrev6GOst/WrG+lMHo5jffupyjvw98DqzrPNjX9T/O79PkwmNzo2vdVWUDae5vjZ",
+	"0n97Hnwl0WF5zW64pWuul7XJ1GUQnNlWbB49SbbFrZ6k1yoDO26bVNUsIer2717tDojeAWrUIPGUJB6b",
+	"bTv7GheazYCMprxeLI3VcUTr0jQdE55awk+s/SY+YeuUZlvZ6Uis5nkFPMNyaSCZmuGioSMP9GtOO2VP",
+	"9AgHcJWVSkFryJIwj/460Fw7qwEwa9BEcBO8zSRMKzbn1TVhtRxhPZz9ChKtjO9V7UKOQL3d9Ov2rz95",
+	"uIvWF9ISAVVDUhiNbmAMhRtxUpeUAz3yJrRfsa4ALlZyqVxqwuhgVGx301HARiF0Gmx1m0YajaWWxoFH",
+	"LiestuxS8Aclz9081IemGAd4tDIBjvy3JgPEYOy2KrgbgXglccnYGsgvcXSu13DVzKXmkYrjrvbSppHH",
+	"sBSM39QrMJF4be/XOFzcJaoruRNmhqjsANEiYh0gp75VgN1Q2zYCiNAtoptq7V3KCfIbaqPKEnmSSWrZ",
+	"9BtD06ltfWR+aNsOictFUOGcLFNg3xyufaN19mpTmVGsnIPDO5qSd50NZBrCjIcxodyfyTrKx2N5iq3C",
+	"I7DhkPYFp/D4d85Z73D06DdKdKNEsGEXxha8jajWCQ791BxT+7rdOzTsdEXYQJRpRTj7934JpLDvymX7",
+	"+C5C/Zq9tBLK+LmDgYe7Z5hRyEIA720cwbEfN07HohNEBFkQfNyizxDblQ9xqm9UtZWjSqsvMIoefIyy",
+	"9rqp8XQ2Ut7vz+vjXn69l1/v5dd7+fVefr2XX+/l1z+W/Prb+HezJPGM2ocXx4KL2X3w1x8ourjjiGTF",
+	"axLIQ/vHqPeXAZ7vu8J0OHOp9GgASVjkLsXphGRlzoWkknc+dwbVcP7iqfecaTJD20S3yIOwwZPH7PS7",
+	"o2ePHv/0+NkXyJWWlCOl0/aBrzmrzSqHh3tYYtnp5gmIgpdsnvOF84uddv16qOx2BVQY0Dp5ld1aZI2c",
+	"ZlffQuqraAppM/2lKq+LJizZzj18tmCy4BcOkRteLbQO58n7Mz45fp52HksOx7g+N6kHjWvGyU+pW4Ly",
+	"5znPNfw85qXUoCsWD9xWuH9vGTJo85XKVr2zgVu8T7vdPRVtKlMheRWpJhcx8vbpqDHN0T4MHmQfbtVP",
+	"Kl5BekiTm8hxpIpy9ASvOxLjRQlxwwZDWSe1eY9OJjH3pPB2dXlZHYDbXGdIz35PWFtK4Le72hhB5I5Y",
+	"y8bvg5ivc294NEbPIp3kKZJpVqdgyyhZ+rlKsNECZOI4RTJT2Srp8Jnu3WKLEI5fLbawHLgSqu5kPNAP",
+	"mZCkB0HBO1QmRes/ByWywReqi3NoW3dtso7TXX/zunWzb+xv0R9ueESDFCEPVGXrSzwkdHFp3e2KksuV",
+	"14NBEpTksE54t8tbm8qjA462ffHo8HHlrr7u7xYt5CCkSp/oWWZQjZWW2LVaclu+c1NmdLveaKnhkco8",
+	"w030u+zElkb3V0KVmCsZKfjZK+95H09q/fx6aIULyJE+qK1U3l3+k40AfePyl8QZoNXMmyhD2NvIuKuA",
+	"ZRHn7iUs86y7y0/f8suAA23NU68SJyreWI5El7CVgUauimR3w+usUjxLKSJD+ZrsdyxjmquTiJKEwMSN",
+	"i1S+wvt1c94ZGncr4S0Y+uTYT0hp9LS2kcO/qSjXJmQ9ch7fHWzcy3V/FP3EV/7wacZZxS/7h9OqKOlM",
+	"bsGm+KW5klEutU8P7nGns+BAvLEtb9V4Nxi+a8NrtQHOiAR5ybirXoNNtanq1JxJTvratYXSGi30uCj1",
+	"wjeJmwwiGn031Jnk5EvbaHGjItUcIvaZbwC8xKbrxQK06XHiOcCZdK2EZLUUhuYqRFqpxLpe4nWNHH3P",
+	"tiz4is0pRE6xX6BSbFabcExttZwu/I4MijgNU/Mz2YThvRIo0OFwXhHWmM4t3TVYiFdQ6lf3GVYm0UJ/",
+	"x/XSL98rs/D/rrOLvgnLQv4GtYGikJ8cu6ysJ8cUBtTaEgewfzRbWCFkEiUyvPGdSb5PW+yBVKYhoIet",
+	"VdLt+plEYdooRoyem+uRQ99mMTiL9nSsr5XUMW34td5V3aSLRxvkgxvwKxZhV/c39x8ob2lAB3hamo2n",
+	"/Ir9vR+5l28hTfrvOzf6Rh+l+0zk95nI7zORb5mJfAud6f3u3ueZ/4TzzP/Boqzvw2//HcJvbyOD/d5a",
+	"CXH/V3O1TRLTcFSR2cryFaR25oaB9yqQt+lOh1ZDYfYY5sWogNxZNaa4QNM311YwktatrxDoFe3Ltp/J",
+	"pANJG6b+oP2vfeae1QcHT4AdPGTdLlZtETDeYVeSVOkTWZrYl+xscjbpD1RBoS7AZd+h1llNhlzbaeOo",
+	"/+GGPZPfV4ONQx0MqVaWvCwBLzVdz+ciFRbhlDuDL1TPFbHNUFBBAchPNRPGZ/sX2rpw2j1hXFpAYiL3",
+	"8Hbfpc5bj1jiUQBIdjsWIfrvbSoQ/buI18dguMh1E5wQeU3Ru6ZPWWjAbQ5uw1Om3qdd+9+cudrNkotz",
+	"CN2FyTXgkleZbzEU3Vy2YJnBVVyl5BOrZnDFRBzQeTObMDYtMmQkY2LXuOKQEuEkFjgdqyBPH6hAqkgr",
+	"xUkDSoi3vvQIBo2BZ4gjdBX+7Auvjs4p5CKxJWkjmmH73ZWsbVRgPYVzZFy/PaMOwG3iDuKkdMj7SAw3",
+	"ec5cRH58QmRPSeNO0L+1B/7P/ZnORXoOGVO1FSK9W3ZEVmQPmkTWc0FHdeUDPSy/e7jH2JF0GYzsEeqp",
+	"NHuTY2q1NfNfhRy6y/oi/mQpiAuobkhFfpj1tKNBZjeeyg6yfiK04cQJiF9GXk7bZoqKPJR6z5aAqCwU",
+	"27xQPn2540zeluBxJu9K8vjNZY97N5qPW5o52OZOcvYbvFCaqsQxCST+9nD+cGtc37++4HndsHrrQx/S",
+	"KLnY6ClTEpzfPbaj0kOkouZNDlZfscpawQx4O9i01UGpGtN5gRNC+kqpKSWpU7VLe9SMZfO3sCMLiz0N",
+	"cxKWbGVXBvM5pIYpe+KV9DenhYkY0Tsrmda5YQbyXNs8reQ23+Fol9hPGAcqxWBNg4IROL7N0WoTx1qJ",
+	"SUqowmF0v6JYymlKXwvMVDy1SZ2E0eylWoj0VCwGlaV9WZu/tLASBqZ0iTdAIir+0oy9Pous3U3XkbL/",
+	"rfbY34VZMn0uygQvJ27qCvS0z6w0yxTe/BLAJ721V9mU6ZZDicpthib11MyV84JLNG7AXFW2l0NlMfRh",
+	"PXUE23W8+kST+b+/Gzdcj6Nbc8QdDhj1RRuyBSQEz2L27tjnbIdcoDFAkUAt2TtR4w5yha46B+tOU4Wu",
+	"d05oXysj6CDGC471IwcblWQT22NkDstR1dxyp8FEUy+FNXUW7ckkMutYCrYhz4AnfIujjzkxTye0DYnb",
+	"6EiyB8cmfHbqGII6pBIJHe2J3plLJN3xfQjQ14fpNvLt3p+I+xPxhz4Rg1vobYNdBDII9lDzCMh/pKzG",
+	"96a1e9PaJ2ha84zKXh+Ova45sNsHcuA0kNboKkVvAl6Kn84B//8e5W4N1YV/LtRVPjmcLI0pD/f3qSzo",
+	"UmmzP/kwDb/p3kfkR3xhR3Czl5W4oMJC7z/8vwEAqeLb0KkVAQA=",
 }
 
 // GetSwagger returns the Swagger specification corresponding to the generated code
@@ -4,6 +4,7 @@
 package generated
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -19,6 +20,14 @@ type Account struct {
 	// specifies the amount of MicroAlgos in the account, without the pending rewards.
 	AmountWithoutPendingRewards uint64 `json:"amount-without-pending-rewards"`
 
+	// \[appl\] applications local data stored in this account.
+	//
+	// Note the raw object uses `map[int] -> AppLocalState` for this type.
+	AppsLocalState *[]ApplicationLocalState `json:"apps-local-state,omitempty"`
+
+	// Specifies maximums on the number of each type that may be stored.
+	AppsTotalSchema *ApplicationStateSchema `json:"apps-total-schema,omitempty"`
+
 	// \[asset\] assets held by this account.
 	//
 	// Note the raw object uses `map[int] -> AssetHolding` for this type.
@@ -27,6 +36,11 @@ type Account struct {
 	// \[spend\] the address against which signing should be checked. If empty, the address of the current account is used. This field can be updated in any transaction by setting the RekeyTo field.
 	AuthAddr *string `json:"auth-addr,omitempty"`
 
+	// \[appp\] parameters of applications created by this account including app global data.
+	//
+	// Note: the raw account uses `map[int] -> AppParams` for this type.
+	CreatedApps *[]Application `json:"created-apps,omitempty"`
+
 	// \[apar\] parameters of assets created by this account.
 	//
 	// Note: the raw account uses `map[int] -> Asset` for this type.
@@ -79,6 +93,69 @@ type AccountParticipation struct {
 	VoteParticipationKey []byte `json:"vote-participation-key"`
 }
 
+// AccountStateDelta defines model for AccountStateDelta.
+type AccountStateDelta struct {
+	Address string `json:"address"`
+
+	// Application state delta.
+	Delta StateDelta `json:"delta"`
+}
+
+// Application defines model for Application.
+type Application struct {
+
+	// \[appidx\] application index.
+	Id uint64 `json:"id"`
+
+	// Stores the global information associated with an application.
+	Params ApplicationParams `json:"params"`
+}
+
+// ApplicationLocalState defines model for ApplicationLocalState.
+type ApplicationLocalState struct {
+
+	// The application which this local state is for.
+	Id uint64 `json:"id"`
+
+	// Represents a key-value store for use in an application.
+	KeyValue *TealKeyValueStore `json:"key-value,omitempty"`
+
+	// Specifies maximums on the number of each type that may be stored.
+	Schema ApplicationStateSchema `json:"schema"`
+}
+
+// ApplicationParams defines model for ApplicationParams.
+type ApplicationParams struct {
+
+	// \[approv\] approval program.
+	ApprovalProgram []byte `json:"approval-program"`
+
+	// \[clearp\] clear state program.
+	ClearStateProgram []byte `json:"clear-state-program"`
+
+	// The address that created this application. This is the address where the parameters and global state for this application can be found.
+	Creator string `json:"creator"`
+
+	// Represents a key-value store for use in an application.
+	GlobalState *TealKeyValueStore `json:"global-state,omitempty"`
+
+	// Specifies maximums on the number of each type that may be stored.
+	GlobalStateSchema *ApplicationStateSchema `json:"global-state-schema,omitempty"`
+
+	// Specifies maximums on the number of each type that may be stored.
+	LocalStateSchema *ApplicationStateSchema `json:"local-state-schema,omitempty"`
+}
+
+// ApplicationStateSchema defines model for ApplicationStateSchema.
+type ApplicationStateSchema struct {
+
+	// \[nbs\] num of byte slices.
+	NumByteSlice uint64 `json:"num-byte-slice"`
+
+	// \[nui\] num of uints.
+	NumUint uint64 `json:"num-uint"`
+}
+
 // Asset defines model for Asset.
 type Asset struct {
 
@@ -150,12 +227,183 @@ type AssetParams struct {
 	Url *string `json:"url,omitempty"`
 }
 
+// DryrunRequest defines model for DryrunRequest.
+type DryrunRequest struct {
+	Accounts []Account     `json:"accounts"`
+	Apps     []Application `json:"apps"`
+
+	// LatestTimestamp is available to some TEAL scripts. Defaults to the latest confirmed timestamp this algod is attached to.
+	LatestTimestamp uint64 `json:"latest-timestamp"`
+
+	// ProtocolVersion specifies a specific version string to operate under, otherwise whatever the current protocol of the network this algod is running in.
+	ProtocolVersion string `json:"protocol-version"`
+
+	// Round is available to some TEAL scripts. Defaults to the current round on the network this algod is attached to.
+	Round   uint64            `json:"round"`
+	Sources []DryrunSource    `json:"sources"`
+	Txns    []json.RawMessage `json:"txns"`
+}
+
+// DryrunSource defines model for DryrunSource.
+type DryrunSource struct {
+	AppIndex uint64 `json:"app-index"`
+
+	// FieldName is what kind of sources this is. If lsig then it goes into the transactions[this.TxnIndex].LogicSig. If approv or clearp it goes into the Approval Program or Clear State Program of application[this.AppIndex].
+	FieldName string `json:"field-name"`
+	Source    string `json:"source"`
+	TxnIndex  uint64 `json:"txn-index"`
+}
+
+// DryrunState defines model for DryrunState.
+type DryrunState struct {
+
+	// Evaluation error if any
+	Error *string `json:"error,omitempty"`
+
+	// Line number
+	Line uint64 `json:"line"`
+
+	// Program counter
+	Pc      uint64       `json:"pc"`
+	Scratch *[]TealValue `json:"scratch,omitempty"`
+	Stack   []TealValue  `json:"stack"`
+}
+
+// DryrunTxnResult defines model for DryrunTxnResult.
+type DryrunTxnResult struct {
+
+	// Execution cost of the application program, as computed by the static check.
+	AppCallCost     *uint64        `json:"app-call-cost,omitempty"`
+	AppCallMessages *[]string      `json:"app-call-messages,omitempty"`
+	AppCallTrace    *[]DryrunState `json:"app-call-trace,omitempty"`
+
+	// Disassembled program line by line.
+	Disassembly []string `json:"disassembly"`
+
+	// Application state delta.
+	GlobalDelta *StateDelta          `json:"global-delta,omitempty"`
+	LocalDeltas *[]AccountStateDelta `json:"local-deltas,omitempty"`
+
+	// Execution cost of the LogicSig program, as computed by the static check.
+	LogicSigCost *uint64 `json:"logic-sig-cost,omitempty"`
+
+	// Disassembled lsig program line by line.
+	LogicSigDisassembly *[]string      `json:"logic-sig-disassembly,omitempty"`
+	LogicSigMessages    *[]string      `json:"logic-sig-messages,omitempty"`
+	LogicSigTrace       *[]DryrunState `json:"logic-sig-trace,omitempty"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Data    *string `json:"data,omitempty"`
 	Message string  `json:"message"`
 }
 
+// EvalDelta defines model for EvalDelta.
+type EvalDelta struct {
+
+	// \[at\] delta action.
+	Action uint64 `json:"action"`
+
+	// \[bs\] bytes value.
+	Bytes *string `json:"bytes,omitempty"`
+
+	// \[ui\] uint value.
+	Uint *uint64 `json:"uint,omitempty"`
+}
+
+// EvalDeltaKeyValue defines model for EvalDeltaKeyValue.
+type EvalDeltaKeyValue struct {
+	Key string `json:"key"`
+
+	// Represents a TEAL value delta.
+	Value EvalDelta `json:"value"`
+}
+
+// PeerScore defines model for PeerScore.
+type PeerScore struct {
+
+	// The time at which the ban of the peer ends, in seconds since the epoch, if it is banned.
+	BannedUntil *uint64 `json:"banned-until,omitempty"`
+
+	// The host of the peer, or the identity it proved, written as an address.
+	Peer string `json:"peer"`
+
+	// The sum of the penalties of the peer, decaying over time.
+	Score uint64 `json:"score"`
+}
+
+// SimulateRequest defines model for SimulateRequest.
+type SimulateRequest struct {
+
+	// Don't check the signatures and multisignatures of the transactions, so that they don't need to be signed. LogicSig programs are evaluated regardless.
+	SkipSignatures *bool `json:"skip-signatures,omitempty"`
+
+	// The transaction groups to simulate, one after the other.
+	TxnGroups []SimulateRequestTransactionGroup `json:"txn-groups"`
+}
+
+// SimulateRequestTransactionGroup defines model for SimulateRequestTransactionGroup.
+type SimulateRequestTransactionGroup struct {
+	Txns []json.RawMessage `json:"txns"`
+}
+
+// SimulateTransactionGroupResult defines model for SimulateTransactionGroupResult.
+type SimulateTransactionGroupResult struct {
+
+	// The index in the group of the transaction that would make it fail, unless the group would be rejected as a whole.
+	FailedAt *uint64 `json:"failed-at,omitempty"`
+
+	// Why the group would fail, in which case it has no effect.
+	FailureMessage *string `json:"failure-message,omitempty"`
+
+	// The result of each transaction of the group.
+	TxnResults []SimulateTransactionResult `json:"txn-results"`
+}
+
+// SimulateTransactionResult defines model for SimulateTransactionResult.
+type SimulateTransactionResult struct {
+
+	// Trace of the evaluation of the approval or clear state program of the transaction, if it is an application call.
+	AppTrace *string `json:"app-trace,omitempty"`
+
+	// What the transaction would apply, if its group would succeed: its closing amount, rewards, application state deltas and inner transactions.
+	ApplyData map[string]interface{} `json:"apply-data"`
+
+	// Trace of the evaluation of the LogicSig program of the transaction, if it has one.
+	LogicTrace *string `json:"logic-trace,omitempty"`
+
+	// The signed transaction.
+	Txn map[string]interface{} `json:"txn"`
+}
+
+// StateDelta defines model for StateDelta.
+type StateDelta []EvalDeltaKeyValue
+
+// TealKeyValue defines model for TealKeyValue.
+type TealKeyValue struct {
+	Key string `json:"key"`
+
+	// Represents a TEAL value.
+	Value TealValue `json:"value"`
+}
+
+// TealKeyValueStore defines model for TealKeyValueStore.
+type TealKeyValueStore []TealKeyValue
+
+// TealValue defines model for TealValue.
+type TealValue struct {
+
+	// \[tb\] bytes value.
+	Bytes string `json:"bytes"`
+
+	// \[tt\] value type.
+	Type uint64 `json:"type"`
+
+	// \[ui\] uint value.
+	Uint uint64 `json:"uint"`
+}
+
 // Version defines model for Version.
 type Version struct {
 
@@ -242,9 +490,36 @@ type TxId string
 // TxType defines model for tx-type.
 type TxType string
 
+// AccountApplicationResponse defines model for AccountApplicationResponse.
+type AccountApplicationResponse ApplicationLocalState
+
 // AccountResponse defines model for AccountResponse.
 type AccountResponse Account
 
+// ApplicationResponse defines model for ApplicationResponse.
+type ApplicationResponse Application
+
+// ApplicationsResponse defines model for ApplicationsResponse.
+type ApplicationsResponse struct {
+	Applications []Application `json:"applications"`
+
+	// Used for pagination, when making another request provide this token with the next parameter.
+	NextToken *string `json:"next-token,omitempty"`
+}
+
+// AssetResponse defines model for AssetResponse.
+type AssetResponse Asset
+
+// BlockDeltaResponse defines model for BlockDeltaResponse.
+type BlockDeltaResponse struct {
+
+	// Block data.
+	Block map[string]interface{} `json:"block"`
+
+	// Changes made by the block. The accounts property lists the address and new account data of every account the block modified; closed accounts have empty account data.
+	Delta map[string]interface{} `json:"delta"`
+}
+
 // BlockResponse defines model for BlockResponse.
 type BlockResponse struct {
 
@@ -269,9 +544,21 @@ type CatchpointStartResponse struct {
 	CatchupMessage string `json:"catchup-message"`
 }
 
+// DryrunResponse defines model for DryrunResponse.
+type DryrunResponse struct {
+	Error string `json:"error"`
+
+	// Protocol version is the protocol version Dryrun was operated under.
+	ProtocolVersion string            `json:"protocol-version"`
+	Txns            []DryrunTxnResult `json:"txns"`
+}
+
 // NodeStatusResponse defines model for NodeStatusResponse.
 type NodeStatusResponse struct {
 
+	// The round before which the node is deleting the blocks it no longer keeps
+	BlockPruneTargetRound *uint64 `json:"block-prune-target-round,omitempty"`
+
 	// The current catchpoint that is being caught up to
 	Catchpoint *string `json:"catchpoint,omitempty"`
 
@@ -290,6 +577,9 @@ type NodeStatusResponse struct {
 	// CatchupTime in nanoseconds
 	CatchupTime uint64 `json:"catchup-time"`
 
+	// The oldest round from which on the node holds all the blocks
+	EarliestBlockRound *uint64 `json:"earliest-block-round,omitempty"`
+
 	// The last catchpoint seen by the node
 	LastCatchpoint *string `json:"last-catchpoint,omitempty"`
 
@@ -315,9 +605,17 @@ type NodeStatusResponse struct {
 	TimeSinceLastRound uint64 `json:"time-since-last-round"`
 }
 
+// PeerScoresResponse defines model for PeerScoresResponse.
+type PeerScoresResponse struct {
+	Peers []PeerScore `json:"peers"`
+}
+
 // PendingTransactionResponse defines model for PendingTransactionResponse.
 type PendingTransactionResponse struct {
 
+	// The application index if the transaction was found and it created an application.
+	ApplicationIndex *uint64 `json:"application-index,omitempty"`
+
 	// The asset index if the transaction was found and it created an asset.
 	AssetIndex *uint64 `json:"asset-index,omitempty"`
 
@@ -361,6 +659,9 @@ type PostCompileResponse struct {
 
 	// base64 encoded program bytes
 	Result string `json:"result"`
+
+	// JSON of the source map
+	Sourcemap *map[string]interface{} `json:"sourcemap,omitempty"`
 }
 
 // PostTransactionsResponse defines model for PostTransactionsResponse.
@@ -370,6 +671,22 @@ type PostTransactionsResponse struct {
 	TxId string `json:"txId"`
 }
 
+// SimulateResponse defines model for SimulateResponse.
+type SimulateResponse struct {
+
+	// Changes made by the transaction groups that would succeed. The accounts property lists the address and new account data of every account they would modify; closed accounts have empty account data.
+	Delta map[string]interface{} `json:"delta"`
+
+	// The round the transaction groups were evaluated in.
+	LastRound uint64 `json:"last-round"`
+
+	// The result of each transaction group, in the order of the request.
+	TxnGroups []SimulateTransactionGroupResult `json:"txn-groups"`
+
+	// Whether all the transaction groups would succeed.
+	WouldSucceed bool `json:"would-succeed"`
+}
+
 // SupplyResponse defines model for SupplyResponse.
 type SupplyResponse struct {
 
@@ -410,6 +727,13 @@ type TransactionParametersResponse struct {
 	MinFee uint64 `json:"min-fee"`
 }
 
+// AccountInformationParams defines parameters for AccountInformation.
+type AccountInformationParams struct {
+
+	// The round at which to look up the account. Rounds that are no longer held in memory are only available from archival nodes that keep the account history.
+	Round *uint64 `json:"round,omitempty"`
+}
+
 // GetPendingTransactionsByAddressParams defines parameters for GetPendingTransactionsByAddress.
 type GetPendingTransactionsByAddressParams struct {
 
@@ -420,6 +744,16 @@ type GetPendingTransactionsByAddressParams struct {
 	Format *string `json:"format,omitempty"`
 }
 
+// GetApplicationsParams defines parameters for GetApplications.
+type GetApplicationsParams struct {
+
+	// Maximum number of results to return.
+	Limit *uint64 `json:"limit,omitempty"`
+
+	// The next page of results. Use the next token provided by the previous results.
+	Next *string `json:"next,omitempty"`
+}
+
 // GetBlockParams defines parameters for GetBlock.
 type GetBlockParams struct {
 
@@ -427,6 +761,23 @@ type GetBlockParams struct {
 	Format *string `json:"format,omitempty"`
 }
 
+// GetBlockDeltaParams defines parameters for GetBlockDelta.
+type GetBlockDeltaParams struct {
+
+	// Configures whether the response object is JSON or MessagePack encoded.
+	Format *string `json:"format,omitempty"`
+}
+
+// TealCompileParams defines parameters for TealCompile.
+type TealCompileParams struct {
+
+	// When set to `true`, returns the source map of the program as a JSON. Defaults to `false`.
+	Sourcemap *bool `json:"sourcemap,omitempty"`
+}
+
+// TealDryrunJSONBody defines parameters for TealDryrun.
+type TealDryrunJSONBody DryrunRequest
+
 // GetPendingTransactionsParams defines parameters for GetPendingTransactions.
 type GetPendingTransactionsParams struct {
 
@@ -443,3 +794,19 @@ type PendingTransactionInformationParams struct {
 	// Configures whether the response object is JSON or MessagePack encoded.
 	Format *string `json:"format,omitempty"`
 }
+
+// SimulateTransactionJSONBody defines parameters for SimulateTransaction.
+type SimulateTransactionJSONBody SimulateRequest
+
+// SimulateTransactionParams defines parameters for SimulateTransaction.
+type SimulateTransactionParams struct {
+
+	// Configures whether the response object is JSON or MessagePack encoded.
+	Format *string `json:"format,omitempty"`
+}
+
+// TealDryrunRequestBody defines body for TealDryrun for application/json ContentType.
+type TealDryrunJSONRequestBody TealDryrunJSONBody
+
+// SimulateTransactionRequestBody defines body for SimulateTransaction for application/json ContentType.
+type SimulateTransactionJSONRequestBody SimulateTransactionJSONBody
@@ -22,13 +22,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/algod/api/server/lib"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated/private"
 	"github.com/algorand/go-algorand/data"
@@ -38,12 +42,22 @@ import (
 	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/network"
 	"github.com/algorand/go-algorand/node"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/rpcs"
 )
 
 const maxTealSourceBytes = 1e5
+const maxTealDryrunBytes = 1e5
+const maxSimulateBytes = 1e6
+
+// maxApplicationsToList is the largest number of applications returned by
+// GetApplications.
+const maxApplicationsToList = 100
+
+// blockDeltaWaitTimeout is how long GetBlockDelta waits for a round to be committed
+var blockDeltaWaitTimeout = 1 * time.Minute
 
 // Handlers is an implementation to the V2 route handler interface defined by the generated code.
 type Handlers struct {
@@ -65,6 +79,7 @@ type NodeInterface interface {
 	StartCatchup(catchpoint string) error
 	AbortCatchup(catchpoint string) error
 	Config() config.Local
+	PeerScores() []network.PeerScore
 }
 
 // RegisterParticipationKeys registers participation keys.
@@ -83,7 +98,7 @@ func (v2 *Handlers) ShutdownNode(ctx echo.Context, params private.ShutdownNodePa
 
 // AccountInformation gets account information for a given account.
 // (GET /v2/accounts/{address})
-func (v2 *Handlers) AccountInformation(ctx echo.Context, address string) error {
+func (v2 *Handlers) AccountInformation(ctx echo.Context, address string, params generated.AccountInformationParams) error {
 	addr, err := basics.UnmarshalChecksumAddress(address)
 	if err != nil {
 		return badRequest(ctx, err, errFailedToParseAddress, v2.Log)
@@ -91,8 +106,17 @@ func (v2 *Handlers) AccountInformation(ctx echo.Context, address string) error {
 
 	myLedger := v2.Node.Ledger()
 	lastRound := myLedger.Latest()
+	if params.Round != nil {
+		if basics.Round(*params.Round) > lastRound {
+			return badRequest(ctx, fmt.Errorf("round %d is after the latest round %d", *params.Round, lastRound), errRoundNotYetCommitted, v2.Log)
+		}
+		lastRound = basics.Round(*params.Round)
+	}
 	record, err := myLedger.Lookup(lastRound, basics.Address(addr))
 	if err != nil {
+		if _, ok := err.(ledger.ErrHistoryNotAvailable); ok {
+			return notFound(ctx, err, errRoundHistoryNotAvailable, v2.Log)
+		}
 		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
 	}
 	recordWithoutPendingRewards, err := myLedger.LookupWithoutRewards(lastRound, basics.Address(addr))
@@ -135,28 +159,27 @@ func (v2 *Handlers) AccountInformation(ctx echo.Context, address string) error {
 	createdAssets := make([]generated.Asset, 0)
 	if len(record.AssetParams) > 0 {
 		for idx, params := range record.AssetParams {
-			assetParams := generated.AssetParams{
-				Creator:       address,
-				Total:         params.Total,
-				Decimals:      uint64(params.Decimals),
-				DefaultFrozen: &params.DefaultFrozen,
-				MetadataHash:  byteOrNil(params.MetadataHash[:]),
-				Name:          strOrNil(params.AssetName),
-				UnitName:      strOrNil(params.UnitName),
-				Url:           strOrNil(params.URL),
-				Clawback:      addrOrNil(params.Clawback),
-				Freeze:        addrOrNil(params.Freeze),
-				Manager:       addrOrNil(params.Manager),
-				Reserve:       addrOrNil(params.Reserve),
-			}
-			asset := generated.Asset{
-				Index:  uint64(idx),
-				Params: assetParams,
-			}
+			asset := assetParamsToAsset(address, idx, &params)
 			createdAssets = append(createdAssets, asset)
 		}
 	}
 
+	appsLocalState := make([]generated.ApplicationLocalState, 0, len(record.AppLocalStates))
+	for appIdx, state := range record.AppLocalStates {
+		appsLocalState = append(appsLocalState, appLocalStateToGenerated(appIdx, &state))
+	}
+
+	createdApps := make([]generated.Application, 0, len(record.AppParams))
+	for appIdx, appParams := range record.AppParams {
+		app := appParamsToApplication(addr.String(), appIdx, &appParams)
+		createdApps = append(createdApps, app)
+	}
+
+	totalAppSchema := generated.ApplicationStateSchema{
+		NumByteSlice: record.TotalAppSchema.NumByteSlice,
+		NumUint:      record.TotalAppSchema.NumUint,
+	}
+
 	var apiParticipation *generated.AccountParticipation
 	if record.VoteID != (crypto.OneTimeSignatureVerifier{}) {
 		apiParticipation = &generated.AccountParticipation{
@@ -181,12 +204,39 @@ func (v2 *Handlers) AccountInformation(ctx echo.Context, address string) error {
 		Participation:               apiParticipation,
 		CreatedAssets:               &createdAssets,
 		Assets:                      &assets,
+		CreatedApps:                 &createdApps,
+		AppsLocalState:              &appsLocalState,
+		AppsTotalSchema:             &totalAppSchema,
 		AuthAddr:                    addrOrNil(record.AuthAddr),
 	}
 
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// AccountApplicationInformation gets the local state of an account for a given application.
+// (GET /v2/accounts/{address}/applications/{application-id})
+func (v2 *Handlers) AccountApplicationInformation(ctx echo.Context, address string, applicationID uint64) error {
+	addr, err := basics.UnmarshalChecksumAddress(address)
+	if err != nil {
+		return badRequest(ctx, err, errFailedToParseAddress, v2.Log)
+	}
+
+	myLedger := v2.Node.Ledger()
+	record, err := myLedger.LookupWithoutRewards(myLedger.Latest(), addr)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+
+	appIdx := basics.AppIndex(applicationID)
+	state, ok := record.AppLocalStates[appIdx]
+	if !ok {
+		return notFound(ctx, errors.New(errAccountAppDoesNotExist), errAccountAppDoesNotExist, v2.Log)
+	}
+
+	response := generated.AccountApplicationResponse(appLocalStateToGenerated(appIdx, &state))
+	return ctx.JSON(http.StatusOK, response)
+}
+
 // GetBlock gets the block for the given round.
 // (GET /v2/blocks/{round})
 func (v2 *Handlers) GetBlock(ctx echo.Context, round uint64, params generated.GetBlockParams) error {
@@ -227,6 +277,78 @@ func (v2 *Handlers) GetBlock(ctx echo.Context, round uint64, params generated.Ge
 	return ctx.Blob(http.StatusOK, contentType, data)
 }
 
+// accountDelta is the new state of an account modified by a block.
+type accountDelta struct {
+	Address string             `codec:"address"`
+	Account basics.AccountData `codec:"account"`
+}
+
+// GetBlockDelta waits for the given round to be committed, and returns its block along with the new state of the
+// accounts it modified. Only the rounds whose changes the ledger holds are available, as listed by LookupDelta;
+// the older ones fail with a not found error.
+// (GET /v2/deltas/{round})
+func (v2 *Handlers) GetBlockDelta(ctx echo.Context, round uint64, params generated.GetBlockDeltaParams) error {
+	handle, contentType, err := getCodecHandle(params.Format)
+	if err != nil {
+		return badRequest(ctx, err, errFailedParsingFormatOption, v2.Log)
+	}
+
+	stat, err := v2.Node.Status()
+	if err != nil {
+		return internalError(ctx, err, errFailedRetrievingNodeStatus, v2.Log)
+	}
+	if stat.Catchpoint != "" {
+		return serviceUnavailable(ctx, fmt.Errorf("GetBlockDelta failed as the node was catchpoint catchuping"), errOperationNotAvailableDuringCatchup, v2.Log)
+	}
+
+	l := v2.Node.Ledger()
+	select {
+	case <-v2.Shutdown:
+		return internalError(ctx, errors.New(errServiceShuttingDown), errServiceShuttingDown, v2.Log)
+	case <-ctx.Request().Context().Done():
+		return requestTimeout(ctx, ctx.Request().Context().Err(), errRoundNotCommitted, v2.Log)
+	case <-time.After(blockDeltaWaitTimeout):
+		return requestTimeout(ctx, fmt.Errorf("round %d was not committed after %v", round, blockDeltaWaitTimeout), errRoundNotCommitted, v2.Log)
+	case <-l.Wait(basics.Round(round)):
+	}
+
+	block, err := l.Block(basics.Round(round))
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+	modified, err := l.LookupDelta(basics.Round(round))
+	if _, ok := err.(ledger.ErrDeltaNotAvailable); ok {
+		return notFound(ctx, err, errRoundDeltaNotAvailable, v2.Log)
+	}
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+
+	accounts := make([]accountDelta, 0, len(modified))
+	for addr, data := range modified {
+		accounts = append(accounts, accountDelta{Address: addr.String(), Account: data})
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Address < accounts[j].Address })
+
+	// Encoding wasn't working well without embedding "real" objects.
+	response := struct {
+		Block bookkeeping.Block `codec:"block"`
+		Delta struct {
+			Accounts []accountDelta `codec:"accounts"`
+		} `codec:"delta"`
+	}{
+		Block: block,
+	}
+	response.Delta.Accounts = accounts
+
+	data, err := encode(handle, response)
+	if err != nil {
+		return internalError(ctx, err, errFailedToEncodeResponse, v2.Log)
+	}
+
+	return ctx.Blob(http.StatusOK, contentType, data)
+}
+
 // GetSupply gets the current supply reported by the ledger.
 // (GET /v2/ledger/supply)
 func (v2 *Handlers) GetSupply(ctx echo.Context) error {
@@ -254,6 +376,8 @@ func (v2 *Handlers) GetStatus(ctx echo.Context) error {
 		return internalError(ctx, err, errFailedRetrievingNodeStatus, v2.Log)
 	}
 
+	earliestBlockRound := uint64(stat.EarliestBlockRound)
+	blockPruneTargetRound := uint64(stat.BlockPruneTargetRound)
 	response := generated.NodeStatusResponse{
 		LastRound:                   uint64(stat.LastRound),
 		LastVersion:                 string(stat.LastVersion),
@@ -269,6 +393,8 @@ func (v2 *Handlers) GetStatus(ctx echo.Context) error {
 		CatchpointProcessedAccounts: &stat.CatchpointCatchupProcessedAccounts,
 		CatchpointTotalBlocks:       &stat.CatchpointCatchupTotalBlocks,
 		CatchpointAcquiredBlocks:    &stat.CatchpointCatchupAcquiredBlocks,
+		EarliestBlockRound:          &earliestBlockRound,
+		BlockPruneTargetRound:       &blockPruneTargetRound,
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -364,6 +490,109 @@ func (v2 *Handlers) RawTransaction(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, generated.PostTransactionsResponse{TxId: txid.String()})
 }
 
+// simulateTxnResult is the outcome of a transaction simulated by SimulateTransaction.
+type simulateTxnResult struct {
+	Txn        transactions.SignedTxn `codec:"txn"`
+	ApplyData  transactions.ApplyData `codec:"apply-data"`
+	LogicTrace string                 `codec:"logic-trace,omitempty"`
+	AppTrace   string                 `codec:"app-trace,omitempty"`
+}
+
+// simulateGroupResult is the outcome of a transaction group simulated by SimulateTransaction.
+type simulateGroupResult struct {
+	TxnResults     []simulateTxnResult `codec:"txn-results"`
+	FailureMessage string              `codec:"failure-message,omitempty"`
+	// FailedAt isn't omitempty, since the codec would omit a pointer to 0
+	FailedAt *uint64 `codec:"failed-at"`
+}
+
+// SimulateTransaction evaluates transaction groups on top of the latest round, without changing the ledger, and
+// returns what they would apply.
+// (POST /v2/transactions/simulate)
+func (v2 *Handlers) SimulateTransaction(ctx echo.Context, params generated.SimulateTransactionParams) error {
+	handle, contentType, err := getCodecHandle(params.Format)
+	if err != nil {
+		return badRequest(ctx, err, errFailedParsingFormatOption, v2.Log)
+	}
+
+	stat, err := v2.Node.Status()
+	if err != nil {
+		return internalError(ctx, err, errFailedRetrievingNodeStatus, v2.Log)
+	}
+	if stat.Catchpoint != "" {
+		return serviceUnavailable(ctx, fmt.Errorf("SimulateTransaction failed as the node was catchpoint catchuping"), errOperationNotAvailableDuringCatchup, v2.Log)
+	}
+
+	buf := new(bytes.Buffer)
+	ctx.Request().Body = http.MaxBytesReader(nil, ctx.Request().Body, maxSimulateBytes)
+	_, err = buf.ReadFrom(ctx.Request().Body)
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+	sr, err := DecodeSimulateRequest(buf.Bytes())
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+	if len(sr.TxnGroups) == 0 {
+		err := errors.New("no transaction groups")
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+
+	txgroups := make([][]transactions.SignedTxn, len(sr.TxnGroups))
+	for i, group := range sr.TxnGroups {
+		txgroups[i] = group.Txns
+	}
+	res, err := v2.Node.Ledger().Simulate(txgroups, sr.SkipSignatures)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+
+	groups := make([]simulateGroupResult, len(res.Groups))
+	for i, group := range res.Groups {
+		groups[i].FailureMessage = group.FailureMessage
+		if group.FailedAt >= 0 {
+			failedAt := uint64(group.FailedAt)
+			groups[i].FailedAt = &failedAt
+		}
+		groups[i].TxnResults = make([]simulateTxnResult, len(group.Txns))
+		for j, txn := range group.Txns {
+			groups[i].TxnResults[j] = simulateTxnResult{
+				Txn:        txn.Txn,
+				ApplyData:  txn.ApplyData,
+				LogicTrace: txn.LogicTrace,
+				AppTrace:   txn.AppTrace,
+			}
+		}
+	}
+
+	accounts := make([]accountDelta, 0, len(res.Delta))
+	for addr, data := range res.Delta {
+		accounts = append(accounts, accountDelta{Address: addr.String(), Account: data})
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Address < accounts[j].Address })
+
+	response := struct {
+		LastRound    uint64                `codec:"last-round"`
+		WouldSucceed bool                  `codec:"would-succeed"`
+		TxnGroups    []simulateGroupResult `codec:"txn-groups"`
+		Delta        struct {
+			Accounts []accountDelta `codec:"accounts"`
+		} `codec:"delta"`
+	}{
+		LastRound:    uint64(res.Round),
+		WouldSucceed: res.WouldSucceed(),
+		TxnGroups:    groups,
+	}
+	response.Delta.Accounts = accounts
+
+	data, err := encode(handle, response)
+	if err != nil {
+		return internalError(ctx, err, errFailedToEncodeResponse, v2.Log)
+	}
+
+	return ctx.Blob(http.StatusOK, contentType, data)
+}
+
 // TransactionParams returns the suggested parameters for constructing a new transaction.
 // (GET /v2/transactions/params)
 func (v2 *Handlers) TransactionParams(ctx echo.Context) error {
@@ -421,6 +650,7 @@ func (v2 *Handlers) PendingTransactionInformation(ctx echo.Context, txid string,
 
 	// Encoding wasn't working well without embedding "real" objects.
 	response := struct {
+		AppIndex        *uint64                `codec:"application-index,omitempty"`
 		AssetIndex      *uint64                `codec:"asset-index,omitempty"`
 		CloseRewards    *uint64                `codec:"close-rewards,omitempty"`
 		ClosingAmount   *uint64                `codec:"closing-amount,omitempty"`
@@ -448,6 +678,9 @@ func (v2 *Handlers) PendingTransactionInformation(ctx echo.Context, txid string,
 		response.CloseRewards = &txn.ApplyData.CloseRewards.Raw
 
 		response.AssetIndex = computeAssetIndexFromTxn(txn, v2.Node.Ledger())
+		if appIndex := lib.ComputeAppIndexFromTxn(txn, v2.Node.Ledger()); appIndex != 0 {
+			response.AppIndex = &appIndex
+		}
 	}
 
 	data, err := encode(handle, response)
@@ -587,9 +820,25 @@ func (v2 *Handlers) AbortCatchup(ctx echo.Context, catchpoint string) error {
 	return v2.abortCatchup(ctx, catchpoint)
 }
 
+// GetPeerScores returns the scores of the gossip peers that misbehaved recently.
+// (GET /v2/status/peers)
+func (v2 *Handlers) GetPeerScores(ctx echo.Context) error {
+	scores := v2.Node.PeerScores()
+	response := private.PeerScoresResponse{Peers: make([]private.PeerScore, 0, len(scores))}
+	for _, score := range scores {
+		peer := private.PeerScore{Peer: score.Peer, Score: score.Score}
+		if !score.BannedUntil.IsZero() {
+			bannedUntil := uint64(score.BannedUntil.Unix())
+			peer.BannedUntil = &bannedUntil
+		}
+		response.Peers = append(response.Peers, peer)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
 // TealCompile compiles TEAL code to binary, return both binary and hash
 // (POST /v2/teal/compile)
-func (v2 *Handlers) TealCompile(ctx echo.Context) error {
+func (v2 *Handlers) TealCompile(ctx echo.Context, params generated.TealCompileParams) error {
 	// return early if teal compile is not allowed in node config
 	if !v2.Node.Config().EnableDeveloperAPI {
 		return ctx.String(http.StatusNotFound, "/teal/compile was not enabled in the configuration file by setting the EnableDeveloperAPI to true")
@@ -598,7 +847,7 @@ func (v2 *Handlers) TealCompile(ctx echo.Context) error {
 	ctx.Request().Body = http.MaxBytesReader(nil, ctx.Request().Body, maxTealSourceBytes)
 	buf.ReadFrom(ctx.Request().Body)
 	source := buf.String()
-	program, err := logic.AssembleString(source)
+	program, sm, err := logic.AssembleStringWithSourceMap(source)
 	if err != nil {
 		return badRequest(ctx, err, err.Error(), v2.Log)
 	}
@@ -609,5 +858,167 @@ func (v2 *Handlers) TealCompile(ctx echo.Context) error {
 		Hash:   addr.String(),
 		Result: base64.StdEncoding.EncodeToString(program),
 	}
+	if params.Sourcemap != nil && *params.Sourcemap {
+		sourcemap := map[string]interface{}{
+			"version":  sm.Version,
+			"sources":  sm.Sources,
+			"names":    sm.Names,
+			"mappings": sm.Mappings,
+		}
+		response.Sourcemap = &sourcemap
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// TealDryrun takes transactions and additional simulated ledger state and returns debugging information.
+// (POST /v2/teal/dryrun)
+func (v2 *Handlers) TealDryrun(ctx echo.Context) error {
+	// return early if teal dryrun is not allowed in node config
+	if !v2.Node.Config().EnableDeveloperAPI {
+		return ctx.String(http.StatusNotFound, "/teal/dryrun was not enabled in the configuration file by setting the EnableDeveloperAPI to true")
+	}
+	buf := new(bytes.Buffer)
+	ctx.Request().Body = http.MaxBytesReader(nil, ctx.Request().Body, maxTealDryrunBytes)
+	buf.ReadFrom(ctx.Request().Body)
+	dr, err := DecodeDryrunRequest(buf.Bytes())
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+
+	myLedger := v2.Node.Ledger()
+	hdr, err := myLedger.BlockHdr(myLedger.Latest())
+	if err != nil {
+		return internalError(ctx, err, errFailedRetrievingLatestBlockHeaderStatus, v2.Log)
+	}
+
+	protocolVersion := hdr.CurrentProtocol
+	if dr.ProtocolVersion != "" {
+		protocolVersion = protocol.ConsensusVersion(dr.ProtocolVersion)
+	}
+	proto, ok := config.Consensus[protocolVersion]
+	if !ok {
+		err = fmt.Errorf("unsupported protocol version: %s", protocolVersion)
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+	if dr.Round == 0 {
+		dr.Round = uint64(hdr.Round + 1)
+	}
+	if dr.LatestTimestamp == 0 {
+		dr.LatestTimestamp = hdr.TimeStamp
+	}
+
+	response := RunDryrunRequest(&dr, &proto)
+	response.ProtocolVersion = string(protocolVersion)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetApplications returns the applications with the largest indices, or the
+// ones following the next token.
+// (GET /v2/applications)
+func (v2 *Handlers) GetApplications(ctx echo.Context, params generated.GetApplicationsParams) error {
+	limit := uint64(maxApplicationsToList)
+	if params.Limit != nil {
+		if *params.Limit == 0 || *params.Limit > maxApplicationsToList {
+			err := fmt.Errorf(errFailedParsingLimit, maxApplicationsToList)
+			return badRequest(ctx, err, err.Error(), v2.Log)
+		}
+		limit = *params.Limit
+	}
+
+	maxAppIdx := basics.AppIndex(math.MaxInt64)
+	if params.Next != nil {
+		next, err := strconv.ParseUint(*params.Next, 10, 64)
+		if err != nil {
+			return badRequest(ctx, err, errFailedParsingNextToken, v2.Log)
+		}
+		maxAppIdx = basics.AppIndex(next)
+	}
+
+	myLedger := v2.Node.Ledger()
+	locs, err := myLedger.ListApplications(maxAppIdx, limit)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+
+	latest := myLedger.Latest()
+	records := make(map[basics.Address]basics.AccountData)
+	response := generated.ApplicationsResponse{Applications: make([]generated.Application, 0, len(locs))}
+	for _, loc := range locs {
+		record, ok := records[loc.Creator]
+		if !ok {
+			record, err = myLedger.LookupWithoutRewards(latest, loc.Creator)
+			if err != nil {
+				return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+			}
+			records[loc.Creator] = record
+		}
+
+		// the application may have been deleted since it was listed.
+		appIdx := basics.AppIndex(loc.Index)
+		appParams, ok := record.AppParams[appIdx]
+		if !ok {
+			continue
+		}
+		response.Applications = append(response.Applications, appParamsToApplication(loc.Creator.String(), appIdx, &appParams))
+	}
+
+	if uint64(len(locs)) == limit && locs[len(locs)-1].Index > 1 {
+		nextToken := strconv.FormatUint(uint64(locs[len(locs)-1].Index)-1, 10)
+		response.NextToken = &nextToken
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetApplicationByID returns application information by app idx.
+// (GET /v2/applications/{application-id})
+func (v2 *Handlers) GetApplicationByID(ctx echo.Context, applicationID uint64) error {
+	appIdx := basics.AppIndex(applicationID)
+	myLedger := v2.Node.Ledger()
+	creator, ok, err := myLedger.GetCreator(basics.CreatableIndex(appIdx), basics.AppCreatable)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+	if !ok {
+		return notFound(ctx, errors.New(errAppDoesNotExist), errAppDoesNotExist, v2.Log)
+	}
+
+	record, err := myLedger.LookupWithoutRewards(myLedger.Latest(), creator)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+
+	appParams, ok := record.AppParams[appIdx]
+	if !ok {
+		return notFound(ctx, errors.New(errAppDoesNotExist), errAppDoesNotExist, v2.Log)
+	}
+
+	response := generated.ApplicationResponse(appParamsToApplication(creator.String(), appIdx, &appParams))
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetAssetByID returns asset information by asset idx.
+// (GET /v2/assets/{asset-id})
+func (v2 *Handlers) GetAssetByID(ctx echo.Context, assetID uint64) error {
+	assetIdx := basics.AssetIndex(assetID)
+	myLedger := v2.Node.Ledger()
+	creator, ok, err := myLedger.GetCreator(basics.CreatableIndex(assetIdx), basics.AssetCreatable)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+	if !ok {
+		return notFound(ctx, errors.New(errAssetDoesNotExist), errAssetDoesNotExist, v2.Log)
+	}
+
+	record, err := myLedger.LookupWithoutRewards(myLedger.Latest(), creator)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+
+	assetParams, ok := record.AssetParams[assetIdx]
+	if !ok {
+		return notFound(ctx, errors.New(errAssetDoesNotExist), errAssetDoesNotExist, v2.Log)
+	}
+
+	response := generated.AssetResponse(assetParamsToAsset(creator.String(), assetIdx, &assetParams))
 	return ctx.JSON(http.StatusOK, response)
 }
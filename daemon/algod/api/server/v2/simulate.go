@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// SimulateRequest is the internal form of generated.SimulateRequest.
+type SimulateRequest struct {
+	// TxnGroups are the transaction groups to evaluate, one after the other
+	TxnGroups []SimulateRequestTransactionGroup `codec:"txn-groups"`
+
+	// SkipSignatures lets the transactions be evaluated without being signed
+	SkipSignatures bool `codec:"skip-signatures"`
+}
+
+// SimulateRequestTransactionGroup is the internal form of
+// generated.SimulateRequestTransactionGroup.
+type SimulateRequestTransactionGroup struct {
+	Txns []transactions.SignedTxn `codec:"txns"`
+}
+
+// DecodeSimulateRequest decodes a SimulateRequest encoded either as JSON or as
+// msgpack.
+func DecodeSimulateRequest(data []byte) (sr SimulateRequest, err error) {
+	err = protocol.DecodeJSON(data, &sr)
+	if err == nil {
+		return
+	}
+	jsonErr := err
+	sr = SimulateRequest{}
+	err = protocol.DecodeReflect(data, &sr)
+	if err != nil {
+		err = fmt.Errorf("could not decode simulate request as json (%v) or msgpack (%v)", jsonErr, err)
+	}
+	return
+}
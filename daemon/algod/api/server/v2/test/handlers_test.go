@@ -18,6 +18,7 @@ package test
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -26,9 +27,15 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
 	v2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2"
 	generatedV2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
+	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated/private"
 	"github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
@@ -59,13 +66,13 @@ func TestSimpleMockBuilding(t *testing.T) {
 	require.Equal(t, t.Name(), handler.Node.GenesisID())
 }
 
-func accountInformationTest(t *testing.T, address string, expectedCode int) {
+func accountInformationTest(t *testing.T, address string, round *uint64, expectedCode int) {
 	handler, c, rec, _, _, releasefunc := setupTestForMethodGet(t)
 	defer releasefunc()
-	err := handler.AccountInformation(c, address)
+	err := handler.AccountInformation(c, address, generatedV2.AccountInformationParams{Round: round})
 	require.NoError(t, err)
 	require.Equal(t, expectedCode, rec.Code)
-	if address == poolAddr.String() {
+	if address == poolAddr.String() && expectedCode == 200 {
 		expectedResponse := poolAddrResponseGolden
 		actualResponse := generatedV2.AccountResponse{}
 		err = protocol.DecodeJSON(rec.Body.Bytes(), &actualResponse)
@@ -75,8 +82,115 @@ func accountInformationTest(t *testing.T, address string, expectedCode int) {
 }
 
 func TestAccountInformation(t *testing.T) {
-	accountInformationTest(t, poolAddr.String(), 200)
-	accountInformationTest(t, "bad account", 400)
+	accountInformationTest(t, poolAddr.String(), nil, 200)
+	accountInformationTest(t, "bad account", nil, 400)
+	round := uint64(0)
+	accountInformationTest(t, poolAddr.String(), &round, 200)
+	round = 1
+	accountInformationTest(t, poolAddr.String(), &round, 400)
+}
+
+func accountApplicationInformationTest(t *testing.T, rootkeyToUse int, appID uint64, expectedCode int) {
+	handler, c, rec, rootkeys, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+	err := handler.AccountApplicationInformation(c, rootkeys[rootkeyToUse].Address().String(), appID)
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, rec.Code)
+	if expectedCode == 200 {
+		response := generatedV2.AccountApplicationResponse{}
+		err = protocol.DecodeJSON(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Equal(t, appID, response.Id)
+		require.Equal(t, uint64(1), response.Schema.NumUint)
+		require.NotNil(t, response.KeyValue)
+		require.Len(t, *response.KeyValue, 1)
+	}
+}
+
+func TestAccountApplicationInformation(t *testing.T) {
+	accountApplicationInformationTest(t, 0, testAppID, 200)
+	accountApplicationInformationTest(t, 0, testAppID+100, 404)
+}
+
+func getApplicationByIDTest(t *testing.T, appID uint64, expectedCode int) {
+	handler, c, rec, rootkeys, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+	err := handler.GetApplicationByID(c, appID)
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, rec.Code)
+	if expectedCode == 200 {
+		response := generatedV2.ApplicationResponse{}
+		err = protocol.DecodeJSON(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Equal(t, appID, response.Id)
+		require.Equal(t, rootkeys[0].Address().String(), response.Params.Creator)
+		require.NotEmpty(t, response.Params.ApprovalProgram)
+		require.NotNil(t, response.Params.GlobalState)
+		require.Len(t, *response.Params.GlobalState, 1)
+	}
+}
+
+func TestGetApplicationByID(t *testing.T) {
+	getApplicationByIDTest(t, testAppID, 200)
+	getApplicationByIDTest(t, testAssetID, 404)
+	getApplicationByIDTest(t, testAppID+100, 404)
+}
+
+func getApplicationsTest(t *testing.T, limit *uint64, next *string, expectedCode int, expectedIDs []uint64, expectedNext *string) {
+	handler, c, rec, rootkeys, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+	err := handler.GetApplications(c, generatedV2.GetApplicationsParams{Limit: limit, Next: next})
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, rec.Code)
+	if expectedCode == 200 {
+		response := generatedV2.ApplicationsResponse{}
+		err = protocol.DecodeJSON(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		ids := []uint64{}
+		for _, app := range response.Applications {
+			require.Equal(t, rootkeys[0].Address().String(), app.Params.Creator)
+			ids = append(ids, app.Id)
+		}
+		require.Equal(t, expectedIDs, ids)
+		require.Equal(t, expectedNext, response.NextToken)
+	}
+}
+
+func TestGetApplications(t *testing.T) {
+	one := uint64(1)
+	tooMany := uint64(101)
+	zero := uint64(0)
+	nextToken := fmt.Sprint(testAppID - 1)
+	notANumber := "a"
+	// every case runs in a subtest, so that it has a ledger of its own.
+	t.Run("all", func(t *testing.T) { getApplicationsTest(t, nil, nil, 200, []uint64{testAppID}, nil) })
+	t.Run("limit", func(t *testing.T) { getApplicationsTest(t, &one, nil, 200, []uint64{testAppID}, &nextToken) })
+	t.Run("next", func(t *testing.T) { getApplicationsTest(t, &one, &nextToken, 200, []uint64{}, nil) })
+	t.Run("zeroLimit", func(t *testing.T) { getApplicationsTest(t, &zero, nil, 400, nil, nil) })
+	t.Run("largeLimit", func(t *testing.T) { getApplicationsTest(t, &tooMany, nil, 400, nil, nil) })
+	t.Run("badNext", func(t *testing.T) { getApplicationsTest(t, nil, &notANumber, 400, nil, nil) })
+}
+
+func getAssetByIDTest(t *testing.T, assetID uint64, expectedCode int) {
+	handler, c, rec, rootkeys, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+	err := handler.GetAssetByID(c, assetID)
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, rec.Code)
+	if expectedCode == 200 {
+		response := generatedV2.AssetResponse{}
+		err = protocol.DecodeJSON(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Equal(t, assetID, response.Index)
+		require.Equal(t, rootkeys[0].Address().String(), response.Params.Creator)
+		require.Equal(t, uint64(100), response.Params.Total)
+	}
+}
+
+func TestGetAssetByID(t *testing.T) {
+	getAssetByIDTest(t, testAssetID, 200)
+	getAssetByIDTest(t, testAppID, 404)
+	getAssetByIDTest(t, testAssetID+100, 404)
 }
 
 func getBlockTest(t *testing.T, blockNum uint64, format string, expectedCode int) {
@@ -94,6 +208,83 @@ func TestGetBlock(t *testing.T) {
 	getBlockTest(t, 0, "bad format", 400)
 }
 
+func getBlockDeltaTest(t *testing.T, blockNum uint64, format string, expectedCode int) {
+	handler, c, rec, _, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+	err := handler.GetBlockDelta(c, blockNum, generatedV2.GetBlockDeltaParams{Format: &format})
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, rec.Code)
+}
+
+func TestGetBlockDelta(t *testing.T) {
+	// the genesis round has no delta
+	getBlockDeltaTest(t, 0, "json", 404)
+	getBlockDeltaTest(t, 0, "bad format", 400)
+}
+
+func TestGetBlockDeltaCommittedRound(t *testing.T) {
+	handler, c, rec, rootkeys, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+
+	// commit round 1, in which the first account pays a new one
+	l := handler.Node.Ledger()
+	genesisHdr, err := l.BlockHdr(0)
+	require.NoError(t, err)
+	proto := config.Consensus[genesisHdr.CurrentProtocol]
+	blk := bookkeeping.MakeBlock(genesisHdr)
+	eval, err := l.StartEvaluator(blk.BlockHeader, 0)
+	require.NoError(t, err)
+	sender := rootkeys[0].Address()
+	receiver := basics.Address{0x01}
+	txn := transactions.Transaction{
+		Type: protocol.PaymentTx,
+		Header: transactions.Header{
+			Sender:      sender,
+			Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+			FirstValid:  blk.Round(),
+			LastValid:   blk.Round(),
+			GenesisHash: genesisHdr.GenesisHash,
+		},
+		PaymentTxnFields: transactions.PaymentTxnFields{
+			Receiver: receiver,
+			Amount:   basics.MicroAlgos{Raw: proto.MinBalance},
+		},
+	}
+	err = eval.Transaction(txn.Sign(rootkeys[0].Secrets()), transactions.ApplyData{})
+	require.NoError(t, err)
+	vb, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	err = l.AddValidatedBlock(*vb, agreement.Certificate{})
+	require.NoError(t, err)
+
+	format := "json"
+	err = handler.GetBlockDelta(c, 1, generatedV2.GetBlockDeltaParams{Format: &format})
+	require.NoError(t, err)
+	require.Equal(t, 200, rec.Code)
+
+	var response struct {
+		Block bookkeeping.Block `codec:"block"`
+		Delta struct {
+			Accounts []struct {
+				Address string             `codec:"address"`
+				Account basics.AccountData `codec:"account"`
+			} `codec:"accounts"`
+		} `codec:"delta"`
+	}
+	err = protocol.DecodeJSON(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Equal(t, basics.Round(1), response.Block.Round())
+	require.Equal(t, 1, len(response.Block.Payset))
+
+	accounts := make(map[string]basics.AccountData)
+	for _, acct := range response.Delta.Accounts {
+		accounts[acct.Address] = acct.Account
+	}
+	require.Contains(t, accounts, sender.String())
+	require.Contains(t, accounts, receiver.String())
+	require.Equal(t, proto.MinBalance, accounts[receiver.String()].MicroAlgos.Raw)
+}
+
 func TestGetSupply(t *testing.T) {
 	handler, c, _, _, _, releasefunc := setupTestForMethodGet(t)
 	defer releasefunc()
@@ -107,6 +298,8 @@ func TestGetStatus(t *testing.T) {
 	err := handler.GetStatus(c)
 	require.NoError(t, err)
 	stat := cannedStatusReportGolden
+	earliestBlockRound := uint64(stat.EarliestBlockRound)
+	blockPruneTargetRound := uint64(stat.BlockPruneTargetRound)
 	expectedResult := generatedV2.NodeStatusResponse{
 		LastRound:                   uint64(stat.LastRound),
 		LastVersion:                 string(stat.LastVersion),
@@ -122,6 +315,8 @@ func TestGetStatus(t *testing.T) {
 		CatchpointProcessedAccounts: &stat.CatchpointCatchupProcessedAccounts,
 		CatchpointTotalBlocks:       &stat.CatchpointCatchupTotalBlocks,
 		CatchpointAcquiredBlocks:    &stat.CatchpointCatchupAcquiredBlocks,
+		EarliestBlockRound:          &earliestBlockRound,
+		BlockPruneTargetRound:       &blockPruneTargetRound,
 	}
 	actualResult := generatedV2.NodeStatusResponse{}
 	err = protocol.DecodeJSON(rec.Body.Bytes(), &actualResult)
@@ -139,6 +334,23 @@ func TestGetStatusAfterBlock(t *testing.T) {
 	require.Equal(t, 400, rec.Code)
 }
 
+func TestGetPeerScores(t *testing.T) {
+	handler, c, rec, _, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+	err := handler.GetPeerScores(c)
+	require.NoError(t, err)
+	require.Equal(t, 200, rec.Code)
+	bannedUntil := uint64(1600000000)
+	expectedResult := private.PeerScoresResponse{Peers: []private.PeerScore{
+		{Peer: "1.2.3.4", Score: 120, BannedUntil: &bannedUntil},
+		{Peer: "5.6.7.8", Score: 30},
+	}}
+	actualResult := private.PeerScoresResponse{}
+	err = protocol.DecodeJSON(rec.Body.Bytes(), &actualResult)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, actualResult)
+}
+
 func TestGetTransactionParams(t *testing.T) {
 	handler, c, rec, _, _, releasefunc := setupTestForMethodGet(t)
 	defer releasefunc()
@@ -235,6 +447,73 @@ func TestPostTransaction(t *testing.T) {
 	postTransactionTest(t, 0, 200)
 }
 
+func simulateTransactionTest(t *testing.T, bytesToUse []byte, format string, expectedCode int) (response generatedV2.SimulateResponse) {
+	handler, _, _, _, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(bytesToUse))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	err := handler.SimulateTransaction(c, generatedV2.SimulateTransactionParams{Format: &format})
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, rec.Code)
+	if rec.Code == http.StatusOK && format == "json" {
+		err = protocol.DecodeJSON(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+	}
+	return
+}
+
+func TestSimulateTransaction(t *testing.T) {
+	_, _, _, stxns, releasefunc := testingenv(t, 1, 1, true)
+	releasefunc()
+	signed := stxns[0]
+	unsigned := signed
+	unsigned.Sig = crypto.Signature{}
+
+	sr := v2.SimulateRequest{
+		TxnGroups: []v2.SimulateRequestTransactionGroup{
+			{Txns: []transactions.SignedTxn{signed}},
+			{Txns: []transactions.SignedTxn{unsigned}},
+		},
+	}
+	for _, encoded := range [][]byte{protocol.EncodeJSON(&sr), protocol.EncodeReflect(&sr)} {
+		response := simulateTransactionTest(t, encoded, "json", 200)
+		require.Equal(t, uint64(1), response.LastRound)
+		require.False(t, response.WouldSucceed)
+		require.Len(t, response.TxnGroups, 2)
+		require.Nil(t, response.TxnGroups[0].FailureMessage)
+		require.Nil(t, response.TxnGroups[0].FailedAt)
+		require.NotNil(t, response.TxnGroups[0].TxnResults[0].ApplyData)
+		require.Len(t, response.TxnGroups[1].TxnResults, 1)
+		require.NotNil(t, response.TxnGroups[1].FailureMessage)
+		require.Equal(t, uint64(0), *response.TxnGroups[1].FailedAt)
+		require.NotEmpty(t, response.Delta["accounts"])
+	}
+	simulateTransactionTest(t, protocol.EncodeJSON(&sr), "msgpack", 200)
+
+	// the unsigned transaction is the signed one, so it is only applied on its own.
+	sr.TxnGroups = sr.TxnGroups[1:]
+	sr.SkipSignatures = true
+	response := simulateTransactionTest(t, protocol.EncodeJSON(&sr), "json", 200)
+	require.True(t, response.WouldSucceed)
+
+	simulateTransactionTest(t, protocol.EncodeJSON(&sr), "bad format", 400)
+	simulateTransactionTest(t, []byte("bad request"), "json", 400)
+	simulateTransactionTest(t, protocol.EncodeJSON(&v2.SimulateRequest{}), "json", 400)
+
+	// the requests over 1MB are rejected before they are decoded.
+	handler, _, _, _, _, releasefunc := setupTestForMethodGet(t)
+	defer releasefunc()
+	format := "json"
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(make([]byte, 1e6+1))), rec)
+	err := handler.SimulateTransaction(c, generatedV2.SimulateTransactionParams{Format: &format})
+	require.NoError(t, err)
+	require.Equal(t, 400, rec.Code)
+	require.Contains(t, rec.Body.String(), "too large")
+}
+
 func startCatchupTest(t *testing.T, catchpoint string, expectedCode int) {
 	numAccounts := 1
 	numTransactions := 1
@@ -293,7 +572,7 @@ func TestAbortCatchup(t *testing.T) {
 	abortCatchupTest(t, badCatchPoint, 400)
 }
 
-func tealCompileTest(t *testing.T, bytesToUse []byte, expectedCode int, enableDeveloperAPI bool) {
+func tealCompileTest(t *testing.T, bytesToUse []byte, expectedCode int, enableDeveloperAPI bool, params generatedV2.TealCompileParams) (response generatedV2.PostCompileResponse) {
 	numAccounts := 1
 	numTransactions := 1
 	offlineAccounts := true
@@ -311,18 +590,84 @@ func tealCompileTest(t *testing.T, bytesToUse []byte, expectedCode int, enableDe
 	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(bytesToUse))
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	err := handler.TealCompile(c)
+	err := handler.TealCompile(c, params)
 	require.NoError(t, err)
 	require.Equal(t, expectedCode, rec.Code)
+	if rec.Code == http.StatusOK {
+		err = protocol.DecodeJSON(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+	}
+	return
 }
 
 func TestTealCompile(t *testing.T) {
-	tealCompileTest(t, nil, 200, true) // nil program should work
+	noParams := generatedV2.TealCompileParams{}
+	tealCompileTest(t, nil, 200, true, noParams) // nil program should work
 	goodProgram := `int 1`
 	goodProgramBytes := []byte(goodProgram)
-	tealCompileTest(t, goodProgramBytes, 200, true)
-	tealCompileTest(t, goodProgramBytes, 404, false)
+	response := tealCompileTest(t, goodProgramBytes, 200, true, noParams)
+	require.Nil(t, response.Sourcemap)
+	tealCompileTest(t, goodProgramBytes, 404, false, noParams)
 	badProgram := "bad program"
 	badProgramBytes := []byte(badProgram)
-	tealCompileTest(t, badProgramBytes, 400, true)
+	tealCompileTest(t, badProgramBytes, 400, true, noParams)
+
+	withSourceMap := true
+	response = tealCompileTest(t, goodProgramBytes, 200, true, generatedV2.TealCompileParams{Sourcemap: &withSourceMap})
+	require.NotNil(t, response.Sourcemap)
+	// version and intcblock 1 take 4 bytes, then the intc_0 of the first line
+	require.Equal(t, ";;;;AAAA", (*response.Sourcemap)["mappings"])
+	require.EqualValues(t, 3, (*response.Sourcemap)["version"])
+}
+
+func tealDryrunTest(t *testing.T, bytesToUse []byte, expectedCode int, enableDeveloperAPI bool) (response generatedV2.DryrunResponse) {
+	numAccounts := 1
+	numTransactions := 1
+	offlineAccounts := true
+	mockLedger, _, _, _, releasefunc := testingenv(t, numAccounts, numTransactions, offlineAccounts)
+	defer releasefunc()
+	dummyShutdownChan := make(chan struct{})
+	mockNode := makeMockNode(mockLedger, t.Name())
+	mockNode.config.EnableDeveloperAPI = enableDeveloperAPI
+	handler := v2.Handlers{
+		Node:     &mockNode,
+		Log:      logging.Base(),
+		Shutdown: dummyShutdownChan,
+	}
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(bytesToUse))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	err := handler.TealDryrun(c)
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, rec.Code)
+	if rec.Code == http.StatusOK {
+		err = protocol.DecodeJSON(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+	}
+	return
+}
+
+func TestTealDryrun(t *testing.T) {
+	dr := v2.DryrunRequest{
+		Txns: []transactions.SignedTxn{{}},
+		Sources: []generatedV2.DryrunSource{
+			{FieldName: "lsig", Source: "int 1", TxnIndex: 0},
+		},
+		ProtocolVersion: string(protocol.ConsensusFuture),
+	}
+	for _, encoded := range [][]byte{protocol.EncodeJSON(&dr), protocol.EncodeReflect(&dr)} {
+		response := tealDryrunTest(t, encoded, 200, true)
+		require.Empty(t, response.Error)
+		require.Equal(t, dr.ProtocolVersion, response.ProtocolVersion)
+		require.Len(t, response.Txns, 1)
+		messages := *response.Txns[0].LogicSigMessages
+		require.Equal(t, "PASS", messages[len(messages)-1])
+	}
+
+	tealDryrunTest(t, protocol.EncodeJSON(&dr), 404, false)
+	tealDryrunTest(t, []byte("bad request"), 400, true)
+
+	dr.ProtocolVersion = "unknown-version"
+	tealDryrunTest(t, protocol.EncodeJSON(&dr), 400, true)
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/network"
 	"github.com/algorand/go-algorand/node"
 	"github.com/algorand/go-algorand/node/indexer"
 	"github.com/algorand/go-algorand/protocol"
@@ -53,17 +54,25 @@ var cannedStatusReportGolden = node.StatusReport{
 	CatchpointCatchupTotalAccounts:     0,
 	CatchpointCatchupTotalBlocks:       0,
 	LastCatchpoint:                     "",
+	EarliestBlockRound:                 basics.Round(0),
+	BlockPruneTargetRound:              basics.Round(0),
 }
 
 var poolAddrRewardBaseGolden = uint64(0)
 var poolAddrAssetsGolden = make([]generatedV2.AssetHolding, 0)
 var poolAddrCreatedAssetsGolden = make([]generatedV2.Asset, 0)
+var poolAddrCreatedAppsGolden = make([]generatedV2.Application, 0)
+var poolAddrAppsLocalStateGolden = make([]generatedV2.ApplicationLocalState, 0)
+var poolAddrAppsTotalSchemaGolden = generatedV2.ApplicationStateSchema{}
 var poolAddrResponseGolden = generatedV2.AccountResponse{
 	Address:                     poolAddr.String(),
 	Amount:                      50000000000,
 	AmountWithoutPendingRewards: 50000000000,
 	Assets:                      &poolAddrAssetsGolden,
 	CreatedAssets:               &poolAddrCreatedAssetsGolden,
+	CreatedApps:                 &poolAddrCreatedAppsGolden,
+	AppsLocalState:              &poolAddrAppsLocalStateGolden,
+	AppsTotalSchema:             &poolAddrAppsTotalSchemaGolden,
 	RewardBase:                  &poolAddrRewardBaseGolden,
 	Status:                      "Not Participating",
 }
@@ -169,6 +178,10 @@ func (m mockNode) AbortCatchup(catchpoint string) error {
 	return nil
 }
 
+func (m mockNode) PeerScores() []network.PeerScore {
+	return []network.PeerScore{{Peer: "1.2.3.4", Score: 120, BannedUntil: time.Unix(1600000000, 0)}, {Peer: "5.6.7.8", Score: 30}}
+}
+
 ////// mock ledger testing environment follows
 
 var sinkAddr = basics.Address{0x7, 0xda, 0xcb, 0x4b, 0x6d, 0x9e, 0xd1, 0x41, 0xb1, 0x75, 0x76, 0xbd, 0x45, 0x9a, 0xe6, 0x42, 0x1d, 0x48, 0x6d, 0xa3, 0xd4, 0xef, 0x22, 0x47, 0xc4, 0x9, 0xa3, 0x96, 0xb8, 0x2e, 0xa2, 0x21}
@@ -176,6 +189,10 @@ var poolAddr = basics.Address{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x
 var genesisHash = crypto.Digest{0xff, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}
 var genesisID = "testingid"
 
+// testAssetID and testAppID are created in genesis by the first test account
+const testAssetID = 1
+const testAppID = 2
+
 var proto = config.Consensus[protocol.ConsensusCurrentVersion]
 
 func testingenv(t testing.TB, numAccounts, numTxs int, offlineAccounts bool) (*data.Ledger, []account.Root, []account.Participation, []transactions.SignedTxn, func()) {
@@ -240,7 +257,26 @@ func testingenv(t testing.TB, numAccounts, numTxs int, offlineAccounts bool) (*d
 		}
 	}
 
+	// the first account creates an asset and an application, and opts into the latter
+	creator := genesis[roots[0].Address()]
+	creator.AssetParams = map[basics.AssetIndex]basics.AssetParams{testAssetID: {Total: 100, UnitName: "tst"}}
+	creator.AppParams = map[basics.AppIndex]basics.AppParams{testAppID: {
+		ApprovalProgram:   []byte{0x02, 0x20, 0x01, 0x01, 0x22},
+		ClearStateProgram: []byte{0x02, 0x20, 0x01, 0x01, 0x22},
+		GlobalState:       basics.TealKeyValue{"k": basics.TealValue{Type: basics.TealUintType, Uint: 1}},
+		LocalStateSchema:  basics.StateSchema{NumUint: 1},
+		GlobalStateSchema: basics.StateSchema{NumUint: 1},
+	}}
+	creator.TotalAppSchema = basics.StateSchema{NumUint: 2}
+	creator.AppLocalStates = map[basics.AppIndex]basics.AppLocalState{testAppID: {
+		Schema:   basics.StateSchema{NumUint: 1},
+		KeyValue: basics.TealKeyValue{"l": basics.TealValue{Type: basics.TealUintType, Uint: 2}},
+	}}
+	genesis[roots[0].Address()] = creator
+
 	genesis[poolAddr] = basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: 100000 * uint64(proto.RewardsRateRefreshInterval)})
+	// the rewards pool is funded, so that blocks can be evaluated on top of the genesis round
+	genesis[sinkAddr] = basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: proto.MinBalance})
 
 	bootstrap := data.MakeGenesisBalances(genesis, poolAddr, sinkAddr)
 
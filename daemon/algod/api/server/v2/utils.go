@@ -17,6 +17,7 @@
 package v2
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	"github.com/algorand/go-codec/codec"
 	"github.com/labstack/echo/v4"
 
+	"github.com/algorand/go-algorand/daemon/algod/api/server/lib"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
 	"github.com/algorand/go-algorand/data"
 	"github.com/algorand/go-algorand/data/basics"
@@ -55,6 +57,10 @@ func notFound(ctx echo.Context, internal error, external string, log logging.Log
 	return returnError(ctx, http.StatusNotFound, internal, external, log)
 }
 
+func requestTimeout(ctx echo.Context, internal error, external string, log logging.Logger) error {
+	return returnError(ctx, http.StatusRequestTimeout, internal, external, log)
+}
+
 func addrOrNil(addr basics.Address) *string {
 	if addr.IsZero() {
 		return nil
@@ -84,26 +90,6 @@ func byteOrNil(data []byte) *[]byte {
 	return &data
 }
 
-func computeAssetIndexInPayset(tx node.TxnWithStatus, txnCounter uint64, payset []transactions.SignedTxnWithAD) (aidx *uint64) {
-	// Compute transaction index in block
-	offset := -1
-	for idx, stxnib := range payset {
-		if tx.Txn.Txn.ID() == stxnib.Txn.ID() {
-			offset = idx
-			break
-		}
-	}
-
-	// Sanity check that txn was in fetched block
-	if offset < 0 {
-		return nil
-	}
-
-	// Count into block to get created asset index
-	idx := txnCounter - uint64(len(payset)) + uint64(offset) + 1
-	return &idx
-}
-
 // computeAssetIndexFromTxn returns the created asset index given a confirmed
 // transaction whose confirmation block is available in the ledger. Note that
 // 0 is an invalid asset index (they start at 1).
@@ -136,7 +122,91 @@ func computeAssetIndexFromTxn(tx node.TxnWithStatus, l *data.Ledger) (aidx *uint
 		return nil
 	}
 
-	return computeAssetIndexInPayset(tx, blk.BlockHeader.TxnCounter, payset)
+	idx := lib.CreatableIndexInPayset(tx, blk.BlockHeader.TxnCounter, payset)
+	if idx == 0 {
+		return nil
+	}
+	return &idx
+}
+
+// convertTKVToGenerated converts a TEAL key/value store into its REST API
+// representation. Keys and byte values are base64 encoded.
+func convertTKVToGenerated(tkv *basics.TealKeyValue) *generated.TealKeyValueStore {
+	if tkv == nil || len(*tkv) == 0 {
+		return nil
+	}
+
+	converted := make(generated.TealKeyValueStore, 0, len(*tkv))
+	for k, v := range *tkv {
+		converted = append(converted, generated.TealKeyValue{
+			Key: base64.StdEncoding.EncodeToString([]byte(k)),
+			Value: generated.TealValue{
+				Type:  uint64(v.Type),
+				Bytes: base64.StdEncoding.EncodeToString([]byte(v.Bytes)),
+				Uint:  v.Uint,
+			},
+		})
+	}
+	return &converted
+}
+
+// appParamsToApplication converts the parameters of an application created by
+// the given account into its REST API representation.
+func appParamsToApplication(creator string, appIdx basics.AppIndex, appParams *basics.AppParams) generated.Application {
+	globalState := convertTKVToGenerated(&appParams.GlobalState)
+	return generated.Application{
+		Id: uint64(appIdx),
+		Params: generated.ApplicationParams{
+			Creator:           creator,
+			ApprovalProgram:   appParams.ApprovalProgram,
+			ClearStateProgram: appParams.ClearStateProgram,
+			GlobalState:       globalState,
+			LocalStateSchema: &generated.ApplicationStateSchema{
+				NumByteSlice: appParams.LocalStateSchema.NumByteSlice,
+				NumUint:      appParams.LocalStateSchema.NumUint,
+			},
+			GlobalStateSchema: &generated.ApplicationStateSchema{
+				NumByteSlice: appParams.GlobalStateSchema.NumByteSlice,
+				NumUint:      appParams.GlobalStateSchema.NumUint,
+			},
+		},
+	}
+}
+
+// assetParamsToAsset converts the parameters of an asset created by the
+// given account into its REST API representation.
+func assetParamsToAsset(creator string, idx basics.AssetIndex, params *basics.AssetParams) generated.Asset {
+	assetParams := generated.AssetParams{
+		Creator:       creator,
+		Total:         params.Total,
+		Decimals:      uint64(params.Decimals),
+		DefaultFrozen: &params.DefaultFrozen,
+		MetadataHash:  byteOrNil(params.MetadataHash[:]),
+		Name:          strOrNil(params.AssetName),
+		UnitName:      strOrNil(params.UnitName),
+		Url:           strOrNil(params.URL),
+		Clawback:      addrOrNil(params.Clawback),
+		Freeze:        addrOrNil(params.Freeze),
+		Manager:       addrOrNil(params.Manager),
+		Reserve:       addrOrNil(params.Reserve),
+	}
+	return generated.Asset{
+		Index:  uint64(idx),
+		Params: assetParams,
+	}
+}
+
+// appLocalStateToGenerated converts an account's local state for the given
+// application into its REST API representation.
+func appLocalStateToGenerated(appIdx basics.AppIndex, state *basics.AppLocalState) generated.ApplicationLocalState {
+	return generated.ApplicationLocalState{
+		Id:       uint64(appIdx),
+		KeyValue: convertTKVToGenerated(&state.KeyValue),
+		Schema: generated.ApplicationStateSchema{
+			NumByteSlice: state.Schema.NumByteSlice,
+			NumUint:      state.Schema.NumUint,
+		},
+	}
 }
 
 // getCodecHandle converts a format string into the encoder + content type
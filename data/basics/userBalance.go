@@ -17,6 +17,7 @@
 package basics
 
 import (
+	"encoding/binary"
 	"reflect"
 
 	"github.com/algorand/go-algorand/config"
@@ -259,6 +260,20 @@ type AssetIndex uint64
 // AppParams
 type AppIndex uint64
 
+// ToBeHashed implements crypto.Hashable
+func (app AppIndex) ToBeHashed() (protocol.HashID, []byte) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(app))
+	return protocol.AppIndex, buf[:]
+}
+
+// Address returns the address of the account controlled by the application.
+// No key corresponds to this address, so only the application itself may
+// send transactions from it, by issuing inner transactions.
+func (app AppIndex) Address() Address {
+	return Address(crypto.HashObj(app))
+}
+
 // CreatableIndex represents either an AssetIndex or AppIndex, which come from
 // the same namespace of indices as each other (both assets and apps are
 // "creatables")
@@ -67,6 +67,23 @@ func makeGenesisBlock(proto protocol.ConsensusVersion, genesisBal GenesisBalance
 		CurrentProtocol: proto,
 	}
 
+	// the assets and applications of the genesis accounts are counted as
+	// transactions, so that the IDs of the ones created later, which derive
+	// from the transaction counter, don't collide with theirs.
+	var txnCounter uint64
+	for _, data := range genesisBal.balances {
+		for aidx := range data.AssetParams {
+			if uint64(aidx) > txnCounter {
+				txnCounter = uint64(aidx)
+			}
+		}
+		for aidx := range data.AppParams {
+			if uint64(aidx) > txnCounter {
+				txnCounter = uint64(aidx)
+			}
+		}
+	}
+
 	blk := bookkeeping.Block{
 		BlockHeader: bookkeeping.BlockHeader{
 			Round:        0,
@@ -78,6 +95,7 @@ func makeGenesisBlock(proto protocol.ConsensusVersion, genesisBal GenesisBalance
 			RewardsState: genesisRewardsState,
 			UpgradeState: genesisProtoState,
 			UpgradeVote:  bookkeeping.UpgradeVote{},
+			TxnCounter:   txnCounter,
 		},
 	}
 
@@ -88,19 +106,15 @@ func makeGenesisBlock(proto protocol.ConsensusVersion, genesisBal GenesisBalance
 	return blk, nil
 }
 
-// LoadLedger creates a Ledger object to represent the ledger with the
-// specified database file prefix, initializing it if necessary.
-func LoadLedger(
-	log logging.Logger, dbFilenamePrefix string, memory bool,
-	genesisProto protocol.ConsensusVersion, genesisBal GenesisBalances, genesisID string, genesisHash crypto.Digest,
-	blockListeners []ledger.BlockListener, cfg config.Local,
-) (*Ledger, error) {
+// MakeGenesisInitState returns the initial state of the ledger of the given
+// genesis.
+func MakeGenesisInitState(genesisProto protocol.ConsensusVersion, genesisBal GenesisBalances, genesisID string, genesisHash crypto.Digest) (ledger.InitState, error) {
 	if genesisBal.balances == nil {
 		genesisBal.balances = make(map[basics.Address]basics.AccountData)
 	}
 	genBlock, err := makeGenesisBlock(genesisProto, genesisBal, genesisID, genesisHash)
 	if err != nil {
-		return nil, err
+		return ledger.InitState{}, err
 	}
 
 	params := config.Consensus[genesisProto]
@@ -111,13 +125,27 @@ func LoadLedger(
 		genesisBal.balances[sinkAddr] = sinkData
 	}
 
-	l := &Ledger{
-		log: log,
-	}
-	genesisInitState := ledger.InitState{
+	return ledger.InitState{
 		Block:       genBlock,
 		Accounts:    genesisBal.balances,
 		GenesisHash: genesisHash,
+	}, nil
+}
+
+// LoadLedger creates a Ledger object to represent the ledger with the
+// specified database file prefix, initializing it if necessary.
+func LoadLedger(
+	log logging.Logger, dbFilenamePrefix string, memory bool,
+	genesisProto protocol.ConsensusVersion, genesisBal GenesisBalances, genesisID string, genesisHash crypto.Digest,
+	blockListeners []ledger.BlockListener, cfg config.Local,
+) (*Ledger, error) {
+	genesisInitState, err := MakeGenesisInitState(genesisProto, genesisBal, genesisID, genesisHash)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Ledger{
+		log: log,
 	}
 	l.log.Debugf("Initializing Ledger(%s)", dbFilenamePrefix)
 
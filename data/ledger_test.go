@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestGenesisBlockTxnCounter(t *testing.T) {
+	balances := map[basics.Address]basics.AccountData{
+		poolAddr: basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: 1e12}),
+		sinkAddr: basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: 1e6}),
+	}
+	initState, err := MakeGenesisInitState(protocol.ConsensusCurrentVersion, MakeGenesisBalances(balances, sinkAddr, poolAddr), genesisID, genesisHash)
+	require.NoError(t, err)
+	require.Zero(t, initState.Block.TxnCounter)
+
+	// the creatables of the genesis accounts are counted.
+	var creator basics.Address
+	creator[0] = 1
+	data := basics.MakeAccountData(basics.Offline, basics.MicroAlgos{Raw: 1e6})
+	data.AssetParams = map[basics.AssetIndex]basics.AssetParams{5: {Total: 1}, 17: {Total: 1}}
+	data.AppParams = map[basics.AppIndex]basics.AppParams{12: {}}
+	balances[creator] = data
+	initState, err = MakeGenesisInitState(protocol.ConsensusCurrentVersion, MakeGenesisBalances(balances, sinkAddr, poolAddr), genesisID, genesisHash)
+	require.NoError(t, err)
+	require.Equal(t, uint64(17), initState.Block.TxnCounter)
+}
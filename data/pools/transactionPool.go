@@ -100,6 +100,7 @@ func MakeTransactionPool(ledger *ledger.Ledger, cfg config.Local) *TransactionPo
 type txPoolVerifyCacheVal struct {
 	txn    transactions.SignedTxn
 	params verify.Params
+	group  []transactions.SignedTxn
 }
 
 // TODO I moved this number to be a constant in the module, we should consider putting it in the local config
@@ -333,7 +334,7 @@ func (pool *TransactionPool) ingest(txgroup []transactions.SignedTxn, verifyPara
 	pool.rememberedTxGroups = append(pool.rememberedTxGroups, txgroup)
 	pool.rememberedVerifyParams = append(pool.rememberedVerifyParams, verifyParams)
 	for i, t := range txgroup {
-		pool.rememberedTxids[t.ID()] = txPoolVerifyCacheVal{txn: t, params: verifyParams[i]}
+		pool.rememberedTxids[t.ID()] = txPoolVerifyCacheVal{txn: t, params: verifyParams[i], group: txgroup}
 	}
 
 	return nil
@@ -387,6 +388,19 @@ func (pool *TransactionPool) Lookup(txid transactions.Txid) (tx transactions.Sig
 	return pool.statusCache.check(txid)
 }
 
+// LookupGroup returns the pending transaction group that contains the
+// transaction with the given txid, if it is still in the pool.
+func (pool *TransactionPool) LookupGroup(txid transactions.Txid) (txgroup []transactions.SignedTxn, found bool) {
+	if pool == nil {
+		return nil, false
+	}
+	pool.pendingMu.RLock()
+	defer pool.pendingMu.RUnlock()
+
+	cacheval, inPool := pool.pendingTxids[txid]
+	return cacheval.group, inPool
+}
+
 // Verified returns whether a given SignedTxn is already in the
 // pool, and, since only verified transactions should be added
 // to the pool, whether that transaction is verified (i.e., Verify
@@ -541,6 +541,12 @@ func TestRememberForget(t *testing.T) {
 	pending := transactionPool.Pending()
 	numberOfTxns := numOfAccounts*numOfAccounts - numOfAccounts
 	require.Len(t, pending, numberOfTxns)
+	for _, txgroup := range pending {
+		group, found := transactionPool.LookupGroup(txgroup[0].ID())
+		require.True(t, found)
+		require.Equal(t, txgroup, group)
+	}
+	txid := pending[0][0].ID()
 
 	blk, err := eval.GenerateBlock()
 	require.NoError(t, err)
@@ -551,6 +557,8 @@ func TestRememberForget(t *testing.T) {
 
 	pending = transactionPool.Pending()
 	require.Len(t, pending, 0)
+	_, found := transactionPool.LookupGroup(txid)
+	require.False(t, found)
 }
 
 //	Test that clean up works
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
 )
 
 const (
@@ -517,8 +518,11 @@ func (ac *ApplicationCallTxnFields) applyClearState(
 		// Execute the ClearStateProgram before we've deleted the LocalState
 		// for this account. If the ClearStateProgram does not fail, apply any
 		// state deltas it generated.
-		pass, evalDelta, err := steva.Eval(params.ClearStateProgram)
-		if err == nil && pass {
+		// The ClearStateProgram may not issue inner transactions, since
+		// its failures are ignored: a program that issues some is treated
+		// as rejected, so none of them are applied
+		pass, evalDelta, innerTxns, err := steva.Eval(params.ClearStateProgram)
+		if err == nil && pass && len(innerTxns) == 0 {
 			// Program execution may produce some GlobalState and LocalState
 			// deltas. Apply them, provided they don't exceed the bounds set by
 			// the GlobalStateSchema and LocalStateSchema. If they do exceed
@@ -534,7 +538,8 @@ func (ac *ApplicationCallTxnFields) applyClearState(
 			// stateful TEAL interpreter to apply state changes
 			ad.EvalDelta = evalDelta
 		} else {
-			// Ignore errors and rejections from the ClearStateProgram
+			// Ignore errors and rejections from the ClearStateProgram,
+			// along with the inner transactions it issued
 		}
 
 		// Fetch the (potentially updated) sender record
@@ -592,12 +597,50 @@ func applyOptIn(balances Balances, sender basics.Address, appIdx basics.AppIndex
 	return balances.Put(record)
 }
 
+// applyInnerTxns applies the inner transactions issued by an application's
+// ApprovalProgram, in order, and records each of them along with its
+// ApplyData. Inner transactions are sent from the application's account and
+// are given transaction counters following the application call's, so that
+// any assets they create receive distinct indices.
+func applyInnerTxns(innerTxns []SignedTxn, appIdx basics.AppIndex, balances Balances, spec SpecialAddresses, ad *ApplyData, txnCounter uint64) error {
+	proto := balances.ConsensusParams()
+	if len(innerTxns) > proto.MaxInnerTransactions {
+		return fmt.Errorf("too many inner transactions %d > %d", len(innerTxns), proto.MaxInnerTransactions)
+	}
+
+	appAddr := appIdx.Address()
+	for i, stxn := range innerTxns {
+		switch stxn.Txn.Type {
+		case protocol.PaymentTx, protocol.AssetTransferTx, protocol.AssetConfigTx:
+		default:
+			return fmt.Errorf("inner transaction %d: type %v not allowed", i, stxn.Txn.Type)
+		}
+
+		if stxn.Txn.Sender != appAddr {
+			return fmt.Errorf("inner transaction %d: sender %v is not the application account %v", i, stxn.Txn.Sender, appAddr)
+		}
+
+		err := stxn.Txn.WellFormed(spec, proto)
+		if err != nil {
+			return fmt.Errorf("inner transaction %d: %v", i, err)
+		}
+
+		innerAd, err := stxn.Txn.Apply(balances, nil, spec, txnCounter+uint64(i)+1)
+		if err != nil {
+			return fmt.Errorf("inner transaction %d: %v", i, err)
+		}
+		ad.InnerTxns = append(ad.InnerTxns, SignedTxnWithAD{SignedTxn: stxn, ApplyData: innerAd})
+	}
+	return nil
+}
+
 func (ac *ApplicationCallTxnFields) apply(header Header, balances Balances, spec SpecialAddresses, ad *ApplyData, txnCounter uint64, steva StateEvaluator) (err error) {
 	defer func() {
 		// If we are returning a non-nil error, then don't return a
 		// non-empty EvalDelta. Not required for correctness.
 		if err != nil && ad != nil {
 			ad.EvalDelta = basics.EvalDelta{}
+			ad.InnerTxns = nil
 		}
 	}()
 
@@ -673,7 +716,7 @@ func (ac *ApplicationCallTxnFields) apply(header Header, balances Balances, spec
 	}
 
 	// Execute the Approval program
-	approved, evalDelta, err := steva.Eval(params.ApprovalProgram)
+	approved, evalDelta, innerTxns, err := steva.Eval(params.ApprovalProgram)
 	if err != nil {
 		return err
 	}
@@ -783,6 +826,13 @@ func (ac *ApplicationCallTxnFields) apply(header Header, balances Balances, spec
 		return fmt.Errorf("invalid application action")
 	}
 
+	// Apply the inner transactions issued by the ApprovalProgram from the
+	// application's account
+	err = applyInnerTxns(innerTxns, appIdx, balances, spec, ad, txnCounter)
+	if err != nil {
+		return err
+	}
+
 	// Fill in applyData, so that consumers don't have to implement a
 	// stateful TEAL interpreter to apply state changes
 	ad.EvalDelta = evalDelta
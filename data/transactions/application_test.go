@@ -213,6 +213,7 @@ func (b *testBalances) SetProto(name protocol.ConsensusVersion) {
 type testEvaluator struct {
 	pass  bool
 	delta basics.EvalDelta
+	inner []SignedTxn
 
 	acctWhitelist      []basics.Address
 	appGlobalWhitelist []basics.AppIndex
@@ -220,11 +221,11 @@ type testEvaluator struct {
 }
 
 // Eval for tests that fail on program version > 10 and returns pass/delta from its own state rather than running the program
-func (e *testEvaluator) Eval(program []byte) (pass bool, stateDelta basics.EvalDelta, err error) {
+func (e *testEvaluator) Eval(program []byte) (pass bool, stateDelta basics.EvalDelta, innerTxns []SignedTxn, err error) {
 	if len(program) < 1 || program[0] > 10 {
-		return false, basics.EvalDelta{}, fmt.Errorf("mock eval error")
+		return false, basics.EvalDelta{}, nil, fmt.Errorf("mock eval error")
 	}
-	return e.pass, e.delta, nil
+	return e.pass, e.delta, e.inner, nil
 }
 
 // Check for tests that fail on program version > 10 and returns program len as cost
@@ -1093,6 +1094,19 @@ func TestAppCallClearState(t *testing.T) {
 	a.Equal(0, len(br.AppLocalStates))
 	a.Equal(basics.StateSchema{}, br.TotalAppSchema)
 	a.Equal(basics.EvalDelta{GlobalDelta: gd}, ad.EvalDelta)
+
+	b.ResetWrites()
+
+	// check existing application with a ClearStateProgram issuing inner transactions
+	ad = &ApplyData{}
+	steva.inner = []SignedTxn{{Txn: Transaction{Type: protocol.PaymentTx}}}
+	err = ac.applyClearState(&b, sender, appIdx, ad, &steva)
+	a.NoError(err)
+	a.Equal(1, b.put)
+	br = b.putBalances[sender]
+	a.Equal(0, len(br.AppLocalStates))
+	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Empty(ad.InnerTxns)
 }
 
 func TestAppCallApplyCloseOut(t *testing.T) {
@@ -1301,6 +1315,103 @@ func TestAppCallApplyDelete(t *testing.T) {
 	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
 }
 
+func TestAppCallApplyInnerTxns(t *testing.T) {
+	a := require.New(t)
+
+	creator := getRandomAddress(a)
+	sender := getRandomAddress(a)
+	receiver := getRandomAddress(a)
+	var txnCounter uint64 = 1
+	appIdx := basics.AppIndex(txnCounter + 1)
+
+	ac := ApplicationCallTxnFields{
+		ApplicationID: appIdx,
+		OnCompletion:  NoOpOC,
+	}
+	params := basics.AppParams{
+		ApprovalProgram: []byte{1},
+	}
+	h := Header{
+		Sender: sender,
+	}
+	var steva testEvaluator
+	var spec SpecialAddresses
+	var ad *ApplyData = &ApplyData{}
+	var b testBalances
+
+	b.balances = make(map[basics.Address]basics.AccountData)
+	b.balances[creator] = basics.AccountData{
+		AppParams: map[basics.AppIndex]basics.AppParams{appIdx: params},
+	}
+	b.appCreators = map[basics.AppIndex]basics.Address{appIdx: creator}
+
+	b.SetProto(protocol.ConsensusFuture)
+	proto := b.ConsensusParams()
+
+	pay := SignedTxn{
+		Txn: Transaction{
+			Type: protocol.PaymentTx,
+			Header: Header{
+				Sender:     appIdx.Address(),
+				Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid: 1,
+				LastValid:  100,
+			},
+			PaymentTxnFields: PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: 1000},
+			},
+		},
+	}
+
+	steva.pass = true
+	steva.inner = []SignedTxn{pay, pay}
+	err := ac.apply(h, &b, spec, ad, txnCounter, &steva)
+	a.NoError(err)
+	a.Equal(2, len(ad.InnerTxns))
+	a.Equal(pay, ad.InnerTxns[0].SignedTxn)
+	a.Equal(pay, ad.InnerTxns[1].SignedTxn)
+
+	// only the application account may send inner transactions
+	bad := pay
+	bad.Txn.Sender = creator
+	steva.inner = []SignedTxn{pay, bad}
+	ad = &ApplyData{}
+	err = ac.apply(h, &b, spec, ad, txnCounter, &steva)
+	a.Error(err)
+	a.Contains(err.Error(), "inner transaction 1: sender")
+	a.Empty(ad.InnerTxns)
+
+	// inner transactions must be well formed
+	bad = pay
+	bad.Txn.Fee = basics.MicroAlgos{}
+	steva.inner = []SignedTxn{bad}
+	ad = &ApplyData{}
+	err = ac.apply(h, &b, spec, ad, txnCounter, &steva)
+	a.Error(err)
+	a.Contains(err.Error(), "less than the minimum")
+	a.Empty(ad.InnerTxns)
+
+	// only pay, axfer and acfg transactions are allowed
+	bad = pay
+	bad.Txn.Type = protocol.KeyRegistrationTx
+	bad.Txn.PaymentTxnFields = PaymentTxnFields{}
+	steva.inner = []SignedTxn{bad}
+	ad = &ApplyData{}
+	err = ac.apply(h, &b, spec, ad, txnCounter, &steva)
+	a.Error(err)
+	a.Contains(err.Error(), "type keyreg not allowed")
+
+	steva.inner = make([]SignedTxn, proto.MaxInnerTransactions+1)
+	for i := range steva.inner {
+		steva.inner[i] = pay
+	}
+	ad = &ApplyData{}
+	err = ac.apply(h, &b, spec, ad, txnCounter, &steva)
+	a.Error(err)
+	a.Contains(err.Error(), "too many inner transactions")
+}
+
 func TestAppCallApplyCreateClearState(t *testing.T) {
 	a := require.New(t)
 
@@ -0,0 +1,784 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// FindingKind classifies the problems reported by Analyze
+type FindingKind string
+
+const (
+	// FindingUnreachable is code that no path from the start of the program gets to
+	FindingUnreachable FindingKind = "unreachable"
+	// FindingStackUnderflow is an op that may pop more values than the stack holds
+	FindingStackUnderflow FindingKind = "stack-underflow"
+	// FindingStackOverflow is a path pushing more than MaxStackDepth values
+	FindingStackOverflow FindingKind = "stack-overflow"
+	// FindingStackHeight is an instruction reached with different stack heights
+	FindingStackHeight FindingKind = "stack-height"
+	// FindingCost is a path costing more than the budget of the program
+	FindingCost FindingKind = "cost"
+	// FindingLoop is a backward branch or a recursive callsub, which makes
+	// the cost depend on the number of iterations
+	FindingLoop FindingKind = "loop"
+	// FindingCallStack is a retsub that no callsub leads to
+	FindingCallStack FindingKind = "callstack"
+	// FindingScratch is a scratch slot stored but never loaded, or loaded but never stored
+	FindingScratch FindingKind = "scratch"
+	// FindingStateKey is a read of a state key the program never writes
+	FindingStateKey FindingKind = "state-key"
+	// FindingSchema is more state keys written than the state schema allows
+	FindingSchema FindingKind = "schema"
+	// FindingUncheckedField is a transaction field a LogicSig should check but never reads
+	FindingUncheckedField FindingKind = "unchecked-field"
+)
+
+// Finding is a problem found by Analyze. PC is -1 for findings about the
+// program as a whole.
+type Finding struct {
+	PC      int         `codec:"pc"`
+	Kind    FindingKind `codec:"kind"`
+	Message string      `codec:"message"`
+}
+
+// BasicBlock is a sequence of instructions that is only entered at its first
+// instruction and only left after its last one
+type BasicBlock struct {
+	// Start is the pc of the first instruction and End the pc following the last one
+	Start int `codec:"start"`
+	End   int `codec:"end"`
+	Cost  int `codec:"cost"`
+	// Successors are the Start of the blocks the control may flow to.
+	// A callsub leads to both the subroutine and the instruction following it.
+	Successors []int `codec:"succ"`
+}
+
+// PathCost is the worst case cost of the paths ending at an exit of the program
+type PathCost struct {
+	// Exit is the pc of the instruction ending the paths, or the length of
+	// the program for the paths running past its last instruction
+	Exit int    `codec:"exit"`
+	Op   string `codec:"op"`
+	Cost int    `codec:"cost"`
+}
+
+// AnalyzeParams describes how a program is going to be run
+type AnalyzeParams struct {
+	Proto *config.ConsensusParams
+
+	// Stateful is set for ApprovalPrograms and ClearStatePrograms
+	Stateful bool
+
+	// GlobalSchema and LocalSchema of the application, if known
+	GlobalSchema *basics.StateSchema
+	LocalSchema  *basics.StateSchema
+}
+
+// Analysis is the result of statically analyzing a program
+type Analysis struct {
+	Version uint64 `codec:"version"`
+
+	// StaticCost is the sum of the costs of all the instructions, which
+	// bounds the cost of programs before version 3
+	StaticCost int `codec:"staticcost"`
+
+	Blocks []BasicBlock `codec:"blocks"`
+	Paths  []PathCost   `codec:"paths"`
+	// MaxCost is the cost of the most expensive path through the program.
+	// When the program has loops only the paths not repeating any
+	// instruction are accounted for.
+	MaxCost       int  `codec:"maxcost"`
+	HasLoops      bool `codec:"loops"`
+	MaxStackDepth int  `codec:"maxstack"`
+
+	Findings []Finding `codec:"findings"`
+}
+
+// instruction is a decoded instruction of the analyzed program
+type instruction struct {
+	pc   int
+	next int
+	spec *OpSpec
+	cost int
+}
+
+// absValue is what is statically known about a value on the stack
+type absValue struct {
+	typ   StackType
+	known bool
+	uint  uint64
+	bytes []byte
+}
+
+// stateAccess records the constant keys an application reads and writes
+type stateAccess struct {
+	reads  map[string]int
+	writes map[string]StackType
+}
+
+type analyzer struct {
+	AnalyzeParams
+	program []byte
+	version uint64
+
+	instrs  []instruction
+	byPC    map[int]int
+	blocks  map[int]*BasicBlock
+	intc    []uint64
+	bytec   [][]byte
+	reached map[int]bool
+
+	analysis Analysis
+	reported map[string]bool
+
+	global stateAccess
+	local  stateAccess
+}
+
+// Analyze builds the control flow graph of a program, computes the worst
+// case cost of its paths and looks for the usual mistakes: unreachable code,
+// stack underflows and overflows, unused scratch slots, state keys that are
+// read but never written or exceed the schema, and LogicSigs that do not
+// check the fields rekeying or closing the account.
+func Analyze(program []byte, params AnalyzeParams) (Analysis, error) {
+	ep := EvalParams{Proto: params.Proto}
+	var cost int
+	var err error
+	if params.Stateful {
+		cost, err = CheckStateful(program, ep)
+	} else {
+		cost, err = Check(program, ep)
+	}
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	a := analyzer{
+		AnalyzeParams: params,
+		program:       program,
+		reported:      make(map[string]bool),
+		global:        stateAccess{make(map[string]int), make(map[string]StackType)},
+		local:         stateAccess{make(map[string]int), make(map[string]StackType)},
+	}
+	err = a.decode()
+	if err != nil {
+		return Analysis{}, err
+	}
+	a.analysis.Version = a.version
+	a.analysis.StaticCost = cost
+
+	a.buildBlocks()
+	a.findUnreachable()
+	a.walkStack()
+	a.walkCost()
+	a.checkCost()
+	a.checkScratch()
+	a.checkState()
+	a.checkUncheckedFields()
+
+	sort.SliceStable(a.analysis.Findings, func(i, j int) bool {
+		return a.analysis.Findings[i].PC < a.analysis.Findings[j].PC
+	})
+	return a.analysis, nil
+}
+
+func (a *analyzer) report(pc int, kind FindingKind, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	key := fmt.Sprintf("%d %s %s", pc, kind, msg)
+	if a.reported[key] {
+		return
+	}
+	a.reported[key] = true
+	a.analysis.Findings = append(a.analysis.Findings, Finding{PC: pc, Kind: kind, Message: msg})
+}
+
+// decode splits the already checked program into instructions
+func (a *analyzer) decode() error {
+	version, vlen := binary.Uvarint(a.program)
+	a.version = version
+
+	var cx evalContext
+	cx.EvalParams = EvalParams{Proto: a.Proto}
+	cx.runModeFlags = runModeSignature
+	if a.Stateful {
+		cx.runModeFlags = runModeApplication
+	}
+	cx.version = version
+	cx.program = a.program
+	cx.pc = vlen
+	cx.instructionStarts = make(map[int]bool)
+
+	a.byPC = make(map[int]int)
+	intcblocks, bytecblocks := 0, 0
+	for cx.pc < len(a.program) {
+		pc := cx.pc
+		cx.instructionStarts[pc] = true
+		cost := cx.checkStep()
+		if cx.err != nil {
+			return cx.err
+		}
+		spec := &opsByOpcode[version][a.program[pc]]
+		a.byPC[pc] = len(a.instrs)
+		a.instrs = append(a.instrs, instruction{pc: pc, next: cx.pc, spec: spec, cost: cost})
+
+		// constants are only known if there is a single block of each kind
+		switch spec.Name {
+		case "intcblock":
+			intcblocks++
+			a.intc, _, _ = parseIntcblock(a.program, pc)
+		case "bytecblock":
+			bytecblocks++
+			a.bytec, _, _ = parseBytecBlock(a.program, pc)
+		}
+	}
+	if intcblocks > 1 {
+		a.intc = nil
+	}
+	if bytecblocks > 1 {
+		a.bytec = nil
+	}
+	return nil
+}
+
+func (a *analyzer) branchTarget(ins *instruction) int {
+	offset := (uint(a.program[ins.pc+1]) << 8) | uint(a.program[ins.pc+2])
+	return ins.pc + 3 + int(int16(offset))
+}
+
+func isBranch(name string) bool {
+	switch name {
+	case "b", "bz", "bnz", "callsub":
+		return true
+	}
+	return false
+}
+
+func isTerminator(name string) bool {
+	switch name {
+	case "return", "err", "retsub":
+		return true
+	}
+	return false
+}
+
+func (a *analyzer) buildBlocks() {
+	if len(a.instrs) == 0 {
+		return
+	}
+	leaders := map[int]bool{a.instrs[0].pc: true}
+	for i := range a.instrs {
+		ins := &a.instrs[i]
+		if isBranch(ins.spec.Name) {
+			leaders[a.branchTarget(ins)] = true
+		}
+		if isBranch(ins.spec.Name) || isTerminator(ins.spec.Name) {
+			leaders[ins.next] = true
+		}
+	}
+
+	a.blocks = make(map[int]*BasicBlock)
+	var current *BasicBlock
+	for i := range a.instrs {
+		ins := &a.instrs[i]
+		if leaders[ins.pc] {
+			a.analysis.Blocks = append(a.analysis.Blocks, BasicBlock{Start: ins.pc})
+			current = &a.analysis.Blocks[len(a.analysis.Blocks)-1]
+		}
+		current.End = ins.next
+		current.Cost += ins.cost
+	}
+
+	for i := range a.analysis.Blocks {
+		block := &a.analysis.Blocks[i]
+		a.blocks[block.Start] = block
+		last := &a.instrs[a.byPC[a.lastPC(block)]]
+		var successors []int
+		switch last.spec.Name {
+		case "b":
+			successors = []int{a.branchTarget(last)}
+		case "bz", "bnz", "callsub":
+			successors = []int{a.branchTarget(last), last.next}
+		case "return", "err", "retsub":
+		default:
+			successors = []int{last.next}
+		}
+		for _, s := range successors {
+			// the end of the program is not a block
+			if s < len(a.program) && (len(block.Successors) == 0 || block.Successors[0] != s) {
+				block.Successors = append(block.Successors, s)
+			}
+		}
+	}
+}
+
+// lastPC returns the pc of the last instruction of a block
+func (a *analyzer) lastPC(block *BasicBlock) int {
+	i := a.byPC[block.Start]
+	for i+1 < len(a.instrs) && a.instrs[i+1].pc < block.End {
+		i++
+	}
+	return a.instrs[i].pc
+}
+
+func (a *analyzer) findUnreachable() {
+	a.reached = make(map[int]bool)
+	if len(a.analysis.Blocks) == 0 {
+		return
+	}
+	queue := []int{a.analysis.Blocks[0].Start}
+	a.reached[queue[0]] = true
+	for len(queue) > 0 {
+		block := a.blocks[queue[0]]
+		queue = queue[1:]
+		for _, s := range block.Successors {
+			if !a.reached[s] {
+				a.reached[s] = true
+				queue = append(queue, s)
+			}
+		}
+	}
+	for _, block := range a.analysis.Blocks {
+		if !a.reached[block.Start] {
+			a.report(block.Start, FindingUnreachable, "code at pc %d to %d is unreachable", block.Start, block.End)
+		}
+	}
+}
+
+// pathState is a block being entered with a call stack and a stack
+type pathState struct {
+	block     int
+	callstack []int
+	stack     []absValue
+}
+
+func stateKey(block int, callstack []int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d", block)
+	for _, ret := range callstack {
+		fmt.Fprintf(&sb, ":%d", ret)
+	}
+	return sb.String()
+}
+
+func inCallstack(callstack []int, ret int) bool {
+	for _, r := range callstack {
+		if r == ret {
+			return true
+		}
+	}
+	return false
+}
+
+// walkStack follows all the paths of the program tracking the height of the
+// stack and the constants on it
+func (a *analyzer) walkStack() {
+	if len(a.analysis.Blocks) == 0 {
+		return
+	}
+	heights := make(map[string]int)
+	work := []pathState{{block: a.analysis.Blocks[0].Start}}
+	for len(work) > 0 {
+		ps := work[len(work)-1]
+		work = work[:len(work)-1]
+
+		key := stateKey(ps.block, ps.callstack)
+		if height, ok := heights[key]; ok {
+			if height != len(ps.stack) {
+				a.report(ps.block, FindingStackHeight, "reached with stack heights %d and %d", height, len(ps.stack))
+			}
+			continue
+		}
+		heights[key] = len(ps.stack)
+
+		block := a.blocks[ps.block]
+		stack := ps.stack
+		var last *instruction
+		ok := true
+		for i := a.byPC[block.Start]; i < len(a.instrs) && a.instrs[i].pc < block.End; i++ {
+			last = &a.instrs[i]
+			stack, ok = a.step(last, stack)
+			if !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		next := func(pc int, callstack []int) {
+			if pc < len(a.program) {
+				work = append(work, pathState{pc, callstack, stack})
+			}
+		}
+		switch last.spec.Name {
+		case "b":
+			next(a.branchTarget(last), ps.callstack)
+		case "bz", "bnz":
+			next(last.next, ps.callstack)
+			next(a.branchTarget(last), ps.callstack)
+		case "callsub":
+			if inCallstack(ps.callstack, last.next) {
+				a.report(last.pc, FindingLoop, "recursive callsub")
+				continue
+			}
+			callstack := make([]int, len(ps.callstack), len(ps.callstack)+1)
+			copy(callstack, ps.callstack)
+			next(a.branchTarget(last), append(callstack, last.next))
+		case "retsub":
+			if len(ps.callstack) == 0 {
+				a.report(last.pc, FindingCallStack, "retsub may be reached without callsub")
+				continue
+			}
+			top := len(ps.callstack) - 1
+			next(ps.callstack[top], ps.callstack[:top])
+		case "return", "err":
+		default:
+			next(last.next, ps.callstack)
+		}
+	}
+}
+
+// step applies the stack effects of an instruction, returning false if the
+// path cannot go on
+func (a *analyzer) step(ins *instruction, stack []absValue) ([]absValue, bool) {
+	spec := ins.spec
+	needed := len(spec.Args)
+	if spec.Name == "dig" {
+		needed = int(a.program[ins.pc+1]) + 1
+	}
+	if len(stack) < needed {
+		a.report(ins.pc, FindingStackUnderflow, "%s needs %d values but the stack may only have %d", spec.Name, needed, len(stack))
+		return stack, false
+	}
+	top := len(stack) - 1
+
+	var pushed []absValue
+	switch spec.Name {
+	case "intc", "intc_0", "intc_1", "intc_2", "intc_3":
+		idx := int(ins.spec.Opcode) - 0x22
+		if spec.Name == "intc" {
+			idx = int(a.program[ins.pc+1])
+		}
+		v := absValue{typ: StackUint64}
+		if idx < len(a.intc) {
+			v.known = true
+			v.uint = a.intc[idx]
+		}
+		pushed = []absValue{v}
+	case "bytec", "bytec_0", "bytec_1", "bytec_2", "bytec_3":
+		idx := int(ins.spec.Opcode) - 0x28
+		if spec.Name == "bytec" {
+			idx = int(a.program[ins.pc+1])
+		}
+		v := absValue{typ: StackBytes}
+		if idx < len(a.bytec) {
+			v.known = true
+			v.bytes = a.bytec[idx]
+		}
+		pushed = []absValue{v}
+	case "pushint":
+		val, _, _ := parsePushInt(a.program, ins.pc)
+		pushed = []absValue{{typ: StackUint64, known: true, uint: val}}
+	case "pushbytes":
+		val, _, _ := parsePushBytes(a.program, ins.pc)
+		pushed = []absValue{{typ: StackBytes, known: true, bytes: val}}
+	case "dup":
+		pushed = []absValue{stack[top], stack[top]}
+	case "dup2":
+		pushed = []absValue{stack[top-1], stack[top], stack[top-1], stack[top]}
+	case "swap":
+		pushed = []absValue{stack[top], stack[top-1]}
+	case "dig":
+		n := int(a.program[ins.pc+1])
+		// dig pops nothing, so keep what it "popped" and add the copy
+		pushed = append([]absValue{}, stack[len(stack)-needed:]...)
+		pushed = append(pushed, stack[top-n])
+	case "app_global_get":
+		a.readKey(&a.global, ins.pc, stack[top])
+	case "app_local_get":
+		a.readKey(&a.local, ins.pc, stack[top])
+	case "app_global_put":
+		a.writeKey(&a.global, stack[top-1], stack[top])
+	case "app_local_put":
+		a.writeKey(&a.local, stack[top-1], stack[top])
+	}
+	if pushed == nil {
+		for _, t := range spec.Returns {
+			pushed = append(pushed, absValue{typ: t})
+		}
+	}
+
+	stack = append(stack[:len(stack)-needed:len(stack)-needed], pushed...)
+	if len(stack) > a.analysis.MaxStackDepth {
+		a.analysis.MaxStackDepth = len(stack)
+	}
+	if len(stack) > MaxStackDepth {
+		a.report(ins.pc, FindingStackOverflow, "stack may grow to %d values, more than %d", len(stack), MaxStackDepth)
+		return stack, false
+	}
+	return stack, true
+}
+
+func (a *analyzer) readKey(sa *stateAccess, pc int, key absValue) {
+	if !key.known {
+		return
+	}
+	if _, ok := sa.reads[string(key.bytes)]; !ok {
+		sa.reads[string(key.bytes)] = pc
+	}
+}
+
+func (a *analyzer) writeKey(sa *stateAccess, key absValue, value absValue) {
+	if !key.known {
+		return
+	}
+	if t, ok := sa.writes[string(key.bytes)]; ok && t != value.typ {
+		sa.writes[string(key.bytes)] = StackAny
+		return
+	}
+	sa.writes[string(key.bytes)] = value.typ
+}
+
+// walkCost computes the worst case cost of reaching every exit of the program
+func (a *analyzer) walkCost() {
+	if len(a.analysis.Blocks) == 0 {
+		return
+	}
+	memo := make(map[string]map[int]int)
+	active := make(map[string]bool)
+	exitOps := make(map[int]string)
+
+	var walk func(block int, callstack []int) map[int]int
+	walk = func(block int, callstack []int) map[int]int {
+		key := stateKey(block, callstack)
+		if costs, ok := memo[key]; ok {
+			return costs
+		}
+		if active[key] {
+			a.analysis.HasLoops = true
+			a.report(block, FindingLoop, "loop back to pc %d, the cost depends on the number of iterations", block)
+			return nil
+		}
+		active[key] = true
+		defer delete(active, key)
+
+		b := a.blocks[block]
+		last := &a.instrs[a.byPC[a.lastPC(b)]]
+		costs := make(map[int]int)
+		follow := func(pc int, callstack []int) {
+			var sub map[int]int
+			if pc >= len(a.program) {
+				exitOps[len(a.program)] = "end"
+				sub = map[int]int{len(a.program): 0}
+			} else {
+				sub = walk(pc, callstack)
+			}
+			for exit, cost := range sub {
+				if prev, ok := costs[exit]; !ok || cost+b.Cost > prev {
+					costs[exit] = cost + b.Cost
+				}
+			}
+		}
+		exit := func() {
+			exitOps[last.pc] = last.spec.Name
+			costs[last.pc] = b.Cost
+		}
+
+		switch last.spec.Name {
+		case "b":
+			follow(a.branchTarget(last), callstack)
+		case "bz", "bnz":
+			follow(last.next, callstack)
+			follow(a.branchTarget(last), callstack)
+		case "callsub":
+			if inCallstack(callstack, last.next) {
+				a.analysis.HasLoops = true
+				break
+			}
+			inner := make([]int, len(callstack), len(callstack)+1)
+			copy(inner, callstack)
+			follow(a.branchTarget(last), append(inner, last.next))
+		case "retsub":
+			if len(callstack) == 0 {
+				exit()
+				break
+			}
+			top := len(callstack) - 1
+			follow(callstack[top], callstack[:top])
+		case "return", "err":
+			exit()
+		default:
+			follow(last.next, callstack)
+		}
+		memo[key] = costs
+		return costs
+	}
+
+	costs := walk(a.analysis.Blocks[0].Start, nil)
+	for exit, cost := range costs {
+		a.analysis.Paths = append(a.analysis.Paths, PathCost{Exit: exit, Op: exitOps[exit], Cost: cost})
+		if cost > a.analysis.MaxCost {
+			a.analysis.MaxCost = cost
+		}
+	}
+	sort.Slice(a.analysis.Paths, func(i, j int) bool {
+		return a.analysis.Paths[i].Exit < a.analysis.Paths[j].Exit
+	})
+}
+
+func (a *analyzer) checkCost() {
+	if a.Proto == nil {
+		return
+	}
+	budget := a.Proto.LogicSigMaxCost
+	if a.Stateful {
+		budget = uint64(a.Proto.MaxAppProgramCost)
+	}
+	if a.version < backBranchEnabledVersion {
+		if uint64(a.analysis.StaticCost) > budget {
+			a.report(-1, FindingCost, "program costs %d, more than the budget of %d", a.analysis.StaticCost, budget)
+		}
+		return
+	}
+	if uint64(a.analysis.MaxCost) > budget {
+		a.report(-1, FindingCost, "the most expensive path costs %d, more than the budget of %d", a.analysis.MaxCost, budget)
+	}
+}
+
+func (a *analyzer) checkScratch() {
+	loads := make(map[byte]int)
+	stores := make(map[byte]int)
+	for _, ins := range a.instrs {
+		if !a.reachedPC(ins.pc) {
+			continue
+		}
+		var seen map[byte]int
+		switch ins.spec.Name {
+		case "load":
+			seen = loads
+		case "store":
+			seen = stores
+		default:
+			continue
+		}
+		slot := a.program[ins.pc+1]
+		if _, ok := seen[slot]; !ok {
+			seen[slot] = ins.pc
+		}
+	}
+	for slot, pc := range stores {
+		if _, ok := loads[slot]; !ok {
+			a.report(pc, FindingScratch, "scratch slot %d is stored but never loaded", slot)
+		}
+	}
+	for slot, pc := range loads {
+		if _, ok := stores[slot]; !ok {
+			a.report(pc, FindingScratch, "scratch slot %d is loaded but never stored, it is always 0", slot)
+		}
+	}
+}
+
+// reachedPC tells if the block holding pc is reachable
+func (a *analyzer) reachedPC(pc int) bool {
+	for i := a.byPC[pc]; i >= 0; i-- {
+		if block, ok := a.blocks[a.instrs[i].pc]; ok {
+			return a.reached[block.Start]
+		}
+	}
+	return false
+}
+
+func (a *analyzer) checkState() {
+	a.checkStateAccess("global", &a.global, a.GlobalSchema)
+	a.checkStateAccess("local", &a.local, a.LocalSchema)
+}
+
+func (a *analyzer) checkStateAccess(scope string, sa *stateAccess, schema *basics.StateSchema) {
+	for key, pc := range sa.reads {
+		if _, ok := sa.writes[key]; !ok {
+			a.report(pc, FindingStateKey, "%s key %s is read but never written by the program", scope, guessByteFormat([]byte(key)))
+		}
+	}
+	if schema == nil {
+		return
+	}
+	var uints, byteSlices uint64
+	for _, t := range sa.writes {
+		switch t {
+		case StackUint64:
+			uints++
+		case StackBytes:
+			byteSlices++
+		}
+	}
+	total := uint64(len(sa.writes))
+	if uints > schema.NumUint {
+		a.report(-1, FindingSchema, "program writes %d %s uint keys but the schema allows %d", uints, scope, schema.NumUint)
+	}
+	if byteSlices > schema.NumByteSlice {
+		a.report(-1, FindingSchema, "program writes %d %s byte slice keys but the schema allows %d", byteSlices, scope, schema.NumByteSlice)
+	}
+	if total > schema.NumUint+schema.NumByteSlice {
+		a.report(-1, FindingSchema, "program writes %d %s keys but the schema allows %d", total, scope, schema.NumUint+schema.NumByteSlice)
+	}
+}
+
+// checkUncheckedFields reports the fields letting a transaction approved by
+// a LogicSig take the account over or empty it, when the program never looks
+// at them. The fields the version of the program can't read aren't reported:
+// the transactions using them are rejected for such programs anyway.
+func (a *analyzer) checkUncheckedFields() {
+	if a.Stateful {
+		return
+	}
+	read := make(map[TxnField]bool)
+	for _, ins := range a.instrs {
+		if !a.reachedPC(ins.pc) {
+			continue
+		}
+		switch ins.spec.Name {
+		case "txn", "txna":
+			read[TxnField(a.program[ins.pc+1])] = true
+		case "gtxn", "gtxna":
+			read[TxnField(a.program[ins.pc+2])] = true
+		}
+	}
+	unchecked := []struct {
+		field TxnField
+		risk  string
+	}{
+		{RekeyTo, "rekey the account"},
+		{CloseRemainderTo, "close the account"},
+		{AssetCloseTo, "close the asset holding"},
+	}
+	for _, u := range unchecked {
+		if txnFieldSpecByField[u.field].version > a.version {
+			continue
+		}
+		if !read[u.field] {
+			a.report(-1, FindingUncheckedField, "%s is never checked, an approved transaction could %s", u.field.String(), u.risk)
+		}
+	}
+}
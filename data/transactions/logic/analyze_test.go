@@ -0,0 +1,259 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+const checkedFieldsPrefix = `txn RekeyTo
+global ZeroAddress
+==
+txn CloseRemainderTo
+global ZeroAddress
+==
+&&
+txn AssetCloseTo
+global ZeroAddress
+==
+&&
+assert
+`
+
+func analyzeSource(t *testing.T, source string, params AnalyzeParams) Analysis {
+	program, err := AssembleString(source)
+	require.NoError(t, err)
+	if params.Proto == nil {
+		proto := defaultEvalProto()
+		params.Proto = &proto
+	}
+	analysis, err := Analyze(program, params)
+	require.NoError(t, err)
+	return analysis
+}
+
+func findingKinds(analysis Analysis) []FindingKind {
+	var kinds []FindingKind
+	for _, f := range analysis.Findings {
+		kinds = append(kinds, f.Kind)
+	}
+	return kinds
+}
+
+func TestAnalyzeUncheckedFields(t *testing.T) {
+	t.Parallel()
+
+	analysis := analyzeSource(t, "int 1", AnalyzeParams{})
+	require.Equal(t, []FindingKind{FindingUncheckedField, FindingUncheckedField, FindingUncheckedField}, findingKinds(analysis))
+	require.Contains(t, analysis.Findings[0].Message, "RekeyTo")
+	require.Equal(t, -1, analysis.Findings[0].PC)
+
+	analysis = analyzeSource(t, checkedFieldsPrefix+"int 1", AnalyzeParams{})
+	require.Empty(t, analysis.Findings)
+
+	// application programs are not LogicSigs
+	analysis = analyzeSource(t, "int 1", AnalyzeParams{Stateful: true})
+	require.Empty(t, analysis.Findings)
+
+	// RekeyTo can't be read, nor used, before version 4.
+	analysis = analyzeSource(t, `#pragma version 2
+txn CloseRemainderTo
+global ZeroAddress
+==
+txn AssetCloseTo
+global ZeroAddress
+==
+&&`, AnalyzeParams{})
+	require.Empty(t, analysis.Findings)
+}
+
+func TestAnalyzeBlocksAndCost(t *testing.T) {
+	t.Parallel()
+
+	source := checkedFieldsPrefix + `int 1
+bnz hash
+int 1
+return
+hash:
+byte "x"
+sha256
+len
+`
+	program, err := AssembleString(source)
+	require.NoError(t, err)
+	analysis := analyzeSource(t, source, AnalyzeParams{})
+	require.Empty(t, analysis.Findings)
+	require.False(t, analysis.HasLoops)
+	require.Equal(t, 3, len(analysis.Blocks))
+	require.Equal(t, 2, len(analysis.Blocks[0].Successors))
+
+	// the return and the end of the program
+	require.Equal(t, 2, len(analysis.Paths))
+	returnPath, endPath := analysis.Paths[0], analysis.Paths[1]
+	require.Equal(t, "return", returnPath.Op)
+	require.Equal(t, "end", endPath.Op)
+	require.Equal(t, len(program), endPath.Exit)
+	// sha256 costs 35, the other ops 1
+	require.Equal(t, returnPath.Cost-2+1+35+1, endPath.Cost)
+	require.Equal(t, endPath.Cost, analysis.MaxCost)
+	require.Equal(t, analysis.StaticCost, analysis.MaxCost+2)
+
+	proto := defaultEvalProto()
+	proto.LogicSigMaxCost = uint64(returnPath.Cost)
+	analysis = analyzeSource(t, source, AnalyzeParams{Proto: &proto})
+	require.Equal(t, []FindingKind{FindingCost}, findingKinds(analysis))
+}
+
+func TestAnalyzeUnreachable(t *testing.T) {
+	t.Parallel()
+
+	analysis := analyzeSource(t, checkedFieldsPrefix+`int 1
+return
+int 2
+`, AnalyzeParams{})
+	require.Equal(t, []FindingKind{FindingUnreachable}, findingKinds(analysis))
+}
+
+func TestAnalyzeStack(t *testing.T) {
+	t.Parallel()
+
+	analysis := analyzeSource(t, checkedFieldsPrefix+`int 0
+bnz skip
+int 1
+skip:
+int 1
++
+`, AnalyzeParams{})
+	require.ElementsMatch(t, []FindingKind{FindingStackHeight, FindingStackUnderflow}, findingKinds(analysis))
+	require.Equal(t, 3, analysis.MaxStackDepth)
+
+	analysis = analyzeSource(t, checkedFieldsPrefix+`int 1
+dig 1
+`, AnalyzeParams{})
+	require.Equal(t, []FindingKind{FindingStackUnderflow}, findingKinds(analysis))
+}
+
+func TestAnalyzeLoopsAndSubroutines(t *testing.T) {
+	t.Parallel()
+
+	analysis := analyzeSource(t, checkedFieldsPrefix+`int 3
+loop:
+int 1
+-
+dup
+bnz loop
+`, AnalyzeParams{})
+	require.True(t, analysis.HasLoops)
+	require.Equal(t, []FindingKind{FindingLoop}, findingKinds(analysis))
+
+	analysis = analyzeSource(t, checkedFieldsPrefix+`int 1
+callsub double
+callsub double
+return
+double:
+dup
++
+retsub
+`, AnalyzeParams{})
+	require.Empty(t, analysis.Findings)
+	require.False(t, analysis.HasLoops)
+	require.Equal(t, 1, len(analysis.Paths))
+	require.Equal(t, "return", analysis.Paths[0].Op)
+
+	analysis = analyzeSource(t, checkedFieldsPrefix+`int 1
+callsub again
+return
+again:
+int 1
+callsub again
+retsub
+`, AnalyzeParams{})
+	require.True(t, analysis.HasLoops)
+	require.Contains(t, findingKinds(analysis), FindingLoop)
+
+	analysis = analyzeSource(t, checkedFieldsPrefix+`int 1
+retsub
+`, AnalyzeParams{})
+	require.Equal(t, []FindingKind{FindingCallStack}, findingKinds(analysis))
+}
+
+func TestAnalyzeScratch(t *testing.T) {
+	t.Parallel()
+
+	analysis := analyzeSource(t, checkedFieldsPrefix+`int 1
+store 0
+int 1
+store 1
+load 1
+load 2
++
+`, AnalyzeParams{})
+	require.Equal(t, []FindingKind{FindingScratch, FindingScratch}, findingKinds(analysis))
+	require.Contains(t, analysis.Findings[0].Message, "slot 0 is stored but never loaded")
+	require.Contains(t, analysis.Findings[1].Message, "slot 2 is loaded but never stored")
+}
+
+func TestAnalyzeState(t *testing.T) {
+	t.Parallel()
+
+	source := `byte "counter"
+int 1
+app_global_put
+byte "owner"
+txn Sender
+app_global_put
+int 0
+byte "name"
+byte "x"
+app_local_put
+byte "missing"
+app_global_get
+pop
+int 0
+byte "name"
+app_local_get
+`
+	analysis := analyzeSource(t, source, AnalyzeParams{Stateful: true})
+	require.Equal(t, []FindingKind{FindingStateKey}, findingKinds(analysis))
+	require.Contains(t, analysis.Findings[0].Message, `global key "missing"`)
+
+	global := basics.StateSchema{NumUint: 1, NumByteSlice: 1}
+	local := basics.StateSchema{}
+	analysis = analyzeSource(t, source, AnalyzeParams{Stateful: true, GlobalSchema: &global, LocalSchema: &local})
+	require.Equal(t, []FindingKind{FindingSchema, FindingSchema, FindingStateKey}, findingKinds(analysis))
+	require.Contains(t, analysis.Findings[0].Message, "1 local byte slice keys but the schema allows 0")
+	require.Contains(t, analysis.Findings[1].Message, "1 local keys")
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	t.Parallel()
+
+	proto := defaultEvalProto()
+	_, err := Analyze([]byte{0x04, 0xff}, AnalyzeParams{Proto: &proto})
+	require.Error(t, err)
+
+	program, err := AssembleString("int 0\nbyte \"k\"\napp_local_get")
+	require.NoError(t, err)
+	_, err = Analyze(program, AnalyzeParams{Proto: &proto})
+	require.Error(t, err)
+	_, err = Analyze(program, AnalyzeParams{Proto: &proto, Stateful: true})
+	require.NoError(t, err)
+}
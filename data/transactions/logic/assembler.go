@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
@@ -64,16 +65,17 @@ type OpStream struct {
 	// Keep a stack of the types of what we would push and pop to typecheck a program
 	typeStack []StackType
 
-	// current sourceLine during assembly
-	sourceLine int
+	// current sourceLine and sourceColumn during assembly
+	sourceLine   int
+	sourceColumn int
 
 	// map label string to position within Out buffer
 	labels map[string]int
 
 	labelReferences []labelReference
 
-	// map opcode offsets to source line
-	offsetToLine map[int]int
+	// map opcode offsets to source location
+	offsetToLocation map[int]SourceLocation
 }
 
 // GetVersion returns the LogicSigVersion we're building to
@@ -96,12 +98,12 @@ func (ops *OpStream) SetLabelHere(label string) error {
 	return nil
 }
 
-// RecordSourceLine adds an entry to pc to line mapping
+// RecordSourceLine adds an entry to pc to source location mapping
 func (ops *OpStream) RecordSourceLine() {
-	if ops.offsetToLine == nil {
-		ops.offsetToLine = make(map[int]int)
+	if ops.offsetToLocation == nil {
+		ops.offsetToLocation = make(map[int]SourceLocation)
 	}
-	ops.offsetToLine[ops.Out.Len()] = ops.sourceLine - 1
+	ops.offsetToLocation[ops.Out.Len()] = SourceLocation{Line: ops.sourceLine - 1, Column: ops.sourceColumn}
 }
 
 // ReferToLabel records an opcode label refence to resolve later
@@ -331,6 +333,30 @@ func (ops *OpStream) AssetParams(val uint64) error {
 	return nil
 }
 
+// AppParams writes opcodes for accessing data from AppParams
+func (ops *OpStream) AppParams(val uint64) error {
+	if val >= uint64(len(AppParamsFieldNames)) {
+		return errors.New("invalid app params field")
+	}
+	ops.Out.WriteByte(opsByName[ops.Version]["app_params_get"].Opcode)
+	ops.Out.WriteByte(uint8(val))
+	ops.tpush(AppParamsFieldTypes[val])
+	ops.tpush(StackUint64)
+	return nil
+}
+
+// AcctParams writes opcodes for accessing data from an account
+func (ops *OpStream) AcctParams(val uint64) error {
+	if val >= uint64(len(AcctParamsFieldNames)) {
+		return errors.New("invalid account params field")
+	}
+	ops.Out.WriteByte(opsByName[ops.Version]["acct_params_get"].Opcode)
+	ops.Out.WriteByte(uint8(val))
+	ops.tpush(AcctParamsFieldTypes[val])
+	ops.tpush(StackUint64)
+	return nil
+}
+
 func assembleInt(ops *OpStream, spec *OpSpec, args []string) error {
 	// check friendly TypeEnum constants
 	te, isTypeEnum := txnTypeConstToUint64[args[0]]
@@ -614,6 +640,69 @@ func assembleBranch(ops *OpStream, spec *OpSpec, args []string) error {
 	return nil
 }
 
+func assembleDig(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return errors.New("dig operation needs one argument")
+	}
+	val, err := strconv.ParseUint(args[0], 0, 64)
+	if err != nil {
+		return err
+	}
+	if val > 255 {
+		return errors.New("dig limited to 0..255")
+	}
+	err = ops.checkArgs(*spec)
+	if err != nil {
+		return err
+	}
+	// dig leaves the stack as is and pushes a copy of one of its values
+	ops.tpusha(spec.Args)
+	ops.tpush(StackAny)
+	ops.Out.WriteByte(spec.Opcode)
+	ops.Out.WriteByte(byte(val))
+	return nil
+}
+
+// pushint {uint64}
+// writes the value as an immediate varuint rather than into the intcblock
+func assemblePushInt(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return errors.New("pushint needs one argument")
+	}
+	val, err := strconv.ParseUint(args[0], 0, 64)
+	if err != nil {
+		return err
+	}
+	ops.Out.WriteByte(spec.Opcode)
+	var scratch [binary.MaxVarintLen64]byte
+	vlen := binary.PutUvarint(scratch[:], val)
+	ops.Out.Write(scratch[:vlen])
+	ops.tpush(StackUint64)
+	return nil
+}
+
+// pushbytes {base64,b64,base32,b32}(...)
+// pushbytes {base64,b64,base32,b32} ...
+// pushbytes 0x....
+// pushbytes "this is a string\n"
+// writes the value as an immediate rather than into the bytecblock
+func assemblePushBytes(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) == 0 {
+		return errors.New("pushbytes needs byte literal argument")
+	}
+	val, _, err := parseBinaryArgs(args)
+	if err != nil {
+		return err
+	}
+	ops.Out.WriteByte(spec.Opcode)
+	var scratch [binary.MaxVarintLen64]byte
+	vlen := binary.PutUvarint(scratch[:], uint64(len(val)))
+	ops.Out.Write(scratch[:vlen])
+	ops.Out.Write(val)
+	ops.tpush(StackBytes)
+	return nil
+}
+
 func assembleLoad(ops *OpStream, spec *OpSpec, args []string) error {
 	if len(args) != 1 {
 		return errors.New("load operation needs one argument")
@@ -727,7 +816,7 @@ func assembleTxna(ops *OpStream, spec *OpSpec, args []string) error {
 		return errors.New("txna expects two arguments")
 	}
 	fs, ok := txnFieldSpecByName[args[0]]
-	if !ok || fs.version > ops.Version || fs.field != ApplicationArgs && fs.field != Accounts {
+	if !ok || fs.version > ops.Version || !txnFieldIsArray(fs.field) {
 		return fmt.Errorf("txna unknown arg %s", args[0])
 	}
 	arrayFieldIdx, err := strconv.ParseUint(args[1], 0, 64)
@@ -773,7 +862,7 @@ func assembleGtxna(ops *OpStream, spec *OpSpec, args []string) error {
 		return err
 	}
 	fs, ok := txnFieldSpecByName[args[1]]
-	if !ok || fs.version > ops.Version || fs.field != ApplicationArgs && fs.field != Accounts {
+	if !ok || fs.version > ops.Version || !txnFieldIsArray(fs.field) {
 		return fmt.Errorf("gtxna unknown arg %s", args[0])
 	}
 	arrayFieldIdx, err := strconv.ParseUint(args[2], 0, 64)
@@ -818,6 +907,45 @@ func assembleAssetParams(ops *OpStream, spec *OpSpec, args []string) error {
 	return ops.AssetParams(uint64(val))
 }
 
+func assembleAppParams(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return errors.New("app_params_get expects one argument")
+	}
+	val, ok := appParamsFields[args[0]]
+	if !ok {
+		return fmt.Errorf("app_params_get unknown arg %v", args[0])
+	}
+	return ops.AppParams(uint64(val))
+}
+
+func assembleAcctParams(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return errors.New("acct_params_get expects one argument")
+	}
+	val, ok := acctParamsFields[args[0]]
+	if !ok {
+		return fmt.Errorf("acct_params_get unknown arg %v", args[0])
+	}
+	return ops.AcctParams(uint64(val))
+}
+
+func assembleItxnField(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return errors.New("itxn_field expects one argument")
+	}
+	fs, ok := txnFieldSpecByName[args[0]]
+	if !ok || fs.version > ops.Version || !innerTxnFields[fs.field] {
+		return fmt.Errorf("itxn_field unknown arg %s", args[0])
+	}
+	err := ops.checkArgs(*spec)
+	if err != nil {
+		return err
+	}
+	ops.Out.WriteByte(spec.Opcode)
+	ops.Out.WriteByte(uint8(fs.field))
+	return nil
+}
+
 type assembleFunc func(*OpStream, *OpSpec, []string) error
 
 func asmDefault(ops *OpStream, spec *OpSpec, args []string) error {
@@ -1009,6 +1137,7 @@ func (ops *OpStream) Assemble(fin io.Reader) error {
 			ops.trace("%d: no fields\n", ops.sourceLine)
 			continue
 		}
+		ops.sourceColumn = strings.Index(line, fields[0])
 		opstring := fields[0]
 		spec, ok := opsByName[ops.Version][opstring]
 		var asmFunc assembleFunc
@@ -1057,11 +1186,11 @@ func (ops *OpStream) resolveLabels() (err error) {
 		}
 		// all branch instructions (currently) are opcode byte and 2 offset bytes, and the destination is relative to the next pc as if the branch was a no-op
 		naturalPc := lr.position + 3
-		if dest < naturalPc {
+		if dest < naturalPc && ops.Version < backBranchEnabledVersion {
 			return fmt.Errorf(":%d label %v is before reference but only forward jumps are allowed", lr.sourceLine, lr.label)
 		}
 		jump := dest - naturalPc
-		if jump > 0x7fff {
+		if jump > 0x7fff || jump < -0x8000 {
 			return fmt.Errorf(":%d label %v is too far away", lr.sourceLine, lr.label)
 		}
 		raw[lr.position+1] = uint8(jump >> 8)
@@ -1119,12 +1248,12 @@ func (ops *OpStream) Bytes() (program []byte, err error) {
 		return
 	}
 
-	// fixup offset to line mapping
-	newOffsetToLine := make(map[int]int, len(ops.offsetToLine))
-	for o, l := range ops.offsetToLine {
-		newOffsetToLine[o+pbl] = l
+	// fixup offset to source location mapping
+	newOffsetToLocation := make(map[int]SourceLocation, len(ops.offsetToLocation))
+	for o, l := range ops.offsetToLocation {
+		newOffsetToLocation[o+pbl] = l
 	}
-	ops.offsetToLine = newOffsetToLine
+	ops.offsetToLocation = newOffsetToLocation
 
 	program = out
 	return
@@ -1156,6 +1285,29 @@ func AssembleStringWithVersion(text string, version uint64) ([]byte, error) {
 // If version is zero it uses #pragma version or fallbacks to AssemblerDefaultVersion.
 // It also returns PC to source line mapping.
 func AssembleStringWithVersionEx(text string, version uint64) ([]byte, map[int]int, error) {
+	program, offsetToLocation, err := assembleStringWithLocations(text, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	offsetToLine := make(map[int]int, len(offsetToLocation))
+	for o, l := range offsetToLocation {
+		offsetToLine[o] = l.Line
+	}
+	return program, offsetToLine, nil
+}
+
+// AssembleStringWithSourceMap takes an entire program in a string and assembles it to bytecode
+// using #pragma version or AssemblerDefaultVersion, like AssembleString.
+// It also returns the source map relating each instruction of the program to its line and column in text.
+func AssembleStringWithSourceMap(text string) ([]byte, SourceMap, error) {
+	program, offsetToLocation, err := assembleStringWithLocations(text, assemblerNoVersion)
+	if err != nil {
+		return nil, SourceMap{}, err
+	}
+	return program, MakeSourceMap("", len(program), offsetToLocation), nil
+}
+
+func assembleStringWithLocations(text string, version uint64) ([]byte, map[int]SourceLocation, error) {
 	sr := strings.NewReader(text)
 	ps := PragmaStream{}
 	err := ps.Process(sr)
@@ -1185,7 +1337,7 @@ func AssembleStringWithVersionEx(text string, version uint64) ([]byte, map[int]i
 		return nil, nil, err
 	}
 	program, err := ops.Bytes()
-	return program, ops.offsetToLine, err
+	return program, ops.offsetToLocation, err
 }
 
 // PragmaStream represents all parsed pragmas from the program
@@ -1245,11 +1397,16 @@ func (ps *PragmaStream) Process(fin io.Reader) (err error) {
 
 type disassembleState struct {
 	program       []byte
+	version       uint64
 	pc            int
 	out           io.Writer
 	labelCount    int
 	pendingLabels map[int]string
 
+	// constants of the last intcblock and bytecblock, to annotate their references
+	intc  []uint64
+	bytec [][]byte
+
 	nextpc int
 	err    error
 }
@@ -1411,12 +1568,52 @@ func checkByteConstBlock(cx *evalContext) int {
 	return 1
 }
 
+func parsePushInt(program []byte, pc int) (val uint64, nextpc int, err error) {
+	pos := pc + 1
+	val, bytesUsed := binary.Uvarint(program[pos:])
+	if bytesUsed <= 0 {
+		err = fmt.Errorf("could not decode int at pc=%d", pos)
+		return
+	}
+	nextpc = pos + bytesUsed
+	return
+}
+
+func checkPushInt(cx *evalContext) int {
+	_, cx.nextpc, cx.err = parsePushInt(cx.program, cx.pc)
+	return 1
+}
+
+func parsePushBytes(program []byte, pc int) (val []byte, nextpc int, err error) {
+	pos := pc + 1
+	length, bytesUsed := binary.Uvarint(program[pos:])
+	if bytesUsed <= 0 {
+		err = fmt.Errorf("could not decode []byte length at pc=%d", pos)
+		return
+	}
+	pos += bytesUsed
+	end := uint64(pos) + length
+	if end > uint64(len(program)) || end < uint64(pos) {
+		err = fmt.Errorf("pushbytes too long at pc=%d", pos)
+		return
+	}
+	val = program[pos:end]
+	nextpc = int(end)
+	return
+}
+
+func checkPushBytes(cx *evalContext) int {
+	_, cx.nextpc, cx.err = parsePushBytes(cx.program, cx.pc)
+	return 1
+}
+
 func disIntcblock(dis *disassembleState, spec *OpSpec) {
 	var intc []uint64
 	intc, dis.nextpc, dis.err = parseIntcblock(dis.program, dis.pc)
 	if dis.err != nil {
 		return
 	}
+	dis.intc = intc
 	_, dis.err = fmt.Fprintf(dis.out, "intcblock")
 	if dis.err != nil {
 		return
@@ -1438,7 +1635,22 @@ func disIntc(dis *disassembleState, spec *OpSpec) {
 		return
 	}
 	dis.nextpc = dis.pc + 2
-	_, dis.err = fmt.Fprintf(dis.out, "intc %d\n", dis.program[dis.pc+1])
+	idx := dis.program[dis.pc+1]
+	_, dis.err = fmt.Fprintf(dis.out, "intc %d%s\n", idx, dis.intcComment(int(idx)))
+}
+
+// disIntcN disassembles intc_0, intc_1, ... naming the constant they load
+func disIntcN(dis *disassembleState, spec *OpSpec) {
+	dis.nextpc = dis.pc + 1
+	idx := int(dis.program[dis.pc] - 0x22) // intc_0
+	_, dis.err = fmt.Fprintf(dis.out, "%s%s\n", spec.Name, dis.intcComment(idx))
+}
+
+func (dis *disassembleState) intcComment(idx int) string {
+	if idx >= len(dis.intc) {
+		return ""
+	}
+	return fmt.Sprintf(" // %d", dis.intc[idx])
 }
 
 func disBytecblock(dis *disassembleState, spec *OpSpec) {
@@ -1447,6 +1659,7 @@ func disBytecblock(dis *disassembleState, spec *OpSpec) {
 	if dis.err != nil {
 		return
 	}
+	dis.bytec = bytec
 	_, dis.err = fmt.Fprintf(dis.out, "bytecblock")
 	if dis.err != nil {
 		return
@@ -1468,7 +1681,46 @@ func disBytec(dis *disassembleState, spec *OpSpec) {
 		return
 	}
 	dis.nextpc = dis.pc + 2
-	_, dis.err = fmt.Fprintf(dis.out, "bytec %d\n", dis.program[dis.pc+1])
+	idx := dis.program[dis.pc+1]
+	_, dis.err = fmt.Fprintf(dis.out, "bytec %d%s\n", idx, dis.bytecComment(int(idx)))
+}
+
+// disBytecN disassembles bytec_0, bytec_1, ... naming the constant they load
+func disBytecN(dis *disassembleState, spec *OpSpec) {
+	dis.nextpc = dis.pc + 1
+	idx := int(dis.program[dis.pc] - 0x28) // bytec_0
+	_, dis.err = fmt.Fprintf(dis.out, "%s%s\n", spec.Name, dis.bytecComment(idx))
+}
+
+func (dis *disassembleState) bytecComment(idx int) string {
+	if idx >= len(dis.bytec) {
+		return ""
+	}
+	return " // " + guessByteFormat(dis.bytec[idx])
+}
+
+// guessByteFormat renders a byte constant the way it was most likely
+// written: as a string literal if it is printable, as an address if it is
+// 32 bytes long, or as hex otherwise
+func guessByteFormat(bytes []byte) string {
+	if len(bytes) == len(basics.Address{}) {
+		var addr basics.Address
+		copy(addr[:], bytes)
+		return fmt.Sprintf("addr %s", addr.String())
+	}
+	if len(bytes) > 0 && isPrintable(bytes) {
+		return strconv.Quote(string(bytes))
+	}
+	return "0x" + hex.EncodeToString(bytes)
+}
+
+func isPrintable(bytes []byte) bool {
+	for _, b := range bytes {
+		if b < ' ' || b > '~' {
+			return false
+		}
+	}
+	return true
 }
 
 func disArg(dis *disassembleState, spec *OpSpec) {
@@ -1577,6 +1829,9 @@ func disBranch(dis *disassembleState, spec *OpSpec) {
 	dis.nextpc = dis.pc + 3
 	offset := (uint(dis.program[dis.pc+1]) << 8) | uint(dis.program[dis.pc+2])
 	target := int(offset) + dis.pc + 3
+	if dis.version >= backBranchEnabledVersion {
+		target = int(int16(offset)) + dis.pc + 3
+	}
 	label, labelExists := dis.pendingLabels[target]
 	if !labelExists {
 		dis.labelCount++
@@ -1586,6 +1841,36 @@ func disBranch(dis *disassembleState, spec *OpSpec) {
 	_, dis.err = fmt.Fprintf(dis.out, "%s %s\n", spec.Name, label)
 }
 
+func disDig(dis *disassembleState, spec *OpSpec) {
+	lastIdx := dis.pc + 1
+	if len(dis.program) <= lastIdx {
+		missing := lastIdx - len(dis.program) + 1
+		dis.err = fmt.Errorf("unexpected %s opcode end: missing %d bytes", spec.Name, missing)
+		return
+	}
+	n := uint(dis.program[dis.pc+1])
+	dis.nextpc = dis.pc + 2
+	_, dis.err = fmt.Fprintf(dis.out, "dig %d\n", n)
+}
+
+func disPushInt(dis *disassembleState, spec *OpSpec) {
+	var val uint64
+	val, dis.nextpc, dis.err = parsePushInt(dis.program, dis.pc)
+	if dis.err != nil {
+		return
+	}
+	_, dis.err = fmt.Fprintf(dis.out, "pushint %d\n", val)
+}
+
+func disPushBytes(dis *disassembleState, spec *OpSpec) {
+	var val []byte
+	val, dis.nextpc, dis.err = parsePushBytes(dis.program, dis.pc)
+	if dis.err != nil {
+		return
+	}
+	_, dis.err = fmt.Fprintf(dis.out, "pushbytes 0x%s\n", hex.EncodeToString(val))
+}
+
 func disLoad(dis *disassembleState, spec *OpSpec) {
 	lastIdx := dis.pc + 1
 	if len(dis.program) <= lastIdx {
@@ -1642,6 +1927,54 @@ func disAssetParams(dis *disassembleState, spec *OpSpec) {
 	_, dis.err = fmt.Fprintf(dis.out, "asset_params_get %s\n", AssetParamsFieldNames[arg])
 }
 
+func disAppParams(dis *disassembleState, spec *OpSpec) {
+	lastIdx := dis.pc + 1
+	if len(dis.program) <= lastIdx {
+		missing := lastIdx - len(dis.program) + 1
+		dis.err = fmt.Errorf("unexpected %s opcode end: missing %d bytes", spec.Name, missing)
+		return
+	}
+	dis.nextpc = dis.pc + 2
+	arg := dis.program[dis.pc+1]
+	if int(arg) >= len(AppParamsFieldNames) {
+		dis.err = fmt.Errorf("invalid app params arg index %d at pc=%d", arg, dis.pc)
+		return
+	}
+	_, dis.err = fmt.Fprintf(dis.out, "app_params_get %s\n", AppParamsFieldNames[arg])
+}
+
+func disAcctParams(dis *disassembleState, spec *OpSpec) {
+	lastIdx := dis.pc + 1
+	if len(dis.program) <= lastIdx {
+		missing := lastIdx - len(dis.program) + 1
+		dis.err = fmt.Errorf("unexpected %s opcode end: missing %d bytes", spec.Name, missing)
+		return
+	}
+	dis.nextpc = dis.pc + 2
+	arg := dis.program[dis.pc+1]
+	if int(arg) >= len(AcctParamsFieldNames) {
+		dis.err = fmt.Errorf("invalid account params arg index %d at pc=%d", arg, dis.pc)
+		return
+	}
+	_, dis.err = fmt.Fprintf(dis.out, "acct_params_get %s\n", AcctParamsFieldNames[arg])
+}
+
+func disItxnField(dis *disassembleState, spec *OpSpec) {
+	lastIdx := dis.pc + 1
+	if len(dis.program) <= lastIdx {
+		missing := lastIdx - len(dis.program) + 1
+		dis.err = fmt.Errorf("unexpected %s opcode end: missing %d bytes", spec.Name, missing)
+		return
+	}
+	dis.nextpc = dis.pc + 2
+	arg := dis.program[dis.pc+1]
+	if int(arg) >= len(TxnFieldNames) {
+		dis.err = fmt.Errorf("invalid itxn_field arg index %d at pc=%d", arg, dis.pc)
+		return
+	}
+	_, dis.err = fmt.Fprintf(dis.out, "itxn_field %s\n", TxnFieldNames[arg])
+}
+
 type disInfo struct {
 	pcOffset       []PCOffset
 	hasStatefulOps bool
@@ -1663,8 +1996,27 @@ func disassembleInstrumented(program []byte) (text string, ds disInfo, err error
 		text = out.String()
 		return
 	}
-	fmt.Fprintf(dis.out, "// version %d\n", version)
+	fmt.Fprintf(dis.out, "#pragma version %d\n", version)
+	dis.version = version
 	dis.pc = vlen
+	if version >= backBranchEnabledVersion {
+		// labels of backward branches must be known before their targets
+		// are reached, so collect them all in advance
+		pre := disassembleState{program: program, version: version, out: ioutil.Discard, pc: vlen}
+		for pre.pc < len(program) {
+			op := opsByOpcode[version][program[pre.pc]]
+			if op.Name == "" {
+				break
+			}
+			op.dis(&pre, &op)
+			if pre.err != nil {
+				break
+			}
+			pre.pc = pre.nextpc
+		}
+		dis.pendingLabels = pre.pendingLabels
+		dis.labelCount = pre.labelCount
+	}
 	for dis.pc < len(program) {
 		label, hasLabel := dis.pendingLabels[dis.pc]
 		if hasLabel {
@@ -1699,12 +2051,21 @@ func disassembleInstrumented(program []byte) (text string, ds disInfo, err error
 		}
 		dis.pc = dis.nextpc
 	}
+	// branching to the end of the program is allowed since v2
+	if label, hasLabel := dis.pendingLabels[len(program)]; hasLabel {
+		_, dis.err = fmt.Fprintf(dis.out, "%s:\n", label)
+		if dis.err != nil {
+			err = dis.err
+			return
+		}
+	}
 	text = out.String()
 	return
 }
 
 // Disassemble produces a text form of program bytes.
-// AssembleString(Disassemble()) should result in the same program bytes.
+// Branch targets get labels and references to constants are annotated with
+// their values. AssembleString(Disassemble()) results in the same program bytes.
 func Disassemble(program []byte) (text string, err error) {
 	text, _, err = disassembleInstrumented(program)
 	return
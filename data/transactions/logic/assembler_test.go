@@ -194,6 +194,87 @@ txn NumAppArgs
 txn NumAccounts
 txn ApprovalProgram
 txn ClearStateProgram
+pushint 1000
+pushbytes "john"
+swap
+int 1
+select
+dig 1
+assert
+callsub stuff
+b next
+stuff:
+retsub
+next:
+int 1
+int 2
+addw
+divmodw
+int 1
+shl
+int 1
+shr
+sqrt
+bitlen
+int 2
+exp
+int 2
+expw
+pushbytes 0x1234
+int 2
+getbit
+int 2
+int 1
+setbit
+int 1
+getbyte
+int 1
+int 2
+setbyte
+byte 0x01
+byte 0x02
+b+
+byte 0x01
+b-
+byte 0x01
+b/
+byte 0x01
+b*
+byte 0x01
+b<
+byte 0x01
+b>
+byte 0x01
+b<=
+byte 0x01
+b>=
+byte 0x01
+b==
+byte 0x01
+b!=
+byte 0x01
+b%
+byte 0x01
+b|
+byte 0x01
+b&
+byte 0x01
+b^
+b~
+int 4
+bzero
+int 0
+app_params_get AppCreator
+pop
+pop
+int 0
+acct_params_get AcctBalance
+pop
+pop
+itxn_begin
+int pay
+itxn_field TypeEnum
+itxn_submit
 `
 
 // Check that assembly output is stable across time.
@@ -219,7 +300,7 @@ func TestAssemble(t *testing.T) {
 	program, err := AssembleString(bigTestAssembleNonsenseProgram)
 	require.NoError(t, err)
 	// check that compilation is stable over time and we assemble to the same bytes this month that we did last month.
-	expectedBytes, _ := hex.DecodeString("022008b7a60cf8acd19181cf959a12f8acd19181cf951af8acd19181cf15f8acd191810f01020026050212340c68656c6c6f20776f726c6421208dae2087fbba51304eb02b91f656948397a7946390e8cb70fc9ea4d95f92251d024242047465737400320032013202320328292929292a0431003101310231043105310731083109310a310b310c310d310e310f3111311231133114311533000033000133000233000433000533000733000833000933000a33000b33000c33000d33000e33000f3300113300123300133300143300152d2e0102222324252104082209240a220b230c240d250e230f23102311231223132314181b1c2b171615400003290349483403350222231d4a484848482a50512a63222352410003420000432105602105612105270463484821052b62482b642b65484821052b2106662b21056721072b682b6921072105700048482107210571004848361c0037001a0031183119311b311d311e311f")
+	expectedBytes, _ := hex.DecodeString("042009b7a60cf8acd19181cf959a12f8acd19181cf951af8acd19181cf15f8acd191810f0102000426070212340c68656c6c6f20776f726c6421208dae2087fbba51304eb02b91f656948397a7946390e8cb70fc9ea4d95f92251d02424204746573740101010200320032013202320328292929292a0431003101310231043105310731083109310a310b310c310d310e310f3111311231133114311533000033000133000233000433000533000733000833000933000a33000b33000c33000d33000e33000f3300113300123300133300143300152d2e0102222324252104082209240a220b230c240d250e230f23102311231223132314181b1c2b171615400003290349483403350222231d4a484848482a50512a63222352410003420000432105602105612105270463484821052b62482b642b65484821052b2106662b21056721072b682b6921072105700048482107210571004848361c0037001a0031183119311b311d311e311f81e80780046a6f686e4c21054d4b014488000342000189210521061e1f2105902105919293210694210695800212342106532106210554210555210521065627052706a02705a12705a22705a32705a42705a52705a62705a72705a82705a92705aa2705ab2705ac2705adae2108af210772064848210773004848b12105b210b3")
 	if bytes.Compare(expectedBytes, program) != 0 {
 		// this print is for convenience if the program has been changed. the hex string can be copy pasted back in as a new expected result.
 		t.Log(hex.EncodeToString(program))
@@ -694,7 +775,7 @@ func TestAssembleRejectNegJump(t *testing.T) {
 	text := `wat:
 int 1
 bnz wat`
-	for v := uint64(1); v <= AssemblerDefaultVersion; v++ {
+	for v := uint64(1); v < backBranchEnabledVersion; v++ {
 		t.Run(fmt.Sprintf("v=%d", v), func(t *testing.T) {
 			program, err := AssembleStringWithVersion(text, v)
 			require.Error(t, err)
@@ -703,6 +784,28 @@ bnz wat`
 	}
 }
 
+func TestAssembleBackBranch(t *testing.T) {
+	t.Parallel()
+	text := `int 1
+loop:
+int 1
+-
+dup
+bnz loop`
+	program, err := AssembleStringWithVersion(text, backBranchEnabledVersion)
+	require.NoError(t, err)
+	// the offset is relative to the instruction following bnz
+	require.Equal(t, []byte{0x03, 0x20, 0x01, 0x01, 0x22, 0x22, 0x09, 0x49, 0x40, 0xff, 0xfa}, program)
+
+	dis, err := Disassemble(program)
+	require.NoError(t, err)
+	require.Contains(t, dis, "label1:\nintc_0 // 1\n")
+	require.Contains(t, dis, "bnz label1\n")
+	p2, err := AssembleStringWithVersion(dis, backBranchEnabledVersion)
+	require.NoError(t, err)
+	require.Equal(t, program, p2)
+}
+
 func TestAssembleBase64(t *testing.T) {
 	t.Parallel()
 	text := `byte base64 //GWRM+yy3BCavBDXO/FYTNZ6o2Jai5edsMCBdDEz+0=
@@ -756,20 +859,19 @@ done:`
 
 func TestAssembleDisassemble(t *testing.T) {
 	// Specifically constructed program text that should be recreated by Disassemble()
-	// TODO: disassemble to int/byte psuedo-ops instead of raw intcblock/bytecblock/intc/bytec
 	t.Parallel()
-	text := `// version 2
+	text := `#pragma version 4
 intcblock 0 1 2 3 4 5
 bytecblock 0xcafed00d 0x1337 0x2001 0xdeadbeef 0x70077007
-intc_1
-intc_0
+intc_1 // 1
+intc_0 // 0
 +
-intc 4
+intc 4 // 4
 *
-bytec_1
-bytec_0
+bytec_1 // 0x1337
+bytec_0 // 0xcafed00d
 ==
-bytec 4
+bytec 4 // 0x70077007
 len
 +
 arg_0
@@ -786,6 +888,9 @@ global GroupSize
 global LogicSigVersion
 global Round
 global LatestTimestamp
+global CurrentApplicationID
+global CreatorAddress
+global CurrentApplicationAddress
 txn Sender
 txn Fee
 bnz label1
@@ -820,6 +925,28 @@ txn Accounts
 txn NumAccounts
 txn ApprovalProgram
 txn ClearStateProgram
+txn RekeyTo
+txn ConfigAsset
+txn ConfigAssetTotal
+txn ConfigAssetDecimals
+txn ConfigAssetDefaultFrozen
+txn ConfigAssetUnitName
+txn ConfigAssetName
+txn ConfigAssetURL
+txn ConfigAssetMetadataHash
+txn ConfigAssetManager
+txn ConfigAssetReserve
+txn ConfigAssetFreeze
+txn ConfigAssetClawback
+txn FreezeAsset
+txn FreezeAssetAccount
+txn FreezeAssetFrozen
+txn Applications
+txn NumApplications
+txn GlobalNumUint
+txn GlobalNumByteSlice
+txn LocalNumUint
+txn LocalNumByteSlice
 gtxn 12 Fee
 `
 	for _, globalField := range GlobalFieldNames {
@@ -845,7 +972,9 @@ func TestAssembleDisassembleCycle(t *testing.T) {
 	t.Parallel()
 
 	tests := map[uint64]string{
-		2: bigTestAssembleNonsenseProgram,
+		4: bigTestAssembleNonsenseProgram,
+		3: bigTestAssembleNonsenseProgram[:strings.Index(bigTestAssembleNonsenseProgram, "addw")],
+		2: bigTestAssembleNonsenseProgram[:strings.Index(bigTestAssembleNonsenseProgram, "pushint")],
 		1: bigTestAssembleNonsenseProgram[:strings.Index(bigTestAssembleNonsenseProgram, "dup2")],
 	}
 
@@ -855,16 +984,41 @@ func TestAssembleDisassembleCycle(t *testing.T) {
 			require.NoError(t, err)
 			t2, err := Disassemble(program)
 			require.NoError(t, err)
-			p2, err := AssembleStringV2(t2)
+			p2, err := AssembleString(t2)
 			if err != nil {
 				t.Log(t2)
 			}
 			require.NoError(t, err)
-			require.Equal(t, program[1:], p2[1:])
+			require.Equal(t, program, p2)
 		})
 	}
 }
 
+func TestDisassembleConstantsAndLabels(t *testing.T) {
+	// Constants loaded from the constant blocks are named in comments and
+	// branch targets, backward ones included, get labels
+	t.Parallel()
+	text := `#pragma version 4
+intcblock 10 20
+bytecblock 0x68656c6c6f 0x0102 0x11223344556677889900aabbccddeeff11223344556677889900aabbccddeeff
+label1:
+intc_1 // 20
+intc_0 // 10
+bytec_0 // "hello"
+bytec_1 // 0x0102
+bytec_2 // addr CERDGRCVMZ3YRGIAVK54ZXPO74ISEM2EKVTHPCEZACVLXTG5537RFGPQME
+bz label1
+b label2
+pop
+label2:
+`
+	program, err := AssembleString(text)
+	require.NoError(t, err)
+	t2, err := Disassemble(program)
+	require.NoError(t, err)
+	require.Equal(t, text, t2)
+}
+
 func TestAssembleDisassembleErrors(t *testing.T) {
 	source := `txn Sender`
 	program, err := AssembleString(source)
@@ -1025,7 +1179,7 @@ func TestAssembleAsset(t *testing.T) {
 
 func TestDisassembleSingleOp(t *testing.T) {
 	// test ensures no double arg_0 entries in disassebly listing
-	sample := "// version 2\narg_0\n"
+	sample := "#pragma version 4\narg_0\n"
 	program, err := AssembleString(sample)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(program))
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/algorand/go-algorand/config"
@@ -92,10 +93,9 @@ func makeDebugState(cx *evalContext) DebugState {
 		disasm = err.Error()
 	}
 
-	hash := sha256.Sum256(cx.program)
 	// initialize DebuggerState with immutable fields
 	ds := DebugState{
-		ExecID:      hex.EncodeToString(hash[:]),
+		ExecID:      GetProgramID(cx.program),
 		Disassembly: disasm,
 		PCOffset:    dsInfo.pcOffset,
 		GroupIndex:  cx.GroupIndex,
@@ -127,6 +127,48 @@ func makeDebugState(cx *evalContext) DebugState {
 	return ds
 }
 
+// GetProgramID returns the ID of a program, the hex encoded sha256 of its
+// bytes. It is the ExecID of the DebugState of the program.
+func GetProgramID(program []byte) string {
+	hash := sha256.Sum256(program)
+	return hex.EncodeToString(hash[:])
+}
+
+// SetSource replaces the disassembly of the program with the source text it
+// was assembled from, so that lines reported to the debugger refer to the
+// source. sm is the source map returned by AssembleStringWithSourceMap.
+func (d *DebugState) SetSource(source string, sm SourceMap) error {
+	locations, err := sm.PCToLocation()
+	if err != nil {
+		return err
+	}
+
+	lines := strings.SplitAfter(source, "\n")
+	lineOffsets := make([]int, len(lines))
+	for i := 1; i < len(lines); i++ {
+		lineOffsets[i] = lineOffsets[i-1] + len(lines[i-1])
+	}
+
+	pcs := make([]int, 0, len(locations))
+	for pc := range locations {
+		pcs = append(pcs, pc)
+	}
+	sort.Ints(pcs)
+
+	pcOffset := make([]PCOffset, 0, len(pcs))
+	for _, pc := range pcs {
+		loc := locations[pc]
+		if loc.Line < 0 || loc.Line >= len(lines) || loc.Column < 0 || loc.Column >= len(lines[loc.Line]) {
+			return fmt.Errorf("source map location %d:%d of pc %d is out of the source", loc.Line, loc.Column, pc)
+		}
+		pcOffset = append(pcOffset, PCOffset{pc, lineOffsets[loc.Line] + loc.Column})
+	}
+
+	d.Disassembly = source
+	d.PCOffset = pcOffset
+	return nil
+}
+
 // LineToPC converts line to pc
 // Return 0 on unsuccess
 func (d *DebugState) LineToPC(line int) int {
@@ -156,16 +198,18 @@ func (d *DebugState) PCToLine(pc int) int {
 	}
 
 	offset := 0
+	found := false
 	for i := 0; i < len(d.PCOffset); i++ {
 		if d.PCOffset[i].PC >= pc {
 			offset = d.PCOffset[i].Offset
+			found = true
 			break
 		}
 	}
 
 	one := 1
 	// handle end of the program
-	if offset == 0 {
+	if !found {
 		offset = d.PCOffset[len(d.PCOffset)-1].Offset
 		one = 0
 	}
@@ -125,3 +125,40 @@ func TestDebuggerHook(t *testing.T) {
 	require.Greater(t, testDbg.update, 1)
 	require.Equal(t, 1, len(testDbg.state.Stack))
 }
+
+func TestDebugStateSetSource(t *testing.T) {
+	source := `int 1
+bnz skip
+  err
+skip:
+  int 2 // indented
+`
+	program, sm, err := AssembleStringWithSourceMap(source)
+	require.NoError(t, err)
+
+	testDbg := testDbgHook{}
+	ep := defaultEvalParams(nil, nil)
+	ep.Debugger = &testDbg
+	pass, err := Eval(program, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	state := testDbg.state
+	require.Equal(t, GetProgramID(program), state.ExecID)
+	require.NotEqual(t, source, state.Disassembly)
+
+	err = state.SetSource(source, sm)
+	require.NoError(t, err)
+	require.Equal(t, source, state.Disassembly)
+
+	// version and intcblock 1 2 take the first 5 bytes
+	for _, pcLine := range []struct{ pc, line int }{{5, 0}, {6, 1}, {9, 2}, {10, 4}} {
+		require.Equal(t, pcLine.line, state.PCToLine(pcLine.pc), "pc %d", pcLine.pc)
+	}
+	require.Equal(t, 9, state.LineToPC(2))
+	require.Equal(t, 10, state.LineToPC(4))
+
+	sm.Version = 2
+	err = state.SetSource(source, sm)
+	require.Error(t, err)
+}
@@ -64,6 +64,34 @@ var opDocList = []stringString{
 	{"^", "A bitwise-xor B"},
 	{"~", "bitwise invert value X"},
 	{"mulw", "A times B out to 128-bit long result as low (top) and high uint64 values on the stack"},
+	{"addw", "A plus B out to 128-bit long result as sum (top) and carry-bit uint64 values on the stack"},
+	{"divmodw", "Pop four uint64 values. The deepest two are interpreted as a uint128 dividend (deepest value is high word), the top two are interpreted as a uint128 divisor. Four uint64 values are pushed to the stack. The deepest two are the quotient (deeper value is the high uint64). The top two are the remainder, low bits on top."},
+	{"shl", "A times 2^B, modulo 2^64"},
+	{"shr", "A divided by 2^B"},
+	{"sqrt", "The largest integer B such that B^2 <= X"},
+	{"bitlen", "The highest set bit in X. If X is a byte-array, it is interpreted as a big-endian unsigned integer. bitlen of 0 is 0, bitlen of 8 is 4"},
+	{"exp", "A raised to the Bth power. Panic if A == B == 0 and on overflow"},
+	{"expw", "A raised to the Bth power as a 128-bit long result as low (top) and high uint64 values on the stack. Panic if A == B == 0 or if the results exceeds 2^128-1"},
+	{"getbit", "pop a target A (integer or byte-array), and index B. Push the Bth bit of A."},
+	{"setbit", "pop a target A, index B, and bit C. Set the Bth bit of A to C, and push the result"},
+	{"getbyte", "pop a byte-array A and integer B. Extract the Bth byte of A and push it as an integer"},
+	{"setbyte", "pop a byte-array A, integer B, and small integer C (between 0..255). Set the Bth byte of A to C, and push the result"},
+	{"b+", "A plus B, where A and B are byte-arrays interpreted as big-endian unsigned integers"},
+	{"b-", "A minus B, where A and B are byte-arrays interpreted as big-endian unsigned integers. Panic on underflow."},
+	{"b/", "A divided by B, where A and B are byte-arrays interpreted as big-endian unsigned integers. Panic if B is zero."},
+	{"b*", "A times B, where A and B are byte-arrays interpreted as big-endian unsigned integers."},
+	{"b<", "A is less than B, where A and B are byte-arrays interpreted as big-endian unsigned integers => { 0 or 1}"},
+	{"b>", "A is greater than B, where A and B are byte-arrays interpreted as big-endian unsigned integers => { 0 or 1}"},
+	{"b<=", "A is less than or equal to B, where A and B are byte-arrays interpreted as big-endian unsigned integers => { 0 or 1}"},
+	{"b>=", "A is greater than or equal to B, where A and B are byte-arrays interpreted as big-endian unsigned integers => { 0 or 1}"},
+	{"b==", "A is equal to B, where A and B are byte-arrays interpreted as big-endian unsigned integers => { 0 or 1}"},
+	{"b!=", "A is not equal to B, where A and B are byte-arrays interpreted as big-endian unsigned integers => { 0 or 1}"},
+	{"b%", "A modulo B, where A and B are byte-arrays interpreted as big-endian unsigned integers. Panic if B is zero."},
+	{"b|", "A bitwise-or B, where A and B are byte-arrays, zero-left extended to the greater of their lengths"},
+	{"b&", "A bitwise-and B, where A and B are byte-arrays, zero-left extended to the greater of their lengths"},
+	{"b^", "A bitwise-xor B, where A and B are byte-arrays, zero-left extended to the greater of their lengths"},
+	{"b~", "X with all bits inverted"},
+	{"bzero", "push a byte-array of length X, containing all zero bytes"},
 	{"intcblock", "load block of uint64 constants"},
 	{"intc", "push value from uint64 constants to stack by index into constants"},
 	{"intc_0", "push constant 0 from intcblock to stack"},
@@ -92,9 +120,17 @@ var opDocList = []stringString{
 	{"bz", "branch if value X is zero"},
 	{"b", "branch unconditionally to offset"},
 	{"return", "use last value on stack as success value; end"},
+	{"assert", "immediately fail unless value X is a non-zero number"},
 	{"pop", "discard value X from stack"},
 	{"dup", "duplicate last value on stack"},
 	{"dup2", "duplicate two last values on stack: A, B -> A, B, A, B"},
+	{"dig", "push the Nth value from the top of the stack. dig 0 is equivalent to dup"},
+	{"swap", "swaps two last values on stack: A, B -> B, A"},
+	{"select", "selects one of two values based on top-of-stack: A, B, C -> (if C != 0 then B else A)"},
+	{"pushbytes", "push the following program bytes to the stack"},
+	{"pushint", "push immediate UINT to the stack as an integer"},
+	{"callsub", "branch unconditionally to TARGET, saving the next instruction on the call stack"},
+	{"retsub", "pop the top instruction from the call stack and branch to it"},
 	{"concat", "pop two byte strings A and B and join them, push the result"},
 	{"substring", "pop a byte string X. For immediate values in 0..255 N and M: extract a range of bytes from it starting at N up to but not including M, push the substring result"},
 	{"substring3", "pop a byte string A and two integers B and C. Extract a range of bytes from A starting at B up to but not including C, push the substring result"},
@@ -110,6 +146,11 @@ var opDocList = []stringString{
 	{"app_global_del", "delete key A from a global state of the current application"},
 	{"asset_holding_get", "read from account specified by Txn.Accounts[A] and asset B holding field X (imm arg) => {0 or 1 (top), value}"},
 	{"asset_params_get", "read from account specified by Txn.Accounts[A] and asset B params field X (imm arg) => {0 or 1 (top), value}"},
+	{"app_params_get", "read from application A params field X (imm arg) => {0 or 1 (top), value}"},
+	{"acct_params_get", "read from account specified by Txn.Accounts[A] params field X (imm arg) => {0 or 1 (top), value}"},
+	{"itxn_begin", "begin preparation of a new inner transaction"},
+	{"itxn_field", "set field F (imm arg) of the current inner transaction to A"},
+	{"itxn_submit", "submit the current inner transaction"},
 }
 
 var opDocByName map[string]string
@@ -134,14 +175,21 @@ var opcodeImmediateNoteList = []stringString{
 	{"txna", "{uint8 transaction field index}{uint8 transaction field array index}"},
 	{"gtxna", "{uint8 transaction group index}{uint8 transaction field index}{uint8 transaction field array index}"},
 	{"global", "{uint8 global field index}"},
-	{"bnz", "{0..0x7fff forward branch offset, big endian}"},
-	{"bz", "{0..0x7fff forward branch offset, big endian}"},
-	{"b", "{0..0x7fff forward branch offset, big endian}"},
+	{"bnz", "{int16 branch offset, big endian. (negative offsets are illegal before v3)}"},
+	{"bz", "{int16 branch offset, big endian. (negative offsets are illegal before v3)}"},
+	{"b", "{int16 branch offset, big endian. (negative offsets are illegal before v3)}"},
+	{"callsub", "{int16 branch offset, big endian}"},
+	{"dig", "{uint8 depth}"},
+	{"pushbytes", "{varuint length} {bytes}"},
+	{"pushint", "{varuint int}"},
 	{"load", "{uint8 position in scratch space to load from}"},
 	{"store", "{uint8 position in scratch space to store to}"},
 	{"substring", "{uint8 start position}{uint8 end position}"},
 	{"asset_holding_get", "{uint8 asset holding field index}"},
 	{"asset_params_get", "{uint8 asset params field index}"},
+	{"app_params_get", "{uint8 app params field index}"},
+	{"acct_params_get", "{uint8 account params field index}"},
+	{"itxn_field", "{uint8 transaction field index}"},
 }
 var opcodeImmediateNotes map[string]string
 
@@ -156,12 +204,21 @@ func OpImmediateNote(opName string) string {
 // further documentation on the function of the opcode
 var opDocExtraList = []stringString{
 	{"ed25519verify", "The 32 byte public key is the last element on the stack, preceded by the 64 byte signature at the second-to-last element on the stack, preceded by the data which was signed at the third-to-last element on the stack."},
-	{"bnz", "The `bnz` instruction opcode 0x40 is followed by two immediate data bytes which are a high byte first and low byte second which together form a 16 bit offset which the instruction may branch to. For a bnz instruction at `pc`, if the last element of the stack is not zero then branch to instruction at `pc + 3 + N`, else proceed to next instruction at `pc + 3`. Branch targets must be well aligned instructions. (e.g. Branching to the second byte of a 2 byte op will be rejected.) Branch offsets are currently limited to forward branches only, 0-0x7fff. A future expansion might make this a signed 16 bit integer allowing for backward branches and looping.\n\nAt LogicSigVersion 2 it became allowed to branch to the end of the program exactly after the last instruction, removing the need for a last instruction or no-op as a branch target at the end. Branching beyond that may still fail the program."},
+	{"bnz", "The `bnz` instruction opcode 0x40 is followed by two immediate data bytes which are a high byte first and low byte second which together form a 16 bit offset which the instruction may branch to. For a bnz instruction at `pc`, if the last element of the stack is not zero then branch to instruction at `pc + 3 + N`, else proceed to next instruction at `pc + 3`. Branch targets must be well aligned instructions. (e.g. Branching to the second byte of a 2 byte op will be rejected.) Branch offsets are limited to forward branches only, 0-0x7fff, until v3. Starting with v3 the offset is a signed 16 bit integer allowing for backward branches and looping.\n\nAt LogicSigVersion 2 it became allowed to branch to the end of the program exactly after the last instruction, removing the need for a last instruction or no-op as a branch target at the end. Branching beyond that may still fail the program."},
 	{"bz", "See `bnz` for details on how branches work. `bz` inverts the behavior of `bnz`."},
 	{"b", "See `bnz` for details on how branches work. `b` always jumps to the offset."},
+	{"callsub", "The call stack is separate from the data stack. Only `callsub` and `retsub` manipulate it."},
+	{"retsub", "The call stack is separate from the data stack. Only `callsub` and `retsub` manipulate it."},
+	{"pushbytes", "pushbytes args are not added to the bytecblock during assembly processes"},
+	{"pushint", "pushint args are not added to the intcblock during assembly processes"},
 	{"intcblock", "`intcblock` loads following program bytes into an array of integer constants in the evaluator. These integer constants can be referred to by `intc` and `intc_*` which will push the value onto the stack. Subsequent calls to `intcblock` reset and replace the integer constants available to the script."},
 	{"bytecblock", "`bytecblock` loads the following program bytes into an array of byte string constants in the evaluator. These constants can be referred to by `bytec` and `bytec_*` which will push the value onto the stack. Subsequent calls to `bytecblock` reset and replace the bytes constants available to the script."},
 	{"*", "Overflow is an error condition which halts execution and fails the transaction. Full precision is available from `mulw`."},
+	{"shl", "Bits shifted out of the uint64 are lost. Shifting by 64 or more is an error."},
+	{"shr", "Shifting by 64 or more is an error."},
+	{"getbit", "see explanation of bit ordering in setbit"},
+	{"setbit", "When A is a uint64, index 0 is the least significant bit. Setting bit 3 to 1 on the integer 0 yields 8, or 2^3. When A is a byte array, index 0 is the leftmost bit of the leftmost byte. Setting bits 0 through 11 to 1 in a 4-byte-array of 0s yields the byte array 0xfff00000. Setting bit 3 to 1 on the 1-byte-array 0x00 yields the byte array 0x10."},
+	{"b+", "The arguments of byte-array math are limited to 64 bytes. The result is the minimal big-endian representation, so a zero result is the empty byte-array."},
 	{"txn", "FirstValidTime causes the program to fail. The field is reserved for future use."},
 	{"gtxn", "for notes on transaction fields available, see `txn`. If this transaction is _i_ in the group, `gtxn i field` is equivalent to `txn field`."},
 	{"btoi", "`btoi` panics if the input is longer than 8 bytes."},
@@ -176,6 +233,11 @@ var opDocExtraList = []stringString{
 	{"app_global_del", "params: state key."},
 	{"asset_holding_get", "params: account index, asset id. Return: did_exist flag (1 if exist and 0 otherwise), value."},
 	{"asset_params_get", "params: account index, asset id. Return: did_exist flag (1 if exist and 0 otherwise), value."},
+	{"app_params_get", "params: application id, 0 for the current application. Return: did_exist flag (1 if exist and 0 otherwise), value."},
+	{"acct_params_get", "params: account index. Return: did_exist flag (1 if the account has a nonzero balance and 0 otherwise), value."},
+	{"itxn_begin", "The new transaction is sent from the application account, with Fee set to the minimum transaction fee and FirstValid and LastValid copied from the application call. `itxn_begin` fails if a previous inner transaction has not been submitted, if MaxInnerTransactions have already been submitted, or during a ClearStateProgram."},
+	{"itxn_field", "Only fields of pay, axfer and acfg transactions may be set, along with Type, TypeEnum, Sender, Fee and Note. Addresses must be 32 byte arrays. `itxn_field` fails if A is of the wrong type for the field."},
+	{"itxn_submit", "The transaction Type must have been set. Submitted transactions are applied in order after the ApprovalProgram approves, and the whole application call fails if any of them fails."},
 }
 
 var opDocExtras map[string]string
@@ -197,10 +259,12 @@ type OpGroup struct {
 
 // OpGroupList is groupings of ops for documentation purposes.
 var OpGroupList = []OpGroup{
-	{"Arithmetic", []string{"sha256", "keccak256", "sha512_256", "ed25519verify", "+", "-", "/", "*", "<", ">", "<=", ">=", "&&", "||", "==", "!=", "!", "len", "itob", "btoi", "%", "|", "&", "^", "~", "mulw", "concat", "substring", "substring3"}},
-	{"Loading Values", []string{"intcblock", "intc", "intc_0", "intc_1", "intc_2", "intc_3", "bytecblock", "bytec", "bytec_0", "bytec_1", "bytec_2", "bytec_3", "arg", "arg_0", "arg_1", "arg_2", "arg_3", "txn", "gtxn", "txna", "gtxna", "global", "load", "store"}},
-	{"Flow Control", []string{"err", "bnz", "bz", "b", "return", "pop", "dup", "dup2"}},
-	{"State Access", []string{"balance", "app_opted_in", "app_local_get", "app_local_get_ex", "app_global_get", "app_global_get_ex", "app_local_put", "app_global_put", "app_local_del", "app_global_del", "asset_holding_get", "asset_params_get"}},
+	{"Arithmetic", []string{"sha256", "keccak256", "sha512_256", "ed25519verify", "+", "-", "/", "*", "<", ">", "<=", ">=", "&&", "||", "==", "!=", "!", "len", "itob", "btoi", "%", "|", "&", "^", "~", "mulw", "addw", "divmodw", "shl", "shr", "sqrt", "bitlen", "exp", "expw", "concat", "substring", "substring3", "getbit", "setbit", "getbyte", "setbyte"}},
+	{"Byte Array Arithmetic", []string{"b+", "b-", "b/", "b*", "b<", "b>", "b<=", "b>=", "b==", "b!=", "b%", "b|", "b&", "b^", "b~", "bzero"}},
+	{"Loading Values", []string{"intcblock", "intc", "intc_0", "intc_1", "intc_2", "intc_3", "pushint", "bytecblock", "bytec", "bytec_0", "bytec_1", "bytec_2", "bytec_3", "pushbytes", "arg", "arg_0", "arg_1", "arg_2", "arg_3", "txn", "gtxn", "txna", "gtxna", "global", "load", "store"}},
+	{"Flow Control", []string{"err", "bnz", "bz", "b", "return", "pop", "dup", "dup2", "dig", "swap", "select", "assert", "callsub", "retsub"}},
+	{"State Access", []string{"balance", "app_opted_in", "app_local_get", "app_local_get_ex", "app_global_get", "app_global_get_ex", "app_local_put", "app_global_put", "app_local_del", "app_global_del", "asset_holding_get", "asset_params_get", "app_params_get", "acct_params_get"}},
+	{"Inner Transactions", []string{"itxn_begin", "itxn_field", "itxn_submit"}},
 }
 
 // OpCost returns the relative cost score for an op
@@ -304,6 +368,28 @@ var txnFieldDocList = []stringString{
 	{"NumAccounts", "Number of Accounts"},
 	{"ApprovalProgram", "Approval program"},
 	{"ClearStateProgram", "Clear state program"},
+	{"RekeyTo", "32 byte Sender's new AuthAddr"},
+	{"ConfigAsset", "Asset ID in asset config transaction"},
+	{"ConfigAssetTotal", "Total number of units of this asset created"},
+	{"ConfigAssetDecimals", "Number of digits to display after the decimal place when displaying the asset"},
+	{"ConfigAssetDefaultFrozen", "Whether the asset's slots are frozen by default or not, 0 or 1"},
+	{"ConfigAssetUnitName", "Unit name of the asset"},
+	{"ConfigAssetName", "The asset name"},
+	{"ConfigAssetURL", "URL"},
+	{"ConfigAssetMetadataHash", "32 byte commitment to some unspecified asset metadata"},
+	{"ConfigAssetManager", "32 byte address"},
+	{"ConfigAssetReserve", "32 byte address"},
+	{"ConfigAssetFreeze", "32 byte address"},
+	{"ConfigAssetClawback", "32 byte address"},
+	{"FreezeAsset", "Asset ID being frozen or un-frozen"},
+	{"FreezeAssetAccount", "32 byte address of the account whose asset slot is being frozen or un-frozen"},
+	{"FreezeAssetFrozen", "The new frozen value, 0 or 1"},
+	{"Applications", "Applications listed in the ApplicationCall transaction. Index 0 is the called application"},
+	{"NumApplications", "Number of Applications"},
+	{"GlobalNumUint", "Number of global state integers in ApplicationCall"},
+	{"GlobalNumByteSlice", "Number of global state byteslices in ApplicationCall"},
+	{"LocalNumUint", "Number of local state integers in ApplicationCall"},
+	{"LocalNumByteSlice", "Number of local state byteslices in ApplicationCall"},
 }
 
 // TxnFieldDocs are notes on fields available by `txn` and `gtxn`
@@ -323,6 +409,9 @@ var globalFieldDocList = []stringString{
 	{"LogicSigVersion", "Maximum supported TEAL version"},
 	{"Round", "Current round number"},
 	{"LatestTimestamp", "Last confirmed block UNIX timestamp. Fails if negative"},
+	{"CurrentApplicationID", "ID of current application executing"},
+	{"CreatorAddress", "Address of the creator of the current application"},
+	{"CurrentApplicationAddress", "Address of the account controlled by the current application"},
 }
 
 // globalFieldDocs are notes on fields available in `global`
@@ -381,9 +470,33 @@ var assetParamsFieldDocList = []stringString{
 // AssetParamsFieldDocs are notes on fields available in `asset_params_get`
 var AssetParamsFieldDocs map[string]string
 
+var appParamsFieldDocList = []stringString{
+	{"AppApprovalProgramHash", "SHA512_256 hash of the approval program"},
+	{"AppClearStateProgramHash", "SHA512_256 hash of the clear state program"},
+	{"AppGlobalNumUint", "Number of uint64 values allowed in global state"},
+	{"AppGlobalNumByteSlice", "Number of byte array values allowed in global state"},
+	{"AppLocalNumUint", "Number of uint64 values allowed in local state"},
+	{"AppLocalNumByteSlice", "Number of byte array values allowed in local state"},
+	{"AppCreator", "Creator address"},
+}
+
+// AppParamsFieldDocs are notes on fields available in `app_params_get`
+var AppParamsFieldDocs map[string]string
+
+var acctParamsFieldDocList = []stringString{
+	{"AcctBalance", "Account balance in microalgos"},
+	{"AcctMinBalance", "Minimum required balance for account, in microalgos"},
+	{"AcctAuthAddr", "Address the account is rekeyed to, or the zero address"},
+}
+
+// AcctParamsFieldDocs are notes on fields available in `acct_params_get`
+var AcctParamsFieldDocs map[string]string
+
 func init() {
 	txnFieldDocs = stringStringListToMap(txnFieldDocList)
 	globalFieldDocs = stringStringListToMap(globalFieldDocList)
 	AssetHoldingFieldDocs = stringStringListToMap(assetHoldingFieldDocList)
 	AssetParamsFieldDocs = stringStringListToMap(assetParamsFieldDocList)
+	AppParamsFieldDocs = stringStringListToMap(appParamsFieldDocList)
+	AcctParamsFieldDocs = stringStringListToMap(acctParamsFieldDocList)
 }
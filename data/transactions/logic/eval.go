@@ -27,6 +27,7 @@ import (
 	"io"
 	"math"
 	"math/big"
+	"math/bits"
 	"runtime"
 	"sort"
 	"strings"
@@ -107,6 +108,9 @@ type LedgerForLogic interface {
 	AppLocalState(addr basics.Address, appIdx basics.AppIndex) (basics.TealKeyValue, error)
 	AssetHolding(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetHolding, error)
 	AssetParams(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetParams, error)
+	AppParams(appIdx basics.AppIndex) (basics.AppParams, basics.Address, error)
+	MinBalance(addr basics.Address, proto *config.ConsensusParams) (basics.MicroAlgos, error)
+	Authorizer(addr basics.Address) (basics.Address, error)
 	ApplicationID() basics.AppIndex
 }
 
@@ -201,10 +205,17 @@ type evalContext struct {
 	stepCount int
 	cost      int
 
+	// return addresses pushed by callsub and popped by retsub
+	callstack []int
+
 	// Ordered set of pc values that a branch could go to.
 	// If Check pc skips a target, the source branch was invalid!
 	branchTargets []int
 
+	// Set of pc values where instructions start. Backward branches
+	// found by Check must go to one of them.
+	instructionStarts map[int]bool
+
 	programHashCached crypto.Digest
 	txidCache         map[int]transactions.Txid
 
@@ -214,6 +225,11 @@ type evalContext struct {
 	readOnlyLocalStates  map[ckey]basics.TealKeyValue
 	appEvalDelta         basics.EvalDelta
 
+	// inner transactions submitted by the program, and the one being built
+	// between itxn_begin and itxn_submit
+	innerTxns       []transactions.SignedTxn
+	pendingInnerTxn *transactions.Transaction
+
 	// Stores state & disassembly for the optional debugger
 	debugState DebugState
 }
@@ -270,6 +286,13 @@ var errTooManyArgs = errors.New("LogicSig has too many arguments")
 
 // EvalStateful executes stateful TEAL program
 func EvalStateful(program []byte, params EvalParams) (pass bool, delta basics.EvalDelta, err error) {
+	pass, delta, _, err = EvalStatefulInner(program, params)
+	return
+}
+
+// EvalStatefulInner executes stateful TEAL program and also returns the inner
+// transactions it submitted, in submission order
+func EvalStatefulInner(program []byte, params EvalParams) (pass bool, delta basics.EvalDelta, innerTxns []transactions.SignedTxn, err error) {
 	var cx evalContext
 	cx.EvalParams = params
 	cx.runModeFlags = runModeApplication
@@ -293,6 +316,9 @@ func EvalStateful(program []byte, params EvalParams) (pass bool, delta basics.Ev
 
 	// Evaluate the program
 	pass, err = eval(program, &cx)
+	if err == nil && cx.pendingInnerTxn != nil {
+		pass, err = false, errors.New("itxn_begin without itxn_submit")
+	}
 
 	// Fill in state deltas
 	for _, idxCow := range cx.localStateCows {
@@ -301,7 +327,7 @@ func EvalStateful(program []byte, params EvalParams) (pass bool, delta basics.Ev
 		}
 	}
 
-	return pass, cx.appEvalDelta, err
+	return pass, cx.appEvalDelta, cx.innerTxns, err
 }
 
 // Eval checks to see if a transaction passes logic
@@ -369,9 +395,9 @@ func eval(program []byte, cx *evalContext) (pass bool, err error) {
 		return false, cx.err
 	}
 
-	// Currently no TEAL version knows about the RekeyTo field, but this
-	// may change in a future TEAL version
-	if !cx.EvalParams.Txn.Txn.RekeyTo.IsZero() {
+	// Programs written before rekeying was available might not check the
+	// RekeyTo field, so they may not approve a transaction that sets it
+	if version < rekeyingEnabledVersion && !cx.EvalParams.Txn.Txn.RekeyTo.IsZero() {
 		cx.err = fmt.Errorf("program version %d doesn't allow transactions with nonzero RekeyTo field", version)
 		return false, cx.err
 	}
@@ -402,7 +428,12 @@ func eval(program []byte, cx *evalContext) (pass bool, err error) {
 
 		cx.step()
 		cx.stepCount++
-		if cx.stepCount > len(cx.program) {
+		if cx.version >= backBranchEnabledVersion {
+			// loops are allowed, the execution is limited by its cost
+			if cx.err == nil && cx.cost > cx.budget() {
+				cx.err = fmt.Errorf("dynamic cost budget of %d exceeded, executing pc=%d", cx.budget(), cx.pc)
+			}
+		} else if cx.stepCount > len(cx.program) {
 			return false, errLoopDetected
 		}
 	}
@@ -480,8 +511,14 @@ func check(program []byte, params EvalParams) (cost int, err error) {
 	cx.pc = vlen
 	cx.EvalParams = params
 	cx.program = program
+	if version >= backBranchEnabledVersion {
+		cx.instructionStarts = make(map[int]bool)
+	}
 	for (cx.err == nil) && (cx.pc < len(cx.program)) {
 		prevpc := cx.pc
+		if cx.instructionStarts != nil {
+			cx.instructionStarts[prevpc] = true
+		}
 		cost += cx.checkStep()
 		if cx.pc <= prevpc {
 			err = fmt.Errorf("pc did not advance, stuck at %d", cx.pc)
@@ -495,6 +532,14 @@ func check(program []byte, params EvalParams) (cost int, err error) {
 	return
 }
 
+// budget returns the maximum cost of a program execution in the current mode
+func (cx *evalContext) budget() int {
+	if cx.runModeFlags == runModeApplication {
+		return cx.Proto.MaxAppProgramCost
+	}
+	return int(cx.Proto.LogicSigMaxCost)
+}
+
 func opCompat(expected, got StackType) bool {
 	if expected == StackAny {
 		return true
@@ -768,6 +813,162 @@ func opMulw(cx *evalContext) {
 	cx.stack[last].Uint = low
 }
 
+func opAddw(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	sum, carry := bits.Add64(cx.stack[prev].Uint, cx.stack[last].Uint, 0)
+	cx.stack[prev].Uint = carry
+	cx.stack[last].Uint = sum
+}
+
+// wideInt builds the 128 bit value hi:lo
+func wideInt(hi, lo uint64) *big.Int {
+	var v, low big.Int
+	v.SetUint64(hi)
+	v.Lsh(&v, 64)
+	low.SetUint64(lo)
+	return v.Or(&v, &low)
+}
+
+// splitWide is the inverse of wideInt, v must fit in 128 bits
+func splitWide(v *big.Int) (hi, lo uint64) {
+	var maxUint, low, high big.Int
+	maxUint.SetUint64(math.MaxUint64)
+	low.And(v, &maxUint)
+	high.Rsh(v, 64)
+	return high.Uint64(), low.Uint64()
+}
+
+func opDivModw(cx *evalContext) {
+	loDivisor := len(cx.stack) - 1
+	hiDivisor := loDivisor - 1
+	loDividend := hiDivisor - 1
+	hiDividend := loDividend - 1
+
+	if cx.stack[hiDivisor].Uint == 0 && cx.stack[loDivisor].Uint == 0 {
+		cx.err = errors.New("divmodw 0")
+		return
+	}
+	dividend := wideInt(cx.stack[hiDividend].Uint, cx.stack[loDividend].Uint)
+	divisor := wideInt(cx.stack[hiDivisor].Uint, cx.stack[loDivisor].Uint)
+	var quo, rem big.Int
+	quo.QuoRem(dividend, divisor, &rem)
+	cx.stack[hiDividend].Uint, cx.stack[loDividend].Uint = splitWide(&quo)
+	cx.stack[hiDivisor].Uint, cx.stack[loDivisor].Uint = splitWide(&rem)
+}
+
+func opShiftLeft(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	if cx.stack[last].Uint > 63 {
+		cx.err = fmt.Errorf("shl arg too big, (%d)", cx.stack[last].Uint)
+		return
+	}
+	cx.stack[prev].Uint = cx.stack[prev].Uint << cx.stack[last].Uint
+	cx.stack = cx.stack[:last]
+}
+
+func opShiftRight(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	if cx.stack[last].Uint > 63 {
+		cx.err = fmt.Errorf("shr arg too big, (%d)", cx.stack[last].Uint)
+		return
+	}
+	cx.stack[prev].Uint = cx.stack[prev].Uint >> cx.stack[last].Uint
+	cx.stack = cx.stack[:last]
+}
+
+func opSqrt(cx *evalContext) {
+	last := len(cx.stack) - 1
+	var v big.Int
+	v.SetUint64(cx.stack[last].Uint)
+	cx.stack[last].Uint = v.Sqrt(&v).Uint64()
+}
+
+func opBitLen(cx *evalContext) {
+	last := len(cx.stack) - 1
+	if cx.stack[last].argType() == StackUint64 {
+		cx.stack[last].Uint = uint64(bits.Len64(cx.stack[last].Uint))
+		return
+	}
+	length := 0
+	for i, b := range cx.stack[last].Bytes {
+		if b != 0 {
+			length = bits.Len8(b) + 8*(len(cx.stack[last].Bytes)-i-1)
+			break
+		}
+	}
+	cx.stack[last].Bytes = nil
+	cx.stack[last].Uint = uint64(length)
+}
+
+func opExpImpl(base uint64, exp uint64) (uint64, error) {
+	if base == 0 && exp == 0 {
+		return 0, errors.New("0^0 is undefined")
+	}
+	if exp == 0 || base == 1 {
+		return 1, nil
+	}
+	if base == 0 {
+		return 0, nil
+	}
+	// base is at least 2, so anything beyond 2^63 overflows
+	if exp > 63 {
+		return 0, fmt.Errorf("%d^%d overflow", base, exp)
+	}
+	answer := base
+	for i := uint64(1); i < exp; i++ {
+		next := answer * base
+		if next/base != answer {
+			return 0, fmt.Errorf("%d^%d overflow", base, exp)
+		}
+		answer = next
+	}
+	return answer, nil
+}
+
+func opExp(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	cx.stack[prev].Uint, cx.err = opExpImpl(cx.stack[prev].Uint, cx.stack[last].Uint)
+	cx.stack = cx.stack[:last]
+}
+
+func opExpwImpl(base uint64, exp uint64) (*big.Int, error) {
+	if base == 0 && exp == 0 {
+		return nil, errors.New("0^0 is undefined")
+	}
+	var answer big.Int
+	if exp == 0 || base == 1 {
+		return answer.SetUint64(1), nil
+	}
+	if base == 0 {
+		return &answer, nil
+	}
+	// base is at least 2, so anything beyond 2^127 overflows
+	if exp > 127 {
+		return nil, fmt.Errorf("%d^%d overflow", base, exp)
+	}
+	var b, e big.Int
+	answer.Exp(b.SetUint64(base), e.SetUint64(exp), nil)
+	if answer.BitLen() > 128 {
+		return nil, fmt.Errorf("%d^%d overflow", base, exp)
+	}
+	return &answer, nil
+}
+
+func opExpw(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	answer, err := opExpwImpl(cx.stack[prev].Uint, cx.stack[last].Uint)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack[prev].Uint, cx.stack[last].Uint = splitWide(answer)
+}
+
 func opLt(cx *evalContext) {
 	last := len(cx.stack) - 1
 	prev := last - 1
@@ -1040,42 +1241,60 @@ func opArg3(cx *evalContext) {
 	opArgN(cx, 3)
 }
 
-// checks any branch that is {op} {int16 be offset}
-func checkBranch(cx *evalContext) int {
+// branchTarget decodes the {int16 be offset} immediate of a branch and
+// returns the pc it points to. Offsets are signed starting with
+// backBranchEnabledVersion.
+func branchTarget(cx *evalContext) (int, error) {
 	offset := (uint(cx.program[cx.pc+1]) << 8) | uint(cx.program[cx.pc+2])
-	if offset > 0x7fff {
-		cx.err = fmt.Errorf("branch offset %x too large", offset)
-		return 1
+	if offset > 0x7fff && cx.version < backBranchEnabledVersion {
+		return 0, fmt.Errorf("%s offset %x too large", opsByOpcode[cx.version][cx.program[cx.pc]].Name, offset)
 	}
-	cx.nextpc = cx.pc + 3
-	target := cx.nextpc + int(offset)
+	target := cx.pc + 3 + int(int16(offset))
 	var branchTooFar bool
 	if cx.version >= 2 {
 		// branching to exactly the end of the program (target == len(cx.program)), the next pc after the last instruction, is okay and ends normally
-		branchTooFar = target > len(cx.program)
+		branchTooFar = target > len(cx.program) || target < 0
 	} else {
 		branchTooFar = target >= len(cx.program)
 	}
 	if branchTooFar {
-		cx.err = errors.New("branch target beyond end of program")
+		return 0, errors.New("branch target beyond end of program")
+	}
+	return target, nil
+}
+
+// checks any branch that is {op} {int16 be offset}
+func checkBranch(cx *evalContext) int {
+	cx.nextpc = cx.pc + 3
+	target, err := branchTarget(cx)
+	if err != nil {
+		cx.err = err
+		return 1
+	}
+	if target < cx.nextpc {
+		// a backward branch must land on an instruction already seen
+		if !cx.instructionStarts[target] {
+			cx.err = fmt.Errorf("back branch target %d is not an aligned instruction", target)
+		}
 		return 1
 	}
 	cx.branchTargets = append(cx.branchTargets, target)
 	sort.Ints(cx.branchTargets)
 	return 1
 }
+
 func opBnz(cx *evalContext) {
 	last := len(cx.stack) - 1
 	cx.nextpc = cx.pc + 3
 	isNonZero := cx.stack[last].Uint != 0
 	cx.stack = cx.stack[:last] // pop
 	if isNonZero {
-		offset := (uint(cx.program[cx.pc+1]) << 8) | uint(cx.program[cx.pc+2])
-		if offset > 0x7fff {
-			cx.err = fmt.Errorf("bnz offset %x too large", offset)
+		target, err := branchTarget(cx)
+		if err != nil {
+			cx.err = err
 			return
 		}
-		cx.nextpc += int(offset)
+		cx.nextpc = target
 	}
 }
 
@@ -1085,22 +1304,54 @@ func opBz(cx *evalContext) {
 	isZero := cx.stack[last].Uint == 0
 	cx.stack = cx.stack[:last] // pop
 	if isZero {
-		offset := (uint(cx.program[cx.pc+1]) << 8) | uint(cx.program[cx.pc+2])
-		if offset > 0x7fff {
-			cx.err = fmt.Errorf("bz offset %x too large", offset)
+		target, err := branchTarget(cx)
+		if err != nil {
+			cx.err = err
 			return
 		}
-		cx.nextpc += int(offset)
+		cx.nextpc = target
 	}
 }
 
 func opB(cx *evalContext) {
-	offset := (uint(cx.program[cx.pc+1]) << 8) | uint(cx.program[cx.pc+2])
-	if offset > 0x7fff {
-		cx.err = fmt.Errorf("b offset %x too large", offset)
+	target, err := branchTarget(cx)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.nextpc = target
+}
+
+// MaxCallStackDepth limits the nesting of callsub
+const MaxCallStackDepth = 1000
+
+func opCallSub(cx *evalContext) {
+	if len(cx.callstack) >= MaxCallStackDepth {
+		cx.err = errors.New("callsub stack overflow")
+		return
+	}
+	cx.callstack = append(cx.callstack, cx.pc+3)
+	opB(cx)
+}
+
+func opRetSub(cx *evalContext) {
+	top := len(cx.callstack) - 1
+	if top < 0 {
+		cx.err = errors.New("retsub with empty callstack")
 		return
 	}
-	cx.nextpc = cx.pc + 3 + int(offset)
+	target := cx.callstack[top]
+	cx.callstack = cx.callstack[:top]
+	cx.nextpc = target
+}
+
+func opAssert(cx *evalContext) {
+	last := len(cx.stack) - 1
+	if cx.stack[last].Uint == 0 {
+		cx.err = errors.New("assert failed")
+		return
+	}
+	cx.stack = cx.stack[:last]
 }
 
 func opPop(cx *evalContext) {
@@ -1120,6 +1371,55 @@ func opDup2(cx *evalContext) {
 	cx.stack = append(cx.stack, cx.stack[prev:]...)
 }
 
+func opDig(cx *evalContext) {
+	depth := int(uint(cx.program[cx.pc+1]))
+	idx := len(cx.stack) - 1 - depth
+	// Need to check stack size explicitly here because checkArgs() doesn't understand dig
+	// so we can't expect our stack to be prechecked.
+	if idx < 0 {
+		cx.err = fmt.Errorf("dig %d with stack size = %d", depth, len(cx.stack))
+		return
+	}
+	sv := cx.stack[idx]
+	cx.stack = append(cx.stack, sv)
+	cx.nextpc = cx.pc + 2
+}
+
+func opSwap(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	cx.stack[last], cx.stack[prev] = cx.stack[prev], cx.stack[last]
+}
+
+func opSelect(cx *evalContext) {
+	last := len(cx.stack) - 1 // condition on top
+	prev := last - 1          // true is one down
+	pprev := prev - 1         // false below that
+
+	if cx.stack[last].Uint != 0 {
+		cx.stack[pprev] = cx.stack[prev]
+	}
+	cx.stack = cx.stack[:prev]
+}
+
+func opPushBytes(cx *evalContext) {
+	var val []byte
+	val, cx.nextpc, cx.err = parsePushBytes(cx.program, cx.pc)
+	if cx.err != nil {
+		return
+	}
+	cx.stack = append(cx.stack, stackValue{Bytes: val})
+}
+
+func opPushInt(cx *evalContext) {
+	var val uint64
+	val, cx.nextpc, cx.err = parsePushInt(cx.program, cx.pc)
+	if cx.err != nil {
+		return
+	}
+	cx.stack = append(cx.stack, stackValue{Uint: val})
+}
+
 func (cx *evalContext) assetHoldingEnumToValue(holding *basics.AssetHolding, field uint64) (sv stackValue, err error) {
 	switch AssetHoldingField(field) {
 	case AssetBalance:
@@ -1184,6 +1484,66 @@ func (cx *evalContext) assetParamsEnumToValue(params *basics.AssetParams, field
 	return
 }
 
+func (cx *evalContext) appParamsEnumToValue(params *basics.AppParams, creator basics.Address, field uint64) (sv stackValue, err error) {
+	switch AppParamsField(field) {
+	case AppApprovalProgramHash:
+		hash := sha512.Sum512_256(params.ApprovalProgram)
+		sv.Bytes = hash[:]
+	case AppClearStateProgramHash:
+		hash := sha512.Sum512_256(params.ClearStateProgram)
+		sv.Bytes = hash[:]
+	case AppGlobalNumUint:
+		sv.Uint = params.GlobalStateSchema.NumUint
+	case AppGlobalNumByteSlice:
+		sv.Uint = params.GlobalStateSchema.NumByteSlice
+	case AppLocalNumUint:
+		sv.Uint = params.LocalStateSchema.NumUint
+	case AppLocalNumByteSlice:
+		sv.Uint = params.LocalStateSchema.NumByteSlice
+	case AppCreator:
+		sv.Bytes = creator[:]
+	default:
+		err = fmt.Errorf("invalid app params field %d", field)
+		return
+	}
+
+	appParamsField := AppParamsField(field)
+	appParamsFieldType := AppParamsFieldTypes[appParamsField]
+	if appParamsFieldType != sv.argType() {
+		err = fmt.Errorf("%s expected field type is %s but got %s", appParamsField.String(), appParamsFieldType.String(), sv.argType().String())
+	}
+	return
+}
+
+func (cx *evalContext) acctParamsEnumToValue(addr basics.Address, field uint64) (sv stackValue, err error) {
+	switch AcctParamsField(field) {
+	case AcctBalance:
+		var balance basics.MicroAlgos
+		balance, err = cx.Ledger.Balance(addr)
+		sv.Uint = balance.Raw
+	case AcctMinBalance:
+		var minBalance basics.MicroAlgos
+		minBalance, err = cx.Ledger.MinBalance(addr, cx.Proto)
+		sv.Uint = minBalance.Raw
+	case AcctAuthAddr:
+		var auth basics.Address
+		auth, err = cx.Ledger.Authorizer(addr)
+		sv.Bytes = auth[:]
+	default:
+		err = fmt.Errorf("invalid account params field %d", field)
+	}
+	if err != nil {
+		return
+	}
+
+	acctParamsField := AcctParamsField(field)
+	acctParamsFieldType := AcctParamsFieldTypes[acctParamsField]
+	if acctParamsFieldType != sv.argType() {
+		err = fmt.Errorf("%s expected field type is %s but got %s", acctParamsField.String(), acctParamsFieldType.String(), sv.argType().String())
+	}
+	return
+}
+
 // TxnFieldToTealValue is a thin wrapper for txnFieldToStack for external use
 func TxnFieldToTealValue(txn *transactions.Transaction, groupIndex int, field TxnField) (basics.TealValue, error) {
 	cx := evalContext{EvalParams: EvalParams{GroupIndex: groupIndex}}
@@ -1289,6 +1649,63 @@ func (cx *evalContext) txnFieldToStack(txn *transactions.Transaction, field TxnF
 	case ClearStateProgram:
 		sv.Bytes = make([]byte, len(txn.ClearStateProgram))
 		copy(sv.Bytes, txn.ClearStateProgram)
+	case RekeyTo:
+		sv.Bytes = txn.RekeyTo[:]
+	case ConfigAsset:
+		sv.Uint = uint64(txn.ConfigAsset)
+	case ConfigAssetTotal:
+		sv.Uint = txn.AssetParams.Total
+	case ConfigAssetDecimals:
+		sv.Uint = uint64(txn.AssetParams.Decimals)
+	case ConfigAssetDefaultFrozen:
+		if txn.AssetParams.DefaultFrozen {
+			sv.Uint = 1
+		}
+	case ConfigAssetUnitName:
+		sv.Bytes = []byte(txn.AssetParams.UnitName)
+	case ConfigAssetName:
+		sv.Bytes = []byte(txn.AssetParams.AssetName)
+	case ConfigAssetURL:
+		sv.Bytes = []byte(txn.AssetParams.URL)
+	case ConfigAssetMetadataHash:
+		sv.Bytes = txn.AssetParams.MetadataHash[:]
+	case ConfigAssetManager:
+		sv.Bytes = txn.AssetParams.Manager[:]
+	case ConfigAssetReserve:
+		sv.Bytes = txn.AssetParams.Reserve[:]
+	case ConfigAssetFreeze:
+		sv.Bytes = txn.AssetParams.Freeze[:]
+	case ConfigAssetClawback:
+		sv.Bytes = txn.AssetParams.Clawback[:]
+	case FreezeAsset:
+		sv.Uint = uint64(txn.FreezeAsset)
+	case FreezeAssetAccount:
+		sv.Bytes = txn.FreezeAccount[:]
+	case FreezeAssetFrozen:
+		if txn.AssetFrozen {
+			sv.Uint = 1
+		}
+	case Applications:
+		if arrayFieldIdx == 0 {
+			// special case: the called application
+			sv.Uint = uint64(txn.ApplicationID)
+		} else {
+			if arrayFieldIdx > uint64(len(txn.ForeignApps)) {
+				err = fmt.Errorf("invalid Applications index %d", arrayFieldIdx)
+				return
+			}
+			sv.Uint = uint64(txn.ForeignApps[arrayFieldIdx-1])
+		}
+	case NumApplications:
+		sv.Uint = uint64(len(txn.ForeignApps))
+	case GlobalNumUint:
+		sv.Uint = txn.GlobalStateSchema.NumUint
+	case GlobalNumByteSlice:
+		sv.Uint = txn.GlobalStateSchema.NumByteSlice
+	case LocalNumUint:
+		sv.Uint = txn.LocalStateSchema.NumUint
+	case LocalNumByteSlice:
+		sv.Uint = txn.LocalStateSchema.NumByteSlice
 	default:
 		err = fmt.Errorf("invalid txn field %d", field)
 		return
@@ -1302,10 +1719,15 @@ func (cx *evalContext) txnFieldToStack(txn *transactions.Transaction, field TxnF
 	return
 }
 
+// txnFieldIsArray reports whether field must be accessed with txna or gtxna
+func txnFieldIsArray(field TxnField) bool {
+	return field == ApplicationArgs || field == Accounts || field == Applications
+}
+
 func opTxn(cx *evalContext) {
 	field := TxnField(uint64(cx.program[cx.pc+1]))
 	fs, ok := txnFieldSpecByField[field]
-	if !ok || fs.version > cx.version || field == ApplicationArgs || field == Accounts {
+	if !ok || fs.version > cx.version || txnFieldIsArray(field) {
 		cx.err = fmt.Errorf("invalid txn field %d", field)
 		return
 	}
@@ -1327,7 +1749,7 @@ func opTxna(cx *evalContext) {
 		cx.err = fmt.Errorf("invalid txn field %d", field)
 		return
 	}
-	if field != ApplicationArgs && field != Accounts {
+	if !txnFieldIsArray(field) {
 		cx.err = fmt.Errorf("txna unsupported field %d", field)
 		return
 	}
@@ -1352,7 +1774,7 @@ func opGtxn(cx *evalContext) {
 	tx := &cx.TxnGroup[gtxid].Txn
 	field := TxnField(uint64(cx.program[cx.pc+2]))
 	fs, ok := txnFieldSpecByField[field]
-	if !ok || fs.version > cx.version || field == ApplicationArgs || field == Accounts {
+	if !ok || fs.version > cx.version || txnFieldIsArray(field) {
 		cx.err = fmt.Errorf("invalid txn field %d", field)
 		return
 	}
@@ -1385,7 +1807,7 @@ func opGtxna(cx *evalContext) {
 		cx.err = fmt.Errorf("invalid txn field %d", field)
 		return
 	}
-	if TxnField(field) != ApplicationArgs && TxnField(field) != Accounts {
+	if !txnFieldIsArray(field) {
 		cx.err = fmt.Errorf("gtxna unsupported field %d", field)
 		return
 	}
@@ -1422,6 +1844,33 @@ func (cx *evalContext) getLatestTimestamp() (timestamp uint64, err error) {
 	return uint64(ts), nil
 }
 
+func (cx *evalContext) getApplicationID() (appID uint64, err error) {
+	if cx.Ledger == nil {
+		err = fmt.Errorf("ledger not available")
+		return
+	}
+	return uint64(cx.Ledger.ApplicationID()), nil
+}
+
+func (cx *evalContext) getCreatorAddress() ([]byte, error) {
+	if cx.Ledger == nil {
+		return nil, fmt.Errorf("ledger not available")
+	}
+	_, creator, err := cx.Ledger.AppParams(cx.Ledger.ApplicationID())
+	if err != nil {
+		return nil, fmt.Errorf("could not find creator of app %d: %v", cx.Ledger.ApplicationID(), err)
+	}
+	return creator[:], nil
+}
+
+func (cx *evalContext) getApplicationAddress() ([]byte, error) {
+	if cx.Ledger == nil {
+		return nil, fmt.Errorf("ledger not available")
+	}
+	addr := cx.Ledger.ApplicationID().Address()
+	return addr[:], nil
+}
+
 var zeroAddress basics.Address
 
 func (cx *evalContext) globalFieldToStack(field GlobalField) (sv stackValue, err error) {
@@ -1442,6 +1891,12 @@ func (cx *evalContext) globalFieldToStack(field GlobalField) (sv stackValue, err
 		sv.Uint, err = cx.getRound()
 	case LatestTimestamp:
 		sv.Uint, err = cx.getLatestTimestamp()
+	case CurrentApplicationID:
+		sv.Uint, err = cx.getApplicationID()
+	case CreatorAddress:
+		sv.Bytes, err = cx.getCreatorAddress()
+	case CurrentApplicationAddress:
+		sv.Bytes, err = cx.getApplicationAddress()
 	default:
 		err = fmt.Errorf("invalid global[%d]", field)
 	}
@@ -1595,6 +2050,298 @@ func opSubstring3(cx *evalContext) {
 	cx.stack = cx.stack[:prev]
 }
 
+func opGetBit(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	idx := cx.stack[last].Uint
+	target := cx.stack[prev]
+
+	var bit uint64
+	if target.argType() == StackUint64 {
+		if idx > 63 {
+			cx.err = errors.New("getbit index > 63 with Uint")
+			return
+		}
+		mask := uint64(1) << idx
+		bit = (target.Uint & mask) >> idx
+	} else {
+		// indexing into a byteslice goes from the leftmost byte, and
+		// from the highest bit of that byte, as if the byteslice was a
+		// big-endian number
+		byteIdx := idx / 8
+		if byteIdx >= uint64(len(target.Bytes)) {
+			cx.err = errors.New("getbit index beyond byteslice")
+			return
+		}
+		byteVal := target.Bytes[byteIdx]
+		bit = uint64(byteVal>>(7-idx%8)) & 1
+	}
+
+	cx.stack[prev].Uint = bit
+	cx.stack[prev].Bytes = nil
+	cx.stack = cx.stack[:last]
+}
+
+func opSetBit(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	pprev := prev - 1
+
+	bit := cx.stack[last].Uint
+	idx := cx.stack[prev].Uint
+	target := cx.stack[pprev]
+
+	if bit > 1 {
+		cx.err = errors.New("setbit value > 1")
+		return
+	}
+
+	if target.argType() == StackUint64 {
+		if idx > 63 {
+			cx.err = errors.New("setbit index > 63 with Uint")
+			return
+		}
+		mask := uint64(1) << idx
+		if bit == uint64(1) {
+			cx.stack[pprev].Uint |= mask // manipulate stack in place
+		} else {
+			cx.stack[pprev].Uint &^= mask // manipulate stack in place
+		}
+	} else {
+		byteIdx := idx / 8
+		if byteIdx >= uint64(len(target.Bytes)) {
+			cx.err = errors.New("setbit index beyond byteslice")
+			return
+		}
+		// the byteslice may be shared with a constant or the scratch
+		// space, so never modify it in place
+		buf := make([]byte, len(target.Bytes))
+		copy(buf, target.Bytes)
+		mask := byte(0x80) >> (idx % 8)
+		if bit == uint64(1) {
+			buf[byteIdx] |= mask
+		} else {
+			buf[byteIdx] &^= mask
+		}
+		cx.stack[pprev].Bytes = buf
+	}
+	cx.stack = cx.stack[:prev]
+}
+
+func opGetByte(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+
+	idx := cx.stack[last].Uint
+	target := cx.stack[prev]
+
+	if idx >= uint64(len(target.Bytes)) {
+		cx.err = errors.New("getbyte index beyond array length")
+		return
+	}
+	cx.stack[prev].Uint = uint64(target.Bytes[idx])
+	cx.stack[prev].Bytes = nil
+	cx.stack = cx.stack[:last]
+}
+
+func opSetByte(cx *evalContext) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+	pprev := prev - 1
+	if cx.stack[last].Uint > 255 {
+		cx.err = errors.New("setbyte value > 255")
+		return
+	}
+	if cx.stack[prev].Uint >= uint64(len(cx.stack[pprev].Bytes)) {
+		cx.err = errors.New("setbyte index beyond array length")
+		return
+	}
+	// copy, see opSetBit
+	buf := make([]byte, len(cx.stack[pprev].Bytes))
+	copy(buf, cx.stack[pprev].Bytes)
+	buf[cx.stack[prev].Uint] = byte(cx.stack[last].Uint)
+	cx.stack[pprev].Bytes = buf
+	cx.stack = cx.stack[:prev]
+}
+
+// MaxByteMathSize is the limit of byte strings supplied as input to byte math opcodes
+const MaxByteMathSize = 64
+
+// opBytesBinOp pops two byte strings, interprets them as big-endian
+// unsigned integers of at most MaxByteMathSize bytes and replaces them
+// with the result of op
+func opBytesBinOp(cx *evalContext, name string, op func(result, x, y *big.Int) error) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+
+	if len(cx.stack[last].Bytes) > MaxByteMathSize || len(cx.stack[prev].Bytes) > MaxByteMathSize {
+		cx.err = fmt.Errorf("%s arguments are limited to %d bytes", name, MaxByteMathSize)
+		return
+	}
+	var x, y, result big.Int
+	x.SetBytes(cx.stack[prev].Bytes)
+	y.SetBytes(cx.stack[last].Bytes)
+	if err := op(&result, &x, &y); err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack[prev].Bytes = append([]byte{}, result.Bytes()...)
+	cx.stack = cx.stack[:last]
+}
+
+func opBytesPlus(cx *evalContext) {
+	opBytesBinOp(cx, "b+", func(result, x, y *big.Int) error {
+		result.Add(x, y)
+		return nil
+	})
+}
+
+func opBytesMinus(cx *evalContext) {
+	opBytesBinOp(cx, "b-", func(result, x, y *big.Int) error {
+		if x.Cmp(y) < 0 {
+			return errors.New("byte math would have negative result")
+		}
+		result.Sub(x, y)
+		return nil
+	})
+}
+
+func opBytesDiv(cx *evalContext) {
+	opBytesBinOp(cx, "b/", func(result, x, y *big.Int) error {
+		if y.Sign() == 0 {
+			return errors.New("division by zero")
+		}
+		result.Div(x, y)
+		return nil
+	})
+}
+
+func opBytesMul(cx *evalContext) {
+	opBytesBinOp(cx, "b*", func(result, x, y *big.Int) error {
+		result.Mul(x, y)
+		return nil
+	})
+}
+
+func opBytesModulo(cx *evalContext) {
+	opBytesBinOp(cx, "b%", func(result, x, y *big.Int) error {
+		if y.Sign() == 0 {
+			return errors.New("modulo by zero")
+		}
+		result.Mod(x, y)
+		return nil
+	})
+}
+
+// opBytesCompare pops two byte strings, compares them as big-endian
+// unsigned integers and pushes 1 if cond holds for the result of the
+// comparison, 0 otherwise
+func opBytesCompare(cx *evalContext, name string, cond func(cmp int) bool) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+
+	if len(cx.stack[last].Bytes) > MaxByteMathSize || len(cx.stack[prev].Bytes) > MaxByteMathSize {
+		cx.err = fmt.Errorf("%s arguments are limited to %d bytes", name, MaxByteMathSize)
+		return
+	}
+	var x, y big.Int
+	x.SetBytes(cx.stack[prev].Bytes)
+	y.SetBytes(cx.stack[last].Bytes)
+	if cond(x.Cmp(&y)) {
+		cx.stack[prev].Uint = 1
+	} else {
+		cx.stack[prev].Uint = 0
+	}
+	cx.stack[prev].Bytes = nil
+	cx.stack = cx.stack[:last]
+}
+
+func opBytesLt(cx *evalContext) {
+	opBytesCompare(cx, "b<", func(cmp int) bool { return cmp < 0 })
+}
+
+func opBytesGt(cx *evalContext) {
+	opBytesCompare(cx, "b>", func(cmp int) bool { return cmp > 0 })
+}
+
+func opBytesLe(cx *evalContext) {
+	opBytesCompare(cx, "b<=", func(cmp int) bool { return cmp <= 0 })
+}
+
+func opBytesGe(cx *evalContext) {
+	opBytesCompare(cx, "b>=", func(cmp int) bool { return cmp >= 0 })
+}
+
+func opBytesEq(cx *evalContext) {
+	opBytesCompare(cx, "b==", func(cmp int) bool { return cmp == 0 })
+}
+
+func opBytesNeq(cx *evalContext) {
+	opBytesCompare(cx, "b!=", func(cmp int) bool { return cmp != 0 })
+}
+
+// zpad left pads the shorter of two byte strings with zeros so that the
+// bitwise byte ops can treat them as numbers of the same length. The
+// result is always a copy.
+func zpad(smaller []byte, size int) []byte {
+	padded := make([]byte, size)
+	copy(padded[size-len(smaller):], smaller)
+	return padded
+}
+
+// opBytesBitwise pops two byte strings and replaces them with the byte by
+// byte result of op, the shorter string is left padded with zeros
+func opBytesBitwise(cx *evalContext, op func(x, y byte) byte) {
+	last := len(cx.stack) - 1
+	prev := last - 1
+
+	x := cx.stack[prev].Bytes
+	y := cx.stack[last].Bytes
+	size := len(x)
+	if len(y) > size {
+		size = len(y)
+	}
+	result := zpad(x, size)
+	y = zpad(y, size)
+	for i := range result {
+		result[i] = op(result[i], y[i])
+	}
+	cx.stack[prev].Bytes = result
+	cx.stack = cx.stack[:last]
+}
+
+func opBytesBitOr(cx *evalContext) {
+	opBytesBitwise(cx, func(x, y byte) byte { return x | y })
+}
+
+func opBytesBitAnd(cx *evalContext) {
+	opBytesBitwise(cx, func(x, y byte) byte { return x & y })
+}
+
+func opBytesBitXor(cx *evalContext) {
+	opBytesBitwise(cx, func(x, y byte) byte { return x ^ y })
+}
+
+func opBytesBitNot(cx *evalContext) {
+	last := len(cx.stack) - 1
+
+	result := make([]byte, len(cx.stack[last].Bytes))
+	for i, b := range cx.stack[last].Bytes {
+		result[i] = ^b
+	}
+	cx.stack[last].Bytes = result
+}
+
+func opBytesZero(cx *evalContext) {
+	last := len(cx.stack) - 1
+	length := cx.stack[last].Uint
+	if length > MaxStringSize {
+		cx.err = fmt.Errorf("bzero attempted to create a too large string")
+		return
+	}
+	cx.stack[last].Bytes = make([]byte, length)
+}
+
 func opBalance(cx *evalContext) {
 	last := len(cx.stack) - 1 // account offset
 
@@ -2076,3 +2823,246 @@ func opAssetParamsGet(cx *evalContext) {
 
 	cx.nextpc = cx.pc + 2
 }
+
+func opAppParamsGet(cx *evalContext) {
+	last := len(cx.stack) - 1 // app id
+
+	appID := cx.stack[last].Uint
+	paramIdx := uint64(cx.program[cx.pc+1])
+
+	if cx.Ledger == nil {
+		cx.err = fmt.Errorf("ledger not available")
+		return
+	}
+
+	if appID == 0 {
+		appID = uint64(cx.Ledger.ApplicationID()) // 0 is an alias for the current app
+	}
+
+	var exist uint64 = 0
+	var value stackValue
+	if params, creator, err := cx.Ledger.AppParams(basics.AppIndex(appID)); err == nil {
+		// params exist, read the value
+		exist = 1
+		value, err = cx.appParamsEnumToValue(&params, creator, paramIdx)
+		if err != nil {
+			cx.err = err
+			return
+		}
+	}
+
+	cx.stack[last] = value
+	cx.stack = append(cx.stack, stackValue{Uint: exist})
+
+	cx.nextpc = cx.pc + 2
+}
+
+func opAcctParamsGet(cx *evalContext) {
+	last := len(cx.stack) - 1 // account offset
+
+	accountIdx := cx.stack[last].Uint
+	paramIdx := uint64(cx.program[cx.pc+1])
+
+	if cx.Ledger == nil {
+		cx.err = fmt.Errorf("ledger not available")
+		return
+	}
+
+	addr, err := cx.Txn.Txn.AddressByIndex(accountIdx, cx.Txn.Txn.Sender)
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	// an account exists if it holds any algos
+	var exist uint64 = 0
+	var value stackValue
+	if balance, err := cx.Ledger.Balance(addr); err == nil && !balance.IsZero() {
+		exist = 1
+		value, err = cx.acctParamsEnumToValue(addr, paramIdx)
+		if err != nil {
+			cx.err = err
+			return
+		}
+	}
+
+	cx.stack[last] = value
+	cx.stack = append(cx.stack, stackValue{Uint: exist})
+
+	cx.nextpc = cx.pc + 2
+}
+
+// innerTxnTypes are the transaction types an application may issue as inner
+// transactions
+var innerTxnTypes = map[protocol.TxType]bool{
+	protocol.PaymentTx:       true,
+	protocol.AssetTransferTx: true,
+	protocol.AssetConfigTx:   true,
+}
+
+// innerTxnFields are the transaction fields that itxn_field may set
+var innerTxnFields = map[TxnField]bool{
+	Type: true, TypeEnum: true, Sender: true, Fee: true, Note: true,
+	Receiver: true, Amount: true, CloseRemainderTo: true,
+	XferAsset: true, AssetAmount: true, AssetSender: true, AssetReceiver: true, AssetCloseTo: true,
+	ConfigAsset: true, ConfigAssetTotal: true, ConfigAssetDecimals: true, ConfigAssetDefaultFrozen: true,
+	ConfigAssetUnitName: true, ConfigAssetName: true, ConfigAssetURL: true, ConfigAssetMetadataHash: true,
+	ConfigAssetManager: true, ConfigAssetReserve: true, ConfigAssetFreeze: true, ConfigAssetClawback: true,
+}
+
+func opItxnBegin(cx *evalContext) {
+	if cx.Ledger == nil {
+		cx.err = fmt.Errorf("ledger not available")
+		return
+	}
+	if cx.Txn.Txn.OnCompletion == transactions.ClearStateOC {
+		cx.err = errors.New("inner transactions are not allowed in ClearStateProgram")
+		return
+	}
+	if cx.pendingInnerTxn != nil {
+		cx.err = errors.New("itxn_begin without itxn_submit")
+		return
+	}
+	if len(cx.innerTxns) >= cx.Proto.MaxInnerTransactions {
+		cx.err = fmt.Errorf("too many inner transactions %d", len(cx.innerTxns)+1)
+		return
+	}
+
+	// Inner transactions are sent from the application account and pay
+	// the minimum fee unless the program sets otherwise
+	cx.pendingInnerTxn = &transactions.Transaction{
+		Header: transactions.Header{
+			Sender:     cx.Ledger.ApplicationID().Address(),
+			Fee:        basics.MicroAlgos{Raw: cx.Proto.MinTxnFee},
+			FirstValid: cx.Txn.Txn.FirstValid,
+			LastValid:  cx.Txn.Txn.LastValid,
+		},
+	}
+}
+
+func stackToAddress(sv stackValue) (addr basics.Address, err error) {
+	if len(sv.Bytes) != len(addr) {
+		err = fmt.Errorf("invalid address length %d", len(sv.Bytes))
+		return
+	}
+	copy(addr[:], sv.Bytes)
+	return
+}
+
+func (cx *evalContext) stackIntoTxnField(sv stackValue, field TxnField, txn *transactions.Transaction) (err error) {
+	fs, ok := txnFieldSpecByField[field]
+	if !ok || fs.version > cx.version || !innerTxnFields[field] {
+		return fmt.Errorf("invalid itxn_field %d", field)
+	}
+	if sv.argType() != fs.ftype {
+		return fmt.Errorf("itxn_field %s wants %s but got %s", field.String(), fs.ftype.String(), sv.typeName())
+	}
+
+	switch field {
+	case Type:
+		txType := protocol.TxType(sv.Bytes)
+		if !innerTxnTypes[txType] {
+			return fmt.Errorf("%s is not a valid inner transaction type", txType)
+		}
+		txn.Type = txType
+	case TypeEnum:
+		if sv.Uint >= uint64(len(TxnTypeNames)) || !innerTxnTypes[protocol.TxType(TxnTypeNames[sv.Uint])] {
+			return fmt.Errorf("%d is not a valid inner transaction type", sv.Uint)
+		}
+		txn.Type = protocol.TxType(TxnTypeNames[sv.Uint])
+	case Sender:
+		txn.Sender, err = stackToAddress(sv)
+	case Fee:
+		txn.Fee.Raw = sv.Uint
+	case Note:
+		if len(sv.Bytes) > cx.Proto.MaxTxnNoteBytes {
+			return fmt.Errorf("%s may not exceed %d bytes", field.String(), cx.Proto.MaxTxnNoteBytes)
+		}
+		txn.Note = append([]byte(nil), sv.Bytes...)
+	case Receiver:
+		txn.Receiver, err = stackToAddress(sv)
+	case Amount:
+		txn.Amount.Raw = sv.Uint
+	case CloseRemainderTo:
+		txn.CloseRemainderTo, err = stackToAddress(sv)
+	case XferAsset:
+		txn.XferAsset = basics.AssetIndex(sv.Uint)
+	case AssetAmount:
+		txn.AssetAmount = sv.Uint
+	case AssetSender:
+		txn.AssetSender, err = stackToAddress(sv)
+	case AssetReceiver:
+		txn.AssetReceiver, err = stackToAddress(sv)
+	case AssetCloseTo:
+		txn.AssetCloseTo, err = stackToAddress(sv)
+	case ConfigAsset:
+		txn.ConfigAsset = basics.AssetIndex(sv.Uint)
+	case ConfigAssetTotal:
+		txn.AssetParams.Total = sv.Uint
+	case ConfigAssetDecimals:
+		if sv.Uint > uint64(cx.Proto.MaxAssetDecimals) {
+			return fmt.Errorf("%s may not exceed %d", field.String(), cx.Proto.MaxAssetDecimals)
+		}
+		txn.AssetParams.Decimals = uint32(sv.Uint)
+	case ConfigAssetDefaultFrozen:
+		if sv.Uint > 1 {
+			return fmt.Errorf("%s must be 0 or 1", field.String())
+		}
+		txn.AssetParams.DefaultFrozen = sv.Uint != 0
+	case ConfigAssetUnitName:
+		txn.AssetParams.UnitName = string(sv.Bytes)
+	case ConfigAssetName:
+		txn.AssetParams.AssetName = string(sv.Bytes)
+	case ConfigAssetURL:
+		txn.AssetParams.URL = string(sv.Bytes)
+	case ConfigAssetMetadataHash:
+		if len(sv.Bytes) != len(txn.AssetParams.MetadataHash) {
+			return fmt.Errorf("%s must be %d bytes", field.String(), len(txn.AssetParams.MetadataHash))
+		}
+		copy(txn.AssetParams.MetadataHash[:], sv.Bytes)
+	case ConfigAssetManager:
+		txn.AssetParams.Manager, err = stackToAddress(sv)
+	case ConfigAssetReserve:
+		txn.AssetParams.Reserve, err = stackToAddress(sv)
+	case ConfigAssetFreeze:
+		txn.AssetParams.Freeze, err = stackToAddress(sv)
+	case ConfigAssetClawback:
+		txn.AssetParams.Clawback, err = stackToAddress(sv)
+	default:
+		err = fmt.Errorf("invalid itxn_field %s", field.String())
+	}
+	return
+}
+
+func opItxnField(cx *evalContext) {
+	last := len(cx.stack) - 1
+	field := TxnField(uint64(cx.program[cx.pc+1]))
+
+	if cx.pendingInnerTxn == nil {
+		cx.err = errors.New("itxn_field without itxn_begin")
+		return
+	}
+
+	err := cx.stackIntoTxnField(cx.stack[last], field, cx.pendingInnerTxn)
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	cx.stack = cx.stack[:last]
+	cx.nextpc = cx.pc + 2
+}
+
+func opItxnSubmit(cx *evalContext) {
+	if cx.pendingInnerTxn == nil {
+		cx.err = errors.New("itxn_submit without itxn_begin")
+		return
+	}
+	if !innerTxnTypes[cx.pendingInnerTxn.Type] {
+		cx.err = errors.New("itxn_submit without a transaction type")
+		return
+	}
+
+	cx.innerTxns = append(cx.innerTxns, transactions.SignedTxn{Txn: *cx.pendingInnerTxn})
+	cx.pendingInnerTxn = nil
+}
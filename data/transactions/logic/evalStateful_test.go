@@ -25,12 +25,15 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/protocol"
 )
 
 type balanceRecord struct {
 	addr     basics.Address
+	auth     basics.Address
 	balance  uint64
 	apps     map[basics.AppIndex]map[string]basics.TealValue
 	holdings map[uint64]basics.AssetHolding
@@ -40,6 +43,8 @@ type balanceRecord struct {
 type testLedger struct {
 	balances     map[basics.Address]balanceRecord
 	applications map[basics.AppIndex]map[string]basics.TealValue
+	appParams    map[basics.AppIndex]basics.AppParams
+	creators     map[basics.AppIndex]basics.Address
 	localCount   int
 	globalCount  int
 	appID        uint64
@@ -63,6 +68,8 @@ func makeTestLedger(balances map[basics.Address]uint64) *testLedger {
 		l.balances[addr] = makeBalanceRecord(addr, balance)
 	}
 	l.applications = make(map[basics.AppIndex]map[string]basics.TealValue)
+	l.appParams = make(map[basics.AppIndex]basics.AppParams)
+	l.creators = make(map[basics.AppIndex]basics.Address)
 	return l
 }
 
@@ -75,6 +82,7 @@ func (l *testLedger) newApp(addr basics.Address, appID uint64) {
 	l.appID = appID
 	appIdx := basics.AppIndex(appID)
 	l.applications[appIdx] = make(map[string]basics.TealValue)
+	l.creators[appIdx] = addr
 	br, ok := l.balances[addr]
 	if !ok {
 		br = makeBalanceRecord(addr, 0)
@@ -167,6 +175,33 @@ func (l *testLedger) AssetParams(addr basics.Address, assetID basics.AssetIndex)
 	return basics.AssetParams{}, fmt.Errorf("no such address")
 }
 
+func (l *testLedger) AppParams(appIdx basics.AppIndex) (basics.AppParams, basics.Address, error) {
+	if appIdx == 0 {
+		appIdx = basics.AppIndex(l.appID)
+	}
+	creator, ok := l.creators[appIdx]
+	if !ok {
+		return basics.AppParams{}, basics.Address{}, fmt.Errorf("no such app")
+	}
+	return l.appParams[appIdx], creator, nil
+}
+
+func (l *testLedger) MinBalance(addr basics.Address, proto *config.ConsensusParams) (basics.MicroAlgos, error) {
+	br, ok := l.balances[addr]
+	if !ok {
+		return basics.MicroAlgos{}, fmt.Errorf("no such address")
+	}
+	return basics.MicroAlgos{Raw: proto.MinBalance * uint64(1+len(br.holdings))}, nil
+}
+
+func (l *testLedger) Authorizer(addr basics.Address) (basics.Address, error) {
+	br, ok := l.balances[addr]
+	if !ok {
+		return basics.Address{}, fmt.Errorf("no such address")
+	}
+	return br.auth, nil
+}
+
 func (l *testLedger) ApplicationID() basics.AppIndex {
 	return basics.AppIndex(l.appID)
 }
@@ -2530,6 +2565,11 @@ func TestReturnTypes(t *testing.T) {
 		"ed25519verify":     "pop\npop\npop\nint 1", // ignore
 		"asset_params_get":  "asset_params_get AssetTotal",
 		"asset_holding_get": "asset_holding_get AssetBalance",
+		"app_params_get":    "app_params_get AppGlobalNumUint",
+		"acct_params_get":   "acct_params_get AcctBalance",
+		"dig":               "dig 0",
+		"pushint":           "pushint 1",
+		"pushbytes":         "pushbytes 0x32",
 	}
 
 	byName := opsByName[LogicVersion]
@@ -2645,3 +2685,311 @@ int 1
 	require.NoError(t, err)
 	require.True(t, pass)
 }
+
+func TestAppParams(t *testing.T) {
+	t.Parallel()
+	for _, field := range AppParamsFieldNames {
+		if !strings.Contains(appParamsTestProgram, field) {
+			t.Errorf("TestAppParams missing field %v", field)
+		}
+	}
+
+	program, err := AssembleString(appParamsTestProgram)
+	require.NoError(t, err)
+
+	txn := makeSampleTxn()
+	ep := defaultEvalParams(nil, &txn)
+	cost, err := CheckStateful(program, ep)
+	require.NoError(t, err)
+	require.True(t, cost < 1000)
+	_, _, err = EvalStateful(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ledger not available")
+
+	ledger := makeTestLedger(nil)
+	ledger.newApp(txn.Txn.Receiver, 100)
+	ledger.appParams[100] = basics.AppParams{
+		ApprovalProgram:   []byte{0x04, 0x81, 0x01}, // pushint 1
+		GlobalStateSchema: basics.StateSchema{NumUint: 1, NumByteSlice: 2},
+		LocalStateSchema:  basics.StateSchema{NumUint: 3, NumByteSlice: 4},
+	}
+	ledger.newApp(txn.Txn.Sender, 123)
+	ep.Ledger = ledger
+
+	sb := strings.Builder{}
+	ep.Trace = &sb
+	pass, _, err := EvalStateful(program, ep)
+	if !pass {
+		t.Log(hex.EncodeToString(program))
+		t.Log(sb.String())
+	}
+	require.NoError(t, err)
+	require.True(t, pass)
+}
+
+const appParamsTestProgram = `int 100
+app_params_get AppApprovalProgramHash
+assert
+byte 0x048101
+sha512_256
+==
+assert
+int 100
+app_params_get AppClearStateProgramHash
+assert
+byte 0x
+sha512_256
+==
+assert
+int 100
+app_params_get AppGlobalNumUint
+assert
+int 1
+==
+assert
+int 100
+app_params_get AppGlobalNumByteSlice
+assert
+int 2
+==
+assert
+int 100
+app_params_get AppLocalNumUint
+assert
+int 3
+==
+assert
+int 100
+app_params_get AppLocalNumByteSlice
+assert
+int 4
+==
+assert
+int 100
+app_params_get AppCreator
+assert
+txn Receiver
+==
+assert
+int 0 // the current app
+app_params_get AppCreator
+assert
+txn Sender
+==
+assert
+int 999
+app_params_get AppCreator
+!
+assert
+int 0
+==
+`
+
+func TestAcctParams(t *testing.T) {
+	t.Parallel()
+	for _, field := range AcctParamsFieldNames {
+		if !strings.Contains(acctParamsTestProgram, field) {
+			t.Errorf("TestAcctParams missing field %v", field)
+		}
+	}
+
+	program, err := AssembleString(acctParamsTestProgram)
+	require.NoError(t, err)
+
+	txn := makeSampleTxn()
+	ep := defaultEvalParams(nil, &txn)
+	ep.Proto.MinBalance = 1000
+	cost, err := CheckStateful(program, ep)
+	require.NoError(t, err)
+	require.True(t, cost < 1000)
+	_, _, err = EvalStateful(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ledger not available")
+
+	ledger := makeTestLedger(
+		map[basics.Address]uint64{
+			txn.Txn.Sender: 5000,
+		},
+	)
+	ledger.setHolding(txn.Txn.Sender, 55, basics.AssetHolding{Amount: 123})
+	br := ledger.balances[txn.Txn.Sender]
+	br.auth = txn.Txn.Receiver
+	ledger.balances[txn.Txn.Sender] = br
+	ep.Ledger = ledger
+
+	sb := strings.Builder{}
+	ep.Trace = &sb
+	pass, _, err := EvalStateful(program, ep)
+	if !pass {
+		t.Log(hex.EncodeToString(program))
+		t.Log(sb.String())
+	}
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	// account index out of range
+	ep.Trace = nil
+	program, err = AssembleString("int 5\nacct_params_get AcctBalance")
+	require.NoError(t, err)
+	_, _, err = EvalStateful(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot load account[5]")
+}
+
+const acctParamsTestProgram = `int 0
+acct_params_get AcctBalance
+assert
+int 5000
+==
+assert
+int 0
+acct_params_get AcctMinBalance
+assert
+int 2000
+==
+assert
+int 0
+acct_params_get AcctAuthAddr
+assert
+txn Receiver
+==
+assert
+int 1 // Txn.Accounts[0] has no balance record
+acct_params_get AcctBalance
+!
+assert
+int 0
+==
+`
+
+func TestInnerTxns(t *testing.T) {
+	t.Parallel()
+
+	program, err := AssembleString(innerTxnsTestProgram)
+	require.NoError(t, err)
+
+	txn := makeSampleTxn()
+	ep := defaultEvalParams(nil, &txn)
+	ep.Proto.MinTxnFee = 1000
+	ep.Proto.MaxTxnNoteBytes = 1024
+	ep.Proto.MaxAssetDecimals = 19
+	ep.Proto.MaxInnerTransactions = 2
+	cost, err := CheckStateful(program, ep)
+	require.NoError(t, err)
+	require.True(t, cost < 1000)
+	_, _, _, err = EvalStatefulInner(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ledger not available")
+
+	ledger := makeTestLedger(nil)
+	ledger.newApp(txn.Txn.Sender, 888)
+	ep.Ledger = ledger
+
+	sb := strings.Builder{}
+	ep.Trace = &sb
+	pass, _, innerTxns, err := EvalStatefulInner(program, ep)
+	if !pass {
+		t.Log(hex.EncodeToString(program))
+		t.Log(sb.String())
+	}
+	require.NoError(t, err)
+	require.True(t, pass)
+	require.Len(t, innerTxns, 2)
+
+	appAddr := basics.AppIndex(888).Address()
+	pay := innerTxns[0].Txn
+	require.Equal(t, protocol.PaymentTx, pay.Type)
+	require.Equal(t, appAddr, pay.Sender)
+	require.Equal(t, uint64(1000), pay.Fee.Raw)
+	require.Equal(t, txn.Txn.FirstValid, pay.FirstValid)
+	require.Equal(t, txn.Txn.LastValid, pay.LastValid)
+	require.Equal(t, txn.Txn.Receiver, pay.Receiver)
+	require.Equal(t, uint64(5000), pay.Amount.Raw)
+	require.Equal(t, []byte("refund"), pay.Note)
+
+	axfer := innerTxns[1].Txn
+	require.Equal(t, protocol.AssetTransferTx, axfer.Type)
+	require.Equal(t, appAddr, axfer.Sender)
+	require.Equal(t, uint64(2000), axfer.Fee.Raw)
+	require.Equal(t, basics.AssetIndex(55), axfer.XferAsset)
+	require.Equal(t, uint64(10), axfer.AssetAmount)
+	require.Equal(t, txn.Txn.Sender, axfer.AssetReceiver)
+
+	// EvalStateful evaluates the same program but drops the inner transactions
+	ep.Trace = nil
+	pass, _, err = EvalStateful(program, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	// only MaxInnerTransactions may be issued
+	ep.Proto.MaxInnerTransactions = 1
+	_, _, _, err = EvalStatefulInner(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "too many inner transactions")
+	ep.Proto.MaxInnerTransactions = 2
+
+	// inner transactions cannot be issued from a ClearStateProgram
+	clearTxn := txn
+	clearTxn.Txn.OnCompletion = transactions.ClearStateOC
+	ep.Txn = &clearTxn
+	_, _, _, err = EvalStatefulInner(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed in ClearStateProgram")
+	ep.Txn = &txn
+
+	failures := map[string]string{
+		"int 1\nitxn_field Amount\nint 1":                                    "itxn_field without itxn_begin",
+		"itxn_begin\nitxn_begin\nint 1":                                      "itxn_begin without itxn_submit",
+		"itxn_begin\nint 1":                                                  "itxn_begin without itxn_submit",
+		"itxn_submit\nint 1":                                                 "itxn_submit without itxn_begin",
+		"itxn_begin\nitxn_submit\nint 1":                                     "itxn_submit without a transaction type",
+		"itxn_begin\nint appl\nitxn_field TypeEnum\nint 1":                   "not a valid inner transaction type",
+		"itxn_begin\nbyte \"keyreg\"\nitxn_field Type\nint 1":                "not a valid inner transaction type",
+		"itxn_begin\nbyte 0x01\nitxn_field Amount\nint 1":                    "itxn_field Amount wants uint64",
+		"itxn_begin\nbyte 0x0102\nitxn_field Receiver\nint 1":                "invalid address length 2",
+		"itxn_begin\nint 2\nitxn_field ConfigAssetDefaultFrozen\nint 1":      "must be 0 or 1",
+		"itxn_begin\nbyte 0x0102\nitxn_field ConfigAssetMetadataHash\nint 1": "must be 32 bytes",
+		"itxn_begin\nint 20\nitxn_field ConfigAssetDecimals\nint 1":          "may not exceed 19",
+	}
+	for source, msg := range failures {
+		program, err := AssembleString(source)
+		require.NoError(t, err, source)
+		_, _, _, err = EvalStatefulInner(program, ep)
+		require.Error(t, err, source)
+		require.Contains(t, err.Error(), msg, source)
+	}
+
+	// fields outside of pay, axfer and acfg transactions cannot be set
+	_, err = AssembleString("itxn_begin\nint 1\nitxn_field ApplicationID")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "itxn_field unknown arg ApplicationID")
+}
+
+const innerTxnsTestProgram = `global CurrentApplicationAddress
+addr U7C5FUHZM5PL5EIS2KHHLL456GS66DZBEEKL2UBQLMKH2X5X5I643ZIM6U
+==
+assert
+itxn_begin
+int pay
+itxn_field TypeEnum
+txn Receiver
+itxn_field Receiver
+int 5000
+itxn_field Amount
+byte "refund"
+itxn_field Note
+itxn_submit
+itxn_begin
+byte "axfer"
+itxn_field Type
+int 2000
+itxn_field Fee
+int 55
+itxn_field XferAsset
+int 10
+itxn_field AssetAmount
+txn Sender
+itxn_field AssetReceiver
+itxn_submit
+int 1
+`
@@ -48,7 +48,7 @@ func defaultEvalProtoV1() config.ConsensusParams {
 }
 
 func defaultEvalProtoWithVersion(version uint64) config.ConsensusParams {
-	return config.ConsensusParams{LogicSigVersion: version, LogicSigMaxCost: 20000}
+	return config.ConsensusParams{LogicSigVersion: version, LogicSigMaxCost: 20000, MaxAppProgramCost: 700}
 }
 
 func defaultEvalParamsV1(sb *strings.Builder, txn *transactions.SignedTxn) EvalParams {
@@ -1144,8 +1144,8 @@ int 1
 `
 
 const globalV2TestProgram = `global LogicSigVersion
-int 2
-==
+int 1
+>
 &&
 global Round
 int 0
@@ -1157,6 +1157,20 @@ int 0
 &&
 `
 
+const globalV4TestProgram = `global CurrentApplicationID
+int 888
+==
+&&
+global CreatorAddress
+addr DFPKC2SJP3OTFVJFMCD356YB7BOT4SJZTGWLIPPFEWL3ZABUFLTOY6ILYE
+==
+&&
+global CurrentApplicationAddress
+addr U7C5FUHZM5PL5EIS2KHHLL456GS66DZBEEKL2UBQLMKH2X5X5I643ZIM6U
+==
+&&
+`
+
 func TestGlobal(t *testing.T) {
 	t.Parallel()
 	type desc struct {
@@ -1176,8 +1190,27 @@ func TestGlobal(t *testing.T) {
 			},
 			func(program []byte, ep EvalParams) (int, error) { return CheckStateful(program, ep) },
 		},
+		3: {
+			LatestTimestamp, globalV1TestProgram + globalV2TestProgram,
+			func(p []byte, ep EvalParams) (bool, error) {
+				pass, _, err := EvalStateful(p, ep)
+				return pass, err
+			},
+			func(program []byte, ep EvalParams) (int, error) { return CheckStateful(program, ep) },
+		},
+		4: {
+			CurrentApplicationAddress, globalV1TestProgram + globalV2TestProgram + globalV4TestProgram,
+			func(p []byte, ep EvalParams) (bool, error) {
+				pass, _, err := EvalStateful(p, ep)
+				return pass, err
+			},
+			func(program []byte, ep EvalParams) (int, error) { return CheckStateful(program, ep) },
+		},
 	}
 	ledger := makeTestLedger(nil)
+	creator, err := basics.UnmarshalChecksumAddress("DFPKC2SJP3OTFVJFMCD356YB7BOT4SJZTGWLIPPFEWL3ZABUFLTOY6ILYE")
+	require.NoError(t, err)
+	ledger.newApp(creator, 888)
 	for v := uint64(0); v <= AssemblerDefaultVersion; v++ {
 		t.Run(fmt.Sprintf("v=%d", v), func(t *testing.T) {
 			last := tests[v].lastField
@@ -1203,11 +1236,12 @@ func TestGlobal(t *testing.T) {
 			block.BlockHeader.Round = 999999
 			block.BlockHeader.TimeStamp = 2069
 			proto := config.ConsensusParams{
-				MinTxnFee:       123,
-				MinBalance:      1000000,
-				MaxTxnLife:      999,
-				LogicSigVersion: LogicVersion,
-				LogicSigMaxCost: 20000,
+				MinTxnFee:         123,
+				MinBalance:        1000000,
+				MaxTxnLife:        999,
+				LogicSigVersion:   LogicVersion,
+				LogicSigMaxCost:   20000,
+				MaxAppProgramCost: 700,
 			}
 			ep := defaultEvalParams(&sb, &txn)
 			ep.TxnGroup = txgroup
@@ -1460,6 +1494,101 @@ arg 10
 &&
 `
 
+var testTxnProgramTextV4 = testTxnProgramText + `txn RekeyTo
+txna Accounts 1
+==
+&&
+txn ConfigAsset
+int 55
+==
+&&
+txn ConfigAssetTotal
+int 1000
+==
+&&
+txn ConfigAssetDecimals
+int 2
+==
+&&
+txn ConfigAssetDefaultFrozen
+int 1
+==
+&&
+txn ConfigAssetUnitName
+byte "tok"
+==
+&&
+txn ConfigAssetName
+byte "token"
+==
+&&
+txn ConfigAssetURL
+byte "http://example.com"
+==
+&&
+txn ConfigAssetMetadataHash
+len
+int 32
+==
+&&
+txn ConfigAssetManager
+txn Sender
+==
+&&
+txn ConfigAssetReserve
+txn Receiver
+==
+&&
+txn ConfigAssetFreeze
+txn CloseRemainderTo
+==
+&&
+txn ConfigAssetClawback
+global ZeroAddress
+==
+&&
+txn FreezeAsset
+int 34
+==
+&&
+txn FreezeAssetAccount
+txn Receiver
+==
+&&
+txn FreezeAssetFrozen
+int 1
+==
+&&
+txna Applications 0
+int 123
+==
+&&
+txna Applications 1
+int 1001
+==
+&&
+txn NumApplications
+int 2
+==
+&&
+txn GlobalNumUint
+int 3
+==
+&&
+txn GlobalNumByteSlice
+int 4
+==
+&&
+txn LocalNumUint
+int 5
+==
+&&
+txn LocalNumByteSlice
+int 6
+==
+&&
+`
+
 func makeSampleTxn() transactions.SignedTxn {
 	var txn transactions.SignedTxn
 	copy(txn.Txn.Sender[:], []byte("aoeuiaoeuiaoeuiaoeuiaoeuiaoeui00"))
@@ -1488,6 +1617,22 @@ func makeSampleTxn() transactions.SignedTxn {
 	txn.Txn.Accounts[0] = txn.Txn.Receiver
 	txn.Txn.ApplicationArgs = make([][]byte, 1)
 	txn.Txn.ApplicationArgs[0] = []byte(protocol.PaymentTx)
+	txn.Txn.ConfigAsset = 55
+	txn.Txn.AssetParams.Total = 1000
+	txn.Txn.AssetParams.Decimals = 2
+	txn.Txn.AssetParams.DefaultFrozen = true
+	txn.Txn.AssetParams.UnitName = "tok"
+	txn.Txn.AssetParams.AssetName = "token"
+	txn.Txn.AssetParams.URL = "http://example.com"
+	txn.Txn.AssetParams.Manager = txn.Txn.Sender
+	txn.Txn.AssetParams.Reserve = txn.Txn.Receiver
+	txn.Txn.AssetParams.Freeze = txn.Txn.CloseRemainderTo
+	txn.Txn.FreezeAsset = 34
+	txn.Txn.FreezeAccount = txn.Txn.Receiver
+	txn.Txn.AssetFrozen = true
+	txn.Txn.ForeignApps = []basics.AppIndex{1001, 1002}
+	txn.Txn.GlobalStateSchema = basics.StateSchema{NumUint: 3, NumByteSlice: 4}
+	txn.Txn.LocalStateSchema = basics.StateSchema{NumUint: 5, NumByteSlice: 6}
 	return txn
 }
 
@@ -1506,7 +1651,7 @@ func makeSampleTxnGroup(txn transactions.SignedTxn) []transactions.SignedTxn {
 func TestTxn(t *testing.T) {
 	t.Parallel()
 	for _, txnField := range TxnFieldNames {
-		if !strings.Contains(testTxnProgramText, txnField) {
+		if !strings.Contains(testTxnProgramTextV4, txnField) {
 			if txnField != FirstValidTime.String() {
 				t.Errorf("TestTxn missing field %v", txnField)
 			}
@@ -1516,6 +1661,7 @@ func TestTxn(t *testing.T) {
 	tests := map[uint64]string{
 		1: testTxnProgramTextV1,
 		2: testTxnProgramText,
+		4: testTxnProgramTextV4,
 	}
 
 	clearProgram, err := AssembleStringWithVersion("int 1", 1)
@@ -1532,6 +1678,9 @@ func TestTxn(t *testing.T) {
 			txn.Txn.ApprovalProgram = program
 			txn.Txn.ClearStateProgram = clearProgram
 			txn.Lsig.Logic = program
+			if v >= rekeyingEnabledVersion {
+				txn.Txn.RekeyTo = txn.Txn.Receiver
+			}
 			txid := txn.Txn.ID()
 			programHash := HashProgram(program)
 			clearProgramHash := HashProgram(clearProgram)
@@ -2489,7 +2638,7 @@ func TestIllegalOp(t *testing.T) {
 
 func TestShortProgram(t *testing.T) {
 	t.Parallel()
-	for v := uint64(1); v <= AssemblerDefaultVersion; v++ {
+	for v := uint64(1); v < backBranchEnabledVersion; v++ {
 		t.Run(fmt.Sprintf("v=%d", v), func(t *testing.T) {
 			program, err := AssembleStringWithVersion(`int 1
 bnz done
@@ -2690,6 +2839,28 @@ func TestProgramProtoForbidden(t *testing.T) {
 	isNotPanic(t, err)
 }
 
+func TestRekeyToVersion(t *testing.T) {
+	t.Parallel()
+	txn := makeSampleTxn()
+	txn.Txn.RekeyTo = txn.Txn.Receiver
+	for v := uint64(1); v <= LogicVersion; v++ {
+		t.Run(fmt.Sprintf("v=%d", v), func(t *testing.T) {
+			program, err := AssembleStringWithVersion("int 1", v)
+			require.NoError(t, err)
+			ep := defaultEvalParams(nil, &txn)
+			pass, err := Eval(program, ep)
+			if v < rekeyingEnabledVersion {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "nonzero RekeyTo field")
+				require.False(t, pass)
+			} else {
+				require.NoError(t, err)
+				require.True(t, pass)
+			}
+		})
+	}
+}
+
 func TestMisalignedBranch(t *testing.T) {
 	t.Parallel()
 	for v := uint64(1); v <= AssemblerDefaultVersion; v++ {
@@ -2746,7 +2917,7 @@ int 1`, v)
 
 func TestBranchTooLarge(t *testing.T) {
 	t.Parallel()
-	for v := uint64(1); v <= AssemblerDefaultVersion; v++ {
+	for v := uint64(1); v < backBranchEnabledVersion; v++ {
 		t.Run(fmt.Sprintf("v=%d", v), func(t *testing.T) {
 			program, err := AssembleStringWithVersion(`int 1
 bnz done
@@ -2783,7 +2954,7 @@ int 1
 	for _, line := range branches {
 		t.Run(fmt.Sprintf("branch=%s", line), func(t *testing.T) {
 			source := fmt.Sprintf(template, line)
-			program, err := AssembleString(source)
+			program, err := AssembleStringWithVersion(source, backBranchEnabledVersion-1)
 			require.NoError(t, err)
 			program[7] = 0xff // clobber the branch offset
 			program[8] = 0xff // clobber the branch offset
@@ -2802,10 +2973,11 @@ int 1
 import random
 
 def foo():
-    for i in range(64):
-        print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
-    for i in range(63):
-        print('+')
+
+	for i in range(64):
+	    print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
+	for i in range(63):
+	    print('+')
 */
 const addBenchmarkSource = `int 20472989571761113
 int 80135167795737348
@@ -2940,10 +3112,11 @@ int 28939890412103745
 import random
 
 def foo():
-    print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
-    for i in range(63):
-        print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
-        print('+')
+
+	print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
+	for i in range(63):
+	    print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
+	    print('+')
 */
 const addBenchmark2Source = `int 8371863094338737
 int 29595196041051360
@@ -3520,7 +3693,7 @@ func TestAllowedOpcodesV2(t *testing.T) {
 
 	cnt := 0
 	for _, spec := range OpSpecs {
-		if spec.Version > 1 && !excluded[spec.Name] {
+		if spec.Version == 2 && !excluded[spec.Name] {
 			source, ok := tests[spec.Name]
 			require.True(t, ok, fmt.Sprintf("Missed opcode in the test: %s", spec.Name))
 			program, err := AssembleString(source)
@@ -3561,3 +3734,432 @@ func TestAllowedOpcodesV2(t *testing.T) {
 	}
 	require.Equal(t, len(tests), cnt)
 }
+
+// check all v3 opcodes: allowed in v3 and not allowed before
+func TestAllowedOpcodesV3(t *testing.T) {
+	t.Parallel()
+
+	// all tests are expected to fail in evaluation
+	tests := map[string]string{
+		"assert":    "int 1\nassert",
+		"dig":       "int 1\ndig 0",
+		"swap":      "int 1\nbyte \"x\"\nswap",
+		"select":    "int 1\nbyte \"x\"\nint 1\nselect",
+		"pushint":   "pushint 7\npushint 4",
+		"pushbytes": `pushbytes "stringsfail?"`,
+		"callsub":   "callsub l\nl:",
+		"retsub":    "retsub",
+	}
+
+	ep := defaultEvalParams(nil, nil)
+
+	cnt := 0
+	for _, spec := range OpSpecs {
+		if spec.Version == 3 {
+			source, ok := tests[spec.Name]
+			require.True(t, ok, fmt.Sprintf("Missed opcode in the test: %s", spec.Name))
+			program, err := AssembleStringWithVersion(source, 3)
+			require.NoError(t, err, source)
+			_, err = Check(program, ep)
+			require.NoError(t, err, source)
+			_, err = Eval(program, ep)
+			require.Error(t, err, source)
+			require.NotContains(t, err.Error(), "illegal opcode")
+
+			for v := byte(0); v <= 2; v++ {
+				program[0] = v
+				_, err = Check(program, ep)
+				require.Error(t, err, source)
+				require.True(t,
+					strings.Contains(err.Error(), "illegal opcode") ||
+						strings.Contains(err.Error(), "pc did not advance"),
+				)
+				_, err = Eval(program, ep)
+				require.Error(t, err, source)
+				require.Contains(t, err.Error(), "illegal opcode")
+			}
+			cnt++
+		}
+	}
+	require.Equal(t, len(tests), cnt)
+}
+
+func evalProgramVersion(t *testing.T, source string, version uint64, ep EvalParams) (bool, error) {
+	program, err := AssembleStringWithVersion(source, version)
+	require.NoError(t, err, source)
+	_, err = Check(program, ep)
+	require.NoError(t, err, source)
+	return Eval(program, ep)
+}
+
+func TestStackManipulationV3(t *testing.T) {
+	t.Parallel()
+
+	ep := defaultEvalParams(nil, nil)
+	accepted := []string{
+		"int 1\nint 2\nswap\nint 1\n==\nassert\nint 2\n==",
+		"int 1\nint 2\nint 1\nselect\nint 2\n==",
+		"int 1\nint 2\nint 0\nselect\nint 1\n==",
+		"byte 0x01\nbyte 0x02\nint 7\nselect\nbyte 0x02\n==",
+		"int 3\nint 2\nint 1\ndig 2\nint 3\n==\nassert\npop\npop\nint 3\n==",
+		"int 5\ndig 0\n==",
+		"pushint 1000\nint 1000\n==",
+		"pushbytes \"abc\"\nbyte 0x616263\n==",
+		"pushint 0\npushint 1\nassert\n!",
+	}
+	for _, source := range accepted {
+		pass, err := evalProgramVersion(t, source, 3, ep)
+		require.NoError(t, err, source)
+		require.True(t, pass, source)
+	}
+
+	failures := map[string]string{
+		"int 0\nassert\nint 1":     "assert failed",
+		"int 1\ndig 1":             "dig 1 with stack size = 1",
+		"int 1\nswap":              "stack underflow",
+		"int 1\nint 1\nselect":     "stack underflow",
+		"byte 0x01\nassert\nint 1": "assert arg 0 wanted type uint64",
+	}
+	for source, msg := range failures {
+		program, err := AssembleStringWithVersion(source, 3)
+		if err != nil {
+			// the assembler catches some of the type errors
+			require.Contains(t, err.Error(), "wanted type", source)
+			continue
+		}
+		pass, err := Eval(program, ep)
+		require.Error(t, err, source)
+		require.Contains(t, err.Error(), msg, source)
+		require.False(t, pass, source)
+	}
+}
+
+func TestBackBranch(t *testing.T) {
+	t.Parallel()
+
+	// sum 1..10 in a loop
+	source := `int 0
+int 10
+loop:
+dup
+store 0
++
+load 0
+int 1
+-
+dup
+bnz loop
+pop
+int 55
+==`
+	ep := defaultEvalParams(nil, nil)
+	pass, err := evalProgramVersion(t, source, 3, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	// backward branches are still illegal in v2
+	program, err := AssembleStringWithVersion(source, 3)
+	require.NoError(t, err)
+	program[0] = 2
+	_, err = Check(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "too large")
+
+	// an infinite loop is stopped by the cost budget of the mode
+	source = "loop:\nint 1\nbnz loop\nint 1"
+	program, err = AssembleStringWithVersion(source, 3)
+	require.NoError(t, err)
+	pass, err = Eval(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("dynamic cost budget of %d exceeded", ep.Proto.LogicSigMaxCost))
+	require.False(t, pass)
+
+	ledger := makeTestLedger(nil)
+	ep.Ledger = ledger
+	pass, _, err = EvalStateful(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("dynamic cost budget of %d exceeded", ep.Proto.MaxAppProgramCost))
+	require.False(t, pass)
+
+	// a backward branch into the middle of an instruction is rejected
+	program, err = AssembleStringWithVersion("int 1\nloop:\npushint 7\npop\nint 0\nbnz loop\nint 1", 3)
+	require.NoError(t, err)
+	program[len(program)-2]++ // point bnz into the pushint immediate
+	_, err = Check(program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not an aligned instruction")
+}
+
+func TestSubroutine(t *testing.T) {
+	t.Parallel()
+
+	ep := defaultEvalParams(nil, nil)
+	source := `int 2
+callsub double
+callsub double
+int 8
+==
+assert
+int 1
+b done
+double:
+dup
++
+retsub
+done:`
+	pass, err := evalProgramVersion(t, source, 3, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	// recursion is possible, bounded by the call stack and the budget
+	source = `int 5
+callsub fact
+int 120
+==
+b done
+fact:
+dup
+int 1
+==
+bnz base
+dup
+int 1
+-
+callsub fact
+*
+base:
+retsub
+done:`
+	pass, err = evalProgramVersion(t, source, 3, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	pass, err = evalProgramVersion(t, "int 1\nretsub", 3, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "retsub with empty callstack")
+	require.False(t, pass)
+
+	// unbounded recursion overflows the call stack before the budget
+	pass, err = evalProgramVersion(t, "top:\ncallsub top", 3, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "callsub stack overflow")
+	require.False(t, pass)
+}
+
+// check all v4 opcodes: allowed in v4 and not allowed before
+func TestAllowedOpcodesV4(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"addw":    "int 1\nint 2\naddw",
+		"divmodw": "int 1\nint 2\nint 3\nint 4\ndivmodw",
+		"shl":     "int 1\nint 2\nshl",
+		"shr":     "int 1\nint 2\nshr",
+		"sqrt":    "int 5\nsqrt",
+		"bitlen":  "int 5\nbitlen",
+		"exp":     "int 5\nint 2\nexp",
+		"expw":    "int 5\nint 2\nexpw",
+		"getbit":  "int 15\nint 64\ngetbit",
+		"setbit":  "int 15\nint 64\nint 0\nsetbit",
+		"getbyte": "byte \"john\"\nint 5\ngetbyte",
+		"setbyte": "byte \"john\"\nint 5\nint 7\nsetbyte",
+		"b+":      "byte 0x01\nbyte 0x02\nb+",
+		"b-":      "byte 0x01\nbyte 0x02\nb-",
+		"b/":      "byte 0x01\nbyte 0x\nb/",
+		"b*":      "byte 0x01\nbyte 0x02\nb*",
+		"b<":      "byte 0x01\nbyte 0x02\nb<",
+		"b>":      "byte 0x01\nbyte 0x02\nb>",
+		"b<=":     "byte 0x01\nbyte 0x02\nb<=",
+		"b>=":     "byte 0x01\nbyte 0x02\nb>=",
+		"b==":     "byte 0x01\nbyte 0x02\nb==",
+		"b!=":     "byte 0x01\nbyte 0x01\nb!=",
+		"b%":      "byte 0x01\nbyte 0x\nb%",
+		"b|":      "byte 0x01\nbyte 0x02\nb|",
+		"b&":      "byte 0x01\nbyte 0x02\nb&",
+		"b^":      "byte 0x01\nbyte 0x02\nb^",
+		"b~":      "byte 0x01\nb~",
+		"bzero":   "int 5000\nbzero",
+
+		"app_params_get":  "int 0\napp_params_get AppCreator",
+		"acct_params_get": "int 0\nacct_params_get AcctBalance",
+		"itxn_begin":      "itxn_begin",
+		"itxn_field":      "int 1\nitxn_field TypeEnum",
+		"itxn_submit":     "itxn_submit",
+	}
+
+	ep := defaultEvalParams(nil, nil)
+
+	cnt := 0
+	for _, spec := range OpSpecs {
+		if spec.Version == 4 {
+			source, ok := tests[spec.Name]
+			require.True(t, ok, fmt.Sprintf("Missed opcode in the test: %s", spec.Name))
+			program, err := AssembleStringWithVersion(source, 4)
+			require.NoError(t, err, source)
+			// all opcodes allowed in stateful mode so use CheckStateful/EvalStateful
+			_, err = CheckStateful(program, ep)
+			require.NoError(t, err, source)
+			_, _, err = EvalStateful(program, ep)
+			if err != nil {
+				require.NotContains(t, err.Error(), "illegal opcode")
+			}
+
+			for v := byte(0); v <= 3; v++ {
+				program[0] = v
+				_, err = Check(program, ep)
+				require.Error(t, err, source)
+				require.True(t,
+					strings.Contains(err.Error(), "illegal opcode") ||
+						strings.Contains(err.Error(), "pc did not advance"),
+				)
+				_, err = Eval(program, ep)
+				require.Error(t, err, source)
+				require.Contains(t, err.Error(), "illegal opcode")
+			}
+			cnt++
+		}
+	}
+	require.Equal(t, len(tests), cnt)
+}
+
+func TestWideMath(t *testing.T) {
+	t.Parallel()
+
+	ep := defaultEvalParams(nil, nil)
+	accepted := []string{
+		// addw carries into the high word
+		"int 0xffffffffffffffff\nint 2\naddw\nint 1\n==\nassert\nint 1\n==",
+		"int 1\nint 2\naddw\nint 3\n==\nassert\nint 0\n==",
+		// 2^64+1 divided by 2 is 2^63 remainder 1
+		"int 1\nint 1\nint 0\nint 2\ndivmodw\nint 1\n==\nassert\nint 0\n==\nassert\nint 0x8000000000000000\n==\nassert\nint 0\n==",
+		// divisor wider than the dividend
+		"int 0\nint 7\nint 1\nint 0\ndivmodw\nint 7\n==\nassert\n!\nassert\n!\nassert\n!",
+		"int 1\nint 63\nshl\nint 0x8000000000000000\n==",
+		"int 3\nint 63\nshl\nint 0x8000000000000000\n==",
+		"int 0x8000000000000000\nint 63\nshr\nint 1\n==",
+		"int 15\nsqrt\nint 3\n==",
+		"int 16\nsqrt\nint 4\n==",
+		"int 0xffffffffffffffff\nsqrt\nint 0xffffffff\n==",
+		"int 0\nbitlen\n!",
+		"int 8\nbitlen\nint 4\n==",
+		"byte 0x0001ff\nbitlen\nint 9\n==",
+		"byte 0x\nbitlen\n!",
+		"int 2\nint 63\nexp\nint 0x8000000000000000\n==",
+		"int 0\nint 5\nexp\n!",
+		"int 7\nint 0\nexp\nint 1\n==",
+		"int 2\nint 64\nexpw\nint 0\n==\nassert\nint 1\n==",
+		"int 0xffffffffffffffff\nint 2\nexpw\nint 1\n==\nassert\nint 0xfffffffffffffffe\n==",
+		"int 8\nint 3\ngetbit\nint 1\n==",
+		"byte 0x10\nint 3\ngetbit\nint 1\n==",
+		"int 0\nint 3\nint 1\nsetbit\nint 8\n==",
+		"int 0xff\nint 0\nint 0\nsetbit\nint 0xfe\n==",
+		"byte 0x00000000\nint 0\nint 1\nsetbit\nint 11\nint 1\nsetbit\nbyte 0x80100000\n==",
+		"byte 0xcafe\nint 1\ngetbyte\nint 0xfe\n==",
+		"byte 0xcafe\nint 0\nint 0xbe\nsetbyte\nbyte 0xbefe\n==",
+	}
+	for _, source := range accepted {
+		pass, err := evalProgramVersion(t, source, 4, ep)
+		require.NoError(t, err, source)
+		require.True(t, pass, source)
+	}
+
+	failures := map[string]string{
+		"int 1\nint 0\nint 0\nint 0\ndivmodw": "divmodw 0",
+		"int 1\nint 64\nshl":                  "shl arg too big",
+		"int 1\nint 64\nshr":                  "shr arg too big",
+		"int 0\nint 0\nexp":                   "0^0 is undefined",
+		"int 2\nint 64\nexp":                  "2^64 overflow",
+		"int 3\nint 41\nexp":                  "3^41 overflow",
+		"int 0\nint 0\nexpw":                  "0^0 is undefined",
+		"int 2\nint 128\nexpw":                "2^128 overflow",
+		"int 1\nint 64\ngetbit":               "getbit index > 63",
+		"byte 0x01\nint 8\ngetbit":            "getbit index beyond byteslice",
+		"int 1\nint 1\nint 2\nsetbit":         "setbit value > 1",
+		"byte 0x01\nint 8\nint 1\nsetbit":     "setbit index beyond byteslice",
+		"byte 0x01\nint 1\ngetbyte":           "getbyte index beyond array length",
+		"byte 0x01\nint 0\nint 256\nsetbyte":  "setbyte value > 255",
+	}
+	for source, msg := range failures {
+		pass, err := evalProgramVersion(t, source, 4, ep)
+		require.Error(t, err, source)
+		require.Contains(t, err.Error(), msg, source)
+		require.False(t, pass, source)
+	}
+}
+
+func TestSetByteDoesNotModifyConstants(t *testing.T) {
+	t.Parallel()
+
+	// the constant is loaded twice, the first copy is modified
+	source := `byte 0x0000
+int 0
+int 1
+setbyte
+byte 0x0000
+int 0
+int 1
+int 1
+setbit
+pop
+byte 0x0000
+==
+assert
+byte 0x0100
+==`
+	pass, err := evalProgramVersion(t, source, 4, defaultEvalParams(nil, nil))
+	require.NoError(t, err)
+	require.True(t, pass)
+}
+
+func TestByteMath(t *testing.T) {
+	t.Parallel()
+
+	ep := defaultEvalParams(nil, nil)
+	accepted := []string{
+		"byte 0x01\nbyte 0x01\nb+\nbyte 0x02\n==",
+		"byte 0xff\nbyte 0x01\nb+\nbyte 0x0100\n==",
+		"byte 0x\nbyte 0x\nb+\nbyte 0x\n==",
+		"byte 0x0100\nbyte 0x01\nb-\nbyte 0xff\n==",
+		"byte 0x01\nbyte 0x01\nb-\nbyte 0x\n==",
+		"byte 0x0100\nbyte 0x02\nb/\nbyte 0x80\n==",
+		"byte 0x0101\nbyte 0x02\nb%\nbyte 0x01\n==",
+		"byte 0xffffffffffffffff\nbyte 0xffffffffffffffff\nb*\nbyte 0xfffffffffffffffe0000000000000001\n==",
+		"byte 0x0001\nbyte 0x01\nb==",
+		"byte 0x0001\nbyte 0x01\nb!=\n!",
+		"byte 0x\nbyte 0x00\nb==",
+		"byte 0x01\nbyte 0x0100\nb<",
+		"byte 0x0100\nbyte 0x01\nb>",
+		"byte 0x01\nbyte 0x0001\nb<=",
+		"byte 0x01\nbyte 0x0001\nb>=",
+		"byte 0x0f00\nbyte 0xf0\nb|\nbyte 0x0ff0\n==",
+		"byte 0x0ff0\nbyte 0xf0\nb&\nbyte 0x00f0\n==",
+		"byte 0x0ff0\nbyte 0xff\nb^\nbyte 0x0f0f\n==",
+		"byte 0x0ff0\nb~\nbyte 0xf00f\n==",
+		"byte 0x\nb~\nbyte 0x\n==",
+		"int 3\nbzero\nbyte 0x000000\n==",
+		"int 0\nbzero\nlen\n!",
+		// arithmetic results are never integers
+		"byte 0x01\nbyte 0x01\nb-\nlen\n!",
+	}
+	for _, source := range accepted {
+		pass, err := evalProgramVersion(t, source, 4, ep)
+		require.NoError(t, err, source)
+		require.True(t, pass, source)
+	}
+
+	tooBig := "byte 0x" + strings.Repeat("ff", MaxByteMathSize+1)
+	failures := map[string]string{
+		"byte 0x01\nbyte 0x02\nb-":      "byte math would have negative result",
+		"byte 0x01\nbyte 0x\nb/":        "division by zero",
+		"byte 0x01\nbyte 0x00\nb%":      "modulo by zero",
+		tooBig + "\nbyte 0x01\nb+":      "b+ arguments are limited to 64 bytes",
+		"byte 0x01\n" + tooBig + "\nb<": "b< arguments are limited to 64 bytes",
+		"int 4097\nbzero":               "bzero attempted to create a too large string",
+	}
+	for source, msg := range failures {
+		pass, err := evalProgramVersion(t, source, 4, ep)
+		require.Error(t, err, source)
+		require.Contains(t, err.Error(), msg, source)
+		require.False(t, pass, source)
+	}
+}
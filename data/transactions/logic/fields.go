@@ -17,11 +17,13 @@
 package logic
 
 import (
+	"fmt"
+
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/protocol"
 )
 
-//go:generate stringer -type=TxnField,GlobalField,AssetParamsField,AssetHoldingField,OnCompletionConstType -output=fields_string.go
+//go:generate stringer -type=TxnField,GlobalField,AssetParamsField,AssetHoldingField,AppParamsField,AcctParamsField,OnCompletionConstType -output=fields_string.go
 
 // TxnField is an enum type for `txn` and `gtxn`
 type TxnField int
@@ -91,6 +93,50 @@ const (
 	ApprovalProgram
 	// ClearStateProgram []byte
 	ClearStateProgram
+	// RekeyTo basics.Address
+	RekeyTo
+	// ConfigAsset basics.AssetIndex
+	ConfigAsset
+	// ConfigAssetTotal AssetParams.Total
+	ConfigAssetTotal
+	// ConfigAssetDecimals AssetParams.Decimals
+	ConfigAssetDecimals
+	// ConfigAssetDefaultFrozen AssetParams.AssetDefaultFrozen
+	ConfigAssetDefaultFrozen
+	// ConfigAssetUnitName AssetParams.UnitName
+	ConfigAssetUnitName
+	// ConfigAssetName AssetParams.AssetName
+	ConfigAssetName
+	// ConfigAssetURL AssetParams.URL
+	ConfigAssetURL
+	// ConfigAssetMetadataHash AssetParams.MetadataHash
+	ConfigAssetMetadataHash
+	// ConfigAssetManager AssetParams.Manager
+	ConfigAssetManager
+	// ConfigAssetReserve AssetParams.Reserve
+	ConfigAssetReserve
+	// ConfigAssetFreeze AssetParams.Freeze
+	ConfigAssetFreeze
+	// ConfigAssetClawback AssetParams.Clawback
+	ConfigAssetClawback
+	// FreezeAsset basics.AssetIndex
+	FreezeAsset
+	// FreezeAssetAccount basics.Address
+	FreezeAssetAccount
+	// FreezeAssetFrozen bool
+	FreezeAssetFrozen
+	// Applications []basics.AppIndex
+	Applications
+	// NumApplications len(Applications)
+	NumApplications
+	// GlobalNumUint uint64
+	GlobalNumUint
+	// GlobalNumByteSlice uint64
+	GlobalNumByteSlice
+	// LocalNumUint uint64
+	LocalNumUint
+	// LocalNumByteSlice uint64
+	LocalNumByteSlice
 
 	invalidTxnField // fence for some setup that loops from Sender..invalidTxnField
 )
@@ -109,7 +155,7 @@ type tfNameSpecMap map[string]txnFieldSpec
 
 func (s tfNameSpecMap) getExtraFor(name string) (extra string) {
 	if s[name].version > 1 {
-		extra = "LogicSigVersion >= 2."
+		extra = fmt.Sprintf("LogicSigVersion >= %d.", s[name].version)
 	}
 	return
 }
@@ -153,6 +199,28 @@ var txnFieldSpecs = []txnFieldSpec{
 	{NumAccounts, StackUint64, 2},
 	{ApprovalProgram, StackBytes, 2},
 	{ClearStateProgram, StackBytes, 2},
+	{RekeyTo, StackBytes, 4},
+	{ConfigAsset, StackUint64, 4},
+	{ConfigAssetTotal, StackUint64, 4},
+	{ConfigAssetDecimals, StackUint64, 4},
+	{ConfigAssetDefaultFrozen, StackUint64, 4},
+	{ConfigAssetUnitName, StackBytes, 4},
+	{ConfigAssetName, StackBytes, 4},
+	{ConfigAssetURL, StackBytes, 4},
+	{ConfigAssetMetadataHash, StackBytes, 4},
+	{ConfigAssetManager, StackBytes, 4},
+	{ConfigAssetReserve, StackBytes, 4},
+	{ConfigAssetFreeze, StackBytes, 4},
+	{ConfigAssetClawback, StackBytes, 4},
+	{FreezeAsset, StackUint64, 4},
+	{FreezeAssetAccount, StackBytes, 4},
+	{FreezeAssetFrozen, StackUint64, 4},
+	{Applications, StackUint64, 4},
+	{NumApplications, StackUint64, 4},
+	{GlobalNumUint, StackUint64, 4},
+	{GlobalNumByteSlice, StackUint64, 4},
+	{LocalNumUint, StackUint64, 4},
+	{LocalNumByteSlice, StackUint64, 4},
 }
 
 // TxnTypeNames is the values of Txn.Type in enum order
@@ -218,6 +286,12 @@ const (
 	Round
 	// LatestTimestamp uint64
 	LatestTimestamp
+	// CurrentApplicationID uint64
+	CurrentApplicationID
+	// CreatorAddress [32]byte
+	CreatorAddress
+	// CurrentApplicationAddress [32]byte
+	CurrentApplicationAddress
 
 	invalidGlobalField
 )
@@ -244,6 +318,9 @@ var globalFieldSpecs = []globalFieldSpec{
 	{LogicSigVersion, StackUint64, modeAny, 2},
 	{Round, StackUint64, runModeApplication, 2},
 	{LatestTimestamp, StackUint64, runModeApplication, 2},
+	{CurrentApplicationID, StackUint64, runModeApplication, 4},
+	{CreatorAddress, StackBytes, runModeApplication, 4},
+	{CurrentApplicationAddress, StackBytes, runModeApplication, 4},
 }
 
 // GlobalFieldSpecByField maps GlobalField to spec
@@ -255,7 +332,7 @@ type gfNameSpecMap map[string]globalFieldSpec
 
 func (s gfNameSpecMap) getExtraFor(name string) (extra string) {
 	if s[name].version > 1 {
-		extra = "LogicSigVersion >= 2."
+		extra = fmt.Sprintf("LogicSigVersion >= %d.", s[name].version)
 	}
 	return
 }
@@ -345,6 +422,82 @@ var AssetParamsFieldTypes []StackType
 
 var assetParamsFields map[string]uint
 
+// AppParamsField is an enum for `app_params_get` opcode
+type AppParamsField int
+
+const (
+	// AppApprovalProgramHash SHA512_256 of AppParams.ApprovalProgram
+	AppApprovalProgramHash AppParamsField = iota
+	// AppClearStateProgramHash SHA512_256 of AppParams.ClearStateProgram
+	AppClearStateProgramHash
+	// AppGlobalNumUint AppParams.GlobalStateSchema.NumUint
+	AppGlobalNumUint
+	// AppGlobalNumByteSlice AppParams.GlobalStateSchema.NumByteSlice
+	AppGlobalNumByteSlice
+	// AppLocalNumUint AppParams.LocalStateSchema.NumUint
+	AppLocalNumUint
+	// AppLocalNumByteSlice AppParams.LocalStateSchema.NumByteSlice
+	AppLocalNumByteSlice
+	// AppCreator creator address of the application
+	AppCreator
+	invalidAppParamsField
+)
+
+// AppParamsFieldNames are arguments to the 'app_params_get' opcode
+var AppParamsFieldNames []string
+
+type appParamsFieldType struct {
+	field AppParamsField
+	ftype StackType
+}
+
+var appParamsFieldTypeList = []appParamsFieldType{
+	{AppApprovalProgramHash, StackBytes},
+	{AppClearStateProgramHash, StackBytes},
+	{AppGlobalNumUint, StackUint64},
+	{AppGlobalNumByteSlice, StackUint64},
+	{AppLocalNumUint, StackUint64},
+	{AppLocalNumByteSlice, StackUint64},
+	{AppCreator, StackBytes},
+}
+
+// AppParamsFieldTypes is StackUint64 StackBytes in parallel with AppParamsFieldNames
+var AppParamsFieldTypes []StackType
+
+var appParamsFields map[string]uint
+
+// AcctParamsField is an enum for `acct_params_get` opcode
+type AcctParamsField int
+
+const (
+	// AcctBalance AccountData.MicroAlgos
+	AcctBalance AcctParamsField = iota
+	// AcctMinBalance minimum balance required by AccountData
+	AcctMinBalance
+	// AcctAuthAddr AccountData.AuthAddr
+	AcctAuthAddr
+	invalidAcctParamsField
+)
+
+// AcctParamsFieldNames are arguments to the 'acct_params_get' opcode
+var AcctParamsFieldNames []string
+
+type acctParamsFieldType struct {
+	field AcctParamsField
+	ftype StackType
+}
+
+var acctParamsFieldTypeList = []acctParamsFieldType{
+	{AcctBalance, StackUint64},
+	{AcctMinBalance, StackUint64},
+	{AcctAuthAddr, StackBytes},
+}
+
+// AcctParamsFieldTypes is StackUint64 StackBytes in parallel with AcctParamsFieldNames
+var AcctParamsFieldTypes []StackType
+
+var acctParamsFields map[string]uint
+
 func init() {
 	TxnFieldNames = make([]string, int(invalidTxnField))
 	for fi := Sender; fi < invalidTxnField; fi++ {
@@ -405,6 +558,32 @@ func init() {
 		assetParamsFields[fn] = uint(i)
 	}
 
+	AppParamsFieldNames = make([]string, int(invalidAppParamsField))
+	for i := AppApprovalProgramHash; i < invalidAppParamsField; i++ {
+		AppParamsFieldNames[int(i)] = i.String()
+	}
+	AppParamsFieldTypes = make([]StackType, len(AppParamsFieldNames))
+	for _, ft := range appParamsFieldTypeList {
+		AppParamsFieldTypes[int(ft.field)] = ft.ftype
+	}
+	appParamsFields = make(map[string]uint)
+	for i, fn := range AppParamsFieldNames {
+		appParamsFields[fn] = uint(i)
+	}
+
+	AcctParamsFieldNames = make([]string, int(invalidAcctParamsField))
+	for i := AcctBalance; i < invalidAcctParamsField; i++ {
+		AcctParamsFieldNames[int(i)] = i.String()
+	}
+	AcctParamsFieldTypes = make([]StackType, len(AcctParamsFieldNames))
+	for _, ft := range acctParamsFieldTypeList {
+		AcctParamsFieldTypes[int(ft.field)] = ft.ftype
+	}
+	acctParamsFields = make(map[string]uint)
+	for i, fn := range AcctParamsFieldNames {
+		acctParamsFields[fn] = uint(i)
+	}
+
 	txnTypeIndexes = make(map[string]int, len(TxnTypeNames))
 	for i, tt := range TxnTypeNames {
 		txnTypeIndexes[tt] = i
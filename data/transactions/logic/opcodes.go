@@ -21,7 +21,18 @@ import (
 )
 
 // LogicVersion defines default assembler and max eval versions
-const LogicVersion = 2
+const LogicVersion = 4
+
+// backBranchEnabledVersion is the first version of TEAL where branches
+// may go backward and subroutines are available. Programs of this version
+// are limited by the dynamic cost of their execution rather than by the
+// number of steps.
+const backBranchEnabledVersion = 3
+
+// rekeyingEnabledVersion is the version of TEAL where RekeyTo functionality
+// was enabled. Programs of earlier versions reject any transaction with a
+// nonzero RekeyTo field.
+const rekeyingEnabledVersion = 4
 
 // opSize records the length in bytes for an op that is constant-length but not length 1
 type opSize struct {
@@ -54,6 +65,11 @@ var oneInt = StackTypes{StackUint64}
 var twoInts = StackTypes{StackUint64, StackUint64}
 var oneAny = StackTypes{StackAny}
 var twoAny = StackTypes{StackAny, StackAny}
+var anyAnyInt = StackTypes{StackAny, StackAny, StackUint64}
+var anyInt = StackTypes{StackAny, StackUint64}
+var anyIntInt = StackTypes{StackAny, StackUint64, StackUint64}
+var byteInt = StackTypes{StackBytes, StackUint64}
+var fourInts = twoInts.plus(twoInts)
 
 // OpSpecs is the table of operations that can be assembled and evaluated.
 //
@@ -97,19 +113,21 @@ var OpSpecs = []OpSpec{
 	{0x1b, "^", opBitXor, asmDefault, disDefault, twoInts, oneInt, 1, modeAny, opSizeDefault},
 	{0x1c, "~", opBitNot, asmDefault, disDefault, oneInt, oneInt, 1, modeAny, opSizeDefault},
 	{0x1d, "mulw", opMulw, asmDefault, disDefault, twoInts, twoInts, 1, modeAny, opSizeDefault},
+	{0x1e, "addw", opAddw, asmDefault, disDefault, twoInts, twoInts, 4, modeAny, opSizeDefault},
+	{0x1f, "divmodw", opDivModw, asmDefault, disDefault, fourInts, fourInts, 4, modeAny, opSize{20, 1, nil}},
 
 	{0x20, "intcblock", opIntConstBlock, assembleIntCBlock, disIntcblock, nil, nil, 1, modeAny, opSize{1, 0, checkIntConstBlock}},
 	{0x21, "intc", opIntConstLoad, assembleIntC, disIntc, nil, oneInt, 1, modeAny, opSize{1, 2, nil}},
-	{0x22, "intc_0", opIntConst0, asmDefault, disDefault, nil, oneInt, 1, modeAny, opSizeDefault},
-	{0x23, "intc_1", opIntConst1, asmDefault, disDefault, nil, oneInt, 1, modeAny, opSizeDefault},
-	{0x24, "intc_2", opIntConst2, asmDefault, disDefault, nil, oneInt, 1, modeAny, opSizeDefault},
-	{0x25, "intc_3", opIntConst3, asmDefault, disDefault, nil, oneInt, 1, modeAny, opSizeDefault},
+	{0x22, "intc_0", opIntConst0, asmDefault, disIntcN, nil, oneInt, 1, modeAny, opSizeDefault},
+	{0x23, "intc_1", opIntConst1, asmDefault, disIntcN, nil, oneInt, 1, modeAny, opSizeDefault},
+	{0x24, "intc_2", opIntConst2, asmDefault, disIntcN, nil, oneInt, 1, modeAny, opSizeDefault},
+	{0x25, "intc_3", opIntConst3, asmDefault, disIntcN, nil, oneInt, 1, modeAny, opSizeDefault},
 	{0x26, "bytecblock", opByteConstBlock, assembleByteCBlock, disBytecblock, nil, nil, 1, modeAny, opSize{1, 0, checkByteConstBlock}},
 	{0x27, "bytec", opByteConstLoad, assembleByteC, disBytec, nil, oneBytes, 1, modeAny, opSize{1, 2, nil}},
-	{0x28, "bytec_0", opByteConst0, asmDefault, disDefault, nil, oneBytes, 1, modeAny, opSizeDefault},
-	{0x29, "bytec_1", opByteConst1, asmDefault, disDefault, nil, oneBytes, 1, modeAny, opSizeDefault},
-	{0x2a, "bytec_2", opByteConst2, asmDefault, disDefault, nil, oneBytes, 1, modeAny, opSizeDefault},
-	{0x2b, "bytec_3", opByteConst3, asmDefault, disDefault, nil, oneBytes, 1, modeAny, opSizeDefault},
+	{0x28, "bytec_0", opByteConst0, asmDefault, disBytecN, nil, oneBytes, 1, modeAny, opSizeDefault},
+	{0x29, "bytec_1", opByteConst1, asmDefault, disBytecN, nil, oneBytes, 1, modeAny, opSizeDefault},
+	{0x2a, "bytec_2", opByteConst2, asmDefault, disBytecN, nil, oneBytes, 1, modeAny, opSizeDefault},
+	{0x2b, "bytec_3", opByteConst3, asmDefault, disBytecN, nil, oneBytes, 1, modeAny, opSizeDefault},
 	{0x2c, "arg", opArg, assembleArg, disArg, nil, oneBytes, 1, runModeSignature, opSize{1, 2, nil}},
 	{0x2d, "arg_0", opArg0, asmDefault, disDefault, nil, oneBytes, 1, runModeSignature, opSizeDefault},
 	{0x2e, "arg_1", opArg1, asmDefault, disDefault, nil, oneBytes, 1, runModeSignature, opSizeDefault},
@@ -132,13 +150,23 @@ var OpSpecs = []OpSpec{
 	{0x41, "bz", opBz, assembleBranch, disBranch, oneInt, nil, 2, modeAny, opSize{1, 3, checkBranch}},
 	{0x42, "b", opB, assembleBranch, disBranch, nil, nil, 2, modeAny, opSize{1, 3, checkBranch}},
 	{0x43, "return", opReturn, asmDefault, disDefault, oneInt, nil, 2, modeAny, opSizeDefault},
+	{0x44, "assert", opAssert, asmDefault, disDefault, oneInt, nil, 3, modeAny, opSizeDefault},
 	{0x48, "pop", opPop, asmDefault, disDefault, oneAny, nil, 1, modeAny, opSizeDefault},
 	{0x49, "dup", opDup, asmDefault, disDefault, oneAny, twoAny, 1, modeAny, opSizeDefault},
 	{0x4a, "dup2", opDup2, asmDefault, disDefault, twoAny, twoAny.plus(twoAny), 2, modeAny, opSizeDefault},
+	// There must be at least one thing on the stack for dig, but
+	// it would be nice if we did better checking than that.
+	{0x4b, "dig", opDig, assembleDig, disDig, oneAny, twoAny, 3, modeAny, opSize{1, 2, nil}},
+	{0x4c, "swap", opSwap, asmDefault, disDefault, twoAny, twoAny, 3, modeAny, opSizeDefault},
+	{0x4d, "select", opSelect, asmDefault, disDefault, anyAnyInt, oneAny, 3, modeAny, opSizeDefault},
 
 	{0x50, "concat", opConcat, asmDefault, disDefault, twoBytes, oneBytes, 2, modeAny, opSizeDefault},
 	{0x51, "substring", opSubstring, assembleSubstring, disSubstring, oneBytes, oneBytes, 2, modeAny, opSize{1, 3, nil}},
 	{0x52, "substring3", opSubstring3, asmDefault, disDefault, byteIntInt, oneBytes, 2, modeAny, opSizeDefault},
+	{0x53, "getbit", opGetBit, asmDefault, disDefault, anyInt, oneInt, 4, modeAny, opSizeDefault},
+	{0x54, "setbit", opSetBit, asmDefault, disDefault, anyIntInt, oneAny, 4, modeAny, opSizeDefault},
+	{0x55, "getbyte", opGetByte, asmDefault, disDefault, byteInt, oneInt, 4, modeAny, opSizeDefault},
+	{0x56, "setbyte", opSetByte, asmDefault, disDefault, byteIntInt, oneBytes, 4, modeAny, opSizeDefault},
 
 	{0x60, "balance", opBalance, asmDefault, disDefault, oneInt, oneInt, 2, runModeApplication, opSizeDefault},
 	{0x61, "app_opted_in", opAppCheckOptedIn, asmDefault, disDefault, twoInts, oneInt, 2, runModeApplication, opSizeDefault},
@@ -153,6 +181,47 @@ var OpSpecs = []OpSpec{
 
 	{0x70, "asset_holding_get", opAssetHoldingGet, assembleAssetHolding, disAssetHolding, twoInts, oneInt.plus(oneAny), 2, runModeApplication, opSize{1, 2, nil}},
 	{0x71, "asset_params_get", opAssetParamsGet, assembleAssetParams, disAssetParams, twoInts, oneInt.plus(oneAny), 2, runModeApplication, opSize{1, 2, nil}},
+	{0x72, "app_params_get", opAppParamsGet, assembleAppParams, disAppParams, oneInt, oneInt.plus(oneAny), 4, runModeApplication, opSize{1, 2, nil}},
+	{0x73, "acct_params_get", opAcctParamsGet, assembleAcctParams, disAcctParams, oneInt, oneInt.plus(oneAny), 4, runModeApplication, opSize{1, 2, nil}},
+
+	// Immediate bytes and ints. Size is 0 because the op is variable length.
+	{0x80, "pushbytes", opPushBytes, assemblePushBytes, disPushBytes, nil, oneBytes, 3, modeAny, opSize{1, 0, checkPushBytes}},
+	{0x81, "pushint", opPushInt, assemblePushInt, disPushInt, nil, oneInt, 3, modeAny, opSize{1, 0, checkPushInt}},
+
+	// "Function oriented"
+	{0x88, "callsub", opCallSub, assembleBranch, disBranch, nil, nil, 3, modeAny, opSize{1, 3, checkBranch}},
+	{0x89, "retsub", opRetSub, asmDefault, disDefault, nil, nil, 3, modeAny, opSizeDefault},
+
+	// More math
+	{0x90, "shl", opShiftLeft, asmDefault, disDefault, twoInts, oneInt, 4, modeAny, opSizeDefault},
+	{0x91, "shr", opShiftRight, asmDefault, disDefault, twoInts, oneInt, 4, modeAny, opSizeDefault},
+	{0x92, "sqrt", opSqrt, asmDefault, disDefault, oneInt, oneInt, 4, modeAny, opSize{4, 1, nil}},
+	{0x93, "bitlen", opBitLen, asmDefault, disDefault, oneAny, oneInt, 4, modeAny, opSizeDefault},
+	{0x94, "exp", opExp, asmDefault, disDefault, twoInts, oneInt, 4, modeAny, opSizeDefault},
+	{0x95, "expw", opExpw, asmDefault, disDefault, twoInts, twoInts, 4, modeAny, opSize{10, 1, nil}},
+
+	// Byteslice math, the byteslices are big-endian unsigned integers
+	{0xa0, "b+", opBytesPlus, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, opSize{10, 1, nil}},
+	{0xa1, "b-", opBytesMinus, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, opSize{10, 1, nil}},
+	{0xa2, "b/", opBytesDiv, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, opSize{20, 1, nil}},
+	{0xa3, "b*", opBytesMul, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, opSize{20, 1, nil}},
+	{0xa4, "b<", opBytesLt, asmDefault, disDefault, twoBytes, oneInt, 4, modeAny, opSizeDefault},
+	{0xa5, "b>", opBytesGt, asmDefault, disDefault, twoBytes, oneInt, 4, modeAny, opSizeDefault},
+	{0xa6, "b<=", opBytesLe, asmDefault, disDefault, twoBytes, oneInt, 4, modeAny, opSizeDefault},
+	{0xa7, "b>=", opBytesGe, asmDefault, disDefault, twoBytes, oneInt, 4, modeAny, opSizeDefault},
+	{0xa8, "b==", opBytesEq, asmDefault, disDefault, twoBytes, oneInt, 4, modeAny, opSizeDefault},
+	{0xa9, "b!=", opBytesNeq, asmDefault, disDefault, twoBytes, oneInt, 4, modeAny, opSizeDefault},
+	{0xaa, "b%", opBytesModulo, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, opSize{20, 1, nil}},
+	{0xab, "b|", opBytesBitOr, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, opSize{6, 1, nil}},
+	{0xac, "b&", opBytesBitAnd, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, opSize{6, 1, nil}},
+	{0xad, "b^", opBytesBitXor, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, opSize{6, 1, nil}},
+	{0xae, "b~", opBytesBitNot, asmDefault, disDefault, oneBytes, oneBytes, 4, modeAny, opSize{4, 1, nil}},
+	{0xaf, "bzero", opBytesZero, asmDefault, disDefault, oneInt, oneBytes, 4, modeAny, opSizeDefault},
+
+	// Inner transactions issued from the application account
+	{0xb1, "itxn_begin", opItxnBegin, asmDefault, disDefault, nil, nil, 4, runModeApplication, opSizeDefault},
+	{0xb2, "itxn_field", opItxnField, assembleItxnField, disItxnField, oneAny, nil, 4, runModeApplication, opSize{1, 2, nil}},
+	{0xb3, "itxn_submit", opItxnSubmit, asmDefault, disDefault, nil, nil, 4, runModeApplication, opSizeDefault},
 }
 
 type sortByOpcode []OpSpec
@@ -0,0 +1,175 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sourceMapVersion is the version of the source map format we produce
+const sourceMapVersion = 3
+
+// SourceLocation is the zero based line and column of an instruction in the
+// assembly source
+type SourceLocation struct {
+	Line   int `codec:"line"`
+	Column int `codec:"column"`
+}
+
+// SourceMap relates program counters of a compiled program to the assembly
+// source it was compiled from. It follows the version 3 source map format
+// understood by browser debuggers, where every byte of the program is a line
+// of the generated code, so the n-th group of Mappings holds the source
+// location of the instruction starting at pc n, if any.
+type SourceMap struct {
+	Version    int      `codec:"version"`
+	File       string   `codec:"file,omitempty"`
+	SourceRoot string   `codec:"sourceRoot,omitempty"`
+	Sources    []string `codec:"sources"`
+	Names      []string `codec:"names"`
+	Mappings   string   `codec:"mappings"`
+}
+
+// MakeSourceMap builds the source map of a programLen bytes long program
+// whose instructions were assembled from the given locations of the source
+// named sourceName
+func MakeSourceMap(sourceName string, programLen int, pcToLocation map[int]SourceLocation) SourceMap {
+	sm := SourceMap{
+		Version: sourceMapVersion,
+		Sources: []string{sourceName},
+		Names:   []string{},
+	}
+
+	var sb strings.Builder
+	prev := SourceLocation{}
+	for pc := 0; pc < programLen; pc++ {
+		if pc > 0 {
+			sb.WriteByte(';')
+		}
+		loc, ok := pcToLocation[pc]
+		if !ok {
+			continue
+		}
+		// generated column, source index, source line, source column;
+		// the source ones are relative to the previous segment
+		sb.WriteString(vlqEncode(0))
+		sb.WriteString(vlqEncode(0))
+		sb.WriteString(vlqEncode(loc.Line - prev.Line))
+		sb.WriteString(vlqEncode(loc.Column - prev.Column))
+		prev = loc
+	}
+	sm.Mappings = sb.String()
+	return sm
+}
+
+// PCToLocation decodes the Mappings of the source map into the source
+// location of each pc that starts an instruction
+func (sm SourceMap) PCToLocation() (map[int]SourceLocation, error) {
+	if sm.Version != sourceMapVersion {
+		return nil, fmt.Errorf("unsupported source map version %d", sm.Version)
+	}
+
+	locations := make(map[int]SourceLocation)
+	if len(sm.Mappings) == 0 {
+		return locations, nil
+	}
+
+	loc := SourceLocation{}
+	for pc, group := range strings.Split(sm.Mappings, ";") {
+		if len(group) == 0 {
+			continue
+		}
+		// only the first segment of a group is meaningful, since every
+		// instruction starts at the first column of its generated line
+		segment := strings.SplitN(group, ",", 2)[0]
+		fields, err := vlqDecode(segment)
+		if err != nil {
+			return nil, fmt.Errorf("pc %d: %v", pc, err)
+		}
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("pc %d: segment has %d fields, expected at least 4", pc, len(fields))
+		}
+		loc.Line += fields[2]
+		loc.Column += fields[3]
+		locations[pc] = loc
+	}
+	return locations, nil
+}
+
+const vlqBase64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+const (
+	vlqShift        = 5
+	vlqContinuation = 1 << vlqShift
+	vlqMask         = vlqContinuation - 1
+)
+
+// vlqEncode writes value as a base64 VLQ, least significant digit first,
+// with the sign in the lowest bit
+func vlqEncode(value int) string {
+	var vlq uint64
+	if value < 0 {
+		vlq = uint64(-value)<<1 | 1
+	} else {
+		vlq = uint64(value) << 1
+	}
+
+	var out []byte
+	for {
+		digit := vlq & vlqMask
+		vlq >>= vlqShift
+		if vlq > 0 {
+			digit |= vlqContinuation
+		}
+		out = append(out, vlqBase64Chars[digit])
+		if vlq == 0 {
+			return string(out)
+		}
+	}
+}
+
+// vlqDecode reads all the base64 VLQ values of a segment
+func vlqDecode(segment string) (values []int, err error) {
+	var vlq uint64
+	var shift uint
+	for i := 0; i < len(segment); i++ {
+		digit := strings.IndexByte(vlqBase64Chars, segment[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base64 VLQ character %q", segment[i])
+		}
+		if shift > 60 {
+			return nil, fmt.Errorf("base64 VLQ value too large")
+		}
+		vlq |= uint64(digit&vlqMask) << shift
+		if digit&vlqContinuation != 0 {
+			shift += vlqShift
+			continue
+		}
+		value := int(vlq >> 1)
+		if vlq&1 != 0 {
+			value = -value
+		}
+		values = append(values, value)
+		vlq = 0
+		shift = 0
+	}
+	if shift != 0 {
+		return nil, fmt.Errorf("unterminated base64 VLQ value")
+	}
+	return values, nil
+}
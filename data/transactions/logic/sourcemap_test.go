@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVLQ(t *testing.T) {
+	t.Parallel()
+
+	// values from the source map specification examples
+	require.Equal(t, "A", vlqEncode(0))
+	require.Equal(t, "C", vlqEncode(1))
+	require.Equal(t, "D", vlqEncode(-1))
+	require.Equal(t, "gB", vlqEncode(16))
+	require.Equal(t, "2H", vlqEncode(123))
+
+	values := []int{0, 1, -1, 15, 16, -16, 123, -4567, 1 << 20, -(1 << 40)}
+	segment := ""
+	for _, v := range values {
+		segment += vlqEncode(v)
+	}
+	decoded, err := vlqDecode(segment)
+	require.NoError(t, err)
+	require.Equal(t, values, decoded)
+
+	_, err = vlqDecode("g")
+	require.Error(t, err)
+	_, err = vlqDecode("A!")
+	require.Error(t, err)
+}
+
+func TestSourceMap(t *testing.T) {
+	t.Parallel()
+
+	locations := map[int]SourceLocation{
+		1: {0, 0},
+		3: {2, 4},
+		4: {1, 0},
+	}
+	sm := MakeSourceMap("test.teal", 6, locations)
+	require.Equal(t, sourceMapVersion, sm.Version)
+	require.Equal(t, []string{"test.teal"}, sm.Sources)
+	require.Equal(t, ";AAAA;;AAEI;AADJ;", sm.Mappings)
+
+	decoded, err := sm.PCToLocation()
+	require.NoError(t, err)
+	require.Equal(t, locations, decoded)
+
+	sm.Version = 2
+	_, err = sm.PCToLocation()
+	require.Error(t, err)
+}
+
+func TestAssembleSourceMap(t *testing.T) {
+	t.Parallel()
+
+	source := `#pragma version 2
+int 1
+// comment
+  byte "x"
+label:
+	pop
+`
+	program, sm, err := AssembleStringWithSourceMap(source)
+	require.NoError(t, err)
+
+	// 02 version, 20 01 01 intcblock, 26 01 01 78 bytecblock
+	require.Equal(t, 11, len(program))
+	require.Equal(t, 11, strings.Count(sm.Mappings, ";")+1)
+
+	locations, err := sm.PCToLocation()
+	require.NoError(t, err)
+	require.Equal(t, map[int]SourceLocation{
+		8:  {1, 0},
+		9:  {3, 2},
+		10: {5, 1},
+	}, locations)
+
+	_, _, err = AssembleStringWithSourceMap("bad op")
+	require.Error(t, err)
+}
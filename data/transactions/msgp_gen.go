@@ -676,32 +676,36 @@ func (z *ApplicationCallTxnFields) MsgIsZero() bool {
 func (z *ApplyData) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0001Len := uint32(5)
-	var zb0001Mask uint8 /* 6 bits */
+	zb0002Len := uint32(6)
+	var zb0002Mask uint8 /* 7 bits */
 	if (*z).ClosingAmount.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x2
+		zb0002Len--
+		zb0002Mask |= 0x2
 	}
 	if (*z).EvalDelta.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x4
+		zb0002Len--
+		zb0002Mask |= 0x4
+	}
+	if len((*z).InnerTxns) == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x8
 	}
 	if (*z).CloseRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x8
+		zb0002Len--
+		zb0002Mask |= 0x10
 	}
 	if (*z).ReceiverRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x10
+		zb0002Len--
+		zb0002Mask |= 0x20
 	}
 	if (*z).SenderRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x20
+		zb0002Len--
+		zb0002Mask |= 0x40
 	}
-	// variable map header, size zb0001Len
-	o = append(o, 0x80|uint8(zb0001Len))
-	if zb0001Len != 0 {
-		if (zb0001Mask & 0x2) == 0 { // if not empty
+	// variable map header, size zb0002Len
+	o = append(o, 0x80|uint8(zb0002Len))
+	if zb0002Len != 0 {
+		if (zb0002Mask & 0x2) == 0 { // if not empty
 			// string "ca"
 			o = append(o, 0xa2, 0x63, 0x61)
 			o, err = (*z).ClosingAmount.MarshalMsg(o)
@@ -710,7 +714,7 @@ func (z *ApplyData) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x4) == 0 { // if not empty
+		if (zb0002Mask & 0x4) == 0 { // if not empty
 			// string "dt"
 			o = append(o, 0xa2, 0x64, 0x74)
 			o, err = (*z).EvalDelta.MarshalMsg(o)
@@ -719,7 +723,23 @@ func (z *ApplyData) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x8) == 0 { // if not empty
+		if (zb0002Mask & 0x8) == 0 { // if not empty
+			// string "itx"
+			o = append(o, 0xa3, 0x69, 0x74, 0x78)
+			if (*z).InnerTxns == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).InnerTxns)))
+			}
+			for zb0001 := range (*z).InnerTxns {
+				o, err = (*z).InnerTxns[zb0001].MarshalMsg(o)
+				if err != nil {
+					err = msgp.WrapError(err, "InnerTxns", zb0001)
+					return
+				}
+			}
+		}
+		if (zb0002Mask & 0x10) == 0 { // if not empty
 			// string "rc"
 			o = append(o, 0xa2, 0x72, 0x63)
 			o, err = (*z).CloseRewards.MarshalMsg(o)
@@ -728,7 +748,7 @@ func (z *ApplyData) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x10) == 0 { // if not empty
+		if (zb0002Mask & 0x20) == 0 { // if not empty
 			// string "rr"
 			o = append(o, 0xa2, 0x72, 0x72)
 			o, err = (*z).ReceiverRewards.MarshalMsg(o)
@@ -737,7 +757,7 @@ func (z *ApplyData) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x20) == 0 { // if not empty
+		if (zb0002Mask & 0x40) == 0 { // if not empty
 			// string "rs"
 			o = append(o, 0xa2, 0x72, 0x73)
 			o, err = (*z).SenderRewards.MarshalMsg(o)
@@ -759,57 +779,86 @@ func (_ *ApplyData) CanMarshalMsg(z interface{}) bool {
 func (z *ApplyData) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
-	var zb0001 int
-	var zb0002 bool
-	zb0001, zb0002, bts, err = msgp.ReadMapHeaderBytes(bts)
+	var zb0002 int
+	var zb0003 bool
+	zb0002, zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
 	if _, ok := err.(msgp.TypeError); ok {
-		zb0001, zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		zb0002, zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
 		if err != nil {
 			err = msgp.WrapError(err)
 			return
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).ClosingAmount.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "ClosingAmount")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SenderRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "SenderRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).ReceiverRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "ReceiverRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).CloseRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "CloseRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).EvalDelta.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "EvalDelta")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			err = msgp.ErrTooManyArrayFields(zb0001)
+		if zb0002 > 0 {
+			zb0002--
+			var zb0004 int
+			var zb0005 bool
+			zb0004, zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "InnerTxns")
+				return
+			}
+			if zb0004 > config.MaxInnerTransactions {
+				err = msgp.ErrOverflow(uint64(zb0004), uint64(config.MaxInnerTransactions))
+				err = msgp.WrapError(err, "struct-from-array", "InnerTxns")
+				return
+			}
+			if zb0005 {
+				(*z).InnerTxns = nil
+			} else if (*z).InnerTxns != nil && cap((*z).InnerTxns) >= zb0004 {
+				(*z).InnerTxns = ((*z).InnerTxns)[:zb0004]
+			} else {
+				(*z).InnerTxns = make([]SignedTxnWithAD, zb0004)
+			}
+			for zb0001 := range (*z).InnerTxns {
+				bts, err = (*z).InnerTxns[zb0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "InnerTxns", zb0001)
+					return
+				}
+			}
+		}
+		if zb0002 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0002)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array")
 				return
@@ -820,11 +869,11 @@ func (z *ApplyData) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			err = msgp.WrapError(err)
 			return
 		}
-		if zb0002 {
+		if zb0003 {
 			(*z) = ApplyData{}
 		}
-		for zb0001 > 0 {
-			zb0001--
+		for zb0002 > 0 {
+			zb0002--
 			field, bts, err = msgp.ReadMapKeyZC(bts)
 			if err != nil {
 				err = msgp.WrapError(err)
@@ -861,6 +910,33 @@ func (z *ApplyData) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					err = msgp.WrapError(err, "EvalDelta")
 					return
 				}
+			case "itx":
+				var zb0006 int
+				var zb0007 bool
+				zb0006, zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "InnerTxns")
+					return
+				}
+				if zb0006 > config.MaxInnerTransactions {
+					err = msgp.ErrOverflow(uint64(zb0006), uint64(config.MaxInnerTransactions))
+					err = msgp.WrapError(err, "InnerTxns")
+					return
+				}
+				if zb0007 {
+					(*z).InnerTxns = nil
+				} else if (*z).InnerTxns != nil && cap((*z).InnerTxns) >= zb0006 {
+					(*z).InnerTxns = ((*z).InnerTxns)[:zb0006]
+				} else {
+					(*z).InnerTxns = make([]SignedTxnWithAD, zb0006)
+				}
+				for zb0001 := range (*z).InnerTxns {
+					bts, err = (*z).InnerTxns[zb0001].UnmarshalMsg(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "InnerTxns", zb0001)
+						return
+					}
+				}
 			default:
 				err = msgp.ErrNoField(string(field))
 				if err != nil {
@@ -881,13 +957,16 @@ func (_ *ApplyData) CanUnmarshalMsg(z interface{}) bool {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *ApplyData) Msgsize() (s int) {
-	s = 1 + 3 + (*z).ClosingAmount.Msgsize() + 3 + (*z).SenderRewards.Msgsize() + 3 + (*z).ReceiverRewards.Msgsize() + 3 + (*z).CloseRewards.Msgsize() + 3 + (*z).EvalDelta.Msgsize()
+	s = 1 + 3 + (*z).ClosingAmount.Msgsize() + 3 + (*z).SenderRewards.Msgsize() + 3 + (*z).ReceiverRewards.Msgsize() + 3 + (*z).CloseRewards.Msgsize() + 3 + (*z).EvalDelta.Msgsize() + 4 + msgp.ArrayHeaderSize
+	for zb0001 := range (*z).InnerTxns {
+		s += (*z).InnerTxns[zb0001].Msgsize()
+	}
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
 func (z *ApplyData) MsgIsZero() bool {
-	return ((*z).ClosingAmount.MsgIsZero()) && ((*z).SenderRewards.MsgIsZero()) && ((*z).ReceiverRewards.MsgIsZero()) && ((*z).CloseRewards.MsgIsZero()) && ((*z).EvalDelta.MsgIsZero())
+	return ((*z).ClosingAmount.MsgIsZero()) && ((*z).SenderRewards.MsgIsZero()) && ((*z).ReceiverRewards.MsgIsZero()) && ((*z).CloseRewards.MsgIsZero()) && ((*z).EvalDelta.MsgIsZero()) && (len((*z).InnerTxns) == 0)
 }
 
 // MarshalMsg implements msgp.Marshaler
@@ -2829,60 +2908,64 @@ func (z *SignedTxn) MsgIsZero() bool {
 func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0001Len := uint32(12)
-	var zb0001Mask uint16 /* 16 bits */
+	zb0002Len := uint32(13)
+	var zb0002Mask uint32 /* 17 bits */
 	if (*z).SignedTxnWithAD.ApplyData.ClosingAmount.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x10
+		zb0002Len--
+		zb0002Mask |= 0x10
 	}
 	if (*z).SignedTxnWithAD.ApplyData.EvalDelta.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x20
+		zb0002Len--
+		zb0002Mask |= 0x20
 	}
 	if (*z).HasGenesisHash == false {
-		zb0001Len--
-		zb0001Mask |= 0x40
+		zb0002Len--
+		zb0002Mask |= 0x40
 	}
 	if (*z).HasGenesisID == false {
-		zb0001Len--
-		zb0001Mask |= 0x80
+		zb0002Len--
+		zb0002Mask |= 0x80
+	}
+	if len((*z).SignedTxnWithAD.ApplyData.InnerTxns) == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x100
 	}
 	if (*z).SignedTxnWithAD.SignedTxn.Lsig.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x100
+		zb0002Len--
+		zb0002Mask |= 0x200
 	}
 	if (*z).SignedTxnWithAD.SignedTxn.Msig.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x200
+		zb0002Len--
+		zb0002Mask |= 0x400
 	}
 	if (*z).SignedTxnWithAD.ApplyData.CloseRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x400
+		zb0002Len--
+		zb0002Mask |= 0x800
 	}
 	if (*z).SignedTxnWithAD.ApplyData.ReceiverRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x800
+		zb0002Len--
+		zb0002Mask |= 0x1000
 	}
 	if (*z).SignedTxnWithAD.ApplyData.SenderRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x1000
+		zb0002Len--
+		zb0002Mask |= 0x2000
 	}
 	if (*z).SignedTxnWithAD.SignedTxn.AuthAddr.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x2000
+		zb0002Len--
+		zb0002Mask |= 0x4000
 	}
 	if (*z).SignedTxnWithAD.SignedTxn.Sig.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x4000
+		zb0002Len--
+		zb0002Mask |= 0x8000
 	}
 	if (*z).SignedTxnWithAD.SignedTxn.Txn.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x8000
+		zb0002Len--
+		zb0002Mask |= 0x10000
 	}
-	// variable map header, size zb0001Len
-	o = append(o, 0x80|uint8(zb0001Len))
-	if zb0001Len != 0 {
-		if (zb0001Mask & 0x10) == 0 { // if not empty
+	// variable map header, size zb0002Len
+	o = append(o, 0x80|uint8(zb0002Len))
+	if zb0002Len != 0 {
+		if (zb0002Mask & 0x10) == 0 { // if not empty
 			// string "ca"
 			o = append(o, 0xa2, 0x63, 0x61)
 			o, err = (*z).SignedTxnWithAD.ApplyData.ClosingAmount.MarshalMsg(o)
@@ -2891,7 +2974,7 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x20) == 0 { // if not empty
+		if (zb0002Mask & 0x20) == 0 { // if not empty
 			// string "dt"
 			o = append(o, 0xa2, 0x64, 0x74)
 			o, err = (*z).SignedTxnWithAD.ApplyData.EvalDelta.MarshalMsg(o)
@@ -2900,17 +2983,33 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x40) == 0 { // if not empty
+		if (zb0002Mask & 0x40) == 0 { // if not empty
 			// string "hgh"
 			o = append(o, 0xa3, 0x68, 0x67, 0x68)
 			o = msgp.AppendBool(o, (*z).HasGenesisHash)
 		}
-		if (zb0001Mask & 0x80) == 0 { // if not empty
+		if (zb0002Mask & 0x80) == 0 { // if not empty
 			// string "hgi"
 			o = append(o, 0xa3, 0x68, 0x67, 0x69)
 			o = msgp.AppendBool(o, (*z).HasGenesisID)
 		}
-		if (zb0001Mask & 0x100) == 0 { // if not empty
+		if (zb0002Mask & 0x100) == 0 { // if not empty
+			// string "itx"
+			o = append(o, 0xa3, 0x69, 0x74, 0x78)
+			if (*z).SignedTxnWithAD.ApplyData.InnerTxns == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).SignedTxnWithAD.ApplyData.InnerTxns)))
+			}
+			for zb0001 := range (*z).SignedTxnWithAD.ApplyData.InnerTxns {
+				o, err = (*z).SignedTxnWithAD.ApplyData.InnerTxns[zb0001].MarshalMsg(o)
+				if err != nil {
+					err = msgp.WrapError(err, "InnerTxns", zb0001)
+					return
+				}
+			}
+		}
+		if (zb0002Mask & 0x200) == 0 { // if not empty
 			// string "lsig"
 			o = append(o, 0xa4, 0x6c, 0x73, 0x69, 0x67)
 			o, err = (*z).SignedTxnWithAD.SignedTxn.Lsig.MarshalMsg(o)
@@ -2919,7 +3018,7 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x200) == 0 { // if not empty
+		if (zb0002Mask & 0x400) == 0 { // if not empty
 			// string "msig"
 			o = append(o, 0xa4, 0x6d, 0x73, 0x69, 0x67)
 			o, err = (*z).SignedTxnWithAD.SignedTxn.Msig.MarshalMsg(o)
@@ -2928,7 +3027,7 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x400) == 0 { // if not empty
+		if (zb0002Mask & 0x800) == 0 { // if not empty
 			// string "rc"
 			o = append(o, 0xa2, 0x72, 0x63)
 			o, err = (*z).SignedTxnWithAD.ApplyData.CloseRewards.MarshalMsg(o)
@@ -2937,7 +3036,7 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x800) == 0 { // if not empty
+		if (zb0002Mask & 0x1000) == 0 { // if not empty
 			// string "rr"
 			o = append(o, 0xa2, 0x72, 0x72)
 			o, err = (*z).SignedTxnWithAD.ApplyData.ReceiverRewards.MarshalMsg(o)
@@ -2946,7 +3045,7 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x1000) == 0 { // if not empty
+		if (zb0002Mask & 0x2000) == 0 { // if not empty
 			// string "rs"
 			o = append(o, 0xa2, 0x72, 0x73)
 			o, err = (*z).SignedTxnWithAD.ApplyData.SenderRewards.MarshalMsg(o)
@@ -2955,7 +3054,7 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x2000) == 0 { // if not empty
+		if (zb0002Mask & 0x4000) == 0 { // if not empty
 			// string "sgnr"
 			o = append(o, 0xa4, 0x73, 0x67, 0x6e, 0x72)
 			o, err = (*z).SignedTxnWithAD.SignedTxn.AuthAddr.MarshalMsg(o)
@@ -2964,7 +3063,7 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x4000) == 0 { // if not empty
+		if (zb0002Mask & 0x8000) == 0 { // if not empty
 			// string "sig"
 			o = append(o, 0xa3, 0x73, 0x69, 0x67)
 			o, err = (*z).SignedTxnWithAD.SignedTxn.Sig.MarshalMsg(o)
@@ -2973,7 +3072,7 @@ func (z *SignedTxnInBlock) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x8000) == 0 { // if not empty
+		if (zb0002Mask & 0x10000) == 0 { // if not empty
 			// string "txn"
 			o = append(o, 0xa3, 0x74, 0x78, 0x6e)
 			o, err = (*z).SignedTxnWithAD.SignedTxn.Txn.MarshalMsg(o)
@@ -2995,113 +3094,142 @@ func (_ *SignedTxnInBlock) CanMarshalMsg(z interface{}) bool {
 func (z *SignedTxnInBlock) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
-	var zb0001 int
-	var zb0002 bool
-	zb0001, zb0002, bts, err = msgp.ReadMapHeaderBytes(bts)
+	var zb0002 int
+	var zb0003 bool
+	zb0002, zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
 	if _, ok := err.(msgp.TypeError); ok {
-		zb0001, zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		zb0002, zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
 		if err != nil {
 			err = msgp.WrapError(err)
 			return
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.SignedTxn.Sig.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "Sig")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.SignedTxn.Msig.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "Msig")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.SignedTxn.Lsig.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "Lsig")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.SignedTxn.Txn.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "Txn")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.SignedTxn.AuthAddr.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "AuthAddr")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.ApplyData.ClosingAmount.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "ClosingAmount")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.ApplyData.SenderRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "SenderRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.ApplyData.ReceiverRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "ReceiverRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.ApplyData.CloseRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "CloseRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxnWithAD.ApplyData.EvalDelta.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "EvalDelta")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
+			var zb0004 int
+			var zb0005 bool
+			zb0004, zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "InnerTxns")
+				return
+			}
+			if zb0004 > config.MaxInnerTransactions {
+				err = msgp.ErrOverflow(uint64(zb0004), uint64(config.MaxInnerTransactions))
+				err = msgp.WrapError(err, "struct-from-array", "InnerTxns")
+				return
+			}
+			if zb0005 {
+				(*z).SignedTxnWithAD.ApplyData.InnerTxns = nil
+			} else if (*z).SignedTxnWithAD.ApplyData.InnerTxns != nil && cap((*z).SignedTxnWithAD.ApplyData.InnerTxns) >= zb0004 {
+				(*z).SignedTxnWithAD.ApplyData.InnerTxns = ((*z).SignedTxnWithAD.ApplyData.InnerTxns)[:zb0004]
+			} else {
+				(*z).SignedTxnWithAD.ApplyData.InnerTxns = make([]SignedTxnWithAD, zb0004)
+			}
+			for zb0001 := range (*z).SignedTxnWithAD.ApplyData.InnerTxns {
+				bts, err = (*z).SignedTxnWithAD.ApplyData.InnerTxns[zb0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "InnerTxns", zb0001)
+					return
+				}
+			}
+		}
+		if zb0002 > 0 {
+			zb0002--
 			(*z).HasGenesisID, bts, err = msgp.ReadBoolBytes(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "HasGenesisID")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			(*z).HasGenesisHash, bts, err = msgp.ReadBoolBytes(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "HasGenesisHash")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			err = msgp.ErrTooManyArrayFields(zb0001)
+		if zb0002 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0002)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array")
 				return
@@ -3112,11 +3240,11 @@ func (z *SignedTxnInBlock) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			err = msgp.WrapError(err)
 			return
 		}
-		if zb0002 {
+		if zb0003 {
 			(*z) = SignedTxnInBlock{}
 		}
-		for zb0001 > 0 {
-			zb0001--
+		for zb0002 > 0 {
+			zb0002--
 			field, bts, err = msgp.ReadMapKeyZC(bts)
 			if err != nil {
 				err = msgp.WrapError(err)
@@ -3183,6 +3311,33 @@ func (z *SignedTxnInBlock) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					err = msgp.WrapError(err, "EvalDelta")
 					return
 				}
+			case "itx":
+				var zb0006 int
+				var zb0007 bool
+				zb0006, zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "InnerTxns")
+					return
+				}
+				if zb0006 > config.MaxInnerTransactions {
+					err = msgp.ErrOverflow(uint64(zb0006), uint64(config.MaxInnerTransactions))
+					err = msgp.WrapError(err, "InnerTxns")
+					return
+				}
+				if zb0007 {
+					(*z).SignedTxnWithAD.ApplyData.InnerTxns = nil
+				} else if (*z).SignedTxnWithAD.ApplyData.InnerTxns != nil && cap((*z).SignedTxnWithAD.ApplyData.InnerTxns) >= zb0006 {
+					(*z).SignedTxnWithAD.ApplyData.InnerTxns = ((*z).SignedTxnWithAD.ApplyData.InnerTxns)[:zb0006]
+				} else {
+					(*z).SignedTxnWithAD.ApplyData.InnerTxns = make([]SignedTxnWithAD, zb0006)
+				}
+				for zb0001 := range (*z).SignedTxnWithAD.ApplyData.InnerTxns {
+					bts, err = (*z).SignedTxnWithAD.ApplyData.InnerTxns[zb0001].UnmarshalMsg(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "InnerTxns", zb0001)
+						return
+					}
+				}
 			case "hgi":
 				(*z).HasGenesisID, bts, err = msgp.ReadBoolBytes(bts)
 				if err != nil {
@@ -3215,65 +3370,73 @@ func (_ *SignedTxnInBlock) CanUnmarshalMsg(z interface{}) bool {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *SignedTxnInBlock) Msgsize() (s int) {
-	s = 1 + 4 + (*z).SignedTxnWithAD.SignedTxn.Sig.Msgsize() + 5 + (*z).SignedTxnWithAD.SignedTxn.Msig.Msgsize() + 5 + (*z).SignedTxnWithAD.SignedTxn.Lsig.Msgsize() + 4 + (*z).SignedTxnWithAD.SignedTxn.Txn.Msgsize() + 5 + (*z).SignedTxnWithAD.SignedTxn.AuthAddr.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.ClosingAmount.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.SenderRewards.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.ReceiverRewards.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.CloseRewards.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.EvalDelta.Msgsize() + 4 + msgp.BoolSize + 4 + msgp.BoolSize
+	s = 1 + 4 + (*z).SignedTxnWithAD.SignedTxn.Sig.Msgsize() + 5 + (*z).SignedTxnWithAD.SignedTxn.Msig.Msgsize() + 5 + (*z).SignedTxnWithAD.SignedTxn.Lsig.Msgsize() + 4 + (*z).SignedTxnWithAD.SignedTxn.Txn.Msgsize() + 5 + (*z).SignedTxnWithAD.SignedTxn.AuthAddr.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.ClosingAmount.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.SenderRewards.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.ReceiverRewards.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.CloseRewards.Msgsize() + 3 + (*z).SignedTxnWithAD.ApplyData.EvalDelta.Msgsize() + 4 + msgp.ArrayHeaderSize
+	for zb0001 := range (*z).SignedTxnWithAD.ApplyData.InnerTxns {
+		s += (*z).SignedTxnWithAD.ApplyData.InnerTxns[zb0001].Msgsize()
+	}
+	s += 4 + msgp.BoolSize + 4 + msgp.BoolSize
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
 func (z *SignedTxnInBlock) MsgIsZero() bool {
-	return ((*z).SignedTxnWithAD.SignedTxn.Sig.MsgIsZero()) && ((*z).SignedTxnWithAD.SignedTxn.Msig.MsgIsZero()) && ((*z).SignedTxnWithAD.SignedTxn.Lsig.MsgIsZero()) && ((*z).SignedTxnWithAD.SignedTxn.Txn.MsgIsZero()) && ((*z).SignedTxnWithAD.SignedTxn.AuthAddr.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.ClosingAmount.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.SenderRewards.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.ReceiverRewards.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.CloseRewards.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.EvalDelta.MsgIsZero()) && ((*z).HasGenesisID == false) && ((*z).HasGenesisHash == false)
+	return ((*z).SignedTxnWithAD.SignedTxn.Sig.MsgIsZero()) && ((*z).SignedTxnWithAD.SignedTxn.Msig.MsgIsZero()) && ((*z).SignedTxnWithAD.SignedTxn.Lsig.MsgIsZero()) && ((*z).SignedTxnWithAD.SignedTxn.Txn.MsgIsZero()) && ((*z).SignedTxnWithAD.SignedTxn.AuthAddr.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.ClosingAmount.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.SenderRewards.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.ReceiverRewards.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.CloseRewards.MsgIsZero()) && ((*z).SignedTxnWithAD.ApplyData.EvalDelta.MsgIsZero()) && (len((*z).SignedTxnWithAD.ApplyData.InnerTxns) == 0) && ((*z).HasGenesisID == false) && ((*z).HasGenesisHash == false)
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0001Len := uint32(10)
-	var zb0001Mask uint16 /* 13 bits */
+	zb0002Len := uint32(11)
+	var zb0002Mask uint16 /* 14 bits */
 	if (*z).ApplyData.ClosingAmount.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x8
+		zb0002Len--
+		zb0002Mask |= 0x8
 	}
 	if (*z).ApplyData.EvalDelta.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x10
+		zb0002Len--
+		zb0002Mask |= 0x10
+	}
+	if len((*z).ApplyData.InnerTxns) == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x20
 	}
 	if (*z).SignedTxn.Lsig.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x20
+		zb0002Len--
+		zb0002Mask |= 0x40
 	}
 	if (*z).SignedTxn.Msig.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x40
+		zb0002Len--
+		zb0002Mask |= 0x80
 	}
 	if (*z).ApplyData.CloseRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x80
+		zb0002Len--
+		zb0002Mask |= 0x100
 	}
 	if (*z).ApplyData.ReceiverRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x100
+		zb0002Len--
+		zb0002Mask |= 0x200
 	}
 	if (*z).ApplyData.SenderRewards.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x200
+		zb0002Len--
+		zb0002Mask |= 0x400
 	}
 	if (*z).SignedTxn.AuthAddr.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x400
+		zb0002Len--
+		zb0002Mask |= 0x800
 	}
 	if (*z).SignedTxn.Sig.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x800
+		zb0002Len--
+		zb0002Mask |= 0x1000
 	}
 	if (*z).SignedTxn.Txn.MsgIsZero() {
-		zb0001Len--
-		zb0001Mask |= 0x1000
+		zb0002Len--
+		zb0002Mask |= 0x2000
 	}
-	// variable map header, size zb0001Len
-	o = append(o, 0x80|uint8(zb0001Len))
-	if zb0001Len != 0 {
-		if (zb0001Mask & 0x8) == 0 { // if not empty
+	// variable map header, size zb0002Len
+	o = append(o, 0x80|uint8(zb0002Len))
+	if zb0002Len != 0 {
+		if (zb0002Mask & 0x8) == 0 { // if not empty
 			// string "ca"
 			o = append(o, 0xa2, 0x63, 0x61)
 			o, err = (*z).ApplyData.ClosingAmount.MarshalMsg(o)
@@ -3282,7 +3445,7 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x10) == 0 { // if not empty
+		if (zb0002Mask & 0x10) == 0 { // if not empty
 			// string "dt"
 			o = append(o, 0xa2, 0x64, 0x74)
 			o, err = (*z).ApplyData.EvalDelta.MarshalMsg(o)
@@ -3291,7 +3454,23 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x20) == 0 { // if not empty
+		if (zb0002Mask & 0x20) == 0 { // if not empty
+			// string "itx"
+			o = append(o, 0xa3, 0x69, 0x74, 0x78)
+			if (*z).ApplyData.InnerTxns == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).ApplyData.InnerTxns)))
+			}
+			for zb0001 := range (*z).ApplyData.InnerTxns {
+				o, err = (*z).ApplyData.InnerTxns[zb0001].MarshalMsg(o)
+				if err != nil {
+					err = msgp.WrapError(err, "InnerTxns", zb0001)
+					return
+				}
+			}
+		}
+		if (zb0002Mask & 0x40) == 0 { // if not empty
 			// string "lsig"
 			o = append(o, 0xa4, 0x6c, 0x73, 0x69, 0x67)
 			o, err = (*z).SignedTxn.Lsig.MarshalMsg(o)
@@ -3300,7 +3479,7 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x40) == 0 { // if not empty
+		if (zb0002Mask & 0x80) == 0 { // if not empty
 			// string "msig"
 			o = append(o, 0xa4, 0x6d, 0x73, 0x69, 0x67)
 			o, err = (*z).SignedTxn.Msig.MarshalMsg(o)
@@ -3309,7 +3488,7 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x80) == 0 { // if not empty
+		if (zb0002Mask & 0x100) == 0 { // if not empty
 			// string "rc"
 			o = append(o, 0xa2, 0x72, 0x63)
 			o, err = (*z).ApplyData.CloseRewards.MarshalMsg(o)
@@ -3318,7 +3497,7 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x100) == 0 { // if not empty
+		if (zb0002Mask & 0x200) == 0 { // if not empty
 			// string "rr"
 			o = append(o, 0xa2, 0x72, 0x72)
 			o, err = (*z).ApplyData.ReceiverRewards.MarshalMsg(o)
@@ -3327,7 +3506,7 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x200) == 0 { // if not empty
+		if (zb0002Mask & 0x400) == 0 { // if not empty
 			// string "rs"
 			o = append(o, 0xa2, 0x72, 0x73)
 			o, err = (*z).ApplyData.SenderRewards.MarshalMsg(o)
@@ -3336,7 +3515,7 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x400) == 0 { // if not empty
+		if (zb0002Mask & 0x800) == 0 { // if not empty
 			// string "sgnr"
 			o = append(o, 0xa4, 0x73, 0x67, 0x6e, 0x72)
 			o, err = (*z).SignedTxn.AuthAddr.MarshalMsg(o)
@@ -3345,7 +3524,7 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x800) == 0 { // if not empty
+		if (zb0002Mask & 0x1000) == 0 { // if not empty
 			// string "sig"
 			o = append(o, 0xa3, 0x73, 0x69, 0x67)
 			o, err = (*z).SignedTxn.Sig.MarshalMsg(o)
@@ -3354,7 +3533,7 @@ func (z *SignedTxnWithAD) MarshalMsg(b []byte) (o []byte, err error) {
 				return
 			}
 		}
-		if (zb0001Mask & 0x1000) == 0 { // if not empty
+		if (zb0002Mask & 0x2000) == 0 { // if not empty
 			// string "txn"
 			o = append(o, 0xa3, 0x74, 0x78, 0x6e)
 			o, err = (*z).SignedTxn.Txn.MarshalMsg(o)
@@ -3376,97 +3555,126 @@ func (_ *SignedTxnWithAD) CanMarshalMsg(z interface{}) bool {
 func (z *SignedTxnWithAD) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
-	var zb0001 int
-	var zb0002 bool
-	zb0001, zb0002, bts, err = msgp.ReadMapHeaderBytes(bts)
+	var zb0002 int
+	var zb0003 bool
+	zb0002, zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
 	if _, ok := err.(msgp.TypeError); ok {
-		zb0001, zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		zb0002, zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
 		if err != nil {
 			err = msgp.WrapError(err)
 			return
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxn.Sig.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "Sig")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxn.Msig.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "Msig")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxn.Lsig.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "Lsig")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxn.Txn.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "Txn")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).SignedTxn.AuthAddr.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "AuthAddr")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).ApplyData.ClosingAmount.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "ClosingAmount")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).ApplyData.SenderRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "SenderRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).ApplyData.ReceiverRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "ReceiverRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).ApplyData.CloseRewards.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "CloseRewards")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			zb0001--
+		if zb0002 > 0 {
+			zb0002--
 			bts, err = (*z).ApplyData.EvalDelta.UnmarshalMsg(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array", "EvalDelta")
 				return
 			}
 		}
-		if zb0001 > 0 {
-			err = msgp.ErrTooManyArrayFields(zb0001)
+		if zb0002 > 0 {
+			zb0002--
+			var zb0004 int
+			var zb0005 bool
+			zb0004, zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "InnerTxns")
+				return
+			}
+			if zb0004 > config.MaxInnerTransactions {
+				err = msgp.ErrOverflow(uint64(zb0004), uint64(config.MaxInnerTransactions))
+				err = msgp.WrapError(err, "struct-from-array", "InnerTxns")
+				return
+			}
+			if zb0005 {
+				(*z).ApplyData.InnerTxns = nil
+			} else if (*z).ApplyData.InnerTxns != nil && cap((*z).ApplyData.InnerTxns) >= zb0004 {
+				(*z).ApplyData.InnerTxns = ((*z).ApplyData.InnerTxns)[:zb0004]
+			} else {
+				(*z).ApplyData.InnerTxns = make([]SignedTxnWithAD, zb0004)
+			}
+			for zb0001 := range (*z).ApplyData.InnerTxns {
+				bts, err = (*z).ApplyData.InnerTxns[zb0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "InnerTxns", zb0001)
+					return
+				}
+			}
+		}
+		if zb0002 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0002)
 			if err != nil {
 				err = msgp.WrapError(err, "struct-from-array")
 				return
@@ -3477,11 +3685,11 @@ func (z *SignedTxnWithAD) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			err = msgp.WrapError(err)
 			return
 		}
-		if zb0002 {
+		if zb0003 {
 			(*z) = SignedTxnWithAD{}
 		}
-		for zb0001 > 0 {
-			zb0001--
+		for zb0002 > 0 {
+			zb0002--
 			field, bts, err = msgp.ReadMapKeyZC(bts)
 			if err != nil {
 				err = msgp.WrapError(err)
@@ -3548,6 +3756,33 @@ func (z *SignedTxnWithAD) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					err = msgp.WrapError(err, "EvalDelta")
 					return
 				}
+			case "itx":
+				var zb0006 int
+				var zb0007 bool
+				zb0006, zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "InnerTxns")
+					return
+				}
+				if zb0006 > config.MaxInnerTransactions {
+					err = msgp.ErrOverflow(uint64(zb0006), uint64(config.MaxInnerTransactions))
+					err = msgp.WrapError(err, "InnerTxns")
+					return
+				}
+				if zb0007 {
+					(*z).ApplyData.InnerTxns = nil
+				} else if (*z).ApplyData.InnerTxns != nil && cap((*z).ApplyData.InnerTxns) >= zb0006 {
+					(*z).ApplyData.InnerTxns = ((*z).ApplyData.InnerTxns)[:zb0006]
+				} else {
+					(*z).ApplyData.InnerTxns = make([]SignedTxnWithAD, zb0006)
+				}
+				for zb0001 := range (*z).ApplyData.InnerTxns {
+					bts, err = (*z).ApplyData.InnerTxns[zb0001].UnmarshalMsg(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "InnerTxns", zb0001)
+						return
+					}
+				}
 			default:
 				err = msgp.ErrNoField(string(field))
 				if err != nil {
@@ -3568,13 +3803,16 @@ func (_ *SignedTxnWithAD) CanUnmarshalMsg(z interface{}) bool {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *SignedTxnWithAD) Msgsize() (s int) {
-	s = 1 + 4 + (*z).SignedTxn.Sig.Msgsize() + 5 + (*z).SignedTxn.Msig.Msgsize() + 5 + (*z).SignedTxn.Lsig.Msgsize() + 4 + (*z).SignedTxn.Txn.Msgsize() + 5 + (*z).SignedTxn.AuthAddr.Msgsize() + 3 + (*z).ApplyData.ClosingAmount.Msgsize() + 3 + (*z).ApplyData.SenderRewards.Msgsize() + 3 + (*z).ApplyData.ReceiverRewards.Msgsize() + 3 + (*z).ApplyData.CloseRewards.Msgsize() + 3 + (*z).ApplyData.EvalDelta.Msgsize()
+	s = 1 + 4 + (*z).SignedTxn.Sig.Msgsize() + 5 + (*z).SignedTxn.Msig.Msgsize() + 5 + (*z).SignedTxn.Lsig.Msgsize() + 4 + (*z).SignedTxn.Txn.Msgsize() + 5 + (*z).SignedTxn.AuthAddr.Msgsize() + 3 + (*z).ApplyData.ClosingAmount.Msgsize() + 3 + (*z).ApplyData.SenderRewards.Msgsize() + 3 + (*z).ApplyData.ReceiverRewards.Msgsize() + 3 + (*z).ApplyData.CloseRewards.Msgsize() + 3 + (*z).ApplyData.EvalDelta.Msgsize() + 4 + msgp.ArrayHeaderSize
+	for zb0001 := range (*z).ApplyData.InnerTxns {
+		s += (*z).ApplyData.InnerTxns[zb0001].Msgsize()
+	}
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
 func (z *SignedTxnWithAD) MsgIsZero() bool {
-	return ((*z).SignedTxn.Sig.MsgIsZero()) && ((*z).SignedTxn.Msig.MsgIsZero()) && ((*z).SignedTxn.Lsig.MsgIsZero()) && ((*z).SignedTxn.Txn.MsgIsZero()) && ((*z).SignedTxn.AuthAddr.MsgIsZero()) && ((*z).ApplyData.ClosingAmount.MsgIsZero()) && ((*z).ApplyData.SenderRewards.MsgIsZero()) && ((*z).ApplyData.ReceiverRewards.MsgIsZero()) && ((*z).ApplyData.CloseRewards.MsgIsZero()) && ((*z).ApplyData.EvalDelta.MsgIsZero())
+	return ((*z).SignedTxn.Sig.MsgIsZero()) && ((*z).SignedTxn.Msig.MsgIsZero()) && ((*z).SignedTxn.Lsig.MsgIsZero()) && ((*z).SignedTxn.Txn.MsgIsZero()) && ((*z).SignedTxn.AuthAddr.MsgIsZero()) && ((*z).ApplyData.ClosingAmount.MsgIsZero()) && ((*z).ApplyData.SenderRewards.MsgIsZero()) && ((*z).ApplyData.ReceiverRewards.MsgIsZero()) && ((*z).ApplyData.CloseRewards.MsgIsZero()) && ((*z).ApplyData.EvalDelta.MsgIsZero()) && (len((*z).ApplyData.InnerTxns) == 0)
 }
 
 // MarshalMsg implements msgp.Marshaler
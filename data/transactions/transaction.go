@@ -17,6 +17,7 @@
 package transactions
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/algorand/go-algorand/config"
@@ -81,7 +82,7 @@ type Balances interface {
 // functionality that may be passed through to Apply from ledger, avoiding a
 // circular dependency between the logic and transactions packages
 type StateEvaluator interface {
-	Eval(program []byte) (pass bool, stateDelta basics.EvalDelta, err error)
+	Eval(program []byte) (pass bool, stateDelta basics.EvalDelta, innerTxns []SignedTxn, err error)
 	Check(program []byte) (cost int, err error)
 	InitLedger(balances Balances, acctWhitelist []basics.Address, appGlobalWhitelist []basics.AppIndex, appIdx basics.AppIndex) error
 }
@@ -148,6 +149,10 @@ type ApplyData struct {
 	ReceiverRewards basics.MicroAlgos `codec:"rr"`
 	CloseRewards    basics.MicroAlgos `codec:"rc"`
 	EvalDelta       basics.EvalDelta  `codec:"dt"`
+
+	// Inner transactions issued by an application call, along with the
+	// ApplyData that resulted from applying each of them.
+	InnerTxns []SignedTxnWithAD `codec:"itx,allocbound=config.MaxInnerTransactions"`
 }
 
 // Equal returns true if two ApplyDatas are equal, ignoring nilness equality on
@@ -168,6 +173,17 @@ func (ad ApplyData) Equal(o ApplyData) bool {
 	if !ad.EvalDelta.Equal(o.EvalDelta) {
 		return false
 	}
+	if len(ad.InnerTxns) != len(o.InnerTxns) {
+		return false
+	}
+	for i := range ad.InnerTxns {
+		if !bytes.Equal(protocol.Encode(&ad.InnerTxns[i].SignedTxn), protocol.Encode(&o.InnerTxns[i].SignedTxn)) {
+			return false
+		}
+		if !ad.InnerTxns[i].ApplyData.Equal(o.InnerTxns[i].ApplyData) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -22,7 +22,11 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/pools"
 	"github.com/algorand/go-algorand/data/transactions"
@@ -39,9 +43,41 @@ import (
 // execution pool for a long duration of time.
 const txBacklogSize = 1000
 
+// The size txRequestBacklogSize used to determine the size of the backlogs of the announced transaction groups we request, and of the
+// requests of our peers we serve, before starting dropping them.
+const txRequestBacklogSize = 1000
+
+// txRequestWorkers is the number of announced transaction groups we request concurrently.
+const txRequestWorkers = 8
+
+// txRequestsPerPeer is the number of announced transaction groups we request concurrently from a single peer, so that
+// one peer can't occupy all the request workers.
+const txRequestsPerPeer = 2
+
+// txRequestMaxAnnouncers is the number of peers that announced a transaction group we keep, to request it from the
+// next one if the previous one fails to send it.
+const txRequestMaxAnnouncers = 4
+
+// txRequestTimeout is how long we wait for a peer to send us a transaction group it announced, and to enqueue the groups it requested.
+const txRequestTimeout = 4 * time.Second
+
+// Topic keys of the requests of announced transaction groups, and of their responses
+const (
+	txidKey    = "txid"
+	txgroupKey = "txgroup"
+)
+
+// txGroupGoneError is the error of the responses for the groups that left the pool since they were announced, either
+// committed or evicted. Unlike the other errors, it doesn't mean the peer misbehaved.
+const txGroupGoneError = "transaction group committed or evicted"
+
 var transactionMessagesHandled = metrics.MakeCounter(metrics.TransactionMessagesHandled)
 var transactionMessagesDroppedFromBacklog = metrics.MakeCounter(metrics.TransactionMessagesDroppedFromBacklog)
 var transactionMessagesDroppedFromPool = metrics.MakeCounter(metrics.TransactionMessagesDroppedFromPool)
+var transactionAnnouncementsHandled = metrics.MakeCounter(metrics.MetricName{Name: "algod_transaction_announcements_handled", Description: "Number of transaction group announcements received"})
+var transactionAnnouncementsRequested = metrics.MakeCounter(metrics.MetricName{Name: "algod_transaction_announcements_requested", Description: "Number of announced transaction groups requested"})
+var transactionAnnouncementsDropped = metrics.MakeCounter(metrics.MetricName{Name: "algod_transaction_announcements_dropped", Description: "Number of announced transaction groups not requested due to a full backlog"})
+var transactionRequestsDropped = metrics.MakeCounter(metrics.MetricName{Name: "algod_transaction_requests_dropped", Description: "Number of transaction group requests of peers not served due to a full backlog"})
 
 // The txBacklogMsg structure used to track a single incoming transaction from the gossip network,
 type txBacklogMsg struct {
@@ -51,6 +87,13 @@ type txBacklogMsg struct {
 	verificationErr   error                    // The verification error generated by the verification function, if any.
 }
 
+// The txRequest structure used to track an announced transaction group we request, along with the peers that
+// announced it and that we haven't requested it from yet.
+type txRequest struct {
+	txid       transactions.Txid
+	announcers []network.UnicastPeer
+}
+
 // TxHandler handles transaction messages
 type TxHandler struct {
 	txPool                *pools.TransactionPool
@@ -64,10 +107,19 @@ type TxHandler struct {
 	net                   network.GossipNode
 	ctx                   context.Context
 	ctxCancel             context.CancelFunc
+
+	// when announceTxns is set, the transaction groups are relayed by announcing their txid, and the
+	// announced groups we don't have are requested from the peers that announced them.
+	announceTxns bool
+	txRequests   chan *txRequest
+	servedReqs   chan network.IncomingMessage
+	requestedMu  deadlock.Mutex
+	requested    map[transactions.Txid]*txRequest
+	outstanding  map[network.UnicastPeer]int
 }
 
 // MakeTxHandler makes a new handler for transaction messages
-func MakeTxHandler(txPool *pools.TransactionPool, ledger *Ledger, net network.GossipNode, genesisID string, genesisHash crypto.Digest, executionPool execpool.BacklogPool) *TxHandler {
+func MakeTxHandler(txPool *pools.TransactionPool, ledger *Ledger, net network.GossipNode, genesisID string, genesisHash crypto.Digest, executionPool execpool.BacklogPool, cfg config.Local) *TxHandler {
 
 	if txPool == nil {
 		logging.Base().Fatal("MakeTxHandler: txPool is nil on initialization")
@@ -88,6 +140,13 @@ func MakeTxHandler(txPool *pools.TransactionPool, ledger *Ledger, net network.Go
 		backlogQueue:          make(chan *txBacklogMsg, txBacklogSize),
 		postVerificationQueue: make(chan *txBacklogMsg, txBacklogSize),
 		net:                   net,
+		announceTxns:          cfg.EnableTxnAnnouncements,
+	}
+	if handler.announceTxns {
+		handler.txRequests = make(chan *txRequest, txRequestBacklogSize)
+		handler.servedReqs = make(chan network.IncomingMessage, txRequestBacklogSize)
+		handler.requested = make(map[transactions.Txid]*txRequest)
+		handler.outstanding = make(map[network.UnicastPeer]int)
 	}
 
 	handler.ctx, handler.ctxCancel = context.WithCancel(context.Background())
@@ -101,6 +160,18 @@ func (handler *TxHandler) Start() {
 	})
 	handler.backlogWg.Add(1)
 	go handler.backlogWorker()
+
+	if handler.announceTxns {
+		handler.net.RegisterHandlers([]network.TaggedMessageHandler{
+			network.TaggedMessageHandler{Tag: protocol.TxnAnnouncementTag, MessageHandler: network.HandlerFunc(handler.processIncomingAnnouncement)},
+			network.TaggedMessageHandler{Tag: protocol.TxnRequestTag, MessageHandler: network.HandlerFunc(handler.processIncomingRequest)},
+		})
+		handler.backlogWg.Add(txRequestWorkers + 1)
+		for i := 0; i < txRequestWorkers; i++ {
+			go handler.txRequestWorker()
+		}
+		go handler.txServeWorker()
+	}
 }
 
 // Stop suspends the processing of incoming messages at the transaction handler
@@ -194,6 +265,12 @@ func (handler *TxHandler) postprocessCheckedTxn(wi *txBacklogMsg) {
 		return
 	}
 
+	// the peers that negotiated announcements get the txid of the group, and the other ones the group itself.
+	if handler.announceTxns {
+		txid := verifiedTxGroup[0].ID()
+		handler.net.Relay(handler.ctx, protocol.TxnAnnouncementTag, txid[:], false, wi.rawmsg.Sender)
+	}
+
 	// We reencode here instead of using rawmsg.Data to avoid broadcasting non-canonical encodings
 	handler.net.Relay(handler.ctx, protocol.TxnTag, reencode(verifiedTxGroup), false, wi.rawmsg.Sender)
 }
@@ -217,8 +294,9 @@ func (handler *TxHandler) asyncVerifySignature(arg interface{}) interface{} {
 	return nil
 }
 
-func (handler *TxHandler) processIncomingTxn(rawmsg network.IncomingMessage) network.OutgoingMessage {
-	dec := protocol.NewDecoderBytes(rawmsg.Data)
+// decodeTxGroup decodes the transaction group of a TxnTag message.
+func decodeTxGroup(data []byte) ([]transactions.SignedTxn, error) {
+	dec := protocol.NewDecoderBytes(data)
 	ntx := 0
 	unverifiedTxGroup := make([]transactions.SignedTxn, 1)
 	for {
@@ -233,17 +311,28 @@ func (handler *TxHandler) processIncomingTxn(rawmsg network.IncomingMessage) net
 			break
 		}
 		if err != nil {
-			logging.Base().Warnf("Received a non-decodable txn: %v", err)
-			return network.OutgoingMessage{Action: network.Disconnect}
+			return nil, fmt.Errorf("non-decodable txn: %v", err)
 		}
 		ntx++
 	}
 	if ntx == 0 {
-		logging.Base().Warnf("Received empty tx group")
+		return nil, fmt.Errorf("empty tx group")
+	}
+	return unverifiedTxGroup[:ntx], nil
+}
+
+func (handler *TxHandler) processIncomingTxn(rawmsg network.IncomingMessage) network.OutgoingMessage {
+	unverifiedTxGroup, err := decodeTxGroup(rawmsg.Data)
+	if err != nil {
+		logging.Base().Warnf("Received a malformed tx group message: %v", err)
 		return network.OutgoingMessage{Action: network.Disconnect}
 	}
-	unverifiedTxGroup = unverifiedTxGroup[:ntx]
+	handler.enqueueTxGroup(rawmsg, unverifiedTxGroup)
+	return network.OutgoingMessage{Action: network.Ignore}
+}
 
+// enqueueTxGroup queues a decoded transaction group for its verification, unless the backlog is full.
+func (handler *TxHandler) enqueueTxGroup(rawmsg network.IncomingMessage, unverifiedTxGroup []transactions.SignedTxn) {
 	select {
 	case handler.backlogQueue <- &txBacklogMsg{
 		rawmsg:            &rawmsg,
@@ -254,10 +343,208 @@ func (handler *TxHandler) processIncomingTxn(rawmsg network.IncomingMessage) net
 		// want to increase the queue size.
 		transactionMessagesDroppedFromBacklog.Inc(nil)
 	}
+}
+
+// processIncomingAnnouncement handles the announcement of a transaction group by the txid of its first transaction,
+// and enqueues its request unless we already have it. If the group is already being requested, the peer is kept
+// as an alternate announcer to request it from.
+func (handler *TxHandler) processIncomingAnnouncement(rawmsg network.IncomingMessage) network.OutgoingMessage {
+	var txid transactions.Txid
+	if len(rawmsg.Data) != len(txid) {
+		logging.Base().Warnf("Received a transaction announcement of %d bytes", len(rawmsg.Data))
+		return network.OutgoingMessage{Action: network.Disconnect}
+	}
+	copy(txid[:], rawmsg.Data)
+	transactionAnnouncementsHandled.Inc(nil)
+
+	peer, ok := rawmsg.Sender.(network.UnicastPeer)
+	if !ok {
+		return network.OutgoingMessage{Action: network.Ignore}
+	}
+	// the transaction is either pending, or was seen recently.
+	if _, _, found := handler.txPool.Lookup(txid); found {
+		return network.OutgoingMessage{Action: network.Ignore}
+	}
 
+	handler.requestedMu.Lock()
+	defer handler.requestedMu.Unlock()
+	if req, has := handler.requested[txid]; has {
+		if len(req.announcers) < txRequestMaxAnnouncers {
+			for _, announcer := range req.announcers {
+				if announcer == peer {
+					return network.OutgoingMessage{Action: network.Ignore}
+				}
+			}
+			req.announcers = append(req.announcers, peer)
+		}
+		return network.OutgoingMessage{Action: network.Ignore}
+	}
+	req := &txRequest{txid: txid, announcers: []network.UnicastPeer{peer}}
+	select {
+	case handler.txRequests <- req:
+		handler.requested[txid] = req
+	default:
+		transactionAnnouncementsDropped.Inc(nil)
+	}
 	return network.OutgoingMessage{Action: network.Ignore}
 }
 
+// txRequestWorker requests the announced transaction groups from the peers that announced them, and handles the
+// groups it gets as if they were gossiped in full.
+func (handler *TxHandler) txRequestWorker() {
+	defer handler.backlogWg.Done()
+	for {
+		select {
+		case req := <-handler.txRequests:
+			handler.fetchTxGroup(req)
+		case <-handler.ctx.Done():
+			return
+		}
+	}
+}
+
+// fetchTxGroup requests the announced transaction group from its announcers one after the other, until one of them
+// sends it.
+func (handler *TxHandler) fetchTxGroup(req *txRequest) {
+	for {
+		peer := handler.nextAnnouncer(req)
+		if peer == nil {
+			return
+		}
+		received := handler.requestTxGroup(req.txid, peer)
+
+		handler.requestedMu.Lock()
+		handler.outstanding[peer]--
+		if handler.outstanding[peer] == 0 {
+			delete(handler.outstanding, peer)
+		}
+		if received {
+			delete(handler.requested, req.txid)
+		}
+		handler.requestedMu.Unlock()
+		if received {
+			return
+		}
+	}
+}
+
+// nextAnnouncer returns the next peer to request the announced transaction group from, skipping the ones that have
+// txRequestsPerPeer requests outstanding already, or nil once there are none left; the request is then forgotten.
+func (handler *TxHandler) nextAnnouncer(req *txRequest) network.UnicastPeer {
+	handler.requestedMu.Lock()
+	defer handler.requestedMu.Unlock()
+	for len(req.announcers) > 0 {
+		peer := req.announcers[0]
+		req.announcers = req.announcers[1:]
+		if handler.outstanding[peer] >= txRequestsPerPeer {
+			transactionAnnouncementsDropped.Inc(nil)
+			continue
+		}
+		handler.outstanding[peer]++
+		return peer
+	}
+	delete(handler.requested, req.txid)
+	return nil
+}
+
+// requestTxGroup requests the announced transaction group from the given peer, and returns whether the peer sent it.
+// The peers that answer with a malformed response, or with another group, are penalized; the ones whose group left
+// their pool since they announced it aren't.
+func (handler *TxHandler) requestTxGroup(txid transactions.Txid, peer network.UnicastPeer) bool {
+	ctx, cancel := context.WithTimeout(handler.ctx, txRequestTimeout)
+	defer cancel()
+	transactionAnnouncementsRequested.Inc(nil)
+	resp, err := peer.Request(ctx, protocol.TxnRequestTag, network.Topics{network.MakeTopic(txidKey, txid[:])})
+	if err != nil {
+		logging.Base().Debugf("could not request announced tx group %v from %s: %v", txid, peer.GetAddress(), err)
+		return false
+	}
+	data, found := resp.Topics.GetValue(txgroupKey)
+	if !found {
+		errMsg, hasErr := resp.Topics.GetValue(network.ErrorKey)
+		logging.Base().Debugf("peer %s did not send announced tx group %v: %s", peer.GetAddress(), txid, string(errMsg))
+		if !hasErr {
+			handler.net.Penalize(peer)
+		}
+		return false
+	}
+
+	txgroup, err := decodeTxGroup(data)
+	if err != nil {
+		logging.Base().Warnf("peer %s sent a malformed announced tx group %v: %v", peer.GetAddress(), txid, err)
+		handler.net.Disconnect(peer)
+		return false
+	}
+	announced := false
+	for _, stxn := range txgroup {
+		if stxn.ID() == txid {
+			announced = true
+			break
+		}
+	}
+	if !announced {
+		logging.Base().Warnf("peer %s sent another tx group than the announced tx group %v", peer.GetAddress(), txid)
+		handler.net.Penalize(peer)
+		return false
+	}
+	handler.enqueueTxGroup(network.IncomingMessage{Sender: peer, Tag: protocol.TxnTag, Data: data}, txgroup)
+	return true
+}
+
+// processIncomingRequest handles the request of a peer for a transaction group we announced.
+func (handler *TxHandler) processIncomingRequest(rawmsg network.IncomingMessage) network.OutgoingMessage {
+	// don't block the network handlers while the response is sent.
+	select {
+	case handler.servedReqs <- rawmsg:
+	default:
+		transactionRequestsDropped.Inc(nil)
+	}
+	return network.OutgoingMessage{Action: network.Ignore}
+}
+
+// txServeWorker responds to the requests of our peers for the transaction groups we announced.
+func (handler *TxHandler) txServeWorker() {
+	defer handler.backlogWg.Done()
+	for {
+		select {
+		case reqMsg := <-handler.servedReqs:
+			handler.serveTxGroup(reqMsg)
+		case <-handler.ctx.Done():
+			return
+		}
+	}
+}
+
+func (handler *TxHandler) serveTxGroup(reqMsg network.IncomingMessage) {
+	target, ok := reqMsg.Sender.(network.UnicastPeer)
+	if !ok {
+		return
+	}
+	var respTopics network.Topics
+	topics, err := network.UnmarshallTopics(reqMsg.Data)
+	if err != nil {
+		respTopics = network.Topics{network.MakeTopic(network.ErrorKey, []byte(err.Error()))}
+	} else if txidBytes, found := topics.GetValue(txidKey); !found || len(txidBytes) != len(transactions.Txid{}) {
+		respTopics = network.Topics{network.MakeTopic(network.ErrorKey, []byte("missing or invalid txid"))}
+	} else {
+		var txid transactions.Txid
+		copy(txid[:], txidBytes)
+		txgroup, found := handler.txPool.LookupGroup(txid)
+		if found {
+			respTopics = network.Topics{network.MakeTopic(txgroupKey, reencode(txgroup))}
+		} else {
+			respTopics = network.Topics{network.MakeTopic(network.ErrorKey, []byte(txGroupGoneError))}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(handler.ctx, txRequestTimeout)
+	defer cancel()
+	err = target.Respond(ctx, reqMsg, respTopics)
+	if err != nil {
+		logging.Base().Debugf("could not respond to tx group request of %s: %v", target.GetAddress(), err)
+	}
+}
+
 // checkAlreadyCommitted test to see if the given transaction ( in the txBacklogMsg ) was already commited, and
 // whether it would qualify as a candidate for the transaction pool.
 //
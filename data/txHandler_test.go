@@ -17,6 +17,7 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -24,13 +25,18 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/algorand/go-algorand/agreement"
 	"github.com/algorand/go-algorand/components/mocks"
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/pools"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/verify"
+	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/network"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/execpool"
 )
@@ -98,7 +104,7 @@ func BenchmarkTxHandlerProcessDecoded(b *testing.B) {
 		}
 	}
 	backlogPool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
-	txHandler := MakeTxHandler(tp, l, &mocks.MockNetwork{}, "", crypto.Digest{}, backlogPool)
+	txHandler := MakeTxHandler(tp, l, &mocks.MockNetwork{}, "", crypto.Digest{}, backlogPool, cfg)
 	b.StartTimer()
 	for _, signedTxn := range signedTransactions {
 		txHandler.processDecoded([]transactions.SignedTxn{signedTxn})
@@ -120,3 +126,163 @@ func BenchmarkTimeAfter(b *testing.B) {
 		}
 	}
 }
+
+// announcingPeer is a peer whose requests are served by a TxHandler, and that records the messages it gets. If
+// txgroup is set, the peer answers all the requests with it instead.
+type announcingPeer struct {
+	server    *TxHandler
+	responses chan network.Topics
+	txgroup   []byte
+}
+
+func (p *announcingPeer) GetAddress() string {
+	return "announcing-peer"
+}
+
+func (p *announcingPeer) Unicast(ctx context.Context, data []byte, tag protocol.Tag) error {
+	return nil
+}
+
+func (p *announcingPeer) Version() string {
+	return "2.1"
+}
+
+func (p *announcingPeer) Request(ctx context.Context, tag network.Tag, topics network.Topics) (resp *network.Response, e error) {
+	if p.txgroup != nil {
+		return &network.Response{Topics: network.Topics{network.MakeTopic(txgroupKey, p.txgroup)}}, nil
+	}
+	p.server.serveTxGroup(network.IncomingMessage{Sender: p, Tag: tag, Data: topics.MarshallTopics()})
+	return &network.Response{Topics: <-p.responses}, nil
+}
+
+func (p *announcingPeer) Respond(ctx context.Context, reqMsg network.IncomingMessage, topics network.Topics) (e error) {
+	p.responses <- topics
+	return nil
+}
+
+// penalizingNetwork is a network that records the peers it's asked to penalize.
+type penalizingNetwork struct {
+	mocks.MockNetwork
+	penalized []network.Peer
+}
+
+func (n *penalizingNetwork) Penalize(badpeer network.Peer) {
+	n.penalized = append(n.penalized, badpeer)
+}
+
+func TestTxHandlerAnnouncements(t *testing.T) {
+	secrets := []*crypto.SignatureSecrets{keypair(), keypair()}
+	genesis := make(map[basics.Address]basics.AccountData)
+	for _, secret := range secrets {
+		genesis[basics.Address(secret.SignatureVerifier)] = basics.MakeAccountData(basics.Online, basics.MicroAlgos{Raw: 10000000000000})
+	}
+	genesis[poolAddr] = basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: proto.MinBalance})
+	cfg := config.GetDefaultLocal()
+	l, err := LoadLedger(logging.TestingLog(t), t.Name(), true, protocol.ConsensusCurrentVersion, MakeGenesisBalances(genesis, sinkAddr, poolAddr), genesisID, genesisHash, nil, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+
+	var txs []transactions.SignedTxn
+	for i, secret := range secrets {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      basics.Address(secret.SignatureVerifier),
+				Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+				LastValid:   basics.Round(proto.MaxTxnLife),
+				GenesisHash: genesisHash,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: basics.Address(secrets[1-i].SignatureVerifier),
+				Amount:   basics.MicroAlgos{Raw: 1000},
+			},
+		}
+		txs = append(txs, tx.Sign(secret))
+	}
+
+	cfg.EnableTxnAnnouncements = true
+	cfg.EnableProcessBlockStats = false
+	backlogPool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
+	defer backlogPool.Shutdown()
+	server := MakeTxHandler(pools.MakeTransactionPool(l.Ledger, cfg), l, &mocks.MockNetwork{}, genesisID, genesisHash, backlogPool, cfg)
+	emptyServer := MakeTxHandler(pools.MakeTransactionPool(l.Ledger, cfg), l, &mocks.MockNetwork{}, genesisID, genesisHash, backlogPool, cfg)
+	clientNet := &penalizingNetwork{}
+	client := MakeTxHandler(pools.MakeTransactionPool(l.Ledger, cfg), l, clientNet, genesisID, genesisHash, backlogPool, cfg)
+	peer := &announcingPeer{server: server, responses: make(chan network.Topics, 1)}
+	emptyPeer := &announcingPeer{server: emptyServer, responses: make(chan network.Topics, 1)}
+
+	// the server has the first transaction only.
+	require.NoError(t, server.txPool.RememberOne(txs[0], verify.Params{}))
+	txid := txs[0].ID()
+	announce := func(sender network.UnicastPeer, txid transactions.Txid) network.OutgoingMessage {
+		return client.processIncomingAnnouncement(network.IncomingMessage{Sender: sender, Tag: protocol.TxnAnnouncementTag, Data: txid[:]})
+	}
+
+	// an announced transaction is only requested once, and the other peers that announced it are kept.
+	require.Equal(t, network.Ignore, announce(emptyPeer, txid).Action)
+	require.Equal(t, network.Ignore, announce(peer, txid).Action)
+	require.Equal(t, network.Ignore, announce(peer, txid).Action)
+	require.Equal(t, 1, len(client.txRequests))
+	req := <-client.txRequests
+	require.Equal(t, txid, req.txid)
+	require.Equal(t, []network.UnicastPeer{emptyPeer, peer}, req.announcers)
+
+	// the peer that no longer has the transaction doesn't send it, so the transaction is requested from the next one.
+	client.fetchTxGroup(req)
+	require.Empty(t, clientNet.penalized)
+	require.Equal(t, 1, len(client.backlogQueue))
+	wi := <-client.backlogQueue
+	require.Equal(t, []transactions.SignedTxn{txs[0]}, wi.unverifiedTxGroup)
+	require.Equal(t, peer, wi.rawmsg.Sender)
+	require.Empty(t, client.requested)
+	require.Empty(t, client.outstanding)
+
+	// the peers that have too many requests outstanding are skipped.
+	require.Equal(t, network.Ignore, announce(peer, txs[1].ID()).Action)
+	req = <-client.txRequests
+	client.outstanding[peer] = txRequestsPerPeer
+	client.fetchTxGroup(req)
+	require.Equal(t, 0, len(client.backlogQueue))
+	require.Empty(t, client.requested)
+	delete(client.outstanding, peer)
+
+	// the server doesn't send what it doesn't have.
+	require.False(t, client.requestTxGroup(txs[1].ID(), peer))
+	require.Equal(t, 0, len(client.backlogQueue))
+
+	// the peers that send another group than the one they announced are penalized, and the group is requested from
+	// the next announcer.
+	lyingPeer := &announcingPeer{txgroup: protocol.Encode(&txs[1])}
+	require.Equal(t, network.Ignore, announce(lyingPeer, txid).Action)
+	require.Equal(t, network.Ignore, announce(peer, txid).Action)
+	req = <-client.txRequests
+	client.fetchTxGroup(req)
+	require.Equal(t, []network.Peer{lyingPeer}, clientNet.penalized)
+	require.Equal(t, 1, len(client.backlogQueue))
+	wi = <-client.backlogQueue
+	require.Equal(t, []transactions.SignedTxn{txs[0]}, wi.unverifiedTxGroup)
+	require.Equal(t, peer, wi.rawmsg.Sender)
+
+	// the peers whose group was committed since they announced it aren't penalized.
+	prev, err := l.BlockHdr(l.Latest())
+	require.NoError(t, err)
+	eval, err := l.StartEvaluator(bookkeeping.MakeBlock(prev).BlockHeader, 0)
+	require.NoError(t, err)
+	require.NoError(t, eval.Transaction(txs[0], transactions.ApplyData{}))
+	vb, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	require.NoError(t, l.AddValidatedBlock(*vb, agreement.Certificate{}))
+	server.txPool.OnNewBlock(vb.Block(), ledger.StateDelta{})
+	_, found := server.txPool.LookupGroup(txid)
+	require.False(t, found)
+	require.False(t, client.requestTxGroup(txid, peer))
+	require.Equal(t, []network.Peer{lyingPeer}, clientNet.penalized)
+
+	// the transactions the client has aren't requested.
+	outmsg := server.processIncomingAnnouncement(network.IncomingMessage{Sender: peer, Tag: protocol.TxnAnnouncementTag, Data: txid[:]})
+	require.Equal(t, network.Ignore, outmsg.Action)
+	require.Equal(t, 0, len(server.txRequests))
+
+	outmsg = client.processIncomingAnnouncement(network.IncomingMessage{Sender: peer, Tag: protocol.TxnAnnouncementTag, Data: txid[:8]})
+	require.Equal(t, network.Disconnect, outmsg.Action)
+}
@@ -103,11 +103,19 @@ func GenerateGenesisFiles(genesisData GenesisData, consensus config.ConsensusPro
 		return fmt.Errorf("protocol %s not supported", proto)
 	}
 
-	return generateGenesisFiles(outDir, proto, consensusParams, genesisData.NetworkName, genesisData.VersionModifier, allocation, genesisData.FirstPartKeyRound, genesisData.LastPartKeyRound, genesisData.PartKeyDilution, genesisData.FeeSink, genesisData.RewardsPool, genesisData.Comment, verbose)
+	var imported []bookkeeping.GenesisAllocation
+	if genesisData.Snapshot != "" {
+		imported, err = loadSnapshotAllocation(genesisData.Snapshot, genesisData.SnapshotFormat, consensusParams, genesisData.FeeSink, genesisData.RewardsPool)
+		if err != nil {
+			return fmt.Errorf("couldn't load the snapshot '%s': %v", genesisData.Snapshot, err)
+		}
+	}
+
+	return generateGenesisFiles(outDir, proto, consensusParams, genesisData.NetworkName, genesisData.VersionModifier, allocation, imported, genesisData.FirstPartKeyRound, genesisData.LastPartKeyRound, genesisData.PartKeyDilution, genesisData.FeeSink, genesisData.RewardsPool, genesisData.Comment, verbose)
 }
 
 func generateGenesisFiles(outDir string, protoVersion protocol.ConsensusVersion, protoParams config.ConsensusParams, netName string, schemaVersionModifier string,
-	allocation []genesisAllocation, firstWalletValid uint64, lastWalletValid uint64, partKeyDilution uint64, feeSink, rewardsPool basics.Address, comment string, verbose bool) (err error) {
+	allocation []genesisAllocation, imported []bookkeeping.GenesisAllocation, firstWalletValid uint64, lastWalletValid uint64, partKeyDilution uint64, feeSink, rewardsPool basics.Address, comment string, verbose bool) (err error) {
 
 	genesisAddrs := make(map[string]basics.Address)
 	records := make(map[string]basics.AccountData)
@@ -316,6 +324,7 @@ func generateGenesisFiles(outDir string, protoVersion protocol.ConsensusVersion,
 			State:   walletData,
 		})
 	}
+	g.Allocation = append(g.Allocation, imported...)
 
 	jsonData := protocol.EncodeJSON(g)
 	err = ioutil.WriteFile(filepath.Join(outDir, config.GenesisJSONFile), append(jsonData, '\n'), 0666)
@@ -0,0 +1,90 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/snapshot"
+)
+
+// snapshotComment is the comment of the genesis allocations of the accounts
+// imported from a snapshot.
+const snapshotComment = "snapshot"
+
+// loadSnapshotAllocation returns the genesis allocation of the accounts of a
+// ledger snapshot, ordered by address.
+//
+// The participation keys of the network the snapshot comes from are of no
+// use on the new one, so its online accounts are taken offline. The rewards
+// pending at the round of the snapshot are added to the balances, since the
+// rewards of the new network start over from zero. The accounts at the
+// addresses of the fee sink and the rewards pool of the new network are left
+// out. The assets and applications keep their IDs; the genesis block counts
+// them, so that the ones created later don't collide with them.
+func loadSnapshotAllocation(path string, format string, proto config.ConsensusParams, feeSink, rewardsPool basics.Address) ([]bookkeeping.GenesisAllocation, error) {
+	if format == "" {
+		format = string(snapshot.FormatJSONL)
+	}
+	f, err := snapshot.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	hdr, balances, err := snapshot.Load(path, f)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]basics.Address, 0, len(balances))
+	for addr := range balances {
+		if addr != feeSink && addr != rewardsPool {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	allocation := make([]bookkeeping.GenesisAllocation, len(addrs))
+	for i, addr := range addrs {
+		data := balances[addr].WithUpdatedRewards(proto, hdr.RewardsLevel)
+		data.RewardsBase = 0
+		data.RewardedMicroAlgos = basics.MicroAlgos{}
+		if data.Status == basics.Online {
+			data.Status = basics.Offline
+		}
+		data.VoteID = crypto.OneTimeSignatureVerifier{}
+		data.SelectionID = crypto.VRFVerifier{}
+		data.VoteFirstValid = 0
+		data.VoteLastValid = 0
+		data.VoteKeyDilution = 0
+
+		allocation[i] = bookkeeping.GenesisAllocation{
+			Address: addr.String(),
+			Comment: snapshotComment,
+			State:   data,
+		}
+	}
+	if len(allocation) == 0 {
+		return nil, fmt.Errorf("snapshot of round %d has no accounts to import", hdr.Round)
+	}
+	return allocation, nil
+}
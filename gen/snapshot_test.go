@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger/snapshot"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestLoadSnapshotAllocation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	var online, offline basics.Address
+	crypto.RandBytes(online[:])
+	crypto.RandBytes(offline[:])
+
+	onlineData := basics.MakeAccountData(basics.Online, basics.MicroAlgos{Raw: 10 * proto.RewardUnit})
+	onlineData.RewardsBase = 2
+	onlineData.RewardedMicroAlgos = basics.MicroAlgos{Raw: 5}
+	crypto.RandBytes(onlineData.VoteID[:])
+	crypto.RandBytes(onlineData.SelectionID[:])
+	onlineData.VoteLastValid = 1000
+	onlineData.VoteKeyDilution = 100
+
+	offlineData := basics.MakeAccountData(basics.Offline, basics.MicroAlgos{Raw: proto.RewardUnit})
+	offlineData.RewardsBase = 5
+	offlineData.AssetParams = map[basics.AssetIndex]basics.AssetParams{7: {Total: 10}}
+	offlineData.Assets = map[basics.AssetIndex]basics.AssetHolding{7: {Amount: 10}}
+
+	path := filepath.Join(dir, "snapshot.jsonl")
+	w, err := snapshot.Create(path, snapshot.FormatJSONL, snapshot.Header{Round: 100, RewardsLevel: 5})
+	require.NoError(t, err)
+	require.NoError(t, w.Write(defaultSinkAddr, basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: 1})))
+	require.NoError(t, w.Write(online, onlineData))
+	require.NoError(t, w.Write(offline, offlineData))
+	require.NoError(t, w.Close())
+
+	allocation, err := loadSnapshotAllocation(path, "", proto, defaultSinkAddr, defaultPoolAddr)
+	require.NoError(t, err)
+	require.Len(t, allocation, 2)
+
+	states := make(map[string]basics.AccountData)
+	for _, alloc := range allocation {
+		require.Equal(t, snapshotComment, alloc.Comment)
+		states[alloc.Address] = alloc.State
+	}
+
+	// the pending rewards are added to the balances, and the online account
+	// is taken offline.
+	imported := states[online.String()]
+	require.Equal(t, basics.Offline, imported.Status)
+	require.Equal(t, 10*proto.RewardUnit+10*3, imported.MicroAlgos.Raw)
+	require.Zero(t, imported.RewardsBase)
+	require.Zero(t, imported.RewardedMicroAlgos.Raw)
+	require.True(t, imported.VoteID.MsgIsZero())
+	require.True(t, imported.SelectionID.MsgIsZero())
+	require.Zero(t, imported.VoteLastValid)
+
+	imported = states[offline.String()]
+	require.Equal(t, proto.RewardUnit, imported.MicroAlgos.Raw)
+	require.Equal(t, offlineData.AssetParams, imported.AssetParams)
+	require.Equal(t, offlineData.Assets, imported.Assets)
+
+	_, err = loadSnapshotAllocation(path, "parquet", proto, defaultSinkAddr, defaultPoolAddr)
+	require.Error(t, err)
+}
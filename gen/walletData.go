@@ -50,6 +50,12 @@ type GenesisData struct {
 	FeeSink           basics.Address
 	RewardsPool       basics.Address
 	Comment           string
+
+	// Snapshot is the path of a ledger snapshot, as written by goal ledger
+	// export, whose accounts are added to the genesis along with the
+	// wallets; SnapshotFormat is its format, jsonl by default.
+	Snapshot       string
+	SnapshotFormat string
 }
 
 // LoadGenesisData loads a GenesisData structure from a json file
@@ -17,6 +17,7 @@
 package ledger
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
@@ -32,9 +33,9 @@ import (
 // accountsDbQueries is used to cache a prepared SQL statement to look up
 // the state of a single account.
 type accountsDbQueries struct {
-	listAssetsStmt               *sql.Stmt
+	listCreatablesStmt           *sql.Stmt
 	lookupStmt                   *sql.Stmt
-	lookupAssetCreatorStmt       *sql.Stmt
+	lookupCreatorStmt            *sql.Stmt
 	deleteStoredCatchpoint       *sql.Stmt
 	insertStoredCatchpoint       *sql.Stmt
 	selectOldestsCatchpointFiles *sql.Stmt
@@ -63,7 +64,8 @@ var accountsSchema = []string{
 		data blob)`,
 	`CREATE TABLE IF NOT EXISTS assetcreators (
 		asset integer primary key,
-		creator blob)`,
+		creator blob,
+		ctype integer)`,
 	`CREATE TABLE IF NOT EXISTS storedcatchpoints (
 		round integer primary key,
 		filename text NOT NULL,
@@ -83,17 +85,41 @@ var accountsResetExprs = []string{
 	`DROP TABLE IF EXISTS acctrounds`,
 	`DROP TABLE IF EXISTS accounttotals`,
 	`DROP TABLE IF EXISTS accountbase`,
+	`DROP TABLE IF EXISTS resources`,
 	`DROP TABLE IF EXISTS assetcreators`,
 	`DROP TABLE IF EXISTS storedcatchpoints`,
 	`DROP TABLE IF EXISTS catchpointstate`,
 	`DROP TABLE IF EXISTS accounthashes`,
+	`DROP TABLE IF EXISTS accounthistory`,
+	`DROP TABLE IF EXISTS totalshistory`,
 }
 
+// resourcesSchema is the resources table, holding the asset and application
+// holdings and params of the accounts in accountbase. Each row describes a
+// single creatable of an account; its data is an AccountData in which only the
+// entries of that creatable are set.
+const resourcesSchema = `CREATE TABLE IF NOT EXISTS %s (
+		address blob,
+		aidx integer,
+		rtype integer,
+		data blob,
+		PRIMARY KEY (address, aidx, rtype))`
+
+// resourcesMigrationChunkSize is the number of accountbase records being
+// converted at a time when moving the account resources into their own table.
+const resourcesMigrationChunkSize = 1000
+
 type accountDelta struct {
 	old basics.AccountData
 	new basics.AccountData
 }
 
+// resourceKey identifies a single creatable of an account in the resources table.
+type resourceKey struct {
+	cidx  basics.CreatableIndex
+	ctype basics.CreatableType
+}
+
 // catchpointState is used to store catchpoint related varaibles into the catchpointstate table.
 type catchpointState string
 
@@ -113,16 +139,35 @@ const (
 	// catchpointStateCatchupBalancesRound is the balance round that is associated with the current running catchpoint catchup. Typically it would be
 	// equal to catchpointStateCatchupBlockRound - 320.
 	catchpointStateCatchupBalancesRound = catchpointState("catchpointCatchupBalancesRound")
+	// catchpointStateCatchupVersion is the version of the catchpoint file staged by the current running catchpoint catchup.
+	// It defines how the staging balances trie is built.
+	catchpointStateCatchupVersion = catchpointState("catchpointCatchupVersion")
 )
 
-func writeCatchpointStagingAssets(ctx context.Context, tx *sql.Tx, addr basics.Address, assetIdx basics.AssetIndex) error {
-	_, err := tx.ExecContext(ctx, "INSERT INTO catchpointassetcreators(asset, creator) VALUES(?, ?)", assetIdx, addr[:])
+func writeCatchpointStagingCreatable(ctx context.Context, tx *sql.Tx, addr basics.Address, cidx basics.CreatableIndex, ctype basics.CreatableType) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO catchpointassetcreators(asset, creator, ctype) VALUES(?, ?, ?)", cidx, addr[:], ctype)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func writeCatchpointStagingResources(ctx context.Context, tx *sql.Tx, resources []encodedResourceRecord) error {
+	insertStmt, err := tx.PrepareContext(ctx, "INSERT INTO catchpointresources(address, aidx, rtype, data) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertStmt.Close()
+
+	for _, resource := range resources {
+		_, err = insertStmt.ExecContext(ctx, resource.Address[:], resource.CreatableIndex, resource.CreatableType, resource.Data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func writeCatchpointStagingBalances(ctx context.Context, tx *sql.Tx, bals []encodedBalanceRecord) error {
 	insertStmt, err := tx.PrepareContext(ctx, "INSERT INTO catchpointbalances(address, data) VALUES(?, ?)")
 	if err != nil {
@@ -150,32 +195,49 @@ func resetCatchpointStagingBalances(ctx context.Context, tx *sql.Tx, newCatchup
 	s := "DROP TABLE IF EXISTS catchpointbalances;"
 	s += "DROP TABLE IF EXISTS catchpointassetcreators;"
 	s += "DROP TABLE IF EXISTS catchpointaccounthashes;"
+	s += "DROP TABLE IF EXISTS catchpointresources;"
 	s += "DELETE FROM accounttotals where id='catchpointStaging';"
 	if newCatchup {
-		s += "CREATE TABLE IF NOT EXISTS catchpointassetcreators(asset integer primary key, creator blob);"
+		s += "CREATE TABLE IF NOT EXISTS catchpointassetcreators(asset integer primary key, creator blob, ctype integer);"
 		s += "CREATE TABLE IF NOT EXISTS catchpointbalances(address blob primary key, data blob);"
+		s += fmt.Sprintf(resourcesSchema, "catchpointresources") + ";"
 		s += "CREATE TABLE IF NOT EXISTS catchpointaccounthashes(id integer primary key, data blob);"
 	}
 	_, err = tx.Exec(s)
 	return err
 }
 
+// resetCatchpointStagingHashes deletes the staging balances trie.
+func resetCatchpointStagingHashes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM catchpointaccounthashes")
+	return err
+}
+
 // applyCatchpointStagingBalances switches the staged catchpoint catchup tables onto the actual
 // tables and update the correct balance round. This is the final step in switching onto the new catchpoint round.
 func applyCatchpointStagingBalances(ctx context.Context, tx *sql.Tx, balancesRound basics.Round) (err error) {
 	s := "ALTER TABLE accountbase RENAME TO accountbase_old;"
+	s += "ALTER TABLE resources RENAME TO resources_old;"
 	s += "ALTER TABLE assetcreators RENAME TO assetcreators_old;"
 	s += "ALTER TABLE accounthashes RENAME TO accounthashes_old;"
 	s += "ALTER TABLE catchpointbalances RENAME TO accountbase;"
+	s += "ALTER TABLE catchpointresources RENAME TO resources;"
 	s += "ALTER TABLE catchpointassetcreators RENAME TO assetcreators;"
 	s += "ALTER TABLE catchpointaccounthashes RENAME TO accounthashes;"
 	s += "DROP TABLE IF EXISTS accountbase_old;"
+	s += "DROP TABLE IF EXISTS resources_old;"
 	s += "DROP TABLE IF EXISTS assetcreators_old;"
 	s += "DROP TABLE IF EXISTS accounthashes_old;"
 	_, err = tx.Exec(s)
 	if err != nil {
 		return err
 	}
+	// the account history doesn't lead up to the catchpoint round; it gets
+	// started over from it once the ledger is reloaded.
+	err = accountsResetHistory(tx)
+	if err != nil {
+		return err
+	}
 	_, err = tx.Exec("INSERT OR REPLACE INTO acctrounds(id, rnd) VALUES('acctbase', ?)", balancesRound)
 	if err != nil {
 		return err
@@ -192,6 +254,29 @@ func getCatchpoint(tx *sql.Tx, round basics.Round) (fileName string, catchpoint
 	return
 }
 
+// storedCatchpointLabels returns the labels of the catchpoints the tracker
+// database holds files for.
+func storedCatchpointLabels(tx *sql.Tx) (labels map[basics.Round]string, err error) {
+	rows, err := tx.Query("SELECT round, catchpoint FROM storedcatchpoints")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	labels = make(map[basics.Round]string)
+	for rows.Next() {
+		var rnd basics.Round
+		var label string
+		err = rows.Scan(&rnd, &label)
+		if err != nil {
+			return
+		}
+		labels[rnd] = label
+	}
+	err = rows.Err()
+	return
+}
+
 // accountsInit fills the database using tx with initAccounts if the
 // database has not been initialized yet.
 //
@@ -205,18 +290,44 @@ func accountsInit(tx *sql.Tx, initAccounts map[basics.Address]basics.AccountData
 		}
 	}
 
-	_, err := tx.Exec("INSERT INTO acctrounds (id, rnd) VALUES ('acctbase', 0)")
+	err := accountsAddCreatableType(tx)
+	if err != nil {
+		return err
+	}
+
+	err = accountsCreateResourcesTable(tx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO acctrounds (id, rnd) VALUES ('acctbase', 0)")
 	if err == nil {
 		var ot basics.OverflowTracker
 		var totals AccountTotals
 
 		for addr, data := range initAccounts {
+			base, resources := splitAccountData(data)
 			_, err = tx.Exec("INSERT INTO accountbase (address, data) VALUES (?, ?)",
-				addr[:], protocol.Encode(&data))
+				addr[:], protocol.Encode(&base))
 			if err != nil {
 				return err
 			}
 
+			for key, rdata := range resources {
+				_, err = tx.Exec("INSERT INTO resources (address, aidx, rtype, data) VALUES (?, ?, ?, ?)",
+					addr[:], key.cidx, key.ctype, protocol.Encode(&rdata))
+				if err != nil {
+					return err
+				}
+			}
+
+			for cidx, delta := range getChangedCreatables(addr, accountDelta{new: data}) {
+				_, err = tx.Exec("INSERT INTO assetcreators (asset, creator, ctype) VALUES (?, ?, ?)", cidx, addr[:], delta.ctype)
+				if err != nil {
+					return err
+				}
+			}
+
 			totals.addAccount(proto, data, &ot)
 		}
 
@@ -240,6 +351,128 @@ func accountsInit(tx *sql.Tx, initAccounts map[basics.Address]basics.AccountData
 	return nil
 }
 
+// accountsAddCreatableType adds the ctype column to an assetcreators table
+// created before applications were tracked. All the existing rows describe
+// assets, which is what the column default reflects.
+func accountsAddCreatableType(tx *sql.Tx) error {
+	rows, err := tx.Query("PRAGMA table_info(assetcreators)")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notnull, pk int
+		var name, ctype string
+		var dflt sql.NullString
+		err = rows.Scan(&cid, &name, &ctype, &notnull, &dflt, &pk)
+		if err != nil {
+			return err
+		}
+		if name == "ctype" {
+			return nil
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE assetcreators ADD COLUMN ctype integer DEFAULT %d", basics.AssetCreatable))
+	return err
+}
+
+// accountsCreateResourcesTable creates the resources table, and moves the
+// asset and application holdings and params of the accountbase records that
+// were written before the table existed into it. The balances trie holds a
+// hash for each accountbase record and one for each (address, creatable)
+// resources record, so when any resources were moved the existing hashes of
+// the whole accounts are dropped, and the trie gets rebuilt by
+// accountsInitialize.
+func accountsCreateResourcesTable(tx *sql.Tx) error {
+	var count int
+	err := tx.QueryRow("SELECT count(*) FROM sqlite_master WHERE type='table' AND name='resources'").Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(resourcesSchema, "resources"))
+	if err != nil {
+		return err
+	}
+
+	type migratedAccount struct {
+		rowid     int64
+		addr      []byte
+		base      basics.AccountData
+		resources map[resourceKey]basics.AccountData
+	}
+
+	moved := false
+	lastRowid := int64(-1)
+	for {
+		rows, err := tx.Query("SELECT rowid, address, data FROM accountbase WHERE rowid > ? ORDER BY rowid LIMIT ?", lastRowid, resourcesMigrationChunkSize)
+		if err != nil {
+			return err
+		}
+
+		var accounts []migratedAccount
+		for rows.Next() {
+			var acct migratedAccount
+			var buf []byte
+			err = rows.Scan(&acct.rowid, &acct.addr, &buf)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			var data basics.AccountData
+			err = protocol.Decode(buf, &data)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			acct.base, acct.resources = splitAccountData(data)
+			accounts = append(accounts, acct)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, acct := range accounts {
+			lastRowid = acct.rowid
+			if len(acct.resources) == 0 {
+				continue
+			}
+			moved = true
+			_, err = tx.Exec("UPDATE accountbase SET data=? WHERE rowid=?", protocol.Encode(&acct.base), acct.rowid)
+			if err != nil {
+				return err
+			}
+			for key, rdata := range acct.resources {
+				_, err = tx.Exec("INSERT INTO resources (address, aidx, rtype, data) VALUES (?, ?, ?, ?)",
+					acct.addr, key.cidx, key.ctype, protocol.Encode(&rdata))
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(accounts) < resourcesMigrationChunkSize {
+			break
+		}
+	}
+
+	if !moved {
+		return nil
+	}
+	return resetAccountHashes(tx)
+}
+
 func resetAccountHashes(tx *sql.Tx) (err error) {
 	_, err = tx.Exec(`DELETE FROM accounthashes`)
 	return
@@ -275,17 +508,17 @@ func accountsDbInit(r db.Queryable, w db.Queryable) (*accountsDbQueries, error)
 	var err error
 	qs := &accountsDbQueries{}
 
-	qs.listAssetsStmt, err = r.Prepare("SELECT asset, creator FROM assetcreators WHERE asset <= ? ORDER BY asset desc LIMIT ?")
+	qs.listCreatablesStmt, err = r.Prepare("SELECT asset, creator FROM assetcreators WHERE asset <= ? AND ctype = ? ORDER BY asset desc LIMIT ?")
 	if err != nil {
 		return nil, err
 	}
 
-	qs.lookupStmt, err = r.Prepare("SELECT data FROM accountbase WHERE address=?")
+	qs.lookupStmt, err = r.Prepare("SELECT accountbase.data, resources.data FROM accountbase LEFT JOIN resources ON resources.address = accountbase.address WHERE accountbase.address=?")
 	if err != nil {
 		return nil, err
 	}
 
-	qs.lookupAssetCreatorStmt, err = r.Prepare("SELECT creator FROM assetcreators WHERE asset=?")
+	qs.lookupCreatorStmt, err = r.Prepare("SELECT creator FROM assetcreators WHERE asset=? AND ctype=?")
 	if err != nil {
 		return nil, err
 	}
@@ -332,10 +565,10 @@ func accountsDbInit(r db.Queryable, w db.Queryable) (*accountsDbQueries, error)
 	return qs, nil
 }
 
-func (qs *accountsDbQueries) listAssets(maxAssetIdx basics.AssetIndex, maxResults uint64) (results []basics.CreatableLocator, err error) {
+func (qs *accountsDbQueries) listCreatables(maxIdx basics.CreatableIndex, maxResults uint64, ctype basics.CreatableType) (results []basics.CreatableLocator, err error) {
 	err = db.Retry(func() error {
-		// Query for assets in range
-		rows, err := qs.listAssetsStmt.Query(maxAssetIdx, maxResults)
+		// Query for creatables in range
+		rows, err := qs.listCreatablesStmt.Query(maxIdx, ctype, maxResults)
 		if err != nil {
 			return err
 		}
@@ -343,7 +576,7 @@ func (qs *accountsDbQueries) listAssets(maxAssetIdx basics.AssetIndex, maxResult
 
 		// For each row, copy into a new CreatableLocator and append to results
 		var buf []byte
-		var al basics.CreatableLocator
+		al := basics.CreatableLocator{Type: ctype}
 		for rows.Next() {
 			err := rows.Scan(&al.Index, &buf)
 			if err != nil {
@@ -357,38 +590,67 @@ func (qs *accountsDbQueries) listAssets(maxAssetIdx basics.AssetIndex, maxResult
 	return
 }
 
-func (qs *accountsDbQueries) lookupAssetCreator(assetIdx basics.AssetIndex) (addr basics.Address, err error) {
+// lookupCreator returns the creator of the given asset or application. ok is
+// false if the creatable does not exist or has been deleted.
+func (qs *accountsDbQueries) lookupCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (addr basics.Address, ok bool, err error) {
 	err = db.Retry(func() error {
 		var buf []byte
-		err := qs.lookupAssetCreatorStmt.QueryRow(assetIdx).Scan(&buf)
+		err := qs.lookupCreatorStmt.QueryRow(cidx, ctype).Scan(&buf)
 
 		if err == sql.ErrNoRows {
-			err = fmt.Errorf("asset %d does not exist or has been deleted", assetIdx)
+			ok = false
+			return nil
 		}
 
 		if err != nil {
 			return err
 		}
+		ok = true
 		copy(addr[:], buf)
 		return nil
 	})
 	return
 }
 
+// lookup returns the account data of addr, assembled from its accountbase
+// record and all of its resources records. An account that doesn't exist has
+// the zero account data.
 func (qs *accountsDbQueries) lookup(addr basics.Address) (data basics.AccountData, err error) {
 	err = db.Retry(func() error {
-		var buf []byte
-		err := qs.lookupStmt.QueryRow(addr[:]).Scan(&buf)
-		if err == nil {
-			return protocol.Decode(buf, &data)
+		// Start from the zero value of data on every attempt; accounts that
+		// are not in the database have no rows.
+		data = basics.AccountData{}
+		rows, err := qs.lookupStmt.Query(addr[:])
+		if err != nil {
+			return err
 		}
+		defer rows.Close()
 
-		if err == sql.ErrNoRows {
-			// Return the zero value of data
-			return nil
+		first := true
+		for rows.Next() {
+			var buf, rbuf []byte
+			err = rows.Scan(&buf, &rbuf)
+			if err != nil {
+				return err
+			}
+			if first {
+				err = protocol.Decode(buf, &data)
+				if err != nil {
+					return err
+				}
+				first = false
+			}
+			if rbuf == nil {
+				continue
+			}
+			var rdata basics.AccountData
+			err = protocol.Decode(rbuf, &rdata)
+			if err != nil {
+				return err
+			}
+			mergeResourceData(&data, rdata)
 		}
-
-		return err
+		return rows.Err()
 	})
 
 	return
@@ -530,6 +792,48 @@ func accountsAll(tx *sql.Tx) (bals map[basics.Address]basics.AccountData, err er
 	}
 
 	err = rows.Err()
+	if err != nil {
+		return
+	}
+	rows.Close()
+
+	resRows, err := tx.Query("SELECT address, data FROM resources")
+	if err != nil {
+		return
+	}
+	defer resRows.Close()
+
+	for resRows.Next() {
+		var addrbuf []byte
+		var buf []byte
+		err = resRows.Scan(&addrbuf, &buf)
+		if err != nil {
+			return
+		}
+
+		var rdata basics.AccountData
+		err = protocol.Decode(buf, &rdata)
+		if err != nil {
+			return
+		}
+
+		var addr basics.Address
+		if len(addrbuf) != len(addr) {
+			err = fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(addr))
+			return
+		}
+
+		copy(addr[:], addrbuf)
+		data, ok := bals[addr]
+		if !ok {
+			err = fmt.Errorf("Account DB has resources for missing account %v", addr)
+			return
+		}
+		mergeResourceData(&data, rdata)
+		bals[addr] = data
+	}
+
+	err = resRows.Err()
 	return
 }
 
@@ -561,16 +865,17 @@ func accountsPutTotals(tx *sql.Tx, totals AccountTotals, catchpointStaging bool)
 	return err
 }
 
-// getChangedAssetIndices takes an accountDelta and returns which AssetIndices
-// were created and which were deleted
-func getChangedAssetIndices(creator basics.Address, delta accountDelta) map[basics.AssetIndex]modifiedAsset {
-	assetMods := make(map[basics.AssetIndex]modifiedAsset)
+// getChangedCreatables takes an accountDelta and returns which assets and
+// applications were created and which were deleted
+func getChangedCreatables(creator basics.Address, delta accountDelta) map[basics.CreatableIndex]modifiedCreatable {
+	creatableMods := make(map[basics.CreatableIndex]modifiedCreatable)
 
 	// Get assets that were created
 	for idx := range delta.new.AssetParams {
 		// AssetParams are in now the balance record now, but _weren't_ before
 		if _, ok := delta.old.AssetParams[idx]; !ok {
-			assetMods[idx] = modifiedAsset{
+			creatableMods[basics.CreatableIndex(idx)] = modifiedCreatable{
+				ctype:   basics.AssetCreatable,
 				created: true,
 				creator: creator,
 			}
@@ -581,14 +886,159 @@ func getChangedAssetIndices(creator basics.Address, delta accountDelta) map[basi
 	for idx := range delta.old.AssetParams {
 		// AssetParams were in the balance record, but _aren't_ anymore
 		if _, ok := delta.new.AssetParams[idx]; !ok {
-			assetMods[idx] = modifiedAsset{
+			creatableMods[basics.CreatableIndex(idx)] = modifiedCreatable{
+				ctype:   basics.AssetCreatable,
 				created: false,
 				creator: creator,
 			}
 		}
 	}
 
-	return assetMods
+	// Get applications that were created
+	for idx := range delta.new.AppParams {
+		if _, ok := delta.old.AppParams[idx]; !ok {
+			creatableMods[basics.CreatableIndex(idx)] = modifiedCreatable{
+				ctype:   basics.AppCreatable,
+				created: true,
+				creator: creator,
+			}
+		}
+	}
+
+	// Get applications that were deleted
+	for idx := range delta.old.AppParams {
+		if _, ok := delta.new.AppParams[idx]; !ok {
+			creatableMods[basics.CreatableIndex(idx)] = modifiedCreatable{
+				ctype:   basics.AppCreatable,
+				created: false,
+				creator: creator,
+			}
+		}
+	}
+
+	return creatableMods
+}
+
+// splitAccountData separates the asset and application holdings and params of
+// an account from the rest of its data. The returned base has none of these,
+// and each of the returned resources holds only the entries of its creatable.
+func splitAccountData(data basics.AccountData) (base basics.AccountData, resources map[resourceKey]basics.AccountData) {
+	base = data
+	base.Assets = nil
+	base.AssetParams = nil
+	base.AppLocalStates = nil
+	base.AppParams = nil
+
+	resources = make(map[resourceKey]basics.AccountData)
+	resource := func(cidx basics.CreatableIndex, ctype basics.CreatableType) basics.AccountData {
+		return resources[resourceKey{cidx: cidx, ctype: ctype}]
+	}
+	for aidx, holding := range data.Assets {
+		key := resourceKey{cidx: basics.CreatableIndex(aidx), ctype: basics.AssetCreatable}
+		rdata := resource(key.cidx, key.ctype)
+		rdata.Assets = map[basics.AssetIndex]basics.AssetHolding{aidx: holding}
+		resources[key] = rdata
+	}
+	for aidx, params := range data.AssetParams {
+		key := resourceKey{cidx: basics.CreatableIndex(aidx), ctype: basics.AssetCreatable}
+		rdata := resource(key.cidx, key.ctype)
+		rdata.AssetParams = map[basics.AssetIndex]basics.AssetParams{aidx: params}
+		resources[key] = rdata
+	}
+	for aidx, state := range data.AppLocalStates {
+		key := resourceKey{cidx: basics.CreatableIndex(aidx), ctype: basics.AppCreatable}
+		rdata := resource(key.cidx, key.ctype)
+		rdata.AppLocalStates = map[basics.AppIndex]basics.AppLocalState{aidx: state}
+		resources[key] = rdata
+	}
+	for aidx, params := range data.AppParams {
+		key := resourceKey{cidx: basics.CreatableIndex(aidx), ctype: basics.AppCreatable}
+		rdata := resource(key.cidx, key.ctype)
+		rdata.AppParams = map[basics.AppIndex]basics.AppParams{aidx: params}
+		resources[key] = rdata
+	}
+	return
+}
+
+// mergeResourceData adds the entries of a single resource, as returned by
+// splitAccountData, back into the account data.
+func mergeResourceData(data *basics.AccountData, rdata basics.AccountData) {
+	for aidx, holding := range rdata.Assets {
+		if data.Assets == nil {
+			data.Assets = make(map[basics.AssetIndex]basics.AssetHolding)
+		}
+		data.Assets[aidx] = holding
+	}
+	for aidx, params := range rdata.AssetParams {
+		if data.AssetParams == nil {
+			data.AssetParams = make(map[basics.AssetIndex]basics.AssetParams)
+		}
+		data.AssetParams[aidx] = params
+	}
+	for aidx, state := range rdata.AppLocalStates {
+		if data.AppLocalStates == nil {
+			data.AppLocalStates = make(map[basics.AppIndex]basics.AppLocalState)
+		}
+		data.AppLocalStates[aidx] = state
+	}
+	for aidx, params := range rdata.AppParams {
+		if data.AppParams == nil {
+			data.AppParams = make(map[basics.AppIndex]basics.AppParams)
+		}
+		data.AppParams[aidx] = params
+	}
+}
+
+// accountRecordsDelta is an accountDelta split at the granularity of the
+// database records: the accountbase record and each of the resources records
+// whose encoding changed.
+type accountRecordsDelta struct {
+	// base is set only if the accountbase record changed, in which case
+	// oldBase and newBase are its old and new encodings. An empty encoding
+	// stands for a missing record.
+	base      *accountDelta
+	oldBase   []byte
+	newBase   []byte
+	resources map[resourceKey]encodedResourceDelta
+}
+
+// encodedResourceDelta is the change of a single resources record. An empty
+// old or new encoding stands for a missing record.
+type encodedResourceDelta struct {
+	old []byte
+	new []byte
+}
+
+// getChangedRecords returns the records that need to be written to the
+// database, and rehashed in the balances trie, for the given accountDelta.
+func getChangedRecords(delta accountDelta) (rd accountRecordsDelta) {
+	oldBase, oldResources := splitAccountData(delta.old)
+	newBase, newResources := splitAccountData(delta.new)
+
+	if !delta.old.IsZero() {
+		rd.oldBase = protocol.Encode(&oldBase)
+	}
+	if !delta.new.IsZero() {
+		rd.newBase = protocol.Encode(&newBase)
+	}
+	if !bytes.Equal(rd.oldBase, rd.newBase) {
+		rd.base = &accountDelta{old: oldBase, new: newBase}
+	}
+
+	rd.resources = make(map[resourceKey]encodedResourceDelta)
+	for key, rdata := range oldResources {
+		rd.resources[key] = encodedResourceDelta{old: protocol.Encode(&rdata)}
+	}
+	for key, rdata := range newResources {
+		rdelta := rd.resources[key]
+		rdelta.new = protocol.Encode(&rdata)
+		if bytes.Equal(rdelta.old, rdelta.new) {
+			delete(rd.resources, key)
+			continue
+		}
+		rd.resources[key] = rdelta
+	}
+	return
 }
 
 func accountsNewRound(tx *sql.Tx, updates map[basics.Address]accountDelta, rewardsLevel uint64, proto config.ConsensusParams) (err error) {
@@ -612,38 +1062,65 @@ func accountsNewRound(tx *sql.Tx, updates map[basics.Address]accountDelta, rewar
 	}
 	defer replaceStmt.Close()
 
-	insertAssetIdxStmt, err := tx.Prepare("INSERT INTO assetcreators (asset, creator) VALUES (?, ?)")
+	insertCreatableIdxStmt, err := tx.Prepare("INSERT INTO assetcreators (asset, creator, ctype) VALUES (?, ?, ?)")
+	if err != nil {
+		return
+	}
+	defer insertCreatableIdxStmt.Close()
+
+	deleteCreatableIdxStmt, err := tx.Prepare("DELETE FROM assetcreators WHERE asset=? AND ctype=?")
 	if err != nil {
 		return
 	}
-	defer insertAssetIdxStmt.Close()
+	defer deleteCreatableIdxStmt.Close()
 
-	deleteAssetIdxStmt, err := tx.Prepare("DELETE FROM assetcreators WHERE asset=?")
+	deleteResourceStmt, err := tx.Prepare("DELETE FROM resources WHERE address=? AND aidx=? AND rtype=?")
 	if err != nil {
 		return
 	}
-	defer deleteAssetIdxStmt.Close()
+	defer deleteResourceStmt.Close()
+
+	replaceResourceStmt, err := tx.Prepare("REPLACE INTO resources (address, aidx, rtype, data) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return
+	}
+	defer replaceResourceStmt.Close()
 
 	for addr, data := range updates {
-		if data.new.IsZero() {
-			// prune empty accounts
-			_, err = deleteStmt.Exec(addr[:])
-		} else {
-			_, err = replaceStmt.Exec(addr[:], protocol.Encode(&data.new))
+		records := getChangedRecords(data)
+		if records.base != nil {
+			if len(records.newBase) == 0 {
+				// prune empty accounts
+				_, err = deleteStmt.Exec(addr[:])
+			} else {
+				_, err = replaceStmt.Exec(addr[:], records.newBase)
+			}
+			if err != nil {
+				return
+			}
 		}
-		if err != nil {
-			return
+
+		// only the resources that changed are rewritten
+		for key, rdelta := range records.resources {
+			if len(rdelta.new) == 0 {
+				_, err = deleteResourceStmt.Exec(addr[:], key.cidx, key.ctype)
+			} else {
+				_, err = replaceResourceStmt.Exec(addr[:], key.cidx, key.ctype, rdelta.new)
+			}
+			if err != nil {
+				return
+			}
 		}
 
 		totals.delAccount(proto, data.old, &ot)
 		totals.addAccount(proto, data.new, &ot)
 
-		adeltas := getChangedAssetIndices(addr, data)
-		for aidx, delta := range adeltas {
+		cdeltas := getChangedCreatables(addr, data)
+		for cidx, delta := range cdeltas {
 			if delta.created {
-				_, err = insertAssetIdxStmt.Exec(aidx, addr[:])
+				_, err = insertCreatableIdxStmt.Exec(cidx, addr[:], delta.ctype)
 			} else {
-				_, err = deleteAssetIdxStmt.Exec(aidx)
+				_, err = deleteCreatableIdxStmt.Exec(cidx, delta.ctype)
 			}
 			if err != nil {
 				return
@@ -710,9 +1187,14 @@ func updateAccountsRound(tx *sql.Tx, rnd basics.Round, hashRound basics.Round) (
 }
 
 // encodedAccountsRange returns an array containing the account data, in the same way it appear in the database
-// starting at entry startAccountIndex, and up to accountCount accounts long.
-func encodedAccountsRange(tx *sql.Tx, startAccountIndex, accountCount int) (bals []encodedBalanceRecord, err error) {
-	rows, err := tx.Query("SELECT address, data FROM accountbase ORDER BY rowid LIMIT ? OFFSET ?", accountCount, startAccountIndex)
+// starting at entry startAccountIndex, and up to accountCount accounts long. When catchpointStaging is set, the
+// accounts are read from the catchpoint catchup staging table.
+func encodedAccountsRange(tx *sql.Tx, catchpointStaging bool, startAccountIndex, accountCount int) (bals []encodedBalanceRecord, err error) {
+	accountsTable := "accountbase"
+	if catchpointStaging {
+		accountsTable = "catchpointbalances"
+	}
+	rows, err := tx.Query(fmt.Sprintf("SELECT address, data FROM %s ORDER BY rowid LIMIT ? OFFSET ?", accountsTable), accountCount, startAccountIndex)
 	if err != nil {
 		return
 	}
@@ -742,6 +1224,120 @@ func encodedAccountsRange(tx *sql.Tx, startAccountIndex, accountCount int) (bals
 	return
 }
 
+// encodedResourcesRange returns an array containing the resources records, in the same way they appear in the database
+// starting at entry startResourceIndex, and up to resourceCount records long. When catchpointStaging is set, the
+// records are read from the catchpoint catchup staging table.
+func encodedResourcesRange(tx *sql.Tx, catchpointStaging bool, startResourceIndex, resourceCount int) (resources []encodedResourceRecord, err error) {
+	resourcesTable := "resources"
+	if catchpointStaging {
+		resourcesTable = "catchpointresources"
+	}
+	rows, err := tx.Query(fmt.Sprintf("SELECT address, aidx, rtype, data FROM %s ORDER BY rowid LIMIT ? OFFSET ?", resourcesTable), resourceCount, startResourceIndex)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	resources = make([]encodedResourceRecord, 0, resourceCount)
+	for rows.Next() {
+		var addrbuf []byte
+		var record encodedResourceRecord
+		var buf []byte
+		err = rows.Scan(&addrbuf, &record.CreatableIndex, &record.CreatableType, &buf)
+		if err != nil {
+			return
+		}
+
+		if len(addrbuf) != len(record.Address) {
+			err = fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(record.Address))
+			return
+		}
+
+		copy(record.Address[:], addrbuf)
+		record.Data = buf
+		resources = append(resources, record)
+	}
+
+	err = rows.Err()
+	return
+}
+
+// encodedFullAccountsRange returns an array containing the account data of each account merged with all of its
+// resources records, starting at entry startAccountIndex of the accounts table, and up to accountCount accounts long.
+// These are the encodings the balances trie of the catchpoint files predating the resources records hashed. When
+// catchpointStaging is set, the accounts are read from the catchpoint catchup staging tables.
+func encodedFullAccountsRange(tx *sql.Tx, catchpointStaging bool, startAccountIndex, accountCount int) (bals []encodedBalanceRecord, err error) {
+	accountsTable, resourcesTable := "accountbase", "resources"
+	if catchpointStaging {
+		accountsTable, resourcesTable = "catchpointbalances", "catchpointresources"
+	}
+	rows, err := tx.Query(fmt.Sprintf("SELECT a.address, a.data, r.data FROM (SELECT rowid, address, data FROM %s ORDER BY rowid LIMIT ? OFFSET ?) a LEFT JOIN %s r ON r.address = a.address ORDER BY a.rowid", accountsTable, resourcesTable), accountCount, startAccountIndex)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	bals = make([]encodedBalanceRecord, 0, accountCount)
+	var addr basics.Address
+	var data basics.AccountData
+	flush := func() {
+		if len(bals) > 0 {
+			bals[len(bals)-1].AccountData = protocol.Encode(&data)
+		}
+	}
+	for rows.Next() {
+		var addrbuf []byte
+		var buf, rbuf []byte
+		err = rows.Scan(&addrbuf, &buf, &rbuf)
+		if err != nil {
+			return
+		}
+
+		if len(addrbuf) != len(addr) {
+			err = fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(addr))
+			return
+		}
+
+		if len(bals) == 0 || !bytes.Equal(addrbuf, bals[len(bals)-1].Address[:]) {
+			flush()
+			copy(addr[:], addrbuf)
+			data = basics.AccountData{}
+			err = protocol.Decode(buf, &data)
+			if err != nil {
+				return
+			}
+			bals = append(bals, encodedBalanceRecord{Address: addr})
+		}
+		if rbuf == nil {
+			continue
+		}
+		var rdata basics.AccountData
+		err = protocol.Decode(rbuf, &rdata)
+		if err != nil {
+			return
+		}
+		mergeResourceData(&data, rdata)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+	flush()
+	return
+}
+
+// totalResources returns the total number of resources records
+func totalResources(ctx context.Context, tx *sql.Tx) (total uint64, err error) {
+	err = tx.QueryRowContext(ctx, "SELECT count(*) FROM resources").Scan(&total)
+	if err == sql.ErrNoRows {
+		total = 0
+		err = nil
+		return
+	}
+	return
+}
+
 // totalAccounts returns the total number of accounts
 func totalAccounts(ctx context.Context, tx *sql.Tx) (total uint64, err error) {
 	err = tx.QueryRowContext(ctx, "SELECT count(*) FROM accountbase").Scan(&total)
@@ -211,6 +211,238 @@ func TestAccountDBRound(t *testing.T) {
 	}
 }
 
+func TestAccountDBCreatables(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	dbs := dbOpenTest(t)
+	setDbLogging(t, dbs)
+	defer dbs.close()
+
+	tx, err := dbs.wdb.Handle.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	creator := randomAddress()
+	old := basics.AccountData{MicroAlgos: basics.MicroAlgos{Raw: 1000000}}
+	err = accountsInit(tx, map[basics.Address]basics.AccountData{creator: old}, proto)
+	require.NoError(t, err)
+
+	new := old
+	new.AssetParams = map[basics.AssetIndex]basics.AssetParams{1: {Total: 10}}
+	new.AppParams = map[basics.AppIndex]basics.AppParams{2: {}}
+
+	cdeltas := getChangedCreatables(creator, accountDelta{old: old, new: new})
+	require.Equal(t, map[basics.CreatableIndex]modifiedCreatable{
+		1: {ctype: basics.AssetCreatable, created: true, creator: creator},
+		2: {ctype: basics.AppCreatable, created: true, creator: creator},
+	}, cdeltas)
+
+	err = accountsNewRound(tx, map[basics.Address]accountDelta{creator: {old: old, new: new}}, 0, proto)
+	require.NoError(t, err)
+
+	aq, err := accountsDbInit(tx, tx)
+	require.NoError(t, err)
+
+	addr, ok, err := aq.lookupCreator(1, basics.AssetCreatable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, creator, addr)
+
+	addr, ok, err = aq.lookupCreator(2, basics.AppCreatable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, creator, addr)
+
+	// an index is only found under the type it was created as
+	_, ok, err = aq.lookupCreator(2, basics.AssetCreatable)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	apps, err := aq.listCreatables(10, 10, basics.AppCreatable)
+	require.NoError(t, err)
+	require.Equal(t, []basics.CreatableLocator{{Type: basics.AppCreatable, Creator: creator, Index: 2}}, apps)
+
+	// deleting the application leaves the asset in place
+	deleted := new
+	deleted.AppParams = nil
+	err = accountsNewRound(tx, map[basics.Address]accountDelta{creator: {old: new, new: deleted}}, 0, proto)
+	require.NoError(t, err)
+
+	_, ok, err = aq.lookupCreator(2, basics.AppCreatable)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = aq.lookupCreator(1, basics.AssetCreatable)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestAccountDBCreatableTypeUpgrade(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	dbs := dbOpenTest(t)
+	setDbLogging(t, dbs)
+	defer dbs.close()
+
+	tx, err := dbs.wdb.Handle.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	// an assetcreators table as written before applications were tracked
+	_, err = tx.Exec("CREATE TABLE assetcreators (asset integer primary key, creator blob)")
+	require.NoError(t, err)
+	creator := randomAddress()
+	_, err = tx.Exec("INSERT INTO assetcreators (asset, creator) VALUES (?, ?)", 7, creator[:])
+	require.NoError(t, err)
+
+	err = accountsInit(tx, nil, proto)
+	require.NoError(t, err)
+	// a second initialization finds the column in place
+	err = accountsInit(tx, nil, proto)
+	require.NoError(t, err)
+
+	aq, err := accountsDbInit(tx, tx)
+	require.NoError(t, err)
+
+	addr, ok, err := aq.lookupCreator(7, basics.AssetCreatable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, creator, addr)
+}
+
+func TestAccountDBResources(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	dbs := dbOpenTest(t)
+	setDbLogging(t, dbs)
+	defer dbs.close()
+
+	tx, err := dbs.wdb.Handle.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	addr := randomAddress()
+	old := basics.AccountData{
+		MicroAlgos:     basics.MicroAlgos{Raw: 1000000},
+		Assets:         map[basics.AssetIndex]basics.AssetHolding{1: {Amount: 5}, 2: {Amount: 6}},
+		AssetParams:    map[basics.AssetIndex]basics.AssetParams{1: {Total: 10}},
+		AppLocalStates: map[basics.AppIndex]basics.AppLocalState{1: {}},
+	}
+	accts := map[basics.Address]basics.AccountData{addr: old}
+	err = accountsInit(tx, accts, proto)
+	require.NoError(t, err)
+	checkAccounts(t, tx, 0, accts)
+
+	// asset 1 holding and params share a record, the app with the same index has its own
+	var count int
+	err = tx.QueryRow("SELECT count(*) FROM resources WHERE address=?", addr[:]).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	// changing a single holding only rewrites its own record
+	new := old
+	new.Assets = map[basics.AssetIndex]basics.AssetHolding{1: {Amount: 5}, 2: {Amount: 7}}
+	records := getChangedRecords(accountDelta{old: old, new: new})
+	require.Nil(t, records.base)
+	require.Equal(t, 1, len(records.resources))
+	_, ok := records.resources[resourceKey{cidx: 2, ctype: basics.AssetCreatable}]
+	require.True(t, ok)
+
+	err = accountsNewRound(tx, map[basics.Address]accountDelta{addr: {old: old, new: new}}, 0, proto)
+	require.NoError(t, err)
+	accts[addr] = new
+	checkAccounts(t, tx, 0, accts)
+
+	// closing the account removes all of its records
+	err = accountsNewRound(tx, map[basics.Address]accountDelta{addr: {old: new}}, 0, proto)
+	require.NoError(t, err)
+	err = tx.QueryRow("SELECT count(*) FROM resources WHERE address=?", addr[:]).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	delete(accts, addr)
+	checkAccounts(t, tx, 0, accts)
+}
+
+func TestAccountDBResourcesUpgrade(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	dbs := dbOpenTest(t)
+	setDbLogging(t, dbs)
+	defer dbs.close()
+
+	tx, err := dbs.wdb.Handle.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	// accountbase records as written before the resources table existed
+	accts := randomAccounts(10)
+	for addr, data := range accts {
+		data.Assets = map[basics.AssetIndex]basics.AssetHolding{3: {Amount: 1}}
+		data.AppParams = map[basics.AppIndex]basics.AppParams{4: {ApprovalProgram: []byte{1}}}
+		accts[addr] = data
+	}
+	for _, tableCreate := range accountsSchema {
+		_, err = tx.Exec(tableCreate)
+		require.NoError(t, err)
+	}
+	_, err = tx.Exec("INSERT INTO acctrounds (id, rnd) VALUES ('acctbase', 0)")
+	require.NoError(t, err)
+	for addr, data := range accts {
+		_, err = tx.Exec("INSERT INTO accountbase (address, data) VALUES (?, ?)", addr[:], protocol.Encode(&data))
+		require.NoError(t, err)
+	}
+	_, err = tx.Exec("INSERT INTO accounthashes (id, data) VALUES (1, ?)", []byte{1})
+	require.NoError(t, err)
+
+	err = accountsInit(tx, nil, proto)
+	require.NoError(t, err)
+	// a second initialization finds the table in place
+	err = accountsInit(tx, nil, proto)
+	require.NoError(t, err)
+
+	var count int
+	err = tx.QueryRow("SELECT count(*) FROM resources").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 2*len(accts), count)
+	// the balances trie hashed the whole accounts, so it's dropped to be rebuilt from the split records
+	err = tx.QueryRow("SELECT count(*) FROM accounthashes").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	bases, err := encodedAccountsRange(tx, false, 0, len(accts))
+	require.NoError(t, err)
+	require.Equal(t, len(accts), len(bases))
+	for _, base := range bases {
+		var data basics.AccountData
+		err = protocol.Decode(base.AccountData, &data)
+		require.NoError(t, err)
+		require.Nil(t, data.Assets)
+		require.Nil(t, data.AppParams)
+	}
+
+	aq, err := accountsDbInit(tx, tx)
+	require.NoError(t, err)
+	for addr, data := range accts {
+		d, err := aq.lookup(addr)
+		require.NoError(t, err)
+		require.Equal(t, data, d)
+	}
+
+	// the accounts merged with their resources, as hashed by older catchpoint files, are encoded as they were before
+	// the upgrade
+	fulls, err := encodedFullAccountsRange(tx, false, 0, len(accts)+1)
+	require.NoError(t, err)
+	require.Equal(t, len(accts), len(fulls))
+	for _, full := range fulls {
+		data := accts[full.Address]
+		require.Equal(t, protocol.Encode(&data), []byte(full.AccountData))
+	}
+	fulls, err = encodedFullAccountsRange(tx, false, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(fulls))
+	require.Equal(t, bases[1].Address, fulls[0].Address)
+}
+
 func BenchmarkReadingAllBalances(b *testing.B) {
 	proto := config.Consensus[protocol.ConsensusCurrentVersion]
 	//b.N = 50000
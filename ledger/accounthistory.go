@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// accountHistorySchema is the account history kept by the archival ledgers
+// that have it enabled. accounthistory holds the state of each account at the
+// rounds that modified it, and totalshistory holds the totals of every round.
+// The oldest round of the history is stored in acctrounds as 'histbase'; the
+// state of all the accounts is recorded at that round.
+var accountHistorySchema = []string{
+	`CREATE TABLE IF NOT EXISTS accounthistory (
+		address blob,
+		round integer,
+		data blob,
+		PRIMARY KEY (address, round))`,
+	`CREATE TABLE IF NOT EXISTS totalshistory (
+		round integer primary key,
+		data blob)`,
+	accountHistoryRoundIndex,
+}
+
+// accountHistoryRoundIndex lets the accounts modified by a round be listed
+// without scanning the whole history.
+const accountHistoryRoundIndex = `CREATE INDEX IF NOT EXISTS accounthistory_round ON accounthistory (round)`
+
+var accountHistoryResetExprs = []string{
+	`DROP TABLE IF EXISTS accounthistory`,
+	`DROP TABLE IF EXISTS totalshistory`,
+	`DELETE FROM acctrounds WHERE id='histbase'`,
+}
+
+type accountHistoryDbQueries struct {
+	lookupStmt   *sql.Stmt
+	modifiedStmt *sql.Stmt
+	totalsStmt   *sql.Stmt
+}
+
+// accountsInitHistory creates the account history tables if they don't exist
+// yet, and records the current state of all the accounts and their totals as
+// the state of round rnd, which becomes the oldest round of the history.
+func accountsInitHistory(tx *sql.Tx, rnd basics.Round) error {
+	var count int
+	err := tx.QueryRow("SELECT count(*) FROM sqlite_master WHERE type='table' AND name='accounthistory'").Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		// the history may predate the index.
+		_, err = tx.Exec(accountHistoryRoundIndex)
+		return err
+	}
+
+	for _, tableCreate := range accountHistorySchema {
+		_, err = tx.Exec(tableCreate)
+		if err != nil {
+			return err
+		}
+	}
+
+	bals, err := accountsAll(tx)
+	if err != nil {
+		return err
+	}
+	insertStmt, err := tx.Prepare("INSERT INTO accounthistory (address, round, data) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertStmt.Close()
+	for addr, data := range bals {
+		_, err = insertStmt.Exec(addr[:], rnd, protocol.Encode(&data))
+		if err != nil {
+			return err
+		}
+	}
+
+	totals, err := accountsTotals(tx, false)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO totalshistory (round, data) VALUES (?, ?)", rnd, protocol.Encode(&totals))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT OR REPLACE INTO acctrounds (id, rnd) VALUES ('histbase', ?)", rnd)
+	return err
+}
+
+// accountsResetHistory deletes the account history.
+func accountsResetHistory(tx *sql.Tx) error {
+	for _, stmt := range accountHistoryResetExprs {
+		_, err := tx.Exec(stmt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accountsHistoryRound returns the oldest round of the account history.
+func accountsHistoryRound(tx *sql.Tx) (rnd basics.Round, err error) {
+	err = tx.QueryRow("SELECT rnd FROM acctrounds WHERE id='histbase'").Scan(&rnd)
+	return
+}
+
+// accountsHistoryNewRound records the state of the accounts modified by round
+// rnd, along with the totals at the end of the round.
+func accountsHistoryNewRound(tx *sql.Tx, rnd basics.Round, updates map[basics.Address]accountDelta, totals AccountTotals) error {
+	replaceStmt, err := tx.Prepare("REPLACE INTO accounthistory (address, round, data) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer replaceStmt.Close()
+
+	for addr, delta := range updates {
+		_, err = replaceStmt.Exec(addr[:], rnd, protocol.Encode(&delta.new))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("REPLACE INTO totalshistory (round, data) VALUES (?, ?)", rnd, protocol.Encode(&totals))
+	return err
+}
+
+// accountsHistoryTotals returns the totals of all accounts at the end of
+// round rnd.
+func accountsHistoryTotals(tx *sql.Tx, rnd basics.Round) (totals AccountTotals, err error) {
+	var buf []byte
+	err = tx.QueryRow("SELECT data FROM totalshistory WHERE round=?", rnd).Scan(&buf)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &totals)
+	return
+}
+
+func accountHistoryDbInit(r db.Queryable) (*accountHistoryDbQueries, error) {
+	var err error
+	qs := &accountHistoryDbQueries{}
+
+	qs.lookupStmt, err = r.Prepare("SELECT data FROM accounthistory WHERE address=? AND round<=? ORDER BY round DESC LIMIT 1")
+	if err != nil {
+		return nil, err
+	}
+
+	qs.modifiedStmt, err = r.Prepare("SELECT address, data FROM accounthistory WHERE round=?")
+	if err != nil {
+		return nil, err
+	}
+
+	qs.totalsStmt, err = r.Prepare("SELECT data FROM totalshistory WHERE round=?")
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+// lookup returns the account data of addr at the end of round rnd. An account
+// that didn't exist at that round has the zero account data.
+func (qs *accountHistoryDbQueries) lookup(addr basics.Address, rnd basics.Round) (data basics.AccountData, err error) {
+	err = db.Retry(func() error {
+		data = basics.AccountData{}
+		var buf []byte
+		err := qs.lookupStmt.QueryRow(addr[:], rnd).Scan(&buf)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return protocol.Decode(buf, &data)
+	})
+	return
+}
+
+// modified returns the new state of the accounts modified by round rnd. It
+// isn't meaningful for the oldest round of the history, at which the state of
+// all the accounts is recorded.
+func (qs *accountHistoryDbQueries) modified(rnd basics.Round) (accts map[basics.Address]basics.AccountData, err error) {
+	err = db.Retry(func() error {
+		accts = make(map[basics.Address]basics.AccountData)
+		rows, err := qs.modifiedStmt.Query(rnd)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var addrbuf, buf []byte
+			err = rows.Scan(&addrbuf, &buf)
+			if err != nil {
+				return err
+			}
+			var addr basics.Address
+			if len(addrbuf) != len(addr) {
+				return fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(addr))
+			}
+			copy(addr[:], addrbuf)
+			var data basics.AccountData
+			err = protocol.Decode(buf, &data)
+			if err != nil {
+				return err
+			}
+			accts[addr] = data
+		}
+		return rows.Err()
+	})
+	return
+}
+
+// totals returns the totals of all accounts at the end of round rnd.
+func (qs *accountHistoryDbQueries) totals(rnd basics.Round) (totals AccountTotals, err error) {
+	err = db.Retry(func() error {
+		totals = AccountTotals{}
+		var buf []byte
+		err := qs.totalsStmt.QueryRow(rnd).Scan(&buf)
+		if err != nil {
+			return err
+		}
+		return protocol.Decode(buf, &totals)
+	})
+	return
+}
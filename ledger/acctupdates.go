@@ -69,15 +69,18 @@ type modifiedAccount struct {
 	ndeltas int
 }
 
-type modifiedAsset struct {
+type modifiedCreatable struct {
+	// Type of the creatable: app or asset
+	ctype basics.CreatableType
+
 	// Created if true, deleted if false
 	created bool
 
-	// Creator is the creator of the asset
+	// Creator is the creator of the app/asset
 	creator basics.Address
 
-	// Keeps track of how many times this asset appears in
-	// accountUpdates.assetDeltas
+	// Keeps track of how many times this app/asset appears in
+	// accountUpdates.creatableDeltas
 	ndeltas int
 }
 
@@ -99,6 +102,10 @@ type accountUpdates struct {
 	// archivalLedger determines whether the associated ledger was configured as archival ledger or not.
 	archivalLedger bool
 
+	// accountHistory determines whether the state of the accounts at every committed round is kept on disk,
+	// so that lookups aren't limited to the rounds that are held in memory. Only archival ledgers keep it.
+	accountHistory bool
+
 	// catchpointFileHistoryLength defines how many catchpoint files we want to store back.
 	// 0 means don't store any, -1 mean unlimited and positive number suggest the number of most recent catchpoint files.
 	catchpointFileHistoryLength int
@@ -106,10 +113,16 @@ type accountUpdates struct {
 	// dynamic variables
 
 	// Connection to the database.
-	dbs dbPair
+	dbs trackerStore
+
+	// Fast accounts DB lookups.
+	accountsq trackerQueries
 
-	// Prepared SQL statements for fast accounts DB lookups.
-	accountsq *accountsDbQueries
+	// The account history lookups; only set when accountHistory is enabled.
+	historyq accountHistoryQueries
+
+	// historyRound is the oldest round of the account history.
+	historyRound basics.Round
 
 	// dbRound is always exactly accountsRound(),
 	// cached to avoid SQL queries.
@@ -122,12 +135,12 @@ type accountUpdates struct {
 	// address that appears in deltas.
 	accounts map[basics.Address]modifiedAccount
 
-	// assetDeltas stores asset updates for every round after dbRound.
-	assetDeltas []map[basics.AssetIndex]modifiedAsset
+	// creatableDeltas stores creatable updates for every round after dbRound.
+	creatableDeltas []map[basics.CreatableIndex]modifiedCreatable
 
-	// assets stores the most recent asset state for every asset
-	// that appears in assetDeltas
-	assets map[basics.AssetIndex]modifiedAsset
+	// creatables stores the most recent state for every creatable that
+	// appears in creatableDeltas
+	creatables map[basics.CreatableIndex]modifiedCreatable
 
 	// protos stores consensus parameters dbRound and every
 	// round after it; i.e., protos is one longer than deltas.
@@ -200,6 +213,7 @@ func (au *accountUpdates) initialize(cfg config.Local, dbPathPrefix string, gene
 	au.initAccounts = genesisAccounts
 	au.dbDirectory = filepath.Dir(dbPathPrefix)
 	au.archivalLedger = cfg.Archival
+	au.accountHistory = cfg.Archival && cfg.EnableAccountHistory
 	au.catchpointInterval = cfg.CatchpointInterval
 	au.catchpointFileHistoryLength = cfg.CatchpointFileHistoryLength
 	if cfg.CatchpointFileHistoryLength < -1 {
@@ -249,6 +263,9 @@ func (au *accountUpdates) close() {
 func (au *accountUpdates) lookup(rnd basics.Round, addr basics.Address, withRewards bool) (data basics.AccountData, err error) {
 	au.accountsMu.RLock()
 	defer au.accountsMu.RUnlock()
+	if rnd < au.dbRound {
+		return au.lookupHistory(rnd, addr, withRewards)
+	}
 	offset, err := au.roundOffset(rnd)
 	if err != nil {
 		return
@@ -291,52 +308,101 @@ func (au *accountUpdates) lookup(rnd basics.Round, addr basics.Address, withRewa
 	return au.accountsq.lookup(addr)
 }
 
-func (au *accountUpdates) listAssets(maxAssetIdx basics.AssetIndex, maxResults uint64) ([]basics.CreatableLocator, error) {
+// lookupHistory returns the state of addr at a round that was already flushed
+// to disk, from the account history. It's called with accountsMu held.
+func (au *accountUpdates) lookupHistory(rnd basics.Round, addr basics.Address, withRewards bool) (data basics.AccountData, err error) {
+	if !au.accountHistory || rnd < au.historyRound {
+		return basics.AccountData{}, au.historyNotAvailable(rnd)
+	}
+
+	data, err = au.historyq.lookup(addr, rnd)
+	if err != nil || !withRewards {
+		return
+	}
+	hdr, err := au.ledger.BlockHdr(rnd)
+	if err != nil {
+		return basics.AccountData{}, err
+	}
+	return data.WithUpdatedRewards(config.Consensus[hdr.CurrentProtocol], hdr.RewardsLevel), nil
+}
+
+func (au *accountUpdates) historyNotAvailable(rnd basics.Round) error {
+	oldest := au.dbRound
+	if au.accountHistory {
+		oldest = au.historyRound
+	}
+	return ErrHistoryNotAvailable{Round: rnd, Oldest: oldest}
+}
+
+// lookupDelta returns the new state of the accounts modified by round rnd.
+// The rounds whose changes are held in memory are available, along with the
+// ones of the account history past its oldest round, if it's enabled.
+func (au *accountUpdates) lookupDelta(rnd basics.Round) (map[basics.Address]basics.AccountData, error) {
+	au.accountsMu.RLock()
+	defer au.accountsMu.RUnlock()
+	latest := au.dbRound + basics.Round(len(au.deltas))
+	oldest := au.dbRound + 1
+	if au.accountHistory {
+		oldest = au.historyRound + 1
+	}
+	if rnd < oldest || rnd > latest {
+		return nil, ErrDeltaNotAvailable{Round: rnd, Oldest: oldest, Latest: latest}
+	}
+	if rnd <= au.dbRound {
+		return au.historyq.modified(rnd)
+	}
+	return modifiedAccounts(au.deltas[rnd-au.dbRound-1]), nil
+}
+
+// listCreatables lists the creatables of the given type with an index no
+// greater than maxCreatableIdx, in descending index order.
+func (au *accountUpdates) listCreatables(maxCreatableIdx basics.CreatableIndex, maxResults uint64, ctype basics.CreatableType) ([]basics.CreatableLocator, error) {
 	au.accountsMu.RLock()
 	defer au.accountsMu.RUnlock()
 
-	// Sort indices for assets that have been created/deleted. If this
+	// Sort indices for creatables that have been created/deleted. If this
 	// turns out to be too inefficient, we could keep around a heap of
-	// created/deleted asset indices in memory.
-	keys := make([]basics.AssetIndex, 0, len(au.assets))
-	for aidx := range au.assets {
-		if aidx <= maxAssetIdx {
-			keys = append(keys, aidx)
+	// created/deleted creatable indices in memory.
+	keys := make([]basics.CreatableIndex, 0, len(au.creatables))
+	for cidx, delta := range au.creatables {
+		if cidx <= maxCreatableIdx && delta.ctype == ctype {
+			keys = append(keys, cidx)
 		}
 	}
 	sort.Slice(keys, func(i, j int) bool { return keys[i] > keys[j] })
 
-	// Check for assets that haven't been synced to disk yet.
-	var unsyncedAssets []basics.CreatableLocator
-	deletedAssets := make(map[basics.AssetIndex]bool)
-	for _, aidx := range keys {
-		delta := au.assets[aidx]
+	// Check for creatables that haven't been synced to disk yet.
+	var unsyncedCreatables []basics.CreatableLocator
+	deletedCreatables := make(map[basics.CreatableIndex]bool)
+	for _, cidx := range keys {
+		delta := au.creatables[cidx]
 		if delta.created {
-			// Created asset that only exists in memory
-			unsyncedAssets = append(unsyncedAssets, basics.CreatableLocator{
-				Index:   basics.CreatableIndex(aidx),
+			// Created creatable that only exists in memory
+			unsyncedCreatables = append(unsyncedCreatables, basics.CreatableLocator{
+				Type:    delta.ctype,
+				Index:   cidx,
 				Creator: delta.creator,
 			})
 		} else {
-			// Mark deleted assets for exclusion from the results set
-			deletedAssets[aidx] = true
+			// Mark deleted creatables for exclusion from the results set
+			deletedCreatables[cidx] = true
 		}
 	}
 
-	// Check in-memory created assets, which will always be newer than anything
+	// Check in-memory created creatables, which will always be newer than anything
 	// in the database
 	var res []basics.CreatableLocator
-	for _, loc := range unsyncedAssets {
+	for _, loc := range unsyncedCreatables {
 		if uint64(len(res)) == maxResults {
 			return res, nil
 		}
 		res = append(res, loc)
 	}
 
-	// Fetch up to maxResults - len(res) + len(deletedAssets) from the database, so we
-	// have enough extras in case assets were deleted
-	numToFetch := maxResults - uint64(len(res)) + uint64(len(deletedAssets))
-	dbResults, err := au.accountsq.listAssets(maxAssetIdx, numToFetch)
+	// Fetch up to maxResults - len(res) + len(deletedCreatables) from the database, so we
+	// have enough extras in case creatables were deleted
+	numToFetch := maxResults - uint64(len(res)) + uint64(len(deletedCreatables))
+	dbResults, err := au.accountsq.listCreatables(maxCreatableIdx, numToFetch, ctype)
 	if err != nil {
 		return nil, err
 	}
@@ -348,8 +414,8 @@ func (au *accountUpdates) listAssets(maxAssetIdx basics.AssetIndex, maxResults u
 			return res, nil
 		}
 
-		// Asset was deleted
-		if _, ok := deletedAssets[basics.AssetIndex(loc.Index)]; ok {
+		// Creatable was deleted
+		if _, ok := deletedCreatables[loc.Index]; ok {
 			continue
 		}
 
@@ -367,41 +433,37 @@ func (au *accountUpdates) getLastCatchpointLabel() string {
 	return au.lastCatchpointLabel
 }
 
-// getAssetCreatorForRound returns the asset creator for a given asset index at a given round
-func (au *accountUpdates) getAssetCreatorForRound(rnd basics.Round, aidx basics.AssetIndex) (basics.Address, error) {
+// getCreatorForRound returns the creator for a given asset or application
+// index at a given round. ok is false if the creatable does not exist or has
+// been deleted at that round.
+func (au *accountUpdates) getCreatorForRound(rnd basics.Round, cidx basics.CreatableIndex, ctype basics.CreatableType) (creator basics.Address, ok bool, err error) {
 	au.accountsMu.RLock()
 	defer au.accountsMu.RUnlock()
 	offset, err := au.roundOffset(rnd)
 	if err != nil {
-		return basics.Address{}, err
+		return basics.Address{}, false, err
 	}
 
-	// If this is the most recent round, au.assets has will have the latest
-	// state and we can skip scanning backwards over assetDeltas
+	// If this is the most recent round, au.creatables has will have the latest
+	// state and we can skip scanning backwards over creatableDeltas
 	if offset == uint64(len(au.deltas)) {
-		// Check if we already have the asset/creator in cache
-		assetDelta, ok := au.assets[aidx]
-		if ok {
-			if assetDelta.created {
-				return assetDelta.creator, nil
-			}
-			return basics.Address{}, fmt.Errorf("asset %v has been deleted", aidx)
+		// Check if we already have the creatable/creator in cache
+		creatableDelta, ok := au.creatables[cidx]
+		if ok && creatableDelta.ctype == ctype {
+			return creatableDelta.creator, creatableDelta.created, nil
 		}
 	} else {
 		for offset > 0 {
 			offset--
-			assetDelta, ok := au.assetDeltas[offset][aidx]
-			if ok {
-				if assetDelta.created {
-					return assetDelta.creator, nil
-				}
-				return basics.Address{}, fmt.Errorf("asset %v has been deleted", aidx)
+			creatableDelta, ok := au.creatableDeltas[offset][cidx]
+			if ok && creatableDelta.ctype == ctype {
+				return creatableDelta.creator, creatableDelta.created, nil
 			}
 		}
 	}
 
 	// Check the database
-	return au.accountsq.lookupAssetCreator(aidx)
+	return au.accountsq.lookupCreator(cidx, ctype)
 }
 
 // committedUpTo enqueues commiting the balances for round committedRound-lookback.
@@ -530,7 +592,7 @@ func (au *accountUpdates) newBlock(blk bookkeeping.Block, delta StateDelta) {
 	}
 	au.deltas = append(au.deltas, delta.accts)
 	au.protos = append(au.protos, proto)
-	au.assetDeltas = append(au.assetDeltas, delta.assets)
+	au.creatableDeltas = append(au.creatableDeltas, delta.creatables)
 	au.roundDigest = append(au.roundDigest, blk.Digest())
 	au.deltasAccum = append(au.deltasAccum, len(delta.accts)+au.deltasAccum[len(au.deltasAccum)-1])
 
@@ -549,12 +611,13 @@ func (au *accountUpdates) newBlock(blk bookkeeping.Block, delta StateDelta) {
 		au.accounts[addr] = macct
 	}
 
-	for aidx, adelta := range delta.assets {
-		masset := au.assets[aidx]
-		masset.creator = adelta.creator
-		masset.created = adelta.created
-		masset.ndeltas++
-		au.assets[aidx] = masset
+	for cidx, cdelta := range delta.creatables {
+		mcreat := au.creatables[cidx]
+		mcreat.creator = cdelta.creator
+		mcreat.created = cdelta.created
+		mcreat.ctype = cdelta.ctype
+		mcreat.ndeltas++
+		au.creatables[cidx] = mcreat
 	}
 
 	if ot.Overflowed {
@@ -571,6 +634,12 @@ func (au *accountUpdates) newBlock(blk bookkeeping.Block, delta StateDelta) {
 func (au *accountUpdates) totals(rnd basics.Round) (totals AccountTotals, err error) {
 	au.accountsMu.RLock()
 	defer au.accountsMu.RUnlock()
+	if rnd < au.dbRound {
+		if !au.accountHistory || rnd < au.historyRound {
+			return AccountTotals{}, au.historyNotAvailable(rnd)
+		}
+		return au.historyq.totals(rnd)
+	}
 	offset, err := au.roundOffset(rnd)
 	if err != nil {
 		return
@@ -582,8 +651,8 @@ func (au *accountUpdates) totals(rnd basics.Round) (totals AccountTotals, err er
 
 func (au *accountUpdates) getCatchpointStream(round basics.Round) (io.ReadCloser, error) {
 	dbFileName := ""
-	err := au.dbs.rdb.Atomic(func(tx *sql.Tx) (err error) {
-		dbFileName, _, _, err = getCatchpoint(tx, round)
+	err := au.dbs.read(func(tx trackerTx) (err error) {
+		dbFileName, _, _, err = tx.getCatchpoint(round)
 		return
 	})
 	if err != nil && err != sql.ErrNoRows {
@@ -679,7 +748,7 @@ func (au *accountUpdates) initializeFromDisk(l ledgerForTracker) (lastBalancesRo
 	}
 
 	lastestBlockRound = l.Latest()
-	err = au.dbs.wdb.Atomic(func(tx *sql.Tx) error {
+	err = au.dbs.write(func(tx trackerTx) error {
 		var err0 error
 		au.dbRound, err0 = au.accountsInitialize(tx)
 		if err0 != nil {
@@ -688,7 +757,7 @@ func (au *accountUpdates) initializeFromDisk(l ledgerForTracker) (lastBalancesRo
 		// Check for blocks DB and tracker DB un-sync
 		if au.dbRound > lastestBlockRound {
 			au.log.Warnf("resetting accounts DB (on round %v, but blocks DB's latest is %v)", au.dbRound, lastestBlockRound)
-			err0 = accountsReset(tx)
+			err0 = tx.accountsReset()
 			if err0 != nil {
 				return err0
 			}
@@ -698,7 +767,19 @@ func (au *accountUpdates) initializeFromDisk(l ledgerForTracker) (lastBalancesRo
 			}
 		}
 
-		totals, err0 := accountsTotals(tx, false)
+		if au.accountHistory {
+			err0 = tx.accountsInitHistory(au.dbRound)
+			if err0 == nil {
+				au.historyRound, err0 = tx.accountsHistoryRound()
+			}
+		} else {
+			err0 = tx.accountsResetHistory()
+		}
+		if err0 != nil {
+			return err0
+		}
+
+		totals, err0 := tx.accountsTotals(false)
 		if err0 != nil {
 			return err0
 		}
@@ -710,11 +791,18 @@ func (au *accountUpdates) initializeFromDisk(l ledgerForTracker) (lastBalancesRo
 		return
 	}
 
-	au.accountsq, err = accountsDbInit(au.dbs.rdb.Handle, au.dbs.wdb.Handle)
+	au.accountsq, err = au.dbs.queries()
 	if err != nil {
 		return
 	}
 
+	if au.accountHistory {
+		au.historyq, err = au.dbs.historyQueries()
+		if err != nil {
+			return
+		}
+	}
+
 	au.lastCatchpointLabel, _, err = au.accountsq.readCatchpointStateString(context.Background(), catchpointStateLastCatchpoint)
 	if err != nil {
 		return
@@ -726,9 +814,9 @@ func (au *accountUpdates) initializeFromDisk(l ledgerForTracker) (lastBalancesRo
 	}
 	au.protos = []config.ConsensusParams{config.Consensus[hdr.CurrentProtocol]}
 	au.deltas = nil
-	au.assetDeltas = nil
+	au.creatableDeltas = nil
 	au.accounts = make(map[basics.Address]modifiedAccount)
-	au.assets = make(map[basics.AssetIndex]modifiedAsset)
+	au.creatables = make(map[basics.CreatableIndex]modifiedCreatable)
 	au.deltasAccum = []int{0}
 
 	// keep these channel closed if we're not generating catchpoint
@@ -759,14 +847,30 @@ func accountHashBuilder(addr basics.Address, accountData basics.AccountData, enc
 	return hash[:]
 }
 
+// resourceHashBuilder calculates the hash key used for the trie by combining the account address, the creatable and
+// the data of one of its resources records. The creatable type byte keeps these hashes apart from the ones of the
+// accounts, whose encodings always start with a msgpack map header.
+func resourceHashBuilder(addr basics.Address, cidx basics.CreatableIndex, ctype basics.CreatableType, encodedResourceData []byte) []byte {
+	hash := make([]byte, 4+crypto.DigestSize)
+	// write out the lowest 32 bits of the creatable index, so that the resources of a single creatable are
+	// kept close to each other in the trie.
+	for i, idx := 3, uint64(cidx); i >= 0; i, idx = i-1, idx>>8 {
+		hash[i] = byte(idx)
+	}
+	entry := append(append(addr[:], byte(ctype)), encodedResourceData...)
+	entryHash := crypto.Hash(entry)
+	copy(hash[4:], entryHash[:])
+	return hash[:]
+}
+
 // Initialize accounts DB if needed and return account round
-func (au *accountUpdates) accountsInitialize(tx *sql.Tx) (basics.Round, error) {
-	err := accountsInit(tx, au.initAccounts, au.initProto)
+func (au *accountUpdates) accountsInitialize(tx trackerTx) (basics.Round, error) {
+	err := tx.accountsInit(au.initAccounts, au.initProto)
 	if err != nil {
 		return 0, err
 	}
 
-	rnd, hashRound, err := accountsRound(tx)
+	rnd, hashRound, err := tx.accountsRound()
 	if err != nil {
 		return 0, err
 	}
@@ -774,7 +878,7 @@ func (au *accountUpdates) accountsInitialize(tx *sql.Tx) (basics.Round, error) {
 	if hashRound != rnd {
 		// if the hashed round is different then the base round, something was modified, and the accounts aren't in sync
 		// with the hashes.
-		err = resetAccountHashes(tx)
+		err = tx.resetAccountHashes()
 		if err != nil {
 			return 0, err
 		}
@@ -785,7 +889,7 @@ func (au *accountUpdates) accountsInitialize(tx *sql.Tx) (basics.Round, error) {
 	}
 
 	// create the merkle trie for the balances
-	committer, err := makeMerkleCommitter(tx, false)
+	committer, err := tx.merkleCommitter(false)
 	if err != nil {
 		return 0, fmt.Errorf("accountsInitialize was unable to makeMerkleCommitter: %v", err)
 	}
@@ -803,7 +907,7 @@ func (au *accountUpdates) accountsInitialize(tx *sql.Tx) (basics.Round, error) {
 	if rootHash.IsZero() {
 		accountIdx := 0
 		for {
-			bal, err := encodedAccountsRange(tx, accountIdx, trieRebuildAccountChunkSize)
+			bal, err := tx.encodedAccountsRange(false, accountIdx, trieRebuildAccountChunkSize)
 			if err != nil {
 				return rnd, err
 			}
@@ -837,6 +941,33 @@ func (au *accountUpdates) accountsInitialize(tx *sql.Tx) (basics.Round, error) {
 			}
 			accountIdx += trieRebuildAccountChunkSize
 		}
+
+		resourceIdx := 0
+		for {
+			resources, err := tx.encodedResourcesRange(false, resourceIdx, trieRebuildAccountChunkSize)
+			if err != nil {
+				return rnd, err
+			}
+			for _, resource := range resources {
+				hash := resourceHashBuilder(resource.Address, resource.CreatableIndex, resource.CreatableType, resource.Data)
+				added, err := trie.Add(hash)
+				if err != nil {
+					return rnd, fmt.Errorf("accountsInitialize was unable to add changes to trie: %v", err)
+				}
+				if !added {
+					au.log.Warnf("attempted to add duplicate hash '%v' to merkle trie.", hash)
+				}
+			}
+
+			_, err = trie.Evict(true)
+			if err != nil {
+				return 0, fmt.Errorf("accountsInitialize was unable to commit changes to trie: %v", err)
+			}
+			if len(resources) < trieRebuildAccountChunkSize {
+				break
+			}
+			resourceIdx += trieRebuildAccountChunkSize
+		}
 	}
 	au.balancesTrie = trie
 	return rnd, nil
@@ -846,27 +977,33 @@ func (au *accountUpdates) accountsUpdateBalances(accountsDeltas map[basics.Addre
 	if au.catchpointInterval == 0 {
 		return nil
 	}
-	var added, deleted bool
 	for addr, delta := range accountsDeltas {
-		if !delta.old.IsZero() {
-			deleteHash := accountHashBuilder(addr, delta.old, protocol.Encode(&delta.old))
-			deleted, err = au.balancesTrie.Delete(deleteHash)
+		records := getChangedRecords(delta)
+		if records.base != nil {
+			var deleteHash, addHash []byte
+			if len(records.oldBase) > 0 {
+				deleteHash = accountHashBuilder(addr, records.base.old, records.oldBase)
+			}
+			if len(records.newBase) > 0 {
+				addHash = accountHashBuilder(addr, records.base.new, records.newBase)
+			}
+			err = au.updateBalancesTrie(deleteHash, addHash)
 			if err != nil {
 				return err
 			}
-			if !deleted {
-				au.log.Warnf("failed to delete hash '%v' from merkle trie", deleteHash)
-			}
 		}
-		if !delta.new.IsZero() {
-			addHash := accountHashBuilder(addr, delta.new, protocol.Encode(&delta.new))
-			added, err = au.balancesTrie.Add(addHash)
+		for key, rdelta := range records.resources {
+			var deleteHash, addHash []byte
+			if len(rdelta.old) > 0 {
+				deleteHash = resourceHashBuilder(addr, key.cidx, key.ctype, rdelta.old)
+			}
+			if len(rdelta.new) > 0 {
+				addHash = resourceHashBuilder(addr, key.cidx, key.ctype, rdelta.new)
+			}
+			err = au.updateBalancesTrie(deleteHash, addHash)
 			if err != nil {
 				return err
 			}
-			if !added {
-				au.log.Warnf("attempted to add duplicate hash '%v' to merkle trie", addHash)
-			}
 		}
 	}
 	// write it all to disk.
@@ -874,6 +1011,29 @@ func (au *accountUpdates) accountsUpdateBalances(accountsDeltas map[basics.Addre
 	return
 }
 
+// updateBalancesTrie replaces the deleteHash entry of the balances trie with the addHash one; either of them may be nil.
+func (au *accountUpdates) updateBalancesTrie(deleteHash, addHash []byte) error {
+	if deleteHash != nil {
+		deleted, err := au.balancesTrie.Delete(deleteHash)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			au.log.Warnf("failed to delete hash '%v' from merkle trie", deleteHash)
+		}
+	}
+	if addHash != nil {
+		added, err := au.balancesTrie.Add(addHash)
+		if err != nil {
+			return err
+		}
+		if !added {
+			au.log.Warnf("attempted to add duplicate hash '%v' to merkle trie", addHash)
+		}
+	}
+	return nil
+}
+
 func (au *accountUpdates) accountsCreateCatchpointLabel(committedRound basics.Round, totals AccountTotals, ledgerBlockDigest crypto.Digest, trieBalancesHash crypto.Digest) (label string, err error) {
 	cpLabel := makeCatchpointLabel(committedRound, ledgerBlockDigest, trieBalancesHash, totals)
 	label = cpLabel.String()
@@ -959,10 +1119,10 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 	// account DB, so that we can drop the corresponding refcounts in
 	// au.accounts.
 	flushcount := make(map[basics.Address]int)
-	assetFlushcount := make(map[basics.AssetIndex]int)
+	creatableFlushcount := make(map[basics.CreatableIndex]int)
 	for i := uint64(0); i < offset; i++ {
-		for aidx := range au.assetDeltas[i] {
-			assetFlushcount[aidx] = assetFlushcount[aidx] + 1
+		for cidx := range au.creatableDeltas[i] {
+			creatableFlushcount[cidx] = creatableFlushcount[cidx] + 1
 		}
 	}
 
@@ -992,10 +1152,10 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 	var catchpointLabel string
 	beforeUpdatingBalancesTime := time.Now()
 	var trieBalancesHash crypto.Digest
-	err := au.dbs.wdb.Atomic(func(tx *sql.Tx) (err error) {
+	err := au.dbs.write(func(tx trackerTx) (err error) {
 		treeTargetRound := basics.Round(0)
 		if au.catchpointInterval > 0 {
-			mc, err0 := makeMerkleCommitter(tx, false)
+			mc, err0 := tx.merkleCommitter(false)
 			if err0 != nil {
 				return err0
 			}
@@ -1012,17 +1172,24 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 			treeTargetRound = dbRound + basics.Round(offset)
 		}
 		for i := uint64(0); i < offset; i++ {
-			err = accountsNewRound(tx, deltas[i], roundTotals[i+1].RewardsLevel, protos[i+1])
+			err = tx.accountsNewRound(deltas[i], roundTotals[i+1].RewardsLevel, protos[i+1])
 			if err != nil {
 				return err
 			}
 
+			if au.accountHistory {
+				err = tx.accountsHistoryNewRound(dbRound+basics.Round(i)+1, deltas[i], roundTotals[i+1])
+				if err != nil {
+					return err
+				}
+			}
+
 			err = au.accountsUpdateBalances(deltas[i])
 			if err != nil {
 				return err
 			}
 		}
-		err = updateAccountsRound(tx, dbRound+basics.Round(offset), treeTargetRound)
+		err = tx.updateAccountsRound(dbRound+basics.Round(offset), treeTargetRound)
 		if isCatchpointRound {
 			trieBalancesHash, err = au.balancesTrie.RootHash()
 			if err != nil {
@@ -1076,21 +1243,21 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 		}
 	}
 
-	for aidx, cnt := range assetFlushcount {
-		masset, ok := au.assets[aidx]
+	for cidx, cnt := range creatableFlushcount {
+		mcreat, ok := au.creatables[cidx]
 		if !ok {
-			au.log.Panicf("inconsistency: flushed %d changes to asset %d, but not in au.assets", cnt, aidx)
+			au.log.Panicf("inconsistency: flushed %d changes to creatable %d, but not in au.creatables", cnt, cidx)
 		}
 
-		if cnt > masset.ndeltas {
-			au.log.Panicf("inconsistency: flushed %d changes to asset %d, but au.assets had %d", cnt, aidx, masset.ndeltas)
+		if cnt > mcreat.ndeltas {
+			au.log.Panicf("inconsistency: flushed %d changes to creatable %d, but au.creatables had %d", cnt, cidx, mcreat.ndeltas)
 		}
 
-		masset.ndeltas -= cnt
-		if masset.ndeltas == 0 {
-			delete(au.assets, aidx)
+		mcreat.ndeltas -= cnt
+		if mcreat.ndeltas == 0 {
+			delete(au.creatables, cidx)
 		} else {
-			au.assets[aidx] = masset
+			au.creatables[cidx] = mcreat
 		}
 	}
 
@@ -1099,7 +1266,7 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 	au.roundDigest = au.roundDigest[offset:]
 	au.protos = au.protos[offset:]
 	au.roundTotals = au.roundTotals[offset:]
-	au.assetDeltas = au.assetDeltas[offset:]
+	au.creatableDeltas = au.creatableDeltas[offset:]
 	au.dbRound = newBase
 	au.lastFlushTime = flushTime
 
@@ -1145,7 +1312,7 @@ func (au *accountUpdates) generateCatchpoint(committedRound basics.Round, label
 	relCatchpointFileName := filepath.Join("catchpoints", catchpointRoundToPath(committedRound))
 	absCatchpointFileName := filepath.Join(au.dbDirectory, relCatchpointFileName)
 
-	catchpointWriter := makeCatchpointWriter(absCatchpointFileName, au.dbs.rdb, committedRound, committedRoundDigest, label)
+	catchpointWriter := makeCatchpointWriter(absCatchpointFileName, au.dbs, committedRound, committedRoundDigest, label)
 
 	more := true
 	const shortChunkExecutionDuration = 50 * time.Millisecond
@@ -17,7 +17,6 @@
 package ledger
 
 import (
-	"database/sql"
 	"fmt"
 	"os"
 	"runtime"
@@ -29,6 +28,7 @@ import (
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/logging"
@@ -36,17 +36,16 @@ import (
 )
 
 type mockLedgerForTracker struct {
-	dbs    dbPair
+	dbs    trackerStore
 	blocks []blockEntry
 	log    logging.Logger
 }
 
 func makeMockLedgerForTracker(t testing.TB) *mockLedgerForTracker {
-	dbs := dbOpenTest(t)
+	dbs := &sqliteTrackerStore{dbs: dbOpenTest(t)}
 	dblogger := logging.TestingLog(t)
 	dblogger.SetLevel(logging.Info)
-	dbs.rdb.SetLogger(dblogger)
-	dbs.wdb.SetLogger(dblogger)
+	dbs.setLogger(dblogger)
 	return &mockLedgerForTracker{dbs: dbs, log: dblogger}
 }
 
@@ -81,12 +80,12 @@ func (ml *mockLedgerForTracker) BlockHdr(rnd basics.Round) (bookkeeping.BlockHea
 	return ml.blocks[int(rnd)].block.BlockHeader, nil
 }
 
-func (ml *mockLedgerForTracker) trackerDB() dbPair {
+func (ml *mockLedgerForTracker) trackerDB() trackerStore {
 	return ml.dbs
 }
 
-func (ml *mockLedgerForTracker) blockDB() dbPair {
-	return dbPair{}
+func (ml *mockLedgerForTracker) blockDB() blockStore {
+	return nil
 }
 
 func (ml *mockLedgerForTracker) trackerLog() logging.Logger {
@@ -106,9 +105,9 @@ func (au *accountUpdates) allBalances(rnd basics.Round) (bals map[basics.Address
 		return
 	}
 
-	err = au.dbs.rdb.Atomic(func(tx *sql.Tx) error {
+	err = au.dbs.read(func(tx trackerTx) error {
 		var err0 error
-		bals, err0 = accountsAll(tx)
+		bals, err0 = tx.accountsAll()
 		return err0
 	})
 	if err != nil {
@@ -286,6 +285,15 @@ func TestAcctUpdates(t *testing.T) {
 		rewardsLevels = append(rewardsLevels, rewardLevel)
 
 		checkAcctUpdates(t, au, 0, i, accts, rewardsLevels, proto)
+
+		modified, err := au.lookupDelta(i)
+		require.NoError(t, err)
+		require.Equal(t, len(updates), len(modified))
+		for addr, delta := range updates {
+			require.Equal(t, delta.new, modified[addr])
+		}
+		_, err = au.lookupDelta(i + 1)
+		require.IsType(t, ErrDeltaNotAvailable{}, err)
 	}
 
 	for i := basics.Round(0); i < 15; i++ {
@@ -295,7 +303,375 @@ func TestAcctUpdates(t *testing.T) {
 		au.committedUpTo(basics.Round(proto.MaxBalLookback) + i)
 		au.waitAccountsWriting()
 		checkAcctUpdates(t, au, i, basics.Round(proto.MaxBalLookback+14), accts, rewardsLevels, proto)
+
+		_, err := au.lookupDelta(au.dbRound)
+		require.IsType(t, ErrDeltaNotAvailable{}, err)
+	}
+}
+
+func TestAcctUpdatesHistory(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	ml := makeMockLedgerForTracker(t)
+	defer ml.close()
+	ml.blocks = randomInitChain(protocol.ConsensusCurrentVersion, 10)
+
+	accts := []map[basics.Address]basics.AccountData{randomAccounts(20)}
+
+	pooldata := basics.AccountData{}
+	pooldata.MicroAlgos.Raw = 1000 * 1000 * 1000 * 1000
+	pooldata.Status = basics.NotParticipating
+	accts[0][testPoolAddr] = pooldata
+
+	sinkdata := basics.AccountData{}
+	sinkdata.MicroAlgos.Raw = 1000 * 1000 * 1000 * 1000
+	sinkdata.Status = basics.NotParticipating
+	accts[0][testSinkAddr] = sinkdata
+
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+	cfg.EnableAccountHistory = true
+	cfg.CatchpointInterval = 0
+	au := &accountUpdates{}
+	au.initialize(cfg, ".", proto, accts[0])
+	defer au.close()
+
+	err := au.loadFromDisk(ml)
+	require.NoError(t, err)
+
+	var totals []AccountTotals
+	for i := basics.Round(0); i < 10; i++ {
+		accts = append(accts, accts[0])
+		rtotals, err := au.totals(i)
+		require.NoError(t, err)
+		totals = append(totals, rtotals)
+	}
+	accts = accts[:10]
+
+	latest := basics.Round(proto.MaxBalLookback + 15)
+	rewardLevel := uint64(0)
+	deltas := make(map[basics.Round]map[basics.Address]accountDelta)
+	for i := basics.Round(10); i <= latest; i++ {
+		rewardLevelDelta := crypto.RandUint64() % 5
+		rewardLevel += rewardLevelDelta
+		updates, newAccts := randomDeltasBalanced(1, accts[i-1], rewardLevel)
+
+		prevTotals, err := au.totals(basics.Round(i - 1))
+		require.NoError(t, err)
+
+		oldPool := accts[i-1][testPoolAddr]
+		newPool := newAccts[testPoolAddr]
+		newPool.MicroAlgos.Raw -= prevTotals.RewardUnits() * rewardLevelDelta
+		updates[testPoolAddr] = accountDelta{old: oldPool, new: newPool}
+		newAccts[testPoolAddr] = newPool
+
+		blk := bookkeeping.Block{
+			BlockHeader: bookkeeping.BlockHeader{
+				Round: basics.Round(i),
+			},
+		}
+		blk.RewardsLevel = rewardLevel
+		blk.CurrentProtocol = protocol.ConsensusCurrentVersion
+		ml.blocks = append(ml.blocks, blockEntry{block: blk})
+
+		au.newBlock(blk, StateDelta{
+			accts: updates,
+			hdr:   &blk.BlockHeader,
+		})
+		accts = append(accts, newAccts)
+		deltas[i] = updates
+
+		rtotals, err := au.totals(i)
+		require.NoError(t, err)
+		totals = append(totals, rtotals)
+	}
+
+	au.lastFlushTime = time.Time{}
+	au.committedUpTo(latest)
+	au.waitAccountsWriting()
+	require.Equal(t, latest-basics.Round(proto.MaxBalLookback), au.dbRound)
+
+	checkHistory := func() {
+		for rnd := basics.Round(0); rnd <= au.latest(); rnd++ {
+			for addr, data := range accts[rnd] {
+				d, err := au.lookup(rnd, addr, false)
+				require.NoError(t, err)
+				require.Equal(t, data, d, "round %d", rnd)
+			}
+			rtotals, err := au.totals(rnd)
+			require.NoError(t, err)
+			require.Equal(t, totals[rnd], rtotals, "round %d", rnd)
+		}
+
+		rnd := basics.Round(12)
+		for addr, data := range accts[rnd] {
+			d, err := au.lookup(rnd, addr, true)
+			require.NoError(t, err)
+			require.Equal(t, data.WithUpdatedRewards(proto, ml.blocks[rnd].block.RewardsLevel), d)
+		}
+
+		// the changes of the rounds that were flushed are read from the history
+		_, err := au.lookupDelta(au.historyRound)
+		require.IsType(t, ErrDeltaNotAvailable{}, err)
+		for rnd := au.historyRound + 1; rnd <= au.latest(); rnd++ {
+			modified, err := au.lookupDelta(rnd)
+			require.NoError(t, err)
+			require.Equal(t, len(deltas[rnd]), len(modified), "round %d", rnd)
+			for addr, delta := range deltas[rnd] {
+				require.Equal(t, delta.new, modified[addr], "round %d", rnd)
+			}
+		}
+	}
+	checkHistory()
+
+	// the history is persisted; the mock ledger can't replay the blocks that
+	// were not flushed, so they're left out.
+	au.close()
+	ml.blocks = ml.blocks[:au.dbRound+1]
+	au = &accountUpdates{}
+	au.initialize(cfg, ".", proto, accts[0])
+	err = au.loadFromDisk(ml)
+	require.NoError(t, err)
+	checkHistory()
+
+	// and dropped once it gets disabled
+	au.close()
+	cfg.EnableAccountHistory = false
+	au = &accountUpdates{}
+	au.initialize(cfg, ".", proto, accts[0])
+	err = au.loadFromDisk(ml)
+	require.NoError(t, err)
+	_, err = au.lookup(1, testPoolAddr, false)
+	require.Equal(t, ErrHistoryNotAvailable{Round: 1, Oldest: au.dbRound}, err)
+	_, err = au.totals(1)
+	require.IsType(t, ErrHistoryNotAvailable{}, err)
+
+	// enabling it again starts the history over from the current round
+	au.close()
+	cfg.EnableAccountHistory = true
+	au = &accountUpdates{}
+	au.initialize(cfg, ".", proto, accts[0])
+	err = au.loadFromDisk(ml)
+	require.NoError(t, err)
+	_, err = au.lookup(1, testPoolAddr, false)
+	require.Equal(t, ErrHistoryNotAvailable{Round: 1, Oldest: au.dbRound}, err)
+	d, err := au.lookup(au.dbRound, testPoolAddr, false)
+	require.NoError(t, err)
+	require.Equal(t, accts[au.dbRound][testPoolAddr], d)
+}
+
+// TestAcctUpdatesApplications checks that the applications created and
+// deleted by the deltas are listed and their creators found, both before and
+// after the deltas are flushed to the database.
+func TestAcctUpdatesApplications(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	ml := makeMockLedgerForTracker(t)
+	defer ml.close()
+	ml.blocks = randomInitChain(protocol.ConsensusCurrentVersion, 10)
+
+	// the genesis creator owns application 1 and asset 2.
+	genesisCreator := randomAddress()
+	genesis := randomAccounts(5)
+	creatorData := randomAccountData(0)
+	creatorData.AppParams = map[basics.AppIndex]basics.AppParams{1: {}}
+	creatorData.AssetParams = map[basics.AssetIndex]basics.AssetParams{2: {Total: 10}}
+	genesis[genesisCreator] = creatorData
+	var creators []basics.Address
+	for addr := range genesis {
+		creators = append(creators, addr)
+	}
+
+	au := &accountUpdates{}
+	au.initialize(config.GetDefaultLocal(), ".", proto, genesis)
+	defer au.close()
+	err := au.loadFromDisk(ml)
+	require.NoError(t, err)
+
+	accts := make(map[basics.Address]basics.AccountData)
+	for addr, data := range genesis {
+		accts[addr] = data
+	}
+	appCreators := map[basics.AppIndex]basics.Address{1: genesisCreator}
+	// existing holds the applications of the latest round, in index order.
+	existing := []basics.AppIndex{1}
+	// createdAt and deletedAt hold the rounds the applications were created
+	// and deleted at.
+	createdAt := make(map[basics.AppIndex]basics.Round)
+	deletedAt := make(map[basics.AppIndex]basics.Round)
+
+	// setApp creates or deletes an application of an account in updates.
+	setApp := func(updates map[basics.Address]accountDelta, addr basics.Address, appIdx basics.AppIndex, create bool) {
+		old := accts[addr]
+		new := old
+		new.AppParams = make(map[basics.AppIndex]basics.AppParams)
+		for idx, params := range old.AppParams {
+			new.AppParams[idx] = params
+		}
+		if create {
+			new.AppParams[appIdx] = basics.AppParams{}
+		} else {
+			delete(new.AppParams, appIdx)
+		}
+		if delta, ok := updates[addr]; ok {
+			old = delta.old
+		}
+		updates[addr] = accountDelta{old: old, new: new}
+		accts[addr] = new
+	}
+
+	// one application is created at every round, and every third round
+	// deletes the previous one.
+	const numApps = 30
+	latest := basics.Round(9)
+	for i := 0; i < numApps; i++ {
+		latest++
+		updates := make(map[basics.Address]accountDelta)
+		if i%3 == 2 {
+			deleted := existing[len(existing)-1]
+			existing = existing[:len(existing)-1]
+			setApp(updates, appCreators[deleted], deleted, false)
+			deletedAt[deleted] = latest
+		}
+		appIdx := basics.AppIndex(100 + i)
+		creator := creators[i%len(creators)]
+		setApp(updates, creator, appIdx, true)
+		appCreators[appIdx] = creator
+		createdAt[appIdx] = latest
+		existing = append(existing, appIdx)
+
+		creatables := make(map[basics.CreatableIndex]modifiedCreatable)
+		for addr, delta := range updates {
+			for cidx, cdelta := range getChangedCreatables(addr, delta) {
+				creatables[cidx] = cdelta
+			}
+		}
+
+		blk := bookkeeping.Block{
+			BlockHeader: bookkeeping.BlockHeader{
+				Round: latest,
+			},
+		}
+		blk.CurrentProtocol = protocol.ConsensusCurrentVersion
+		au.newBlock(blk, StateDelta{
+			accts:      updates,
+			creatables: creatables,
+			hdr:        &blk.BlockHeader,
+		})
+	}
+
+	checkApps := func(checkPast bool) {
+		locs, err := au.listCreatables(basics.CreatableIndex(latest+1000), 1000, basics.AppCreatable)
+		require.NoError(t, err)
+		require.Equal(t, len(existing), len(locs))
+		for i, loc := range locs {
+			appIdx := existing[len(existing)-1-i]
+			require.Equal(t, basics.CreatableLocator{Type: basics.AppCreatable, Creator: appCreators[appIdx], Index: basics.CreatableIndex(appIdx)}, loc)
+		}
+
+		// the results start at the maximum index, and are capped.
+		maxIdx := existing[len(existing)-3]
+		locs, err = au.listCreatables(basics.CreatableIndex(maxIdx), 4, basics.AppCreatable)
+		require.NoError(t, err)
+		require.Equal(t, 4, len(locs))
+		for i, loc := range locs {
+			require.Equal(t, basics.CreatableIndex(existing[len(existing)-3-i]), loc.Index)
+		}
+
+		// the applications aren't listed as assets, nor the other way around.
+		assets, err := au.listCreatables(basics.CreatableIndex(latest+1000), 1000, basics.AssetCreatable)
+		require.NoError(t, err)
+		require.Equal(t, []basics.CreatableLocator{{Type: basics.AssetCreatable, Creator: genesisCreator, Index: 2}}, assets)
+		_, ok, err := au.getCreatorForRound(latest, 2, basics.AppCreatable)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		for appIdx, creator := range appCreators {
+			_, deleted := deletedAt[appIdx]
+			c, ok, err := au.getCreatorForRound(latest, basics.CreatableIndex(appIdx), basics.AppCreatable)
+			require.NoError(t, err)
+			require.Equal(t, !deleted, ok)
+			if ok {
+				require.Equal(t, creator, c)
+			}
+			if !checkPast || appIdx == 1 {
+				continue
+			}
+
+			_, ok, err = au.getCreatorForRound(createdAt[appIdx]-1, basics.CreatableIndex(appIdx), basics.AppCreatable)
+			require.NoError(t, err)
+			require.False(t, ok)
+			c, ok, err = au.getCreatorForRound(createdAt[appIdx], basics.CreatableIndex(appIdx), basics.AppCreatable)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, creator, c)
+			if deleted {
+				_, ok, err = au.getCreatorForRound(deletedAt[appIdx], basics.CreatableIndex(appIdx), basics.AppCreatable)
+				require.NoError(t, err)
+				require.False(t, ok)
+			}
+		}
 	}
+
+	// all the applications but the genesis one are in the deltas.
+	checkApps(true)
+
+	// and once the deltas are flushed, in the database.
+	au.lastFlushTime = time.Time{}
+	au.committedUpTo(latest + basics.Round(proto.MaxBalLookback))
+	au.waitAccountsWriting()
+	require.Equal(t, latest, au.dbRound)
+	checkApps(false)
+}
+
+func TestAcctUpdatesBalancesTrieResources(t *testing.T) {
+	trie, err := merkletrie.MakeTrie(&merkletrie.InMemoryCommitter{}, trieCachedNodesCount)
+	require.NoError(t, err)
+	au := &accountUpdates{catchpointInterval: 1, balancesTrie: trie, log: logging.TestingLog(t)}
+
+	// the trie holds the hashes of the account and of each of its (address, creatable) resources records
+	expectedRoot := func(accts map[basics.Address]basics.AccountData) crypto.Digest {
+		expected, err := merkletrie.MakeTrie(&merkletrie.InMemoryCommitter{}, trieCachedNodesCount)
+		require.NoError(t, err)
+		for addr, data := range accts {
+			base, resources := splitAccountData(data)
+			encoded := protocol.Encode(&base)
+			_, err = expected.Add(accountHashBuilder(addr, base, encoded))
+			require.NoError(t, err)
+			for key, rdata := range resources {
+				_, err = expected.Add(resourceHashBuilder(addr, key.cidx, key.ctype, protocol.Encode(&rdata)))
+				require.NoError(t, err)
+			}
+		}
+		root, err := expected.RootHash()
+		require.NoError(t, err)
+		return root
+	}
+	checkTrie := func(accts map[basics.Address]basics.AccountData) {
+		root, err := au.balancesTrie.RootHash()
+		require.NoError(t, err)
+		require.Equal(t, expectedRoot(accts), root)
+	}
+
+	addr := randomAddress()
+	old := randomAccountData(100)
+	old.Assets = map[basics.AssetIndex]basics.AssetHolding{1: {Amount: 1}, 2: {Amount: 2}}
+	old.AppLocalStates = map[basics.AppIndex]basics.AppLocalState{1: {}}
+	err = au.accountsUpdateBalances(map[basics.Address]accountDelta{addr: {new: old}})
+	require.NoError(t, err)
+	checkTrie(map[basics.Address]basics.AccountData{addr: old})
+
+	// touching a single holding only replaces the hash of its resources record
+	new := old
+	new.Assets = map[basics.AssetIndex]basics.AssetHolding{1: {Amount: 1}, 2: {Amount: 3}}
+	err = au.accountsUpdateBalances(map[basics.Address]accountDelta{addr: {old: old, new: new}})
+	require.NoError(t, err)
+	checkTrie(map[basics.Address]basics.AccountData{addr: new})
+
+	// closing the account removes all of its hashes
+	err = au.accountsUpdateBalances(map[basics.Address]accountDelta{addr: {old: new}})
+	require.NoError(t, err)
+	checkTrie(map[basics.Address]basics.AccountData{})
 }
 
 func TestAcctUpdatesFastUpdates(t *testing.T) {
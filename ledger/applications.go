@@ -0,0 +1,208 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+)
+
+// appTealEvaluator implements transactions.StateEvaluator for the
+// application calls of a block. InitLedger must be called before Check or
+// Eval.
+type appTealEvaluator struct {
+	evalParams logic.EvalParams
+
+	// round and timestamp are the values of the global Round and
+	// LatestTimestamp fields.
+	round     basics.Round
+	timestamp int64
+}
+
+// Eval evaluates a stateful TEAL program for the application call.
+func (ae *appTealEvaluator) Eval(program []byte) (pass bool, stateDelta basics.EvalDelta, innerTxns []transactions.SignedTxn, err error) {
+	return logic.EvalStatefulInner(program, ae.evalParams)
+}
+
+// Check computes the cost of a stateful TEAL program for the application call.
+func (ae *appTealEvaluator) Check(program []byte) (cost int, err error) {
+	return logic.CheckStateful(program, ae.evalParams)
+}
+
+// InitLedger restricts the programs of the application call to the balance
+// records of the given accounts, and to the global state of the given
+// applications.
+func (ae *appTealEvaluator) InitLedger(balances transactions.Balances, acctWhitelist []basics.Address, appGlobalWhitelist []basics.AppIndex, appIdx basics.AppIndex) error {
+	ledger := &appLedger{
+		balances:  balances,
+		appIdx:    appIdx,
+		accounts:  make(map[basics.Address]bool, len(acctWhitelist)+1),
+		apps:      make(map[basics.AppIndex]bool, len(appGlobalWhitelist)),
+		round:     ae.round,
+		timestamp: ae.timestamp,
+	}
+	for _, addr := range acctWhitelist {
+		ledger.accounts[addr] = true
+	}
+	// the application account sends the inner transactions of the application.
+	ledger.accounts[appIdx.Address()] = true
+	for _, aidx := range appGlobalWhitelist {
+		ledger.apps[aidx] = true
+	}
+	ae.evalParams.Ledger = ledger
+	return nil
+}
+
+// appLedger implements logic.LedgerForLogic on top of the balances a
+// transaction is applied to.
+type appLedger struct {
+	balances transactions.Balances
+	appIdx   basics.AppIndex
+
+	// accounts and apps are the balance records and global states the
+	// programs of the application call may access.
+	accounts map[basics.Address]bool
+	apps     map[basics.AppIndex]bool
+
+	round     basics.Round
+	timestamp int64
+}
+
+func (al *appLedger) get(addr basics.Address) (basics.BalanceRecord, error) {
+	if !al.accounts[addr] {
+		return basics.BalanceRecord{}, fmt.Errorf("cannot access account %s", addr.String())
+	}
+	return al.balances.Get(addr, true)
+}
+
+// appParams returns the params and the creator of an application, the
+// current one if appIdx is 0.
+func (al *appLedger) appParams(appIdx basics.AppIndex) (basics.AppParams, basics.Address, error) {
+	if appIdx == 0 {
+		appIdx = al.appIdx
+	}
+	if !al.apps[appIdx] {
+		return basics.AppParams{}, basics.Address{}, fmt.Errorf("cannot access app %d", appIdx)
+	}
+	creator, exists, err := al.balances.GetAppCreator(appIdx)
+	if err != nil {
+		return basics.AppParams{}, basics.Address{}, err
+	}
+	if !exists {
+		return basics.AppParams{}, basics.Address{}, fmt.Errorf("app %d does not exist", appIdx)
+	}
+	record, err := al.balances.Get(creator, false)
+	if err != nil {
+		return basics.AppParams{}, basics.Address{}, err
+	}
+	params, ok := record.AppParams[appIdx]
+	if !ok {
+		return basics.AppParams{}, basics.Address{}, fmt.Errorf("app %d not found in the account of its creator %s", appIdx, creator.String())
+	}
+	return params, creator, nil
+}
+
+func (al *appLedger) Balance(addr basics.Address) (basics.MicroAlgos, error) {
+	record, err := al.get(addr)
+	if err != nil {
+		return basics.MicroAlgos{}, err
+	}
+	return record.MicroAlgos, nil
+}
+
+func (al *appLedger) Round() basics.Round {
+	return al.round
+}
+
+func (al *appLedger) LatestTimestamp() int64 {
+	return al.timestamp
+}
+
+func (al *appLedger) AppGlobalState(appIdx basics.AppIndex) (basics.TealKeyValue, error) {
+	params, _, err := al.appParams(appIdx)
+	if err != nil {
+		return nil, err
+	}
+	return params.GlobalState, nil
+}
+
+func (al *appLedger) AppLocalState(addr basics.Address, appIdx basics.AppIndex) (basics.TealKeyValue, error) {
+	if appIdx == 0 {
+		appIdx = al.appIdx
+	}
+	record, err := al.get(addr)
+	if err != nil {
+		return nil, err
+	}
+	state, ok := record.AppLocalStates[appIdx]
+	if !ok {
+		return nil, fmt.Errorf("account %s is not opted in to app %d", addr.String(), appIdx)
+	}
+	return state.KeyValue, nil
+}
+
+func (al *appLedger) AssetHolding(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetHolding, error) {
+	record, err := al.get(addr)
+	if err != nil {
+		return basics.AssetHolding{}, err
+	}
+	holding, ok := record.Assets[assetIdx]
+	if !ok {
+		return basics.AssetHolding{}, fmt.Errorf("account %s has not opted in to asset %d", addr.String(), assetIdx)
+	}
+	return holding, nil
+}
+
+func (al *appLedger) AssetParams(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetParams, error) {
+	record, err := al.get(addr)
+	if err != nil {
+		return basics.AssetParams{}, err
+	}
+	params, ok := record.AssetParams[assetIdx]
+	if !ok {
+		return basics.AssetParams{}, fmt.Errorf("account %s has not created asset %d", addr.String(), assetIdx)
+	}
+	return params, nil
+}
+
+func (al *appLedger) AppParams(appIdx basics.AppIndex) (basics.AppParams, basics.Address, error) {
+	return al.appParams(appIdx)
+}
+
+func (al *appLedger) MinBalance(addr basics.Address, proto *config.ConsensusParams) (basics.MicroAlgos, error) {
+	record, err := al.get(addr)
+	if err != nil {
+		return basics.MicroAlgos{}, err
+	}
+	return record.MinBalance(proto), nil
+}
+
+func (al *appLedger) Authorizer(addr basics.Address) (basics.Address, error) {
+	record, err := al.get(addr)
+	if err != nil {
+		return basics.Address{}, err
+	}
+	return record.AuthAddr, nil
+}
+
+func (al *appLedger) ApplicationID() basics.AppIndex {
+	return al.appIdx
+}
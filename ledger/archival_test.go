@@ -18,13 +18,13 @@ package ledger
 
 import (
 	"crypto/rand"
-	"database/sql"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -68,11 +68,11 @@ func (wl *wrappedLedger) Latest() basics.Round {
 	return wl.l.Latest()
 }
 
-func (wl *wrappedLedger) trackerDB() dbPair {
+func (wl *wrappedLedger) trackerDB() trackerStore {
 	return wl.l.trackerDB()
 }
 
-func (wl *wrappedLedger) blockDB() dbPair {
+func (wl *wrappedLedger) blockDB() blockStore {
 	return wl.l.blockDB()
 }
 
@@ -179,11 +179,11 @@ func TestArchivalRestart(t *testing.T) {
 	l.WaitForCommit(blk.Round())
 
 	var latest, earliest basics.Round
-	err = l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
-		latest, err = blockLatest(tx)
+	err = l.blockDBs.read(func(tx blockTx) error {
+		latest, err = tx.latest()
 		require.NoError(t, err)
 
-		earliest, err = blockEarliest(tx)
+		earliest, err = tx.earliest()
 		require.NoError(t, err)
 		return err
 	})
@@ -196,11 +196,11 @@ func TestArchivalRestart(t *testing.T) {
 	require.NoError(t, err)
 	defer l.Close()
 
-	err = l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
-		latest, err = blockLatest(tx)
+	err = l.blockDBs.read(func(tx blockTx) error {
+		latest, err = tx.latest()
 		require.NoError(t, err)
 
-		earliest, err = blockEarliest(tx)
+		earliest, err = tx.earliest()
 		require.NoError(t, err)
 		return err
 	})
@@ -515,12 +515,17 @@ func TestArchivalFromNonArchival(t *testing.T) {
 	}
 	l.WaitForCommit(blk.Round())
 
+	// the blocks are pruned in the background, after the trackers commit
+	for i := 0; i < 500 && l.BlockRetentionStatus().EarliestRound == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
 	var latest, earliest basics.Round
-	err = l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
-		latest, err = blockLatest(tx)
+	err = l.blockDBs.read(func(tx blockTx) error {
+		latest, err = tx.latest()
 		require.NoError(t, err)
 
-		earliest, err = blockEarliest(tx)
+		earliest, err = tx.earliest()
 		require.NoError(t, err)
 		return err
 	})
@@ -536,11 +541,11 @@ func TestArchivalFromNonArchival(t *testing.T) {
 	require.NoError(t, err)
 	defer l.Close()
 
-	err = l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
-		latest, err = blockLatest(tx)
+	err = l.blockDBs.read(func(tx blockTx) error {
+		latest, err = tx.latest()
 		require.NoError(t, err)
 
-		earliest, err = blockEarliest(tx)
+		earliest, err = tx.earliest()
 		require.NoError(t, err)
 		return err
 	})
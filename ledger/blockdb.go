@@ -215,17 +215,43 @@ func blockEarliest(tx *sql.Tx) (basics.Round, error) {
 	return 0, fmt.Errorf("no blocks present")
 }
 
-func blockForgetBefore(tx *sql.Tx, rnd basics.Round) error {
+// blockEarliestPrunable returns the oldest round whose block may be pruned;
+// the blocks of the rounds that are a multiple of keepInterval, if it's
+// non-zero, are never pruned. It returns the earliest round if there's none.
+func blockEarliestPrunable(tx *sql.Tx, keepInterval uint64) (basics.Round, error) {
+	if keepInterval == 0 {
+		return blockEarliest(tx)
+	}
+
+	var min sql.NullInt64
+	err := tx.QueryRow("SELECT MIN(rnd) FROM blocks WHERE rnd%?!=0", keepInterval).Scan(&min)
+	if err != nil {
+		return 0, err
+	}
+
+	if min.Valid {
+		return basics.Round(min.Int64), nil
+	}
+	return blockEarliest(tx)
+}
+
+// blockForgetRange deletes the blocks of the rounds in [start, end), except
+// for the rounds that are a multiple of keepInterval, if it's non-zero.
+func blockForgetRange(tx *sql.Tx, start, end basics.Round, keepInterval uint64) error {
 	next, err := blockNext(tx)
 	if err != nil {
 		return err
 	}
 
-	if rnd >= next {
-		return fmt.Errorf("forgetting too much: rnd %d >= next %d", rnd, next)
+	if end >= next {
+		return fmt.Errorf("forgetting too much: rnd %d >= next %d", end, next)
 	}
 
-	_, err = tx.Exec("DELETE FROM blocks WHERE rnd<?", rnd)
+	if keepInterval == 0 {
+		_, err = tx.Exec("DELETE FROM blocks WHERE rnd>=? AND rnd<?", start, end)
+	} else {
+		_, err = tx.Exec("DELETE FROM blocks WHERE rnd>=? AND rnd<? AND rnd%?!=0", start, end, keepInterval)
+	}
 	return err
 }
 
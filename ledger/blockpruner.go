@@ -0,0 +1,289 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// blockPrunerBatchRounds is the number of rounds whose blocks are deleted in
+// a single transaction.
+const blockPrunerBatchRounds = 1000
+
+// blockPrunerBatchInterval is the pause between two batches, which leaves
+// the blocks database to the blockQueue syncer.
+var blockPrunerBatchInterval = 10 * time.Millisecond
+
+// blockRetentionPolicy describes the blocks a non-archival ledger keeps on
+// top of the ones its trackers need.
+type blockRetentionPolicy struct {
+	// rounds is the number of most recent rounds whose blocks are kept.
+	rounds basics.Round
+	// duration is how long the blocks are kept after their timestamp.
+	duration time.Duration
+	// catchpointInterval, if non-zero, keeps the blocks of the rounds that
+	// are a multiple of it.
+	catchpointInterval uint64
+}
+
+func makeBlockRetentionPolicy(cfg config.Local) blockRetentionPolicy {
+	policy := blockRetentionPolicy{
+		rounds:   basics.Round(cfg.BlockRetentionRounds),
+		duration: time.Duration(cfg.BlockRetentionDays) * 24 * time.Hour,
+	}
+	if cfg.BlockRetentionKeepCatchpoints {
+		policy.catchpointInterval = cfg.CatchpointInterval
+	}
+	return policy
+}
+
+// BlockRetentionStatus describes the blocks held by the ledger and the
+// progress of their pruning.
+type BlockRetentionStatus struct {
+	// EarliestRound is the oldest round from which on the ledger holds all
+	// the blocks. Older catchpoint blocks may be kept as well.
+	EarliestRound basics.Round
+
+	// PruneTarget is the round before which the blocks are being deleted.
+	// It is ahead of EarliestRound while the pruner catches up.
+	PruneTarget basics.Round
+}
+
+// blockPruner deletes the blocks the ledger no longer keeps. It runs in the
+// background and deletes them in small batches, so that trimming a large
+// number of blocks doesn't hold up the writing of the new ones.
+type blockPruner struct {
+	l      *Ledger
+	policy blockRetentionPolicy
+
+	mu deadlock.Mutex
+	// minToSave is the oldest round whose block the trackers need, and
+	// latest is the latest committed round.
+	minToSave basics.Round
+	latest    basics.Round
+	// target is the round before which the blocks are being deleted.
+	target basics.Round
+	// earliest is the oldest round from which on all the blocks are held.
+	earliest basics.Round
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func makeBlockPruner(l *Ledger, policy blockRetentionPolicy) (*blockPruner, error) {
+	p := &blockPruner{
+		l:      l,
+		policy: policy,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	err := l.blockDBs.read(func(tx blockTx) error {
+		var err0 error
+		p.earliest, err0 = tx.earliestPrunable(policy.catchpointInterval)
+		return err0
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.target = p.earliest
+
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	go p.run()
+	return p, nil
+}
+
+func (p *blockPruner) close() {
+	p.cancel()
+	<-p.done
+}
+
+// committed informs the pruner that the blocks up to latest were written, and
+// that the trackers need the blocks from minToSave on. It doesn't block.
+func (p *blockPruner) committed(minToSave, latest basics.Round) {
+	p.mu.Lock()
+	p.minToSave = minToSave
+	p.latest = latest
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// reset re-reads the oldest block after the blocks database was replaced, as
+// by a catchpoint catchup. The pruning waits for the next committed call.
+func (p *blockPruner) reset() error {
+	var earliest basics.Round
+	err := p.l.blockDBs.read(func(tx blockTx) error {
+		var err0 error
+		earliest, err0 = tx.earliestPrunable(p.policy.catchpointInterval)
+		return err0
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.earliest = earliest
+	p.target = earliest
+	p.minToSave = 0
+	p.latest = 0
+	return nil
+}
+
+func (p *blockPruner) status() BlockRetentionStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return BlockRetentionStatus{
+		EarliestRound: p.earliest,
+		PruneTarget:   p.target,
+	}
+}
+
+func (p *blockPruner) run() {
+	defer close(p.done)
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.wake:
+		}
+
+		target, err := p.retentionTarget()
+		if err != nil {
+			p.l.log.Warnf("blockPruner: unable to determine the blocks to keep: %v", err)
+			continue
+		}
+		p.pruneBefore(target)
+	}
+}
+
+// retentionTarget returns the round before which the blocks are not kept,
+// besides the catchpoint blocks.
+func (p *blockPruner) retentionTarget() (basics.Round, error) {
+	p.mu.Lock()
+	target := p.minToSave
+	latest := p.latest
+	earliest := p.earliest
+	p.mu.Unlock()
+
+	// the latest block is always kept.
+	if target > latest {
+		target = latest
+	}
+	if p.policy.rounds > 0 {
+		if latest+1 < p.policy.rounds {
+			target = 0
+		} else if latest+1-p.policy.rounds < target {
+			target = latest + 1 - p.policy.rounds
+		}
+	}
+	if p.policy.duration > 0 && target > earliest {
+		cutoff := time.Now().Add(-p.policy.duration).Unix()
+		rnd, err := p.firstRoundAfter(cutoff, earliest, target)
+		if err != nil {
+			return 0, err
+		}
+		target = rnd
+	}
+
+	p.mu.Lock()
+	if target > p.target {
+		p.target = target
+	}
+	p.mu.Unlock()
+	return target, nil
+}
+
+// firstRoundAfter returns the first round in [lo, hi) whose block has a
+// timestamp no older than cutoff, or hi if there is none. The blocks that are
+// already gone are older than any of the held ones.
+func (p *blockPruner) firstRoundAfter(cutoff int64, lo, hi basics.Round) (rnd basics.Round, err error) {
+	err = p.l.blockDBs.read(func(tx blockTx) error {
+		lo, hi := lo, hi
+		for lo < hi {
+			mid := lo + (hi-lo)/2
+			hdr, err0 := tx.getHdr(mid)
+			if _, ok := err0.(ErrNoEntry); ok || (err0 == nil && hdr.TimeStamp < cutoff) {
+				lo = mid + 1
+				continue
+			}
+			if err0 != nil {
+				return err0
+			}
+			hi = mid
+		}
+		rnd = lo
+		return nil
+	})
+	return
+}
+
+// pruneBefore deletes the blocks older than target in batches, until it
+// reaches target or the pruner is closed.
+func (p *blockPruner) pruneBefore(target basics.Round) {
+	for {
+		p.mu.Lock()
+		start := p.earliest
+		// the target is stale if the pruner was reset meanwhile.
+		stale := target > p.target
+		p.mu.Unlock()
+		if start >= target || stale {
+			return
+		}
+
+		end := start + blockPrunerBatchRounds
+		if end > target {
+			end = target
+		}
+		err := p.l.blockDBs.write(func(tx blockTx) error {
+			return tx.forgetRange(start, end, p.policy.catchpointInterval)
+		})
+		if err != nil {
+			p.l.log.Warnf("blockPruner: blockForgetRange(%d, %d): %v", start, end, err)
+			return
+		}
+
+		p.mu.Lock()
+		if p.earliest == start {
+			p.earliest = end
+		}
+		p.mu.Unlock()
+
+		err = p.l.blockDBs.reclaimSpace()
+		if err != nil {
+			p.l.log.Warnf("blockPruner: unable to reclaim the space of the blocks database: %v", err)
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-time.After(blockPrunerBatchInterval):
+		}
+	}
+}
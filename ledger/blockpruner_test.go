@@ -0,0 +1,221 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// openPrunedLedger opens a non-archival ledger with the given retention
+// settings, and adds blocks up to round maxBlocks to it; timestamp returns the
+// timestamp of each of them.
+func openPrunedLedger(t *testing.T, cfg config.Local, maxBlocks int, timestamp func(basics.Round) int64) (*Ledger, func()) {
+	name := strings.Replace(t.Name(), "/", "_", -1)
+	dbTempDir, err := ioutil.TempDir("", "testdir"+name)
+	require.NoError(t, err)
+	dbName := fmt.Sprintf("%s.%d", name, crypto.RandUint64())
+	dbPrefix := filepath.Join(dbTempDir, dbName)
+
+	genesisInitState := getInitState()
+	cfg.Archival = false
+	l, err := OpenLedger(logging.TestingLog(t), dbPrefix, false, genesisInitState, cfg)
+	require.NoError(t, err)
+
+	blk := genesisInitState.Block
+	for i := 0; i < maxBlocks; i++ {
+		blk.BlockHeader.Round++
+		blk.BlockHeader.TimeStamp = timestamp(blk.BlockHeader.Round)
+		l.AddBlock(blk, agreement.Certificate{})
+	}
+	l.WaitForCommit(blk.Round())
+
+	return l, func() {
+		l.Close()
+		os.RemoveAll(dbTempDir)
+	}
+}
+
+// waitPruned waits for the ledger to prune the blocks before rnd, and returns
+// the rounds of the blocks it holds.
+func waitPruned(t *testing.T, l *Ledger, rnd basics.Round) (rounds []basics.Round) {
+	for i := 0; i < 500 && l.BlockRetentionStatus().EarliestRound < rnd; i++ {
+		// the trackers flush their state in the background, and the pruner
+		// only learns about it on the next commit.
+		latest := l.Latest()
+		l.blockQ.pruner.committed(l.notifyCommit(latest), latest)
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, BlockRetentionStatus{EarliestRound: rnd, PruneTarget: rnd}, l.BlockRetentionStatus())
+
+	err := l.blockDBs.read(func(tx blockTx) error {
+		latest, err := tx.latest()
+		if err != nil {
+			return err
+		}
+		rounds = nil
+		for rnd := basics.Round(0); rnd <= latest; rnd++ {
+			_, err = tx.getHdr(rnd)
+			if _, ok := err.(ErrNoEntry); ok {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			rounds = append(rounds, rnd)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return
+}
+
+func TestBlockPrunerRounds(t *testing.T) {
+	for _, backend := range []string{blockStoreSQLite, blockStoreKV} {
+		t.Run(backend, func(t *testing.T) {
+			cfg := config.GetDefaultLocal()
+			cfg.BlockRetentionRounds = 1500
+			cfg.BlockStorageBackend = backend
+			l, release := openPrunedLedger(t, cfg, 2000, func(basics.Round) int64 { return time.Now().Unix() })
+			defer release()
+
+			rounds := waitPruned(t, l, 501)
+			require.Equal(t, 1500, len(rounds))
+			require.Equal(t, basics.Round(501), rounds[0])
+
+			_, err := l.Block(500)
+			require.IsType(t, ErrNoEntry{}, err)
+			_, err = l.Block(501)
+			require.NoError(t, err)
+
+			if backend == blockStoreSQLite {
+				require.True(t, l.blockDBs.(*sqliteBlockStore).vacuum)
+			}
+		})
+	}
+}
+
+func TestBlockPrunerDays(t *testing.T) {
+	now := time.Now()
+	cfg := config.GetDefaultLocal()
+	cfg.BlockRetentionDays = 1
+	l, release := openPrunedLedger(t, cfg, 2000, func(rnd basics.Round) int64 {
+		if rnd < 300 {
+			return now.Add(-48 * time.Hour).Unix()
+		}
+		return now.Unix()
+	})
+	defer release()
+
+	rounds := waitPruned(t, l, 300)
+	require.Equal(t, basics.Round(300), rounds[0])
+}
+
+func TestBlockPrunerKeepCatchpoints(t *testing.T) {
+	cfg := config.GetDefaultLocal()
+	cfg.CatchpointInterval = 100
+	cfg.BlockRetentionRounds = 1500
+	cfg.BlockRetentionKeepCatchpoints = true
+	l, release := openPrunedLedger(t, cfg, 2000, func(basics.Round) int64 { return time.Now().Unix() })
+	defer release()
+
+	rounds := waitPruned(t, l, 501)
+	require.Equal(t, []basics.Round{0, 100, 200, 300, 400, 500, 501}, rounds[:7])
+	_, err := l.Block(400)
+	require.NoError(t, err)
+
+	// the catchpoint blocks are not pruned again when the ledger is reopened
+	p, err := makeBlockPruner(l, l.blockRetention)
+	require.NoError(t, err)
+	defer p.close()
+	require.Equal(t, basics.Round(501), p.status().EarliestRound)
+}
+
+func TestBlockPrunerCatchpointCatchup(t *testing.T) {
+	cfg := config.GetDefaultLocal()
+	cfg.BlockRetentionRounds = 1500
+	l, release := openPrunedLedger(t, cfg, 2000, func(basics.Round) int64 { return time.Now().Unix() })
+	defer release()
+	waitPruned(t, l, 501)
+
+	// a catchpoint catchup replaces the blocks with the one of its round.
+	blk, err := l.Block(2000)
+	require.NoError(t, err)
+	blk.BlockHeader.Round = 5000
+	catchpointAccessor := MakeCatchpointCatchupAccessor(l, l.log)
+	err = catchpointAccessor.StoreFirstBlock(context.Background(), &blk)
+	require.NoError(t, err)
+	err = catchpointAccessor.FinishBlocks(context.Background(), true)
+	require.NoError(t, err)
+
+	require.Equal(t, BlockRetentionStatus{EarliestRound: 5000, PruneTarget: 5000}, l.BlockRetentionStatus())
+}
+
+func TestBlockPrunerVacuumMigration(t *testing.T) {
+	dbTempDir, err := ioutil.TempDir("", "testdir"+t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dbTempDir)
+	dbPrefix := filepath.Join(dbTempDir, fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64()))
+
+	// the blocks database of an older ledger isn't incrementally vacuumed.
+	_, blockDBFilename, err := ledgerDBFilenames(dbPrefix, false)
+	require.NoError(t, err)
+	acc, err := db.MakeAccessor(blockDBFilename, false, false)
+	require.NoError(t, err)
+	err = acc.Atomic(func(tx *sql.Tx) error {
+		return blockInit(tx, []bookkeeping.Block{getInitState().Block})
+	})
+	require.NoError(t, err)
+	acc.Close()
+
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+	l, err := OpenLedger(logging.TestingLog(t), dbPrefix, false, getInitState(), cfg)
+	require.NoError(t, err)
+	require.False(t, l.blockDBs.(*sqliteBlockStore).vacuum)
+	l.Close()
+
+	// it gets converted once the ledger prunes its blocks.
+	cfg.Archival = false
+	l, err = OpenLedger(logging.TestingLog(t), dbPrefix, false, getInitState(), cfg)
+	require.NoError(t, err)
+	defer l.Close()
+	require.True(t, l.blockDBs.(*sqliteBlockStore).vacuum)
+	var autoVacuum int
+	err = l.blockDBs.(*sqliteBlockStore).dbs.rdb.Atomic(func(tx *sql.Tx) error {
+		return tx.QueryRow("PRAGMA auto_vacuum").Scan(&autoVacuum)
+	})
+	require.NoError(t, err)
+	require.Equal(t, sqliteIncrementalVacuum, autoVacuum)
+}
@@ -17,7 +17,6 @@
 package ledger
 
 import (
-	"database/sql"
 	"fmt"
 	"sync"
 
@@ -36,7 +35,8 @@ type blockEntry struct {
 }
 
 type blockQueue struct {
-	l *Ledger
+	l      *Ledger
+	pruner *blockPruner
 
 	lastCommitted basics.Round
 	q             []blockEntry
@@ -53,15 +53,20 @@ func bqInit(l *Ledger) (*blockQueue, error) {
 	bq.l = l
 	bq.running = true
 	bq.closed = make(chan struct{})
-	err := bq.l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
+	err := bq.l.blockDBs.read(func(tx blockTx) error {
 		var err0 error
-		bq.lastCommitted, err0 = blockLatest(tx)
+		bq.lastCommitted, err0 = tx.latest()
 		return err0
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	bq.pruner, err = makeBlockPruner(l, l.blockRetention)
+	if err != nil {
+		return nil, err
+	}
+
 	go bq.syncer()
 	return bq, nil
 }
@@ -75,6 +80,7 @@ func (bq *blockQueue) close() {
 		// to ensure that the sync goroutine isn't busy in a notifyCommit
 		// call which might be blocked inside one of the trackers.
 		<-bq.closed
+		bq.pruner.close()
 	}()
 
 	if bq.running {
@@ -100,9 +106,9 @@ func (bq *blockQueue) syncer() {
 		workQ := bq.q
 		bq.mu.Unlock()
 
-		err := bq.l.blockDBs.wdb.Atomic(func(tx *sql.Tx) error {
+		err := bq.l.blockDBs.write(func(tx blockTx) error {
 			for _, e := range workQ {
-				err0 := blockPut(tx, e.block, e.cert)
+				err0 := tx.put(e.block, e.cert)
 				if err0 != nil {
 					return err0
 				}
@@ -133,12 +139,7 @@ func (bq *blockQueue) syncer() {
 			bq.mu.Unlock()
 
 			minToSave := bq.l.notifyCommit(committed)
-			err = bq.l.blockDBs.wdb.Atomic(func(tx *sql.Tx) error {
-				return blockForgetBefore(tx, minToSave)
-			})
-			if err != nil {
-				bq.l.log.Warnf("blockQueue.syncer: blockForgetBefore(%d): %v", minToSave, err)
-			}
+			bq.pruner.committed(minToSave, committed)
 
 			bq.mu.Lock()
 		}
@@ -244,9 +245,9 @@ func (bq *blockQueue) getBlock(r basics.Round) (blk bookkeeping.Block, err error
 		return
 	}
 
-	err = bq.l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
+	err = bq.l.blockDBs.read(func(tx blockTx) error {
 		var err0 error
-		blk, err0 = blockGet(tx, r)
+		blk, err0 = tx.get(r)
 		return err0
 	})
 	err = updateErrNoEntry(err, lastCommitted, latest)
@@ -263,9 +264,9 @@ func (bq *blockQueue) getBlockHdr(r basics.Round) (hdr bookkeeping.BlockHeader,
 		return
 	}
 
-	err = bq.l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
+	err = bq.l.blockDBs.read(func(tx blockTx) error {
 		var err0 error
-		hdr, err0 = blockGetHdr(tx, r)
+		hdr, err0 = tx.getHdr(r)
 		return err0
 	})
 	err = updateErrNoEntry(err, lastCommitted, latest)
@@ -286,9 +287,9 @@ func (bq *blockQueue) getEncodedBlockCert(r basics.Round) (blk []byte, cert []by
 		return
 	}
 
-	err = bq.l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
+	err = bq.l.blockDBs.read(func(tx blockTx) error {
 		var err0 error
-		blk, cert, err0 = blockGetEncodedCert(tx, r)
+		blk, cert, err0 = tx.getEncodedCert(r)
 		return err0
 	})
 	err = updateErrNoEntry(err, lastCommitted, latest)
@@ -305,9 +306,9 @@ func (bq *blockQueue) getBlockCert(r basics.Round) (blk bookkeeping.Block, cert
 		return
 	}
 
-	err = bq.l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
+	err = bq.l.blockDBs.read(func(tx blockTx) error {
 		var err0 error
-		blk, cert, err0 = blockGetCert(tx, r)
+		blk, cert, err0 = tx.getCert(r)
 		return err0
 	})
 	err = updateErrNoEntry(err, lastCommitted, latest)
@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+)
+
+// The storage backends of the blocks database, as set by the
+// BlockStorageBackend config parameter.
+const (
+	blockStoreSQLite = "sqlite"
+	blockStoreKV     = "kv"
+)
+
+// blockStore is the blocks database of the ledger. Besides the blocks of the
+// ledger, it holds the ones a catchpoint catchup stages before they replace
+// them.
+type blockStore interface {
+	// read runs fn in a transaction that doesn't write to the database.
+	read(fn func(tx blockTx) error) error
+
+	// write runs fn in a read-write transaction, whose changes are all
+	// committed if fn returns nil, and discarded otherwise. fn may be run
+	// more than once if the transaction has to be retried.
+	write(fn func(tx blockTx) error) error
+
+	// reclaimSpace returns some of the space of the deleted blocks to the
+	// file system.
+	reclaimSpace() error
+
+	// enableReclaimSpace makes reclaimSpace effective on a database that
+	// was created without support for it. It may rewrite the whole database.
+	enableReclaimSpace() error
+
+	setLogger(log logging.Logger)
+	close()
+}
+
+// blockTx is a transaction of a blockStore. Its methods have the semantics
+// of the blockdb.go functions of the same names.
+type blockTx interface {
+	init(initBlocks []bookkeeping.Block) error
+	reset() error
+
+	get(rnd basics.Round) (bookkeeping.Block, error)
+	getHdr(rnd basics.Round) (bookkeeping.BlockHeader, error)
+	getEncodedCert(rnd basics.Round) (blk []byte, cert []byte, err error)
+	getCert(rnd basics.Round) (bookkeeping.Block, agreement.Certificate, error)
+	put(blk bookkeeping.Block, cert agreement.Certificate) error
+
+	next() (basics.Round, error)
+	latest() (basics.Round, error)
+	earliest() (basics.Round, error)
+	earliestPrunable(keepInterval uint64) (basics.Round, error)
+	forgetRange(start, end basics.Round, keepInterval uint64) error
+
+	startCatchupStaging(blk bookkeeping.Block) error
+	putStaging(blk bookkeeping.Block) error
+	completeCatchup() error
+	abortCatchup() error
+	ensureSingleBlock() (bookkeeping.Block, error)
+}
+
+// blockStorePath returns the name of the file, or of the directory for the
+// kv backend, of the blocks database of the ledger stored at dbPathPrefix.
+func blockStorePath(backend string, dbPathPrefix string, dbMem bool) (string, error) {
+	switch backend {
+	case blockStoreSQLite, "":
+		_, blockDBFilename, err := ledgerDBFilenames(dbPathPrefix, dbMem)
+		return blockDBFilename, err
+	case blockStoreKV:
+		return dbPathPrefix + ".block.kv", nil
+	default:
+		return "", fmt.Errorf("unknown block storage backend %q", backend)
+	}
+}
+
+// openBlockStore opens the blocks database of the ledger stored at
+// dbPathPrefix with the given backend. A read-only database must exist.
+//
+// The blocks aren't converted from one backend to the other, so it fails if
+// the ledger already exists with another backend.
+func openBlockStore(backend string, dbPathPrefix string, readOnly bool, dbMem bool) (blockStore, error) {
+	path, err := blockStorePath(backend, dbPathPrefix, dbMem)
+	if err != nil {
+		return nil, err
+	}
+	if !dbMem {
+		err = checkBlockStoreBackend(backend, dbPathPrefix, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if backend == blockStoreKV {
+		return openKVBlockStore(path, readOnly, dbMem)
+	}
+	return openSQLiteBlockStore(path, readOnly, dbMem)
+}
+
+// checkBlockStoreBackend makes sure that the ledger stored at dbPathPrefix
+// doesn't keep its blocks with another backend than the given one.
+func checkBlockStoreBackend(backend string, dbPathPrefix string, path string) error {
+	_, err := os.Stat(path)
+	if err == nil || !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, other := range []string{blockStoreSQLite, blockStoreKV} {
+		if other == backend || (backend == "" && other == blockStoreSQLite) {
+			continue
+		}
+		otherPath, err := blockStorePath(other, dbPathPrefix, false)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stat(otherPath)
+		if err == nil {
+			return fmt.Errorf("the ledger stores its blocks in %s with the %s backend, not the %s one", otherPath, other, backend)
+		}
+	}
+	return nil
+}
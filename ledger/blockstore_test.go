@@ -0,0 +1,402 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var blockStoreBackends = []string{blockStoreSQLite, blockStoreKV}
+
+// testBlockStore runs a conformance test against each backend, with a
+// blocks database of its own in a temporary directory. The test gets the
+// prefix of the database, to reopen it.
+func testBlockStore(t *testing.T, test func(t *testing.T, backend string, dbPrefix string, bs blockStore)) {
+	for _, backend := range blockStoreBackends {
+		t.Run(backend, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "blockstore")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			dbPrefix := filepath.Join(dir, "ledger")
+
+			bs, err := openBlockStore(backend, dbPrefix, false, false)
+			require.NoError(t, err)
+			bs.setLogger(logging.TestingLog(t))
+			defer func() { bs.close() }()
+			test(t, backend, dbPrefix, bs)
+		})
+	}
+}
+
+// storedBlock is what a blockStore returns for a round. The blocks are read
+// in a transaction and checked afterwards, since a failed assertion doesn't
+// end the SQLite transactions it happens in.
+type storedBlock struct {
+	blk     bookkeeping.Block
+	hdr     bookkeeping.BlockHeader
+	certBlk bookkeeping.Block
+	cert    agreement.Certificate
+	blkbuf  []byte
+	certbuf []byte
+	errs    [4]error
+}
+
+func readStoredBlocks(t *testing.T, bs blockStore, rounds []basics.Round) []storedBlock {
+	stored := make([]storedBlock, len(rounds))
+	err := bs.read(func(tx blockTx) error {
+		for i, rnd := range rounds {
+			sb := &stored[i]
+			sb.blk, sb.errs[0] = tx.get(rnd)
+			sb.hdr, sb.errs[1] = tx.getHdr(rnd)
+			sb.certBlk, sb.cert, sb.errs[2] = tx.getCert(rnd)
+			sb.blkbuf, sb.certbuf, sb.errs[3] = tx.getEncodedCert(rnd)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return stored
+}
+
+// checkBlockEntries checks that the blocks are held by a blockStore.
+func checkBlockEntries(t *testing.T, bs blockStore, blocks []blockEntry) {
+	rounds := make([]basics.Round, len(blocks))
+	for i, e := range blocks {
+		rounds[i] = e.block.Round()
+	}
+	for i, sb := range readStoredBlocks(t, bs, rounds) {
+		e := blocks[i]
+		for _, err := range sb.errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, e.block, sb.blk)
+		require.Equal(t, e.block.BlockHeader, sb.hdr)
+		require.Equal(t, e.block, sb.certBlk)
+		require.Equal(t, e.cert, sb.cert)
+		require.Equal(t, protocol.Encode(&e.block), sb.blkbuf)
+		require.Equal(t, protocol.Encode(&e.cert), sb.certbuf)
+	}
+}
+
+// checkBlocksMissing checks that a blockStore doesn't hold the blocks of the
+// rounds.
+func checkBlocksMissing(t *testing.T, bs blockStore, rounds ...basics.Round) {
+	for i, sb := range readStoredBlocks(t, bs, rounds) {
+		for _, err := range sb.errs {
+			require.Equal(t, ErrNoEntry{Round: rounds[i]}, err)
+		}
+	}
+}
+
+// readBlockRange returns the next, latest and earliest rounds of a
+// blockStore.
+func readBlockRange(t *testing.T, bs blockStore) (next, latest, earliest basics.Round, latestErr, earliestErr error) {
+	err := bs.read(func(tx blockTx) (err error) {
+		next, err = tx.next()
+		latest, latestErr = tx.latest()
+		earliest, earliestErr = tx.earliest()
+		return
+	})
+	require.NoError(t, err)
+	return
+}
+
+// checkBlockStore is checkBlockDB for a blockStore holding the blocks from
+// the one of round blocks[0].block.Round() on.
+func checkBlockStore(t *testing.T, bs blockStore, blocks []blockEntry) {
+	next, latest, earliest, latestErr, earliestErr := readBlockRange(t, bs)
+	if len(blocks) == 0 {
+		require.Equal(t, basics.Round(0), next)
+		require.Error(t, latestErr)
+		require.Error(t, earliestErr)
+		return
+	}
+	last := blocks[len(blocks)-1].block.Round()
+	require.Equal(t, last+1, next)
+	require.NoError(t, latestErr)
+	require.Equal(t, last, latest)
+	require.NoError(t, earliestErr)
+	require.Equal(t, blocks[0].block.Round(), earliest)
+	checkBlockEntries(t, bs, blocks)
+	checkBlocksMissing(t, bs, next)
+}
+
+// putBlocks writes blocks in a single transaction.
+func putBlocks(bs blockStore, blocks []blockEntry) error {
+	return bs.write(func(tx blockTx) error {
+		for _, e := range blocks {
+			err := tx.put(e.block, e.cert)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func TestBlockStoreInit(t *testing.T) {
+	testBlockStore(t, func(t *testing.T, backend string, dbPrefix string, bs blockStore) {
+		err := bs.write(func(tx blockTx) error { return tx.init(nil) })
+		require.NoError(t, err)
+		checkBlockStore(t, bs, nil)
+
+		blocks := randomInitChain(protocol.ConsensusCurrentVersion, 10)
+		err = bs.write(func(tx blockTx) error { return tx.init(blockChainBlocks(blocks)) })
+		require.NoError(t, err)
+		checkBlockStore(t, bs, blocks)
+
+		// the blocks are only written to an empty database.
+		err = bs.write(func(tx blockTx) error {
+			return tx.init(blockChainBlocks(randomInitChain(protocol.ConsensusCurrentVersion, 3)))
+		})
+		require.NoError(t, err)
+		checkBlockStore(t, bs, blocks)
+
+		err = bs.write(func(tx blockTx) error { return tx.reset() })
+		require.NoError(t, err)
+		err = bs.write(func(tx blockTx) error { return tx.init(nil) })
+		require.NoError(t, err)
+		checkBlockStore(t, bs, nil)
+	})
+}
+
+func TestBlockStorePut(t *testing.T) {
+	testBlockStore(t, func(t *testing.T, backend string, dbPrefix string, bs blockStore) {
+		blocks := randomInitChain(protocol.ConsensusCurrentVersion, 1)
+		err := bs.write(func(tx blockTx) error { return tx.init(blockChainBlocks(blocks)) })
+		require.NoError(t, err)
+
+		for i := 1; i < 20; i++ {
+			blocks = append(blocks, randomBlock(basics.Round(i)))
+		}
+		require.NoError(t, putBlocks(bs, blocks[1:10]))
+		checkBlockStore(t, bs, blocks[:10])
+
+		// the blocks are written in sequence.
+		require.Error(t, putBlocks(bs, blocks[11:12]))
+		require.Error(t, putBlocks(bs, blocks[9:10]))
+
+		// a failed transaction writes none of its blocks.
+		require.Error(t, putBlocks(bs, append(append([]blockEntry{}, blocks[10:15]...), blocks[16])))
+		checkBlockStore(t, bs, blocks[:10])
+
+		require.NoError(t, putBlocks(bs, blocks[10:]))
+		checkBlockStore(t, bs, blocks)
+
+		// the blocks outlive the database.
+		bs.close()
+		bs, err = openBlockStore(backend, dbPrefix, true, false)
+		require.NoError(t, err)
+		defer bs.close()
+		checkBlockStore(t, bs, blocks)
+		require.Error(t, putBlocks(bs, []blockEntry{randomBlock(basics.Round(len(blocks)))}))
+		checkBlockStore(t, bs, blocks)
+	})
+}
+
+func TestBlockStoreForgetRange(t *testing.T) {
+	testBlockStore(t, func(t *testing.T, backend string, dbPrefix string, bs blockStore) {
+		blocks := randomInitChain(protocol.ConsensusCurrentVersion, 1)
+		err := bs.write(func(tx blockTx) error { return tx.init(blockChainBlocks(blocks)) })
+		require.NoError(t, err)
+		for i := 1; i < 100; i++ {
+			blocks = append(blocks, randomBlock(basics.Round(i)))
+		}
+		require.NoError(t, putBlocks(bs, blocks[1:]))
+
+		earliestPrunable := func(keepInterval uint64) (rnd basics.Round) {
+			err := bs.read(func(tx blockTx) (err error) {
+				rnd, err = tx.earliestPrunable(keepInterval)
+				return
+			})
+			require.NoError(t, err)
+			return
+		}
+		require.Equal(t, basics.Round(0), earliestPrunable(0))
+		require.Equal(t, basics.Round(1), earliestPrunable(10))
+
+		err = bs.write(func(tx blockTx) error { return tx.forgetRange(0, 100, 0) })
+		require.Error(t, err)
+
+		err = bs.write(func(tx blockTx) error { return tx.forgetRange(0, 10, 0) })
+		require.NoError(t, err)
+		checkBlockStore(t, bs, blocks[10:])
+
+		// the blocks of the multiples of the keep interval stay.
+		err = bs.write(func(tx blockTx) error { return tx.forgetRange(10, 45, 20) })
+		require.NoError(t, err)
+		checkBlockEntries(t, bs, []blockEntry{blocks[20], blocks[40]})
+		checkBlockEntries(t, bs, blocks[45:])
+		checkBlocksMissing(t, bs, 9, 10, 19, 30, 44)
+		_, _, earliest, _, err := readBlockRange(t, bs)
+		require.NoError(t, err)
+		require.Equal(t, basics.Round(20), earliest)
+		require.Equal(t, basics.Round(45), earliestPrunable(20))
+		require.Equal(t, basics.Round(20), earliestPrunable(0))
+
+		// forgetting the same blocks again does nothing.
+		err = bs.write(func(tx blockTx) error { return tx.forgetRange(0, 45, 20) })
+		require.NoError(t, err)
+		require.Equal(t, basics.Round(45), earliestPrunable(20))
+
+		err = bs.write(func(tx blockTx) error { return tx.forgetRange(45, 99, 0) })
+		require.NoError(t, err)
+		require.Equal(t, basics.Round(99), earliestPrunable(20))
+		require.NoError(t, bs.reclaimSpace())
+		checkBlockEntries(t, bs, []blockEntry{blocks[20], blocks[40], blocks[99]})
+		checkBlocksMissing(t, bs, 45, 98)
+	})
+}
+
+func TestBlockStoreCatchup(t *testing.T) {
+	testBlockStore(t, func(t *testing.T, backend string, dbPrefix string, bs blockStore) {
+		blocks := randomInitChain(protocol.ConsensusCurrentVersion, 10)
+		err := bs.write(func(tx blockTx) error { return tx.init(blockChainBlocks(blocks)) })
+		require.NoError(t, err)
+
+		var staged []blockEntry
+		for i := 98; i <= 100; i++ {
+			staged = append(staged, randomBlock(basics.Round(i)))
+		}
+		// there is no staged block to begin with.
+		err = bs.write(func(tx blockTx) error {
+			_, err := tx.ensureSingleBlock()
+			return err
+		})
+		require.Error(t, err)
+
+		stage := func() {
+			err := bs.write(func(tx blockTx) error { return tx.startCatchupStaging(staged[2].block) })
+			require.NoError(t, err)
+			err = bs.write(func(tx blockTx) error {
+				for _, e := range staged[:2] {
+					err := tx.putStaging(e.block)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		// the staged blocks don't replace the ones of the ledger until the
+		// catchup completes.
+		stage()
+		checkBlockStore(t, bs, blocks)
+		err = bs.write(func(tx blockTx) error { return tx.abortCatchup() })
+		require.NoError(t, err)
+		err = bs.write(func(tx blockTx) error {
+			_, err := tx.ensureSingleBlock()
+			return err
+		})
+		require.Error(t, err)
+		checkBlockStore(t, bs, blocks)
+
+		stage()
+		var first bookkeeping.Block
+		err = bs.write(func(tx blockTx) (err error) {
+			first, err = tx.ensureSingleBlock()
+			if err != nil {
+				return err
+			}
+			return tx.completeCatchup()
+		})
+		require.NoError(t, err)
+		require.Equal(t, staged[2].block, first)
+
+		// only the latest staged block is kept, without a certificate.
+		stored := readStoredBlocks(t, bs, []basics.Round{100})[0]
+		for _, err := range stored.errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, staged[2].block, stored.blk)
+		require.Equal(t, agreement.Certificate{}, stored.cert)
+		require.Nil(t, stored.certbuf)
+		checkBlocksMissing(t, bs, 9, 98, 99)
+
+		require.NoError(t, putBlocks(bs, []blockEntry{randomBlock(101)}))
+		next, latest, earliest, _, _ := readBlockRange(t, bs)
+		require.Equal(t, basics.Round(102), next)
+		require.Equal(t, basics.Round(101), latest)
+		require.Equal(t, basics.Round(100), earliest)
+	})
+}
+
+func TestBlockStoreBackendMismatch(t *testing.T) {
+	testBlockStore(t, func(t *testing.T, backend string, dbPrefix string, bs blockStore) {
+		for _, other := range blockStoreBackends {
+			if other != backend {
+				_, err := openBlockStore(other, dbPrefix, false, false)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), backend)
+			}
+		}
+		_, err := openBlockStore("lsm", dbPrefix, false, false)
+		require.Error(t, err)
+	})
+}
+
+func TestLedgerBlockStorageBackend(t *testing.T) {
+	for _, backend := range blockStoreBackends {
+		t.Run(backend, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "blockstore")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			dbPrefix := filepath.Join(dir, "ledger")
+
+			genesisInitState := getInitState()
+			cfg := config.GetDefaultLocal()
+			cfg.Archival = true
+			cfg.BlockStorageBackend = backend
+			l, err := OpenLedger(logging.TestingLog(t), dbPrefix, false, genesisInitState, cfg)
+			require.NoError(t, err)
+
+			blk := genesisInitState.Block
+			for i := 0; i < 10; i++ {
+				blk.BlockHeader.Round++
+				require.NoError(t, l.AddBlock(blk, agreement.Certificate{}))
+			}
+			l.WaitForCommit(blk.Round())
+			l.Close()
+
+			l, err = OpenLedger(logging.TestingLog(t), dbPrefix, false, genesisInitState, cfg)
+			require.NoError(t, err)
+			defer l.Close()
+			require.Equal(t, blk.Round(), l.Latest())
+			stored, err := l.Block(blk.Round())
+			require.NoError(t, err)
+			require.Equal(t, blk, stored)
+
+			path, err := blockStorePath(backend, dbPrefix, false)
+			require.NoError(t, err)
+			_, err = os.Stat(path)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -20,7 +20,6 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	"context"
-	"database/sql"
 	"fmt"
 	"hash"
 	"io"
@@ -32,7 +31,6 @@ import (
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/protocol"
-	"github.com/algorand/go-algorand/util/db"
 )
 
 const (
@@ -40,8 +38,18 @@ const (
 	// note that the last chunk would typically be less than this number.
 	BalancesPerCatchpointFileChunk = 512
 
-	// catchpointFileVersion is the catchpoint file version
-	catchpointFileVersion = uint64(0200)
+	// ResourcesPerCatchpointFileChunk defines the number of resources records that would be stored in each chunk in the catchpoint file.
+	// note that the last chunk would typically be less than this number.
+	ResourcesPerCatchpointFileChunk = 4096
+
+	// catchpointFileVersion is the catchpoint file version. The labels of these catchpoints hash the balances trie
+	// holding an entry for each account and one for each of its (address, creatable) resources records.
+	catchpointFileVersion = uint64(0201)
+
+	// catchpointFileVersionFullAccounts is the version of the catchpoint files that hold the asset and application
+	// records of each account within its balance record, rather than in resources chunks. Their labels hash a
+	// balances trie holding a single entry for each whole account. These are still accepted during catchpoint catchup.
+	catchpointFileVersionFullAccounts = uint64(0200)
 )
 
 // catchpointWriter is the struct managing the persistance of accounts data into the catchpoint file.
@@ -51,7 +59,7 @@ const (
 type catchpointWriter struct {
 	hasher            hash.Hash
 	innerWriter       io.WriteCloser
-	dbr               db.Accessor
+	dbr               trackerStore
 	filePath          string
 	file              *os.File
 	gzip              *gzip.Writer
@@ -59,8 +67,11 @@ type catchpointWriter struct {
 	headerWritten     bool
 	balancesOffset    int
 	balancesChunk     catchpointFileBalancesChunk
+	resourcesOffset   int
+	resourcesChunk    catchpointFileResourcesChunk
 	fileHeader        *catchpointFileHeader
 	balancesChunkNum  uint64
+	resourcesChunkNum uint64
 	writtenBytes      int64
 	blocksRound       basics.Round
 	blockHeaderDigest crypto.Digest
@@ -74,6 +85,15 @@ type encodedBalanceRecord struct {
 	AccountData msgp.Raw       `codec:"ad,allocbound=basics.MaxEncodedAccountDataSize"`
 }
 
+type encodedResourceRecord struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Address        basics.Address        `codec:"pk,allocbound=crypto.DigestSize"`
+	CreatableIndex basics.CreatableIndex `codec:"ci"`
+	CreatableType  basics.CreatableType  `codec:"ct"`
+	Data           msgp.Raw              `codec:"rd,allocbound=basics.MaxEncodedAccountDataSize"`
+}
+
 type catchpointFileHeader struct {
 	_struct struct{} `codec:",omitempty,omitemptyarray"`
 
@@ -83,6 +103,8 @@ type catchpointFileHeader struct {
 	Totals            AccountTotals `codec:"accountTotals"`
 	TotalAccounts     uint64        `codec:"accountsCount"`
 	TotalChunks       uint64        `codec:"chunksCount"`
+	TotalResources    uint64        `codec:"resourcesCount"`
+	TotalResChunks    uint64        `codec:"resourceChunksCount"`
 	Catchpoint        string        `codec:"catchpoint"`
 	BlockHeaderDigest crypto.Digest `codec:"blockHeaderDigest"`
 }
@@ -92,7 +114,12 @@ type catchpointFileBalancesChunk struct {
 	Balances []encodedBalanceRecord `codec:"bl,allocbound=BalancesPerCatchpointFileChunk"`
 }
 
-func makeCatchpointWriter(filePath string, dbr db.Accessor, blocksRound basics.Round, blockHeaderDigest crypto.Digest, label string) *catchpointWriter {
+type catchpointFileResourcesChunk struct {
+	_struct   struct{}                `codec:",omitempty,omitemptyarray"`
+	Resources []encodedResourceRecord `codec:"rs,allocbound=ResourcesPerCatchpointFileChunk"`
+}
+
+func makeCatchpointWriter(filePath string, dbr trackerStore, blocksRound basics.Round, blockHeaderDigest crypto.Digest, label string) *catchpointWriter {
 	return &catchpointWriter{
 		filePath:          filePath,
 		dbr:               dbr,
@@ -136,7 +163,7 @@ func (cw *catchpointWriter) WriteStep(ctx context.Context) (more bool, err error
 	}
 
 	if cw.fileHeader == nil {
-		err = cw.dbr.Atomic(cw.readHeaderFromDatabase)
+		err = cw.dbr.read(cw.readHeaderFromDatabase)
 		if err != nil {
 			return
 		}
@@ -170,8 +197,8 @@ func (cw *catchpointWriter) WriteStep(ctx context.Context) (more bool, err error
 			return
 		}
 
-		if len(cw.balancesChunk.Balances) == 0 {
-			err = cw.dbr.Atomic(cw.readDatabaseStep)
+		if len(cw.balancesChunk.Balances) == 0 && len(cw.resourcesChunk.Resources) == 0 {
+			err = cw.dbr.read(cw.readDatabaseStep)
 			if err != nil {
 				return
 			}
@@ -182,66 +209,98 @@ func (cw *catchpointWriter) WriteStep(ctx context.Context) (more bool, err error
 			return
 		}
 
-		// write to disk.
+		// write to disk; the balances chunks are followed by the resources chunks.
 		if len(cw.balancesChunk.Balances) > 0 {
 			cw.balancesChunkNum++
-			encodedChunk := protocol.Encode(&cw.balancesChunk)
-			err = cw.tar.WriteHeader(&tar.Header{
-				Name: fmt.Sprintf("balances.%d.%d.msgpack", cw.balancesChunkNum, cw.fileHeader.TotalChunks),
-				Mode: 0600,
-				Size: int64(len(encodedChunk)),
-			})
-
+			err = cw.writeChunk(fmt.Sprintf("balances.%d.%d.msgpack", cw.balancesChunkNum, cw.fileHeader.TotalChunks), protocol.Encode(&cw.balancesChunk))
 			if err != nil {
 				return
 			}
-			_, err = cw.tar.Write(encodedChunk)
+			cw.balancesChunk.Balances = nil
+			continue
+		}
+		if len(cw.resourcesChunk.Resources) > 0 {
+			cw.resourcesChunkNum++
+			err = cw.writeChunk(fmt.Sprintf("resources.%d.%d.msgpack", cw.resourcesChunkNum, cw.fileHeader.TotalResChunks), protocol.Encode(&cw.resourcesChunk))
 			if err != nil {
 				return
 			}
+			cw.resourcesChunk.Resources = nil
+			continue
+		}
 
-			if len(cw.balancesChunk.Balances) < BalancesPerCatchpointFileChunk || cw.balancesChunkNum == cw.fileHeader.TotalChunks {
-				cw.tar.Close()
-				cw.gzip.Close()
-				cw.file.Close()
-				cw.balancesChunk.Balances = nil
-				cw.file = nil
-				var fileInfo os.FileInfo
-				fileInfo, err = os.Stat(cw.filePath)
-				if err != nil {
-					return false, err
-				}
-				cw.writtenBytes = fileInfo.Size()
-				return false, nil
-			}
-			cw.balancesChunk.Balances = nil
+		// nothing left to write.
+		cw.tar.Close()
+		cw.gzip.Close()
+		cw.file.Close()
+		cw.file = nil
+		var fileInfo os.FileInfo
+		fileInfo, err = os.Stat(cw.filePath)
+		if err != nil {
+			return false, err
 		}
+		cw.writtenBytes = fileInfo.Size()
+		return false, nil
 	}
 }
 
-func (cw *catchpointWriter) readDatabaseStep(tx *sql.Tx) (err error) {
-	cw.balancesChunk.Balances, err = encodedAccountsRange(tx, cw.balancesOffset, BalancesPerCatchpointFileChunk)
-	if err == nil {
-		cw.balancesOffset += BalancesPerCatchpointFileChunk
+// writeChunk writes a single named chunk into the catchpoint file
+func (cw *catchpointWriter) writeChunk(name string, encodedChunk []byte) error {
+	err := cw.tar.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0600,
+		Size: int64(len(encodedChunk)),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = cw.tar.Write(encodedChunk)
+	return err
+}
+
+// readDatabaseStep reads the next chunk of records to be written; once all the balances chunks were read, it reads the
+// resources chunks. Nothing is read after the last resources chunk.
+func (cw *catchpointWriter) readDatabaseStep(tx trackerTx) (err error) {
+	if cw.balancesChunkNum < cw.fileHeader.TotalChunks {
+		cw.balancesChunk.Balances, err = tx.encodedAccountsRange(false, cw.balancesOffset, BalancesPerCatchpointFileChunk)
+		if err == nil {
+			cw.balancesOffset += BalancesPerCatchpointFileChunk
+		}
+		if err != nil || len(cw.balancesChunk.Balances) > 0 {
+			return
+		}
+		// the accounts were fewer than expected; carry on with the resources.
+		cw.balancesChunkNum = cw.fileHeader.TotalChunks
+	}
+	if cw.resourcesChunkNum < cw.fileHeader.TotalResChunks {
+		cw.resourcesChunk.Resources, err = tx.encodedResourcesRange(false, cw.resourcesOffset, ResourcesPerCatchpointFileChunk)
+		if err == nil {
+			cw.resourcesOffset += ResourcesPerCatchpointFileChunk
+		}
 	}
 	return
 }
 
-func (cw *catchpointWriter) readHeaderFromDatabase(tx *sql.Tx) (err error) {
+func (cw *catchpointWriter) readHeaderFromDatabase(tx trackerTx) (err error) {
 	var header catchpointFileHeader
-	header.BalancesRound, _, err = accountsRound(tx)
+	header.BalancesRound, _, err = tx.accountsRound()
 	if err != nil {
 		return
 	}
-	header.Totals, err = accountsTotals(tx, false)
+	header.Totals, err = tx.accountsTotals(false)
 	if err != nil {
 		return
 	}
-	header.TotalAccounts, err = totalAccounts(context.Background(), tx)
+	header.TotalAccounts, err = tx.totalAccounts(context.Background())
 	if err != nil {
 		return
 	}
 	header.TotalChunks = (header.TotalAccounts + BalancesPerCatchpointFileChunk - 1) / BalancesPerCatchpointFileChunk
+	header.TotalResources, err = tx.totalResources(context.Background())
+	if err != nil {
+		return
+	}
+	header.TotalResChunks = (header.TotalResources + ResourcesPerCatchpointFileChunk - 1) / ResourcesPerCatchpointFileChunk
 	header.BlocksRound = cw.blocksRound
 	header.Catchpoint = cw.label
 	header.Version = catchpointFileVersion
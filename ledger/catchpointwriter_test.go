@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -32,7 +33,9 @@ import (
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
 	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
 )
 
@@ -153,7 +156,7 @@ func TestBasicCatchpointWriter(t *testing.T) {
 	blocksRound := basics.Round(12345)
 	blockHeaderDigest := crypto.Hash([]byte{1, 2, 3})
 	catchpointLabel := fmt.Sprintf("%d#%v", blocksRound, blockHeaderDigest) // this is not a correct way to create a label, but it's good enough for this unit test
-	writer := makeCatchpointWriter(fileName, ml.trackerDB().rdb, blocksRound, blockHeaderDigest, catchpointLabel)
+	writer := makeCatchpointWriter(fileName, ml.trackerDB(), blocksRound, blockHeaderDigest, catchpointLabel)
 	for {
 		more, err := writer.WriteStep(context.Background())
 		require.NoError(t, err)
@@ -212,3 +215,102 @@ func TestBasicCatchpointWriter(t *testing.T) {
 		}
 	}
 }
+
+func TestCatchpointFileVersionsStaging(t *testing.T) {
+	genesisInitState, _, _ := genesis(10)
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	const inMem = true
+	l, err := OpenLedger(logging.Base(), dbName, inMem, genesisInitState, config.GetDefaultLocal())
+	require.NoError(t, err)
+	defer l.Close()
+
+	// the accounts hold assets and applications; older catchpoint files keep these within their balance records,
+	// while the current ones have them in resources chunks.
+	accts := randomAccounts(20)
+	var fullBalances, baseBalances catchpointFileBalancesChunk
+	var resources catchpointFileResourcesChunk
+	// the labels of older catchpoint files hash a trie of the whole accounts, while the current ones hash a trie of
+	// the accounts and each of their resources records.
+	fullTrie, err := merkletrie.MakeTrie(&merkletrie.InMemoryCommitter{}, trieCachedNodesCount)
+	require.NoError(t, err)
+	recordsTrie, err := merkletrie.MakeTrie(&merkletrie.InMemoryCommitter{}, trieCachedNodesCount)
+	require.NoError(t, err)
+	i := 0
+	for addr, data := range accts {
+		data.Assets = map[basics.AssetIndex]basics.AssetHolding{basics.AssetIndex(i + 1): {Amount: 1}}
+		data.AppParams = map[basics.AppIndex]basics.AppParams{basics.AppIndex(i + 100): {ApprovalProgram: []byte{1}}}
+		i++
+
+		encoded := protocol.Encode(&data)
+		fullBalances.Balances = append(fullBalances.Balances, encodedBalanceRecord{Address: addr, AccountData: encoded})
+		_, err = fullTrie.Add(accountHashBuilder(addr, data, encoded))
+		require.NoError(t, err)
+
+		base, accountResources := splitAccountData(data)
+		encodedBase := protocol.Encode(&base)
+		baseBalances.Balances = append(baseBalances.Balances, encodedBalanceRecord{Address: addr, AccountData: encodedBase})
+		_, err = recordsTrie.Add(accountHashBuilder(addr, base, encodedBase))
+		require.NoError(t, err)
+		for key, rdata := range accountResources {
+			encodedResource := protocol.Encode(&rdata)
+			resources.Resources = append(resources.Resources, encodedResourceRecord{Address: addr, CreatableIndex: key.cidx, CreatableType: key.ctype, Data: encodedResource})
+			_, err = recordsTrie.Add(resourceHashBuilder(addr, key.cidx, key.ctype, encodedResource))
+			require.NoError(t, err)
+		}
+	}
+	fullRoot, err := fullTrie.RootHash()
+	require.NoError(t, err)
+	recordsRoot, err := recordsTrie.RootHash()
+	require.NoError(t, err)
+	require.NotEqual(t, fullRoot, recordsRoot)
+
+	ctx := context.Background()
+	accessor := MakeCatchpointCatchupAccessor(l, logging.Base()).(*CatchpointCatchupAccessorImpl)
+	stage := func(header catchpointFileHeader, chunks map[string][]byte, expectedRoot crypto.Digest) {
+		require.NoError(t, accessor.ResetStagingBalances(ctx, true))
+		var progress CatchpointCatchupAccessorProgress
+		require.NoError(t, accessor.ProgressStagingBalances(ctx, "content.msgpack", protocol.Encode(&header), &progress))
+		for _, name := range []string{"balances.1.1.msgpack", "resources.1.1.msgpack"} {
+			if chunk, ok := chunks[name]; ok {
+				require.NoError(t, accessor.ProgressStagingBalances(ctx, name, chunk, &progress))
+			}
+		}
+		require.Equal(t, uint64(len(accts)), progress.ProcessedAccounts)
+		require.NoError(t, accessor.buildMerkleTrie(ctx))
+
+		var root crypto.Digest
+		var resourcesCount, creatablesCount int
+		rdb := l.trackerDB().(*sqliteTrackerStore).dbs.rdb
+		err := rdb.Atomic(func(tx *sql.Tx) error {
+			mc, err := makeMerkleCommitter(tx, true)
+			if err != nil {
+				return err
+			}
+			trie, err := merkletrie.MakeTrie(mc, trieCachedNodesCount)
+			if err != nil {
+				return err
+			}
+			root, err = trie.RootHash()
+			if err != nil {
+				return err
+			}
+			err = tx.QueryRow("SELECT count(*) FROM catchpointresources").Scan(&resourcesCount)
+			if err != nil {
+				return err
+			}
+			return tx.QueryRow("SELECT count(*) FROM catchpointassetcreators").Scan(&creatablesCount)
+		})
+		require.NoError(t, err)
+		require.Equal(t, expectedRoot, root)
+		require.Equal(t, 2*len(accts), resourcesCount)
+		require.Equal(t, len(accts), creatablesCount)
+	}
+
+	stage(catchpointFileHeader{Version: catchpointFileVersionFullAccounts, TotalAccounts: uint64(len(accts)), TotalChunks: 1},
+		map[string][]byte{"balances.1.1.msgpack": protocol.Encode(&fullBalances)}, fullRoot)
+	stage(catchpointFileHeader{Version: catchpointFileVersion, TotalAccounts: uint64(len(accts)), TotalChunks: 1, TotalResources: uint64(len(resources.Resources)), TotalResChunks: 1},
+		map[string][]byte{"balances.1.1.msgpack": protocol.Encode(&baseBalances), "resources.1.1.msgpack": protocol.Encode(&resources)}, recordsRoot)
+
+	// building the trie again starts over from an empty one
+	require.NoError(t, accessor.buildMerkleTrie(ctx))
+}
@@ -18,7 +18,6 @@ package ledger
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"strings"
 
@@ -85,8 +84,8 @@ type CatchpointCatchupAccessorImpl struct {
 	// log copied from ledger
 	log logging.Logger
 
-	// Prepared SQL statements for fast accounts DB lookups.
-	accountsq *accountsDbQueries
+	// Fast accounts DB lookups.
+	accountsq trackerQueries
 }
 
 // CatchpointCatchupState is the state of the current catchpoint catchup process
@@ -110,9 +109,7 @@ const (
 
 // MakeCatchpointCatchupAccessor creates a CatchpointCatchupAccessor given a ledger
 func MakeCatchpointCatchupAccessor(ledger *Ledger, log logging.Logger) CatchpointCatchupAccessor {
-	rdb := ledger.trackerDB().rdb
-	wdb := ledger.trackerDB().wdb
-	accountsq, err := accountsDbInit(rdb.Handle, wdb.Handle)
+	accountsq, err := ledger.trackerDB().queries()
 	if err != nil {
 		log.Warnf("unable to initialize account db in MakeCatchpointCatchupAccessor : %v", err)
 		return nil
@@ -172,16 +169,16 @@ func (c *CatchpointCatchupAccessorImpl) SetLabel(ctx context.Context, label stri
 
 // ResetStagingBalances resets the current staging balances, preparing for a new set of balances to be added
 func (c *CatchpointCatchupAccessorImpl) ResetStagingBalances(ctx context.Context, newCatchup bool) (err error) {
-	wdb := c.ledger.trackerDB().wdb
-	err = wdb.Atomic(func(tx *sql.Tx) (err error) {
-		err = resetCatchpointStagingBalances(ctx, tx, newCatchup)
+	trackerDBs := c.ledger.trackerDB()
+	err = trackerDBs.write(func(tx trackerTx) (err error) {
+		err = tx.resetCatchpointStagingBalances(ctx, newCatchup)
 		if err != nil {
 			return fmt.Errorf("unable to reset catchpoint catchup balances : %v", err)
 		}
 		if !newCatchup {
-			sq, err := accountsDbInit(tx, tx)
+			sq, err := tx.queries()
 			if err != nil {
-				return fmt.Errorf("unable to initialize accountsDbInit: %v", err)
+				return fmt.Errorf("unable to initialize the accounts queries: %v", err)
 			}
 			_, err = sq.writeCatchpointStateUint64(ctx, catchpointStateCatchupBalancesRound, 0)
 			if err != nil {
@@ -197,6 +194,10 @@ func (c *CatchpointCatchupAccessorImpl) ResetStagingBalances(ctx context.Context
 			if err != nil {
 				return err
 			}
+			_, err = sq.writeCatchpointStateUint64(ctx, catchpointStateCatchupVersion, 0)
+			if err != nil {
+				return err
+			}
 			_, err = sq.writeCatchpointStateUint64(ctx, catchpointStateCatchupState, 0)
 			if err != nil {
 				return fmt.Errorf("unable to write catchpoint catchup state '%s': %v", catchpointStateCatchupState, err)
@@ -209,11 +210,13 @@ func (c *CatchpointCatchupAccessorImpl) ResetStagingBalances(ctx context.Context
 
 // CatchpointCatchupAccessorProgress is used by the caller of ProgressStagingBalances to obtain progress information
 type CatchpointCatchupAccessorProgress struct {
-	TotalAccounts     uint64
-	ProcessedAccounts uint64
-	ProcessedBytes    uint64
-	TotalChunks       uint64
-	SeenHeader        bool
+	TotalAccounts      uint64
+	ProcessedAccounts  uint64
+	TotalResources     uint64
+	ProcessedResources uint64
+	ProcessedBytes     uint64
+	TotalChunks        uint64
+	SeenHeader         bool
 }
 
 // ProgressStagingBalances deserialize the given bytes as a temporary staging balances
@@ -224,6 +227,9 @@ func (c *CatchpointCatchupAccessorImpl) ProgressStagingBalances(ctx context.Cont
 	if strings.HasPrefix(sectionName, "balances.") && strings.HasSuffix(sectionName, ".msgpack") {
 		return c.processStagingBalances(ctx, bytes, progress)
 	}
+	if strings.HasPrefix(sectionName, "resources.") && strings.HasSuffix(sectionName, ".msgpack") {
+		return c.processStagingResources(ctx, bytes, progress)
+	}
 	// we want to allow undefined sections to support backward compatibility.
 	c.log.Warnf("CatchpointCatchupAccessorImpl::ProgressStagingBalances encountered unexpected section name '%s' of length %d, which would be ignored", sectionName, len(bytes))
 	return nil
@@ -239,30 +245,35 @@ func (c *CatchpointCatchupAccessorImpl) processStagingContent(ctx context.Contex
 	if err != nil {
 		return err
 	}
-	if fileHeader.Version != catchpointFileVersion {
+	if fileHeader.Version != catchpointFileVersion && fileHeader.Version != catchpointFileVersionFullAccounts {
 		return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingContent: unable to process catchpoint - version %d is not supported", fileHeader.Version)
 	}
 
 	// the following fields are now going to be ignored. We could add these to the database and validate these
 	// later on:
 	// TotalAccounts, TotalAccounts, Catchpoint, BlockHeaderDigest, BalancesRound
-	wdb := c.ledger.trackerDB().wdb
-	err = wdb.Atomic(func(tx *sql.Tx) (err error) {
-		sq, err := accountsDbInit(tx, tx)
+	trackerDBs := c.ledger.trackerDB()
+	err = trackerDBs.write(func(tx trackerTx) (err error) {
+		sq, err := tx.queries()
 		if err != nil {
-			return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingContent: unable to initialize accountsDbInit: %v", err)
+			return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingContent: unable to initialize the accounts queries: %v", err)
 		}
 		_, err = sq.writeCatchpointStateUint64(ctx, catchpointStateCatchupBlockRound, uint64(fileHeader.BlocksRound))
 		if err != nil {
 			return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingContent: unable to write catchpoint catchup state '%s': %v", catchpointStateCatchupBlockRound, err)
 		}
-		err = accountsPutTotals(tx, fileHeader.Totals, true)
+		_, err = sq.writeCatchpointStateUint64(ctx, catchpointStateCatchupVersion, fileHeader.Version)
+		if err != nil {
+			return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingContent: unable to write catchpoint catchup state '%s': %v", catchpointStateCatchupVersion, err)
+		}
+		err = tx.accountsPutTotals(fileHeader.Totals, true)
 		return
 	})
 	if err == nil {
 		progress.SeenHeader = true
 		progress.TotalAccounts = fileHeader.TotalAccounts
-		progress.TotalChunks = fileHeader.TotalChunks
+		progress.TotalResources = fileHeader.TotalResources
+		progress.TotalChunks = fileHeader.TotalChunks + fileHeader.TotalResChunks
 	}
 	return err
 }
@@ -283,60 +294,212 @@ func (c *CatchpointCatchupAccessorImpl) processStagingBalances(ctx context.Conte
 		return fmt.Errorf("processStagingBalances received a chunk with no accounts")
 	}
 
-	wdb := c.ledger.trackerDB().wdb
-	err = wdb.Atomic(func(tx *sql.Tx) (err error) {
-		// create the merkle trie for the balances
-		mc, err0 := makeMerkleCommitter(tx, true)
-		if err0 != nil {
-			return err0
-		}
-		trie, err := merkletrie.MakeTrie(mc, trieCachedNodesCount)
+	// the balance records of older catchpoint files hold the resources of the accounts too; these are staged
+	// separately, as they would have been found in the resources chunks.
+	bases := make([]encodedBalanceRecord, len(balances.Balances))
+	var resources []encodedResourceRecord
+	for i, balance := range balances.Balances {
+		var accountData basics.AccountData
+		err = protocol.Decode(balance.AccountData, &accountData)
 		if err != nil {
 			return err
 		}
+		base, accountResources := splitAccountData(accountData)
+		bases[i] = balance
+		if len(accountResources) == 0 {
+			continue
+		}
+		bases[i].AccountData = protocol.Encode(&base)
+		for key, rdata := range accountResources {
+			resources = append(resources, encodedResourceRecord{
+				Address:        balance.Address,
+				CreatableIndex: key.cidx,
+				CreatableType:  key.ctype,
+				Data:           protocol.Encode(&rdata),
+			})
+		}
+	}
 
-		err = writeCatchpointStagingBalances(ctx, tx, balances.Balances)
+	trackerDBs := c.ledger.trackerDB()
+	err = trackerDBs.write(func(tx trackerTx) (err error) {
+		err = tx.writeCatchpointStagingBalances(ctx, bases)
 		if err != nil {
 			return
 		}
+		if len(resources) == 0 {
+			return
+		}
+		return c.writeStagingResources(ctx, tx, resources)
+	})
+	if err == nil {
+		progress.ProcessedAccounts += uint64(len(balances.Balances))
+		progress.ProcessedBytes += uint64(len(bytes))
+	}
+	return err
+}
+
+// processStagingResources deserialize the given bytes as temporary staging resources records
+func (c *CatchpointCatchupAccessorImpl) processStagingResources(ctx context.Context, bytes []byte, progress *CatchpointCatchupAccessorProgress) (err error) {
+	if !progress.SeenHeader {
+		return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingResources: content chunk was missing")
+	}
 
-		for _, balance := range balances.Balances {
-			var accountData basics.AccountData
-			err = protocol.Decode(balance.AccountData, &accountData)
+	var chunk catchpointFileResourcesChunk
+	err = protocol.Decode(bytes, &chunk)
+	if err != nil {
+		return err
+	}
+
+	if len(chunk.Resources) == 0 {
+		return fmt.Errorf("processStagingResources received a chunk with no resources")
+	}
+
+	trackerDBs := c.ledger.trackerDB()
+	err = trackerDBs.write(func(tx trackerTx) (err error) {
+		return c.writeStagingResources(ctx, tx, chunk.Resources)
+	})
+	if err == nil {
+		progress.ProcessedResources += uint64(len(chunk.Resources))
+		progress.ProcessedBytes += uint64(len(bytes))
+	}
+	return err
+}
+
+// writeStagingResources writes the given resources records to the staging tables, along with the creators of the
+// assets and applications they hold the params of.
+func (c *CatchpointCatchupAccessorImpl) writeStagingResources(ctx context.Context, tx trackerTx, resources []encodedResourceRecord) (err error) {
+	err = tx.writeCatchpointStagingResources(ctx, resources)
+	if err != nil {
+		return
+	}
+
+	for _, resource := range resources {
+		var resourceData basics.AccountData
+		err = protocol.Decode(resource.Data, &resourceData)
+		if err != nil {
+			return err
+		}
+
+		// resources holding asset or app params mark the creator of that asset or application.
+		if len(resourceData.AssetParams) > 0 || len(resourceData.AppParams) > 0 {
+			err = tx.writeCatchpointStagingCreatable(ctx, resource.Address, resource.CreatableIndex, resource.CreatableType)
 			if err != nil {
 				return err
 			}
+		}
+	}
+	return nil
+}
+
+// buildMerkleTrie adds the staged accounts to the staging balances trie. Since the catchpoint files predating the
+// resources records hash each account merged with all of its resources, the trie can only be built once all the
+// chunks of the catchpoint file were staged. Newer files hash each account and each of its resources records
+// separately, the way the balances trie of the ledger does.
+func (c *CatchpointCatchupAccessorImpl) buildMerkleTrie(ctx context.Context) (err error) {
+	version, _, err := c.accountsq.readCatchpointStateUint64(ctx, catchpointStateCatchupVersion)
+	if err != nil {
+		return fmt.Errorf("unable to read catchpoint catchup state '%s': %v", catchpointStateCatchupVersion, err)
+	}
 
-			// if the account has any asset params, it means that it's the creator of an asset.
-			if len(accountData.AssetParams) > 0 {
-				for aidx := range accountData.AssetParams {
-					err = writeCatchpointStagingAssets(ctx, tx, balance.Address, aidx)
-					if err != nil {
-						return err
-					}
-				}
+	trackerDBs := c.ledger.trackerDB()
+	// start over from an empty trie, in case it was partially built already.
+	err = trackerDBs.write(func(tx trackerTx) (err error) {
+		return tx.resetCatchpointStagingHashes(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	if version == catchpointFileVersionFullAccounts {
+		return c.addStagingHashes(ctx, func(tx trackerTx, trie *merkletrie.Trie, offset int) (int, error) {
+			bals, err := tx.encodedFullAccountsRange(true, offset, trieRebuildAccountChunkSize)
+			if err != nil {
+				return 0, err
 			}
+			return len(bals), addStagingAccountHashes(trie, bals)
+		})
+	}
 
-			hash := accountHashBuilder(balance.Address, accountData, balance.AccountData)
-			added, err := trie.Add(hash)
+	err = c.addStagingHashes(ctx, func(tx trackerTx, trie *merkletrie.Trie, offset int) (int, error) {
+		bals, err := tx.encodedAccountsRange(true, offset, trieRebuildAccountChunkSize)
+		if err != nil {
+			return 0, err
+		}
+		return len(bals), addStagingAccountHashes(trie, bals)
+	})
+	if err != nil {
+		return err
+	}
+	return c.addStagingHashes(ctx, func(tx trackerTx, trie *merkletrie.Trie, offset int) (int, error) {
+		resources, err := tx.encodedResourcesRange(true, offset, trieRebuildAccountChunkSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, resource := range resources {
+			added, err := trie.Add(resourceHashBuilder(resource.Address, resource.CreatableIndex, resource.CreatableType, resource.Data))
+			if err != nil {
+				return 0, err
+			}
 			if !added {
-				return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingBalances: The provided catchpoint file contained the same account more than once. Account address %#v, account data %#v", balance.Address, accountData)
+				return 0, fmt.Errorf("CatchpointCatchupAccessorImpl::buildMerkleTrie: The provided catchpoint file contained the same resource more than once. Account address %#v, creatable %d", resource.Address, resource.CreatableIndex)
 			}
+		}
+		return len(resources), nil
+	})
+}
+
+// addStagingHashes calls addChunk with the staging balances trie for consecutive chunks of staged records, each in its
+// own transaction, until addChunk reports a partial chunk.
+func (c *CatchpointCatchupAccessorImpl) addStagingHashes(ctx context.Context, addChunk func(tx trackerTx, trie *merkletrie.Trie, offset int) (int, error)) (err error) {
+	trackerDBs := c.ledger.trackerDB()
+	for offset := 0; ; offset += trieRebuildAccountChunkSize {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var count int
+		err = trackerDBs.write(func(tx trackerTx) (err error) {
+			mc, err0 := tx.merkleCommitter(true)
+			if err0 != nil {
+				return err0
+			}
+			trie, err := merkletrie.MakeTrie(mc, trieCachedNodesCount)
 			if err != nil {
 				return err
 			}
+			count, err = addChunk(tx, trie, offset)
+			if err != nil {
+				return err
+			}
+			return trie.Commit()
+		})
+		if err != nil {
+			return err
+		}
+		if count < trieRebuildAccountChunkSize {
+			return nil
 		}
-		err = trie.Commit()
+	}
+}
+
+// addStagingAccountHashes adds the hashes of the given staged accounts to the staging balances trie.
+func addStagingAccountHashes(trie *merkletrie.Trie, bals []encodedBalanceRecord) error {
+	for _, balance := range bals {
+		var accountData basics.AccountData
+		err := protocol.Decode(balance.AccountData, &accountData)
 		if err != nil {
-			return
+			return err
+		}
+
+		hash := accountHashBuilder(balance.Address, accountData, balance.AccountData)
+		added, err := trie.Add(hash)
+		if err != nil {
+			return err
+		}
+		if !added {
+			return fmt.Errorf("CatchpointCatchupAccessorImpl::buildMerkleTrie: The provided catchpoint file contained the same account more than once. Account address %#v, account data %#v", balance.Address, accountData)
 		}
-		return
-	})
-	if err == nil {
-		progress.ProcessedAccounts += uint64(len(balances.Balances))
-		progress.ProcessedBytes += uint64(len(bytes))
 	}
-	return err
+	return nil
 }
 
 // GetCatchupBlockRound returns the latest block round matching the current catchpoint
@@ -351,7 +514,7 @@ func (c *CatchpointCatchupAccessorImpl) GetCatchupBlockRound(ctx context.Context
 
 // VerifyCatchpoint verifies that the catchpoint is valid by reconstructing the label.
 func (c *CatchpointCatchupAccessorImpl) VerifyCatchpoint(ctx context.Context, blk *bookkeeping.Block) (err error) {
-	rdb := c.ledger.trackerDB().rdb
+	trackerDBs := c.ledger.trackerDB()
 	var balancesHash crypto.Digest
 	var blockRound basics.Round
 	var totals AccountTotals
@@ -369,9 +532,14 @@ func (c *CatchpointCatchupAccessorImpl) VerifyCatchpoint(ctx context.Context, bl
 	}
 	blockRound = basics.Round(iRound)
 
-	err = rdb.Atomic(func(tx *sql.Tx) (err error) {
+	err = c.buildMerkleTrie(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to build the balances trie: %v", err)
+	}
+
+	err = trackerDBs.read(func(tx trackerTx) (err error) {
 		// create the merkle trie for the balances
-		mc, err0 := makeMerkleCommitter(tx, true)
+		mc, err0 := tx.merkleCommitter(true)
 		if err0 != nil {
 			return fmt.Errorf("unable to make MerkleCommitter: %v", err0)
 		}
@@ -386,7 +554,7 @@ func (c *CatchpointCatchupAccessorImpl) VerifyCatchpoint(ctx context.Context, bl
 			return fmt.Errorf("unable to get trie root hash: %v", err)
 		}
 
-		totals, err = accountsTotals(tx, true)
+		totals, err = tx.accountsTotals(true)
 		if err != nil {
 			return fmt.Errorf("unable to get accounts totals: %v", err)
 		}
@@ -413,9 +581,9 @@ func (c *CatchpointCatchupAccessorImpl) StoreBalancesRound(ctx context.Context,
 	// calculate the balances round and store it. It *should* be identical to the one in the catchpoint file header, but we don't want to
 	// trust the one in the catchpoint file header, so we'll calculate it ourselves.
 	balancesRound := blk.Round() - basics.Round(config.Consensus[blk.CurrentProtocol].MaxBalLookback)
-	wdb := c.ledger.trackerDB().wdb
-	err = wdb.Atomic(func(tx *sql.Tx) (err error) {
-		sq, err := accountsDbInit(tx, tx)
+	trackerDBs := c.ledger.trackerDB()
+	err = trackerDBs.write(func(tx trackerTx) (err error) {
+		sq, err := tx.queries()
 		_, err = sq.writeCatchpointStateUint64(ctx, catchpointStateCatchupBalancesRound, uint64(balancesRound))
 		if err != nil {
 			return fmt.Errorf("CatchpointCatchupAccessorImpl::StoreBalancesRound: unable to write catchpoint catchup state '%s': %v", catchpointStateCatchupBalancesRound, err)
@@ -428,8 +596,8 @@ func (c *CatchpointCatchupAccessorImpl) StoreBalancesRound(ctx context.Context,
 // StoreFirstBlock stores a single block to the blocks database.
 func (c *CatchpointCatchupAccessorImpl) StoreFirstBlock(ctx context.Context, blk *bookkeeping.Block) (err error) {
 	blockDbs := c.ledger.blockDB()
-	err = blockDbs.wdb.Atomic(func(tx *sql.Tx) (err error) {
-		return blockStartCatchupStaging(tx, *blk)
+	err = blockDbs.write(func(tx blockTx) (err error) {
+		return tx.startCatchupStaging(*blk)
 	})
 	if err != nil {
 		return err
@@ -440,8 +608,8 @@ func (c *CatchpointCatchupAccessorImpl) StoreFirstBlock(ctx context.Context, blk
 // StoreBlock stores a single block to the blocks database.
 func (c *CatchpointCatchupAccessorImpl) StoreBlock(ctx context.Context, blk *bookkeeping.Block) (err error) {
 	blockDbs := c.ledger.blockDB()
-	err = blockDbs.wdb.Atomic(func(tx *sql.Tx) (err error) {
-		return blockPutStaging(tx, *blk)
+	err = blockDbs.write(func(tx blockTx) (err error) {
+		return tx.putStaging(*blk)
 	})
 	if err != nil {
 		return err
@@ -452,23 +620,27 @@ func (c *CatchpointCatchupAccessorImpl) StoreBlock(ctx context.Context, blk *boo
 // FinishBlocks concludes the catchup of the blocks database.
 func (c *CatchpointCatchupAccessorImpl) FinishBlocks(ctx context.Context, applyChanges bool) (err error) {
 	blockDbs := c.ledger.blockDB()
-	err = blockDbs.wdb.Atomic(func(tx *sql.Tx) (err error) {
+	err = blockDbs.write(func(tx blockTx) (err error) {
 		if applyChanges {
-			return blockCompleteCatchup(tx)
+			return tx.completeCatchup()
 		}
-		return blockAbortCatchup(tx)
+		return tx.abortCatchup()
 	})
 	if err != nil {
 		return err
 	}
+	if applyChanges {
+		// the blocks the pruner knew of are gone.
+		return c.ledger.blockQ.pruner.reset()
+	}
 	return nil
 }
 
 // EnsureFirstBlock ensure that we have a single block in the staging block table, and returns that block
 func (c *CatchpointCatchupAccessorImpl) EnsureFirstBlock(ctx context.Context) (blk bookkeeping.Block, err error) {
 	blockDbs := c.ledger.blockDB()
-	err = blockDbs.wdb.Atomic(func(tx *sql.Tx) (err error) {
-		blk, err = blockEnsureSingleBlock(tx)
+	err = blockDbs.write(func(tx blockTx) (err error) {
+		blk, err = tx.ensureSingleBlock()
 		return
 	})
 	if err != nil {
@@ -494,14 +666,14 @@ func (c *CatchpointCatchupAccessorImpl) CompleteCatchup(ctx context.Context) (er
 
 // finishBalances concludes the catchup of the balances(tracker) database.
 func (c *CatchpointCatchupAccessorImpl) finishBalances(ctx context.Context) (err error) {
-	wdb := c.ledger.trackerDB().wdb
-	err = wdb.Atomic(func(tx *sql.Tx) (err error) {
+	trackerDBs := c.ledger.trackerDB()
+	err = trackerDBs.write(func(tx trackerTx) (err error) {
 		var balancesRound uint64
 		var totals AccountTotals
 
-		sq, err := accountsDbInit(tx, tx)
+		sq, err := tx.queries()
 		if err != nil {
-			return fmt.Errorf("unable to initialize accountsDbInit: %v", err)
+			return fmt.Errorf("unable to initialize the accounts queries: %v", err)
 		}
 
 		balancesRound, _, err = sq.readCatchpointStateUint64(ctx, catchpointStateCatchupBalancesRound)
@@ -509,22 +681,22 @@ func (c *CatchpointCatchupAccessorImpl) finishBalances(ctx context.Context) (err
 			return err
 		}
 
-		totals, err = accountsTotals(tx, true)
+		totals, err = tx.accountsTotals(true)
 		if err != nil {
 			return err
 		}
 
-		err = applyCatchpointStagingBalances(ctx, tx, basics.Round(balancesRound))
+		err = tx.applyCatchpointStagingBalances(ctx, basics.Round(balancesRound))
 		if err != nil {
 			return err
 		}
 
-		err = accountsPutTotals(tx, totals, false)
+		err = tx.accountsPutTotals(totals, false)
 		if err != nil {
 			return err
 		}
 
-		err = resetCatchpointStagingBalances(ctx, tx, false)
+		err = tx.resetCatchpointStagingBalances(ctx, false)
 		if err != nil {
 			return err
 		}
@@ -17,8 +17,6 @@
 package ledger
 
 import (
-	"fmt"
-
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
@@ -38,7 +36,7 @@ type roundCowParent interface {
 	lookup(basics.Address) (basics.AccountData, error)
 	isDup(basics.Round, basics.Round, transactions.Txid, txlease) (bool, error)
 	txnCounter() uint64
-	getAssetCreator(assetIdx basics.AssetIndex) (basics.Address, error)
+	getCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error)
 }
 
 type roundCowState struct {
@@ -56,27 +54,41 @@ type StateDelta struct {
 	// new Txids for the txtail and TxnCounter, mapped to txn.LastValid
 	Txids map[transactions.Txid]basics.Round
 
+	// number of inner transactions issued by applications; these advance
+	// the TxnCounter but are not tracked in the txtail
+	innerTxns uint64
+
 	// new txleases for the txtail mapped to expiration
 	txleases map[txlease]basics.Round
 
-	// new assets creator lookup table
-	assets map[basics.AssetIndex]modifiedAsset
+	// new creatables creator lookup table
+	creatables map[basics.CreatableIndex]modifiedCreatable
 
 	// new block header; read-only
 	hdr *bookkeeping.BlockHeader
 }
 
+// modifiedAccounts returns the new state of the accounts in accts. Closed
+// accounts have the zero AccountData.
+func modifiedAccounts(accts map[basics.Address]accountDelta) map[basics.Address]basics.AccountData {
+	modified := make(map[basics.Address]basics.AccountData, len(accts))
+	for addr, delta := range accts {
+		modified[addr] = delta.new
+	}
+	return modified
+}
+
 func makeRoundCowState(b roundCowParent, hdr bookkeeping.BlockHeader) *roundCowState {
 	return &roundCowState{
 		lookupParent: b,
 		commitParent: nil,
 		proto:        config.Consensus[hdr.CurrentProtocol],
 		mods: StateDelta{
-			accts:    make(map[basics.Address]accountDelta),
-			Txids:    make(map[transactions.Txid]basics.Round),
-			txleases: make(map[txlease]basics.Round),
-			assets:   make(map[basics.AssetIndex]modifiedAsset),
-			hdr:      &hdr,
+			accts:      make(map[basics.Address]accountDelta),
+			Txids:      make(map[transactions.Txid]basics.Round),
+			txleases:   make(map[txlease]basics.Round),
+			creatables: make(map[basics.CreatableIndex]modifiedCreatable),
+			hdr:        &hdr,
 		},
 	}
 }
@@ -85,15 +97,12 @@ func (cb *roundCowState) rewardsLevel() uint64 {
 	return cb.mods.hdr.RewardsLevel
 }
 
-func (cb *roundCowState) getAssetCreator(aidx basics.AssetIndex) (basics.Address, error) {
-	delta, ok := cb.mods.assets[aidx]
-	if ok {
-		if delta.created {
-			return delta.creator, nil
-		}
-		return basics.Address{}, fmt.Errorf("asset %d has been deleted", aidx)
+func (cb *roundCowState) getCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error) {
+	delta, ok := cb.mods.creatables[cidx]
+	if ok && delta.ctype == ctype {
+		return delta.creator, delta.created, nil
 	}
-	return cb.lookupParent.getAssetCreator(aidx)
+	return cb.lookupParent.getCreator(cidx, ctype)
 }
 
 func (cb *roundCowState) lookup(addr basics.Address) (data basics.AccountData, err error) {
@@ -122,7 +131,7 @@ func (cb *roundCowState) isDup(firstValid, lastValid basics.Round, txid transact
 }
 
 func (cb *roundCowState) txnCounter() uint64 {
-	return cb.lookupParent.txnCounter() + uint64(len(cb.mods.Txids))
+	return cb.lookupParent.txnCounter() + uint64(len(cb.mods.Txids)) + cb.mods.innerTxns
 }
 
 func (cb *roundCowState) put(addr basics.Address, old basics.AccountData, new basics.AccountData) {
@@ -133,10 +142,10 @@ func (cb *roundCowState) put(addr basics.Address, old basics.AccountData, new ba
 		cb.mods.accts[addr] = accountDelta{old: old, new: new}
 	}
 
-	// Get which asset indices were created and deleted, and update state
-	assetDeltas := getChangedAssetIndices(addr, accountDelta{old: old, new: new})
-	for aidx, delta := range assetDeltas {
-		cb.mods.assets[aidx] = delta
+	// Get which asset and app indices were created and deleted, and update state
+	creatableDeltas := getChangedCreatables(addr, accountDelta{old: old, new: new})
+	for cidx, delta := range creatableDeltas {
+		cb.mods.creatables[cidx] = delta
 	}
 }
 
@@ -145,17 +154,21 @@ func (cb *roundCowState) addTx(txn transactions.Transaction, txid transactions.T
 	cb.mods.txleases[txlease{sender: txn.Sender, lease: txn.Lease}] = txn.LastValid
 }
 
+func (cb *roundCowState) addInnerTxns(count int) {
+	cb.mods.innerTxns += uint64(count)
+}
+
 func (cb *roundCowState) child() *roundCowState {
 	return &roundCowState{
 		lookupParent: cb,
 		commitParent: cb,
 		proto:        cb.proto,
 		mods: StateDelta{
-			accts:    make(map[basics.Address]accountDelta),
-			Txids:    make(map[transactions.Txid]basics.Round),
-			txleases: make(map[txlease]basics.Round),
-			assets:   make(map[basics.AssetIndex]modifiedAsset),
-			hdr:      cb.mods.hdr,
+			accts:      make(map[basics.Address]accountDelta),
+			Txids:      make(map[transactions.Txid]basics.Round),
+			txleases:   make(map[txlease]basics.Round),
+			creatables: make(map[basics.CreatableIndex]modifiedCreatable),
+			hdr:        cb.mods.hdr,
 		},
 	}
 }
@@ -176,11 +189,12 @@ func (cb *roundCowState) commitToParent() {
 	for txid, lv := range cb.mods.Txids {
 		cb.commitParent.mods.Txids[txid] = lv
 	}
+	cb.commitParent.mods.innerTxns += cb.mods.innerTxns
 	for txl, expires := range cb.mods.txleases {
 		cb.commitParent.mods.txleases[txl] = expires
 	}
-	for aidx, delta := range cb.mods.assets {
-		cb.commitParent.mods.assets[aidx] = delta
+	for cidx, delta := range cb.mods.creatables {
+		cb.commitParent.mods.creatables[cidx] = delta
 	}
 }
 
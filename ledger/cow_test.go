@@ -38,8 +38,8 @@ func (ml *mockLedger) isDup(firstValid, lastValid basics.Round, txn transactions
 	return false, nil
 }
 
-func (ml *mockLedger) getAssetCreator(assetIdx basics.AssetIndex) (basics.Address, error) {
-	return basics.Address{}, nil
+func (ml *mockLedger) getCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error) {
+	return basics.Address{}, false, nil
 }
 
 func (ml *mockLedger) txnCounter() uint64 {
@@ -98,3 +98,25 @@ func TestCowBalance(t *testing.T) {
 	c1.commitToParent()
 	checkCow(t, c0, accts2)
 }
+
+func TestCowTxnCounter(t *testing.T) {
+	ml := mockLedger{balanceMap: randomAccounts(1)}
+
+	c0 := makeRoundCowState(&ml, bookkeeping.BlockHeader{})
+	require.Equal(t, uint64(0), c0.txnCounter())
+
+	c1 := c0.child()
+	txn := transactions.Transaction{}
+	txn.Sender = randomAddress()
+	c1.addTx(txn, txn.ID())
+	require.Equal(t, uint64(1), c1.txnCounter())
+
+	// inner transactions advance the counter without entering the txtail
+	c1.addInnerTxns(3)
+	require.Equal(t, uint64(4), c1.txnCounter())
+	require.Equal(t, 1, len(c1.mods.Txids))
+	require.Equal(t, uint64(0), c0.txnCounter())
+
+	c1.commitToParent()
+	require.Equal(t, uint64(4), c0.txnCounter())
+}
@@ -35,12 +35,22 @@ func (p dbPair) close() {
 }
 
 func dbOpen(filename string, memory bool) (p dbPair, err error) {
-	p.rdb, err = db.MakeAccessor(filename, true, memory)
+	return dbOpenWith(db.MakeAccessor, filename, memory)
+}
+
+// dbOpenIncrementalVacuum is like dbOpen, but the database, if it gets created,
+// can be vacuumed incrementally.
+func dbOpenIncrementalVacuum(filename string, memory bool) (p dbPair, err error) {
+	return dbOpenWith(db.MakeIncrementalVacuumAccessor, filename, memory)
+}
+
+func dbOpenWith(makeAccessor func(string, bool, bool) (db.Accessor, error), filename string, memory bool) (p dbPair, err error) {
+	p.rdb, err = makeAccessor(filename, true, memory)
 	if err != nil {
 		return
 	}
 
-	p.wdb, err = db.MakeAccessor(filename, false, memory)
+	p.wdb, err = makeAccessor(filename, false, memory)
 	if err != nil {
 		p.rdb.Close()
 		return
@@ -44,6 +44,34 @@ func (bile BlockInLedgerError) Error() string {
 	return fmt.Sprintf("block number already in ledger: block %d < next Round %d", bile.LastRound, bile.NextRound)
 }
 
+// ErrDeltaNotAvailable is returned when the changes made by a round are not
+// held by the ledger; they're only kept for the rounds that were not yet
+// flushed to disk, and by the archival ledgers with the account history
+// enabled.
+type ErrDeltaNotAvailable struct {
+	Round  basics.Round
+	Oldest basics.Round
+	Latest basics.Round
+}
+
+// Error satisfies builtin interface `error`
+func (err ErrDeltaNotAvailable) Error() string {
+	return fmt.Sprintf("ledger does not have the changes of round %d (oldest %d, latest %d)", err.Round, err.Oldest, err.Latest)
+}
+
+// ErrHistoryNotAvailable is returned when the state of the accounts at a
+// round is not held by the ledger; past the in-memory rounds, it's only kept
+// by archival ledgers with the account history enabled.
+type ErrHistoryNotAvailable struct {
+	Round  basics.Round
+	Oldest basics.Round
+}
+
+// Error satisfies builtin interface `error`
+func (err ErrHistoryNotAvailable) Error() string {
+	return fmt.Sprintf("ledger does not have the account state of round %d (oldest %d)", err.Round, err.Oldest)
+}
+
 // ErrNoEntry is used to indicate that a block is not present in the ledger.
 type ErrNoEntry struct {
 	Round     basics.Round
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
@@ -27,6 +28,7 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/committee"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/data/transactions/verify"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
@@ -59,13 +61,23 @@ type roundCowBase struct {
 
 	// The current protocol consensus params.
 	proto config.ConsensusParams
+
+	// The accounts and creators loaded by prefetch, if any.
+	accounts map[basics.Address]basics.AccountData
+	creators map[creatableKey]foundCreator
 }
 
-func (x *roundCowBase) getAssetCreator(assetIdx basics.AssetIndex) (basics.Address, error) {
-	return x.l.GetAssetCreatorForRound(x.rnd, assetIdx)
+func (x *roundCowBase) getCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error) {
+	if found, ok := x.creators[creatableKey{cidx: cidx, ctype: ctype}]; ok {
+		return found.addr, found.ok, nil
+	}
+	return x.l.GetCreatorForRound(x.rnd, cidx, ctype)
 }
 
 func (x *roundCowBase) lookup(addr basics.Address) (basics.AccountData, error) {
+	if data, ok := x.accounts[addr]; ok {
+		return data, nil
+	}
 	return x.l.LookupWithoutRewards(x.rnd, addr)
 }
 
@@ -78,15 +90,24 @@ func (x *roundCowBase) txnCounter() uint64 {
 }
 
 func (cs *roundCowState) GetAssetCreator(assetIdx basics.AssetIndex) (basics.Address, error) {
-	return cs.getAssetCreator(assetIdx)
+	creator, ok, err := cs.getCreator(basics.CreatableIndex(assetIdx), basics.AssetCreatable)
+	if err != nil {
+		return basics.Address{}, err
+	}
+	if !ok {
+		return basics.Address{}, fmt.Errorf("asset %d does not exist or has been deleted", assetIdx)
+	}
+	return creator, nil
 }
 
 func (cs *roundCowState) GetAppCreator(appIdx basics.AppIndex) (basics.Address, bool, error) {
-	return basics.Address{}, false, errors.New("applications not implemented")
+	return cs.getCreator(basics.CreatableIndex(appIdx), basics.AppCreatable)
 }
 
+// PutWithCreatables is like Put; the created and deleted creatables are found
+// from the change of the balance record by put.
 func (cs *roundCowState) PutWithCreatables(record basics.BalanceRecord, newCreatables []basics.CreatableLocator, deletedCreatables []basics.CreatableLocator) error {
-	return errors.New("applications not implemented")
+	return cs.Put(record)
 }
 
 // wrappers for roundCowState to satisfy the (current) transactions.Balances interface
@@ -177,6 +198,10 @@ type BlockEvaluator struct {
 	block        bookkeeping.Block
 	blockTxBytes int
 
+	// appTraces, if set, receives the traces of the programs of the
+	// application calls of the next group, by their index in the group.
+	appTraces []strings.Builder
+
 	l ledgerForEvaluator
 }
 
@@ -188,13 +213,16 @@ type ledgerForEvaluator interface {
 	isDup(config.ConsensusParams, basics.Round, basics.Round, basics.Round, transactions.Txid, txlease) (bool, error)
 	GetRoundTxIds(rnd basics.Round) (txMap map[transactions.Txid]bool)
 	LookupWithoutRewards(basics.Round, basics.Address) (basics.AccountData, error)
-	GetAssetCreatorForRound(basics.Round, basics.AssetIndex) (basics.Address, error)
+	GetCreatorForRound(basics.Round, basics.CreatableIndex, basics.CreatableType) (basics.Address, bool, error)
 }
 
 // StartEvaluator creates a BlockEvaluator, given a ledger and a block header
 // of the block that the caller is planning to evaluate. If the length of the
 // payset being evaluated is known in advance, a paysetHint >= 0 can be
 // passed, avoiding unnecessary payset slice growth.
+//
+// The accounts aren't prefetched: the transactions are only known as they
+// are added to the evaluator, so each one loads the accounts it accesses.
 func (l *Ledger) StartEvaluator(hdr bookkeeping.BlockHeader, paysetHint int) (*BlockEvaluator, error) {
 	return startEvaluator(l, hdr, paysetHint, true, true)
 }
@@ -464,13 +492,21 @@ func (eval *BlockEvaluator) TransactionGroup(txads []transactions.SignedTxnWithA
 // If the transaction group cannot be added to the block without violating some constraints,
 // an error is returned and the block evaluator state is unchanged.
 func (eval *BlockEvaluator) transactionGroup(txgroup []transactions.SignedTxnWithAD) error {
+	_, err := eval.transactionGroupFailedAt(txgroup)
+	return err
+}
+
+// transactionGroupFailedAt is like transactionGroup, but when the group
+// cannot be added to the block, it also returns the index of the transaction
+// that failed, or -1 if the group is rejected as a whole.
+func (eval *BlockEvaluator) transactionGroupFailedAt(txgroup []transactions.SignedTxnWithAD) (int, error) {
 	// Nothing to do if there are no transactions.
 	if len(txgroup) == 0 {
-		return nil
+		return -1, nil
 	}
 
 	if len(txgroup) > eval.proto.MaxTxGroupSize {
-		return fmt.Errorf("group size %d exceeds maximum %d", len(txgroup), eval.proto.MaxTxGroupSize)
+		return -1, fmt.Errorf("group size %d exceeds maximum %d", len(txgroup), eval.proto.MaxTxGroupSize)
 	}
 
 	var txibs []transactions.SignedTxnInBlock
@@ -478,13 +514,14 @@ func (eval *BlockEvaluator) transactionGroup(txgroup []transactions.SignedTxnWit
 	var groupTxBytes int
 
 	cow := eval.state.child()
+	appEvals := eval.prepareAppEvaluators(txgroup)
 
 	for gi, txad := range txgroup {
 		var txib transactions.SignedTxnInBlock
 
-		err := eval.transaction(txad.SignedTxn, txad.ApplyData, cow, &txib)
+		err := eval.transaction(txad.SignedTxn, appEvals[gi], txad.ApplyData, cow, &txib)
 		if err != nil {
-			return err
+			return gi, err
 		}
 
 		txibs = append(txibs, txib)
@@ -492,13 +529,13 @@ func (eval *BlockEvaluator) transactionGroup(txgroup []transactions.SignedTxnWit
 		if eval.validate {
 			groupTxBytes += len(protocol.Encode(&txib))
 			if eval.blockTxBytes+groupTxBytes > eval.proto.MaxTxnBytesPerBlock {
-				return ErrNoSpace
+				return gi, ErrNoSpace
 			}
 		}
 
 		// Make sure all transactions in group have the same group value
 		if txad.SignedTxn.Txn.Group != txgroup[0].SignedTxn.Txn.Group {
-			return fmt.Errorf("transactionGroup: inconsistent group values: %v != %v",
+			return gi, fmt.Errorf("transactionGroup: inconsistent group values: %v != %v",
 				txad.SignedTxn.Txn.Group, txgroup[0].SignedTxn.Txn.Group)
 		}
 
@@ -508,14 +545,14 @@ func (eval *BlockEvaluator) transactionGroup(txgroup []transactions.SignedTxnWit
 
 			group.TxGroupHashes = append(group.TxGroupHashes, crypto.HashObj(txWithoutGroup))
 		} else if len(txgroup) > 1 {
-			return fmt.Errorf("transactionGroup: [%d] had zero Group but was submitted in a group of %d", gi, len(txgroup))
+			return gi, fmt.Errorf("transactionGroup: [%d] had zero Group but was submitted in a group of %d", gi, len(txgroup))
 		}
 	}
 
 	// If we had a non-zero Group value, check that all group members are present.
 	if group.TxGroupHashes != nil {
 		if txgroup[0].SignedTxn.Txn.Group != crypto.HashObj(group) {
-			return fmt.Errorf("transactionGroup: incomplete group: %v != %v (%v)",
+			return -1, fmt.Errorf("transactionGroup: incomplete group: %v != %v (%v)",
 				txgroup[0].SignedTxn.Txn.Group, crypto.HashObj(group), group)
 		}
 	}
@@ -524,13 +561,48 @@ func (eval *BlockEvaluator) transactionGroup(txgroup []transactions.SignedTxnWit
 	eval.blockTxBytes += groupTxBytes
 	cow.commitToParent()
 
-	return nil
+	return -1, nil
+}
+
+// prepareAppEvaluators returns the evaluators of the application calls of a
+// transaction group, and nil for its other transactions.
+func (eval *BlockEvaluator) prepareAppEvaluators(txgroup []transactions.SignedTxnWithAD) []*appTealEvaluator {
+	var groupNoAD []transactions.SignedTxn
+	appEvals := make([]*appTealEvaluator, len(txgroup))
+	for i, txad := range txgroup {
+		if txad.SignedTxn.Txn.Type != protocol.ApplicationCallTx {
+			continue
+		}
+
+		// The programs see the group without its ApplyData
+		if groupNoAD == nil {
+			groupNoAD = make([]transactions.SignedTxn, len(txgroup))
+			for j := range txgroup {
+				groupNoAD[j] = txgroup[j].SignedTxn
+			}
+		}
+
+		appEvals[i] = &appTealEvaluator{
+			evalParams: logic.EvalParams{
+				Txn:        &groupNoAD[i],
+				Proto:      &eval.proto,
+				TxnGroup:   groupNoAD,
+				GroupIndex: i,
+			},
+			round:     eval.block.Round(),
+			timestamp: eval.prevHeader.TimeStamp,
+		}
+		if eval.appTraces != nil {
+			appEvals[i].evalParams.Trace = &eval.appTraces[i]
+		}
+	}
+	return appEvals
 }
 
 // transaction tentatively executes a new transaction as part of this block evaluation.
 // If the transaction cannot be added to the block without violating some constraints,
 // an error is returned and the block evaluator state is unchanged.
-func (eval *BlockEvaluator) transaction(txn transactions.SignedTxn, ad transactions.ApplyData, cow *roundCowState, txib *transactions.SignedTxnInBlock) error {
+func (eval *BlockEvaluator) transaction(txn transactions.SignedTxn, appEval *appTealEvaluator, ad transactions.ApplyData, cow *roundCowState, txib *transactions.SignedTxnInBlock) error {
 	var err error
 
 	// Only compute the TxID once
@@ -571,8 +643,14 @@ func (eval *BlockEvaluator) transaction(txn transactions.SignedTxn, ad transacti
 		RewardsPool: eval.block.BlockHeader.RewardsPool,
 	}
 
+	// Only application calls have a StateEvaluator
+	var steva transactions.StateEvaluator
+	if appEval != nil {
+		steva = appEval
+	}
+
 	// Apply the transaction, updating the cow balances
-	applyData, err := txn.Txn.Apply(cow, nil, spec, cow.txnCounter())
+	applyData, err := txn.Txn.Apply(cow, steva, spec, cow.txnCounter())
 	if err != nil {
 		return fmt.Errorf("transaction %v: %v", txid, err)
 	}
@@ -629,8 +707,10 @@ func (eval *BlockEvaluator) transaction(txn transactions.SignedTxn, ad transacti
 		}
 	}
 
-	// Remember this txn
+	// Remember this txn, and count any inner transactions it issued so
+	// that assets they created are not reused by later transactions
 	cow.addTx(txn.Txn, txid)
+	cow.addInnerTxns(len(applyData.InnerTxns))
 
 	return nil
 }
@@ -769,6 +849,11 @@ func (l *Ledger) eval(ctx context.Context, blk bookkeeping.Block, validate bool,
 		return StateDelta{}, err
 	}
 
+	// Load the accounts the payset accesses ahead of evaluating it
+	if base, ok := eval.state.lookupParent.(*roundCowBase); ok && l.prefetch {
+		base.prefetch(ctx, paysetgroups)
+	}
+
 	var txvalidator evalTxValidator
 	ctx, cf := context.WithCancel(ctx)
 	defer cf()
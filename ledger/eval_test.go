@@ -29,6 +29,7 @@ import (
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/execpool"
@@ -222,3 +223,104 @@ func TestRekeying(t *testing.T) {
 
 	// TODO: More tests
 }
+
+func TestEvalAppInnerTxns(t *testing.T) {
+	genesisInitState, addrs, keys := genesis(10)
+	genesisInitState.Block.CurrentProtocol = protocol.ConsensusFuture
+
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+	l, err := OpenLedger(logging.Base(), dbName, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+
+	// the application pays 5000 to its second account when called
+	approval, err := logic.AssembleString(`txn ApplicationID
+bz done
+itxn_begin
+int pay
+itxn_field TypeEnum
+txn Accounts 1
+itxn_field Receiver
+int 5000
+itxn_field Amount
+itxn_submit
+done:
+int 1
+`)
+	require.NoError(t, err)
+	clear, err := logic.AssembleString("int 1")
+	require.NoError(t, err)
+
+	newBlock := bookkeeping.MakeBlock(genesisInitState.Block.BlockHeader)
+	eval, err := l.StartEvaluator(newBlock.BlockHeader, 0)
+	require.NoError(t, err)
+
+	proto := config.Consensus[protocol.ConsensusFuture]
+	header := transactions.Header{
+		Sender:      addrs[0],
+		Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+		FirstValid:  newBlock.Round(),
+		LastValid:   newBlock.Round(),
+		GenesisHash: genesisInitState.GenesisHash,
+	}
+
+	create := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApprovalProgram:   approval,
+			ClearStateProgram: clear,
+		},
+	}
+	require.NoError(t, eval.Transaction(create.Sign(keys[0]), transactions.ApplyData{}))
+
+	// the first transaction of the first block creates application 1
+	appIdx := basics.AppIndex(1)
+	header.Note = []byte("fund")
+	fund := transactions.Transaction{
+		Type:   protocol.PaymentTx,
+		Header: header,
+		PaymentTxnFields: transactions.PaymentTxnFields{
+			Receiver: appIdx.Address(),
+			Amount:   basics.MicroAlgos{Raw: 1000000},
+		},
+	}
+	require.NoError(t, eval.Transaction(fund.Sign(keys[0]), transactions.ApplyData{}))
+
+	header.Note = []byte("call")
+	call := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApplicationID: appIdx,
+			Accounts:      []basics.Address{addrs[1]},
+		},
+	}
+	require.NoError(t, eval.Transaction(call.Sign(keys[0]), transactions.ApplyData{}))
+
+	validatedBlock, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	// the inner payment counts as a transaction of the block
+	require.Equal(t, uint64(4), validatedBlock.blk.TxnCounter)
+
+	payset, err := validatedBlock.blk.DecodePaysetFlat()
+	require.NoError(t, err)
+	require.Len(t, payset, 3)
+	require.Len(t, payset[2].ApplyData.InnerTxns, 1)
+	inner := payset[2].ApplyData.InnerTxns[0].Txn
+	require.Equal(t, appIdx.Address(), inner.Sender)
+	require.Equal(t, addrs[1], inner.Receiver)
+	require.Equal(t, uint64(5000), inner.Amount.Raw)
+
+	require.NoError(t, l.AddValidatedBlock(*validatedBlock, agreement.Certificate{}))
+
+	appAcct, err := l.Lookup(newBlock.Round(), appIdx.Address())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000000-5000-proto.MinTxnFee), appAcct.MicroAlgos.Raw)
+	receiver, err := l.Lookup(newBlock.Round(), addrs[1])
+	require.NoError(t, err)
+	require.Equal(t, genesisInitState.Accounts[addrs[1]].MicroAlgos.Raw+5000, receiver.MicroAlgos.Raw)
+}
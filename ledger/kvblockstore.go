@@ -0,0 +1,399 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/kvstore"
+)
+
+// kvBlockStoreCompactSegments is the number of segments reclaimSpace
+// compacts at most.
+const kvBlockStoreCompactSegments = 4
+
+// The keys of the kv blocks database are made of a table, a kind and the
+// big endian round of the block, so that the keys of each kind of record of a
+// table are ordered by round. The blocks of the ledger are in the blocks
+// table, and the ones of a catchpoint catchup in the staging one. The header,
+// the block and the certificate of a round are stored separately, so that the
+// headers can be read without decoding the rest of the blocks; the staged
+// blocks have no certificate.
+const (
+	kvBlocksTable  = 'b'
+	kvStagingTable = 's'
+
+	kvHeaderKind = 'h'
+	kvBlockKind  = 'b'
+	kvCertKind   = 'c'
+)
+
+var kvBlockKinds = []byte{kvHeaderKind, kvBlockKind, kvCertKind}
+
+func kvBlockKey(table, kind byte, rnd basics.Round) []byte {
+	key := make([]byte, 10)
+	key[0] = table
+	key[1] = kind
+	binary.BigEndian.PutUint64(key[2:], uint64(rnd))
+	return key
+}
+
+func kvBlockKeyRound(key []byte) basics.Round {
+	return basics.Round(binary.BigEndian.Uint64(key[2:]))
+}
+
+// kvBlockStore is the blockStore of a kvstore.Store.
+type kvBlockStore struct {
+	store *kvstore.Store
+}
+
+func openKVBlockStore(path string, readOnly bool, dbMem bool) (*kvBlockStore, error) {
+	store, err := kvstore.Open(path, kvstore.Options{ReadOnly: readOnly, InMemory: dbMem})
+	if err != nil {
+		return nil, err
+	}
+	return &kvBlockStore{store: store}, nil
+}
+
+func (s *kvBlockStore) read(fn func(tx blockTx) error) error {
+	return s.store.View(func(tx *kvstore.Tx) error {
+		return fn(kvBlockTx{tx})
+	})
+}
+
+func (s *kvBlockStore) write(fn func(tx blockTx) error) error {
+	return s.store.Update(func(tx *kvstore.Tx) error {
+		return fn(kvBlockTx{tx})
+	})
+}
+
+func (s *kvBlockStore) reclaimSpace() error {
+	_, err := s.store.Compact(kvBlockStoreCompactSegments)
+	return err
+}
+
+// enableReclaimSpace does nothing, since the kv store can always be compacted.
+func (s *kvBlockStore) enableReclaimSpace() error {
+	return nil
+}
+
+// setLogger does nothing, since the kv store doesn't log.
+func (s *kvBlockStore) setLogger(log logging.Logger) {
+}
+
+func (s *kvBlockStore) close() {
+	s.store.Close()
+}
+
+type kvBlockTx struct {
+	tx *kvstore.Tx
+}
+
+// first and last return the first and the last round of the blocks of a
+// table, if there are any.
+func (t kvBlockTx) first(table byte) (basics.Round, bool) {
+	key, ok := t.tx.First([]byte{table, kvHeaderKind}, []byte{table, kvHeaderKind + 1})
+	if !ok {
+		return 0, false
+	}
+	return kvBlockKeyRound(key), true
+}
+
+func (t kvBlockTx) last(table byte) (basics.Round, bool) {
+	key, ok := t.tx.Last([]byte{table, kvHeaderKind}, []byte{table, kvHeaderKind + 1})
+	if !ok {
+		return 0, false
+	}
+	return kvBlockKeyRound(key), true
+}
+
+// rounds returns the rounds of the blocks of a table in [start, end).
+func (t kvBlockTx) rounds(table byte, start, end basics.Round) (rounds []basics.Round) {
+	t.tx.Ascend(kvBlockKey(table, kvHeaderKind, start), kvBlockKey(table, kvHeaderKind, end), func(key []byte) bool {
+		rounds = append(rounds, kvBlockKeyRound(key))
+		return true
+	})
+	return
+}
+
+// allRounds returns the rounds of all the blocks of a table.
+func (t kvBlockTx) allRounds(table byte) (rounds []basics.Round) {
+	t.tx.Ascend([]byte{table, kvHeaderKind}, []byte{table, kvHeaderKind + 1}, func(key []byte) bool {
+		rounds = append(rounds, kvBlockKeyRound(key))
+		return true
+	})
+	return
+}
+
+func (t kvBlockTx) deleteBlock(table byte, rnd basics.Round) error {
+	for _, kind := range kvBlockKinds {
+		err := t.tx.Delete(kvBlockKey(table, kind, rnd))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t kvBlockTx) putBlock(table byte, blk bookkeeping.Block, cert *agreement.Certificate) error {
+	err := t.tx.Put(kvBlockKey(table, kvHeaderKind, blk.Round()), protocol.Encode(&blk.BlockHeader))
+	if err != nil {
+		return err
+	}
+	err = t.tx.Put(kvBlockKey(table, kvBlockKind, blk.Round()), protocol.Encode(&blk))
+	if err != nil {
+		return err
+	}
+	if cert != nil {
+		return t.tx.Put(kvBlockKey(table, kvCertKind, blk.Round()), protocol.Encode(cert))
+	}
+	return nil
+}
+
+func (t kvBlockTx) getRecord(table, kind byte, rnd basics.Round) ([]byte, error) {
+	buf, err := t.tx.Get(kvBlockKey(table, kind, rnd))
+	if err == kvstore.ErrNotFound {
+		err = ErrNoEntry{Round: rnd}
+	}
+	return buf, err
+}
+
+func (t kvBlockTx) init(initBlocks []bookkeeping.Block) error {
+	next, err := t.next()
+	if err != nil {
+		return err
+	}
+
+	if next == 0 {
+		for _, blk := range initBlocks {
+			err = t.put(blk, agreement.Certificate{})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t kvBlockTx) reset() error {
+	for _, rnd := range t.allRounds(kvBlocksTable) {
+		err := t.deleteBlock(kvBlocksTable, rnd)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t kvBlockTx) get(rnd basics.Round) (blk bookkeeping.Block, err error) {
+	buf, err := t.getRecord(kvBlocksTable, kvBlockKind, rnd)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &blk)
+	return
+}
+
+func (t kvBlockTx) getHdr(rnd basics.Round) (hdr bookkeeping.BlockHeader, err error) {
+	buf, err := t.getRecord(kvBlocksTable, kvHeaderKind, rnd)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &hdr)
+	return
+}
+
+func (t kvBlockTx) getEncodedCert(rnd basics.Round) (blk []byte, cert []byte, err error) {
+	blk, err = t.getRecord(kvBlocksTable, kvBlockKind, rnd)
+	if err != nil {
+		return
+	}
+	cert, err = t.tx.Get(kvBlockKey(kvBlocksTable, kvCertKind, rnd))
+	if err == kvstore.ErrNotFound {
+		cert, err = nil, nil
+	}
+	return
+}
+
+func (t kvBlockTx) getCert(rnd basics.Round) (blk bookkeeping.Block, cert agreement.Certificate, err error) {
+	blkbuf, certbuf, err := t.getEncodedCert(rnd)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(blkbuf, &blk)
+	if err != nil {
+		return
+	}
+
+	if certbuf != nil {
+		err = protocol.Decode(certbuf, &cert)
+	}
+	return
+}
+
+func (t kvBlockTx) put(blk bookkeeping.Block, cert agreement.Certificate) error {
+	next, err := t.next()
+	if err != nil {
+		return err
+	}
+	if blk.Round() != next {
+		return fmt.Errorf("inserting block %d but expected %d", blk.Round(), next)
+	}
+	return t.putBlock(kvBlocksTable, blk, &cert)
+}
+
+func (t kvBlockTx) next() (basics.Round, error) {
+	latest, ok := t.last(kvBlocksTable)
+	if !ok {
+		return 0, nil
+	}
+	return latest + 1, nil
+}
+
+func (t kvBlockTx) latest() (basics.Round, error) {
+	latest, ok := t.last(kvBlocksTable)
+	if !ok {
+		return 0, fmt.Errorf("no blocks present")
+	}
+	return latest, nil
+}
+
+func (t kvBlockTx) earliest() (basics.Round, error) {
+	earliest, ok := t.first(kvBlocksTable)
+	if !ok {
+		return 0, fmt.Errorf("no blocks present")
+	}
+	return earliest, nil
+}
+
+func (t kvBlockTx) earliestPrunable(keepInterval uint64) (basics.Round, error) {
+	if keepInterval == 0 {
+		return t.earliest()
+	}
+
+	var rnd basics.Round
+	found := false
+	t.tx.Ascend([]byte{kvBlocksTable, kvHeaderKind}, []byte{kvBlocksTable, kvHeaderKind + 1}, func(key []byte) bool {
+		rnd = kvBlockKeyRound(key)
+		found = uint64(rnd)%keepInterval != 0
+		return !found
+	})
+	if found {
+		return rnd, nil
+	}
+	return t.earliest()
+}
+
+func (t kvBlockTx) forgetRange(start, end basics.Round, keepInterval uint64) error {
+	next, err := t.next()
+	if err != nil {
+		return err
+	}
+
+	if end >= next {
+		return fmt.Errorf("forgetting too much: rnd %d >= next %d", end, next)
+	}
+
+	for _, rnd := range t.rounds(kvBlocksTable, start, end) {
+		if keepInterval != 0 && uint64(rnd)%keepInterval == 0 {
+			continue
+		}
+		err = t.deleteBlock(kvBlocksTable, rnd)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t kvBlockTx) startCatchupStaging(blk bookkeeping.Block) error {
+	err := t.abortCatchup()
+	if err != nil {
+		return err
+	}
+	return t.putBlock(kvStagingTable, blk, nil)
+}
+
+func (t kvBlockTx) putStaging(blk bookkeeping.Block) error {
+	return t.putBlock(kvStagingTable, blk, nil)
+}
+
+func (t kvBlockTx) completeCatchup() error {
+	err := t.reset()
+	if err != nil {
+		return err
+	}
+
+	for _, rnd := range t.allRounds(kvStagingTable) {
+		for _, kind := range kvBlockKinds {
+			buf, err := t.tx.Get(kvBlockKey(kvStagingTable, kind, rnd))
+			if err == kvstore.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			err = t.tx.Put(kvBlockKey(kvBlocksTable, kind, rnd), buf)
+			if err != nil {
+				return err
+			}
+		}
+		err = t.deleteBlock(kvStagingTable, rnd)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t kvBlockTx) abortCatchup() error {
+	for _, rnd := range t.allRounds(kvStagingTable) {
+		err := t.deleteBlock(kvStagingTable, rnd)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t kvBlockTx) ensureSingleBlock() (blk bookkeeping.Block, err error) {
+	round, ok := t.last(kvStagingTable)
+	if !ok {
+		return bookkeeping.Block{}, ErrNoEntry{}
+	}
+
+	// delete all the blocks that aren't the latest one.
+	for _, rnd := range t.rounds(kvStagingTable, 0, round) {
+		err = t.deleteBlock(kvStagingTable, rnd)
+		if err != nil {
+			return bookkeeping.Block{}, err
+		}
+	}
+
+	buf, err := t.getRecord(kvStagingTable, kvBlockKind, round)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &blk)
+	return blk, err
+}
@@ -0,0 +1,1167 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/kvstore"
+)
+
+// kvTrackerStoreCompactSegments is the number of segments compacted at most
+// after each write transaction. The accounts are overwritten all over the
+// store, so it is compacted a little at a time rather than in batches.
+const kvTrackerStoreCompactSegments = 1
+
+// The keys of the kv tracker database start with the table of their record.
+// The accounts are then keyed by address, the resources by address, creatable
+// index and type, the creators by creatable type and index, the pages of the
+// balances trie by number, the rounds and the catchpoint state by name, and
+// the catchpoints and the totals history by round. The account history is
+// keyed by address and round, and indexed by round and address. The integers
+// are big endian, so that the keys sort in the order of the numbers.
+//
+// The accounts, resources, creators and hashes tables come in two slots,
+// lowercase and uppercase: one holds the records of the ledger, and the other
+// the ones a catchpoint catchup stages. The kvSlotsKey record is set when the
+// uppercase slot holds the ones of the ledger, so that the staged records
+// replace them without being copied.
+const (
+	kvAccountsTable        = 'a'
+	kvResourcesTable       = 'r'
+	kvCreatorsTable        = 'c'
+	kvHashesTable          = 'h'
+	kvTotalsTable          = 't'
+	kvStagingTotalsTable   = 'T'
+	kvRoundsTable          = 'o'
+	kvCatchpointStateTable = 's'
+	kvCatchpointsTable     = 'p'
+	kvHistoryTable         = 'y'
+	kvHistoryRoundsTable   = 'z'
+	kvTotalsHistoryTable   = 'x'
+	kvSlotsTable           = 'g'
+)
+
+// kvSlottedTables are the tables the ledger and a catchpoint catchup each
+// have a slot of.
+var kvSlottedTables = []byte{kvAccountsTable, kvResourcesTable, kvCreatorsTable, kvHashesTable}
+
+var kvSlotsKey = []byte{kvSlotsTable}
+
+// The names of the rounds of the kv tracker database, as in the acctrounds
+// table.
+const (
+	kvAccountsRound = "acctbase"
+	kvHashesRound   = "hashbase"
+	kvHistoryRound  = "histbase"
+)
+
+// The kinds of values of the catchpoint state records.
+const (
+	kvStateUint64 = 'i'
+	kvStateString = 's'
+)
+
+// kvStoredCatchpoint is a record of the catchpoints table.
+type kvStoredCatchpoint struct {
+	FileName   string `codec:"filename"`
+	Catchpoint string `codec:"catchpoint"`
+	FileSize   int64  `codec:"filesize"`
+	Pinned     bool   `codec:"pinned"`
+}
+
+func kvUint64(v uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, v)
+	return buf
+}
+
+func kvKey(table byte, parts ...[]byte) []byte {
+	key := []byte{table}
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	return key
+}
+
+// kvPrefixEnd returns the smallest key that is greater than all the keys
+// starting with prefix, or nil if there is none.
+func kvPrefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func kvResourceKey(table byte, addr basics.Address, cidx basics.CreatableIndex, ctype basics.CreatableType) []byte {
+	return kvKey(table, addr[:], kvUint64(uint64(cidx)), kvUint64(uint64(ctype)))
+}
+
+func kvResourceKeyRecord(key []byte) (addr basics.Address, cidx basics.CreatableIndex, ctype basics.CreatableType) {
+	copy(addr[:], key[1:])
+	cidx = basics.CreatableIndex(binary.BigEndian.Uint64(key[1+len(addr):]))
+	ctype = basics.CreatableType(binary.BigEndian.Uint64(key[1+len(addr)+8:]))
+	return
+}
+
+func kvCreatorKey(table byte, cidx basics.CreatableIndex, ctype basics.CreatableType) []byte {
+	return kvKey(table, kvUint64(uint64(ctype)), kvUint64(uint64(cidx)))
+}
+
+func kvHistoryKey(addr basics.Address, rnd basics.Round) []byte {
+	return kvKey(kvHistoryTable, addr[:], kvUint64(uint64(rnd)))
+}
+
+func kvHistoryRoundKey(rnd basics.Round, addr basics.Address) []byte {
+	return kvKey(kvHistoryRoundsTable, kvUint64(uint64(rnd)), addr[:])
+}
+
+// kvTrackerStore is the trackerStore of a kvstore.Store.
+type kvTrackerStore struct {
+	store *kvstore.Store
+	log   logging.Logger
+}
+
+func openKVTrackerStore(path string, readOnly bool, dbMem bool) (*kvTrackerStore, error) {
+	store, err := kvstore.Open(path, kvstore.Options{ReadOnly: readOnly, InMemory: dbMem})
+	if err != nil {
+		return nil, err
+	}
+	return &kvTrackerStore{store: store}, nil
+}
+
+func (s *kvTrackerStore) read(fn func(tx trackerTx) error) error {
+	return s.store.View(func(tx *kvstore.Tx) error {
+		return fn(makeKVTrackerTx(tx))
+	})
+}
+
+func (s *kvTrackerStore) write(fn func(tx trackerTx) error) error {
+	err := s.store.Update(func(tx *kvstore.Tx) error {
+		return fn(makeKVTrackerTx(tx))
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.store.Compact(kvTrackerStoreCompactSegments)
+	if err != nil && s.log != nil {
+		// the transaction was committed; the space is reclaimed later on.
+		s.log.Warnf("kvTrackerStore: unable to compact the tracker database: %v", err)
+	}
+	return nil
+}
+
+func (s *kvTrackerStore) queries() (trackerQueries, error) {
+	return kvTrackerQueries{s}, nil
+}
+
+func (s *kvTrackerStore) historyQueries() (accountHistoryQueries, error) {
+	return kvHistoryQueries{s}, nil
+}
+
+func (s *kvTrackerStore) setLogger(log logging.Logger) {
+	s.log = log
+}
+
+func (s *kvTrackerStore) close() {
+	s.store.Close()
+}
+
+// view and update run fn in a transaction of the store.
+func (s *kvTrackerStore) view(fn func(t *kvTrackerTx) error) error {
+	return s.store.View(func(tx *kvstore.Tx) error {
+		return fn(makeKVTrackerTx(tx))
+	})
+}
+
+func (s *kvTrackerStore) update(fn func(t *kvTrackerTx) error) error {
+	return s.store.Update(func(tx *kvstore.Tx) error {
+		return fn(makeKVTrackerTx(tx))
+	})
+}
+
+// kvTrackerQueries runs each of the queries of a kvTrackerTx in a
+// transaction of its own.
+type kvTrackerQueries struct {
+	s *kvTrackerStore
+}
+
+func (q kvTrackerQueries) listCreatables(maxIdx basics.CreatableIndex, maxResults uint64, ctype basics.CreatableType) (results []basics.CreatableLocator, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		results, err = t.listCreatables(maxIdx, maxResults, ctype)
+		return
+	})
+	return
+}
+
+func (q kvTrackerQueries) lookupCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (addr basics.Address, ok bool, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		addr, ok, err = t.lookupCreator(cidx, ctype)
+		return
+	})
+	return
+}
+
+func (q kvTrackerQueries) lookup(addr basics.Address) (data basics.AccountData, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		data, err = t.lookup(addr)
+		return
+	})
+	return
+}
+
+func (q kvTrackerQueries) storeCatchpoint(ctx context.Context, round basics.Round, fileName string, catchpoint string, fileSize int64) error {
+	return q.s.update(func(t *kvTrackerTx) error {
+		return t.storeCatchpoint(ctx, round, fileName, catchpoint, fileSize)
+	})
+}
+
+func (q kvTrackerQueries) getOldestCatchpointFiles(ctx context.Context, fileCount int, filesToKeep int) (fileNames map[basics.Round]string, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		fileNames, err = t.getOldestCatchpointFiles(ctx, fileCount, filesToKeep)
+		return
+	})
+	return
+}
+
+func (q kvTrackerQueries) readCatchpointStateUint64(ctx context.Context, stateName catchpointState) (rnd uint64, def bool, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		rnd, def, err = t.readCatchpointStateUint64(ctx, stateName)
+		return
+	})
+	return
+}
+
+func (q kvTrackerQueries) writeCatchpointStateUint64(ctx context.Context, stateName catchpointState, setValue uint64) (cleared bool, err error) {
+	err = q.s.update(func(t *kvTrackerTx) (err error) {
+		cleared, err = t.writeCatchpointStateUint64(ctx, stateName, setValue)
+		return
+	})
+	return
+}
+
+func (q kvTrackerQueries) readCatchpointStateString(ctx context.Context, stateName catchpointState) (str string, def bool, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		str, def, err = t.readCatchpointStateString(ctx, stateName)
+		return
+	})
+	return
+}
+
+func (q kvTrackerQueries) writeCatchpointStateString(ctx context.Context, stateName catchpointState, setValue string) (cleared bool, err error) {
+	err = q.s.update(func(t *kvTrackerTx) (err error) {
+		cleared, err = t.writeCatchpointStateString(ctx, stateName, setValue)
+		return
+	})
+	return
+}
+
+// kvHistoryQueries runs each of the account history queries of a
+// kvTrackerTx in a transaction of its own.
+type kvHistoryQueries struct {
+	s *kvTrackerStore
+}
+
+func (q kvHistoryQueries) lookup(addr basics.Address, rnd basics.Round) (data basics.AccountData, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		data, err = t.historyLookup(addr, rnd)
+		return
+	})
+	return
+}
+
+func (q kvHistoryQueries) modified(rnd basics.Round) (accts map[basics.Address]basics.AccountData, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		accts, err = t.historyModified(rnd)
+		return
+	})
+	return
+}
+
+func (q kvHistoryQueries) totals(rnd basics.Round) (totals AccountTotals, err error) {
+	err = q.s.view(func(t *kvTrackerTx) (err error) {
+		totals, err = t.accountsHistoryTotals(rnd)
+		return
+	})
+	return
+}
+
+// kvTrackerTx is a trackerTx of a kvstore.Tx. It also implements the
+// trackerQueries in the transaction.
+type kvTrackerTx struct {
+	tx *kvstore.Tx
+	// swapped is set when the uppercase slot of the slotted tables holds the
+	// records of the ledger.
+	swapped bool
+}
+
+func makeKVTrackerTx(tx *kvstore.Tx) *kvTrackerTx {
+	return &kvTrackerTx{tx: tx, swapped: tx.Has(kvSlotsKey)}
+}
+
+// table returns the slot of a slotted table that holds the records of the
+// ledger, or the staged ones.
+func (t *kvTrackerTx) table(table byte, catchpointStaging bool) byte {
+	if catchpointStaging != t.swapped {
+		return table - 'a' + 'A'
+	}
+	return table
+}
+
+// keys returns the keys that start with prefix, in order.
+func (t *kvTrackerTx) keys(prefix []byte) (keys [][]byte) {
+	t.tx.Ascend(prefix, kvPrefixEnd(prefix), func(key []byte) bool {
+		keys = append(keys, append([]byte(nil), key...))
+		return true
+	})
+	return
+}
+
+// keysRange returns up to count of the keys that start with prefix, from the
+// one at index start on.
+func (t *kvTrackerTx) keysRange(prefix []byte, start, count int) (keys [][]byte) {
+	i := 0
+	t.tx.Ascend(prefix, kvPrefixEnd(prefix), func(key []byte) bool {
+		if len(keys) >= count {
+			return false
+		}
+		if i >= start {
+			keys = append(keys, append([]byte(nil), key...))
+		}
+		i++
+		return true
+	})
+	return
+}
+
+func (t *kvTrackerTx) count(prefix []byte) (n uint64) {
+	t.tx.Ascend(prefix, kvPrefixEnd(prefix), func(key []byte) bool {
+		n++
+		return true
+	})
+	return
+}
+
+func (t *kvTrackerTx) deletePrefix(prefix []byte) error {
+	for _, key := range t.keys(prefix) {
+		err := t.tx.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *kvTrackerTx) getRound(id string) (rnd basics.Round, ok bool, err error) {
+	buf, err := t.tx.Get(kvKey(kvRoundsTable, []byte(id)))
+	if err == kvstore.ErrNotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return basics.Round(binary.BigEndian.Uint64(buf)), true, nil
+}
+
+func (t *kvTrackerTx) putRound(id string, rnd basics.Round) error {
+	return t.tx.Put(kvKey(kvRoundsTable, []byte(id)), kvUint64(uint64(rnd)))
+}
+
+// getAccount returns the account data of an account record, merged with its
+// resources records.
+func (t *kvTrackerTx) getAccount(catchpointStaging bool, addr basics.Address) (data basics.AccountData, ok bool, err error) {
+	buf, err := t.tx.Get(kvKey(t.table(kvAccountsTable, catchpointStaging), addr[:]))
+	if err == kvstore.ErrNotFound {
+		return basics.AccountData{}, false, nil
+	}
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &data)
+	if err != nil {
+		return
+	}
+
+	for _, key := range t.keys(kvKey(t.table(kvResourcesTable, catchpointStaging), addr[:])) {
+		buf, err = t.tx.Get(key)
+		if err != nil {
+			return
+		}
+		var rdata basics.AccountData
+		err = protocol.Decode(buf, &rdata)
+		if err != nil {
+			return
+		}
+		mergeResourceData(&data, rdata)
+	}
+	return data, true, nil
+}
+
+func (t *kvTrackerTx) queries() (trackerQueries, error) {
+	return t, nil
+}
+
+func (t *kvTrackerTx) listCreatables(maxIdx basics.CreatableIndex, maxResults uint64, ctype basics.CreatableType) (results []basics.CreatableLocator, err error) {
+	table := t.table(kvCreatorsTable, false)
+	prefix := kvKey(table, kvUint64(uint64(ctype)))
+	end := kvPrefixEnd(kvCreatorKey(table, maxIdx, ctype))
+	for uint64(len(results)) < maxResults {
+		key, ok := t.tx.Last(prefix, end)
+		if !ok {
+			break
+		}
+		var buf []byte
+		buf, err = t.tx.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		al := basics.CreatableLocator{Type: ctype, Index: basics.CreatableIndex(binary.BigEndian.Uint64(key[len(prefix):]))}
+		copy(al.Creator[:], buf)
+		results = append(results, al)
+		end = key
+	}
+	return
+}
+
+func (t *kvTrackerTx) lookupCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (addr basics.Address, ok bool, err error) {
+	buf, err := t.tx.Get(kvCreatorKey(t.table(kvCreatorsTable, false), cidx, ctype))
+	if err == kvstore.ErrNotFound {
+		return addr, false, nil
+	}
+	if err != nil {
+		return
+	}
+	copy(addr[:], buf)
+	return addr, true, nil
+}
+
+func (t *kvTrackerTx) lookup(addr basics.Address) (data basics.AccountData, err error) {
+	data, _, err = t.getAccount(false, addr)
+	return
+}
+
+func (t *kvTrackerTx) storeCatchpoint(ctx context.Context, round basics.Round, fileName string, catchpoint string, fileSize int64) error {
+	key := kvKey(kvCatchpointsTable, kvUint64(uint64(round)))
+	err := t.tx.Delete(key)
+	if err != nil || (fileName == "" && catchpoint == "" && fileSize == 0) {
+		return err
+	}
+	return t.tx.Put(key, protocol.EncodeReflect(&kvStoredCatchpoint{FileName: fileName, Catchpoint: catchpoint, FileSize: fileSize}))
+}
+
+// storedCatchpoints returns the records of the catchpoints table, by round.
+func (t *kvTrackerTx) storedCatchpoints() (rounds []basics.Round, catchpoints []kvStoredCatchpoint, err error) {
+	prefix := []byte{kvCatchpointsTable}
+	for _, key := range t.keys(prefix) {
+		buf, err := t.tx.Get(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		var cp kvStoredCatchpoint
+		err = protocol.DecodeReflect(buf, &cp)
+		if err != nil {
+			return nil, nil, err
+		}
+		rounds = append(rounds, basics.Round(binary.BigEndian.Uint64(key[len(prefix):])))
+		catchpoints = append(catchpoints, cp)
+	}
+	return
+}
+
+func (t *kvTrackerTx) getOldestCatchpointFiles(ctx context.Context, fileCount int, filesToKeep int) (fileNames map[basics.Round]string, err error) {
+	rounds, catchpoints, err := t.storedCatchpoints()
+	if err != nil {
+		return nil, err
+	}
+	var unpinned []int
+	for i, cp := range catchpoints {
+		if !cp.Pinned {
+			unpinned = append(unpinned, i)
+		}
+	}
+
+	// the files up to the newest one that isn't among the filesToKeep most
+	// recent ones can be deleted.
+	var newest basics.Round
+	if filesToKeep < len(unpinned) {
+		newest = rounds[unpinned[len(unpinned)-1-filesToKeep]]
+	}
+	fileNames = make(map[basics.Round]string)
+	for _, i := range unpinned {
+		if len(fileNames) >= fileCount || rounds[i] > newest {
+			break
+		}
+		fileNames[rounds[i]] = catchpoints[i].FileName
+	}
+	return fileNames, nil
+}
+
+// getCatchpointState returns the value of a catchpoint state record of the
+// given kind, and whether it was found.
+func (t *kvTrackerTx) getCatchpointState(stateName catchpointState, kind byte) ([]byte, bool, error) {
+	buf, err := t.tx.Get(kvKey(kvCatchpointStateTable, []byte(stateName)))
+	if err == kvstore.ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(buf) == 0 || buf[0] != kind {
+		return nil, false, nil
+	}
+	return buf[1:], true, nil
+}
+
+func (t *kvTrackerTx) readCatchpointStateUint64(ctx context.Context, stateName catchpointState) (rnd uint64, def bool, err error) {
+	buf, ok, err := t.getCatchpointState(stateName, kvStateUint64)
+	if err != nil || !ok {
+		return 0, true, err
+	}
+	return binary.BigEndian.Uint64(buf), false, nil
+}
+
+func (t *kvTrackerTx) writeCatchpointStateUint64(ctx context.Context, stateName catchpointState, setValue uint64) (cleared bool, err error) {
+	key := kvKey(kvCatchpointStateTable, []byte(stateName))
+	if setValue == 0 {
+		return true, t.tx.Delete(key)
+	}
+	return false, t.tx.Put(key, kvKey(kvStateUint64, kvUint64(setValue)))
+}
+
+func (t *kvTrackerTx) readCatchpointStateString(ctx context.Context, stateName catchpointState) (str string, def bool, err error) {
+	buf, ok, err := t.getCatchpointState(stateName, kvStateString)
+	if err != nil || !ok {
+		return "", true, err
+	}
+	return string(buf), false, nil
+}
+
+func (t *kvTrackerTx) writeCatchpointStateString(ctx context.Context, stateName catchpointState, setValue string) (cleared bool, err error) {
+	key := kvKey(kvCatchpointStateTable, []byte(stateName))
+	if setValue == "" {
+		return true, t.tx.Delete(key)
+	}
+	return false, t.tx.Put(key, kvKey(kvStateString, []byte(setValue)))
+}
+
+// putCreatedRecords writes the account and resources records and the
+// creators of a new account.
+func (t *kvTrackerTx) putCreatedRecords(addr basics.Address, data basics.AccountData) error {
+	base, resources := splitAccountData(data)
+	err := t.tx.Put(kvKey(t.table(kvAccountsTable, false), addr[:]), protocol.Encode(&base))
+	if err != nil {
+		return err
+	}
+	for key, rdata := range resources {
+		err = t.tx.Put(kvResourceKey(t.table(kvResourcesTable, false), addr, key.cidx, key.ctype), protocol.Encode(&rdata))
+		if err != nil {
+			return err
+		}
+	}
+	for cidx, delta := range getChangedCreatables(addr, accountDelta{new: data}) {
+		err = t.tx.Put(kvCreatorKey(t.table(kvCreatorsTable, false), cidx, delta.ctype), addr[:])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *kvTrackerTx) accountsInit(initAccounts map[basics.Address]basics.AccountData, proto config.ConsensusParams) error {
+	_, ok, err := t.getRound(kvAccountsRound)
+	if err != nil || ok {
+		return err
+	}
+
+	var ot basics.OverflowTracker
+	var totals AccountTotals
+	for addr, data := range initAccounts {
+		err = t.putCreatedRecords(addr, data)
+		if err != nil {
+			return err
+		}
+		totals.addAccount(proto, data, &ot)
+	}
+	if ot.Overflowed {
+		return fmt.Errorf("overflow computing totals")
+	}
+
+	err = t.accountsPutTotals(totals, false)
+	if err != nil {
+		return err
+	}
+	return t.putRound(kvAccountsRound, 0)
+}
+
+// accountsReset deletes all the records but the staged ones, the way the
+// SQLite accountsReset drops all the tables but the staging ones.
+func (t *kvTrackerTx) accountsReset() error {
+	var keys [][]byte
+	t.tx.Ascend(nil, nil, func(key []byte) bool {
+		staged := false
+		for _, table := range kvSlottedTables {
+			staged = staged || key[0] == t.table(table, true)
+		}
+		if !staged {
+			keys = append(keys, append([]byte(nil), key...))
+		}
+		return true
+	})
+	for _, key := range keys {
+		err := t.tx.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *kvTrackerTx) accountsRound() (rnd basics.Round, hashrnd basics.Round, err error) {
+	rnd, ok, err := t.getRound(kvAccountsRound)
+	if err != nil {
+		return
+	}
+	if !ok {
+		return 0, 0, sql.ErrNoRows
+	}
+	hashrnd, _, err = t.getRound(kvHashesRound)
+	return
+}
+
+func (t *kvTrackerTx) updateAccountsRound(rnd basics.Round, hashRound basics.Round) error {
+	base, ok, err := t.getRound(kvAccountsRound)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if base > rnd {
+		return fmt.Errorf("newRound %d is not after base %d", rnd, base)
+	}
+	err = t.putRound(kvAccountsRound, rnd)
+	if err != nil {
+		return err
+	}
+	return t.putRound(kvHashesRound, hashRound)
+}
+
+func (t *kvTrackerTx) accountsAll() (bals map[basics.Address]basics.AccountData, err error) {
+	bals = make(map[basics.Address]basics.AccountData)
+	prefix := []byte{t.table(kvAccountsTable, false)}
+	for _, key := range t.keys(prefix) {
+		buf, err := t.tx.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		var data basics.AccountData
+		err = protocol.Decode(buf, &data)
+		if err != nil {
+			return nil, err
+		}
+		var addr basics.Address
+		copy(addr[:], key[len(prefix):])
+		bals[addr] = data
+	}
+
+	for _, key := range t.keys([]byte{t.table(kvResourcesTable, false)}) {
+		buf, err := t.tx.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		var rdata basics.AccountData
+		err = protocol.Decode(buf, &rdata)
+		if err != nil {
+			return nil, err
+		}
+		addr, _, _ := kvResourceKeyRecord(key)
+		data, ok := bals[addr]
+		if !ok {
+			return nil, fmt.Errorf("Account DB has resources for missing account %v", addr)
+		}
+		mergeResourceData(&data, rdata)
+		bals[addr] = data
+	}
+	return bals, nil
+}
+
+func kvTotalsKey(catchpointStaging bool) []byte {
+	if catchpointStaging {
+		return []byte{kvStagingTotalsTable}
+	}
+	return []byte{kvTotalsTable}
+}
+
+func (t *kvTrackerTx) accountsTotals(catchpointStaging bool) (totals AccountTotals, err error) {
+	buf, err := t.tx.Get(kvTotalsKey(catchpointStaging))
+	if err == kvstore.ErrNotFound {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &totals)
+	return
+}
+
+func (t *kvTrackerTx) accountsPutTotals(totals AccountTotals, catchpointStaging bool) error {
+	return t.tx.Put(kvTotalsKey(catchpointStaging), protocol.Encode(&totals))
+}
+
+func (t *kvTrackerTx) accountsNewRound(updates map[basics.Address]accountDelta, rewardsLevel uint64, proto config.ConsensusParams) error {
+	var ot basics.OverflowTracker
+	totals, err := t.accountsTotals(false)
+	if err != nil {
+		return err
+	}
+
+	totals.applyRewards(rewardsLevel, &ot)
+
+	accountsTable := t.table(kvAccountsTable, false)
+	resourcesTable := t.table(kvResourcesTable, false)
+	creatorsTable := t.table(kvCreatorsTable, false)
+	for addr, data := range updates {
+		records := getChangedRecords(data)
+		if records.base != nil {
+			if len(records.newBase) == 0 {
+				// prune empty accounts
+				err = t.tx.Delete(kvKey(accountsTable, addr[:]))
+			} else {
+				err = t.tx.Put(kvKey(accountsTable, addr[:]), records.newBase)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// only the resources that changed are rewritten
+		for key, rdelta := range records.resources {
+			if len(rdelta.new) == 0 {
+				err = t.tx.Delete(kvResourceKey(resourcesTable, addr, key.cidx, key.ctype))
+			} else {
+				err = t.tx.Put(kvResourceKey(resourcesTable, addr, key.cidx, key.ctype), rdelta.new)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		totals.delAccount(proto, data.old, &ot)
+		totals.addAccount(proto, data.new, &ot)
+
+		for cidx, delta := range getChangedCreatables(addr, data) {
+			if delta.created {
+				err = t.tx.Put(kvCreatorKey(creatorsTable, cidx, delta.ctype), addr[:])
+			} else {
+				err = t.tx.Delete(kvCreatorKey(creatorsTable, cidx, delta.ctype))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if ot.Overflowed {
+		return fmt.Errorf("overflow computing totals")
+	}
+	return t.accountsPutTotals(totals, false)
+}
+
+func (t *kvTrackerTx) encodedAccountsRange(catchpointStaging bool, startAccountIndex, accountCount int) ([]encodedBalanceRecord, error) {
+	prefix := []byte{t.table(kvAccountsTable, catchpointStaging)}
+	keys := t.keysRange(prefix, startAccountIndex, accountCount)
+	bals := make([]encodedBalanceRecord, 0, len(keys))
+	for _, key := range keys {
+		buf, err := t.tx.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		var addr basics.Address
+		copy(addr[:], key[len(prefix):])
+		bals = append(bals, encodedBalanceRecord{Address: addr, AccountData: buf})
+	}
+	return bals, nil
+}
+
+func (t *kvTrackerTx) encodedResourcesRange(catchpointStaging bool, startResourceIndex, resourceCount int) ([]encodedResourceRecord, error) {
+	keys := t.keysRange([]byte{t.table(kvResourcesTable, catchpointStaging)}, startResourceIndex, resourceCount)
+	resources := make([]encodedResourceRecord, 0, len(keys))
+	for _, key := range keys {
+		buf, err := t.tx.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		var record encodedResourceRecord
+		record.Address, record.CreatableIndex, record.CreatableType = kvResourceKeyRecord(key)
+		record.Data = buf
+		resources = append(resources, record)
+	}
+	return resources, nil
+}
+
+func (t *kvTrackerTx) encodedFullAccountsRange(catchpointStaging bool, startAccountIndex, accountCount int) ([]encodedBalanceRecord, error) {
+	prefix := []byte{t.table(kvAccountsTable, catchpointStaging)}
+	keys := t.keysRange(prefix, startAccountIndex, accountCount)
+	bals := make([]encodedBalanceRecord, 0, len(keys))
+	for _, key := range keys {
+		var addr basics.Address
+		copy(addr[:], key[len(prefix):])
+		data, _, err := t.getAccount(catchpointStaging, addr)
+		if err != nil {
+			return nil, err
+		}
+		bals = append(bals, encodedBalanceRecord{Address: addr, AccountData: protocol.Encode(&data)})
+	}
+	return bals, nil
+}
+
+func (t *kvTrackerTx) totalAccounts(ctx context.Context) (uint64, error) {
+	return t.count([]byte{t.table(kvAccountsTable, false)}), nil
+}
+
+func (t *kvTrackerTx) totalResources(ctx context.Context) (uint64, error) {
+	return t.count([]byte{t.table(kvResourcesTable, false)}), nil
+}
+
+func (t *kvTrackerTx) merkleCommitter(staging bool) (merkletrie.Committer, error) {
+	return kvMerkleCommitter{tx: t.tx, table: t.table(kvHashesTable, staging)}, nil
+}
+
+func (t *kvTrackerTx) resetAccountHashes() error {
+	return t.deletePrefix([]byte{t.table(kvHashesTable, false)})
+}
+
+func (t *kvTrackerTx) getCatchpoint(round basics.Round) (fileName string, catchpoint string, fileSize int64, err error) {
+	buf, err := t.tx.Get(kvKey(kvCatchpointsTable, kvUint64(uint64(round))))
+	if err == kvstore.ErrNotFound {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		return
+	}
+	var cp kvStoredCatchpoint
+	err = protocol.DecodeReflect(buf, &cp)
+	return cp.FileName, cp.Catchpoint, cp.FileSize, err
+}
+
+func (t *kvTrackerTx) storedCatchpointLabels() (map[basics.Round]string, error) {
+	rounds, catchpoints, err := t.storedCatchpoints()
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[basics.Round]string, len(rounds))
+	for i, rnd := range rounds {
+		labels[rnd] = catchpoints[i].Catchpoint
+	}
+	return labels, nil
+}
+
+func (t *kvTrackerTx) resetCatchpointStagingBalances(ctx context.Context, newCatchup bool) error {
+	for _, table := range kvSlottedTables {
+		err := t.deletePrefix([]byte{t.table(table, true)})
+		if err != nil {
+			return err
+		}
+	}
+	return t.tx.Delete(kvTotalsKey(true))
+}
+
+func (t *kvTrackerTx) writeCatchpointStagingBalances(ctx context.Context, bals []encodedBalanceRecord) error {
+	table := t.table(kvAccountsTable, true)
+	for _, balance := range bals {
+		key := kvKey(table, balance.Address[:])
+		if t.tx.Has(key) {
+			return fmt.Errorf("the staged balances already hold account %v", balance.Address)
+		}
+		err := t.tx.Put(key, balance.AccountData)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *kvTrackerTx) writeCatchpointStagingResources(ctx context.Context, resources []encodedResourceRecord) error {
+	table := t.table(kvResourcesTable, true)
+	for _, resource := range resources {
+		key := kvResourceKey(table, resource.Address, resource.CreatableIndex, resource.CreatableType)
+		if t.tx.Has(key) {
+			return fmt.Errorf("the staged resources already hold creatable %d of account %v", resource.CreatableIndex, resource.Address)
+		}
+		err := t.tx.Put(key, resource.Data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *kvTrackerTx) writeCatchpointStagingCreatable(ctx context.Context, addr basics.Address, cidx basics.CreatableIndex, ctype basics.CreatableType) error {
+	return t.tx.Put(kvCreatorKey(t.table(kvCreatorsTable, true), cidx, ctype), addr[:])
+}
+
+func (t *kvTrackerTx) resetCatchpointStagingHashes(ctx context.Context) error {
+	return t.deletePrefix([]byte{t.table(kvHashesTable, true)})
+}
+
+// applyCatchpointStagingBalances swaps the slots of the ledger and of the
+// catchpoint catchup, and deletes the records the ledger had.
+func (t *kvTrackerTx) applyCatchpointStagingBalances(ctx context.Context, balancesRound basics.Round) error {
+	var err error
+	if t.swapped {
+		err = t.tx.Delete(kvSlotsKey)
+	} else {
+		err = t.tx.Put(kvSlotsKey, nil)
+	}
+	if err != nil {
+		return err
+	}
+	t.swapped = !t.swapped
+
+	for _, table := range kvSlottedTables {
+		err = t.deletePrefix([]byte{t.table(table, true)})
+		if err != nil {
+			return err
+		}
+	}
+	// the account history doesn't lead up to the catchpoint round; it gets
+	// started over from it once the ledger is reloaded.
+	err = t.accountsResetHistory()
+	if err != nil {
+		return err
+	}
+	err = t.putRound(kvAccountsRound, balancesRound)
+	if err != nil {
+		return err
+	}
+	return t.putRound(kvHashesRound, balancesRound)
+}
+
+func (t *kvTrackerTx) accountsInitHistory(rnd basics.Round) error {
+	_, ok, err := t.getRound(kvHistoryRound)
+	if err != nil || ok {
+		return err
+	}
+
+	bals, err := t.accountsAll()
+	if err != nil {
+		return err
+	}
+	for addr, data := range bals {
+		err = t.putHistory(addr, rnd, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	totals, err := t.accountsTotals(false)
+	if err != nil {
+		return err
+	}
+	err = t.tx.Put(kvKey(kvTotalsHistoryTable, kvUint64(uint64(rnd))), protocol.Encode(&totals))
+	if err != nil {
+		return err
+	}
+	return t.putRound(kvHistoryRound, rnd)
+}
+
+func (t *kvTrackerTx) putHistory(addr basics.Address, rnd basics.Round, data basics.AccountData) error {
+	err := t.tx.Put(kvHistoryKey(addr, rnd), protocol.Encode(&data))
+	if err != nil {
+		return err
+	}
+	return t.tx.Put(kvHistoryRoundKey(rnd, addr), nil)
+}
+
+func (t *kvTrackerTx) accountsResetHistory() error {
+	for _, table := range []byte{kvHistoryTable, kvHistoryRoundsTable, kvTotalsHistoryTable} {
+		err := t.deletePrefix([]byte{table})
+		if err != nil {
+			return err
+		}
+	}
+	return t.tx.Delete(kvKey(kvRoundsTable, []byte(kvHistoryRound)))
+}
+
+func (t *kvTrackerTx) accountsHistoryRound() (basics.Round, error) {
+	rnd, ok, err := t.getRound(kvHistoryRound)
+	if err == nil && !ok {
+		err = sql.ErrNoRows
+	}
+	return rnd, err
+}
+
+func (t *kvTrackerTx) accountsHistoryNewRound(rnd basics.Round, updates map[basics.Address]accountDelta, totals AccountTotals) error {
+	for addr, delta := range updates {
+		err := t.putHistory(addr, rnd, delta.new)
+		if err != nil {
+			return err
+		}
+	}
+	return t.tx.Put(kvKey(kvTotalsHistoryTable, kvUint64(uint64(rnd))), protocol.Encode(&totals))
+}
+
+func (t *kvTrackerTx) accountsHistoryTotals(rnd basics.Round) (totals AccountTotals, err error) {
+	buf, err := t.tx.Get(kvKey(kvTotalsHistoryTable, kvUint64(uint64(rnd))))
+	if err == kvstore.ErrNotFound {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &totals)
+	return
+}
+
+// historyLookup is accountHistoryDbQueries.lookup.
+func (t *kvTrackerTx) historyLookup(addr basics.Address, rnd basics.Round) (data basics.AccountData, err error) {
+	key, ok := t.tx.Last(kvHistoryKey(addr, 0), kvPrefixEnd(kvHistoryKey(addr, rnd)))
+	if !ok {
+		return
+	}
+	buf, err := t.tx.Get(key)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &data)
+	return
+}
+
+// historyModified is accountHistoryDbQueries.modified.
+func (t *kvTrackerTx) historyModified(rnd basics.Round) (map[basics.Address]basics.AccountData, error) {
+	accts := make(map[basics.Address]basics.AccountData)
+	prefix := kvKey(kvHistoryRoundsTable, kvUint64(uint64(rnd)))
+	for _, key := range t.keys(prefix) {
+		var addr basics.Address
+		copy(addr[:], key[len(prefix):])
+		buf, err := t.tx.Get(kvHistoryKey(addr, rnd))
+		if err != nil {
+			return nil, err
+		}
+		var data basics.AccountData
+		err = protocol.Decode(buf, &data)
+		if err != nil {
+			return nil, err
+		}
+		accts[addr] = data
+	}
+	return accts, nil
+}
+
+func (t *kvTrackerTx) snapshotAccountsCurrent(visit func(basics.Address, basics.AccountData) error) error {
+	prefix := []byte{t.table(kvAccountsTable, false)}
+	var err error
+	t.tx.Ascend(prefix, kvPrefixEnd(prefix), func(key []byte) bool {
+		var addr basics.Address
+		copy(addr[:], key[len(prefix):])
+		var data basics.AccountData
+		data, _, err = t.getAccount(false, addr)
+		if err == nil {
+			err = visit(addr, data)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (t *kvTrackerTx) snapshotAccountsHistory(rnd basics.Round, visit func(basics.Address, basics.AccountData) error) error {
+	// the history of each address is ordered by round, so the state of an
+	// account at rnd is the last of its records up to rnd.
+	prefix := []byte{kvHistoryTable}
+	var err error
+	var last []byte
+	flush := func() {
+		if last == nil {
+			return
+		}
+		var addr basics.Address
+		copy(addr[:], last[len(prefix):])
+		var buf []byte
+		buf, err = t.tx.Get(last)
+		if err != nil {
+			return
+		}
+		var data basics.AccountData
+		err = protocol.Decode(buf, &data)
+		// the accounts deleted by the time of rnd are recorded as empty.
+		if err == nil && !data.IsZero() {
+			err = visit(addr, data)
+		}
+		last = nil
+	}
+	t.tx.Ascend(prefix, kvPrefixEnd(prefix), func(key []byte) bool {
+		addrEnd := len(prefix) + len(basics.Address{})
+		if last != nil && string(key[:addrEnd]) != string(last[:addrEnd]) {
+			flush()
+			if err != nil {
+				return false
+			}
+		}
+		if basics.Round(binary.BigEndian.Uint64(key[addrEnd:])) <= rnd {
+			last = append(last[:0], key...)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	flush()
+	return err
+}
+
+// kvMerkleCommitter stores the pages of a balances trie in a table of the
+// kv tracker database.
+type kvMerkleCommitter struct {
+	tx    *kvstore.Tx
+	table byte
+}
+
+// StorePage stores a single page.
+func (mc kvMerkleCommitter) StorePage(page uint64, content []byte) error {
+	key := kvKey(mc.table, kvUint64(page))
+	if len(content) == 0 {
+		return mc.tx.Delete(key)
+	}
+	return mc.tx.Put(key, content)
+}
+
+// LoadPage loads a single page.
+func (mc kvMerkleCommitter) LoadPage(page uint64) (content []byte, err error) {
+	content, err = mc.tx.Get(kvKey(mc.table, kvUint64(page)))
+	if err == kvstore.ErrNotFound {
+		return nil, nil
+	}
+	return content, err
+}
+
+// GetNodesCountPerPage returns the page size ( number of nodes per page )
+func (mc kvMerkleCommitter) GetNodesCountPerPage() (pageSize int64) {
+	return merkleCommitterNodesPerPage
+}
@@ -18,7 +18,6 @@ package ledger
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"io"
 	"os"
@@ -39,8 +38,8 @@ type Ledger struct {
 	// Database connections to the DBs storing blocks and tracker state.
 	// We use potentially different databases to avoid SQLite contention
 	// during catchup.
-	trackerDBs dbPair
-	blockDBs   dbPair
+	trackerDBs trackerStore
+	blockDBs   blockStore
 
 	// blockQ is the buffer of added blocks that will be flushed to
 	// persistent storage
@@ -52,6 +51,14 @@ type Ledger struct {
 	// (archival mode) or trims older blocks to save space (non-archival).
 	archival bool
 
+	// blockRetention lists the blocks a non-archival ledger keeps on top of
+	// the ones its trackers need.
+	blockRetention blockRetentionPolicy
+
+	// prefetch determines whether the accounts a block accesses are loaded
+	// concurrently before it's evaluated.
+	prefetch bool
+
 	// genesisHash stores the genesis hash for this ledger.
 	genesisHash crypto.Digest
 
@@ -91,6 +98,8 @@ func OpenLedger(
 	l := &Ledger{
 		log:             log,
 		archival:        cfg.Archival,
+		blockRetention:  makeBlockRetentionPolicy(cfg),
+		prefetch:        cfg.EnableLedgerPrefetch,
 		genesisHash:     genesisInitState.GenesisHash,
 		genesisAccounts: genesisInitState.Accounts,
 		genesisProto:    config.Consensus[genesisInitState.Block.CurrentProtocol],
@@ -104,17 +113,25 @@ func OpenLedger(
 		}
 	}()
 
-	l.trackerDBs, l.blockDBs, err = openLedgerDB(dbPathPrefix, dbMem)
+	l.trackerDBs, l.blockDBs, err = openLedgerDB(dbPathPrefix, dbMem, cfg.TrackerStorageBackend, cfg.BlockStorageBackend)
 	if err != nil {
 		err = fmt.Errorf("OpenLedger.openLedgerDB %v", err)
 		return nil, err
 	}
-	l.trackerDBs.rdb.SetLogger(log)
-	l.trackerDBs.wdb.SetLogger(log)
-	l.blockDBs.rdb.SetLogger(log)
-	l.blockDBs.wdb.SetLogger(log)
+	l.trackerDBs.setLogger(log)
+	l.blockDBs.setLogger(log)
+
+	if !cfg.Archival {
+		// the blocks databases of older non-archival ledgers don't give the
+		// space of the pruned blocks back to the file system.
+		err = l.blockDBs.enableReclaimSpace()
+		if err != nil {
+			err = fmt.Errorf("OpenLedger.enableReclaimSpace %v", err)
+			return nil, err
+		}
+	}
 
-	err = l.blockDBs.wdb.Atomic(func(tx *sql.Tx) error {
+	err = l.blockDBs.write(func(tx blockTx) error {
 		return initBlocksDB(tx, l, []bookkeeping.Block{genesisInitState.Block}, cfg.Archival)
 	})
 	if err != nil {
@@ -176,13 +193,13 @@ func (l *Ledger) reloadLedger() error {
 // verifyMatchingGenesisHash tests to see that the latest block header pointing to the same genesis hash provided in genesisHash.
 func (l *Ledger) verifyMatchingGenesisHash() (err error) {
 	// Check that the genesis hash, if present, matches.
-	err = l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
-		latest, err := blockLatest(tx)
+	err = l.blockDBs.read(func(tx blockTx) error {
+		latest, err := tx.latest()
 		if err != nil {
 			return err
 		}
 
-		hdr, err := blockGetHdr(tx, latest)
+		hdr, err := tx.getHdr(latest)
 		if err != nil {
 			return err
 		}
@@ -199,12 +216,11 @@ func (l *Ledger) verifyMatchingGenesisHash() (err error) {
 	return
 }
 
-func openLedgerDB(dbPathPrefix string, dbMem bool) (trackerDBs dbPair, blockDBs dbPair, err error) {
+// ledgerDBFilenames returns the names of the tracker and blocks database
+// files of the ledger stored at dbPathPrefix.
+func ledgerDBFilenames(dbPathPrefix string, dbMem bool) (trackerDBFilename string, blockDBFilename string, err error) {
 	// Backwards compatibility: we used to store both blocks and tracker
 	// state in a single SQLite db file.
-	var trackerDBFilename string
-	var blockDBFilename string
-
 	commonDBFilename := dbPathPrefix + ".sqlite"
 	if !dbMem {
 		_, err = os.Stat(commonDBFilename)
@@ -212,24 +228,26 @@ func openLedgerDB(dbPathPrefix string, dbMem bool) (trackerDBs dbPair, blockDBs
 
 	if !dbMem && os.IsNotExist(err) {
 		// No common file, so use two separate files for blocks and tracker.
-		trackerDBFilename = dbPathPrefix + ".tracker.sqlite"
-		blockDBFilename = dbPathPrefix + ".block.sqlite"
+		return dbPathPrefix + ".tracker.sqlite", dbPathPrefix + ".block.sqlite", nil
 	} else if err == nil {
 		// Legacy common file exists (or testing in-memory, where performance
 		// doesn't matter), use same database for everything.
-		trackerDBFilename = commonDBFilename
-		blockDBFilename = commonDBFilename
-	} else {
-		return
+		return commonDBFilename, commonDBFilename, nil
 	}
+	return
+}
 
-	trackerDBs, err = dbOpen(trackerDBFilename, dbMem)
+// openLedgerDB opens the tracker and blocks databases of the ledger stored at
+// dbPathPrefix with the given backends.
+func openLedgerDB(dbPathPrefix string, dbMem bool, trackerBackend string, blockBackend string) (trackerDBs trackerStore, blockDBs blockStore, err error) {
+	trackerDBs, err = openTrackerStore(trackerBackend, dbPathPrefix, false, dbMem)
 	if err != nil {
 		return
 	}
 
-	blockDBs, err = dbOpen(blockDBFilename, dbMem)
+	blockDBs, err = openBlockStore(blockBackend, dbPathPrefix, false, dbMem)
 	if err != nil {
+		trackerDBs.close()
 		return
 	}
 	return
@@ -239,8 +257,8 @@ func openLedgerDB(dbPathPrefix string, dbMem bool) (trackerDBs dbPair, blockDBs
 // - creates and populates it with genesis blocks
 // - ensures DB is in good shape for archival mode and resets it if not
 // - does nothing if everything looks good
-func initBlocksDB(tx *sql.Tx, l *Ledger, initBlocks []bookkeeping.Block, isArchival bool) (err error) {
-	err = blockInit(tx, initBlocks)
+func initBlocksDB(tx blockTx, l *Ledger, initBlocks []bookkeeping.Block, isArchival bool) (err error) {
+	err = tx.init(initBlocks)
 	if err != nil {
 		err = fmt.Errorf("initBlocksDB.blockInit %v", err)
 		return err
@@ -248,7 +266,7 @@ func initBlocksDB(tx *sql.Tx, l *Ledger, initBlocks []bookkeeping.Block, isArchi
 
 	// in archival mode check if DB contains all blocks up to the latest
 	if isArchival {
-		earliest, err := blockEarliest(tx)
+		earliest, err := tx.earliest()
 		if err != nil {
 			err = fmt.Errorf("initBlocksDB.blockEarliest %v", err)
 			return err
@@ -258,12 +276,12 @@ func initBlocksDB(tx *sql.Tx, l *Ledger, initBlocks []bookkeeping.Block, isArchi
 		// So reset the DB and init it again
 		if earliest != basics.Round(0) {
 			l.log.Warnf("resetting blocks DB (earliest block is %v)", earliest)
-			err := blockResetDB(tx)
+			err := tx.reset()
 			if err != nil {
 				err = fmt.Errorf("initBlocksDB.blockResetDB %v", err)
 				return err
 			}
-			err = blockInit(tx, initBlocks)
+			err = tx.init(initBlocks)
 			if err != nil {
 				err = fmt.Errorf("initBlocksDB.blockInit 2 %v", err)
 				return err
@@ -286,8 +304,12 @@ func (l *Ledger) Close() {
 	l.trackers.close()
 
 	// last, we close the underlaying database connections.
-	l.blockDBs.close()
-	l.trackerDBs.close()
+	if l.blockDBs != nil {
+		l.blockDBs.close()
+	}
+	if l.trackerDBs != nil {
+		l.trackerDBs.close()
+	}
 }
 
 // RegisterBlockListeners registers listeners that will be called when a
@@ -312,6 +334,12 @@ func (l *Ledger) notifyCommit(r basics.Round) basics.Round {
 	return minToSave
 }
 
+// BlockRetentionStatus reports the blocks held by the ledger, and the
+// progress of the pruning of the blocks it no longer keeps.
+func (l *Ledger) BlockRetentionStatus() BlockRetentionStatus {
+	return l.blockQ.pruner.status()
+}
+
 // GetLastCatchpointLabel returns the latest catchpoint label that was written to the
 // database.
 func (l *Ledger) GetLastCatchpointLabel() string {
@@ -326,7 +354,7 @@ func (l *Ledger) GetLastCatchpointLabel() string {
 func (l *Ledger) GetAssetCreatorForRound(rnd basics.Round, assetIdx basics.AssetIndex) (basics.Address, error) {
 	l.trackerMu.RLock()
 	defer l.trackerMu.RUnlock()
-	return l.accts.getAssetCreatorForRound(rnd, assetIdx)
+	return l.getAssetCreatorForRound(rnd, assetIdx)
 }
 
 // GetAssetCreator is like GetAssetCreatorForRound, but for the latest round
@@ -334,7 +362,42 @@ func (l *Ledger) GetAssetCreatorForRound(rnd basics.Round, assetIdx basics.Asset
 func (l *Ledger) GetAssetCreator(assetIdx basics.AssetIndex) (basics.Address, error) {
 	l.trackerMu.RLock()
 	defer l.trackerMu.RUnlock()
-	return l.accts.getAssetCreatorForRound(l.blockQ.latest(), assetIdx)
+	return l.getAssetCreatorForRound(l.blockQ.latest(), assetIdx)
+}
+
+func (l *Ledger) getAssetCreatorForRound(rnd basics.Round, assetIdx basics.AssetIndex) (basics.Address, error) {
+	creator, ok, err := l.accts.getCreatorForRound(rnd, basics.CreatableIndex(assetIdx), basics.AssetCreatable)
+	if err != nil {
+		return basics.Address{}, err
+	}
+	if !ok {
+		return basics.Address{}, fmt.Errorf("asset %d does not exist or has been deleted", assetIdx)
+	}
+	return creator, nil
+}
+
+// GetCreatorForRound looks up the creator of an asset or application given
+// its numerical index. ok is false if no such creatable exists as of rnd.
+func (l *Ledger) GetCreatorForRound(rnd basics.Round, cidx basics.CreatableIndex, ctype basics.CreatableType) (creator basics.Address, ok bool, err error) {
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.accts.getCreatorForRound(rnd, cidx, ctype)
+}
+
+// GetCreator is like GetCreatorForRound, but for the latest round and race
+// free with respect to ledger.Latest()
+func (l *Ledger) GetCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (creator basics.Address, ok bool, err error) {
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.accts.getCreatorForRound(l.blockQ.latest(), cidx, ctype)
+}
+
+// GetAppCreator looks up the creator of an application given its numerical
+// index. ok is false if no such application exists as of rnd.
+func (l *Ledger) GetAppCreator(rnd basics.Round, appIdx basics.AppIndex) (creator basics.Address, ok bool, err error) {
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.accts.getCreatorForRound(rnd, basics.CreatableIndex(appIdx), basics.AppCreatable)
 }
 
 // ListAssets takes a maximum asset index and maximum result length, and
@@ -343,7 +406,27 @@ func (l *Ledger) GetAssetCreator(assetIdx basics.AssetIndex) (basics.Address, er
 func (l *Ledger) ListAssets(maxAssetIdx basics.AssetIndex, maxResults uint64) (results []basics.CreatableLocator, err error) {
 	l.trackerMu.RLock()
 	defer l.trackerMu.RUnlock()
-	return l.accts.listAssets(maxAssetIdx, maxResults)
+	return l.accts.listCreatables(basics.CreatableIndex(maxAssetIdx), maxResults, basics.AssetCreatable)
+}
+
+// ListApplications takes a maximum application index and maximum result
+// length, and returns up to that many applications from the database where
+// the application id is less than or equal to the maximum.
+func (l *Ledger) ListApplications(maxAppIdx basics.AppIndex, maxResults uint64) (results []basics.CreatableLocator, err error) {
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.accts.listCreatables(basics.CreatableIndex(maxAppIdx), maxResults, basics.AppCreatable)
+}
+
+// LookupDelta returns the new state of the accounts modified by round rnd,
+// as its StateDelta's ModifiedAccounts does. The changes are available for
+// the rounds that were not yet flushed to disk, which are at least the last
+// MaxBalLookback rounds, and for the rounds of the account history of the
+// archival ledgers that enable it; other ones fail with ErrDeltaNotAvailable.
+func (l *Ledger) LookupDelta(rnd basics.Round) (map[basics.Address]basics.AccountData, error) {
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.accts.lookupDelta(rnd)
 }
 
 // Lookup uses the accounts tracker to return the account state for a
@@ -522,12 +605,12 @@ func (l *Ledger) GetCatchpointStream(round basics.Round) (io.ReadCloser, error)
 }
 
 // ledgerForTracker methods
-func (l *Ledger) trackerDB() dbPair {
+func (l *Ledger) trackerDB() trackerStore {
 	return l.trackerDBs
 }
 
 // ledgerForTracker methods
-func (l *Ledger) blockDB() dbPair {
+func (l *Ledger) blockDB() blockStore {
 	return l.blockDBs
 }
 
@@ -209,6 +209,43 @@ func TestLedgerBasic(t *testing.T) {
 	defer l.Close()
 }
 
+func TestLedgerApplications(t *testing.T) {
+	genesisInitState, _ := testGenerateInitState(t, protocol.ConsensusCurrentVersion)
+	creator := testPoolAddr
+	data := genesisInitState.Accounts[creator]
+	data.AppParams = map[basics.AppIndex]basics.AppParams{3: {}, 5: {}, 8: {}}
+	data.AssetParams = map[basics.AssetIndex]basics.AssetParams{4: {Total: 1}}
+	genesisInitState.Accounts[creator] = data
+
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	log := logging.TestingLog(t)
+	l, err := OpenLedger(log, t.Name(), inMem, genesisInitState, cfg)
+	require.NoError(t, err, "could not open ledger")
+	defer l.Close()
+
+	apps, err := l.ListApplications(7, 10)
+	require.NoError(t, err)
+	require.Equal(t, []basics.CreatableLocator{
+		{Type: basics.AppCreatable, Creator: creator, Index: 5},
+		{Type: basics.AppCreatable, Creator: creator, Index: 3},
+	}, apps)
+
+	apps, err = l.ListApplications(100, 1)
+	require.NoError(t, err)
+	require.Equal(t, []basics.CreatableLocator{{Type: basics.AppCreatable, Creator: creator, Index: 8}}, apps)
+
+	addr, ok, err := l.GetAppCreator(l.Latest(), 5)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, creator, addr)
+
+	// asset 4 isn't an application.
+	_, ok, err = l.GetAppCreator(l.Latest(), 4)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
 func TestLedgerBlockHeaders(t *testing.T) {
 	a := require.New(t)
 
@@ -47,6 +47,14 @@ import (
 //           |-----> (*) Msgsize
 //           |-----> (*) MsgIsZero
 //
+// catchpointFileResourcesChunk
+//               |-----> (*) MarshalMsg
+//               |-----> (*) CanMarshalMsg
+//               |-----> (*) UnmarshalMsg
+//               |-----> (*) CanUnmarshalMsg
+//               |-----> (*) Msgsize
+//               |-----> (*) MsgIsZero
+//
 // catchpointState
 //        |-----> MarshalMsg
 //        |-----> CanMarshalMsg
@@ -63,6 +71,14 @@ import (
 //           |-----> (*) Msgsize
 //           |-----> (*) MsgIsZero
 //
+// encodedResourceRecord
+//           |-----> (*) MarshalMsg
+//           |-----> (*) CanMarshalMsg
+//           |-----> (*) UnmarshalMsg
+//           |-----> (*) CanUnmarshalMsg
+//           |-----> (*) Msgsize
+//           |-----> (*) MsgIsZero
+//
 
 // MarshalMsg implements msgp.Marshaler
 func (z *AccountTotals) MarshalMsg(b []byte) (o []byte, err error) {
@@ -1200,8 +1216,8 @@ func (z *catchpointFileBalancesChunk) MsgIsZero() bool {
 func (z *catchpointFileHeader) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0001Len := uint32(8)
-	var zb0001Mask uint16 /* 9 bits */
+	zb0001Len := uint32(10)
+	var zb0001Mask uint16 /* 11 bits */
 	if (*z).Totals.MsgIsZero() {
 		zb0001Len--
 		zb0001Mask |= 0x2
@@ -1230,10 +1246,18 @@ func (z *catchpointFileHeader) MarshalMsg(b []byte) (o []byte, err error) {
 		zb0001Len--
 		zb0001Mask |= 0x80
 	}
-	if (*z).Version == 0 {
+	if (*z).TotalResChunks == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x100
 	}
+	if (*z).TotalResources == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x200
+	}
+	if (*z).Version == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x400
+	}
 	// variable map header, size zb0001Len
 	o = append(o, 0x80|uint8(zb0001Len))
 	if zb0001Len != 0 {
@@ -1289,6 +1313,16 @@ func (z *catchpointFileHeader) MarshalMsg(b []byte) (o []byte, err error) {
 			o = msgp.AppendUint64(o, (*z).TotalChunks)
 		}
 		if (zb0001Mask & 0x100) == 0 { // if not empty
+			// string "resourceChunksCount"
+			o = append(o, 0xb3, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
+			o = msgp.AppendUint64(o, (*z).TotalResChunks)
+		}
+		if (zb0001Mask & 0x200) == 0 { // if not empty
+			// string "resourcesCount"
+			o = append(o, 0xae, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
+			o = msgp.AppendUint64(o, (*z).TotalResources)
+		}
+		if (zb0001Mask & 0x400) == 0 { // if not empty
 			// string "version"
 			o = append(o, 0xa7, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e)
 			o = msgp.AppendUint64(o, (*z).Version)
@@ -1363,6 +1397,22 @@ func (z *catchpointFileHeader) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				return
 			}
 		}
+		if zb0001 > 0 {
+			zb0001--
+			(*z).TotalResources, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalResources")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			(*z).TotalResChunks, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalResChunks")
+				return
+			}
+		}
 		if zb0001 > 0 {
 			zb0001--
 			(*z).Catchpoint, bts, err = msgp.ReadStringBytes(bts)
@@ -1438,6 +1488,18 @@ func (z *catchpointFileHeader) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					err = msgp.WrapError(err, "TotalChunks")
 					return
 				}
+			case "resourcesCount":
+				(*z).TotalResources, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalResources")
+					return
+				}
+			case "resourceChunksCount":
+				(*z).TotalResChunks, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalResChunks")
+					return
+				}
 			case "catchpoint":
 				(*z).Catchpoint, bts, err = msgp.ReadStringBytes(bts)
 				if err != nil {
@@ -1470,13 +1532,175 @@ func (_ *catchpointFileHeader) CanUnmarshalMsg(z interface{}) bool {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *catchpointFileHeader) Msgsize() (s int) {
-	s = 1 + 8 + msgp.Uint64Size + 14 + (*z).BalancesRound.Msgsize() + 12 + (*z).BlocksRound.Msgsize() + 14 + (*z).Totals.Msgsize() + 14 + msgp.Uint64Size + 12 + msgp.Uint64Size + 11 + msgp.StringPrefixSize + len((*z).Catchpoint) + 18 + (*z).BlockHeaderDigest.Msgsize()
+	s = 1 + 8 + msgp.Uint64Size + 14 + (*z).BalancesRound.Msgsize() + 12 + (*z).BlocksRound.Msgsize() + 14 + (*z).Totals.Msgsize() + 14 + msgp.Uint64Size + 12 + msgp.Uint64Size + 15 + msgp.Uint64Size + 20 + msgp.Uint64Size + 11 + msgp.StringPrefixSize + len((*z).Catchpoint) + 18 + (*z).BlockHeaderDigest.Msgsize()
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
 func (z *catchpointFileHeader) MsgIsZero() bool {
-	return ((*z).Version == 0) && ((*z).BalancesRound.MsgIsZero()) && ((*z).BlocksRound.MsgIsZero()) && ((*z).Totals.MsgIsZero()) && ((*z).TotalAccounts == 0) && ((*z).TotalChunks == 0) && ((*z).Catchpoint == "") && ((*z).BlockHeaderDigest.MsgIsZero())
+	return ((*z).Version == 0) && ((*z).BalancesRound.MsgIsZero()) && ((*z).BlocksRound.MsgIsZero()) && ((*z).Totals.MsgIsZero()) && ((*z).TotalAccounts == 0) && ((*z).TotalChunks == 0) && ((*z).TotalResources == 0) && ((*z).TotalResChunks == 0) && ((*z).Catchpoint == "") && ((*z).BlockHeaderDigest.MsgIsZero())
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *catchpointFileResourcesChunk) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0002Len := uint32(1)
+	var zb0002Mask uint8 /* 2 bits */
+	if len((*z).Resources) == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x2
+	}
+	// variable map header, size zb0002Len
+	o = append(o, 0x80|uint8(zb0002Len))
+	if zb0002Len != 0 {
+		if (zb0002Mask & 0x2) == 0 { // if not empty
+			// string "rs"
+			o = append(o, 0xa2, 0x72, 0x73)
+			if (*z).Resources == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).Resources)))
+			}
+			for zb0001 := range (*z).Resources {
+				o, err = (*z).Resources[zb0001].MarshalMsg(o)
+				if err != nil {
+					err = msgp.WrapError(err, "Resources", zb0001)
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+func (_ *catchpointFileResourcesChunk) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*catchpointFileResourcesChunk)
+	return ok
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *catchpointFileResourcesChunk) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0002 int
+	var zb0003 bool
+	zb0002, zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if _, ok := err.(msgp.TypeError); ok {
+		zb0002, zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0002 > 0 {
+			zb0002--
+			var zb0004 int
+			var zb0005 bool
+			zb0004, zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Resources")
+				return
+			}
+			if zb0004 > ResourcesPerCatchpointFileChunk {
+				err = msgp.ErrOverflow(uint64(zb0004), uint64(ResourcesPerCatchpointFileChunk))
+				err = msgp.WrapError(err, "struct-from-array", "Resources")
+				return
+			}
+			if zb0005 {
+				(*z).Resources = nil
+			} else if (*z).Resources != nil && cap((*z).Resources) >= zb0004 {
+				(*z).Resources = ((*z).Resources)[:zb0004]
+			} else {
+				(*z).Resources = make([]encodedResourceRecord, zb0004)
+			}
+			for zb0001 := range (*z).Resources {
+				bts, err = (*z).Resources[zb0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001)
+					return
+				}
+			}
+		}
+		if zb0002 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0002)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array")
+				return
+			}
+		}
+	} else {
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0003 {
+			(*z) = catchpointFileResourcesChunk{}
+		}
+		for zb0002 > 0 {
+			zb0002--
+			field, bts, err = msgp.ReadMapKeyZC(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+			switch string(field) {
+			case "rs":
+				var zb0006 int
+				var zb0007 bool
+				zb0006, zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Resources")
+					return
+				}
+				if zb0006 > ResourcesPerCatchpointFileChunk {
+					err = msgp.ErrOverflow(uint64(zb0006), uint64(ResourcesPerCatchpointFileChunk))
+					err = msgp.WrapError(err, "Resources")
+					return
+				}
+				if zb0007 {
+					(*z).Resources = nil
+				} else if (*z).Resources != nil && cap((*z).Resources) >= zb0006 {
+					(*z).Resources = ((*z).Resources)[:zb0006]
+				} else {
+					(*z).Resources = make([]encodedResourceRecord, zb0006)
+				}
+				for zb0001 := range (*z).Resources {
+					bts, err = (*z).Resources[zb0001].UnmarshalMsg(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "Resources", zb0001)
+						return
+					}
+				}
+			default:
+				err = msgp.ErrNoField(string(field))
+				if err != nil {
+					err = msgp.WrapError(err)
+					return
+				}
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+func (_ *catchpointFileResourcesChunk) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*catchpointFileResourcesChunk)
+	return ok
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *catchpointFileResourcesChunk) Msgsize() (s int) {
+	s = 1 + 3 + msgp.ArrayHeaderSize
+	for zb0001 := range (*z).Resources {
+		s += (*z).Resources[zb0001].Msgsize()
+	}
+	return
+}
+
+// MsgIsZero returns whether this is a zero value
+func (z *catchpointFileResourcesChunk) MsgIsZero() bool {
+	return (len((*z).Resources) == 0)
 }
 
 // MarshalMsg implements msgp.Marshaler
@@ -1661,3 +1885,194 @@ func (z *encodedBalanceRecord) Msgsize() (s int) {
 func (z *encodedBalanceRecord) MsgIsZero() bool {
 	return ((*z).Address.MsgIsZero()) && ((*z).AccountData.MsgIsZero())
 }
+
+// MarshalMsg implements msgp.Marshaler
+func (z *encodedResourceRecord) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0001Len := uint32(4)
+	var zb0001Mask uint8 /* 5 bits */
+	if (*z).CreatableIndex.MsgIsZero() {
+		zb0001Len--
+		zb0001Mask |= 0x2
+	}
+	if (*z).CreatableType.MsgIsZero() {
+		zb0001Len--
+		zb0001Mask |= 0x4
+	}
+	if (*z).Address.MsgIsZero() {
+		zb0001Len--
+		zb0001Mask |= 0x8
+	}
+	if (*z).Data.MsgIsZero() {
+		zb0001Len--
+		zb0001Mask |= 0x10
+	}
+	// variable map header, size zb0001Len
+	o = append(o, 0x80|uint8(zb0001Len))
+	if zb0001Len != 0 {
+		if (zb0001Mask & 0x2) == 0 { // if not empty
+			// string "ci"
+			o = append(o, 0xa2, 0x63, 0x69)
+			o, err = (*z).CreatableIndex.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "CreatableIndex")
+				return
+			}
+		}
+		if (zb0001Mask & 0x4) == 0 { // if not empty
+			// string "ct"
+			o = append(o, 0xa2, 0x63, 0x74)
+			o, err = (*z).CreatableType.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "CreatableType")
+				return
+			}
+		}
+		if (zb0001Mask & 0x8) == 0 { // if not empty
+			// string "pk"
+			o = append(o, 0xa2, 0x70, 0x6b)
+			o, err = (*z).Address.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "Address")
+				return
+			}
+		}
+		if (zb0001Mask & 0x10) == 0 { // if not empty
+			// string "rd"
+			o = append(o, 0xa2, 0x72, 0x64)
+			o, err = (*z).Data.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "Data")
+				return
+			}
+		}
+	}
+	return
+}
+
+func (_ *encodedResourceRecord) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*encodedResourceRecord)
+	return ok
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *encodedResourceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 int
+	var zb0002 bool
+	zb0001, zb0002, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if _, ok := err.(msgp.TypeError); ok {
+		zb0001, zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0001 > 0 {
+			zb0001--
+			bts, err = (*z).Address.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Address")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			bts, err = (*z).CreatableIndex.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "CreatableIndex")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			bts, err = (*z).CreatableType.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "CreatableType")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			bts, err = (*z).Data.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Data")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0001)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array")
+				return
+			}
+		}
+	} else {
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0002 {
+			(*z) = encodedResourceRecord{}
+		}
+		for zb0001 > 0 {
+			zb0001--
+			field, bts, err = msgp.ReadMapKeyZC(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+			switch string(field) {
+			case "pk":
+				bts, err = (*z).Address.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Address")
+					return
+				}
+			case "ci":
+				bts, err = (*z).CreatableIndex.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "CreatableIndex")
+					return
+				}
+			case "ct":
+				bts, err = (*z).CreatableType.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "CreatableType")
+					return
+				}
+			case "rd":
+				bts, err = (*z).Data.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Data")
+					return
+				}
+			default:
+				err = msgp.ErrNoField(string(field))
+				if err != nil {
+					err = msgp.WrapError(err)
+					return
+				}
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+func (_ *encodedResourceRecord) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*encodedResourceRecord)
+	return ok
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *encodedResourceRecord) Msgsize() (s int) {
+	s = 1 + 3 + (*z).Address.Msgsize() + 3 + (*z).CreatableIndex.Msgsize() + 3 + (*z).CreatableType.Msgsize() + 3 + (*z).Data.Msgsize()
+	return
+}
+
+// MsgIsZero returns whether this is a zero value
+func (z *encodedResourceRecord) MsgIsZero() bool {
+	return ((*z).Address.MsgIsZero()) && ((*z).CreatableIndex.MsgIsZero()) && ((*z).CreatableType.MsgIsZero()) && ((*z).Data.MsgIsZero())
+}
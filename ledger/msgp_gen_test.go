@@ -259,6 +259,68 @@ func BenchmarkUnmarshalcatchpointFileHeader(b *testing.B) {
 	}
 }
 
+func TestMarshalUnmarshalcatchpointFileResourcesChunk(t *testing.T) {
+	v := catchpointFileResourcesChunk{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func TestRandomizedEncodingcatchpointFileResourcesChunk(t *testing.T) {
+	protocol.RunEncodingTest(t, &catchpointFileResourcesChunk{})
+}
+
+func BenchmarkMarshalMsgcatchpointFileResourcesChunk(b *testing.B) {
+	v := catchpointFileResourcesChunk{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgcatchpointFileResourcesChunk(b *testing.B) {
+	v := catchpointFileResourcesChunk{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalcatchpointFileResourcesChunk(b *testing.B) {
+	v := catchpointFileResourcesChunk{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestMarshalUnmarshalencodedBalanceRecord(t *testing.T) {
 	v := encodedBalanceRecord{}
 	bts, err := v.MarshalMsg(nil)
@@ -320,3 +382,65 @@ func BenchmarkUnmarshalencodedBalanceRecord(b *testing.B) {
 		}
 	}
 }
+
+func TestMarshalUnmarshalencodedResourceRecord(t *testing.T) {
+	v := encodedResourceRecord{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func TestRandomizedEncodingencodedResourceRecord(t *testing.T) {
+	protocol.RunEncodingTest(t, &encodedResourceRecord{})
+}
+
+func BenchmarkMarshalMsgencodedResourceRecord(b *testing.B) {
+	v := encodedResourceRecord{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgencodedResourceRecord(b *testing.B) {
+	v := encodedResourceRecord{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalencodedResourceRecord(b *testing.B) {
+	v := encodedResourceRecord{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
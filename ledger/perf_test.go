@@ -19,6 +19,9 @@ package ledger
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/algorand/go-deadlock"
@@ -129,7 +132,18 @@ func BenchmarkManyAccounts(b *testing.B) {
 	}
 }
 
+// BenchmarkValidate measures the block validation latency, with and without
+// prefetching the accounts of the block ahead of its evaluation.
 func BenchmarkValidate(b *testing.B) {
+	b.Run("prefetch", func(b *testing.B) {
+		benchmarkValidate(b, true)
+	})
+	b.Run("noprefetch", func(b *testing.B) {
+		benchmarkValidate(b, false)
+	})
+}
+
+func benchmarkValidate(b *testing.B, prefetch bool) {
 	b.StopTimer()
 
 	genesisInitState, addrs, keys := genesis(10000)
@@ -141,6 +155,7 @@ func BenchmarkValidate(b *testing.B) {
 	const inMem = true
 	cfg := config.GetDefaultLocal()
 	cfg.Archival = true
+	cfg.EnableLedgerPrefetch = prefetch
 	l, err := OpenLedger(logging.Base(), dbName, inMem, genesisInitState, cfg)
 	require.NoError(b, err)
 	defer l.Close()
@@ -152,36 +167,33 @@ func BenchmarkValidate(b *testing.B) {
 		proto, ok := config.Consensus[newblk.CurrentProtocol]
 		require.True(b, ok)
 
-		var txbytes int
-		for i := 0; i < 10000; i++ {
+		// let the evaluator fill in the rewards state and the txn root
+		eval, err := l.StartEvaluator(newblk.BlockHeader, 0)
+		require.NoError(b, err)
+		for i := 0; i < len(addrs); i++ {
 			t := transactions.Transaction{
 				Type: protocol.PaymentTx,
 				Header: transactions.Header{
-					Sender:     addrs[i],
-					Fee:        basics.MicroAlgos{Raw: 1},
-					FirstValid: newblk.Round(),
-					LastValid:  newblk.Round(),
+					Sender:      addrs[i],
+					Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+					FirstValid:  newblk.Round(),
+					LastValid:   newblk.Round(),
+					GenesisHash: genesisInitState.GenesisHash,
 				},
 				PaymentTxnFields: transactions.PaymentTxnFields{
-					Amount: basics.MicroAlgos{Raw: 1},
+					Receiver: addrs[(i+1)%len(addrs)],
+					Amount:   basics.MicroAlgos{Raw: 1},
 				},
 			}
-			crypto.RandBytes(t.Receiver[:])
-			st := t.Sign(keys[i])
-
-			txib, err := newblk.EncodeSignedTxn(st, transactions.ApplyData{})
-			require.NoError(b, err)
-
-			txlen := len(protocol.Encode(&txib))
-			if txbytes+txlen > proto.MaxTxnBytesPerBlock {
+			err = eval.Transaction(t.Sign(keys[i]), transactions.ApplyData{})
+			if err == ErrNoSpace {
 				break
 			}
-
-			txbytes += txlen
-			newblk.Payset = append(newblk.Payset, txib)
+			require.NoError(b, err)
 		}
-
-		newblk.BlockHeader.TxnRoot = newblk.Payset.Commit(false)
+		vb, err := eval.GenerateBlock()
+		require.NoError(b, err)
+		newblk = vb.Block()
 
 		b.StartTimer()
 		_, err = l.Validate(context.Background(), newblk, nil, backlogPool)
@@ -189,3 +201,183 @@ func BenchmarkValidate(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+// makeBenchmarkBlocks returns n blocks, from round 0 on, holding txns random
+// payments each.
+func makeBenchmarkBlocks(b *testing.B, n int, txns int) []blockEntry {
+	blocks := make([]blockEntry, n)
+	for i := range blocks {
+		blocks[i] = randomBlock(basics.Round(i))
+		for j := 0; j < txns; j++ {
+			var st transactions.SignedTxn
+			crypto.RandBytes(st.Sig[:])
+			st.Txn.Type = protocol.PaymentTx
+			crypto.RandBytes(st.Txn.Sender[:])
+			crypto.RandBytes(st.Txn.Receiver[:])
+			st.Txn.Fee = basics.MicroAlgos{Raw: 1000}
+			st.Txn.Amount = basics.MicroAlgos{Raw: crypto.RandUint64()}
+			txib, err := blocks[i].block.EncodeSignedTxn(st, transactions.ApplyData{})
+			require.NoError(b, err)
+			blocks[i].block.Payset = append(blocks[i].block.Payset, txib)
+		}
+	}
+	return blocks
+}
+
+// BenchmarkBlockStore measures the writing, the reading and the pruning of
+// blocks of 100 payments with each backend of the blocks database.
+func BenchmarkBlockStore(b *testing.B) {
+	const txnsPerBlock = 100
+	for _, backend := range blockStoreBackends {
+		b.Run(backend+"/put", func(b *testing.B) {
+			bs, release := openBenchmarkBlockStore(b, backend)
+			defer release()
+			blocks := makeBenchmarkBlocks(b, b.N, txnsPerBlock)
+
+			b.ResetTimer()
+			for _, e := range blocks {
+				err := putBlocks(bs, []blockEntry{e})
+				require.NoError(b, err)
+			}
+		})
+
+		const stored = 2000
+		b.Run(backend+"/getHdr", func(b *testing.B) {
+			bs, release := openBenchmarkBlockStore(b, backend)
+			defer release()
+			require.NoError(b, putBlocks(bs, makeBenchmarkBlocks(b, stored, txnsPerBlock)))
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				err := bs.read(func(tx blockTx) error {
+					_, err := tx.getHdr(basics.Round(crypto.RandUint64() % stored))
+					return err
+				})
+				require.NoError(b, err)
+			}
+		})
+		b.Run(backend+"/getEncodedCert", func(b *testing.B) {
+			bs, release := openBenchmarkBlockStore(b, backend)
+			defer release()
+			require.NoError(b, putBlocks(bs, makeBenchmarkBlocks(b, stored, txnsPerBlock)))
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				err := bs.read(func(tx blockTx) error {
+					_, _, err := tx.getEncodedCert(basics.Round(crypto.RandUint64() % stored))
+					return err
+				})
+				require.NoError(b, err)
+			}
+		})
+		b.Run(backend+"/forgetRange", func(b *testing.B) {
+			bs, release := openBenchmarkBlockStore(b, backend)
+			defer release()
+			require.NoError(b, putBlocks(bs, makeBenchmarkBlocks(b, b.N+1, txnsPerBlock)))
+
+			// deletes the blocks in batches, the way the block pruner does.
+			b.ResetTimer()
+			for start := 0; start < b.N; start += blockPrunerBatchRounds {
+				end := start + blockPrunerBatchRounds
+				if end > b.N {
+					end = b.N
+				}
+				err := bs.write(func(tx blockTx) error {
+					return tx.forgetRange(basics.Round(start), basics.Round(end), 0)
+				})
+				require.NoError(b, err)
+				require.NoError(b, bs.reclaimSpace())
+			}
+		})
+	}
+}
+
+func openBenchmarkBlockStore(b *testing.B, backend string) (blockStore, func()) {
+	dir, err := ioutil.TempDir("", "blockstore")
+	require.NoError(b, err)
+	bs, err := openBlockStore(backend, filepath.Join(dir, "ledger"), false, false)
+	require.NoError(b, err)
+	err = bs.write(func(tx blockTx) error { return tx.init(nil) })
+	require.NoError(b, err)
+	return bs, func() {
+		bs.close()
+		os.RemoveAll(dir)
+	}
+}
+
+// BenchmarkTrackerStore measures the account lookups, the writing of the
+// account updates of a round and the reading of the catchpoint chunks of the
+// tracker database backends.
+func BenchmarkTrackerStore(b *testing.B) {
+	const stored = 10000
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	for _, backend := range trackerStoreBackends {
+		b.Run(backend+"/lookup", func(b *testing.B) {
+			accts := randomAccounts(stored)
+			ts, release := openBenchmarkTrackerStore(b, backend, accts)
+			defer release()
+			qs, err := ts.queries()
+			require.NoError(b, err)
+			addrs := make([]basics.Address, 0, len(accts))
+			for addr := range accts {
+				addrs = append(addrs, addr)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := qs.lookup(addrs[crypto.RandUint64()%uint64(len(addrs))])
+				require.NoError(b, err)
+			}
+		})
+		b.Run(backend+"/accountsNewRound", func(b *testing.B) {
+			accts := randomAccounts(stored)
+			ts, release := openBenchmarkTrackerStore(b, backend, accts)
+			defer release()
+			rounds := make([]map[basics.Address]accountDelta, b.N)
+			for i := range rounds {
+				rounds[i], accts, _ = randomDeltas(100, accts, 0)
+			}
+
+			b.ResetTimer()
+			for i, updates := range rounds {
+				err := ts.write(func(tx trackerTx) error {
+					err := tx.accountsNewRound(updates, 0, proto)
+					if err != nil {
+						return err
+					}
+					return tx.updateAccountsRound(basics.Round(i+1), 0)
+				})
+				require.NoError(b, err)
+			}
+		})
+		b.Run(backend+"/encodedAccountsRange", func(b *testing.B) {
+			ts, release := openBenchmarkTrackerStore(b, backend, randomAccounts(stored))
+			defer release()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				offset := int(crypto.RandUint64() % (stored - BalancesPerCatchpointFileChunk))
+				err := ts.read(func(tx trackerTx) error {
+					_, err := tx.encodedAccountsRange(false, offset, BalancesPerCatchpointFileChunk)
+					return err
+				})
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func openBenchmarkTrackerStore(b *testing.B, backend string, accts map[basics.Address]basics.AccountData) (trackerStore, func()) {
+	dir, err := ioutil.TempDir("", "trackerstore")
+	require.NoError(b, err)
+	ts, err := openTrackerStore(backend, filepath.Join(dir, "ledger"), false, false)
+	require.NoError(b, err)
+	err = ts.write(func(tx trackerTx) error {
+		return tx.accountsInit(accts, config.Consensus[protocol.ConsensusCurrentVersion])
+	})
+	require.NoError(b, err)
+	return ts, func() {
+		ts.close()
+		os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+)
+
+// prefetchWorkers is the number of goroutines concurrently loading the
+// accounts of a block from the ledger.
+var prefetchWorkers = runtime.NumCPU()
+
+// creatableKey identifies an asset or an application.
+type creatableKey struct {
+	cidx  basics.CreatableIndex
+	ctype basics.CreatableType
+}
+
+// foundCreator is the result of a creator lookup.
+type foundCreator struct {
+	addr basics.Address
+	ok   bool
+}
+
+// prefetchTargets lists the accounts and creatables accessed by the
+// transactions of the groups, including the inner transactions recorded in
+// their apply data.
+type prefetchTargets struct {
+	addrs      map[basics.Address]bool
+	creatables map[creatableKey]bool
+}
+
+func (pt *prefetchTargets) addAddress(addr basics.Address) {
+	if addr.IsZero() {
+		return
+	}
+	pt.addrs[addr] = true
+}
+
+func (pt *prefetchTargets) addCreatable(cidx basics.CreatableIndex, ctype basics.CreatableType) {
+	if cidx == 0 {
+		return
+	}
+	pt.creatables[creatableKey{cidx: cidx, ctype: ctype}] = true
+}
+
+func (pt *prefetchTargets) addTransaction(txn transactions.Transaction, ad transactions.ApplyData) {
+	pt.addAddress(txn.Sender)
+	pt.addAddress(txn.Receiver)
+	pt.addAddress(txn.CloseRemainderTo)
+	pt.addAddress(txn.AssetSender)
+	pt.addAddress(txn.AssetReceiver)
+	pt.addAddress(txn.AssetCloseTo)
+	pt.addAddress(txn.FreezeAccount)
+	for _, addr := range txn.Accounts {
+		pt.addAddress(addr)
+	}
+
+	pt.addCreatable(basics.CreatableIndex(txn.XferAsset), basics.AssetCreatable)
+	pt.addCreatable(basics.CreatableIndex(txn.ConfigAsset), basics.AssetCreatable)
+	pt.addCreatable(basics.CreatableIndex(txn.FreezeAsset), basics.AssetCreatable)
+	if txn.ApplicationID != 0 {
+		pt.addCreatable(basics.CreatableIndex(txn.ApplicationID), basics.AppCreatable)
+		pt.addAddress(txn.ApplicationID.Address())
+	}
+	for _, aidx := range txn.ForeignApps {
+		pt.addCreatable(basics.CreatableIndex(aidx), basics.AppCreatable)
+	}
+
+	for _, itx := range ad.InnerTxns {
+		pt.addTransaction(itx.Txn, itx.ApplyData)
+	}
+}
+
+func makePrefetchTargets(groups [][]transactions.SignedTxnWithAD) prefetchTargets {
+	pt := prefetchTargets{
+		addrs:      make(map[basics.Address]bool),
+		creatables: make(map[creatableKey]bool),
+	}
+	for _, group := range groups {
+		for _, stxn := range group {
+			pt.addTransaction(stxn.Txn, stxn.ApplyData)
+		}
+	}
+	return pt
+}
+
+// parallelize calls fn for every index up to n from at most prefetchWorkers
+// goroutines, and returns once all the calls are done or ctx is canceled.
+func parallelize(ctx context.Context, n int, fn func(i int)) {
+	workers := prefetchWorkers
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n || ctx.Err() != nil {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// prefetch concurrently loads the accounts and creators that the transaction
+// groups access, so that evaluating them doesn't wait on the ledger one lookup
+// at a time. The creators are resolved first, since their accounts are loaded
+// along with the others. Failed lookups are not cached; the evaluation repeats
+// them and reports the error.
+//
+// prefetch has to complete before the evaluation starts, as the cached state
+// is read without locking.
+func (x *roundCowBase) prefetch(ctx context.Context, groups [][]transactions.SignedTxnWithAD) {
+	targets := makePrefetchTargets(groups)
+	var mu sync.Mutex
+
+	creatables := make([]creatableKey, 0, len(targets.creatables))
+	for c := range targets.creatables {
+		creatables = append(creatables, c)
+	}
+	creators := make(map[creatableKey]foundCreator, len(creatables))
+	parallelize(ctx, len(creatables), func(i int) {
+		c := creatables[i]
+		addr, ok, err := x.l.GetCreatorForRound(x.rnd, c.cidx, c.ctype)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		creators[c] = foundCreator{addr: addr, ok: ok}
+		if ok {
+			targets.addAddress(addr)
+		}
+		mu.Unlock()
+	})
+
+	addrs := make([]basics.Address, 0, len(targets.addrs))
+	for addr := range targets.addrs {
+		addrs = append(addrs, addr)
+	}
+	accounts := make(map[basics.Address]basics.AccountData, len(addrs))
+	parallelize(ctx, len(addrs), func(i int) {
+		data, err := x.l.LookupWithoutRewards(x.rnd, addrs[i])
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		accounts[addrs[i]] = data
+		mu.Unlock()
+	})
+
+	x.creators = creators
+	x.accounts = accounts
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// prefetchTestLedger is a ledgerForEvaluator counting the lookups it serves
+type prefetchTestLedger struct {
+	mu       sync.Mutex
+	accounts map[basics.Address]basics.AccountData
+	creators map[creatableKey]basics.Address
+	lookups  map[basics.Address]int
+	failing  basics.Address
+}
+
+func (pl *prefetchTestLedger) GenesisHash() crypto.Digest {
+	return crypto.Digest{}
+}
+
+func (pl *prefetchTestLedger) BlockHdr(basics.Round) (bookkeeping.BlockHeader, error) {
+	return bookkeeping.BlockHeader{}, nil
+}
+
+func (pl *prefetchTestLedger) Lookup(rnd basics.Round, addr basics.Address) (basics.AccountData, error) {
+	return pl.LookupWithoutRewards(rnd, addr)
+}
+
+func (pl *prefetchTestLedger) Totals(basics.Round) (AccountTotals, error) {
+	return AccountTotals{}, nil
+}
+
+func (pl *prefetchTestLedger) isDup(config.ConsensusParams, basics.Round, basics.Round, basics.Round, transactions.Txid, txlease) (bool, error) {
+	return false, nil
+}
+
+func (pl *prefetchTestLedger) GetRoundTxIds(rnd basics.Round) map[transactions.Txid]bool {
+	return nil
+}
+
+func (pl *prefetchTestLedger) LookupWithoutRewards(rnd basics.Round, addr basics.Address) (basics.AccountData, error) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.lookups[addr]++
+	if addr == pl.failing {
+		return basics.AccountData{}, fmt.Errorf("lookup of %v failed", addr)
+	}
+	return pl.accounts[addr], nil
+}
+
+func (pl *prefetchTestLedger) GetCreatorForRound(rnd basics.Round, cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error) {
+	addr, ok := pl.creators[creatableKey{cidx: cidx, ctype: ctype}]
+	return addr, ok, nil
+}
+
+func TestPrefetch(t *testing.T) {
+	sender := randomAddress()
+	receiver := randomAddress()
+	assetCreator := randomAddress()
+	appCreator := randomAddress()
+	foreign := randomAddress()
+	inner := randomAddress()
+	failing := randomAddress()
+
+	pl := &prefetchTestLedger{
+		accounts: map[basics.Address]basics.AccountData{
+			sender:       {MicroAlgos: basics.MicroAlgos{Raw: 1}},
+			receiver:     {MicroAlgos: basics.MicroAlgos{Raw: 2}},
+			assetCreator: {MicroAlgos: basics.MicroAlgos{Raw: 3}},
+			appCreator:   {MicroAlgos: basics.MicroAlgos{Raw: 4}},
+		},
+		creators: map[creatableKey]basics.Address{
+			{cidx: 5, ctype: basics.AssetCreatable}: assetCreator,
+			{cidx: 6, ctype: basics.AppCreatable}:   appCreator,
+		},
+		lookups: make(map[basics.Address]int),
+		failing: failing,
+	}
+
+	pay := transactions.SignedTxnWithAD{}
+	pay.Txn.Type = protocol.PaymentTx
+	pay.Txn.Sender = sender
+	pay.Txn.Receiver = receiver
+	pay.Txn.CloseRemainderTo = failing
+
+	xfer := transactions.SignedTxnWithAD{}
+	xfer.Txn.Type = protocol.AssetTransferTx
+	xfer.Txn.Sender = sender
+	xfer.Txn.XferAsset = 5
+	xfer.Txn.AssetReceiver = receiver
+
+	call := transactions.SignedTxnWithAD{}
+	call.Txn.Type = protocol.ApplicationCallTx
+	call.Txn.Sender = sender
+	call.Txn.ApplicationID = 6
+	call.Txn.Accounts = []basics.Address{foreign}
+	call.Txn.ForeignApps = []basics.AppIndex{7}
+	innerPay := transactions.SignedTxnWithAD{}
+	innerPay.Txn.Type = protocol.PaymentTx
+	innerPay.Txn.Sender = basics.AppIndex(6).Address()
+	innerPay.Txn.Receiver = inner
+	call.ApplyData.InnerTxns = []transactions.SignedTxnWithAD{innerPay}
+
+	base := &roundCowBase{l: pl, rnd: 1}
+	base.prefetch(context.Background(), [][]transactions.SignedTxnWithAD{{pay}, {xfer, call}})
+
+	expected := []basics.Address{sender, receiver, assetCreator, appCreator, foreign, inner, basics.AppIndex(6).Address(), failing}
+	require.Equal(t, len(expected), len(pl.lookups))
+	for _, addr := range expected {
+		require.Equal(t, 1, pl.lookups[addr], "%v", addr)
+	}
+
+	// prefetched state is served without going back to the ledger
+	for _, addr := range []basics.Address{sender, receiver, assetCreator, appCreator} {
+		data, err := base.lookup(addr)
+		require.NoError(t, err)
+		require.Equal(t, pl.accounts[addr], data)
+		require.Equal(t, 1, pl.lookups[addr])
+	}
+	creator, ok, err := base.getCreator(5, basics.AssetCreatable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, assetCreator, creator)
+	_, ok, err = base.getCreator(7, basics.AppCreatable)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// failed lookups are repeated, and report their error
+	_, err = base.lookup(failing)
+	require.Error(t, err)
+	require.Equal(t, 2, pl.lookups[failing])
+}
+
+func TestPrefetchCanceled(t *testing.T) {
+	pl := &prefetchTestLedger{lookups: make(map[basics.Address]int)}
+
+	pay := transactions.SignedTxnWithAD{}
+	pay.Txn.Type = protocol.PaymentTx
+	pay.Txn.Sender = randomAddress()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	base := &roundCowBase{l: pl, rnd: 1}
+	base.prefetch(ctx, [][]transactions.SignedTxnWithAD{{pay}})
+	require.Empty(t, pl.lookups)
+	require.Empty(t, base.accounts)
+}
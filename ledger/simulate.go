@@ -0,0 +1,216 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/data/transactions/verify"
+)
+
+// SimulatedTxn is the outcome of a transaction of a simulation.
+type SimulatedTxn struct {
+	Txn transactions.SignedTxn
+
+	// ApplyData is set if the group of the transaction was applied.
+	ApplyData transactions.ApplyData
+
+	// LogicTrace is the trace of the evaluation of the LogicSig program of
+	// the transaction, if it has one.
+	LogicTrace string
+
+	// AppTrace is the trace of the evaluation of the approval or clear
+	// state program of an application call.
+	AppTrace string
+}
+
+// SimulatedGroup is the outcome of a transaction group of a simulation.
+type SimulatedGroup struct {
+	Txns []SimulatedTxn
+
+	// FailureMessage tells why the group could not be applied, in which
+	// case it has no effect on the simulated state.
+	FailureMessage string
+
+	// FailedAt is the index of the transaction that made the group fail, or
+	// -1 if the group didn't fail or was rejected as a whole.
+	FailedAt int
+}
+
+// SimulationResult is the outcome of Simulate.
+type SimulationResult struct {
+	// Round is the round the groups were evaluated in, the one following
+	// the latest round of the ledger.
+	Round basics.Round
+
+	Groups []SimulatedGroup
+
+	// Delta holds the new state of the accounts modified by the groups that
+	// were applied. Closed accounts have the zero AccountData.
+	Delta map[basics.Address]basics.AccountData
+}
+
+// WouldSucceed tells whether all the groups were applied.
+func (r SimulationResult) WouldSucceed() bool {
+	for _, group := range r.Groups {
+		if group.FailureMessage != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Simulate evaluates the transaction groups, one after the other, in a block
+// following the latest round of the ledger, as they would be if they were
+// added to it, without changing the ledger. A group that fails has no effect
+// on the ones that follow it.
+//
+// With skipSignatures, the transactions don't need to be signed: their
+// signatures and multisignatures aren't checked. LogicSig programs are
+// always evaluated, and traced, as are the programs of the application
+// calls.
+//
+// The returned error is only set if the simulation can't be run at all; the
+// failures of the groups are reported in the result.
+func (l *Ledger) Simulate(txgroups [][]transactions.SignedTxn, skipSignatures bool) (SimulationResult, error) {
+	prev, err := l.BlockHdr(l.Latest())
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	// MakeBlock panics on a protocol we don't know about.
+	_, upgradeState, err := bookkeeping.ProcessUpgradeParams(prev)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	if _, ok := config.Consensus[upgradeState.CurrentProtocol]; !ok {
+		return SimulationResult{}, fmt.Errorf("cannot simulate transactions under unsupported protocol %s", upgradeState.CurrentProtocol)
+	}
+
+	next := bookkeeping.MakeBlock(prev)
+	eval, err := l.StartEvaluator(next.BlockHeader, 0)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	// starting the evaluator already withdraws the rewards of the round from
+	// the rewards pool, which isn't an effect of the groups.
+	startAccts := modifiedAccounts(eval.state.mods.accts)
+
+	res := SimulationResult{Round: eval.Round()}
+	for _, txgroup := range txgroups {
+		res.Groups = append(res.Groups, eval.simulateGroup(txgroup, skipSignatures))
+	}
+
+	res.Delta = make(map[basics.Address]basics.AccountData)
+	for addr, data := range modifiedAccounts(eval.state.mods.accts) {
+		startData, ok := startAccts[addr]
+		if ok && reflect.DeepEqual(startData, data) {
+			continue
+		}
+		res.Delta[addr] = data
+	}
+	return res, nil
+}
+
+// simulateGroup checks and applies a group of Simulate.
+func (eval *BlockEvaluator) simulateGroup(txgroup []transactions.SignedTxn, skipSignatures bool) SimulatedGroup {
+	group := SimulatedGroup{FailedAt: -1}
+	if len(txgroup) == 0 {
+		group.FailureMessage = "empty transaction group"
+		return group
+	}
+
+	ctxs := verify.PrepareContexts(txgroup, eval.block.BlockHeader)
+	txads := make([]transactions.SignedTxnWithAD, len(txgroup))
+	for i := range txgroup {
+		txads[i].SignedTxn = txgroup[i]
+		group.Txns = append(group.Txns, SimulatedTxn{Txn: txgroup[i]})
+	}
+
+	for i := range txgroup {
+		trace, err := eval.simulateSignature(&txgroup[i], ctxs[i], skipSignatures)
+		group.Txns[i].LogicTrace = trace
+		if err != nil && group.FailureMessage == "" {
+			group.FailureMessage = fmt.Sprintf("transaction %v: %v", txgroup[i].ID(), err)
+			group.FailedAt = i
+		}
+	}
+	if group.FailureMessage != "" {
+		return group
+	}
+
+	paysetLen := len(eval.block.Payset)
+	eval.appTraces = make([]strings.Builder, len(txgroup))
+	failedAt, err := eval.transactionGroupFailedAt(txads)
+	for i := range group.Txns {
+		group.Txns[i].AppTrace = eval.appTraces[i].String()
+	}
+	eval.appTraces = nil
+	if err != nil {
+		group.FailureMessage = err.Error()
+		group.FailedAt = failedAt
+		return group
+	}
+	for i, txib := range eval.block.Payset[paysetLen:] {
+		group.Txns[i].ApplyData = txib.ApplyData
+	}
+	return group
+}
+
+// simulateSignature checks a transaction the way the transaction pool does
+// before it is evaluated, except for its signatures with skipSignatures, and
+// returns the trace of its LogicSig program, if it has one.
+func (eval *BlockEvaluator) simulateSignature(txn *transactions.SignedTxn, ctx verify.Context, skipSignatures bool) (string, error) {
+	err := txn.Txn.WellFormed(ctx.CurrSpecAddrs, eval.proto)
+	if err != nil {
+		return "", fmt.Errorf("malformed: %v", err)
+	}
+
+	var trace strings.Builder
+	if len(txn.Lsig.Logic) != 0 {
+		ep := logic.EvalParams{
+			Txn:        txn,
+			Proto:      &eval.proto,
+			Trace:      &trace,
+			TxnGroup:   ctx.Group,
+			GroupIndex: ctx.GroupIndex,
+		}
+		pass, err := logic.Eval(txn.Lsig.Logic, ep)
+		if err != nil {
+			return trace.String(), fmt.Errorf("rejected by logic err=%v", err)
+		}
+		if !pass {
+			return trace.String(), fmt.Errorf("rejected by logic")
+		}
+	}
+
+	if !skipSignatures {
+		err = verify.Txn(txn, ctx)
+		if err != nil {
+			return trace.String(), fmt.Errorf("failed to verify: %v", err)
+		}
+	}
+	return trace.String(), nil
+}
@@ -0,0 +1,292 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestLedgerSimulate(t *testing.T) {
+	genesisInitState, initSecrets := testGenerateInitState(t, protocol.ConsensusCurrentVersion)
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	l, err := OpenLedger(logging.TestingLog(t), t.Name(), inMem, genesisInitState, cfg)
+	require.NoError(t, err, "could not open ledger")
+	defer l.Close()
+
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	var addrs []basics.Address
+	for addr := range genesisInitState.Accounts {
+		if addr != testPoolAddr && addr != testSinkAddr {
+			addrs = append(addrs, addr)
+		}
+	}
+	sender, receiver := addrs[0], addrs[1]
+	senderData := genesisInitState.Accounts[sender]
+
+	pay := func(from, to basics.Address, amount uint64) transactions.Transaction {
+		return transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      from,
+				Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid:  l.Latest(),
+				LastValid:   l.Latest() + 10,
+				GenesisID:   t.Name(),
+				GenesisHash: genesisInitState.GenesisHash,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: to,
+				Amount:   basics.MicroAlgos{Raw: amount},
+			},
+		}
+	}
+	sign := func(txn transactions.Transaction) transactions.SignedTxn {
+		return txn.Sign(initSecrets[txn.Sender])
+	}
+
+	approve, err := logic.AssembleStringWithVersion("int 1", proto.LogicSigVersion)
+	require.NoError(t, err)
+	reject, err := logic.AssembleStringWithVersion("int 0", proto.LogicSigVersion)
+	require.NoError(t, err)
+	contract := basics.Address(logic.HashProgram(approve))
+
+	fromContract := transactions.SignedTxn{Txn: pay(contract, receiver, 1000)}
+	fromContract.Lsig.Logic = approve
+	rejected := transactions.SignedTxn{Txn: pay(contract, receiver, 2000)}
+	rejected.Lsig.Logic = reject
+
+	txgroups := [][]transactions.SignedTxn{
+		// funds the contract account.
+		{sign(pay(sender, contract, 1000000))},
+		// fails, since it isn't signed.
+		{{Txn: pay(sender, receiver, 1)}},
+		// overspends.
+		{sign(pay(receiver, sender, 1000000000))},
+		// is approved, and can spend the funds of the first group.
+		{fromContract},
+		{rejected},
+	}
+
+	res, err := l.Simulate(txgroups, false)
+	require.NoError(t, err)
+	require.Equal(t, l.Latest()+1, res.Round)
+	require.False(t, res.WouldSucceed())
+	require.Equal(t, len(txgroups), len(res.Groups))
+
+	failed := make([]bool, len(txgroups))
+	for i, group := range res.Groups {
+		require.Equal(t, len(txgroups[i]), len(group.Txns))
+		require.Equal(t, txgroups[i][0], group.Txns[0].Txn)
+		failed[i] = group.FailureMessage != ""
+		if failed[i] {
+			require.Equal(t, 0, group.FailedAt)
+		} else {
+			require.Equal(t, -1, group.FailedAt)
+		}
+	}
+	require.Equal(t, []bool{false, true, true, false, true}, failed)
+	require.Contains(t, res.Groups[1].FailureMessage, "failed to verify")
+	require.Contains(t, res.Groups[2].FailureMessage, "overspend")
+	require.Contains(t, res.Groups[4].FailureMessage, "rejected by logic")
+
+	// the LogicSig programs are traced, whether they pass or not.
+	require.Empty(t, res.Groups[0].Txns[0].LogicTrace)
+	require.Contains(t, res.Groups[3].Txns[0].LogicTrace, "intc_0")
+	require.Contains(t, res.Groups[4].Txns[0].LogicTrace, "intc_0")
+
+	// only the accounts touched by the applied groups are in the delta.
+	require.Equal(t, 4, len(res.Delta))
+	require.Equal(t, senderData.MicroAlgos.Raw-1000000-proto.MinTxnFee, res.Delta[sender].MicroAlgos.Raw)
+	require.Equal(t, 1000000-1000-proto.MinTxnFee, res.Delta[contract].MicroAlgos.Raw)
+	require.Equal(t, genesisInitState.Accounts[receiver].MicroAlgos.Raw+1000, res.Delta[receiver].MicroAlgos.Raw)
+	require.Equal(t, genesisInitState.Accounts[testSinkAddr].MicroAlgos.Raw+2*proto.MinTxnFee, res.Delta[testSinkAddr].MicroAlgos.Raw)
+
+	// the ledger isn't changed.
+	data, err := l.Lookup(l.Latest(), sender)
+	require.NoError(t, err)
+	require.Equal(t, senderData.MicroAlgos, data.MicroAlgos)
+	_, err = l.Lookup(l.Latest(), contract)
+	require.NoError(t, err)
+
+	// without signature checks, the unsigned transaction is applied too.
+	res, err = l.Simulate(txgroups[:2], true)
+	require.NoError(t, err)
+	require.True(t, res.WouldSucceed())
+	require.Equal(t, senderData.MicroAlgos.Raw-1000000-1-2*proto.MinTxnFee, res.Delta[sender].MicroAlgos.Raw)
+}
+
+func TestLedgerSimulateGroup(t *testing.T) {
+	genesisInitState, initSecrets := testGenerateInitState(t, protocol.ConsensusCurrentVersion)
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	l, err := OpenLedger(logging.TestingLog(t), t.Name(), inMem, genesisInitState, cfg)
+	require.NoError(t, err, "could not open ledger")
+	defer l.Close()
+
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	var addrs []basics.Address
+	for addr := range genesisInitState.Accounts {
+		if addr != testPoolAddr && addr != testSinkAddr {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	var txns []transactions.Transaction
+	var group transactions.TxGroup
+	for i := 0; i < 3; i++ {
+		txn := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addrs[i],
+				Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid:  l.Latest(),
+				LastValid:   l.Latest() + 10,
+				GenesisID:   t.Name(),
+				GenesisHash: genesisInitState.GenesisHash,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addrs[i+1],
+				Amount:   basics.MicroAlgos{Raw: 1000},
+			},
+		}
+		// the last transaction overspends.
+		if i == 2 {
+			txn.Amount.Raw = 1000000000
+		}
+		txns = append(txns, txn)
+		group.TxGroupHashes = append(group.TxGroupHashes, crypto.HashObj(txn))
+	}
+	var stxns []transactions.SignedTxn
+	for _, txn := range txns {
+		txn.Group = crypto.HashObj(group)
+		stxns = append(stxns, txn.Sign(initSecrets[txn.Sender]))
+	}
+
+	res, err := l.Simulate([][]transactions.SignedTxn{stxns, stxns[:2]}, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res.Groups))
+
+	// the group fails at its last transaction, and none of it is applied.
+	require.Equal(t, 2, res.Groups[0].FailedAt)
+	require.Contains(t, res.Groups[0].FailureMessage, "overspend")
+	for _, txn := range res.Groups[0].Txns {
+		require.Equal(t, transactions.ApplyData{}, txn.ApplyData)
+	}
+
+	// a partial group is rejected as a whole.
+	require.Equal(t, -1, res.Groups[1].FailedAt)
+	require.Contains(t, res.Groups[1].FailureMessage, "incomplete group")
+	require.Empty(t, res.Delta)
+}
+
+func TestLedgerSimulateApp(t *testing.T) {
+	genesisInitState, addrs, keys := genesis(10)
+	genesisInitState.Block.CurrentProtocol = protocol.ConsensusFuture
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	l, err := OpenLedger(logging.TestingLog(t), t.Name(), inMem, genesisInitState, cfg)
+	require.NoError(t, err, "could not open ledger")
+	defer l.Close()
+
+	// the application pays 5000 to its second account when called
+	approval, err := logic.AssembleString(`txn ApplicationID
+bz done
+byte "calls"
+int 1
+app_global_put
+itxn_begin
+int pay
+itxn_field TypeEnum
+txn Accounts 1
+itxn_field Receiver
+int 5000
+itxn_field Amount
+itxn_submit
+done:
+int 1
+`)
+	require.NoError(t, err)
+	clear, err := logic.AssembleString("int 1")
+	require.NoError(t, err)
+
+	proto := config.Consensus[protocol.ConsensusFuture]
+	header := transactions.Header{
+		Sender:      addrs[0],
+		Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+		FirstValid:  l.Latest(),
+		LastValid:   l.Latest() + 10,
+		GenesisHash: genesisInitState.GenesisHash,
+	}
+	create := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApprovalProgram:   approval,
+			ClearStateProgram: clear,
+			GlobalStateSchema: basics.StateSchema{NumUint: 1},
+		},
+	}
+	// the first transaction of the round creates application 1
+	appIdx := basics.AppIndex(1)
+	header.Note = []byte("fund")
+	fund := transactions.Transaction{
+		Type:   protocol.PaymentTx,
+		Header: header,
+		PaymentTxnFields: transactions.PaymentTxnFields{
+			Receiver: appIdx.Address(),
+			Amount:   basics.MicroAlgos{Raw: 1000000},
+		},
+	}
+	header.Note = []byte("call")
+	call := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApplicationID: appIdx,
+			Accounts:      []basics.Address{addrs[1]},
+		},
+	}
+
+	res, err := l.Simulate([][]transactions.SignedTxn{{create.Sign(keys[0])}, {fund.Sign(keys[0])}, {call.Sign(keys[0])}}, false)
+	require.NoError(t, err)
+	require.True(t, res.WouldSucceed())
+
+	// the approval programs are traced.
+	require.Contains(t, res.Groups[0].Txns[0].AppTrace, "bz")
+	require.Empty(t, res.Groups[1].Txns[0].AppTrace)
+	require.Contains(t, res.Groups[2].Txns[0].AppTrace, "itxn_submit")
+	require.Empty(t, res.Groups[2].Txns[0].LogicTrace)
+
+	// the application call applies its state delta and its inner payment.
+	ad := res.Groups[2].Txns[0].ApplyData
+	require.Equal(t, basics.ValueDelta{Action: basics.SetUintAction, Uint: 1}, ad.EvalDelta.GlobalDelta["calls"])
+	require.Len(t, ad.InnerTxns, 1)
+	require.Equal(t, addrs[1], ad.InnerTxns[0].Txn.Receiver)
+	require.Equal(t, uint64(5000), ad.InnerTxns[0].Txn.Amount.Raw)
+	require.Equal(t, genesisInitState.Accounts[addrs[1]].MicroAlgos.Raw+5000, res.Delta[addrs[1]].MicroAlgos.Raw)
+}
@@ -0,0 +1,217 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// SnapshotAccounts reads the state of all the accounts at round rnd from the
+// tracker database of the ledger stored at dbPathPrefix. It first calls begin
+// with the round of the snapshot and the totals of the accounts at that round,
+// and then visit on each of the accounts, in the order of their addresses. If
+// rnd is zero, the accounts are read at the round of the tracker database.
+// Rounds older than it are only available from archival ledgers that keep the
+// account history.
+//
+// The database is opened read-only, and read in a single transaction, so the
+// snapshot is consistent even when the ledger is being written by a running
+// node.
+func SnapshotAccounts(dbPathPrefix string, rnd basics.Round, begin func(basics.Round, AccountTotals) error, visit func(basics.Address, basics.AccountData) error) error {
+	backend, err := existingTrackerStoreBackend(dbPathPrefix)
+	if err != nil {
+		return err
+	}
+	trackerDBs, err := openTrackerStore(backend, dbPathPrefix, true, false)
+	if err != nil {
+		return err
+	}
+	defer trackerDBs.close()
+
+	return trackerDBs.read(func(tx trackerTx) error {
+		dbRound, _, err0 := tx.accountsRound()
+		if err0 != nil {
+			return err0
+		}
+		round := rnd
+		if round == 0 || round == dbRound {
+			round = dbRound
+			totals, err0 := tx.accountsTotals(false)
+			if err0 != nil {
+				return err0
+			}
+			err0 = begin(round, totals)
+			if err0 != nil {
+				return err0
+			}
+			return tx.snapshotAccountsCurrent(visit)
+		}
+
+		if round > dbRound {
+			return fmt.Errorf("SnapshotAccounts: round %d is not committed to the tracker database yet (latest %d)", round, dbRound)
+		}
+		histRound, err0 := tx.accountsHistoryRound()
+		if err0 == sql.ErrNoRows {
+			return ErrHistoryNotAvailable{Round: round, Oldest: dbRound}
+		}
+		if err0 != nil {
+			return err0
+		}
+		if round < histRound {
+			return ErrHistoryNotAvailable{Round: round, Oldest: histRound}
+		}
+		totals, err0 := tx.accountsHistoryTotals(round)
+		if err0 != nil {
+			return err0
+		}
+		err0 = begin(round, totals)
+		if err0 != nil {
+			return err0
+		}
+		return tx.snapshotAccountsHistory(round, visit)
+	})
+}
+
+// snapshotAccountsCurrent visits the accountbase records, each merged with
+// its resources records.
+func snapshotAccountsCurrent(tx *sql.Tx, visit func(basics.Address, basics.AccountData) error) error {
+	rows, err := tx.Query("SELECT address, data FROM accountbase ORDER BY address")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	resRows, err := tx.Query("SELECT address, data FROM resources ORDER BY address")
+	if err != nil {
+		return err
+	}
+	defer resRows.Close()
+
+	// both queries are ordered by address, so the resources of each account
+	// directly follow the ones of the previous account.
+	var res struct {
+		addr  basics.Address
+		data  basics.AccountData
+		valid bool
+	}
+	nextResource := func() error {
+		res.valid = false
+		if !resRows.Next() {
+			return resRows.Err()
+		}
+		var addrbuf, buf []byte
+		err := resRows.Scan(&addrbuf, &buf)
+		if err != nil {
+			return err
+		}
+		if len(addrbuf) != len(res.addr) {
+			return fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(res.addr))
+		}
+		copy(res.addr[:], addrbuf)
+		res.data = basics.AccountData{}
+		err = protocol.Decode(buf, &res.data)
+		res.valid = err == nil
+		return err
+	}
+	err = nextResource()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var addrbuf, buf []byte
+		err = rows.Scan(&addrbuf, &buf)
+		if err != nil {
+			return err
+		}
+		var addr basics.Address
+		if len(addrbuf) != len(addr) {
+			return fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(addr))
+		}
+		copy(addr[:], addrbuf)
+		var data basics.AccountData
+		err = protocol.Decode(buf, &data)
+		if err != nil {
+			return err
+		}
+
+		if res.valid && bytes.Compare(res.addr[:], addr[:]) < 0 {
+			return fmt.Errorf("Account DB has resources for missing account %v", res.addr)
+		}
+		for res.valid && res.addr == addr {
+			mergeResourceData(&data, res.data)
+			err = nextResource()
+			if err != nil {
+				return err
+			}
+		}
+
+		err = visit(addr, data)
+		if err != nil {
+			return err
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+	if res.valid {
+		return fmt.Errorf("Account DB has resources for missing account %v", res.addr)
+	}
+	return nil
+}
+
+// snapshotAccountsHistory visits the accounts that exist at round rnd,
+// according to the account history.
+func snapshotAccountsHistory(tx *sql.Tx, rnd basics.Round, visit func(basics.Address, basics.AccountData) error) error {
+	rows, err := tx.Query("SELECT address, data FROM accounthistory h WHERE round=(SELECT MAX(round) FROM accounthistory WHERE address=h.address AND round<=?) ORDER BY address", rnd)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var addrbuf, buf []byte
+		err = rows.Scan(&addrbuf, &buf)
+		if err != nil {
+			return err
+		}
+		var addr basics.Address
+		if len(addrbuf) != len(addr) {
+			return fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(addr))
+		}
+		copy(addr[:], addrbuf)
+		var data basics.AccountData
+		err = protocol.Decode(buf, &data)
+		if err != nil {
+			return err
+		}
+		// the accounts deleted by the time of rnd are recorded as empty.
+		if data.IsZero() {
+			continue
+		}
+		err = visit(addr, data)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
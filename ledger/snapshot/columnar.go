@@ -0,0 +1,153 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/algorand/go-codec/codec"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// columnarFile is the content of a columnar snapshot.
+type columnarFile struct {
+	Header Header                   `codec:"header"`
+	Tables map[string]columnarTable `codec:"tables"`
+}
+
+// columnarTable holds the columns of a table, by name. Each of them is a
+// msgpack array of Rows values.
+type columnarTable struct {
+	Rows    uint64               `codec:"rows"`
+	Columns map[string]codec.Raw `codec:"columns"`
+}
+
+type columnarWriter struct {
+	path string
+	hdr  Header
+	// columns holds a slice of values for each field of each table.
+	columns [][]reflect.Value
+	rows    []uint64
+}
+
+func makeColumnarWriter(path string, hdr Header) *columnarWriter {
+	w := &columnarWriter{
+		path:    path,
+		hdr:     hdr,
+		columns: make([][]reflect.Value, len(tables)),
+		rows:    make([]uint64, len(tables)),
+	}
+	for i, t := range tables {
+		w.columns[i] = make([]reflect.Value, t.typ.NumField())
+		for j := range w.columns[i] {
+			w.columns[i][j] = reflect.MakeSlice(reflect.SliceOf(t.typ.Field(j).Type), 0, 0)
+		}
+	}
+	return w
+}
+
+func (w *columnarWriter) Write(addr basics.Address, data basics.AccountData) error {
+	records := makeAccountRecords(addr, data)
+	return records.visit(func(tableIndex int, record interface{}) error {
+		v := reflect.ValueOf(record).Elem()
+		for j, column := range w.columns[tableIndex] {
+			w.columns[tableIndex][j] = reflect.Append(column, v.Field(j))
+		}
+		w.rows[tableIndex]++
+		return nil
+	})
+}
+
+func (w *columnarWriter) Close() error {
+	file := columnarFile{
+		Header: w.hdr,
+		Tables: make(map[string]columnarTable, len(tables)),
+	}
+	for i, t := range tables {
+		ct := columnarTable{
+			Rows:    w.rows[i],
+			Columns: make(map[string]codec.Raw, len(w.columns[i])),
+		}
+		for j, name := range t.columns() {
+			ct.Columns[name] = protocol.EncodeReflect(w.columns[i][j].Interface())
+		}
+		file.Tables[t.name] = ct
+	}
+	return ioutil.WriteFile(w.path, protocol.EncodeReflect(&file), 0666)
+}
+
+func loadColumnar(path string, b *balancesBuilder) (hdr Header, err error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var file columnarFile
+	err = protocol.DecodeReflect(buf, &file)
+	if err != nil {
+		return
+	}
+	hdr = file.Header
+	err = checkVersion(hdr)
+	if err != nil {
+		return
+	}
+
+	for _, t := range tables {
+		err = loadColumnarTable(file.Tables[t.name], t, b)
+		if err != nil {
+			err = fmt.Errorf("%s: table %s: %v", path, t.name, err)
+			return
+		}
+	}
+	return
+}
+
+func loadColumnarTable(ct columnarTable, t table, b *balancesBuilder) error {
+	names := t.columns()
+	columns := make([]reflect.Value, len(names))
+	for j, name := range names {
+		raw, ok := ct.Columns[name]
+		if !ok {
+			return fmt.Errorf("missing column %s", name)
+		}
+		column := reflect.New(reflect.SliceOf(t.typ.Field(j).Type))
+		err := protocol.DecodeReflect(raw, column.Interface())
+		if err != nil {
+			return fmt.Errorf("column %s: %v", name, err)
+		}
+		if uint64(column.Elem().Len()) != ct.Rows {
+			return fmt.Errorf("column %s has %d rows instead of %d", name, column.Elem().Len(), ct.Rows)
+		}
+		columns[j] = column.Elem()
+	}
+
+	for i := 0; i < int(ct.Rows); i++ {
+		record := reflect.New(t.typ)
+		for j, column := range columns {
+			record.Elem().Field(j).Set(column.Index(i))
+		}
+		err := b.add(record.Interface())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
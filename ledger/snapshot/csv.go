@@ -0,0 +1,276 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var addressType = reflect.TypeOf(basics.Address{})
+
+// csvFile is a CSV file of a table being written.
+type csvFile struct {
+	f   *os.File
+	buf *bufio.Writer
+	w   *csv.Writer
+}
+
+type csvWriter struct {
+	files []csvFile
+}
+
+func csvFilename(dir string, t table) string {
+	return filepath.Join(dir, t.name+".csv")
+}
+
+func makeCSVWriter(dir string, hdr Header) (*csvWriter, error) {
+	err := os.MkdirAll(dir, 0777)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &csvWriter{}
+	for _, t := range append([]table{headerTable}, tables...) {
+		f, err := os.OpenFile(csvFilename(dir, t), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+		if err != nil {
+			w.close()
+			return nil, err
+		}
+		file := csvFile{f: f, buf: bufio.NewWriter(f)}
+		file.w = csv.NewWriter(file.buf)
+		w.files = append(w.files, file)
+		err = file.w.Write(t.columns())
+		if err != nil {
+			w.close()
+			return nil, err
+		}
+	}
+
+	err = w.writeRecord(w.files[0].w, &hdr)
+	if err != nil {
+		w.close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *csvWriter) writeRecord(cw *csv.Writer, record interface{}) error {
+	v := reflect.ValueOf(record).Elem()
+	row := make([]string, v.NumField())
+	for i := range row {
+		var err error
+		row[i], err = formatCSVValue(v.Field(i))
+		if err != nil {
+			return err
+		}
+	}
+	return cw.Write(row)
+}
+
+func (w *csvWriter) Write(addr basics.Address, data basics.AccountData) error {
+	records := makeAccountRecords(addr, data)
+	return records.visit(func(tableIndex int, record interface{}) error {
+		return w.writeRecord(w.files[tableIndex+1].w, record)
+	})
+}
+
+func (w *csvWriter) Close() (err error) {
+	for _, file := range w.files {
+		file.w.Flush()
+		if err == nil {
+			err = file.w.Error()
+		}
+		if err == nil {
+			err = file.buf.Flush()
+		}
+	}
+	closeErr := w.close()
+	if err == nil {
+		err = closeErr
+	}
+	return
+}
+
+func (w *csvWriter) close() (err error) {
+	for _, file := range w.files {
+		closeErr := file.f.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return
+}
+
+// formatCSVValue returns the text of a field of a record. Addresses are
+// written in their checksummed form, and the other byte strings in base64.
+// Integers, booleans and strings are written as is, and anything else is
+// written in JSON.
+func formatCSVValue(v reflect.Value) (string, error) {
+	switch {
+	case v.Type() == addressType:
+		return v.Interface().(basics.Address).String(), nil
+	case v.Kind() == reflect.String:
+		return v.String(), nil
+	case v.Kind() == reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return base64.StdEncoding.EncodeToString(b), nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+	case v.Kind() == reflect.Slice && v.Len() == 0:
+		return "", nil
+	}
+	var buf bytes.Buffer
+	err := json.Compact(&buf, protocol.EncodeJSON(v.Interface()))
+	return buf.String(), err
+}
+
+// parseCSVValue sets a field of a record from its text.
+func parseCSVValue(v reflect.Value, text string) error {
+	switch {
+	case v.Type() == addressType:
+		return v.Addr().Interface().(*basics.Address).UnmarshalText([]byte(text))
+	case v.Kind() == reflect.String:
+		v.SetString(text)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		u, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+		b, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return err
+		}
+		if len(b) != v.Len() {
+			return fmt.Errorf("%d bytes instead of %d", len(b), v.Len())
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		b, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return err
+		}
+		if len(b) > 0 {
+			v.SetBytes(b)
+		}
+	case text == "":
+	default:
+		return protocol.DecodeJSON([]byte(text), v.Addr().Interface())
+	}
+	return nil
+}
+
+// loadCSVTable reads the CSV file of a table, and calls f on each of its
+// records.
+func loadCSVTable(dir string, t table, f func(record interface{}) error) error {
+	filename := csvFilename(dir, t)
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(bufio.NewReader(file))
+	columns, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	// the columns are matched by name, so that they may be reordered.
+	fieldIndexes := make([]int, len(columns))
+	expected := t.columns()
+	for i, column := range columns {
+		fieldIndexes[i] = -1
+		for j, name := range expected {
+			if name == column {
+				fieldIndexes[i] = j
+			}
+		}
+		if fieldIndexes[i] < 0 {
+			return fmt.Errorf("%s: unknown column %q", filename, column)
+		}
+	}
+
+	for rowNumber := 1; ; rowNumber++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", filename, err)
+		}
+		record := reflect.New(t.typ)
+		for i, text := range row {
+			err = parseCSVValue(record.Elem().Field(fieldIndexes[i]), text)
+			if err != nil {
+				return fmt.Errorf("%s: row %d: column %s: %v", filename, rowNumber, columns[i], err)
+			}
+		}
+		err = f(record.Interface())
+		if err != nil {
+			return fmt.Errorf("%s: row %d: %v", filename, rowNumber, err)
+		}
+	}
+}
+
+func loadCSV(dir string, b *balancesBuilder) (hdr Header, err error) {
+	rows := 0
+	err = loadCSVTable(dir, headerTable, func(record interface{}) error {
+		rows++
+		hdr = *record.(*Header)
+		return checkVersion(hdr)
+	})
+	if err != nil {
+		return
+	}
+	if rows != 1 {
+		err = fmt.Errorf("%s: %d headers instead of 1", csvFilename(dir, headerTable), rows)
+		return
+	}
+
+	for _, t := range tables {
+		err = loadCSVTable(dir, t, b.add)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
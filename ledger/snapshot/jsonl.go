@@ -0,0 +1,163 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// jsonlLine is a line of a jsonl snapshot; a single one of its fields is set.
+type jsonlLine struct {
+	Header        *Header        `codec:"header,omitempty"`
+	Account       *Account       `codec:"account,omitempty"`
+	AssetHolding  *AssetHolding  `codec:"asset-holding,omitempty"`
+	AssetParams   *AssetParams   `codec:"asset-params,omitempty"`
+	AppParams     *AppParams     `codec:"app-params,omitempty"`
+	AppLocalState *AppLocalState `codec:"app-local-state,omitempty"`
+}
+
+func (line jsonlLine) record() interface{} {
+	switch {
+	case line.Account != nil:
+		return line.Account
+	case line.AssetHolding != nil:
+		return line.AssetHolding
+	case line.AssetParams != nil:
+		return line.AssetParams
+	case line.AppParams != nil:
+		return line.AppParams
+	case line.AppLocalState != nil:
+		return line.AppLocalState
+	}
+	return nil
+}
+
+type jsonlWriter struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+func makeJSONLWriter(path string, hdr Header) (*jsonlWriter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, err
+	}
+	w := &jsonlWriter{f: f, w: bufio.NewWriter(f)}
+	err = w.writeLine(jsonlLine{Header: &hdr})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *jsonlWriter) writeLine(line jsonlLine) error {
+	var buf bytes.Buffer
+	// protocol.EncodeJSON indents its output, which has to fit on a line.
+	err := json.Compact(&buf, protocol.EncodeJSON(&line))
+	if err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = w.w.Write(buf.Bytes())
+	return err
+}
+
+func (w *jsonlWriter) Write(addr basics.Address, data basics.AccountData) error {
+	records := makeAccountRecords(addr, data)
+	return records.visit(func(tableIndex int, record interface{}) error {
+		var line jsonlLine
+		switch r := record.(type) {
+		case *Account:
+			line.Account = r
+		case *AssetHolding:
+			line.AssetHolding = r
+		case *AssetParams:
+			line.AssetParams = r
+		case *AppParams:
+			line.AppParams = r
+		case *AppLocalState:
+			line.AppLocalState = r
+		}
+		return w.writeLine(line)
+	})
+}
+
+func (w *jsonlWriter) Close() error {
+	err := w.w.Flush()
+	if err != nil {
+		w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
+
+func loadJSONL(path string, b *balancesBuilder) (hdr Header, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for lineNumber := 1; ; lineNumber++ {
+		buf, readErr := r.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return hdr, readErr
+		}
+		if len(bytes.TrimSpace(buf)) > 0 {
+			var line jsonlLine
+			err = protocol.DecodeJSON(buf, &line)
+			if err == nil {
+				err = loadJSONLLine(line, lineNumber, &hdr, b)
+			}
+			if err != nil {
+				return hdr, fmt.Errorf("%s:%d: %v", path, lineNumber, err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+	if hdr.Version == 0 {
+		err = fmt.Errorf("%s: snapshot has no header", path)
+	}
+	return
+}
+
+func loadJSONLLine(line jsonlLine, lineNumber int, hdr *Header, b *balancesBuilder) error {
+	if lineNumber == 1 {
+		if line.Header == nil {
+			return fmt.Errorf("snapshot does not start with a header")
+		}
+		*hdr = *line.Header
+		return checkVersion(*hdr)
+	}
+	record := line.record()
+	if record == nil {
+		return fmt.Errorf("line holds no record")
+	}
+	return b.add(record)
+}
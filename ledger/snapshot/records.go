@@ -0,0 +1,372 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// Version is the version of the snapshot records written by this package.
+const Version = 1
+
+// Header describes a snapshot. It is the first record of every snapshot.
+type Header struct {
+	Version     uint64        `codec:"version"`
+	GenesisID   string        `codec:"genesis-id"`
+	GenesisHash crypto.Digest `codec:"genesis-hash"`
+	Round       basics.Round  `codec:"round"`
+
+	// RewardsLevel is the rewards level at Round, which the rewards of the
+	// accounts are computed against.
+	RewardsLevel uint64 `codec:"rewards-level"`
+}
+
+// Account is the record of the base data of an account. Status is one of
+// "Offline", "Online" and "Not Participating".
+type Account struct {
+	Address            basics.Address `codec:"address"`
+	Status             string         `codec:"status"`
+	MicroAlgos         uint64         `codec:"amount"`
+	RewardsBase        uint64         `codec:"rewards-base"`
+	RewardedMicroAlgos uint64         `codec:"rewards"`
+
+	VoteID          crypto.OneTimeSignatureVerifier `codec:"vote-id"`
+	SelectionID     crypto.VRFVerifier              `codec:"selection-id"`
+	VoteFirstValid  basics.Round                    `codec:"vote-first-valid"`
+	VoteLastValid   basics.Round                    `codec:"vote-last-valid"`
+	VoteKeyDilution uint64                          `codec:"vote-key-dilution"`
+
+	AuthAddr                   basics.Address `codec:"auth-addr"`
+	TotalAppSchemaNumUint      uint64         `codec:"total-app-schema-num-uint"`
+	TotalAppSchemaNumByteSlice uint64         `codec:"total-app-schema-num-byte-slice"`
+}
+
+// AssetHolding is the record of the holding of an asset by an account.
+type AssetHolding struct {
+	Address basics.Address `codec:"address"`
+	AssetID uint64         `codec:"asset-id"`
+	Amount  uint64         `codec:"amount"`
+	Frozen  bool           `codec:"frozen"`
+}
+
+// AssetParams is the record of the parameters of an asset, held by its
+// creator.
+type AssetParams struct {
+	Creator       basics.Address `codec:"creator"`
+	AssetID       uint64         `codec:"asset-id"`
+	Total         uint64         `codec:"total"`
+	Decimals      uint32         `codec:"decimals"`
+	DefaultFrozen bool           `codec:"default-frozen"`
+	UnitName      string         `codec:"unit-name"`
+	AssetName     string         `codec:"asset-name"`
+	URL           string         `codec:"url"`
+	MetadataHash  [32]byte       `codec:"metadata-hash"`
+	Manager       basics.Address `codec:"manager"`
+	Reserve       basics.Address `codec:"reserve"`
+	Freeze        basics.Address `codec:"freeze"`
+	Clawback      basics.Address `codec:"clawback"`
+}
+
+// AppParams is the record of the parameters and global state of an
+// application, held by its creator.
+type AppParams struct {
+	Creator                       basics.Address `codec:"creator"`
+	AppID                         uint64         `codec:"app-id"`
+	ApprovalProgram               []byte         `codec:"approval-program"`
+	ClearStateProgram             []byte         `codec:"clear-state-program"`
+	LocalStateSchemaNumUint       uint64         `codec:"local-state-schema-num-uint"`
+	LocalStateSchemaNumByteSlice  uint64         `codec:"local-state-schema-num-byte-slice"`
+	GlobalStateSchemaNumUint      uint64         `codec:"global-state-schema-num-uint"`
+	GlobalStateSchemaNumByteSlice uint64         `codec:"global-state-schema-num-byte-slice"`
+	GlobalState                   []KeyValue     `codec:"global-state"`
+}
+
+// AppLocalState is the record of the local state of an account in an
+// application it opted in.
+type AppLocalState struct {
+	Address            basics.Address `codec:"address"`
+	AppID              uint64         `codec:"app-id"`
+	SchemaNumUint      uint64         `codec:"schema-num-uint"`
+	SchemaNumByteSlice uint64         `codec:"schema-num-byte-slice"`
+	KeyValue           []KeyValue     `codec:"key-value"`
+}
+
+// KeyValue is an entry of the key/value store of an application. Type is 1
+// for byte slice values, held in Bytes, and 2 for integer ones, held in Uint.
+type KeyValue struct {
+	Key   []byte          `codec:"key"`
+	Type  basics.TealType `codec:"type"`
+	Bytes []byte          `codec:"bytes"`
+	Uint  uint64          `codec:"uint"`
+}
+
+// accountRecords are the records of a single account.
+type accountRecords struct {
+	account        Account
+	assetHoldings  []AssetHolding
+	assetParams    []AssetParams
+	appParams      []AppParams
+	appLocalStates []AppLocalState
+}
+
+// makeAccountRecords splits the data of an account into its records. The
+// records of its creatables are ordered by their IDs, so that writing the
+// same state twice gives the same snapshot.
+func makeAccountRecords(addr basics.Address, data basics.AccountData) (r accountRecords) {
+	r.account = Account{
+		Address:                    addr,
+		Status:                     data.Status.String(),
+		MicroAlgos:                 data.MicroAlgos.Raw,
+		RewardsBase:                data.RewardsBase,
+		RewardedMicroAlgos:         data.RewardedMicroAlgos.Raw,
+		VoteID:                     data.VoteID,
+		SelectionID:                data.SelectionID,
+		VoteFirstValid:             data.VoteFirstValid,
+		VoteLastValid:              data.VoteLastValid,
+		VoteKeyDilution:            data.VoteKeyDilution,
+		AuthAddr:                   data.AuthAddr,
+		TotalAppSchemaNumUint:      data.TotalAppSchema.NumUint,
+		TotalAppSchemaNumByteSlice: data.TotalAppSchema.NumByteSlice,
+	}
+
+	for aidx, holding := range data.Assets {
+		r.assetHoldings = append(r.assetHoldings, AssetHolding{
+			Address: addr,
+			AssetID: uint64(aidx),
+			Amount:  holding.Amount,
+			Frozen:  holding.Frozen,
+		})
+	}
+	sort.Slice(r.assetHoldings, func(i, j int) bool { return r.assetHoldings[i].AssetID < r.assetHoldings[j].AssetID })
+
+	for aidx, params := range data.AssetParams {
+		r.assetParams = append(r.assetParams, AssetParams{
+			Creator:       addr,
+			AssetID:       uint64(aidx),
+			Total:         params.Total,
+			Decimals:      params.Decimals,
+			DefaultFrozen: params.DefaultFrozen,
+			UnitName:      params.UnitName,
+			AssetName:     params.AssetName,
+			URL:           params.URL,
+			MetadataHash:  params.MetadataHash,
+			Manager:       params.Manager,
+			Reserve:       params.Reserve,
+			Freeze:        params.Freeze,
+			Clawback:      params.Clawback,
+		})
+	}
+	sort.Slice(r.assetParams, func(i, j int) bool { return r.assetParams[i].AssetID < r.assetParams[j].AssetID })
+
+	for aidx, params := range data.AppParams {
+		r.appParams = append(r.appParams, AppParams{
+			Creator:                       addr,
+			AppID:                         uint64(aidx),
+			ApprovalProgram:               params.ApprovalProgram,
+			ClearStateProgram:             params.ClearStateProgram,
+			LocalStateSchemaNumUint:       params.LocalStateSchema.NumUint,
+			LocalStateSchemaNumByteSlice:  params.LocalStateSchema.NumByteSlice,
+			GlobalStateSchemaNumUint:      params.GlobalStateSchema.NumUint,
+			GlobalStateSchemaNumByteSlice: params.GlobalStateSchema.NumByteSlice,
+			GlobalState:                   makeKeyValues(params.GlobalState),
+		})
+	}
+	sort.Slice(r.appParams, func(i, j int) bool { return r.appParams[i].AppID < r.appParams[j].AppID })
+
+	for aidx, state := range data.AppLocalStates {
+		r.appLocalStates = append(r.appLocalStates, AppLocalState{
+			Address:            addr,
+			AppID:              uint64(aidx),
+			SchemaNumUint:      state.Schema.NumUint,
+			SchemaNumByteSlice: state.Schema.NumByteSlice,
+			KeyValue:           makeKeyValues(state.KeyValue),
+		})
+	}
+	sort.Slice(r.appLocalStates, func(i, j int) bool { return r.appLocalStates[i].AppID < r.appLocalStates[j].AppID })
+	return
+}
+
+func makeKeyValues(tkv basics.TealKeyValue) (kvs []KeyValue) {
+	for key, value := range tkv {
+		kvs = append(kvs, KeyValue{
+			Key:   []byte(key),
+			Type:  value.Type,
+			Bytes: []byte(value.Bytes),
+			Uint:  value.Uint,
+		})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return string(kvs[i].Key) < string(kvs[j].Key) })
+	return
+}
+
+func tealKeyValue(kvs []KeyValue) basics.TealKeyValue {
+	if len(kvs) == 0 {
+		return nil
+	}
+	tkv := make(basics.TealKeyValue, len(kvs))
+	for _, kv := range kvs {
+		tkv[string(kv.Key)] = basics.TealValue{
+			Type:  kv.Type,
+			Bytes: string(kv.Bytes),
+			Uint:  kv.Uint,
+		}
+	}
+	return tkv
+}
+
+func parseStatus(status string) (basics.Status, error) {
+	for _, s := range []basics.Status{basics.Offline, basics.Online, basics.NotParticipating} {
+		if s.String() == status {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown account status %q", status)
+}
+
+// balancesBuilder rebuilds the state of the accounts from their records.
+type balancesBuilder struct {
+	balances map[basics.Address]basics.AccountData
+}
+
+func makeBalancesBuilder() *balancesBuilder {
+	return &balancesBuilder{balances: make(map[basics.Address]basics.AccountData)}
+}
+
+// data returns the data of the account of a creatable record, which has to
+// follow the record of the account itself.
+func (b *balancesBuilder) data(addr basics.Address) (basics.AccountData, error) {
+	data, ok := b.balances[addr]
+	if !ok {
+		return data, fmt.Errorf("snapshot has records for missing account %v", addr)
+	}
+	return data, nil
+}
+
+// add adds a record to the accounts.
+func (b *balancesBuilder) add(record interface{}) error {
+	switch r := record.(type) {
+	case *Account:
+		if _, ok := b.balances[r.Address]; ok {
+			return fmt.Errorf("snapshot has account %v twice", r.Address)
+		}
+		status, err := parseStatus(r.Status)
+		if err != nil {
+			return fmt.Errorf("account %v: %v", r.Address, err)
+		}
+		b.balances[r.Address] = basics.AccountData{
+			Status:             status,
+			MicroAlgos:         basics.MicroAlgos{Raw: r.MicroAlgos},
+			RewardsBase:        r.RewardsBase,
+			RewardedMicroAlgos: basics.MicroAlgos{Raw: r.RewardedMicroAlgos},
+			VoteID:             r.VoteID,
+			SelectionID:        r.SelectionID,
+			VoteFirstValid:     r.VoteFirstValid,
+			VoteLastValid:      r.VoteLastValid,
+			VoteKeyDilution:    r.VoteKeyDilution,
+			AuthAddr:           r.AuthAddr,
+			TotalAppSchema: basics.StateSchema{
+				NumUint:      r.TotalAppSchemaNumUint,
+				NumByteSlice: r.TotalAppSchemaNumByteSlice,
+			},
+		}
+
+	case *AssetHolding:
+		data, err := b.data(r.Address)
+		if err != nil {
+			return err
+		}
+		if data.Assets == nil {
+			data.Assets = make(map[basics.AssetIndex]basics.AssetHolding)
+		}
+		data.Assets[basics.AssetIndex(r.AssetID)] = basics.AssetHolding{
+			Amount: r.Amount,
+			Frozen: r.Frozen,
+		}
+		b.balances[r.Address] = data
+
+	case *AssetParams:
+		data, err := b.data(r.Creator)
+		if err != nil {
+			return err
+		}
+		if data.AssetParams == nil {
+			data.AssetParams = make(map[basics.AssetIndex]basics.AssetParams)
+		}
+		data.AssetParams[basics.AssetIndex(r.AssetID)] = basics.AssetParams{
+			Total:         r.Total,
+			Decimals:      r.Decimals,
+			DefaultFrozen: r.DefaultFrozen,
+			UnitName:      r.UnitName,
+			AssetName:     r.AssetName,
+			URL:           r.URL,
+			MetadataHash:  r.MetadataHash,
+			Manager:       r.Manager,
+			Reserve:       r.Reserve,
+			Freeze:        r.Freeze,
+			Clawback:      r.Clawback,
+		}
+		b.balances[r.Creator] = data
+
+	case *AppParams:
+		data, err := b.data(r.Creator)
+		if err != nil {
+			return err
+		}
+		if data.AppParams == nil {
+			data.AppParams = make(map[basics.AppIndex]basics.AppParams)
+		}
+		data.AppParams[basics.AppIndex(r.AppID)] = basics.AppParams{
+			ApprovalProgram:   r.ApprovalProgram,
+			ClearStateProgram: r.ClearStateProgram,
+			LocalStateSchema: basics.StateSchema{
+				NumUint:      r.LocalStateSchemaNumUint,
+				NumByteSlice: r.LocalStateSchemaNumByteSlice,
+			},
+			GlobalStateSchema: basics.StateSchema{
+				NumUint:      r.GlobalStateSchemaNumUint,
+				NumByteSlice: r.GlobalStateSchemaNumByteSlice,
+			},
+			GlobalState: tealKeyValue(r.GlobalState),
+		}
+		b.balances[r.Creator] = data
+
+	case *AppLocalState:
+		data, err := b.data(r.Address)
+		if err != nil {
+			return err
+		}
+		if data.AppLocalStates == nil {
+			data.AppLocalStates = make(map[basics.AppIndex]basics.AppLocalState)
+		}
+		data.AppLocalStates[basics.AppIndex(r.AppID)] = basics.AppLocalState{
+			Schema: basics.StateSchema{
+				NumUint:      r.SchemaNumUint,
+				NumByteSlice: r.SchemaNumByteSlice,
+			},
+			KeyValue: tealKeyValue(r.KeyValue),
+		}
+		b.balances[r.Address] = data
+
+	default:
+		return fmt.Errorf("unexpected snapshot record %T", record)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package snapshot writes and reads the state of the accounts of a ledger at
+// a round in formats that other tools can consume, as opposed to catchpoint
+// files, which only nodes can read.
+//
+// A snapshot is made of a header and of five tables: the base data of the
+// accounts, their asset holdings, the parameters of the assets they created,
+// the parameters and global state of the applications they created, and their
+// local states in applications. Each row of a table is a record of one of the
+// types of this package.
+package snapshot
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// Format is the encoding of a snapshot.
+type Format string
+
+const (
+	// FormatJSONL is a single file holding a JSON object per line. The first
+	// line holds the header, and each following line a single record, under
+	// the name of its type; the records of each account follow the one of
+	// the account itself.
+	FormatJSONL Format = "jsonl"
+
+	// FormatCSV is a directory holding a CSV file for the header and for
+	// each of the tables, whose first line names the columns. Byte strings
+	// are encoded in base64, and the key/value stores of the applications in
+	// JSON.
+	FormatCSV Format = "csv"
+
+	// FormatColumnar is a single msgpack file holding the header and the
+	// columns of each table, each of them an array of the values of one of
+	// the fields of the records. It is written once all the accounts are
+	// known, so its writer holds the whole snapshot in memory.
+	FormatColumnar Format = "columnar"
+)
+
+// Formats are the supported formats.
+var Formats = []Format{FormatJSONL, FormatCSV, FormatColumnar}
+
+// ParseFormat returns the format of the given name.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown snapshot format %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// table describes a table of the snapshot.
+type table struct {
+	name string
+	typ  reflect.Type
+}
+
+var headerTable = table{name: "header", typ: reflect.TypeOf(Header{})}
+
+// tables are the tables of the snapshot, in the order their records are
+// loaded: the records of the creatables need the one of their account.
+var tables = []table{
+	{name: "accounts", typ: reflect.TypeOf(Account{})},
+	{name: "asset-holdings", typ: reflect.TypeOf(AssetHolding{})},
+	{name: "asset-params", typ: reflect.TypeOf(AssetParams{})},
+	{name: "app-params", typ: reflect.TypeOf(AppParams{})},
+	{name: "app-local-states", typ: reflect.TypeOf(AppLocalState{})},
+}
+
+// columns returns the names of the fields of the records of the table.
+func (t table) columns() []string {
+	columns := make([]string, t.typ.NumField())
+	for i := range columns {
+		columns[i] = strings.Split(t.typ.Field(i).Tag.Get("codec"), ",")[0]
+	}
+	return columns
+}
+
+// visit calls f on each of the records of an account, along with the index
+// of its table.
+func (r *accountRecords) visit(f func(tableIndex int, record interface{}) error) error {
+	err := f(0, &r.account)
+	if err != nil {
+		return err
+	}
+	for i := range r.assetHoldings {
+		err = f(1, &r.assetHoldings[i])
+		if err != nil {
+			return err
+		}
+	}
+	for i := range r.assetParams {
+		err = f(2, &r.assetParams[i])
+		if err != nil {
+			return err
+		}
+	}
+	for i := range r.appParams {
+		err = f(3, &r.appParams[i])
+		if err != nil {
+			return err
+		}
+	}
+	for i := range r.appLocalStates {
+		err = f(4, &r.appLocalStates[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writer writes the state of the accounts to a snapshot.
+type Writer interface {
+	// Write adds an account to the snapshot.
+	Write(addr basics.Address, data basics.AccountData) error
+
+	// Close completes the snapshot. It has to be called for the snapshot
+	// to be usable.
+	Close() error
+}
+
+// Create starts writing a snapshot at path, in the given format. The
+// version of the header is set to the one of this package.
+func Create(path string, format Format, hdr Header) (Writer, error) {
+	hdr.Version = Version
+	switch format {
+	case FormatJSONL:
+		return makeJSONLWriter(path, hdr)
+	case FormatCSV:
+		return makeCSVWriter(path, hdr)
+	case FormatColumnar:
+		return makeColumnarWriter(path, hdr), nil
+	}
+	return nil, fmt.Errorf("unknown snapshot format %q", format)
+}
+
+// Load reads the snapshot at path, in the given format, and returns its
+// header and the state of its accounts.
+func Load(path string, format Format) (hdr Header, balances map[basics.Address]basics.AccountData, err error) {
+	b := makeBalancesBuilder()
+	switch format {
+	case FormatJSONL:
+		hdr, err = loadJSONL(path, b)
+	case FormatCSV:
+		hdr, err = loadCSV(path, b)
+	case FormatColumnar:
+		hdr, err = loadColumnar(path, b)
+	default:
+		err = fmt.Errorf("unknown snapshot format %q", format)
+	}
+	if err != nil {
+		return
+	}
+	return hdr, b.balances, nil
+}
+
+func checkVersion(hdr Header) error {
+	if hdr.Version != Version {
+		return fmt.Errorf("unsupported snapshot version %d, expected %d", hdr.Version, Version)
+	}
+	return nil
+}
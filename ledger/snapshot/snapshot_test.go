@@ -0,0 +1,234 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+func randomAddress() (addr basics.Address) {
+	crypto.RandBytes(addr[:])
+	return
+}
+
+// makeTestBalances returns accounts holding each kind of record.
+func makeTestBalances() map[basics.Address]basics.AccountData {
+	balances := make(map[basics.Address]basics.AccountData)
+
+	creator := randomAddress()
+	holder := randomAddress()
+	online := randomAddress()
+
+	data := basics.MakeAccountData(basics.Offline, basics.MicroAlgos{Raw: 5000000})
+	data.RewardsBase = 7
+	data.AuthAddr = holder
+	data.TotalAppSchema = basics.StateSchema{NumUint: 2, NumByteSlice: 1}
+	data.AssetParams = map[basics.AssetIndex]basics.AssetParams{
+		10: {
+			Total:     1000,
+			Decimals:  2,
+			UnitName:  "tok",
+			AssetName: "token, \"quoted\"\nand multiline",
+			URL:       "https://example.com",
+			Manager:   creator,
+			Clawback:  holder,
+		},
+		3: {Total: 1, DefaultFrozen: true},
+	}
+	data.Assets = map[basics.AssetIndex]basics.AssetHolding{10: {Amount: 900}}
+	data.AppParams = map[basics.AppIndex]basics.AppParams{
+		20: {
+			ApprovalProgram:   []byte{0x02, 0x20, 0x01, 0x01, 0x22},
+			ClearStateProgram: []byte{0x02, 0x20, 0x01, 0x01, 0x22},
+			LocalStateSchema:  basics.StateSchema{NumUint: 1},
+			GlobalStateSchema: basics.StateSchema{NumUint: 1, NumByteSlice: 1},
+			GlobalState: basics.TealKeyValue{
+				"counter":       {Type: basics.TealUintType, Uint: 42},
+				"\x00\xffowner": {Type: basics.TealBytesType, Bytes: string(creator[:])},
+			},
+		},
+	}
+	var hash [32]byte
+	crypto.RandBytes(hash[:])
+	params := data.AssetParams[3]
+	params.MetadataHash = hash
+	data.AssetParams[3] = params
+	balances[creator] = data
+
+	data = basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: 100})
+	data.RewardedMicroAlgos = basics.MicroAlgos{Raw: 3}
+	data.Assets = map[basics.AssetIndex]basics.AssetHolding{10: {Amount: 100, Frozen: true}, 3: {}}
+	data.AppLocalStates = map[basics.AppIndex]basics.AppLocalState{
+		20: {
+			Schema:   basics.StateSchema{NumUint: 1},
+			KeyValue: basics.TealKeyValue{"seen": {Type: basics.TealUintType, Uint: 1}},
+		},
+		21: {Schema: basics.StateSchema{NumByteSlice: 2}},
+	}
+	balances[holder] = data
+
+	data = basics.MakeAccountData(basics.Online, basics.MicroAlgos{Raw: 1234567})
+	crypto.RandBytes(data.VoteID[:])
+	crypto.RandBytes(data.SelectionID[:])
+	data.VoteFirstValid = 1
+	data.VoteLastValid = 3000000
+	data.VoteKeyDilution = 10000
+	balances[online] = data
+
+	return balances
+}
+
+// writeSnapshot writes the balances in address order, as the ledger does.
+func writeSnapshot(t *testing.T, path string, format Format, hdr Header, balances map[basics.Address]basics.AccountData) {
+	var addrs []basics.Address
+	for addr := range balances {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return strings.Compare(string(addrs[i][:]), string(addrs[j][:])) < 0 })
+
+	w, err := Create(path, format, hdr)
+	require.NoError(t, err)
+	for _, addr := range addrs {
+		require.NoError(t, w.Write(addr, balances[addr]))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	hdr := Header{
+		GenesisID:    "test-v1",
+		Round:        1234,
+		RewardsLevel: 56,
+	}
+	crypto.RandBytes(hdr.GenesisHash[:])
+	balances := makeTestBalances()
+
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			path := filepath.Join(dir, "snapshot."+string(format))
+			writeSnapshot(t, path, format, hdr, balances)
+
+			loadedHdr, loaded, err := Load(path, format)
+			require.NoError(t, err)
+			expectedHdr := hdr
+			expectedHdr.Version = Version
+			require.Equal(t, expectedHdr, loadedHdr)
+			require.Equal(t, balances, loaded)
+		})
+	}
+}
+
+func TestSnapshotEmpty(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, format := range Formats {
+		path := filepath.Join(dir, "empty."+string(format))
+		writeSnapshot(t, path, format, Header{Round: 1}, nil)
+
+		hdr, loaded, err := Load(path, format)
+		require.NoError(t, err, format)
+		require.Equal(t, basics.Round(1), hdr.Round)
+		require.Empty(t, loaded)
+	}
+}
+
+func TestSnapshotJSONLErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "snapshot.jsonl")
+	writeSnapshot(t, path, FormatJSONL, Header{Round: 1}, makeTestBalances())
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(string(content), "\n")
+
+	// the records of a creatable need the one of their account.
+	var reordered []string
+	reordered = append(reordered, lines[0])
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, `{"account"`) {
+			reordered = append(reordered, line)
+		}
+	}
+	require.NoError(t, ioutil.WriteFile(path, []byte(strings.Join(reordered, "")), 0666))
+	_, _, err = Load(path, FormatJSONL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing account")
+
+	// the header comes first.
+	require.NoError(t, ioutil.WriteFile(path, []byte(strings.Join(lines[1:], "")), 0666))
+	_, _, err = Load(path, FormatJSONL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "header")
+
+	// unknown fields are rejected.
+	require.NoError(t, ioutil.WriteFile(path, []byte(lines[0]+`{"account":{"address":"`+basics.Address{}.String()+`","unknown":1}}`+"\n"), 0666))
+	_, _, err = Load(path, FormatJSONL)
+	require.Error(t, err)
+}
+
+func TestSnapshotCSVColumnOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	addr := randomAddress()
+	balances := map[basics.Address]basics.AccountData{
+		addr: basics.MakeAccountData(basics.Offline, basics.MicroAlgos{Raw: 42}),
+	}
+	writeSnapshot(t, dir, FormatCSV, Header{Round: 1}, balances)
+
+	// the columns of the CSV files are matched by name.
+	accounts := "amount,address,status\n42," + addr.String() + ",Offline\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "accounts.csv"), []byte(accounts), 0666))
+	_, loaded, err := Load(dir, FormatCSV)
+	require.NoError(t, err)
+	require.Equal(t, balances, loaded)
+
+	accounts = "amount,address,balance\n42," + addr.String() + ",1\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "accounts.csv"), []byte(accounts), 0666))
+	_, _, err = Load(dir, FormatCSV)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown column")
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range Formats {
+		f, err := ParseFormat(string(format))
+		require.NoError(t, err)
+		require.Equal(t, format, f)
+	}
+	_, err := ParseFormat("parquet")
+	require.Error(t, err)
+}
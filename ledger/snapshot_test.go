@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// checkSnapshot takes a snapshot of the ledger at round rnd, and compares it
+// with the accounts the ledger looks up at the round of the snapshot.
+func checkSnapshot(t *testing.T, l *Ledger, dbPrefix string, rnd basics.Round, accounts int) basics.Round {
+	var addrs []basics.Address
+	snapshot := make(map[basics.Address]basics.AccountData)
+	var round basics.Round
+	var totals AccountTotals
+	begin := func(snapshotRound basics.Round, snapshotTotals AccountTotals) error {
+		round = snapshotRound
+		totals = snapshotTotals
+		return nil
+	}
+	err := SnapshotAccounts(dbPrefix, rnd, begin, func(addr basics.Address, data basics.AccountData) error {
+		if len(addrs) > 0 {
+			require.Equal(t, -1, bytes.Compare(addrs[len(addrs)-1][:], addr[:]))
+		}
+		addrs = append(addrs, addr)
+		snapshot[addr] = data
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, accounts, len(addrs))
+
+	for addr, data := range snapshot {
+		expected, err := l.LookupWithoutRewards(round, addr)
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	}
+
+	expectedTotals, err := l.Totals(round)
+	require.NoError(t, err)
+	require.Equal(t, expectedTotals, totals)
+	return round
+}
+
+func TestSnapshotAccounts(t *testing.T) {
+	dbTempDir, err := ioutil.TempDir("", "testdir"+t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dbTempDir)
+	dbPrefix := filepath.Join(dbTempDir, "ledger")
+
+	genesisInitState, initSecrets := testGenerateInitState(t, protocol.ConsensusCurrentVersion)
+	var addrs []basics.Address
+	for addr := range genesisInitState.Accounts {
+		if addr != testPoolAddr && addr != testSinkAddr {
+			addrs = append(addrs, addr)
+		}
+	}
+	creator := genesisInitState.Accounts[addrs[0]]
+	creator.AssetParams = map[basics.AssetIndex]basics.AssetParams{10: {Total: 100, UnitName: "tok"}}
+	creator.Assets = map[basics.AssetIndex]basics.AssetHolding{10: {Amount: 100}}
+	genesisInitState.Accounts[addrs[0]] = creator
+
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+	cfg.EnableAccountHistory = true
+	l, err := OpenLedger(logging.TestingLog(t), dbPrefix, false, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	for i := 0; i < int(proto.MaxBalLookback)+20; i++ {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addrs[i%len(addrs)],
+				Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid:  l.Latest() + 1,
+				LastValid:   l.Latest() + 10,
+				GenesisID:   t.Name(),
+				GenesisHash: genesisInitState.GenesisHash,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addrs[(i+1)%len(addrs)],
+				Amount:   basics.MicroAlgos{Raw: uint64(1000 + i)},
+			},
+		}
+		prev, err := l.BlockHdr(l.Latest())
+		require.NoError(t, err)
+		eval, err := l.StartEvaluator(bookkeeping.MakeBlock(prev).BlockHeader, 0)
+		require.NoError(t, err)
+		require.NoError(t, eval.Transaction(sign(initSecrets, tx), transactions.ApplyData{}))
+		vb, err := eval.GenerateBlock()
+		require.NoError(t, err)
+		require.NoError(t, l.AddValidatedBlock(*vb, agreement.Certificate{}))
+	}
+	l.WaitForCommit(l.Latest())
+
+	// the accounts are flushed at most once per balancesFlushInterval, so
+	// clear the timer to flush every round the lookback allows.
+	l.trackerMu.Lock()
+	l.accts.waitAccountsWriting()
+	l.accts.lastFlushTime = time.Time{}
+	l.trackers.committedUpTo(l.Latest())
+	l.trackerMu.Unlock()
+	l.accts.waitAccountsWriting()
+
+	round := checkSnapshot(t, l, dbPrefix, 0, len(genesisInitState.Accounts))
+	require.NotZero(t, round)
+	require.Equal(t, round, checkSnapshot(t, l, dbPrefix, round, len(genesisInitState.Accounts)))
+
+	// older rounds are read from the account history.
+	require.Equal(t, basics.Round(5), checkSnapshot(t, l, dbPrefix, 5, len(genesisInitState.Accounts)))
+
+	err = SnapshotAccounts(dbPrefix, l.Latest()+1, nil, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// sqliteIncrementalVacuum is the value of the auto_vacuum pragma of the
+// databases that are incrementally vacuumed.
+const sqliteIncrementalVacuum = 2
+
+// sqliteVacuumPages is the number of free pages reclaimSpace returns to the
+// file system.
+const sqliteVacuumPages = 1024
+
+// sqliteBlockStore is the blockStore of the blocks table of a SQLite
+// database.
+type sqliteBlockStore struct {
+	dbs dbPair
+	// vacuum is set if the database is incrementally vacuumed.
+	vacuum bool
+}
+
+func openSQLiteBlockStore(filename string, readOnly bool, dbMem bool) (*sqliteBlockStore, error) {
+	s := &sqliteBlockStore{}
+	var err error
+	if readOnly {
+		s.dbs.rdb, err = db.MakeAccessor(filename, true, dbMem)
+	} else {
+		// the space of the pruned blocks is given back to the file system incrementally.
+		s.dbs, err = dbOpenIncrementalVacuum(filename, dbMem)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.dbs.rdb.Atomic(func(tx *sql.Tx) error {
+		var autoVacuum int
+		err0 := tx.QueryRow("PRAGMA auto_vacuum").Scan(&autoVacuum)
+		s.vacuum = autoVacuum == sqliteIncrementalVacuum
+		return err0
+	})
+	if err != nil {
+		s.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *sqliteBlockStore) read(fn func(tx blockTx) error) error {
+	return s.dbs.rdb.Atomic(func(tx *sql.Tx) error {
+		return fn(sqliteBlockTx{tx})
+	})
+}
+
+func (s *sqliteBlockStore) write(fn func(tx blockTx) error) error {
+	if s.dbs.wdb.Handle == nil {
+		return fmt.Errorf("blocks database is read-only")
+	}
+	return s.dbs.wdb.Atomic(func(tx *sql.Tx) error {
+		return fn(sqliteBlockTx{tx})
+	})
+}
+
+func (s *sqliteBlockStore) reclaimSpace() error {
+	if !s.vacuum || s.dbs.wdb.Handle == nil {
+		return nil
+	}
+	return s.dbs.wdb.Atomic(func(tx *sql.Tx) error {
+		_, err := tx.Exec(fmt.Sprintf("PRAGMA incremental_vacuum(%d)", sqliteVacuumPages))
+		return err
+	})
+}
+
+// enableReclaimSpace converts a database created before the blocks got
+// pruned to incremental vacuum, with a one-time VACUUM.
+func (s *sqliteBlockStore) enableReclaimSpace() error {
+	if s.vacuum {
+		return nil
+	}
+	if s.dbs.wdb.Handle == nil {
+		return fmt.Errorf("blocks database is read-only")
+	}
+	_, err := s.dbs.wdb.EnableIncrementalVacuum(context.Background())
+	if err != nil {
+		return err
+	}
+	s.vacuum = true
+	return nil
+}
+
+func (s *sqliteBlockStore) setLogger(log logging.Logger) {
+	s.dbs.rdb.SetLogger(log)
+	if s.dbs.wdb.Handle != nil {
+		s.dbs.wdb.SetLogger(log)
+	}
+}
+
+func (s *sqliteBlockStore) close() {
+	s.dbs.close()
+}
+
+// sqliteBlockTx runs the blockdb.go queries in a SQLite transaction.
+type sqliteBlockTx struct {
+	tx *sql.Tx
+}
+
+func (t sqliteBlockTx) init(initBlocks []bookkeeping.Block) error {
+	return blockInit(t.tx, initBlocks)
+}
+
+func (t sqliteBlockTx) reset() error {
+	return blockResetDB(t.tx)
+}
+
+func (t sqliteBlockTx) get(rnd basics.Round) (bookkeeping.Block, error) {
+	return blockGet(t.tx, rnd)
+}
+
+func (t sqliteBlockTx) getHdr(rnd basics.Round) (bookkeeping.BlockHeader, error) {
+	return blockGetHdr(t.tx, rnd)
+}
+
+func (t sqliteBlockTx) getEncodedCert(rnd basics.Round) ([]byte, []byte, error) {
+	return blockGetEncodedCert(t.tx, rnd)
+}
+
+func (t sqliteBlockTx) getCert(rnd basics.Round) (bookkeeping.Block, agreement.Certificate, error) {
+	return blockGetCert(t.tx, rnd)
+}
+
+func (t sqliteBlockTx) put(blk bookkeeping.Block, cert agreement.Certificate) error {
+	return blockPut(t.tx, blk, cert)
+}
+
+func (t sqliteBlockTx) next() (basics.Round, error) {
+	return blockNext(t.tx)
+}
+
+func (t sqliteBlockTx) latest() (basics.Round, error) {
+	return blockLatest(t.tx)
+}
+
+func (t sqliteBlockTx) earliest() (basics.Round, error) {
+	return blockEarliest(t.tx)
+}
+
+func (t sqliteBlockTx) earliestPrunable(keepInterval uint64) (basics.Round, error) {
+	return blockEarliestPrunable(t.tx, keepInterval)
+}
+
+func (t sqliteBlockTx) forgetRange(start, end basics.Round, keepInterval uint64) error {
+	return blockForgetRange(t.tx, start, end, keepInterval)
+}
+
+func (t sqliteBlockTx) startCatchupStaging(blk bookkeeping.Block) error {
+	return blockStartCatchupStaging(t.tx, blk)
+}
+
+func (t sqliteBlockTx) putStaging(blk bookkeeping.Block) error {
+	return blockPutStaging(t.tx, blk)
+}
+
+func (t sqliteBlockTx) completeCatchup() error {
+	return blockCompleteCatchup(t.tx)
+}
+
+func (t sqliteBlockTx) abortCatchup() error {
+	return blockAbortCatchup(t.tx)
+}
+
+func (t sqliteBlockTx) ensureSingleBlock() (bookkeeping.Block, error) {
+	return blockEnsureSingleBlock(t.tx)
+}
@@ -0,0 +1,231 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// sqliteTrackerStore is the trackerStore of a SQLite database.
+type sqliteTrackerStore struct {
+	dbs dbPair
+}
+
+func openSQLiteTrackerStore(filename string, readOnly bool, dbMem bool) (*sqliteTrackerStore, error) {
+	s := &sqliteTrackerStore{}
+	var err error
+	if readOnly {
+		s.dbs.rdb, err = db.MakeAccessor(filename, true, dbMem)
+	} else {
+		s.dbs, err = dbOpen(filename, dbMem)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *sqliteTrackerStore) read(fn func(tx trackerTx) error) error {
+	return s.dbs.rdb.Atomic(func(tx *sql.Tx) error {
+		return fn(sqliteTrackerTx{tx})
+	})
+}
+
+func (s *sqliteTrackerStore) write(fn func(tx trackerTx) error) error {
+	if s.dbs.wdb.Handle == nil {
+		return fmt.Errorf("tracker database is read-only")
+	}
+	return s.dbs.wdb.Atomic(func(tx *sql.Tx) error {
+		return fn(sqliteTrackerTx{tx})
+	})
+}
+
+func (s *sqliteTrackerStore) queries() (trackerQueries, error) {
+	if s.dbs.wdb.Handle == nil {
+		return nil, fmt.Errorf("tracker database is read-only")
+	}
+	qs, err := accountsDbInit(s.dbs.rdb.Handle, s.dbs.wdb.Handle)
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (s *sqliteTrackerStore) historyQueries() (accountHistoryQueries, error) {
+	qs, err := accountHistoryDbInit(s.dbs.rdb.Handle)
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (s *sqliteTrackerStore) setLogger(log logging.Logger) {
+	s.dbs.rdb.SetLogger(log)
+	if s.dbs.wdb.Handle != nil {
+		s.dbs.wdb.SetLogger(log)
+	}
+}
+
+func (s *sqliteTrackerStore) close() {
+	s.dbs.close()
+}
+
+// sqliteTrackerTx runs the accountdb.go and accounthistory.go queries in a
+// SQLite transaction.
+type sqliteTrackerTx struct {
+	tx *sql.Tx
+}
+
+func (t sqliteTrackerTx) queries() (trackerQueries, error) {
+	qs, err := accountsDbInit(t.tx, t.tx)
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (t sqliteTrackerTx) accountsInit(initAccounts map[basics.Address]basics.AccountData, proto config.ConsensusParams) error {
+	return accountsInit(t.tx, initAccounts, proto)
+}
+
+func (t sqliteTrackerTx) accountsReset() error {
+	return accountsReset(t.tx)
+}
+
+func (t sqliteTrackerTx) accountsRound() (basics.Round, basics.Round, error) {
+	return accountsRound(t.tx)
+}
+
+func (t sqliteTrackerTx) updateAccountsRound(rnd basics.Round, hashRound basics.Round) error {
+	return updateAccountsRound(t.tx, rnd, hashRound)
+}
+
+func (t sqliteTrackerTx) accountsAll() (map[basics.Address]basics.AccountData, error) {
+	return accountsAll(t.tx)
+}
+
+func (t sqliteTrackerTx) accountsTotals(catchpointStaging bool) (AccountTotals, error) {
+	return accountsTotals(t.tx, catchpointStaging)
+}
+
+func (t sqliteTrackerTx) accountsPutTotals(totals AccountTotals, catchpointStaging bool) error {
+	return accountsPutTotals(t.tx, totals, catchpointStaging)
+}
+
+func (t sqliteTrackerTx) accountsNewRound(updates map[basics.Address]accountDelta, rewardsLevel uint64, proto config.ConsensusParams) error {
+	return accountsNewRound(t.tx, updates, rewardsLevel, proto)
+}
+
+func (t sqliteTrackerTx) encodedAccountsRange(catchpointStaging bool, startAccountIndex, accountCount int) ([]encodedBalanceRecord, error) {
+	return encodedAccountsRange(t.tx, catchpointStaging, startAccountIndex, accountCount)
+}
+
+func (t sqliteTrackerTx) encodedResourcesRange(catchpointStaging bool, startResourceIndex, resourceCount int) ([]encodedResourceRecord, error) {
+	return encodedResourcesRange(t.tx, catchpointStaging, startResourceIndex, resourceCount)
+}
+
+func (t sqliteTrackerTx) encodedFullAccountsRange(catchpointStaging bool, startAccountIndex, accountCount int) ([]encodedBalanceRecord, error) {
+	return encodedFullAccountsRange(t.tx, catchpointStaging, startAccountIndex, accountCount)
+}
+
+func (t sqliteTrackerTx) totalAccounts(ctx context.Context) (uint64, error) {
+	return totalAccounts(ctx, t.tx)
+}
+
+func (t sqliteTrackerTx) totalResources(ctx context.Context) (uint64, error) {
+	return totalResources(ctx, t.tx)
+}
+
+func (t sqliteTrackerTx) merkleCommitter(staging bool) (merkletrie.Committer, error) {
+	mc, err := makeMerkleCommitter(t.tx, staging)
+	if err != nil {
+		return nil, err
+	}
+	return mc, nil
+}
+
+func (t sqliteTrackerTx) resetAccountHashes() error {
+	return resetAccountHashes(t.tx)
+}
+
+func (t sqliteTrackerTx) getCatchpoint(round basics.Round) (string, string, int64, error) {
+	return getCatchpoint(t.tx, round)
+}
+
+func (t sqliteTrackerTx) storedCatchpointLabels() (map[basics.Round]string, error) {
+	return storedCatchpointLabels(t.tx)
+}
+
+func (t sqliteTrackerTx) resetCatchpointStagingBalances(ctx context.Context, newCatchup bool) error {
+	return resetCatchpointStagingBalances(ctx, t.tx, newCatchup)
+}
+
+func (t sqliteTrackerTx) writeCatchpointStagingBalances(ctx context.Context, bals []encodedBalanceRecord) error {
+	return writeCatchpointStagingBalances(ctx, t.tx, bals)
+}
+
+func (t sqliteTrackerTx) writeCatchpointStagingResources(ctx context.Context, resources []encodedResourceRecord) error {
+	return writeCatchpointStagingResources(ctx, t.tx, resources)
+}
+
+func (t sqliteTrackerTx) writeCatchpointStagingCreatable(ctx context.Context, addr basics.Address, cidx basics.CreatableIndex, ctype basics.CreatableType) error {
+	return writeCatchpointStagingCreatable(ctx, t.tx, addr, cidx, ctype)
+}
+
+func (t sqliteTrackerTx) resetCatchpointStagingHashes(ctx context.Context) error {
+	return resetCatchpointStagingHashes(ctx, t.tx)
+}
+
+func (t sqliteTrackerTx) applyCatchpointStagingBalances(ctx context.Context, balancesRound basics.Round) error {
+	return applyCatchpointStagingBalances(ctx, t.tx, balancesRound)
+}
+
+func (t sqliteTrackerTx) accountsInitHistory(rnd basics.Round) error {
+	return accountsInitHistory(t.tx, rnd)
+}
+
+func (t sqliteTrackerTx) accountsResetHistory() error {
+	return accountsResetHistory(t.tx)
+}
+
+func (t sqliteTrackerTx) accountsHistoryRound() (basics.Round, error) {
+	return accountsHistoryRound(t.tx)
+}
+
+func (t sqliteTrackerTx) accountsHistoryNewRound(rnd basics.Round, updates map[basics.Address]accountDelta, totals AccountTotals) error {
+	return accountsHistoryNewRound(t.tx, rnd, updates, totals)
+}
+
+func (t sqliteTrackerTx) accountsHistoryTotals(rnd basics.Round) (AccountTotals, error) {
+	return accountsHistoryTotals(t.tx, rnd)
+}
+
+func (t sqliteTrackerTx) snapshotAccountsCurrent(visit func(basics.Address, basics.AccountData) error) error {
+	return snapshotAccountsCurrent(t.tx, visit)
+}
+
+func (t sqliteTrackerTx) snapshotAccountsHistory(rnd basics.Round, visit func(basics.Address, basics.AccountData) error) error {
+	return snapshotAccountsHistory(t.tx, rnd, visit)
+}
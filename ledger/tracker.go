@@ -82,8 +82,8 @@ type ledgerTracker interface {
 // ledgerForTracker defines the part of the ledger that a tracker can
 // access.  This is particularly useful for testing trackers in isolation.
 type ledgerForTracker interface {
-	trackerDB() dbPair
-	blockDB() dbPair
+	trackerDB() trackerStore
+	blockDB() blockStore
 	trackerLog() logging.Logger
 	trackerEvalVerified(bookkeeping.Block) (StateDelta, error)
 
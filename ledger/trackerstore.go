@@ -0,0 +1,210 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
+)
+
+// The storage backends of the tracker database, as set by the
+// TrackerStorageBackend config parameter.
+const (
+	trackerStoreSQLite = "sqlite"
+	trackerStoreKV     = "kv"
+)
+
+// trackerStore is the tracker database of the ledger. It holds the accounts
+// at the round of the accountUpdates tracker along with their merkle trie,
+// the catchpoints, the account history of the archival ledgers, and the
+// accounts a catchpoint catchup stages before they replace them.
+type trackerStore interface {
+	// read runs fn in a transaction that doesn't write to the database.
+	read(fn func(tx trackerTx) error) error
+
+	// write runs fn in a read-write transaction, whose changes are all
+	// committed if fn returns nil, and discarded otherwise. fn may be run
+	// more than once if the transaction has to be retried.
+	write(fn func(tx trackerTx) error) error
+
+	// queries returns the lookups the trackers run outside of the
+	// transactions; each of them runs in a transaction of its own.
+	queries() (trackerQueries, error)
+
+	// historyQueries returns the lookups of the account history.
+	historyQueries() (accountHistoryQueries, error)
+
+	setLogger(log logging.Logger)
+	close()
+}
+
+// trackerQueries are the lookups of the accounts and of the catchpoints of a
+// trackerStore. They have the semantics of the accountsDbQueries methods of
+// the same names.
+type trackerQueries interface {
+	listCreatables(maxIdx basics.CreatableIndex, maxResults uint64, ctype basics.CreatableType) ([]basics.CreatableLocator, error)
+	lookupCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error)
+	lookup(addr basics.Address) (basics.AccountData, error)
+
+	storeCatchpoint(ctx context.Context, round basics.Round, fileName string, catchpoint string, fileSize int64) error
+	getOldestCatchpointFiles(ctx context.Context, fileCount int, filesToKeep int) (map[basics.Round]string, error)
+	readCatchpointStateUint64(ctx context.Context, stateName catchpointState) (uint64, bool, error)
+	writeCatchpointStateUint64(ctx context.Context, stateName catchpointState, setValue uint64) (bool, error)
+	readCatchpointStateString(ctx context.Context, stateName catchpointState) (string, bool, error)
+	writeCatchpointStateString(ctx context.Context, stateName catchpointState, setValue string) (bool, error)
+}
+
+// accountHistoryQueries are the lookups of the account history of a
+// trackerStore. They have the semantics of the accountHistoryDbQueries
+// methods of the same names.
+type accountHistoryQueries interface {
+	lookup(addr basics.Address, rnd basics.Round) (basics.AccountData, error)
+	modified(rnd basics.Round) (map[basics.Address]basics.AccountData, error)
+	totals(rnd basics.Round) (AccountTotals, error)
+}
+
+// trackerTx is a transaction of a trackerStore. Its methods have the
+// semantics of the accountdb.go and accounthistory.go functions of the same
+// names, including the sql.ErrNoRows errors of the records that are missing.
+type trackerTx interface {
+	// queries returns the trackerQueries of the transaction.
+	queries() (trackerQueries, error)
+
+	accountsInit(initAccounts map[basics.Address]basics.AccountData, proto config.ConsensusParams) error
+	accountsReset() error
+	accountsRound() (rnd basics.Round, hashrnd basics.Round, err error)
+	updateAccountsRound(rnd basics.Round, hashRound basics.Round) error
+	accountsAll() (map[basics.Address]basics.AccountData, error)
+	accountsTotals(catchpointStaging bool) (AccountTotals, error)
+	accountsPutTotals(totals AccountTotals, catchpointStaging bool) error
+	accountsNewRound(updates map[basics.Address]accountDelta, rewardsLevel uint64, proto config.ConsensusParams) error
+
+	encodedAccountsRange(catchpointStaging bool, startAccountIndex, accountCount int) ([]encodedBalanceRecord, error)
+	encodedResourcesRange(catchpointStaging bool, startResourceIndex, resourceCount int) ([]encodedResourceRecord, error)
+	encodedFullAccountsRange(catchpointStaging bool, startAccountIndex, accountCount int) ([]encodedBalanceRecord, error)
+	totalAccounts(ctx context.Context) (uint64, error)
+	totalResources(ctx context.Context) (uint64, error)
+
+	// merkleCommitter returns the committer of the pages of the balances
+	// trie, or of the staging one, to the transaction.
+	merkleCommitter(staging bool) (merkletrie.Committer, error)
+	resetAccountHashes() error
+
+	getCatchpoint(round basics.Round) (fileName string, catchpoint string, fileSize int64, err error)
+	storedCatchpointLabels() (map[basics.Round]string, error)
+
+	resetCatchpointStagingBalances(ctx context.Context, newCatchup bool) error
+	writeCatchpointStagingBalances(ctx context.Context, bals []encodedBalanceRecord) error
+	writeCatchpointStagingResources(ctx context.Context, resources []encodedResourceRecord) error
+	writeCatchpointStagingCreatable(ctx context.Context, addr basics.Address, cidx basics.CreatableIndex, ctype basics.CreatableType) error
+	resetCatchpointStagingHashes(ctx context.Context) error
+	applyCatchpointStagingBalances(ctx context.Context, balancesRound basics.Round) error
+
+	accountsInitHistory(rnd basics.Round) error
+	accountsResetHistory() error
+	accountsHistoryRound() (basics.Round, error)
+	accountsHistoryNewRound(rnd basics.Round, updates map[basics.Address]accountDelta, totals AccountTotals) error
+	accountsHistoryTotals(rnd basics.Round) (AccountTotals, error)
+
+	snapshotAccountsCurrent(visit func(basics.Address, basics.AccountData) error) error
+	snapshotAccountsHistory(rnd basics.Round, visit func(basics.Address, basics.AccountData) error) error
+}
+
+// trackerStorePath returns the name of the file, or of the directory for the
+// kv backend, of the tracker database of the ledger stored at dbPathPrefix.
+func trackerStorePath(backend string, dbPathPrefix string, dbMem bool) (string, error) {
+	switch backend {
+	case trackerStoreSQLite, "":
+		trackerDBFilename, _, err := ledgerDBFilenames(dbPathPrefix, dbMem)
+		return trackerDBFilename, err
+	case trackerStoreKV:
+		return dbPathPrefix + ".tracker.kv", nil
+	default:
+		return "", fmt.Errorf("unknown tracker storage backend %q", backend)
+	}
+}
+
+// openTrackerStore opens the tracker database of the ledger stored at
+// dbPathPrefix with the given backend. A read-only database must exist.
+//
+// The accounts aren't converted from one backend to the other, so it fails
+// if the ledger already exists with another backend.
+func openTrackerStore(backend string, dbPathPrefix string, readOnly bool, dbMem bool) (trackerStore, error) {
+	path, err := trackerStorePath(backend, dbPathPrefix, dbMem)
+	if err != nil {
+		return nil, err
+	}
+	if !dbMem {
+		err = checkTrackerStoreBackend(backend, dbPathPrefix, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if backend == trackerStoreKV {
+		return openKVTrackerStore(path, readOnly, dbMem)
+	}
+	return openSQLiteTrackerStore(path, readOnly, dbMem)
+}
+
+// checkTrackerStoreBackend makes sure that the ledger stored at dbPathPrefix
+// doesn't keep its accounts with another backend than the given one.
+func checkTrackerStoreBackend(backend string, dbPathPrefix string, path string) error {
+	_, err := os.Stat(path)
+	if err == nil || !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, other := range []string{trackerStoreSQLite, trackerStoreKV} {
+		if other == backend || (backend == "" && other == trackerStoreSQLite) {
+			continue
+		}
+		otherPath, err := trackerStorePath(other, dbPathPrefix, false)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stat(otherPath)
+		if err == nil {
+			return fmt.Errorf("the ledger stores its accounts in %s with the %s backend, not the %s one", otherPath, other, backend)
+		}
+	}
+	return nil
+}
+
+// existingTrackerStoreBackend returns the backend of the tracker database of
+// the ledger stored at dbPathPrefix, which must exist.
+func existingTrackerStoreBackend(dbPathPrefix string) (string, error) {
+	var err error
+	for _, backend := range []string{trackerStoreSQLite, trackerStoreKV} {
+		var path string
+		path, err = trackerStorePath(backend, dbPathPrefix, false)
+		if err != nil {
+			return "", err
+		}
+		_, err = os.Stat(path)
+		if err == nil {
+			return backend, nil
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,899 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var trackerStoreBackends = []string{trackerStoreSQLite, trackerStoreKV}
+
+// testTrackerStore runs a conformance test against each backend, with a
+// tracker database of its own in a temporary directory. The test gets the
+// prefix of the database, to reopen it.
+func testTrackerStore(t *testing.T, test func(t *testing.T, backend string, dbPrefix string, ts trackerStore)) {
+	for _, backend := range trackerStoreBackends {
+		t.Run(backend, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "trackerstore")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			dbPrefix := filepath.Join(dir, "ledger")
+
+			ts, err := openTrackerStore(backend, dbPrefix, false, false)
+			require.NoError(t, err)
+			ts.setLogger(logging.TestingLog(t))
+			defer func() { ts.close() }()
+			test(t, backend, dbPrefix, ts)
+		})
+	}
+}
+
+// trackerTestAccounts returns random accounts, some of which hold assets and
+// applications. The indices of the assets and of the applications don't
+// overlap, since the SQLite creators table is keyed by index.
+func trackerTestAccounts(n int) map[basics.Address]basics.AccountData {
+	accts := randomAccounts(n)
+	idx := uint64(1)
+	for addr, data := range accts {
+		if idx > uint64(n) {
+			break
+		}
+		data.AssetParams = map[basics.AssetIndex]basics.AssetParams{basics.AssetIndex(idx): {Total: idx}}
+		data.Assets = map[basics.AssetIndex]basics.AssetHolding{basics.AssetIndex(idx): {Amount: idx}}
+		data.AppParams = map[basics.AppIndex]basics.AppParams{basics.AppIndex(idx + 1): {}}
+		data.AppLocalStates = map[basics.AppIndex]basics.AppLocalState{basics.AppIndex(idx + 1): {}}
+		accts[addr] = data
+		idx += 2
+	}
+	return accts
+}
+
+// trackerTestTotals returns the totals of the accounts, with no rewards.
+func trackerTestTotals(accts map[basics.Address]basics.AccountData) AccountTotals {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	var ot basics.OverflowTracker
+	var totals AccountTotals
+	for _, data := range accts {
+		totals.addAccount(proto, data, &ot)
+	}
+	return totals
+}
+
+// trackerTestRecords returns the encoded records the accounts are stored
+// as, the way a catchpoint file holds them.
+func trackerTestRecords(accts map[basics.Address]basics.AccountData) (bals []encodedBalanceRecord, resources []encodedResourceRecord) {
+	for addr, data := range accts {
+		base, accountResources := splitAccountData(data)
+		bals = append(bals, encodedBalanceRecord{Address: addr, AccountData: protocol.Encode(&base)})
+		for key, rdata := range accountResources {
+			resources = append(resources, encodedResourceRecord{
+				Address:        addr,
+				CreatableIndex: key.cidx,
+				CreatableType:  key.ctype,
+				Data:           protocol.Encode(&rdata),
+			})
+		}
+	}
+	return
+}
+
+// storedAccounts is what a trackerStore holds for some accounts. The
+// accounts are read in a transaction and checked afterwards, since a failed
+// assertion doesn't end the SQLite transactions it happens in.
+type storedAccounts struct {
+	rnd, hashRound basics.Round
+	totals         AccountTotals
+	all            map[basics.Address]basics.AccountData
+	lookups        map[basics.Address]basics.AccountData
+	creators       map[basics.CreatableIndex]basics.Address
+	assets         []basics.CreatableLocator
+	apps           []basics.CreatableLocator
+}
+
+func readStoredAccounts(t *testing.T, ts trackerStore, accts map[basics.Address]basics.AccountData) (stored storedAccounts) {
+	stored.lookups = make(map[basics.Address]basics.AccountData)
+	stored.creators = make(map[basics.CreatableIndex]basics.Address)
+	err := ts.read(func(tx trackerTx) (err error) {
+		stored.rnd, stored.hashRound, err = tx.accountsRound()
+		if err != nil {
+			return
+		}
+		stored.totals, err = tx.accountsTotals(false)
+		if err != nil {
+			return
+		}
+		stored.all, err = tx.accountsAll()
+		return
+	})
+	require.NoError(t, err)
+
+	qs, err := ts.queries()
+	require.NoError(t, err)
+	for addr, data := range accts {
+		stored.lookups[addr], err = qs.lookup(addr)
+		require.NoError(t, err)
+		for aidx := range data.AssetParams {
+			creator, ok, err := qs.lookupCreator(basics.CreatableIndex(aidx), basics.AssetCreatable)
+			require.NoError(t, err)
+			require.True(t, ok)
+			stored.creators[basics.CreatableIndex(aidx)] = creator
+		}
+		for aidx := range data.AppParams {
+			creator, ok, err := qs.lookupCreator(basics.CreatableIndex(aidx), basics.AppCreatable)
+			require.NoError(t, err)
+			require.True(t, ok)
+			stored.creators[basics.CreatableIndex(aidx)] = creator
+		}
+	}
+	stored.assets, err = qs.listCreatables(basics.CreatableIndex(1<<62), 1<<20, basics.AssetCreatable)
+	require.NoError(t, err)
+	stored.apps, err = qs.listCreatables(basics.CreatableIndex(1<<62), 1<<20, basics.AppCreatable)
+	require.NoError(t, err)
+	return
+}
+
+// checkTrackerAccounts checks that a trackerStore holds exactly the accounts
+// at round rnd.
+func checkTrackerAccounts(t *testing.T, ts trackerStore, rnd basics.Round, accts map[basics.Address]basics.AccountData) {
+	stored := readStoredAccounts(t, ts, accts)
+	require.Equal(t, rnd, stored.rnd)
+	require.Equal(t, trackerTestTotals(accts), stored.totals)
+	require.Equal(t, accts, stored.all)
+	require.Equal(t, accts, stored.lookups)
+
+	creators := make(map[basics.CreatableIndex]basics.Address)
+	var assets, apps int
+	for addr, data := range accts {
+		for aidx := range data.AssetParams {
+			creators[basics.CreatableIndex(aidx)] = addr
+			assets++
+		}
+		for aidx := range data.AppParams {
+			creators[basics.CreatableIndex(aidx)] = addr
+			apps++
+		}
+	}
+	require.Equal(t, creators, stored.creators)
+	require.Equal(t, assets, len(stored.assets))
+	require.Equal(t, apps, len(stored.apps))
+	// the creatables are listed from the highest index down.
+	for _, list := range [][]basics.CreatableLocator{stored.assets, stored.apps} {
+		for i, cl := range list {
+			require.Equal(t, creators[cl.Index], cl.Creator)
+			if i > 0 {
+				require.Less(t, uint64(cl.Index), uint64(list[i-1].Index))
+			}
+		}
+	}
+}
+
+func TestTrackerStoreAccounts(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	testTrackerStore(t, func(t *testing.T, backend string, dbPrefix string, ts trackerStore) {
+		accts := trackerTestAccounts(20)
+		err := ts.write(func(tx trackerTx) error {
+			return tx.accountsInit(accts, proto)
+		})
+		require.NoError(t, err)
+		checkTrackerAccounts(t, ts, 0, accts)
+
+		// the accounts of an initialized database are kept.
+		err = ts.write(func(tx trackerTx) error {
+			return tx.accountsInit(trackerTestAccounts(5), proto)
+		})
+		require.NoError(t, err)
+		checkTrackerAccounts(t, ts, 0, accts)
+
+		for rnd := basics.Round(1); rnd <= 5; rnd++ {
+			updates, newAccts, _ := randomDeltas(10, accts, 0)
+			// a creator closes, and a new account creates an asset.
+			for addr, data := range accts {
+				if len(data.AssetParams) > 0 {
+					updates[addr] = accountDelta{old: data}
+					delete(newAccts, addr)
+					break
+				}
+			}
+			creator := randomAddress()
+			created := randomAccountData(0)
+			created.AssetParams = map[basics.AssetIndex]basics.AssetParams{basics.AssetIndex(1000 + rnd): {Total: 1}}
+			updates[creator] = accountDelta{new: created}
+			newAccts[creator] = created
+
+			err = ts.write(func(tx trackerTx) error {
+				err := tx.accountsNewRound(updates, 0, proto)
+				if err != nil {
+					return err
+				}
+				return tx.updateAccountsRound(rnd, rnd)
+			})
+			require.NoError(t, err)
+			accts = newAccts
+			checkTrackerAccounts(t, ts, rnd, accts)
+		}
+
+		stored := readStoredAccounts(t, ts, nil)
+		require.Equal(t, basics.Round(5), stored.hashRound)
+		err = ts.write(func(tx trackerTx) error {
+			return tx.updateAccountsRound(4, 4)
+		})
+		require.Error(t, err)
+
+		// the accounts are still there once the database is reopened.
+		ts.close()
+		ts, err = openTrackerStore(backend, dbPrefix, false, false)
+		require.NoError(t, err)
+		checkTrackerAccounts(t, ts, 5, accts)
+
+		var missingErr error
+		err = ts.read(func(tx trackerTx) error {
+			_, missingErr = tx.accountsTotals(true)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, sql.ErrNoRows, missingErr)
+
+		// a reset database gets initialized again.
+		err = ts.write(func(tx trackerTx) error {
+			return tx.accountsReset()
+		})
+		require.NoError(t, err)
+		accts = trackerTestAccounts(3)
+		err = ts.write(func(tx trackerTx) error {
+			return tx.accountsInit(accts, proto)
+		})
+		require.NoError(t, err)
+		checkTrackerAccounts(t, ts, 0, accts)
+	})
+}
+
+func TestTrackerStoreRanges(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	testTrackerStore(t, func(t *testing.T, backend string, dbPrefix string, ts trackerStore) {
+		accts := trackerTestAccounts(25)
+		err := ts.write(func(tx trackerTx) error {
+			return tx.accountsInit(accts, proto)
+		})
+		require.NoError(t, err)
+		bals, resources := trackerTestRecords(accts)
+
+		var gotBals, gotFull []encodedBalanceRecord
+		var gotResources []encodedResourceRecord
+		var totalAccounts, totalResources uint64
+		err = ts.read(func(tx trackerTx) (err error) {
+			for offset := 0; ; offset += 7 {
+				chunk, err := tx.encodedAccountsRange(false, offset, 7)
+				if err != nil {
+					return err
+				}
+				gotBals = append(gotBals, chunk...)
+				full, err := tx.encodedFullAccountsRange(false, offset, 7)
+				if err != nil {
+					return err
+				}
+				gotFull = append(gotFull, full...)
+				if len(chunk) < 7 {
+					break
+				}
+			}
+			for offset := 0; ; offset += 7 {
+				chunk, err := tx.encodedResourcesRange(false, offset, 7)
+				if err != nil {
+					return err
+				}
+				gotResources = append(gotResources, chunk...)
+				if len(chunk) < 7 {
+					break
+				}
+			}
+			totalAccounts, err = tx.totalAccounts(context.Background())
+			if err != nil {
+				return
+			}
+			totalResources, err = tx.totalResources(context.Background())
+			return
+		})
+		require.NoError(t, err)
+
+		require.ElementsMatch(t, bals, gotBals)
+		require.ElementsMatch(t, resources, gotResources)
+		require.Equal(t, uint64(len(bals)), totalAccounts)
+		require.Equal(t, uint64(len(resources)), totalResources)
+
+		// the full accounts are listed in the order of the accounts.
+		require.Equal(t, len(gotBals), len(gotFull))
+		for i, balance := range gotFull {
+			require.Equal(t, gotBals[i].Address, balance.Address)
+			var data basics.AccountData
+			require.NoError(t, protocol.Decode(balance.AccountData, &data))
+			require.Equal(t, accts[balance.Address], data)
+		}
+	})
+}
+
+func TestTrackerStoreMerkleCommitter(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	testTrackerStore(t, func(t *testing.T, backend string, dbPrefix string, ts trackerStore) {
+		err := ts.write(func(tx trackerTx) error {
+			err := tx.accountsInit(nil, proto)
+			if err != nil {
+				return err
+			}
+			return tx.resetCatchpointStagingBalances(context.Background(), true)
+		})
+		require.NoError(t, err)
+
+		var hashes [][]byte
+		expected, err := merkletrie.MakeTrie(&merkletrie.InMemoryCommitter{}, trieCachedNodesCount)
+		require.NoError(t, err)
+		for i := 0; i < 1000; i++ {
+			hash := crypto.Hash([]byte{byte(i), byte(i >> 8)})
+			hashes = append(hashes, hash[:])
+			_, err = expected.Add(hash[:])
+			require.NoError(t, err)
+		}
+		expectedRoot, err := expected.RootHash()
+		require.NoError(t, err)
+
+		// the trie is built over a few transactions, the way the
+		// accountUpdates and the catchpoint catchup do.
+		for _, staging := range []bool{false, true} {
+			for i := 0; i < len(hashes); i += 250 {
+				err = ts.write(func(tx trackerTx) error {
+					mc, err := tx.merkleCommitter(staging)
+					if err != nil {
+						return err
+					}
+					trie, err := merkletrie.MakeTrie(mc, trieCachedNodesCount)
+					if err != nil {
+						return err
+					}
+					for _, hash := range hashes[i : i+250] {
+						_, err = trie.Add(hash)
+						if err != nil {
+							return err
+						}
+					}
+					return trie.Commit()
+				})
+				require.NoError(t, err)
+			}
+		}
+
+		readRoot := func(staging bool) (root crypto.Digest) {
+			err := ts.read(func(tx trackerTx) error {
+				mc, err := tx.merkleCommitter(staging)
+				if err != nil {
+					return err
+				}
+				trie, err := merkletrie.MakeTrie(mc, trieCachedNodesCount)
+				if err != nil {
+					return err
+				}
+				root, err = trie.RootHash()
+				return err
+			})
+			require.NoError(t, err)
+			return
+		}
+		require.Equal(t, expectedRoot, readRoot(false))
+		require.Equal(t, expectedRoot, readRoot(true))
+
+		// empty pages are deleted.
+		var page []byte
+		err = ts.write(func(tx trackerTx) error {
+			mc, err := tx.merkleCommitter(false)
+			if err != nil {
+				return err
+			}
+			err = mc.StorePage(1<<40, []byte{1, 2, 3})
+			if err != nil {
+				return err
+			}
+			err = mc.StorePage(1<<40, nil)
+			if err != nil {
+				return err
+			}
+			page, err = mc.LoadPage(1 << 40)
+			return err
+		})
+		require.NoError(t, err)
+		require.Nil(t, page)
+
+		err = ts.write(func(tx trackerTx) error {
+			return tx.resetAccountHashes()
+		})
+		require.NoError(t, err)
+		require.True(t, readRoot(false).IsZero())
+		require.Equal(t, expectedRoot, readRoot(true))
+
+		err = ts.write(func(tx trackerTx) error {
+			return tx.resetCatchpointStagingHashes(context.Background())
+		})
+		require.NoError(t, err)
+		require.True(t, readRoot(true).IsZero())
+	})
+}
+
+func TestTrackerStoreCatchpoints(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	testTrackerStore(t, func(t *testing.T, backend string, dbPrefix string, ts trackerStore) {
+		ctx := context.Background()
+		err := ts.write(func(tx trackerTx) error {
+			return tx.accountsInit(nil, proto)
+		})
+		require.NoError(t, err)
+		qs, err := ts.queries()
+		require.NoError(t, err)
+
+		for _, rnd := range []basics.Round{10, 20, 30, 40} {
+			err = qs.storeCatchpoint(ctx, rnd, catchpointRoundToPath(rnd), fmt.Sprintf("%d#label", rnd), int64(rnd)*100)
+			require.NoError(t, err)
+		}
+		err = qs.storeCatchpoint(ctx, 30, "", "", 0)
+		require.NoError(t, err)
+
+		var labels map[basics.Round]string
+		var fileName, label string
+		var fileSize int64
+		var missingErr error
+		err = ts.read(func(tx trackerTx) (err error) {
+			labels, err = tx.storedCatchpointLabels()
+			if err != nil {
+				return
+			}
+			_, _, _, missingErr = tx.getCatchpoint(30)
+			fileName, label, fileSize, err = tx.getCatchpoint(20)
+			return
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[basics.Round]string{10: "10#label", 20: "20#label", 40: "40#label"}, labels)
+		require.Equal(t, sql.ErrNoRows, missingErr)
+		require.Equal(t, catchpointRoundToPath(20), fileName)
+		require.Equal(t, "20#label", label)
+		require.Equal(t, int64(2000), fileSize)
+
+		fileNames, err := qs.getOldestCatchpointFiles(ctx, 1, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[basics.Round]string{10: catchpointRoundToPath(10)}, fileNames)
+		fileNames, err = qs.getOldestCatchpointFiles(ctx, 5, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[basics.Round]string{10: catchpointRoundToPath(10), 20: catchpointRoundToPath(20)}, fileNames)
+		fileNames, err = qs.getOldestCatchpointFiles(ctx, 5, 3)
+		require.NoError(t, err)
+		require.Empty(t, fileNames)
+
+		// the catchpoint state records hold numbers or strings.
+		cleared, err := qs.writeCatchpointStateUint64(ctx, catchpointStateCatchupBlockRound, 123)
+		require.NoError(t, err)
+		require.False(t, cleared)
+		cleared, err = qs.writeCatchpointStateString(ctx, catchpointStateCatchupLabel, "123#label")
+		require.NoError(t, err)
+		require.False(t, cleared)
+
+		n, def, err := qs.readCatchpointStateUint64(ctx, catchpointStateCatchupBlockRound)
+		require.NoError(t, err)
+		require.False(t, def)
+		require.Equal(t, uint64(123), n)
+		str, def, err := qs.readCatchpointStateString(ctx, catchpointStateCatchupLabel)
+		require.NoError(t, err)
+		require.False(t, def)
+		require.Equal(t, "123#label", str)
+		n, def, err = qs.readCatchpointStateUint64(ctx, catchpointStateCatchupState)
+		require.NoError(t, err)
+		require.True(t, def)
+		require.Equal(t, uint64(0), n)
+
+		cleared, err = qs.writeCatchpointStateUint64(ctx, catchpointStateCatchupBlockRound, 0)
+		require.NoError(t, err)
+		require.True(t, cleared)
+		cleared, err = qs.writeCatchpointStateString(ctx, catchpointStateCatchupLabel, "")
+		require.NoError(t, err)
+		require.True(t, cleared)
+		_, def, err = qs.readCatchpointStateUint64(ctx, catchpointStateCatchupBlockRound)
+		require.NoError(t, err)
+		require.True(t, def)
+		_, def, err = qs.readCatchpointStateString(ctx, catchpointStateCatchupLabel)
+		require.NoError(t, err)
+		require.True(t, def)
+
+		// the queries of a transaction are rolled back along with it.
+		err = ts.write(func(tx trackerTx) error {
+			txqs, err := tx.queries()
+			if err != nil {
+				return err
+			}
+			_, err = txqs.writeCatchpointStateUint64(ctx, catchpointStateCatchupVersion, 2)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("rolled back")
+		})
+		require.Error(t, err)
+		_, def, err = qs.readCatchpointStateUint64(ctx, catchpointStateCatchupVersion)
+		require.NoError(t, err)
+		require.True(t, def)
+	})
+}
+
+// stageTrackerAccounts stages the accounts the way a catchpoint catchup does.
+func stageTrackerAccounts(ts trackerStore, accts map[basics.Address]basics.AccountData) error {
+	ctx := context.Background()
+	bals, resources := trackerTestRecords(accts)
+	return ts.write(func(tx trackerTx) error {
+		err := tx.resetCatchpointStagingBalances(ctx, true)
+		if err != nil {
+			return err
+		}
+		err = tx.writeCatchpointStagingBalances(ctx, bals)
+		if err != nil {
+			return err
+		}
+		err = tx.writeCatchpointStagingResources(ctx, resources)
+		if err != nil {
+			return err
+		}
+		for addr, data := range accts {
+			for aidx := range data.AssetParams {
+				err = tx.writeCatchpointStagingCreatable(ctx, addr, basics.CreatableIndex(aidx), basics.AssetCreatable)
+				if err != nil {
+					return err
+				}
+			}
+			for aidx := range data.AppParams {
+				err = tx.writeCatchpointStagingCreatable(ctx, addr, basics.CreatableIndex(aidx), basics.AppCreatable)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err = tx.accountsPutTotals(trackerTestTotals(accts), true)
+		if err != nil {
+			return err
+		}
+		mc, err := tx.merkleCommitter(true)
+		if err != nil {
+			return err
+		}
+		return mc.StorePage(0, []byte{byte(len(accts))})
+	})
+}
+
+func TestTrackerStoreCatchpointStaging(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	testTrackerStore(t, func(t *testing.T, backend string, dbPrefix string, ts trackerStore) {
+		ctx := context.Background()
+		accts := trackerTestAccounts(10)
+		err := ts.write(func(tx trackerTx) error {
+			err := tx.accountsInit(accts, proto)
+			if err != nil {
+				return err
+			}
+			return tx.accountsInitHistory(0)
+		})
+		require.NoError(t, err)
+
+		// the staged accounts replace the ones of the ledger a couple of
+		// times over.
+		for i, balancesRound := range []basics.Round{100, 200, 300} {
+			staged := trackerTestAccounts(10 + i)
+			require.NoError(t, stageTrackerAccounts(ts, staged))
+			checkTrackerAccounts(t, ts, basics.Round(i*100), accts)
+
+			var stagedBals []encodedBalanceRecord
+			var stagedTotals AccountTotals
+			err = ts.read(func(tx trackerTx) (err error) {
+				stagedBals, err = tx.encodedFullAccountsRange(true, 0, 100)
+				if err != nil {
+					return
+				}
+				stagedTotals, err = tx.accountsTotals(true)
+				return
+			})
+			require.NoError(t, err)
+			require.Equal(t, len(staged), len(stagedBals))
+			for _, balance := range stagedBals {
+				var data basics.AccountData
+				require.NoError(t, protocol.Decode(balance.AccountData, &data))
+				require.Equal(t, staged[balance.Address], data)
+			}
+			require.Equal(t, trackerTestTotals(staged), stagedTotals)
+
+			// a staged account can't be written twice.
+			bals, _ := trackerTestRecords(staged)
+			err = ts.write(func(tx trackerTx) error {
+				return tx.writeCatchpointStagingBalances(ctx, bals[:1])
+			})
+			require.Error(t, err)
+
+			var page []byte
+			var historyErr error
+			err = ts.write(func(tx trackerTx) error {
+				err := tx.applyCatchpointStagingBalances(ctx, balancesRound)
+				if err != nil {
+					return err
+				}
+				err = tx.accountsPutTotals(stagedTotals, false)
+				if err != nil {
+					return err
+				}
+				err = tx.resetCatchpointStagingBalances(ctx, false)
+				if err != nil {
+					return err
+				}
+				mc, err := tx.merkleCommitter(false)
+				if err != nil {
+					return err
+				}
+				page, err = mc.LoadPage(0)
+				if err != nil {
+					return err
+				}
+				_, historyErr = tx.accountsHistoryRound()
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, []byte{byte(len(staged))}, page)
+			require.Equal(t, sql.ErrNoRows, historyErr)
+
+			accts = staged
+			stored := readStoredAccounts(t, ts, nil)
+			require.Equal(t, balancesRound, stored.hashRound)
+			checkTrackerAccounts(t, ts, balancesRound, accts)
+		}
+	})
+}
+
+func TestTrackerStoreHistory(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	testTrackerStore(t, func(t *testing.T, backend string, dbPrefix string, ts trackerStore) {
+		accts := []map[basics.Address]basics.AccountData{trackerTestAccounts(20)}
+		totals := []AccountTotals{trackerTestTotals(accts[0])}
+		err := ts.write(func(tx trackerTx) error {
+			err := tx.accountsInit(accts[0], proto)
+			if err != nil {
+				return err
+			}
+			return tx.accountsInitHistory(0)
+		})
+		require.NoError(t, err)
+
+		var modified []map[basics.Address]basics.AccountData
+		for rnd := basics.Round(1); rnd <= 5; rnd++ {
+			updates, newAccts, _ := randomDeltas(5, accts[rnd-1], 0)
+			// an account gets closed.
+			for addr, data := range accts[rnd-1] {
+				if _, ok := updates[addr]; !ok {
+					updates[addr] = accountDelta{old: data}
+					delete(newAccts, addr)
+					break
+				}
+			}
+			roundModified := make(map[basics.Address]basics.AccountData)
+			for addr, delta := range updates {
+				roundModified[addr] = delta.new
+			}
+			accts = append(accts, newAccts)
+			totals = append(totals, trackerTestTotals(newAccts))
+			modified = append(modified, roundModified)
+
+			err = ts.write(func(tx trackerTx) error {
+				err := tx.accountsNewRound(updates, 0, proto)
+				if err != nil {
+					return err
+				}
+				err = tx.accountsHistoryNewRound(rnd, updates, totals[rnd])
+				if err != nil {
+					return err
+				}
+				return tx.updateAccountsRound(rnd, 0)
+			})
+			require.NoError(t, err)
+		}
+
+		hq, err := ts.historyQueries()
+		require.NoError(t, err)
+		for rnd := basics.Round(0); rnd <= 5; rnd++ {
+			for addr, data := range accts[5] {
+				stored, err := hq.lookup(addr, rnd)
+				require.NoError(t, err)
+				require.Equal(t, accts[rnd][addr], stored, "account %v of round %d", addr, rnd)
+				if rnd == 5 {
+					require.Equal(t, data, stored)
+				}
+			}
+			stored, err := hq.totals(rnd)
+			require.NoError(t, err)
+			require.Equal(t, totals[rnd], stored)
+			if rnd > 0 {
+				stored, err := hq.modified(rnd)
+				require.NoError(t, err)
+				require.Equal(t, modified[rnd-1], stored)
+			}
+		}
+		_, err = hq.totals(6)
+		require.Equal(t, sql.ErrNoRows, err)
+
+		// the snapshots visit the accounts in the order of their addresses.
+		var historyRound basics.Round
+		var historyTotals AccountTotals
+		var current []basics.Address
+		currentAccts := make(map[basics.Address]basics.AccountData)
+		historyAccts := make([]map[basics.Address]basics.AccountData, len(accts))
+		err = ts.read(func(tx trackerTx) (err error) {
+			historyRound, err = tx.accountsHistoryRound()
+			if err != nil {
+				return
+			}
+			historyTotals, err = tx.accountsHistoryTotals(3)
+			if err != nil {
+				return
+			}
+			err = tx.snapshotAccountsCurrent(func(addr basics.Address, data basics.AccountData) error {
+				current = append(current, addr)
+				currentAccts[addr] = data
+				return nil
+			})
+			if err != nil {
+				return
+			}
+			for rnd := range accts {
+				historyAccts[rnd] = make(map[basics.Address]basics.AccountData)
+				err = tx.snapshotAccountsHistory(basics.Round(rnd), func(addr basics.Address, data basics.AccountData) error {
+					historyAccts[rnd][addr] = data
+					return nil
+				})
+				if err != nil {
+					return
+				}
+			}
+			return
+		})
+		require.NoError(t, err)
+		require.Equal(t, basics.Round(0), historyRound)
+		require.Equal(t, totals[3], historyTotals)
+		require.Equal(t, accts[5], currentAccts)
+		for i := 1; i < len(current); i++ {
+			require.Equal(t, -1, bytes.Compare(current[i-1][:], current[i][:]))
+		}
+		for rnd := range accts {
+			require.Equal(t, accts[rnd], historyAccts[rnd], "round %d", rnd)
+		}
+
+		var historyErr error
+		err = ts.write(func(tx trackerTx) error {
+			err := tx.accountsResetHistory()
+			if err != nil {
+				return err
+			}
+			_, historyErr = tx.accountsHistoryRound()
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, sql.ErrNoRows, historyErr)
+	})
+}
+
+func TestTrackerStoreReadOnly(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	testTrackerStore(t, func(t *testing.T, backend string, dbPrefix string, ts trackerStore) {
+		accts := trackerTestAccounts(5)
+		err := ts.write(func(tx trackerTx) error {
+			return tx.accountsInit(accts, proto)
+		})
+		require.NoError(t, err)
+		ts.close()
+
+		ts, err = openTrackerStore(backend, dbPrefix, true, false)
+		require.NoError(t, err)
+		var all map[basics.Address]basics.AccountData
+		err = ts.read(func(tx trackerTx) (err error) {
+			all, err = tx.accountsAll()
+			return
+		})
+		require.NoError(t, err)
+		require.Equal(t, accts, all)
+
+		err = ts.write(func(tx trackerTx) error {
+			return tx.resetAccountHashes()
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestTrackerStoreBackendMismatch(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	testTrackerStore(t, func(t *testing.T, backend string, dbPrefix string, ts trackerStore) {
+		err := ts.write(func(tx trackerTx) error {
+			return tx.accountsInit(nil, proto)
+		})
+		require.NoError(t, err)
+
+		for _, other := range trackerStoreBackends {
+			if other != backend {
+				_, err := openTrackerStore(other, dbPrefix, false, false)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), backend)
+			}
+		}
+		_, err = openTrackerStore("lsm", dbPrefix, false, false)
+		require.Error(t, err)
+
+		existing, err := existingTrackerStoreBackend(dbPrefix)
+		require.NoError(t, err)
+		require.Equal(t, backend, existing)
+	})
+}
+
+func TestLedgerTrackerStorageBackend(t *testing.T) {
+	for _, backend := range trackerStoreBackends {
+		t.Run(backend, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "trackerstore")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			dbPrefix := filepath.Join(dir, "ledger")
+
+			genesisInitState := getInitState()
+			cfg := config.GetDefaultLocal()
+			cfg.Archival = true
+			cfg.EnableAccountHistory = true
+			cfg.TrackerStorageBackend = backend
+			l, err := OpenLedger(logging.TestingLog(t), dbPrefix, false, genesisInitState, cfg)
+			require.NoError(t, err)
+
+			blk := genesisInitState.Block
+			for i := 0; i < 10; i++ {
+				blk.BlockHeader.Round++
+				require.NoError(t, l.AddBlock(blk, agreement.Certificate{}))
+			}
+			l.WaitForCommit(blk.Round())
+			l.Close()
+
+			l, err = OpenLedger(logging.TestingLog(t), dbPrefix, false, genesisInitState, cfg)
+			require.NoError(t, err)
+			defer l.Close()
+			require.Equal(t, blk.Round(), l.Latest())
+			for addr, data := range genesisInitState.Accounts {
+				stored, err := l.LookupWithoutRewards(blk.Round(), addr)
+				require.NoError(t, err)
+				require.Equal(t, data.MicroAlgos, stored.MicroAlgos)
+			}
+
+			path, err := trackerStorePath(backend, dbPrefix, false)
+			require.NoError(t, err)
+			_, err = os.Stat(path)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,632 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/execpool"
+	"github.com/algorand/go-algorand/util/kvstore"
+)
+
+// verifyMaxAccountMismatches is the number of differing accounts that are
+// described individually in a VerifyReport.
+const verifyMaxAccountMismatches = 10
+
+// verifyProgressInterval is the number of replayed rounds between two
+// progress messages.
+const verifyProgressInterval = 10000
+
+// verifyCatchpointLabelTimeout is how long the replay waits for the label of
+// a catchpoint it compares with the tracker database.
+var verifyCatchpointLabelTimeout = time.Minute
+
+// VerifyReport is the outcome of VerifyLedger.
+type VerifyReport struct {
+	// EarliestBlock and LatestBlock are the rounds of the oldest and the
+	// latest blocks of the blocks database.
+	EarliestBlock basics.Round
+	LatestBlock   basics.Round
+
+	// TrackerRound is the round of the accounts of the tracker database,
+	// and Accounts is their number.
+	TrackerRound basics.Round
+	Accounts     uint64
+
+	// Replayed is the latest block validated by replaying the blocks from
+	// the genesis. It is zero if the blocks database doesn't start at the
+	// genesis block, in which case the blocks are not replayed.
+	Replayed basics.Round
+
+	// CatchpointLabels is the number of catchpoint labels of the tracker
+	// database that were recomputed.
+	CatchpointLabels int
+
+	// Mismatches describes the problems found in the ledger.
+	Mismatches []string
+
+	// Repaired is set when the tracker database was fixed.
+	Repaired bool
+}
+
+// ledgerVerifier holds the state of a VerifyLedger run.
+type ledgerVerifier struct {
+	log    logging.Logger
+	report VerifyReport
+
+	dbPathPrefix      string
+	trackerBackend    string
+	trackerDBFilename string
+	blockDBFilename   string
+	blockDBs          blockStore
+	trackerDBs        trackerStore
+
+	// trackerOK is set once the tracker database was read.
+	trackerOK bool
+	// storedTotals are the totals of the tracker database, and totals and
+	// balancesRoot are the ones computed from its accounts.
+	storedTotals AccountTotals
+	totals       AccountTotals
+	balancesRoot crypto.Digest
+	// catchpoints are the catchpoint labels of the tracker database.
+	catchpoints map[basics.Round]string
+	// catchpointInterval is the interval of the catchpoints computed by the
+	// replay.
+	catchpointInterval uint64
+
+	// totalsMismatch and hashesMismatch are set when the stored totals and
+	// merkle trie don't match the accounts, which can be fixed in place.
+	// rebuild is set by the other problems, which are only fixed by
+	// rebuilding the tracker database from the blocks.
+	totalsMismatch bool
+	hashesMismatch bool
+	rebuild        bool
+}
+
+// VerifyLedger checks the ledger stored at dbPathPrefix, which must not be
+// in use. It opens the blocks and the tracker databases read-only, and
+// recomputes the account totals, the merkle trie root of the accounts and the
+// latest catchpoint label from the accounts of the tracker database. If the
+// blocks database starts at the genesis block, it also validates all the
+// blocks by replaying them on a scratch ledger, and compares the replayed
+// accounts, totals and catchpoint labels with the tracker database.
+//
+// If repair is set and problems were found, the tracker database is fixed:
+// when all the blocks could be replayed, it is replaced with the one of the
+// scratch ledger, and the original one is kept with a .bak suffix. Otherwise,
+// only the totals and the merkle trie can be fixed, by deriving them from the
+// stored accounts.
+func VerifyLedger(log logging.Logger, dbPathPrefix string, genesisInitState InitState, cfg config.Local, repair bool) (report VerifyReport, err error) {
+	v := ledgerVerifier{
+		log:                log,
+		dbPathPrefix:       dbPathPrefix,
+		trackerBackend:     cfg.TrackerStorageBackend,
+		catchpoints:        make(map[basics.Round]string),
+		catchpointInterval: cfg.CatchpointInterval,
+	}
+	defer v.close()
+
+	v.trackerDBFilename, err = trackerStorePath(cfg.TrackerStorageBackend, dbPathPrefix, false)
+	if err != nil {
+		return
+	}
+	v.blockDBFilename, err = blockStorePath(cfg.BlockStorageBackend, dbPathPrefix, false)
+	if err != nil {
+		return
+	}
+	_, err = os.Stat(v.blockDBFilename)
+	if err != nil {
+		return
+	}
+	v.blockDBs, err = openBlockStore(cfg.BlockStorageBackend, dbPathPrefix, true, false)
+	if err == kvstore.ErrLocked {
+		err = fmt.Errorf("VerifyLedger: the blocks database is in use, the node must be stopped: %v", err)
+	}
+	if err != nil {
+		return
+	}
+	v.blockDBs.setLogger(log)
+
+	err = v.blockDBs.read(func(tx blockTx) error {
+		var err0 error
+		v.report.EarliestBlock, err0 = tx.earliest()
+		if err0 != nil {
+			return err0
+		}
+		v.report.LatestBlock, err0 = tx.latest()
+		return err0
+	})
+	if err != nil {
+		err = fmt.Errorf("VerifyLedger: unable to read the blocks database: %v", err)
+		return
+	}
+
+	err = v.checkTracker()
+	if err != nil {
+		return
+	}
+
+	var freshPrefix string
+	if v.report.EarliestBlock == 0 {
+		var fresh *Ledger
+		fresh, freshPrefix, err = v.replay(dbPathPrefix, genesisInitState)
+		if fresh != nil {
+			// only the databases of the scratch ledger are used from now on.
+			fresh.Close()
+			defer os.RemoveAll(filepath.Dir(freshPrefix))
+		}
+		if err != nil {
+			return v.report, err
+		}
+	}
+
+	if !repair || len(v.report.Mismatches) == 0 {
+		return v.report, nil
+	}
+	if v.rebuild {
+		err = v.rebuildTracker(freshPrefix)
+	} else {
+		err = v.fixTracker()
+	}
+	return v.report, err
+}
+
+func (v *ledgerVerifier) close() {
+	if v.trackerDBs != nil {
+		v.trackerDBs.close()
+		v.trackerDBs = nil
+	}
+	if v.blockDBs != nil {
+		v.blockDBs.close()
+		v.blockDBs = nil
+	}
+}
+
+// mismatchf records a problem of the ledger; rebuild tells whether fixing it
+// requires rebuilding the tracker database.
+func (v *ledgerVerifier) mismatchf(rebuild bool, format string, args ...interface{}) {
+	v.report.Mismatches = append(v.report.Mismatches, fmt.Sprintf(format, args...))
+	v.rebuild = v.rebuild || rebuild
+}
+
+func (v *ledgerVerifier) blockHdr(rnd basics.Round) (hdr bookkeeping.BlockHeader, err error) {
+	err = v.blockDBs.read(func(tx blockTx) error {
+		var err0 error
+		hdr, err0 = tx.getHdr(rnd)
+		return err0
+	})
+	return
+}
+
+// checkTracker verifies that the totals, the merkle trie and the latest
+// catchpoint label of the tracker database match its accounts. It only
+// fails if the tracker database is in use.
+func (v *ledgerVerifier) checkTracker() error {
+	_, err := os.Stat(v.trackerDBFilename)
+	if err == nil {
+		v.trackerDBs, err = openTrackerStore(v.trackerBackend, v.dbPathPrefix, true, false)
+		if err == kvstore.ErrLocked {
+			return fmt.Errorf("VerifyLedger: the tracker database is in use, the node must be stopped: %v", err)
+		}
+	}
+	if err == nil {
+		v.trackerDBs.setLogger(v.log)
+		err = v.trackerDBs.read(v.checkTrackerTx)
+	}
+	if err != nil {
+		v.mismatchf(true, "tracker database: %v", err)
+		return nil
+	}
+	v.trackerOK = true
+	return nil
+}
+
+func (v *ledgerVerifier) checkTrackerTx(tx trackerTx) (err error) {
+	rnd, hashRound, err := tx.accountsRound()
+	if err != nil {
+		return err
+	}
+	v.report.TrackerRound = rnd
+	if rnd > v.report.LatestBlock {
+		return fmt.Errorf("round %d is ahead of the latest block %d", rnd, v.report.LatestBlock)
+	}
+	hdr, err := v.blockHdr(rnd)
+	if err != nil {
+		return fmt.Errorf("unable to read the block of round %d: %v", rnd, err)
+	}
+	proto := config.Consensus[hdr.CurrentProtocol]
+
+	v.storedTotals, err = tx.accountsTotals(false)
+	if err != nil {
+		return err
+	}
+
+	v.totals = AccountTotals{RewardsLevel: hdr.RewardsLevel}
+	var ot basics.OverflowTracker
+	trie, err := merkletrie.MakeTrie(&merkletrie.InMemoryCommitter{}, trieCachedNodesCount)
+	if err != nil {
+		return err
+	}
+	v.report.Accounts = 0
+	for accountIdx := 0; ; accountIdx += trieRebuildAccountChunkSize {
+		bals, err := tx.encodedAccountsRange(false, accountIdx, trieRebuildAccountChunkSize)
+		if err != nil {
+			return err
+		}
+		for _, balance := range bals {
+			var accountData basics.AccountData
+			err = protocol.Decode(balance.AccountData, &accountData)
+			if err != nil {
+				return err
+			}
+			v.totals.addAccount(proto, accountData, &ot)
+			_, err = trie.Add(accountHashBuilder(balance.Address, accountData, balance.AccountData))
+			if err != nil {
+				return err
+			}
+		}
+		v.report.Accounts += uint64(len(bals))
+		if len(bals) < trieRebuildAccountChunkSize {
+			break
+		}
+	}
+	for resourceIdx := 0; ; resourceIdx += trieRebuildAccountChunkSize {
+		resources, err := tx.encodedResourcesRange(false, resourceIdx, trieRebuildAccountChunkSize)
+		if err != nil {
+			return err
+		}
+		for _, resource := range resources {
+			_, err = trie.Add(resourceHashBuilder(resource.Address, resource.CreatableIndex, resource.CreatableType, resource.Data))
+			if err != nil {
+				return err
+			}
+		}
+		if len(resources) < trieRebuildAccountChunkSize {
+			break
+		}
+	}
+	if ot.Overflowed {
+		return fmt.Errorf("overflow computing the totals of round %d", rnd)
+	}
+	v.balancesRoot, err = trie.RootHash()
+	if err != nil {
+		return err
+	}
+
+	if v.totals != v.storedTotals {
+		v.totalsMismatch = true
+		v.mismatchf(false, "account totals of round %d: stored %+v, computed %+v", rnd, v.storedTotals, v.totals)
+	}
+
+	if hashRound == rnd {
+		committer, err := tx.merkleCommitter(false)
+		if err != nil {
+			return err
+		}
+		storedTrie, err := merkletrie.MakeTrie(committer, trieCachedNodesCount)
+		if err != nil {
+			return err
+		}
+		storedRoot, err := storedTrie.RootHash()
+		if err != nil {
+			return err
+		}
+		// the trie is only maintained by the nodes that compute catchpoints.
+		if !storedRoot.IsZero() && storedRoot != v.balancesRoot {
+			v.hashesMismatch = true
+			v.mismatchf(false, "accounts merkle trie root of round %d: stored %v, computed %v", rnd, storedRoot, v.balancesRoot)
+		}
+	}
+
+	err = v.readCatchpoints(tx)
+	if err != nil {
+		return err
+	}
+
+	// the label of a catchpoint is made when the accounts of the round
+	// MaxBalLookback rounds before it are committed.
+	cpRound := rnd + basics.Round(proto.MaxBalLookback)
+	if _, ok := v.catchpoints[cpRound]; ok && cpRound <= v.report.LatestBlock {
+		cpHdr, err := v.blockHdr(cpRound)
+		if err != nil {
+			return fmt.Errorf("unable to read the block of round %d: %v", cpRound, err)
+		}
+		label := makeCatchpointLabel(cpRound, crypto.Digest(cpHdr.Hash()), v.balancesRoot, v.totals)
+		hash := label.Hash()
+		v.checkCatchpointLabel(cpRound, fmt.Sprintf("%d#%s", cpRound, base32Encoder.EncodeToString(hash[:])))
+	}
+	return nil
+}
+
+// readCatchpoints reads the labels of the catchpoints the tracker database
+// holds files for, and the one of the latest catchpoint.
+func (v *ledgerVerifier) readCatchpoints(tx trackerTx) error {
+	labels, err := tx.storedCatchpointLabels()
+	if err != nil {
+		return err
+	}
+	for rnd, label := range labels {
+		v.catchpoints[rnd] = label
+	}
+
+	qs, err := tx.queries()
+	if err != nil {
+		return err
+	}
+	label, _, err := qs.readCatchpointStateString(context.Background(), catchpointStateLastCatchpoint)
+	if err != nil || label == "" {
+		return err
+	}
+	rnd, _, err := ParseCatchpointLabel(label)
+	if err != nil {
+		v.mismatchf(false, "latest catchpoint label %q: %v", label, err)
+		return nil
+	}
+	v.catchpoints[rnd] = label
+	return nil
+}
+
+// checkCatchpointLabel compares a computed catchpoint label with the one the
+// tracker database has for the same round, if any.
+func (v *ledgerVerifier) checkCatchpointLabel(rnd basics.Round, label string) {
+	stored, ok := v.catchpoints[rnd]
+	if !ok {
+		return
+	}
+	v.report.CatchpointLabels++
+	if stored != label {
+		v.mismatchf(true, "catchpoint label of round %d: stored %s, computed %s", rnd, stored, label)
+	}
+}
+
+// replay validates the blocks of the blocks database on a scratch ledger,
+// starting from the genesis. It returns the scratch ledger, or nil if the
+// blocks couldn't be replayed, and the path prefix of its databases.
+func (v *ledgerVerifier) replay(dbPathPrefix string, genesisInitState InitState) (fresh *Ledger, freshPrefix string, err error) {
+	genesisHdr, err := v.blockHdr(0)
+	if err != nil {
+		return nil, "", err
+	}
+	if genesisHdr.Hash() != genesisInitState.Block.Hash() {
+		v.mismatchf(true, "the genesis block %v doesn't match the genesis %v", genesisHdr.Hash(), genesisInitState.Block.Hash())
+		return nil, "", nil
+	}
+
+	tmpDir, err := ioutil.TempDir(filepath.Dir(dbPathPrefix), "verify")
+	if err != nil {
+		return nil, "", err
+	}
+	freshPrefix = filepath.Join(tmpDir, filepath.Base(dbPathPrefix))
+
+	// the scratch ledger doesn't keep the blocks its trackers are done with.
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = false
+	cfg.CatchpointInterval = v.catchpointInterval
+	cfg.TrackerStorageBackend = v.trackerBackend
+	fresh, err = OpenLedger(v.log, freshPrefix, false, genesisInitState, cfg)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, "", err
+	}
+
+	backlogPool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
+	defer backlogPool.Shutdown()
+
+	if v.trackerOK && v.report.TrackerRound == 0 {
+		v.compareAccounts(fresh)
+	}
+	for rnd := basics.Round(1); rnd <= v.report.LatestBlock; rnd++ {
+		var blk bookkeeping.Block
+		var cert agreement.Certificate
+		err = v.blockDBs.read(func(tx blockTx) error {
+			var err0 error
+			blk, cert, err0 = tx.getCert(rnd)
+			return err0
+		})
+		if err != nil {
+			v.mismatchf(true, "block %d: %v", rnd, err)
+			return fresh, freshPrefix, nil
+		}
+
+		vb, err := fresh.Validate(context.Background(), blk, nil, backlogPool)
+		if err != nil {
+			v.mismatchf(true, "block %d: %v", rnd, err)
+			return fresh, freshPrefix, nil
+		}
+		err = fresh.AddValidatedBlock(*vb, cert)
+		if err != nil {
+			return fresh, freshPrefix, err
+		}
+		v.report.Replayed = rnd
+
+		if v.trackerOK && rnd == v.report.TrackerRound {
+			v.compareAccounts(fresh)
+		}
+		if _, ok := v.catchpoints[rnd]; ok && v.catchpointInterval != 0 && uint64(rnd)%v.catchpointInterval == 0 {
+			v.waitCatchpointLabel(fresh, rnd)
+		}
+		if rnd%verifyProgressInterval == 0 {
+			v.log.Infof("VerifyLedger: replayed %d of %d blocks", rnd, v.report.LatestBlock)
+		}
+	}
+	fresh.WaitForCommit(v.report.LatestBlock)
+	return fresh, freshPrefix, nil
+}
+
+// compareAccounts compares the accounts of the tracker database and their
+// totals with the ones of the scratch ledger, which is at the tracker round.
+// The accounts that are missing from the tracker database show in the totals.
+func (v *ledgerVerifier) compareAccounts(fresh *Ledger) {
+	rnd := v.report.TrackerRound
+	var bals map[basics.Address]basics.AccountData
+	err := v.trackerDBs.read(func(tx trackerTx) error {
+		var err0 error
+		bals, err0 = tx.accountsAll()
+		return err0
+	})
+	if err != nil {
+		v.mismatchf(true, "tracker database: %v", err)
+		return
+	}
+
+	addrs := make([]basics.Address, 0, len(bals))
+	for addr := range bals {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	differing := 0
+	for _, addr := range addrs {
+		data := bals[addr]
+		replayed, err := fresh.LookupWithoutRewards(rnd, addr)
+		if err != nil {
+			v.mismatchf(true, "account %v: %v", addr, err)
+			return
+		}
+		if bytes.Equal(protocol.Encode(&data), protocol.Encode(&replayed)) {
+			continue
+		}
+		differing++
+		if differing <= verifyMaxAccountMismatches {
+			v.mismatchf(true, "account %v of round %d: stored %+v, replayed %+v", addr, rnd, data, replayed)
+		}
+	}
+	if differing > verifyMaxAccountMismatches {
+		v.mismatchf(true, "%d more accounts of round %d differ", differing-verifyMaxAccountMismatches, rnd)
+	}
+
+	totals, err := fresh.Totals(rnd)
+	if err != nil {
+		v.mismatchf(true, "account totals of round %d: %v", rnd, err)
+		return
+	}
+	// the stored totals are checked against the accounts by checkTracker.
+	if totals != v.totals {
+		v.mismatchf(true, "account totals of round %d: computed %+v, replayed %+v", rnd, v.totals, totals)
+	}
+}
+
+// waitCatchpointLabel waits for the scratch ledger to make the label of the
+// catchpoint of round rnd, and compares it with the tracker database one.
+func (v *ledgerVerifier) waitCatchpointLabel(fresh *Ledger, rnd basics.Round) {
+	deadline := time.Now().Add(verifyCatchpointLabelTimeout)
+	for {
+		label := fresh.GetLastCatchpointLabel()
+		labelRound, _, err := ParseCatchpointLabel(label)
+		if err == nil && labelRound >= rnd {
+			if labelRound == rnd {
+				v.checkCatchpointLabel(rnd, label)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			v.log.Warnf("VerifyLedger: the catchpoint label of round %d wasn't made by the replay", rnd)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// rebuildTracker replaces the tracker database with the one of the closed
+// scratch ledger stored at freshPrefix, once it replayed all the blocks.
+func (v *ledgerVerifier) rebuildTracker(freshPrefix string) error {
+	if freshPrefix == "" {
+		return fmt.Errorf("VerifyLedger: unable to rebuild the tracker database: the blocks database doesn't start at the genesis block")
+	}
+	if v.report.Replayed != v.report.LatestBlock {
+		return fmt.Errorf("VerifyLedger: unable to rebuild the tracker database: only the blocks up to round %d could be replayed", v.report.Replayed)
+	}
+	if v.trackerDBFilename == v.blockDBFilename {
+		return fmt.Errorf("VerifyLedger: unable to rebuild the tracker database, which is stored along with the blocks in %s", v.trackerDBFilename)
+	}
+	freshTrackerDBFilename, err := trackerStorePath(v.trackerBackend, freshPrefix, false)
+	if err != nil {
+		return err
+	}
+	v.close()
+
+	// the kv database is a directory; the SQLite one comes with its
+	// write-ahead log files.
+	suffixes := []string{"", "-wal", "-shm"}
+	if v.trackerBackend == trackerStoreKV {
+		suffixes = []string{""}
+	}
+	// the catchpoint files of the original database are no longer listed
+	// in the rebuilt one.
+	for _, suffix := range suffixes {
+		err = os.Rename(v.trackerDBFilename+suffix, v.trackerDBFilename+".bak"+suffix)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	for _, suffix := range suffixes {
+		err = os.Rename(freshTrackerDBFilename+suffix, v.trackerDBFilename+suffix)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	v.report.Repaired = true
+	return nil
+}
+
+// fixTracker fixes the totals and the merkle trie of the tracker database,
+// when the blocks to rebuild it aren't available. They are derived from the
+// stored accounts, which can't be verified.
+func (v *ledgerVerifier) fixTracker() error {
+	v.close()
+
+	trackerDBs, err := openTrackerStore(v.trackerBackend, v.dbPathPrefix, false, false)
+	if err != nil {
+		return err
+	}
+	defer trackerDBs.close()
+	trackerDBs.setLogger(v.log)
+
+	err = trackerDBs.write(func(tx trackerTx) error {
+		if v.totalsMismatch {
+			err0 := tx.accountsPutTotals(v.totals, false)
+			if err0 != nil {
+				return err0
+			}
+		}
+		if v.hashesMismatch {
+			// the trie is rebuilt when the ledger is opened.
+			return tx.resetAccountHashes()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	v.report.Repaired = true
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// makeVerifiedLedger writes an archival ledger holding a payment in each of
+// its blocks, and a catchpoint at round 400, to a temporary directory.
+func makeVerifiedLedger(t *testing.T) (dbPrefix string, genesisInitState InitState, cfg config.Local, release func()) {
+	dbTempDir, err := ioutil.TempDir("", "testdir"+t.Name())
+	require.NoError(t, err)
+	dbPrefix = filepath.Join(dbTempDir, "ledger")
+
+	genesisInitState, initSecrets := testGenerateInitState(t, protocol.ConsensusCurrentVersion)
+	cfg = config.GetDefaultLocal()
+	cfg.Archival = true
+	cfg.CatchpointInterval = 100
+	l, err := OpenLedger(logging.TestingLog(t), dbPrefix, false, genesisInitState, cfg)
+	require.NoError(t, err)
+
+	var addrs []basics.Address
+	for addr := range genesisInitState.Accounts {
+		if addr != testPoolAddr && addr != testSinkAddr {
+			addrs = append(addrs, addr)
+		}
+	}
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	for i := 0; i < 420; i++ {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addrs[i%len(addrs)],
+				Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid:  l.Latest() + 1,
+				LastValid:   l.Latest() + 10,
+				GenesisID:   t.Name(),
+				GenesisHash: genesisInitState.GenesisHash,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addrs[(i+1)%len(addrs)],
+				Amount:   basics.MicroAlgos{Raw: uint64(1000 + i)},
+			},
+		}
+		prev, err := l.BlockHdr(l.Latest())
+		require.NoError(t, err)
+		eval, err := l.StartEvaluator(bookkeeping.MakeBlock(prev).BlockHeader, 0)
+		require.NoError(t, err)
+		require.NoError(t, eval.Transaction(sign(initSecrets, tx), transactions.ApplyData{}))
+		vb, err := eval.GenerateBlock()
+		require.NoError(t, err)
+		require.NoError(t, l.AddValidatedBlock(*vb, agreement.Certificate{}))
+	}
+	l.WaitForCommit(l.Latest())
+	for i := 0; i < 500 && !strings.HasPrefix(l.GetLastCatchpointLabel(), "400#"); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.True(t, strings.HasPrefix(l.GetLastCatchpointLabel(), "400#"))
+	l.Close()
+
+	return dbPrefix, genesisInitState, cfg, func() {
+		os.RemoveAll(dbTempDir)
+	}
+}
+
+// updateTrackerDB runs a statement on the tracker database of the ledger.
+func updateTrackerDB(t *testing.T, dbPrefix string, query string, args ...interface{}) {
+	trackerDBs, err := db.MakeAccessor(dbPrefix+".tracker.sqlite", false, false)
+	require.NoError(t, err)
+	defer trackerDBs.Close()
+	err = trackerDBs.Atomic(func(tx *sql.Tx) error {
+		_, err0 := tx.Exec(query, args...)
+		return err0
+	})
+	require.NoError(t, err)
+}
+
+func TestVerifyLedger(t *testing.T) {
+	dbPrefix, genesisInitState, cfg, release := makeVerifiedLedger(t)
+	defer release()
+	log := logging.TestingLog(t)
+
+	report, err := VerifyLedger(log, dbPrefix, genesisInitState, cfg, false)
+	require.NoError(t, err)
+	require.Empty(t, report.Mismatches)
+	require.Equal(t, basics.Round(0), report.EarliestBlock)
+	require.Equal(t, basics.Round(420), report.LatestBlock)
+	require.Equal(t, basics.Round(420), report.Replayed)
+	require.NotZero(t, report.TrackerRound)
+	require.Equal(t, uint64(len(genesisInitState.Accounts)), report.Accounts)
+	require.NotZero(t, report.CatchpointLabels)
+	require.False(t, report.Repaired)
+
+	// totals that don't match the accounts are fixed in place.
+	updateTrackerDB(t, dbPrefix, "UPDATE accounttotals SET online=online+1 WHERE id=''")
+	report, err = VerifyLedger(log, dbPrefix, genesisInitState, cfg, false)
+	require.NoError(t, err)
+	require.Len(t, report.Mismatches, 1)
+	require.Contains(t, report.Mismatches[0], "account totals")
+
+	report, err = VerifyLedger(log, dbPrefix, genesisInitState, cfg, true)
+	require.NoError(t, err)
+	require.True(t, report.Repaired)
+	_, err = os.Stat(dbPrefix + ".tracker.sqlite.bak")
+	require.True(t, os.IsNotExist(err))
+
+	report, err = VerifyLedger(log, dbPrefix, genesisInitState, cfg, false)
+	require.NoError(t, err)
+	require.Empty(t, report.Mismatches)
+}
+
+func TestVerifyLedgerRebuild(t *testing.T) {
+	dbPrefix, genesisInitState, cfg, release := makeVerifiedLedger(t)
+	defer release()
+	log := logging.TestingLog(t)
+
+	// an account that differs from the replayed one requires rebuilding the
+	// tracker database.
+	data := basics.MakeAccountData(basics.Offline, basics.MicroAlgos{Raw: 1})
+	updateTrackerDB(t, dbPrefix, "UPDATE accountbase SET data=? WHERE address=?", protocol.Encode(&data), testPoolAddr[:])
+
+	report, err := VerifyLedger(log, dbPrefix, genesisInitState, cfg, false)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Mismatches)
+	found := false
+	for _, mismatch := range report.Mismatches {
+		found = found || strings.HasPrefix(mismatch, "account "+testPoolAddr.String())
+	}
+	require.True(t, found, "%v", report.Mismatches)
+
+	report, err = VerifyLedger(log, dbPrefix, genesisInitState, cfg, true)
+	require.NoError(t, err)
+	require.True(t, report.Repaired)
+	_, err = os.Stat(dbPrefix + ".tracker.sqlite.bak")
+	require.NoError(t, err)
+
+	report, err = VerifyLedger(log, dbPrefix, genesisInitState, cfg, false)
+	require.NoError(t, err)
+	require.Empty(t, report.Mismatches)
+
+	// the rebuilt tracker database is picked up by the ledger.
+	l, err := OpenLedger(log, dbPrefix, false, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+	require.Equal(t, basics.Round(420), l.Latest())
+	pool, err := l.LookupWithoutRewards(l.Latest(), testPoolAddr)
+	require.NoError(t, err)
+	require.Equal(t, basics.NotParticipating, pool.Status)
+}
+
+func TestVerifyLedgerMissingTracker(t *testing.T) {
+	dbPrefix, genesisInitState, cfg, release := makeVerifiedLedger(t)
+	defer release()
+	log := logging.TestingLog(t)
+
+	require.NoError(t, os.Remove(dbPrefix+".tracker.sqlite"))
+	report, err := VerifyLedger(log, dbPrefix, genesisInitState, cfg, true)
+	require.NoError(t, err)
+	require.Len(t, report.Mismatches, 1)
+	require.True(t, report.Repaired)
+
+	report, err = VerifyLedger(log, dbPrefix, genesisInitState, cfg, false)
+	require.NoError(t, err)
+	require.Empty(t, report.Mismatches)
+}
@@ -649,6 +649,74 @@ func (c *Client) AccountInformation(account string) (resp v1.Account, err error)
 	return
 }
 
+// AccountInformationV2 takes an address and returns its information,
+// including the applications it created or opted in to
+func (c *Client) AccountInformationV2(account string) (resp generatedV2.Account, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.AccountInformationV2(account)
+	}
+	return
+}
+
+// AccountApplicationInformation takes an address and an application index and
+// returns the local state of the account for that application
+func (c *Client) AccountApplicationInformation(account string, index uint64) (resp generatedV2.ApplicationLocalState, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.AccountApplicationInformation(account, index)
+	}
+	return
+}
+
+// ApplicationInformation takes an application index and returns its creator,
+// programs, schemas and global state
+func (c *Client) ApplicationInformation(index uint64) (resp generatedV2.Application, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.ApplicationInformation(index)
+	}
+	return
+}
+
+// Applications returns up to limit applications, in decreasing index order,
+// starting after the ones of the next token if it isn't empty
+func (c *Client) Applications(limit uint64, next string) (resp generatedV2.ApplicationsResponse, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.Applications(limit, next)
+	}
+	return
+}
+
+// AssetInformationV2 takes an asset index and returns its creator and parameters
+func (c *Client) AssetInformationV2(index uint64) (resp generatedV2.Asset, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.AssetInformationV2(index)
+	}
+	return
+}
+
+// Dryrun takes an encoded DryrunRequest and asks the node to evaluate it
+func (c *Client) Dryrun(data []byte) (resp generatedV2.DryrunResponse, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.RawDryrun(data)
+	}
+	return
+}
+
+// Simulate takes an encoded SimulateRequest and asks the node to evaluate its
+// transaction groups without committing them
+func (c *Client) Simulate(data []byte) (resp generatedV2.SimulateResponse, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.RawSimulate(data)
+	}
+	return
+}
+
 // AssetInformation takes an asset's index and returns its information
 func (c *Client) AssetInformation(index uint64) (resp v1.AssetParams, err error) {
 	algod, err := c.ensureAlgodClient()
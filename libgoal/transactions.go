@@ -627,6 +627,115 @@ func (c *Client) MakeUnsignedAssetFreezeTx(index uint64, accountToChange string,
 	return tx, nil
 }
 
+// MakeUnsignedAppCreateTx makes a transaction for creating an application
+// with the given programs and schemas. onComplete is applied after the
+// application is created, which allows the creator to opt in at creation.
+//
+// Call FillUnsignedTxTemplate afterwards to fill out common fields in
+// the resulting transaction template.
+func (c *Client) MakeUnsignedAppCreateTx(onComplete transactions.OnCompletion, approvalProg []byte, clearProg []byte, globalSchema basics.StateSchema, localSchema basics.StateSchema, appArgs [][]byte, accounts []string, foreignApps []uint64) (tx transactions.Transaction, err error) {
+	return c.MakeUnsignedApplicationCallTx(0, appArgs, accounts, foreignApps, onComplete, approvalProg, clearProg, globalSchema, localSchema)
+}
+
+// MakeUnsignedAppUpdateTx makes a transaction for updating the programs of
+// an application.
+//
+// Call FillUnsignedTxTemplate afterwards to fill out common fields in
+// the resulting transaction template.
+func (c *Client) MakeUnsignedAppUpdateTx(appIdx uint64, appArgs [][]byte, accounts []string, foreignApps []uint64, approvalProg []byte, clearProg []byte) (tx transactions.Transaction, err error) {
+	return c.MakeUnsignedApplicationCallTx(appIdx, appArgs, accounts, foreignApps, transactions.UpdateApplicationOC, approvalProg, clearProg, basics.StateSchema{}, basics.StateSchema{})
+}
+
+// MakeUnsignedAppDeleteTx makes a transaction for deleting an application.
+//
+// Call FillUnsignedTxTemplate afterwards to fill out common fields in
+// the resulting transaction template.
+func (c *Client) MakeUnsignedAppDeleteTx(appIdx uint64, appArgs [][]byte, accounts []string, foreignApps []uint64) (tx transactions.Transaction, err error) {
+	return c.MakeUnsignedApplicationCallTx(appIdx, appArgs, accounts, foreignApps, transactions.DeleteApplicationOC, nil, nil, basics.StateSchema{}, basics.StateSchema{})
+}
+
+// MakeUnsignedAppOptInTx makes a transaction for opting in to (allocating
+// some account-specific state for) an application.
+//
+// Call FillUnsignedTxTemplate afterwards to fill out common fields in
+// the resulting transaction template.
+func (c *Client) MakeUnsignedAppOptInTx(appIdx uint64, appArgs [][]byte, accounts []string, foreignApps []uint64) (tx transactions.Transaction, err error) {
+	return c.MakeUnsignedApplicationCallTx(appIdx, appArgs, accounts, foreignApps, transactions.OptInOC, nil, nil, basics.StateSchema{}, basics.StateSchema{})
+}
+
+// MakeUnsignedAppCloseOutTx makes a transaction for closing out of
+// (deallocating all account-specific state for) an application.
+//
+// Call FillUnsignedTxTemplate afterwards to fill out common fields in
+// the resulting transaction template.
+func (c *Client) MakeUnsignedAppCloseOutTx(appIdx uint64, appArgs [][]byte, accounts []string, foreignApps []uint64) (tx transactions.Transaction, err error) {
+	return c.MakeUnsignedApplicationCallTx(appIdx, appArgs, accounts, foreignApps, transactions.CloseOutOC, nil, nil, basics.StateSchema{}, basics.StateSchema{})
+}
+
+// MakeUnsignedAppClearStateTx makes a transaction for clearing out all
+// account-specific state for an application. It may not be rejected by the
+// application's logic.
+//
+// Call FillUnsignedTxTemplate afterwards to fill out common fields in
+// the resulting transaction template.
+func (c *Client) MakeUnsignedAppClearStateTx(appIdx uint64, appArgs [][]byte, accounts []string, foreignApps []uint64) (tx transactions.Transaction, err error) {
+	return c.MakeUnsignedApplicationCallTx(appIdx, appArgs, accounts, foreignApps, transactions.ClearStateOC, nil, nil, basics.StateSchema{}, basics.StateSchema{})
+}
+
+// MakeUnsignedAppNoOpTx makes a transaction for interacting with an existing
+// application, potentially updating any account-specific local state and
+// global state associated with it.
+//
+// Call FillUnsignedTxTemplate afterwards to fill out common fields in
+// the resulting transaction template.
+func (c *Client) MakeUnsignedAppNoOpTx(appIdx uint64, appArgs [][]byte, accounts []string, foreignApps []uint64) (tx transactions.Transaction, err error) {
+	return c.MakeUnsignedApplicationCallTx(appIdx, appArgs, accounts, foreignApps, transactions.NoOpOC, nil, nil, basics.StateSchema{}, basics.StateSchema{})
+}
+
+// MakeUnsignedApplicationCallTx is a helper for the above ApplicationCall
+// transaction constructors. A fully custom ApplicationCall transaction may
+// be constructed using this method.
+//
+// Call FillUnsignedTxTemplate afterwards to fill out common fields in
+// the resulting transaction template.
+func (c *Client) MakeUnsignedApplicationCallTx(appIdx uint64, appArgs [][]byte, accounts []string, foreignApps []uint64, onCompletion transactions.OnCompletion, approvalProg []byte, clearProg []byte, globalSchema basics.StateSchema, localSchema basics.StateSchema) (tx transactions.Transaction, err error) {
+	tx.Type = protocol.ApplicationCallTx
+	tx.ApplicationID = basics.AppIndex(appIdx)
+	tx.OnCompletion = onCompletion
+
+	tx.ApplicationArgs = appArgs
+	tx.Accounts, err = parseTxnAccounts(accounts)
+	if err != nil {
+		return tx, err
+	}
+
+	tx.ForeignApps = parseTxnForeignApps(foreignApps)
+	tx.ApprovalProgram = approvalProg
+	tx.ClearStateProgram = clearProg
+	tx.LocalStateSchema = localSchema
+	tx.GlobalStateSchema = globalSchema
+
+	return tx, nil
+}
+
+func parseTxnAccounts(accounts []string) (parsed []basics.Address, err error) {
+	for _, acct := range accounts {
+		addr, err := basics.UnmarshalChecksumAddress(acct)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, addr)
+	}
+	return
+}
+
+func parseTxnForeignApps(foreignApps []uint64) (parsed []basics.AppIndex) {
+	for _, aidx := range foreignApps {
+		parsed = append(parsed, basics.AppIndex(aidx))
+	}
+	return
+}
+
 // GroupID computes the group ID for a group of transactions.
 func (c *Client) GroupID(txgroup []transactions.Transaction) (gid crypto.Digest, err error) {
 	var group transactions.TxGroup
@@ -0,0 +1,158 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"sync"
+
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/metrics"
+)
+
+// A compressed message is sent with the CompressedMsgTag tag, followed by the tag of the original message and its
+// deflated payload. Only the peers that negotiated a compression scheme during the connection handshake send and
+// receive compressed messages; the others exchange all their messages as is.
+
+// compressionDeflate is the compression scheme of the compress/flate package, used at its best speed.
+const compressionDeflate = "deflate"
+
+// SupportedCompressionSchemes contains the list of message compression schemes supported by this node ( in order of preference ).
+var SupportedCompressionSchemes = []string{compressionDeflate}
+
+// compressionThresholds are the minimal payload sizes, by tag, of the messages that get compressed. Messages of
+// other tags are never compressed.
+var compressionThresholds = map[protocol.Tag]int{
+	protocol.ProposalPayloadTag: 1024,
+	protocol.TxnTag:             512,
+}
+
+var networkCompressionSentRawBytesTotal = metrics.MakeCounter(metrics.MetricName{Name: "algod_network_compression_sent_raw_bytes_total", Description: "Total size of the messages sent compressed, before compression, by tag"})
+var networkCompressionSentBytesTotal = metrics.MakeCounter(metrics.MetricName{Name: "algod_network_compression_sent_bytes_total", Description: "Total size of the compressed messages sent, by tag"})
+var networkCompressionReceivedRawBytesTotal = metrics.MakeCounter(metrics.MetricName{Name: "algod_network_compression_received_raw_bytes_total", Description: "Total size of the compressed messages received, after decompression, by tag"})
+var networkCompressionReceivedBytesTotal = metrics.MakeCounter(metrics.MetricName{Name: "algod_network_compression_received_bytes_total", Description: "Total size of the compressed messages received, by tag"})
+
+// compressionTagLabels holds the metric labels of the compressed messages, so that they aren't allocated for every
+// message. The tags we don't compress ourselves share the same labels, so that a peer can't make them grow.
+var compressionTagLabels = make(map[protocol.Tag]map[string]string)
+var compressionOtherTagLabels = map[string]string{"tag": "other"}
+
+func init() {
+	for tag := range compressionThresholds {
+		compressionTagLabels[tag] = map[string]string{"tag": string(tag)}
+	}
+}
+
+func compressionLabels(tag protocol.Tag) map[string]string {
+	if labels, has := compressionTagLabels[tag]; has {
+		return labels
+	}
+	return compressionOtherTagLabels
+}
+
+var deflateWriters = sync.Pool{
+	New: func() interface{} {
+		// flate.NewWriter only fails on an invalid level.
+		writer, _ := flate.NewWriter(nil, flate.BestSpeed)
+		return writer
+	},
+}
+
+// checkCompressionMatch looks in the CompressionAcceptHeader headers of a request for a compression scheme we support, and
+// returns it, or an empty string if there is none.
+func checkCompressionMatch(otherHeaders http.Header) string {
+	otherAcceptedSchemes := otherHeaders[textproto.CanonicalMIMEHeaderKey(CompressionAcceptHeader)]
+	for _, supportedScheme := range SupportedCompressionSchemes {
+		for _, otherAcceptedScheme := range otherAcceptedSchemes {
+			if supportedScheme == otherAcceptedScheme {
+				return supportedScheme
+			}
+		}
+	}
+	return ""
+}
+
+// checkCompressionResponse returns the compression scheme the server chose in the CompressionHeader header of its
+// response, or an empty string if it didn't choose one of ours.
+func checkCompressionResponse(otherHeaders http.Header) string {
+	otherScheme := otherHeaders.Get(CompressionHeader)
+	for _, supportedScheme := range SupportedCompressionSchemes {
+		if supportedScheme == otherScheme {
+			return supportedScheme
+		}
+	}
+	return ""
+}
+
+// compressMessage returns the compressed message for the given tag and payload, or nil if the message shouldn't be
+// compressed: either its tag isn't compressed, its payload is below the threshold of its tag, or it doesn't get smaller.
+func compressMessage(tag protocol.Tag, data []byte) []byte {
+	threshold, has := compressionThresholds[tag]
+	if !has || len(data) < threshold {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(protocol.CompressedMsgTag) + len(tag) + len(data))
+	buf.WriteString(string(protocol.CompressedMsgTag))
+	buf.WriteString(string(tag))
+	writer := deflateWriters.Get().(*flate.Writer)
+	defer deflateWriters.Put(writer)
+	writer.Reset(&buf)
+	// writing to a bytes.Buffer doesn't fail.
+	writer.Write(data)
+	writer.Close()
+	if buf.Len() >= len(tag)+len(data) {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// messageDecompressor decompresses the compressed messages of a peer. It isn't safe for concurrent use.
+type messageDecompressor struct {
+	reader io.ReadCloser
+}
+
+// decompress returns the original tag and payload of the payload of a compressed message. The payload can't expand
+// beyond maxMessageLength.
+func (d *messageDecompressor) decompress(data []byte) (protocol.Tag, []byte, error) {
+	if len(data) < len(protocol.CompressedMsgTag) {
+		return "", nil, fmt.Errorf("compressed message too short: %d bytes", len(data))
+	}
+	tag := protocol.Tag(data[:len(protocol.CompressedMsgTag)])
+	compressed := bytes.NewReader(data[len(protocol.CompressedMsgTag):])
+	if d.reader == nil {
+		d.reader = flate.NewReader(compressed)
+	} else if err := d.reader.(flate.Resetter).Reset(compressed, nil); err != nil {
+		return "", nil, err
+	}
+
+	out, err := ioutil.ReadAll(io.LimitReader(d.reader, maxMessageLength+1))
+	if err != nil {
+		return "", nil, err
+	}
+	if len(out) > maxMessageLength {
+		return "", nil, ErrIncomingMsgTooLarge
+	}
+	return tag, out, nil
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestCompressMessage(t *testing.T) {
+	data := bytes.Repeat([]byte("proposal"), 1000)
+	compressed := compressMessage(protocol.ProposalPayloadTag, data)
+	require.NotNil(t, compressed)
+	require.Less(t, len(compressed), len(data))
+	require.Equal(t, protocol.CompressedMsgTag, protocol.Tag(compressed[:2]))
+
+	var decompressor messageDecompressor
+	tag, out, err := decompressor.decompress(compressed[2:])
+	require.NoError(t, err)
+	require.Equal(t, protocol.ProposalPayloadTag, tag)
+	require.Equal(t, data, out)
+
+	// the decompressor is reused for the following messages.
+	data = bytes.Repeat([]byte("txn"), 1000)
+	compressed = compressMessage(protocol.TxnTag, data)
+	require.NotNil(t, compressed)
+	tag, out, err = decompressor.decompress(compressed[2:])
+	require.NoError(t, err)
+	require.Equal(t, protocol.TxnTag, tag)
+	require.Equal(t, data, out)
+
+	// small messages, messages of other tags and messages that don't get smaller aren't compressed.
+	require.Nil(t, compressMessage(protocol.ProposalPayloadTag, data[:100]))
+	require.Nil(t, compressMessage(protocol.AgreementVoteTag, data))
+	random := make([]byte, 4096)
+	crypto.RandBytes(random)
+	require.Nil(t, compressMessage(protocol.ProposalPayloadTag, random))
+
+	_, _, err = decompressor.decompress([]byte("P"))
+	require.Error(t, err)
+	_, _, err = decompressor.decompress(append([]byte(protocol.ProposalPayloadTag), random...))
+	require.Error(t, err)
+
+	// the payload can't expand beyond maxMessageLength.
+	compressed = compressMessage(protocol.ProposalPayloadTag, make([]byte, maxMessageLength+1))
+	require.NotNil(t, compressed)
+	_, _, err = decompressor.decompress(compressed[2:])
+	require.Equal(t, ErrIncomingMsgTooLarge, err)
+}
+
+func TestCompressionMatch(t *testing.T) {
+	header := make(http.Header)
+	require.Equal(t, "", checkCompressionMatch(header))
+	require.Equal(t, "", checkCompressionResponse(header))
+
+	header.Add(CompressionAcceptHeader, "unknown")
+	require.Equal(t, "", checkCompressionMatch(header))
+	header.Add(CompressionAcceptHeader, compressionDeflate)
+	require.Equal(t, compressionDeflate, checkCompressionMatch(header))
+
+	header.Set(CompressionHeader, "unknown")
+	require.Equal(t, "", checkCompressionResponse(header))
+	header.Set(CompressionHeader, compressionDeflate)
+	require.Equal(t, compressionDeflate, checkCompressionResponse(header))
+}
+
+type payloadHandler struct {
+	payloads chan []byte
+}
+
+func (h *payloadHandler) Handle(message IncomingMessage) OutgoingMessage {
+	h.payloads <- message.Data
+	return OutgoingMessage{Action: Ignore}
+}
+
+func testWebsocketNetworkCompression(t *testing.T, enableA, enableB bool, compression string) {
+	netA := makeTestWebsocketNode(t)
+	netA.config.GossipFanout = 1
+	netA.config.EnableMessageCompression = enableA
+	netA.Start()
+	defer func() { t.Log("stopping A"); netA.Stop(); t.Log("A done") }()
+	netB := makeTestWebsocketNode(t)
+	netB.config.GossipFanout = 1
+	netB.config.EnableMessageCompression = enableB
+	addrA, postListen := netA.Address()
+	require.True(t, postListen)
+	netB.phonebook.ReplacePeerList([]string{addrA}, "default")
+	netB.Start()
+	defer func() { t.Log("stopping B"); netB.Stop(); t.Log("B done") }()
+
+	handlerA := &payloadHandler{payloads: make(chan []byte, 10)}
+	netA.RegisterHandlers([]TaggedMessageHandler{{Tag: protocol.ProposalPayloadTag, MessageHandler: handlerA}})
+	handlerB := &payloadHandler{payloads: make(chan []byte, 10)}
+	netB.RegisterHandlers([]TaggedMessageHandler{{Tag: protocol.ProposalPayloadTag, MessageHandler: handlerB}})
+
+	readyTimeout := time.NewTimer(2 * time.Second)
+	waitReady(t, netA, readyTimeout.C)
+	waitReady(t, netB, readyTimeout.C)
+
+	for _, wn := range []*WebsocketNetwork{netA, netB} {
+		wn.peersLock.RLock()
+		require.Equal(t, 1, len(wn.peers))
+		require.Equal(t, compression, wn.peers[0].compression)
+		wn.peersLock.RUnlock()
+	}
+
+	// compressed or not, the messages are received as they were sent, both ways.
+	payloads := [][]byte{bytes.Repeat([]byte("proposal"), 1000), []byte("small")}
+	for _, payload := range payloads {
+		netA.Broadcast(context.Background(), protocol.ProposalPayloadTag, payload, true, nil)
+		netB.Broadcast(context.Background(), protocol.ProposalPayloadTag, payload, true, nil)
+	}
+	for _, handler := range []*payloadHandler{handlerA, handlerB} {
+		var received [][]byte
+		for range payloads {
+			select {
+			case payload := <-handler.payloads:
+				received = append(received, payload)
+			case <-time.After(2 * time.Second):
+				require.Fail(t, "timeout waiting for the message")
+			}
+		}
+		require.ElementsMatch(t, payloads, received)
+	}
+}
+
+func TestWebsocketNetworkCompression(t *testing.T) {
+	testWebsocketNetworkCompression(t, true, true, compressionDeflate)
+}
+
+func TestWebsocketNetworkCompressionFallback(t *testing.T) {
+	testWebsocketNetworkCompression(t, true, false, "")
+	testWebsocketNetworkCompression(t, false, true, "")
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"time"
+
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/metrics"
+)
+
+var networkMessageRateLimitedTotal = metrics.MakeCounter(metrics.MetricName{Name: "algod_network_message_rate_limited_total", Description: "number of incoming messages dropped for exceeding the rate limit of their tag, by tag"})
+var networkMessageUninterestedTotal = metrics.MakeCounter(metrics.MetricName{Name: "algod_network_message_uninterested_total", Description: "number of incoming messages of a rate limited tag dropped since the node isn't interested in the tag, by tag"})
+
+// msgRateLimit is the token-bucket limit of the messages of a tag each peer can send.
+type msgRateLimit struct {
+	// rate is the number of messages per second the bucket refills with.
+	rate float64
+	// capacity is the number of messages the bucket holds.
+	capacity float64
+	// labels are the metric labels of the messages of the tag.
+	labels map[string]string
+}
+
+// makeMsgRateLimits parses the IncomingMessageRateLimits of the config, ignoring the invalid tags.
+func (wn *WebsocketNetwork) makeMsgRateLimits() map[protocol.Tag]*msgRateLimit {
+	if len(wn.config.IncomingMessageRateLimits) == 0 {
+		return nil
+	}
+	burstSeconds := float64(wn.config.IncomingMessageRateBurstSeconds)
+	if burstSeconds < 1 {
+		burstSeconds = 1
+	}
+	limits := make(map[protocol.Tag]*msgRateLimit, len(wn.config.IncomingMessageRateLimits))
+	for tag, rate := range wn.config.IncomingMessageRateLimits {
+		if len(tag) != len(protocol.TxnTag) {
+			wn.log.Warnf("ignoring the rate limit of invalid tag %s of IncomingMessageRateLimits", tag)
+			continue
+		}
+		limits[protocol.Tag(tag)] = &msgRateLimit{
+			rate:     float64(rate),
+			capacity: float64(rate) * burstSeconds,
+			labels:   map[string]string{"tag": tag},
+		}
+	}
+	return limits
+}
+
+// tokenBucket holds the messages of a tag a peer can still send, as of its last update.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills the bucket up to the given time, and takes a message out of it if it isn't empty.
+func (tb *tokenBucket) take(limit *msgRateLimit, now time.Time) bool {
+	if elapsed := now.Sub(tb.updated).Seconds(); elapsed > 0 {
+		tb.tokens += elapsed * limit.rate
+		if tb.tokens > limit.capacity {
+			tb.tokens = limit.capacity
+		}
+		tb.updated = now
+	}
+	if tb.tokens < 1 {
+		return false
+	}
+	tb.tokens--
+	return true
+}
+
+// msgRateLimiter holds the token buckets of the tags of a peer. It is used by the read loop of the peer only, and
+// isn't safe for concurrent use.
+type msgRateLimiter struct {
+	limits  map[protocol.Tag]*msgRateLimit
+	buckets map[protocol.Tag]*tokenBucket
+}
+
+func makeMsgRateLimiter(limits map[protocol.Tag]*msgRateLimit) msgRateLimiter {
+	return msgRateLimiter{limits: limits, buckets: make(map[protocol.Tag]*tokenBucket, len(limits))}
+}
+
+// allow tells whether a message of the given tag is within the rate limit of its tag, and counts the dropped ones.
+// When the node announced the tags it is interested in, the messages of the limited tags outside of them are
+// dropped, since the peer was asked not to send them.
+func (rl *msgRateLimiter) allow(tag protocol.Tag, interest map[protocol.Tag]bool, now time.Time) bool {
+	limit, has := rl.limits[tag]
+	if !has {
+		return true
+	}
+	if interest != nil && !interest[tag] {
+		networkMessageUninterestedTotal.Inc(limit.labels)
+		return false
+	}
+	bucket := rl.buckets[tag]
+	if bucket == nil {
+		bucket = &tokenBucket{tokens: limit.capacity, updated: now}
+		rl.buckets[tag] = bucket
+	}
+	if bucket.take(limit, now) {
+		return true
+	}
+	networkMessageRateLimitedTotal.Inc(limit.labels)
+	return false
+}
+
+// setMessagesOfInterest records the tags the node announced it is interested in with a message-of-interest message.
+func (wn *WebsocketNetwork) setMessagesOfInterest(data []byte) {
+	tags, err := unmarshallMessageOfInterest(data)
+	if err != nil {
+		wn.log.Warnf("could not unmarshall the message-of-interest message we sent: %v", err)
+		return
+	}
+	wn.messagesOfInterest.Store(tags)
+}
+
+// getMessagesOfInterest returns the tags the node announced it is interested in, or nil if it didn't.
+func (wn *WebsocketNetwork) getMessagesOfInterest() map[protocol.Tag]bool {
+	tags, _ := wn.messagesOfInterest.Load().(map[protocol.Tag]bool)
+	return tags
+}
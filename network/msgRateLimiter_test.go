@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestMsgRateLimiter(t *testing.T) {
+	wn := makeTestWebsocketNode(t)
+	wn.config.IncomingMessageRateLimits = map[string]uint64{"TX": 10, "invalid": 10}
+	wn.config.IncomingMessageRateBurstSeconds = 2
+	limits := wn.makeMsgRateLimits()
+	require.Equal(t, 1, len(limits))
+	require.Equal(t, 20.0, limits[protocol.TxnTag].capacity)
+
+	now := time.Now()
+	rl := makeMsgRateLimiter(limits)
+	// a new peer can send a burst of its capacity at once.
+	for i := 0; i < 20; i++ {
+		require.True(t, rl.allow(protocol.TxnTag, nil, now))
+	}
+	require.False(t, rl.allow(protocol.TxnTag, nil, now))
+	// the tags without a limit aren't limited.
+	require.True(t, rl.allow(protocol.ProposalPayloadTag, nil, now))
+
+	// the bucket refills with the rate, up to its capacity.
+	now = now.Add(time.Second)
+	for i := 0; i < 10; i++ {
+		require.True(t, rl.allow(protocol.TxnTag, nil, now))
+	}
+	require.False(t, rl.allow(protocol.TxnTag, nil, now))
+	now = now.Add(time.Minute)
+	for i := 0; i < 20; i++ {
+		require.True(t, rl.allow(protocol.TxnTag, nil, now))
+	}
+	require.False(t, rl.allow(protocol.TxnTag, nil, now))
+
+	// the limited tags the node isn't interested in are dropped, even with budget left, unlike the other tags.
+	now = now.Add(time.Minute)
+	require.False(t, rl.allow(protocol.TxnTag, map[protocol.Tag]bool{protocol.AgreementVoteTag: true}, now))
+	require.True(t, rl.allow(protocol.ProposalPayloadTag, map[protocol.Tag]bool{protocol.AgreementVoteTag: true}, now))
+	for i := 0; i < 20; i++ {
+		require.True(t, rl.allow(protocol.TxnTag, map[protocol.Tag]bool{protocol.TxnTag: true}, now))
+	}
+	require.False(t, rl.allow(protocol.TxnTag, map[protocol.Tag]bool{protocol.TxnTag: true}, now))
+}
+
+func TestMessagesOfInterest(t *testing.T) {
+	wn := makeTestWebsocketNode(t)
+	require.Nil(t, wn.getMessagesOfInterest())
+	wn.setMessagesOfInterest(MarshallMessageOfInterest([]protocol.Tag{protocol.TxnTag, protocol.AgreementVoteTag}))
+	require.Equal(t, map[protocol.Tag]bool{protocol.TxnTag: true, protocol.AgreementVoteTag: true}, wn.getMessagesOfInterest())
+	wn.setMessagesOfInterest([]byte{0x88})
+	require.Equal(t, map[protocol.Tag]bool{protocol.TxnTag: true, protocol.AgreementVoteTag: true}, wn.getMessagesOfInterest())
+}
+
+func testWebsocketNetworkMessageRateLimit(t *testing.T, compression bool) {
+	conf := defaultConfig
+	conf.GossipFanout = 1
+	conf.EnableMessageCompression = compression
+	conf.IncomingMessageRateLimits = map[string]uint64{string(protocol.ProposalPayloadTag): 1}
+	conf.IncomingMessageRateBurstSeconds = 2
+	netA := makeTestWebsocketNodeWithConfig(t, conf)
+	handlerA := &payloadHandler{payloads: make(chan []byte, 10)}
+	netA.RegisterHandlers([]TaggedMessageHandler{{Tag: protocol.ProposalPayloadTag, MessageHandler: handlerA}})
+	netA.Start()
+	defer func() { t.Log("stopping A"); netA.Stop(); t.Log("A done") }()
+
+	netB := makeTestWebsocketNode(t)
+	netB.config.GossipFanout = 1
+	netB.config.EnableMessageCompression = compression
+	addrA, postListen := netA.Address()
+	require.True(t, postListen)
+	netB.phonebook.ReplacePeerList([]string{addrA}, "default")
+	netB.Start()
+	defer func() { t.Log("stopping B"); netB.Stop(); t.Log("B done") }()
+
+	readyTimeout := time.NewTimer(2 * time.Second)
+	waitReady(t, netA, readyTimeout.C)
+	waitReady(t, netB, readyTimeout.C)
+
+	// only a burst of two messages gets through, compressed or not, and the peer stays connected.
+	payload := []byte("proposal")
+	if compression {
+		payload = bytes.Repeat(payload, 1000)
+		require.NotNil(t, compressMessage(protocol.ProposalPayloadTag, payload))
+	}
+	for i := 0; i < 10; i++ {
+		netB.Broadcast(context.Background(), protocol.ProposalPayloadTag, payload, true, nil)
+	}
+	require.Eventually(t, func() bool { return len(handlerA.payloads) == 2 }, 2*time.Second, 10*time.Millisecond)
+	require.Never(t, func() bool { return len(handlerA.payloads) > 2 }, 500*time.Millisecond, 10*time.Millisecond)
+	require.Equal(t, 1, netA.NumPeers())
+}
+
+func TestWebsocketNetworkMessageRateLimit(t *testing.T) {
+	testWebsocketNetworkMessageRateLimit(t, false)
+}
+
+func TestWebsocketNetworkMessageRateLimitCompressed(t *testing.T) {
+	testWebsocketNetworkMessageRateLimit(t, true)
+}
@@ -0,0 +1,418 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// The peer identity handshake lets two nodes prove to each other that they
+// hold the secret key of an ed25519 identity. The node opening a connection
+// sends a challenge in its request, which the other node signs in its
+// response along with a challenge of its own. Once connected, the first node
+// signs that second challenge in a NetIdentityVerificationTag message.
+//
+// Each signature covers an identityProof, which binds the challenge to the
+// role of the signer and to the challenge it sent, so that a peer can't
+// pass the signature a node made on another connection off as its own.
+
+// peerIdentityVerificationTimeout is how long an incoming peer has to prove its
+// identity when the node only accepts the peers of its allowlist.
+const peerIdentityVerificationTimeout = time.Minute
+
+// identityVerificationLength is the length of a NetIdentityVerificationTag
+// message: the identity of the peer followed by its signature of the challenge.
+const identityVerificationLength = len(crypto.PublicKey{}) + len(crypto.Signature{})
+
+// identityChallenge is a challenge a peer signs to prove its identity.
+type identityChallenge [32]byte
+
+func newIdentityChallenge() (c identityChallenge) {
+	crypto.RandBytes(c[:])
+	return
+}
+
+func parseIdentityChallenge(s string) (c identityChallenge, err error) {
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return
+	}
+	if len(buf) != len(c) {
+		return c, fmt.Errorf("identity challenge has %d bytes rather than %d", len(buf), len(c))
+	}
+	copy(c[:], buf)
+	return
+}
+
+func (c identityChallenge) String() string {
+	return base64.StdEncoding.EncodeToString(c[:])
+}
+
+// identityProof is what a node signs to prove its identity to a peer.
+type identityProof struct {
+	// responder is set if the node accepted the connection, and clear if it
+	// opened it.
+	responder bool
+
+	// answered is the challenge of the peer that the node answers, and sent
+	// the one the node sent to the peer.
+	answered identityChallenge
+	sent     identityChallenge
+
+	// prover is the identity the node proves, and verifier the identity the
+	// peer proved first, if any.
+	prover   crypto.PublicKey
+	verifier crypto.PublicKey
+}
+
+// ToBeHashed implements the crypto.Hashable interface.
+func (p identityProof) ToBeHashed() (protocol.HashID, []byte) {
+	buf := make([]byte, 0, 1+len(p.answered)+len(p.sent)+len(p.prover)+len(p.verifier))
+	if p.responder {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	buf = append(buf, p.answered[:]...)
+	buf = append(buf, p.sent[:]...)
+	buf = append(buf, p.prover[:]...)
+	buf = append(buf, p.verifier[:]...)
+	return protocol.NetIdentityChallenge, buf
+}
+
+// LoadIdentity reads the key a node proves its identity with from the given
+// file, creating the file with a new random key if it doesn't exist.
+func LoadIdentity(filename string) (*crypto.SignatureSecrets, error) {
+	var seed crypto.Seed
+	buf, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		crypto.RandBytes(seed[:])
+		err = ioutil.WriteFile(filename, seed[:], 0600)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.GenerateSignatureSecrets(seed), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) != len(seed) {
+		return nil, fmt.Errorf("identity file %s has %d bytes rather than %d", filename, len(buf), len(seed))
+	}
+	copy(seed[:], buf)
+	return crypto.GenerateSignatureSecrets(seed), nil
+}
+
+// SetIdentity enables the peer identity handshake, in which the node proves
+// its identity with the given key. It must be called before Start.
+func (wn *WebsocketNetwork) SetIdentity(identity *crypto.SignatureSecrets) {
+	wn.identity = identity
+	wn.identityTracker = makeIdentityTracker(identity.SignatureVerifier)
+}
+
+// parseIdentityList parses the identities of PeerIdentityAllowlist or
+// PeerIdentityDenylist, which are written as addresses.
+func (wn *WebsocketNetwork) parseIdentityList(name string, list map[string]bool) map[crypto.PublicKey]bool {
+	identities := make(map[crypto.PublicKey]bool, len(list))
+	for s, listed := range list {
+		if !listed {
+			continue
+		}
+		addr, err := basics.UnmarshalChecksumAddress(s)
+		if err != nil {
+			wn.log.Warnf("ignoring invalid identity %s of %s: %v", s, name, err)
+			continue
+		}
+		identities[crypto.PublicKey(addr)] = true
+	}
+	return identities
+}
+
+func identityString(identity crypto.PublicKey) string {
+	return basics.Address(identity).String()
+}
+
+// identityRequired tells whether the node only accepts peers that prove an
+// identity of its allowlist.
+func (wn *WebsocketNetwork) identityRequired() bool {
+	return wn.identity != nil && len(wn.identityAllowlist) > 0
+}
+
+// checkIdentityPermitted returns an error if the allowlist or the denylist
+// don't let the node connect with the given identity.
+func (wn *WebsocketNetwork) checkIdentityPermitted(identity crypto.PublicKey) error {
+	if wn.identityDenylist[identity] {
+		return fmt.Errorf("identity %s is denied", identityString(identity))
+	}
+	if len(wn.identityAllowlist) > 0 && !wn.identityAllowlist[identity] {
+		return fmt.Errorf("identity %s is not allowed", identityString(identity))
+	}
+	return nil
+}
+
+// setIdentityHeaders proves the identity of the node in the response to a
+// request that included the given challenge; sent is the challenge of the
+// response.
+func (wn *WebsocketNetwork) setIdentityHeaders(header http.Header, challenge identityChallenge, sent identityChallenge) {
+	sig := wn.identity.Sign(identityProof{
+		responder: true,
+		answered:  challenge,
+		sent:      sent,
+		prover:    wn.identity.SignatureVerifier,
+	})
+	header.Set(IdentityHeader, identityString(wn.identity.SignatureVerifier))
+	header.Set(IdentitySignatureHeader, base64.StdEncoding.EncodeToString(sig[:]))
+}
+
+// verifyIdentityHeaders checks the identity a node proved in its response to
+// a request that included the given challenge. It returns whether the node
+// proved an identity, and an error if the connection should be dropped.
+func (wn *WebsocketNetwork) verifyIdentityHeaders(header http.Header, challenge identityChallenge) (identity crypto.PublicKey, verified bool, err error) {
+	identityStr := header.Get(IdentityHeader)
+	if identityStr == "" {
+		if wn.identityRequired() {
+			return identity, false, fmt.Errorf("peer did not prove its identity")
+		}
+		return identity, false, nil
+	}
+
+	addr, err := basics.UnmarshalChecksumAddress(identityStr)
+	if err != nil {
+		return identity, false, fmt.Errorf("invalid identity %s: %v", identityStr, err)
+	}
+	identity = crypto.PublicKey(addr)
+	sigBytes, err := base64.StdEncoding.DecodeString(header.Get(IdentitySignatureHeader))
+	if err != nil {
+		return identity, false, fmt.Errorf("invalid signature of identity %s: %v", identityStr, err)
+	}
+	var sig crypto.Signature
+	if len(sigBytes) != len(sig) {
+		return identity, false, fmt.Errorf("signature of identity %s has %d bytes rather than %d", identityStr, len(sigBytes), len(sig))
+	}
+	copy(sig[:], sigBytes)
+	// the challenge of the response is covered by the signature, whether it
+	// is valid or not.
+	sent, _ := parseIdentityChallenge(header.Get(IdentityChallengeHeader))
+	proof := identityProof{
+		responder: true,
+		answered:  challenge,
+		sent:      sent,
+		prover:    identity,
+	}
+	if !identity.Verify(proof, sig) {
+		return identity, false, fmt.Errorf("bad signature of identity %s", identityStr)
+	}
+
+	err = wn.checkIdentityPermitted(identity)
+	if err != nil {
+		return identity, false, err
+	}
+	return identity, true, nil
+}
+
+// makeIdentityVerification makes the NetIdentityVerificationTag message that
+// proves the identity of the node to a peer whose response included the
+// given challenge. sent is the challenge of the request of the node, and
+// verifier the identity the peer proved in its response, if any.
+func (wn *WebsocketNetwork) makeIdentityVerification(challenge identityChallenge, sent identityChallenge, verifier crypto.PublicKey) []byte {
+	sig := wn.identity.Sign(identityProof{
+		answered: challenge,
+		sent:     sent,
+		prover:   wn.identity.SignatureVerifier,
+		verifier: verifier,
+	})
+	msg := make([]byte, 0, len(protocol.NetIdentityVerificationTag)+identityVerificationLength)
+	msg = append(msg, []byte(protocol.NetIdentityVerificationTag)...)
+	msg = append(msg, wn.identity.SignatureVerifier[:]...)
+	return append(msg, sig[:]...)
+}
+
+// verifyIdentityVerification returns the identity proved by the data of a
+// NetIdentityVerificationTag message answering the given challenge of a
+// response. received is the challenge of the request the response answered,
+// and verifier the identity the node proved in the response, if any.
+func verifyIdentityVerification(challenge identityChallenge, received identityChallenge, verifier crypto.PublicKey, data []byte) (identity crypto.PublicKey, err error) {
+	if len(data) != identityVerificationLength {
+		return identity, fmt.Errorf("identity verification has %d bytes rather than %d", len(data), identityVerificationLength)
+	}
+	var sig crypto.Signature
+	copy(identity[:], data)
+	copy(sig[:], data[len(identity):])
+	proof := identityProof{
+		answered: challenge,
+		sent:     received,
+		prover:   identity,
+		verifier: verifier,
+	}
+	if !identity.Verify(proof, sig) {
+		return identity, fmt.Errorf("bad signature of identity %s", identityString(identity))
+	}
+	return identity, nil
+}
+
+func identityVerificationHandler(message IncomingMessage) OutgoingMessage {
+	wn := message.Net.(*WebsocketNetwork)
+	peer := message.Sender.(*wsPeer)
+	if wn.identity == nil || peer.outgoing || peer.identityChallenge == (identityChallenge{}) || peer.hasIdentity() {
+		return OutgoingMessage{}
+	}
+
+	// the node proved its identity in its response if the request had a
+	// challenge.
+	var verifier crypto.PublicKey
+	if peer.requestIdentityChallenge != (identityChallenge{}) {
+		verifier = wn.identity.SignatureVerifier
+	}
+	identity, err := verifyIdentityVerification(peer.identityChallenge, peer.requestIdentityChallenge, verifier, message.Data)
+	if err != nil {
+		wn.log.Warnf("peer %s failed to prove its identity: %v", peer.rootURL, err)
+		networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "bad identity verification"})
+		return OutgoingMessage{Action: Disconnect}
+	}
+	err = wn.checkIdentityPermitted(identity)
+	if err != nil {
+		wn.log.Infof("peer %s rejected: %v", peer.rootURL, err)
+		networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "identity not permitted"})
+		wn.wg.Add(1)
+		go wn.disconnectThread(peer, disconnectIdentityNotPermitted)
+		return OutgoingMessage{}
+	}
+	wn.claimIdentity(peer, identity)
+	return OutgoingMessage{}
+}
+
+var identityHandlers = []TaggedMessageHandler{
+	TaggedMessageHandler{protocol.NetIdentityVerificationTag, HandlerFunc(identityVerificationHandler)},
+}
+
+// claimIdentity records the identity a peer proved. If another peer has the
+// same identity, one of the two is disconnected; claimIdentity returns false
+// if it is the given one. The peers of a banned or throttled identity are
+// disconnected too.
+func (wn *WebsocketNetwork) claimIdentity(peer *wsPeer, identity crypto.PublicKey) bool {
+	if wn.peerBlocked(identityString(identity)) {
+		wn.log.Infof("dropping connection with peer %s of banned or throttled identity %s", peer.rootURL, identityString(identity))
+		networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "banned or throttled"})
+		if !peer.outgoing {
+			wn.wg.Add(1)
+			go wn.disconnectThread(peer, disconnectBanned)
+		}
+		return false
+	}
+
+	wn.peersLock.Lock()
+	drop := wn.identityTracker.setIdentity(peer, identity)
+	wn.peersLock.Unlock()
+
+	if drop == nil {
+		return true
+	}
+	wn.log.Infof("dropping duplicate connection with peer %s of identity %s", drop.rootURL, identityString(identity))
+	networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "duplicate identity"})
+	if drop == peer {
+		if !peer.outgoing {
+			wn.wg.Add(1)
+			go wn.disconnectThread(peer, disconnectDuplicateIdentity)
+		}
+		return false
+	}
+	wn.wg.Add(1)
+	go wn.disconnectThread(drop, disconnectDuplicateIdentity)
+	return true
+}
+
+// unverifiedIdentityMessage tells whether a message should be ignored, since
+// it comes from an incoming peer that hasn't yet proved an identity while the
+// node only accepts the peers of its allowlist.
+func (wn *WebsocketNetwork) unverifiedIdentityMessage(msg IncomingMessage) bool {
+	if !wn.identityRequired() || msg.Tag == protocol.NetIdentityVerificationTag {
+		return false
+	}
+	peer, ok := msg.Sender.(*wsPeer)
+	return ok && !peer.outgoing && !peer.hasIdentity()
+}
+
+// The identityTracker keeps a single peer per identity. The data structure is
+// not thread-safe; it is protected by wn.peersLock.
+type identityTracker struct {
+	// local is the identity of the node.
+	local crypto.PublicKey
+
+	peerByIdentity map[crypto.PublicKey]*wsPeer
+}
+
+func makeIdentityTracker(local crypto.PublicKey) *identityTracker {
+	return &identityTracker{
+		local:          local,
+		peerByIdentity: make(map[crypto.PublicKey]*wsPeer),
+	}
+}
+
+// setIdentity records the identity a peer proved, and returns the peer to
+// disconnect if another one has the same identity. Of two connections made
+// in the same direction, the first one is kept. Of two connections made in
+// opposite directions, which happens when two nodes connect to each other at
+// the same time, both nodes keep the one opened by the node with the lowest
+// identity, so that they don't each drop a different one.
+func (it *identityTracker) setIdentity(peer *wsPeer, identity crypto.PublicKey) (drop *wsPeer) {
+	old, ok := it.peerByIdentity[identity]
+	if ok && old != peer {
+		if old.outgoing == peer.outgoing || it.initiator(old) == it.keptInitiator(identity) {
+			return peer
+		}
+		drop = old
+	}
+
+	it.peerByIdentity[identity] = peer
+	peer.identity = identity
+	atomic.StoreUint32(&peer.identityVerified, 1)
+	return
+}
+
+func (it *identityTracker) initiator(peer *wsPeer) crypto.PublicKey {
+	if peer.outgoing {
+		return it.local
+	}
+	return peer.identity
+}
+
+func (it *identityTracker) keptInitiator(remote crypto.PublicKey) crypto.PublicKey {
+	if bytes.Compare(it.local[:], remote[:]) < 0 {
+		return it.local
+	}
+	return remote
+}
+
+func (it *identityTracker) removePeer(peer *wsPeer) {
+	if !peer.hasIdentity() {
+		return
+	}
+	if it.peerByIdentity[peer.identity] == peer {
+		delete(it.peerByIdentity, peer.identity)
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func makeTestIdentity() *crypto.SignatureSecrets {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	return crypto.GenerateSignatureSecrets(seed)
+}
+
+func TestLoadIdentity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "netidentity")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "identity")
+
+	identity, err := LoadIdentity(filename)
+	require.NoError(t, err)
+	loaded, err := LoadIdentity(filename)
+	require.NoError(t, err)
+	require.Equal(t, identity, loaded)
+
+	err = ioutil.WriteFile(filename, []byte("short"), 0600)
+	require.NoError(t, err)
+	_, err = LoadIdentity(filename)
+	require.Error(t, err)
+}
+
+func TestIdentityHeaders(t *testing.T) {
+	identityA := makeTestIdentity()
+	identityB := makeTestIdentity()
+	netA := makeTestWebsocketNode(t)
+	netA.SetIdentity(identityA)
+	netB := makeTestWebsocketNode(t)
+	netB.SetIdentity(identityB)
+
+	challenge := newIdentityChallenge()
+	parsed, err := parseIdentityChallenge(challenge.String())
+	require.NoError(t, err)
+	require.Equal(t, challenge, parsed)
+
+	header := make(http.Header)
+	_, verified, err := netB.verifyIdentityHeaders(header, challenge)
+	require.NoError(t, err)
+	require.False(t, verified)
+
+	sent := newIdentityChallenge()
+	netA.setIdentityHeaders(header, challenge, sent)
+	header.Set(IdentityChallengeHeader, sent.String())
+	identity, verified, err := netB.verifyIdentityHeaders(header, challenge)
+	require.NoError(t, err)
+	require.True(t, verified)
+	require.Equal(t, identityA.SignatureVerifier, identity)
+
+	// the signature only answers the challenge it was made for, along with
+	// the challenge of the response.
+	_, _, err = netB.verifyIdentityHeaders(header, newIdentityChallenge())
+	require.Error(t, err)
+	header.Set(IdentityChallengeHeader, newIdentityChallenge().String())
+	_, _, err = netB.verifyIdentityHeaders(header, challenge)
+	require.Error(t, err)
+	header.Set(IdentityChallengeHeader, sent.String())
+
+	netB.identityDenylist = map[crypto.PublicKey]bool{identityA.SignatureVerifier: true}
+	_, _, err = netB.verifyIdentityHeaders(header, challenge)
+	require.Error(t, err)
+
+	// with an allowlist, the peers have to prove an identity of the list.
+	netB.identityDenylist = nil
+	netB.identityAllowlist = map[crypto.PublicKey]bool{identityB.SignatureVerifier: true}
+	_, _, err = netB.verifyIdentityHeaders(header, challenge)
+	require.Error(t, err)
+	_, _, err = netB.verifyIdentityHeaders(make(http.Header), challenge)
+	require.Error(t, err)
+	netB.identityAllowlist[identityA.SignatureVerifier] = true
+	_, verified, err = netB.verifyIdentityHeaders(header, challenge)
+	require.NoError(t, err)
+	require.True(t, verified)
+}
+
+func TestIdentityVerification(t *testing.T) {
+	identityA := makeTestIdentity()
+	netA := makeTestWebsocketNode(t)
+	netA.SetIdentity(identityA)
+	verifier := makeTestIdentity().SignatureVerifier
+
+	challenge := newIdentityChallenge()
+	sent := newIdentityChallenge()
+	msg := netA.makeIdentityVerification(challenge, sent, verifier)
+	require.Equal(t, []byte(protocol.NetIdentityVerificationTag), msg[:len(protocol.NetIdentityVerificationTag)])
+	data := msg[len(protocol.NetIdentityVerificationTag):]
+
+	identity, err := verifyIdentityVerification(challenge, sent, verifier, data)
+	require.NoError(t, err)
+	require.Equal(t, identityA.SignatureVerifier, identity)
+
+	_, err = verifyIdentityVerification(newIdentityChallenge(), sent, verifier, data)
+	require.Error(t, err)
+	_, err = verifyIdentityVerification(challenge, newIdentityChallenge(), verifier, data)
+	require.Error(t, err)
+	_, err = verifyIdentityVerification(challenge, sent, crypto.PublicKey{}, data)
+	require.Error(t, err)
+	_, err = verifyIdentityVerification(challenge, sent, verifier, data[1:])
+	require.Error(t, err)
+}
+
+// TestIdentityReflection checks that a peer can't prove the identity of a
+// node with the signatures the node made on other connections.
+func TestIdentityReflection(t *testing.T) {
+	identityA := makeTestIdentity()
+	identityB := makeTestIdentity()
+	netA := makeTestWebsocketNode(t)
+	netA.SetIdentity(identityA)
+	netB := makeTestWebsocketNode(t)
+	netB.SetIdentity(identityB)
+
+	// a peer connects to B, and receives the challenge of B's response.
+	requestToB := newIdentityChallenge()
+	challengeOfB := newIdentityChallenge()
+
+	// the peer connects to A with the challenge of B in its request, and
+	// passes A's response off as its verification message to B.
+	header := make(http.Header)
+	challengeOfA := newIdentityChallenge()
+	netA.setIdentityHeaders(header, challengeOfB, challengeOfA)
+	sigBytes, err := base64.StdEncoding.DecodeString(header.Get(IdentitySignatureHeader))
+	require.NoError(t, err)
+	reflected := append(append([]byte(nil), identityA.SignatureVerifier[:]...), sigBytes...)
+	_, err = verifyIdentityVerification(challengeOfB, requestToB, identityB.SignatureVerifier, reflected)
+	require.Error(t, err)
+	// whichever challenge the peer sent A.
+	_, err = verifyIdentityVerification(challengeOfB, challengeOfA, identityB.SignatureVerifier, reflected)
+	require.Error(t, err)
+
+	// nor can a peer that B connects to pass the verification message A sent
+	// it off as its response to B.
+	msg := netA.makeIdentityVerification(requestToB, challengeOfB, identityB.SignatureVerifier)
+	data := msg[len(protocol.NetIdentityVerificationTag):]
+	header = make(http.Header)
+	header.Set(IdentityHeader, identityString(identityA.SignatureVerifier))
+	header.Set(IdentitySignatureHeader, base64.StdEncoding.EncodeToString(data[len(crypto.PublicKey{}):]))
+	header.Set(IdentityChallengeHeader, challengeOfB.String())
+	_, _, err = netB.verifyIdentityHeaders(header, requestToB)
+	require.Error(t, err)
+}
+
+func TestIdentityTracker(t *testing.T) {
+	local := makeTestIdentity().SignatureVerifier
+	remote := makeTestIdentity().SignatureVerifier
+	it := makeIdentityTracker(local)
+
+	// of two connections in the same direction, the first one is kept.
+	first := &wsPeer{}
+	second := &wsPeer{}
+	require.Nil(t, it.setIdentity(first, remote))
+	require.Equal(t, second, it.setIdentity(second, remote))
+	require.True(t, first.hasIdentity())
+	require.False(t, second.hasIdentity())
+	it.removePeer(second)
+	require.Equal(t, first, it.peerByIdentity[remote])
+	it.removePeer(first)
+	require.Empty(t, it.peerByIdentity)
+
+	// of two connections in opposite directions, the one opened by the lowest identity is kept.
+	outgoing := &wsPeer{outgoing: true}
+	incoming := &wsPeer{}
+	kept, dropped := outgoing, incoming
+	if bytes.Compare(remote[:], local[:]) < 0 {
+		kept, dropped = incoming, outgoing
+	}
+	require.Nil(t, it.setIdentity(outgoing, remote))
+	drop := it.setIdentity(incoming, remote)
+	require.Equal(t, dropped, drop)
+	require.Equal(t, kept, it.peerByIdentity[remote])
+
+	it = makeIdentityTracker(local)
+	outgoing = &wsPeer{outgoing: true}
+	incoming = &wsPeer{}
+	if kept.outgoing {
+		kept, dropped = outgoing, incoming
+	} else {
+		kept, dropped = incoming, outgoing
+	}
+	require.Nil(t, it.setIdentity(incoming, remote))
+	drop = it.setIdentity(outgoing, remote)
+	require.Equal(t, dropped, drop)
+	require.Equal(t, kept, it.peerByIdentity[remote])
+}
+
+func waitIdentity(t *testing.T, wn *WebsocketNetwork, identity crypto.PublicKey) {
+	require.Eventually(t, func() bool {
+		wn.peersLock.RLock()
+		defer wn.peersLock.RUnlock()
+		return len(wn.peers) == 1 && wn.peers[0].hasIdentity() && wn.peers[0].identity == identity
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWebsocketNetworkIdentity(t *testing.T) {
+	identityA := makeTestIdentity()
+	netA := makeTestWebsocketNode(t)
+	netA.SetIdentity(identityA)
+	netA.config.GossipFanout = 1
+	netA.Start()
+	defer func() { t.Log("stopping A"); netA.Stop(); t.Log("A done") }()
+
+	identityB := makeTestIdentity()
+	netB := makeTestWebsocketNode(t)
+	netB.SetIdentity(identityB)
+	netB.config.GossipFanout = 1
+	addrA, postListen := netA.Address()
+	require.True(t, postListen)
+	netB.phonebook.ReplacePeerList([]string{addrA}, "default")
+	netB.Start()
+	defer func() { t.Log("stopping B"); netB.Stop(); t.Log("B done") }()
+
+	readyTimeout := time.NewTimer(2 * time.Second)
+	waitReady(t, netA, readyTimeout.C)
+	waitReady(t, netB, readyTimeout.C)
+
+	waitIdentity(t, netA, identityB.SignatureVerifier)
+	waitIdentity(t, netB, identityA.SignatureVerifier)
+}
+
+func TestWebsocketNetworkIdentityDenied(t *testing.T) {
+	identityA := makeTestIdentity()
+	identityB := makeTestIdentity()
+	netA := makeTestWebsocketNode(t)
+	netA.SetIdentity(identityA)
+	netA.identityDenylist = map[crypto.PublicKey]bool{identityB.SignatureVerifier: true}
+	netA.config.GossipFanout = 1
+	netA.Start()
+	defer func() { t.Log("stopping A"); netA.Stop(); t.Log("A done") }()
+	addrA, postListen := netA.Address()
+	require.True(t, postListen)
+
+	// A drops B once it proves its identity.
+	netB := makeTestWebsocketNode(t)
+	netB.SetIdentity(identityB)
+	netB.config.GossipFanout = 1
+	netB.phonebook.ReplacePeerList([]string{addrA}, "default")
+	netB.Start()
+	defer func() { t.Log("stopping B"); netB.Stop(); t.Log("B done") }()
+
+	// C only accepts another identity than the one of A.
+	netC := makeTestWebsocketNode(t)
+	netC.SetIdentity(makeTestIdentity())
+	netC.identityAllowlist = map[crypto.PublicKey]bool{identityB.SignatureVerifier: true}
+	netC.config.GossipFanout = 1
+	netC.phonebook.ReplacePeerList([]string{addrA}, "default")
+	netC.Start()
+	defer func() { t.Log("stopping C"); netC.Stop(); t.Log("C done") }()
+
+	require.Never(t, func() bool {
+		netA.peersLock.RLock()
+		defer netA.peersLock.RUnlock()
+		for _, peer := range netA.peers {
+			if peer.hasIdentity() {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, 0, netC.NumPeers())
+}
@@ -0,0 +1,332 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/util/metrics"
+)
+
+// The penalties of the handler outcomes. A peer whose score reaches PeerBanScore is banned.
+const (
+	// badDataPenalty is the penalty of a peer that sent a message for which it gets disconnected.
+	badDataPenalty = 50
+	// penalizePenalty is the penalty of a peer that sent a message for which a handler returned Penalize.
+	penalizePenalty = 10
+)
+
+// minPeerScore is the score under which a peer that isn't banned is forgotten.
+const minPeerScore = 1
+
+var networkPeerBans = metrics.MakeCounter(metrics.MetricName{Name: "algod_network_peer_bans_total", Description: "number of peers banned for misbehaving"})
+
+// PeerScore is the score of a peer, identified by its host or its identity.
+type PeerScore struct {
+	// Peer is either the host of the peer, or the identity it proved, written as an address.
+	Peer string
+	// Score is the sum of the penalties of the peer, decaying with a PeerScoreHalfLifeSeconds half-life.
+	Score uint64
+	// BannedUntil is the time at which the ban of the peer ends, or the zero time if the peer isn't banned.
+	BannedUntil time.Time
+}
+
+// peerScore is the decaying score of a peer, as of its last update.
+type peerScore struct {
+	score       float64
+	updated     time.Time
+	bannedUntil time.Time
+}
+
+// peerBan is a ban as persisted in the bans file.
+type peerBan struct {
+	Peer  string    `json:"peer"`
+	Until time.Time `json:"until"`
+}
+
+// peerScoreTracker accumulates the penalties of the peers, keyed by host and identity, and bans the ones whose
+// score reaches banScore. The peers whose score is above half of banScore are throttled: they can't connect until
+// their score decays below it.
+type peerScoreTracker struct {
+	halfLife    time.Duration
+	banScore    float64
+	banDuration time.Duration
+
+	// bansFile is the file the bans are persisted to, if any.
+	bansFile string
+
+	mu     deadlock.Mutex
+	scores map[string]*peerScore
+}
+
+func makePeerScoreTracker(halfLife time.Duration, banScore uint64, banDuration time.Duration) *peerScoreTracker {
+	return &peerScoreTracker{
+		halfLife:    halfLife,
+		banScore:    float64(banScore),
+		banDuration: banDuration,
+		scores:      make(map[string]*peerScore),
+	}
+}
+
+// decay updates the score to the given time.
+func (ps *peerScore) decay(halfLife time.Duration, now time.Time) {
+	elapsed := now.Sub(ps.updated)
+	if elapsed > 0 && halfLife > 0 {
+		ps.score *= math.Pow(0.5, float64(elapsed)/float64(halfLife))
+	}
+	ps.updated = now
+}
+
+// penalize adds the penalty to the scores of the keys of a peer, and returns whether it got them banned, along with
+// the error of persisting the bans, if any.
+func (pst *peerScoreTracker) penalize(keys []string, penalty float64, now time.Time) (banned bool, err error) {
+	pst.mu.Lock()
+	defer pst.mu.Unlock()
+	for _, key := range keys {
+		ps := pst.scores[key]
+		if ps == nil {
+			ps = &peerScore{updated: now}
+			pst.scores[key] = ps
+		}
+		ps.decay(pst.halfLife, now)
+		ps.score += penalty
+		if ps.score >= pst.banScore && !now.Before(ps.bannedUntil) {
+			ps.bannedUntil = now.Add(pst.banDuration)
+			banned = true
+		}
+	}
+	if banned {
+		err = pst.saveBans(now)
+	}
+	return
+}
+
+// bannedUntil returns the time at which the ban of a key ends, or the zero time if it isn't banned.
+func (pst *peerScoreTracker) bannedUntil(key string, now time.Time) time.Time {
+	pst.mu.Lock()
+	defer pst.mu.Unlock()
+	ps := pst.scores[key]
+	if ps == nil || !now.Before(ps.bannedUntil) {
+		return time.Time{}
+	}
+	return ps.bannedUntil
+}
+
+// blockedUntil returns the time until which a key can't connect, either because it is banned or throttled, or the
+// zero time if it can.
+func (pst *peerScoreTracker) blockedUntil(key string, now time.Time) time.Time {
+	pst.mu.Lock()
+	defer pst.mu.Unlock()
+	ps := pst.scores[key]
+	if ps == nil {
+		return time.Time{}
+	}
+	if now.Before(ps.bannedUntil) {
+		return ps.bannedUntil
+	}
+	ps.decay(pst.halfLife, now)
+	throttleScore := pst.banScore / 2
+	if ps.score <= throttleScore {
+		return time.Time{}
+	}
+	// the time it takes for the score to decay down to throttleScore.
+	return now.Add(time.Duration(float64(pst.halfLife) * math.Log2(ps.score/throttleScore)))
+}
+
+// prune forgets the peers that aren't banned and whose score decayed away. The caller is assumed to be holding pst.mu.
+func (pst *peerScoreTracker) prune(now time.Time) {
+	for key, ps := range pst.scores {
+		ps.decay(pst.halfLife, now)
+		if !now.Before(ps.bannedUntil) && ps.score < minPeerScore {
+			delete(pst.scores, key)
+		}
+	}
+}
+
+// sweep forgets the peers whose score decayed away, so that the scores don't accumulate the peers that misbehaved once.
+func (pst *peerScoreTracker) sweep(now time.Time) {
+	pst.mu.Lock()
+	defer pst.mu.Unlock()
+	pst.prune(now)
+}
+
+// list returns the current scores, and forgets the peers that aren't banned and whose score decayed away.
+func (pst *peerScoreTracker) list(now time.Time) []PeerScore {
+	pst.mu.Lock()
+	defer pst.mu.Unlock()
+	pst.prune(now)
+	out := make([]PeerScore, 0, len(pst.scores))
+	for key, ps := range pst.scores {
+		score := PeerScore{Peer: key, Score: uint64(math.Round(ps.score))}
+		if now.Before(ps.bannedUntil) {
+			score.BannedUntil = ps.bannedUntil
+		}
+		out = append(out, score)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Peer < out[j].Peer })
+	return out
+}
+
+// loadBans loads the bans that aren't over from the bans file, if it exists, and keeps persisting the bans to it.
+func (pst *peerScoreTracker) loadBans(filename string, now time.Time) error {
+	pst.mu.Lock()
+	defer pst.mu.Unlock()
+	pst.bansFile = filename
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var bans []peerBan
+	err = json.Unmarshal(data, &bans)
+	if err != nil {
+		return err
+	}
+	for _, ban := range bans {
+		if !now.Before(ban.Until) {
+			continue
+		}
+		// a banned peer keeps the score that got it banned.
+		pst.scores[ban.Peer] = &peerScore{score: pst.banScore, updated: now, bannedUntil: ban.Until}
+	}
+	return nil
+}
+
+// saveBans persists the bans that aren't over to the bans file. The caller is assumed to be holding pst.mu.
+func (pst *peerScoreTracker) saveBans(now time.Time) error {
+	if pst.bansFile == "" {
+		return nil
+	}
+	bans := make([]peerBan, 0)
+	for key, ps := range pst.scores {
+		if now.Before(ps.bannedUntil) {
+			bans = append(bans, peerBan{Peer: key, Until: ps.bannedUntil})
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].Peer < bans[j].Peer })
+	data, err := json.MarshalIndent(bans, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmpFile := pst.bansFile + ".tmp"
+	err = ioutil.WriteFile(tmpFile, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, pst.bansFile)
+}
+
+// LoadPeerBans loads the bans of misbehaving peers persisted in the given file, and persists the new bans to it.
+// It has no effect unless EnablePeerScoring is set.
+func (wn *WebsocketNetwork) LoadPeerBans(filename string) error {
+	if wn.peerScores == nil {
+		return nil
+	}
+	return wn.peerScores.loadBans(filename, time.Now())
+}
+
+// PeerScores returns the scores of the peers that misbehaved recently, sorted by peer.
+func (wn *WebsocketNetwork) PeerScores() []PeerScore {
+	if wn.peerScores == nil {
+		return nil
+	}
+	return wn.peerScores.list(time.Now())
+}
+
+// peerScoreKeys returns the keys a peer is scored by: its host, and its identity if it proved one.
+func peerScoreKeys(peer *wsPeer) []string {
+	keys := make([]string, 0, 2)
+	if peer.OriginAddress() != "" {
+		keys = append(keys, peer.OriginAddress())
+	} else if peer.conn != nil {
+		keys = append(keys, justHost(peer.conn.RemoteAddr().String()))
+	}
+	if peer.hasIdentity() {
+		keys = append(keys, identityString(peer.identity))
+	}
+	return keys
+}
+
+// penalizePeer adds the penalty to the score of a peer, and disconnects all its connections if it gets it banned.
+func (wn *WebsocketNetwork) penalizePeer(peer *wsPeer, penalty float64) {
+	if wn.peerScores == nil {
+		return
+	}
+	keys := peerScoreKeys(peer)
+	banned, err := wn.peerScores.penalize(keys, penalty, time.Now())
+	if !banned {
+		return
+	}
+	wn.log.Infof("banning peer %v for %v", keys, wn.peerScores.banDuration)
+	networkPeerBans.Inc(nil)
+	if err != nil {
+		wn.log.Warnf("could not persist the peer bans: %v", err)
+	}
+
+	wn.peersLock.RLock()
+	defer wn.peersLock.RUnlock()
+	for _, other := range wn.peers {
+		if other.peerBanned(wn.peerScores, time.Now()) {
+			wn.wg.Add(1)
+			go wn.disconnectThread(other, disconnectBanned)
+		}
+	}
+}
+
+// peerBanned tells whether the host or the identity of the peer is banned.
+func (wp *wsPeer) peerBanned(pst *peerScoreTracker, now time.Time) bool {
+	for _, key := range peerScoreKeys(wp) {
+		if !pst.bannedUntil(key, now).IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// peerBlocked tells whether a host or an identity can't connect, because it is banned or throttled.
+func (wn *WebsocketNetwork) peerBlocked(key string) bool {
+	return wn.peerScores != nil && !wn.peerScores.blockedUntil(key, time.Now()).IsZero()
+}
+
+// checkBlockedPeer rejects the incoming connections of banned or throttled hosts, asking them to retry once they
+// no longer are.
+func (wn *WebsocketNetwork) checkBlockedPeer(response http.ResponseWriter, remoteHost string) int {
+	if wn.peerScores == nil {
+		return http.StatusOK
+	}
+	now := time.Now()
+	blockedUntil := wn.peerScores.blockedUntil(remoteHost, now)
+	if blockedUntil.IsZero() {
+		return http.StatusOK
+	}
+	networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "banned or throttled"})
+	retryAfter := int(math.Ceil(blockedUntil.Sub(now).Seconds()))
+	response.Header().Set(TooManyRequestsRetryAfterHeader, strconv.Itoa(retryAfter))
+	response.WriteHeader(http.StatusTooManyRequests)
+	return http.StatusTooManyRequests
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestPeerScoreTracker(t *testing.T) {
+	now := time.Now()
+	pst := makePeerScoreTracker(time.Minute, 100, time.Hour)
+
+	banned, err := pst.penalize([]string{"host"}, 40, now)
+	require.NoError(t, err)
+	require.False(t, banned)
+	require.True(t, pst.blockedUntil("host", now).IsZero())
+	require.True(t, pst.blockedUntil("other", now).IsZero())
+
+	// above half of the ban score, the peer is throttled until its score decays below it.
+	banned, err = pst.penalize([]string{"host"}, 40, now)
+	require.NoError(t, err)
+	require.False(t, banned)
+	require.True(t, pst.bannedUntil("host", now).IsZero())
+	blockedUntil := pst.blockedUntil("host", now)
+	require.WithinDuration(t, now.Add(time.Duration(float64(time.Minute)*0.678)), blockedUntil, time.Second)
+	require.True(t, pst.blockedUntil("host", blockedUntil.Add(time.Second)).IsZero())
+
+	// the score decays with the half-life.
+	later := now.Add(time.Minute)
+	require.Equal(t, []PeerScore{{Peer: "host", Score: 40}}, pst.list(later))
+
+	banned, err = pst.penalize([]string{"host", "identity"}, 80, later)
+	require.NoError(t, err)
+	require.True(t, banned)
+	require.Equal(t, later.Add(time.Hour), pst.bannedUntil("host", later))
+	require.Equal(t, later.Add(time.Hour), pst.blockedUntil("host", later))
+	require.True(t, pst.bannedUntil("identity", later).IsZero())
+	require.Equal(t, []PeerScore{{Peer: "host", Score: 120, BannedUntil: later.Add(time.Hour)}, {Peer: "identity", Score: 80}}, pst.list(later))
+
+	// once the ban is over and the scores decayed away, the peers are forgotten.
+	require.Empty(t, pst.list(later.Add(2*time.Hour)))
+	require.Empty(t, pst.scores)
+
+	// the sweep forgets them too, without anyone listing the scores.
+	_, err = pst.penalize([]string{"host"}, 40, now)
+	require.NoError(t, err)
+	pst.sweep(now.Add(time.Minute))
+	require.Len(t, pst.scores, 1)
+	pst.sweep(now.Add(time.Hour))
+	require.Empty(t, pst.scores)
+}
+
+func TestPeerBansPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peerbans")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "peerbans.json")
+
+	now := time.Now()
+	pst := makePeerScoreTracker(time.Minute, 100, time.Hour)
+	require.NoError(t, pst.loadBans(filename, now))
+	_, err = pst.penalize([]string{"short"}, 100, now.Add(-59*time.Minute))
+	require.NoError(t, err)
+	_, err = pst.penalize([]string{"host"}, 100, now)
+	require.NoError(t, err)
+	_, err = pst.penalize([]string{"unbanned"}, 10, now)
+	require.NoError(t, err)
+
+	// only the bans that aren't over are loaded.
+	loaded := makePeerScoreTracker(time.Minute, 100, time.Hour)
+	require.NoError(t, loaded.loadBans(filename, now.Add(time.Minute)))
+	require.Equal(t, now.Add(time.Hour).Unix(), loaded.bannedUntil("host", now.Add(time.Minute)).Unix())
+	require.True(t, loaded.bannedUntil("short", now.Add(time.Minute)).IsZero())
+	require.True(t, loaded.blockedUntil("unbanned", now.Add(time.Minute)).IsZero())
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte("garbage"), 0600))
+	require.Error(t, makePeerScoreTracker(time.Minute, 100, time.Hour).loadBans(filename, now))
+}
+
+type policyHandler struct {
+	action ForwardingPolicy
+}
+
+func (h *policyHandler) Handle(message IncomingMessage) OutgoingMessage {
+	return OutgoingMessage{Action: h.action}
+}
+
+func TestWebsocketNetworkPeerBan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peerbans")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	conf := defaultConfig
+	conf.GossipFanout = 1
+	conf.EnablePeerScoring = true
+	conf.PeerBanScore = 50
+	netA := makeTestWebsocketNodeWithConfig(t, conf)
+	require.NoError(t, netA.LoadPeerBans(filepath.Join(dir, "peerbans.json")))
+	netA.RegisterHandlers([]TaggedMessageHandler{
+		{Tag: protocol.ProposalPayloadTag, MessageHandler: &policyHandler{action: Penalize}},
+		{Tag: protocol.TxnTag, MessageHandler: &policyHandler{action: Disconnect}},
+	})
+	netA.Start()
+	defer func() { t.Log("stopping A"); netA.Stop(); t.Log("A done") }()
+
+	netB := makeTestWebsocketNode(t)
+	netB.config.GossipFanout = 1
+	addrA, postListen := netA.Address()
+	require.True(t, postListen)
+	netB.phonebook.ReplacePeerList([]string{addrA}, "default")
+	netB.Start()
+	defer func() { t.Log("stopping B"); netB.Stop(); t.Log("B done") }()
+
+	readyTimeout := time.NewTimer(2 * time.Second)
+	waitReady(t, netA, readyTimeout.C)
+	waitReady(t, netB, readyTimeout.C)
+
+	// penalized messages add up without dropping the peer.
+	for i := 0; i < 3; i++ {
+		netB.Broadcast(context.Background(), protocol.ProposalPayloadTag, []byte("proposal"), true, nil)
+	}
+	require.Eventually(t, func() bool {
+		scores := netA.PeerScores()
+		return len(scores) == 1 && scores[0].Score == 3*penalizePenalty
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, netA.NumPeers())
+
+	// a message the peer gets disconnected for gets it banned, and it can't reconnect.
+	netB.Broadcast(context.Background(), protocol.TxnTag, []byte("txn"), true, nil)
+	require.Eventually(t, func() bool {
+		scores := netA.PeerScores()
+		return len(scores) == 1 && !scores[0].BannedUntil.IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Never(t, func() bool { return netA.NumPeers() > 0 }, time.Second, 10*time.Millisecond)
+
+	loaded := makePeerScoreTracker(time.Minute, 50, time.Hour)
+	require.NoError(t, loaded.loadBans(filepath.Join(dir, "peerbans.json"), time.Now()))
+	require.Equal(t, netA.PeerScores()[0].BannedUntil.Unix(), loaded.bannedUntil(netA.PeerScores()[0].Peer, time.Now()).Unix())
+}
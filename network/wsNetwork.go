@@ -151,6 +151,7 @@ type GossipNode interface {
 	Broadcast(ctx context.Context, tag protocol.Tag, data []byte, wait bool, except Peer) error
 	Relay(ctx context.Context, tag protocol.Tag, data []byte, wait bool, except Peer) error
 	Disconnect(badnode Peer)
+	Penalize(badnode Peer)
 	DisconnectPeers()
 	Ready() chan struct{}
 
@@ -235,6 +236,9 @@ const (
 
 	// Respond - reply to the sender
 	Respond
+
+	// Penalize - add a penalty to the score of the peer that sent this message, without disconnecting it
+	Penalize
 )
 
 // MessageHandler takes a IncomingMessage (e.g., vote, transaction), processes it, and returns what (if anything)
@@ -325,6 +329,24 @@ type WebsocketNetwork struct {
 	prioTracker      *prioTracker
 	prioResponseChan chan *wsPeer
 
+	// identity is the key the node proves its identity to its peers with, if the peer identity handshake is enabled.
+	identity        *crypto.SignatureSecrets
+	identityTracker *identityTracker
+
+	// identityAllowlist and identityDenylist hold the identities of PeerIdentityAllowlist and PeerIdentityDenylist.
+	identityAllowlist map[crypto.PublicKey]bool
+	identityDenylist  map[crypto.PublicKey]bool
+
+	// peerScores accumulates the penalties of the misbehaving peers, if EnablePeerScoring is set.
+	peerScores *peerScoreTracker
+
+	// msgRateLimits holds the rate limits of IncomingMessageRateLimits, by tag.
+	msgRateLimits map[protocol.Tag]*msgRateLimit
+
+	// messagesOfInterest holds the tags of the last message-of-interest message the node sent, as a
+	// map[protocol.Tag]bool, so that the rate limits only apply to them.
+	messagesOfInterest atomic.Value
+
 	// outgoingMessagesBufferSize is the size used for outgoing messages.
 	outgoingMessagesBufferSize int
 
@@ -395,6 +417,9 @@ func (wn *WebsocketNetwork) PublicAddress() string {
 // if wait is true then the call blocks until the packet has actually been sent to all neighbors.
 // TODO: add `priority` argument so that we don't have to guess it based on tag
 func (wn *WebsocketNetwork) Broadcast(ctx context.Context, tag protocol.Tag, data []byte, wait bool, except Peer) error {
+	if tag == protocol.MsgOfInterestTag {
+		wn.setMessagesOfInterest(data)
+	}
 	request := broadcastRequest{tag: tag, data: data, enqueueTime: time.Now()}
 	if except != nil {
 		request.except = except.(*wsPeer)
@@ -456,12 +481,23 @@ func (wn *WebsocketNetwork) Disconnect(node Peer) {
 	wn.disconnect(node, disconnectBadData)
 }
 
+// Penalize adds a penalty to the score of a peer that sent an invalid message, without disconnecting it. It's the
+// counterpart of the Penalize action for the handlers that find the message invalid after they returned.
+func (wn *WebsocketNetwork) Penalize(node Peer) {
+	if peer, ok := node.(*wsPeer); ok {
+		wn.penalizePeer(peer, penalizePenalty)
+	}
+}
+
 // Disconnect from a peer, probably due to protocol errors.
 func (wn *WebsocketNetwork) disconnect(badnode Peer, reason disconnectReason) {
 	if badnode == nil {
 		return
 	}
 	peer := badnode.(*wsPeer)
+	if reason == disconnectBadData {
+		wn.penalizePeer(peer, badDataPenalty)
+	}
 	peer.CloseAndWait()
 	wn.removePeer(peer, reason)
 }
@@ -592,6 +628,12 @@ func (wn *WebsocketNetwork) setup() {
 	wn.tryConnectAddrs = make(map[string]int64)
 	wn.eventualReadyDelay = time.Minute
 	wn.prioTracker = newPrioTracker(wn)
+	wn.identityAllowlist = wn.parseIdentityList("PeerIdentityAllowlist", wn.config.PeerIdentityAllowlist)
+	wn.identityDenylist = wn.parseIdentityList("PeerIdentityDenylist", wn.config.PeerIdentityDenylist)
+	if wn.config.EnablePeerScoring {
+		wn.peerScores = makePeerScoreTracker(time.Duration(wn.config.PeerScoreHalfLifeSeconds)*time.Second, wn.config.PeerBanScore, time.Duration(wn.config.PeerBanDurationSeconds)*time.Second)
+	}
+	wn.msgRateLimits = wn.makeMsgRateLimits()
 	if wn.slowWritingPeerMonitorInterval == 0 {
 		wn.slowWritingPeerMonitorInterval = slowWritingPeerMonitorInterval
 	}
@@ -711,6 +753,9 @@ func (wn *WebsocketNetwork) Start() {
 	if wn.prioScheme != nil {
 		wn.RegisterHandlers(prioHandlers)
 	}
+	if wn.identity != nil {
+		wn.RegisterHandlers(identityHandlers)
+	}
 	if wn.listener != nil {
 		wn.wg.Add(1)
 		go wn.httpdThread()
@@ -789,7 +834,7 @@ func (wn *WebsocketNetwork) RegisterHandlers(dispatch []TaggedMessageHandler) {
 // ClearHandlers deregisters all the existing message handlers.
 func (wn *WebsocketNetwork) ClearHandlers() {
 	// exclude the internal handlers. These would get cleared out when Stop is called.
-	wn.handlers.ClearHandlers([]Tag{protocol.PingTag, protocol.PingReplyTag, protocol.NetPrioResponseTag})
+	wn.handlers.ClearHandlers([]Tag{protocol.PingTag, protocol.PingReplyTag, protocol.NetPrioResponseTag, protocol.NetIdentityVerificationTag})
 }
 
 func (wn *WebsocketNetwork) setHeaders(header http.Header) {
@@ -956,6 +1001,11 @@ func (wn *WebsocketNetwork) ServeHTTP(response http.ResponseWriter, request *htt
 		return
 	}
 
+	if wn.checkBlockedPeer(response, trackedRequest.remoteHost) != http.StatusOK {
+		// we've already logged and written all response(s).
+		return
+	}
+
 	matchingVersion, otherVersion := wn.checkProtocolVersionMatch(request.Header)
 	if matchingVersion == "" {
 		wn.log.Infof("new peer %s version mismatch, mine=%v theirs=%s, headers %#v", request.RemoteAddr, SupportedProtocolVersions, otherVersion, request.Header)
@@ -986,6 +1036,26 @@ func (wn *WebsocketNetwork) ServeHTTP(response http.ResponseWriter, request *htt
 		challenge = wn.prioScheme.NewPrioChallenge()
 		responseHeader.Set(PriorityChallengeHeader, challenge)
 	}
+	var identityChallenge, requestIdentityChallenge identityChallenge
+	if wn.identity != nil {
+		identityChallenge = newIdentityChallenge()
+		if otherChallenge, err := parseIdentityChallenge(request.Header.Get(IdentityChallengeHeader)); err == nil {
+			wn.setIdentityHeaders(responseHeader, otherChallenge, identityChallenge)
+			requestIdentityChallenge = otherChallenge
+		}
+		responseHeader.Set(IdentityChallengeHeader, identityChallenge.String())
+	}
+	var compression string
+	if wn.config.EnableMessageCompression {
+		compression = checkCompressionMatch(request.Header)
+		if compression != "" {
+			responseHeader.Set(CompressionHeader, compression)
+		}
+	}
+	txnAnnouncements := wn.config.EnableTxnAnnouncements && request.Header.Get(TxnAnnouncementsHeader) == "1"
+	if txnAnnouncements {
+		responseHeader.Set(TxnAnnouncementsHeader, "1")
+	}
 	conn, err := wn.upgrader.Upgrade(response, request, responseHeader)
 	if err != nil {
 		wn.log.Info("ws upgrade fail ", err)
@@ -999,14 +1069,18 @@ func (wn *WebsocketNetwork) ServeHTTP(response http.ResponseWriter, request *htt
 	}
 
 	peer := &wsPeer{
-		wsPeerCore:        makePeerCore(wn, trackedRequest.otherPublicAddr, wn.GetRoundTripper(), trackedRequest.remoteHost),
-		conn:              conn,
-		outgoing:          false,
-		InstanceName:      trackedRequest.otherInstanceName,
-		incomingMsgFilter: wn.incomingMsgFilter,
-		prioChallenge:     challenge,
-		createTime:        trackedRequest.created,
-		version:           matchingVersion,
+		wsPeerCore:               makePeerCore(wn, trackedRequest.otherPublicAddr, wn.GetRoundTripper(), trackedRequest.remoteHost),
+		conn:                     conn,
+		outgoing:                 false,
+		InstanceName:             trackedRequest.otherInstanceName,
+		incomingMsgFilter:        wn.incomingMsgFilter,
+		prioChallenge:            challenge,
+		identityChallenge:        identityChallenge,
+		requestIdentityChallenge: requestIdentityChallenge,
+		createTime:               trackedRequest.created,
+		version:                  matchingVersion,
+		compression:              compression,
+		txnAnnouncements:         txnAnnouncements,
 	}
 	peer.TelemetryGUID = trackedRequest.otherTelemetryGUID
 	peer.init(wn.config, wn.outgoingMessagesBufferSize)
@@ -1042,6 +1116,9 @@ func (wn *WebsocketNetwork) messageHandlerThread() {
 					wn.log.Warnf("could not send on msg.processing")
 				}
 			}
+			if wn.unverifiedIdentityMessage(msg) {
+				continue
+			}
 			if wn.config.EnableOutgoingNetworkMessageFiltering && len(msg.Data) >= messageFilterSize {
 				wn.sendFilterMessage(msg)
 			}
@@ -1063,6 +1140,8 @@ func (wn *WebsocketNetwork) messageHandlerThread() {
 				wn.Broadcast(wn.ctx, msg.Tag, msg.Data, false, msg.Sender)
 			case Respond:
 				msg.Sender.(*wsPeer).Respond(wn.ctx, msg, outmsg.Topics)
+			case Penalize:
+				wn.Penalize(msg.Sender)
 			default:
 			}
 		case <-inactivityCheckTicker.C:
@@ -1086,8 +1165,14 @@ func (wn *WebsocketNetwork) checkPeersConnectivity() {
 			wn.wg.Add(1)
 			go wn.disconnectThread(peer, disconnectIdleConn)
 			networkIdlePeerDrops.Inc(nil)
+		} else if wn.identityRequired() && !peer.outgoing && !peer.hasIdentity() && currentTime.Sub(peer.createTime) > peerIdentityVerificationTimeout {
+			wn.wg.Add(1)
+			go wn.disconnectThread(peer, disconnectIdentityNotVerified)
 		}
 	}
+	if wn.peerScores != nil {
+		wn.peerScores.sweep(currentTime)
+	}
 }
 
 // checkSlowWritingPeers tests each of the peer's current message timestamp.
@@ -1183,17 +1268,25 @@ func (wn *WebsocketNetwork) innerBroadcast(request broadcastRequest, prio bool,
 	*ppeers = wn.peerSnapshot(*ppeers)
 	peers := *ppeers
 
+	// the message is compressed once, the first time it's sent to a peer that negotiated compression.
+	var compressed []byte
+	compressionDone := false
+
 	// first send to all the easy outbound peers who don't block, get them started.
 	sentMessageCount := 0
 	for pi, peer := range peers {
 		if wn.config.BroadcastConnectionsLimit >= 0 && sentMessageCount >= wn.config.BroadcastConnectionsLimit {
 			break
 		}
-		if peer == request.except {
+		if peer == request.except || !peer.wantsBroadcast(request.tag) {
 			peers[pi] = nil
 			continue
 		}
-		ok := peer.writeNonBlock(mbytes, prio, digest, request.enqueueTime)
+		if peer.compression != "" && !compressionDone {
+			compressed = compressMessage(request.tag, request.data)
+			compressionDone = true
+		}
+		ok := peer.writeNonBlockCompressed(mbytes, compressed, prio, digest, request.enqueueTime)
 		if ok {
 			peers[pi] = nil
 			sentMessageCount++
@@ -1629,6 +1722,16 @@ var SupportedProtocolVersions = []string{"2.1", "1"}
 // ProtocolVersion is the current version attached to the ProtocolVersionHeader header
 const ProtocolVersion = "1"
 
+// CompressionAcceptHeader HTTP header by which a client advertises the message compression schemes it supports.
+const CompressionAcceptHeader = "X-Algorand-Accept-Compression"
+
+// CompressionHeader HTTP header by which a server reports the message compression scheme it chose among the ones of the client.
+const CompressionHeader = "X-Algorand-Compression"
+
+// TxnAnnouncementsHeader HTTP header by which a client offers, and the server accepts, to exchange transaction
+// announcements ( TxnAnnouncementTag ) rather than full transactions ( TxnTag ).
+const TxnAnnouncementsHeader = "X-Algorand-TxnAnnouncements"
+
 // TelemetryIDHeader HTTP header for telemetry-id for logging
 const TelemetryIDHeader = "X-Algorand-TelId"
 
@@ -1647,6 +1750,15 @@ const InstanceNameHeader = "X-Algorand-InstanceName"
 // PriorityChallengeHeader HTTP header informs a client about the challenge it should sign to increase network priority.
 const PriorityChallengeHeader = "X-Algorand-PriorityChallenge"
 
+// IdentityChallengeHeader HTTP header informs the other node about the challenge it should sign to prove its identity.
+const IdentityChallengeHeader = "X-Algorand-IdentityChallenge"
+
+// IdentityHeader HTTP header by which a node reports the identity it proves in the IdentitySignatureHeader header.
+const IdentityHeader = "X-Algorand-Identity"
+
+// IdentitySignatureHeader HTTP header by which a node reports its signature of the challenge of the request.
+const IdentitySignatureHeader = "X-Algorand-IdentitySignature"
+
 // TooManyRequestsRetryAfterHeader HTTP header let the client know when to make the next connection attempt
 const TooManyRequestsRetryAfterHeader = "Retry-After"
 
@@ -1704,6 +1816,9 @@ func (wn *WebsocketNetwork) tryConnectReserveAddr(addr string) (gossipAddr strin
 	if err != nil {
 		return "", false
 	}
+	if parsedURL, err := ParseHostOrURL(addr); err == nil && wn.peerBlocked(parsedURL.Hostname()) {
+		return "", false
+	}
 	_, exists = wn.tryConnectAddrs[gossipAddr]
 	if exists {
 		return "", false
@@ -1758,6 +1873,19 @@ func (wn *WebsocketNetwork) tryConnect(addr, gossipAddr string) {
 	SetUserAgentHeader(requestHeader)
 	myInstanceName := wn.log.GetInstanceName()
 	requestHeader.Set(InstanceNameHeader, myInstanceName)
+	var identityChallenge identityChallenge
+	if wn.identity != nil {
+		identityChallenge = newIdentityChallenge()
+		requestHeader.Set(IdentityChallengeHeader, identityChallenge.String())
+	}
+	if wn.config.EnableMessageCompression {
+		for _, supportedScheme := range SupportedCompressionSchemes {
+			requestHeader.Add(CompressionAcceptHeader, supportedScheme)
+		}
+	}
+	if wn.config.EnableTxnAnnouncements {
+		requestHeader.Set(TxnAnnouncementsHeader, "1")
+	}
 	var websocketDialer = websocket.Dialer{
 		Proxy:             http.ProxyFromEnvironment,
 		HandshakeTimeout:  45 * time.Second,
@@ -1809,6 +1937,31 @@ func (wn *WebsocketNetwork) tryConnect(addr, gossipAddr string) {
 		return
 	}
 
+	if wn.peerBlocked(justHost(conn.RemoteAddr().String())) {
+		wn.log.Infof("ws connect(%s) fail - peer banned or throttled", gossipAddr)
+		networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "banned or throttled"})
+		conn.Close()
+		return
+	}
+
+	var identity crypto.PublicKey
+	identityVerified := false
+	if wn.identity != nil {
+		identity, identityVerified, err = wn.verifyIdentityHeaders(response.Header, identityChallenge)
+		if err != nil {
+			wn.log.Warnf("ws connect(%s) fail - %v", gossipAddr, err)
+			networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "identity not verified"})
+			conn.Close()
+			return
+		}
+	}
+
+	var compression string
+	if wn.config.EnableMessageCompression {
+		compression = checkCompressionResponse(response.Header)
+	}
+	txnAnnouncements := wn.config.EnableTxnAnnouncements && response.Header.Get(TxnAnnouncementsHeader) == "1"
+
 	throttledConnection := false
 	if atomic.AddInt32(&wn.throttledOutgoingConnections, int32(-1)) >= 0 {
 		throttledConnection = true
@@ -1825,8 +1978,14 @@ func (wn *WebsocketNetwork) tryConnect(addr, gossipAddr string) {
 		connMonitor:                 wn.connPerfMonitor,
 		throttledOutgoingConnection: throttledConnection,
 		version:                     matchingVersion,
+		compression:                 compression,
+		txnAnnouncements:            txnAnnouncements,
 	}
 	peer.TelemetryGUID, peer.InstanceName, _ = getCommonHeaders(response.Header)
+	if identityVerified && !wn.claimIdentity(peer, identity) {
+		conn.Close()
+		return
+	}
 	peer.init(wn.config, wn.outgoingMessagesBufferSize)
 	wn.addPeer(peer)
 	localAddr, _ := wn.Address()
@@ -1843,6 +2002,15 @@ func (wn *WebsocketNetwork) tryConnect(addr, gossipAddr string) {
 	peers.Set(float64(wn.NumPeers()), nil)
 	outgoingPeers.Set(float64(wn.numOutgoingPeers()), nil)
 
+	if wn.identity != nil {
+		if otherChallenge, err := parseIdentityChallenge(response.Header.Get(IdentityChallengeHeader)); err == nil {
+			sent := peer.writeNonBlock(wn.makeIdentityVerification(otherChallenge, identityChallenge, identity), true, crypto.Digest{}, time.Now())
+			if !sent {
+				wn.log.With("remote", addr).With("local", localAddr).Warnf("could not send identity verification to %v", addr)
+			}
+		}
+	}
+
 	if wn.prioScheme != nil {
 		challenge := response.Header.Get(PriorityChallengeHeader)
 		if challenge != "" {
@@ -1942,6 +2110,9 @@ func (wn *WebsocketNetwork) removePeer(peer *wsPeer, reason disconnectReason) {
 	if peer.peerIndex < len(wn.peers) && wn.peers[peer.peerIndex] == peer {
 		heap.Remove(peersHeap{wn}, peer.peerIndex)
 		wn.prioTracker.removePeer(peer)
+		if wn.identityTracker != nil {
+			wn.identityTracker.removePeer(peer)
+		}
 		if peer.throttledOutgoingConnection {
 			atomic.AddInt32(&wn.throttledOutgoingConnections, int32(1))
 		}
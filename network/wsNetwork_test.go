@@ -1541,3 +1541,60 @@ func TestWebsocketNetworkMessageOfInterest(t *testing.T) {
 		}
 	}
 }
+
+func testWebsocketNetworkTxnAnnouncements(t *testing.T, enableA, enableB bool, negotiated bool) {
+	netA := makeTestWebsocketNode(t)
+	netA.config.GossipFanout = 1
+	netA.config.EnableTxnAnnouncements = enableA
+	netA.Start()
+	defer func() { t.Log("stopping A"); netA.Stop(); t.Log("A done") }()
+	netB := makeTestWebsocketNode(t)
+	netB.config.GossipFanout = 1
+	netB.config.EnableTxnAnnouncements = enableB
+	addrA, postListen := netA.Address()
+	require.True(t, postListen)
+	netB.phonebook.ReplacePeerList([]string{addrA}, "default")
+	netB.Start()
+	defer func() { t.Log("stopping B"); netB.Stop(); t.Log("B done") }()
+
+	txnHandler := &payloadHandler{payloads: make(chan []byte, 10)}
+	announcementHandler := &payloadHandler{payloads: make(chan []byte, 10)}
+	netB.RegisterHandlers([]TaggedMessageHandler{
+		{Tag: protocol.TxnTag, MessageHandler: txnHandler},
+		{Tag: protocol.TxnAnnouncementTag, MessageHandler: announcementHandler},
+	})
+
+	readyTimeout := time.NewTimer(2 * time.Second)
+	waitReady(t, netA, readyTimeout.C)
+	waitReady(t, netB, readyTimeout.C)
+
+	for _, wn := range []*WebsocketNetwork{netA, netB} {
+		wn.peersLock.RLock()
+		require.Equal(t, 1, len(wn.peers))
+		require.Equal(t, negotiated, wn.peers[0].txnAnnouncements)
+		wn.peersLock.RUnlock()
+	}
+
+	// the peer gets either the announcement or the full transaction, depending on what was negotiated.
+	netA.Broadcast(context.Background(), protocol.TxnAnnouncementTag, []byte("announcement"), true, nil)
+	netA.Broadcast(context.Background(), protocol.TxnTag, []byte("transaction"), true, nil)
+	received, skipped := txnHandler, announcementHandler
+	if negotiated {
+		received, skipped = announcementHandler, txnHandler
+	}
+	select {
+	case <-received.payloads:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "timeout waiting for the message")
+	}
+	require.Never(t, func() bool { return len(skipped.payloads) > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestWebsocketNetworkTxnAnnouncements(t *testing.T) {
+	testWebsocketNetworkTxnAnnouncements(t, true, true, true)
+}
+
+func TestWebsocketNetworkTxnAnnouncementsFallback(t *testing.T) {
+	testWebsocketNetworkTxnAnnouncements(t, true, false, false)
+	testWebsocketNetworkTxnAnnouncements(t, false, true, false)
+}
@@ -64,20 +64,23 @@ var outgoingNetworkMessageFilteredOutBytesTotal = metrics.MakeCounter(metrics.Ou
 // defaultSendMessageTags is the default list of messages which a peer would
 // allow to be sent without receiving any explicit request.
 var defaultSendMessageTags = map[protocol.Tag]bool{
-	protocol.AgreementVoteTag:   true,
-	protocol.MsgDigestSkipTag:   true,
-	protocol.NetPrioResponseTag: true,
-	protocol.PingTag:            true,
-	protocol.PingReplyTag:       true,
-	protocol.ProposalPayloadTag: true,
-	protocol.TopicMsgRespTag:    true,
-	protocol.MsgOfInterestTag:   true,
-	protocol.TxnTag:             true,
-	protocol.UniCatchupReqTag:   true,
-	protocol.UniEnsBlockReqTag:  true,
-	protocol.UniEnsBlockResTag:  true,
-	protocol.UniCatchupResTag:   true,
-	protocol.VoteBundleTag:      true,
+	protocol.AgreementVoteTag:           true,
+	protocol.MsgDigestSkipTag:           true,
+	protocol.NetIdentityVerificationTag: true,
+	protocol.NetPrioResponseTag:         true,
+	protocol.PingTag:                    true,
+	protocol.PingReplyTag:               true,
+	protocol.ProposalPayloadTag:         true,
+	protocol.TopicMsgRespTag:            true,
+	protocol.MsgOfInterestTag:           true,
+	protocol.TxnTag:                     true,
+	protocol.TxnAnnouncementTag:         true,
+	protocol.TxnRequestTag:              true,
+	protocol.UniCatchupReqTag:           true,
+	protocol.UniEnsBlockReqTag:          true,
+	protocol.UniEnsBlockResTag:          true,
+	protocol.UniCatchupResTag:           true,
+	protocol.VoteBundleTag:              true,
 }
 
 // interface allows substituting debug implementation for *websocket.Conn
@@ -92,6 +95,7 @@ type wsPeerWebsocketConn interface {
 
 type sendMessage struct {
 	data         []byte
+	compressed   []byte                // the compressed form of data, sent instead of it if the peer negotiated compression; nil if data isn't to be compressed.
 	enqueued     time.Time             // the time at which the message was first generated
 	peerEnqueued time.Time             // the time at which the peer was attempting to enqueue the message
 	msgTags      map[protocol.Tag]bool // when msgTags is speficied ( i.e. non-nil ), the send goroutine is to replace the message tag filter with this one. No data would be accompanied to this message.
@@ -115,6 +119,10 @@ const disconnectIdleConn disconnectReason = "IdleConnection"
 const disconnectSlowConn disconnectReason = "SlowConnection"
 const disconnectLeastPerformingPeer disconnectReason = "LeastPerformingPeer"
 const disconnectCliqueResolve disconnectReason = "CliqueResolving"
+const disconnectDuplicateIdentity disconnectReason = "DuplicateIdentity"
+const disconnectIdentityNotPermitted disconnectReason = "IdentityNotPermitted"
+const disconnectIdentityNotVerified disconnectReason = "IdentityNotVerified"
+const disconnectBanned disconnectReason = "Banned"
 
 // Response is the structure holding the response from the server
 type Response struct {
@@ -174,12 +182,30 @@ type wsPeer struct {
 	prioAddress basics.Address
 	prioWeight  uint64
 
+	// Challenge sent to the peer on an incoming connection, which it signs to prove its identity
+	identityChallenge identityChallenge
+	// requestIdentityChallenge is the challenge the peer sent in the request of an incoming
+	// connection, which the node signed to prove its identity
+	requestIdentityChallenge identityChallenge
+
+	// identity is the identity the peer proved, once identityVerified is set.
+	identity         crypto.PublicKey
+	identityVerified uint32
+
 	// createTime is the time at which the connection was established with the peer.
 	createTime time.Time
 
 	// peer version ( this is one of the version supported by the current node and listed in SupportedProtocolVersions )
 	version string
 
+	// compression is the message compression scheme negotiated with the peer ( one of SupportedCompressionSchemes ),
+	// or empty if the messages are exchanged as is.
+	compression string
+
+	// txnAnnouncements is set if both ends agreed to relay transactions as announcements; such a peer receives the
+	// TxnAnnouncementTag broadcasts instead of the TxnTag ones.
+	txnAnnouncements bool
+
 	// Nonce used to uniquely identify requests
 	requestNonce uint64
 
@@ -261,7 +287,8 @@ func (wp *wsPeer) Version() string {
 	return wp.version
 }
 
-// 	Unicast sends the given bytes to this specific peer. Does not wait for message to be sent.
+//	Unicast sends the given bytes to this specific peer. Does not wait for message to be sent.
+//
 // (Implements UnicastPeer)
 func (wp *wsPeer) Unicast(ctx context.Context, msg []byte, tag protocol.Tag) error {
 	var err error
@@ -275,7 +302,11 @@ func (wp *wsPeer) Unicast(ctx context.Context, msg []byte, tag protocol.Tag) err
 		digest = crypto.Hash(mbytes)
 	}
 
-	ok := wp.writeNonBlock(mbytes, false, digest, time.Now())
+	var compressed []byte
+	if wp.compression != "" {
+		compressed = compressMessage(tag, msg)
+	}
+	ok := wp.writeNonBlockCompressed(mbytes, compressed, false, digest, time.Now())
 	if !ok {
 		networkBroadcastsDropped.Inc(nil)
 		err = fmt.Errorf("wsPeer failed to unicast: %v", wp.GetAddress())
@@ -341,6 +372,11 @@ func (wp *wsPeer) init(config config.Local, sendBufferLength int) {
 	go wp.writeLoop()
 }
 
+// hasIdentity tells whether the peer proved its identity.
+func (wp *wsPeer) hasIdentity() bool {
+	return atomic.LoadUint32(&wp.identityVerified) != 0
+}
+
 // returns the originating address of an incoming connection. For outgoing connection this function returns an empty string.
 func (wp *wsPeer) OriginAddress() string {
 	return wp.originAddress
@@ -355,6 +391,18 @@ func (wp *wsPeer) reportReadErr(err error) {
 	}
 }
 
+// wantsBroadcast returns false for the broadcasts that don't go to this peer: transactions are relayed to the peers
+// that negotiated announcements as TxnAnnouncementTag messages only, and to the other peers as TxnTag messages only.
+func (wp *wsPeer) wantsBroadcast(t protocol.Tag) bool {
+	switch t {
+	case protocol.TxnTag:
+		return !wp.txnAnnouncements
+	case protocol.TxnAnnouncementTag:
+		return wp.txnAnnouncements
+	}
+	return true
+}
+
 func dedupSafeTag(t protocol.Tag) bool {
 	// Votes and Transactions are the only thing we're sure it's safe to de-dup on receipt.
 	return t == protocol.AgreementVoteTag || t == protocol.TxnTag
@@ -364,6 +412,8 @@ func (wp *wsPeer) readLoop() {
 	defer wp.readLoopCleanup()
 	wp.conn.SetReadLimit(maxMessageLength)
 	slurper := LimitedReaderSlurper{Limit: maxMessageLength}
+	var decompressor messageDecompressor
+	rateLimiter := makeMsgRateLimiter(wp.net.msgRateLimits)
 	for {
 		msg := IncomingMessage{}
 		mtype, reader, err := wp.conn.NextReader()
@@ -392,6 +442,10 @@ func (wp *wsPeer) readLoop() {
 			return
 		}
 		msg.Tag = Tag(string(tag[:]))
+		// drop the messages over the rate limit of their tag before reading them.
+		if !rateLimiter.allow(msg.Tag, wp.net.getMessagesOfInterest(), time.Now()) {
+			continue
+		}
 		slurper.Reset()
 		err = slurper.Read(reader)
 		if err != nil {
@@ -405,6 +459,25 @@ func (wp *wsPeer) readLoop() {
 		atomic.StoreInt64(&wp.lastPacketTime, msg.Received)
 		networkReceivedBytesTotal.AddUint64(uint64(len(msg.Data)+2), nil)
 		networkMessageReceivedTotal.AddUint64(1, nil)
+		if msg.Tag == protocol.CompressedMsgTag {
+			if wp.compression == "" {
+				wp.net.log.Warnf("peer sent a compressed message without negotiating compression")
+				networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "protocol"})
+				return
+			}
+			// drop the messages over the rate limit of their inner tag before inflating them.
+			if len(msg.Data) >= len(protocol.CompressedMsgTag) && !rateLimiter.allow(protocol.Tag(msg.Data[:len(protocol.CompressedMsgTag)]), wp.net.getMessagesOfInterest(), time.Now()) {
+				continue
+			}
+			compressedLen := len(msg.Data) + 2
+			msg.Tag, msg.Data, err = decompressor.decompress(msg.Data)
+			if err != nil {
+				wp.reportReadErr(err)
+				return
+			}
+			networkCompressionReceivedBytesTotal.AddUint64(uint64(compressedLen), compressionLabels(msg.Tag))
+			networkCompressionReceivedRawBytesTotal.AddUint64(uint64(len(msg.Data)+2), compressionLabels(msg.Tag))
+		}
 		msg.Sender = wp
 
 		// for outgoing connections, we want to notify the connection monitor that we've received
@@ -561,9 +634,13 @@ func (wp *wsPeer) writeLoopSend(msg sendMessage) (exit bool) {
 		networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "stale message"})
 		return true
 	}
+	data := msg.data
+	if msg.compressed != nil && wp.compression != "" {
+		data = msg.compressed
+	}
 	atomic.StoreInt64(&wp.intermittentOutgoingMessageEnqueueTime, msg.enqueued.UnixNano())
 	defer atomic.StoreInt64(&wp.intermittentOutgoingMessageEnqueueTime, 0)
-	err := wp.conn.WriteMessage(websocket.BinaryMessage, msg.data)
+	err := wp.conn.WriteMessage(websocket.BinaryMessage, data)
 	if err != nil {
 		if atomic.LoadInt32(&wp.didInnerClose) == 0 {
 			wp.net.log.Warn("peer write error ", err)
@@ -572,8 +649,12 @@ func (wp *wsPeer) writeLoopSend(msg sendMessage) (exit bool) {
 		return true
 	}
 	atomic.StoreInt64(&wp.lastPacketTime, time.Now().UnixNano())
-	networkSentBytesTotal.AddUint64(uint64(len(msg.data)), nil)
+	networkSentBytesTotal.AddUint64(uint64(len(data)), nil)
 	networkMessageSentTotal.AddUint64(1, nil)
+	if len(data) != len(msg.data) {
+		networkCompressionSentBytesTotal.AddUint64(uint64(len(data)), compressionLabels(tag))
+		networkCompressionSentRawBytesTotal.AddUint64(uint64(len(msg.data)), compressionLabels(tag))
+	}
 	networkMessageQueueMicrosTotal.AddUint64(uint64(time.Now().Sub(msg.peerEnqueued).Nanoseconds()/1000), nil)
 	return false
 }
@@ -612,6 +693,13 @@ func (wp *wsPeer) writeLoopCleanup() {
 
 // return true if enqueued/sent
 func (wp *wsPeer) writeNonBlock(data []byte, highPrio bool, digest crypto.Digest, msgEnqueueTime time.Time) bool {
+	return wp.writeNonBlockCompressed(data, nil, highPrio, digest, msgEnqueueTime)
+}
+
+// writeNonBlockCompressed is writeNonBlock for a message that may be sent in the compressed form returned by
+// compressMessage, if the peer negotiated compression.
+// return true if enqueued/sent
+func (wp *wsPeer) writeNonBlockCompressed(data []byte, compressed []byte, highPrio bool, digest crypto.Digest, msgEnqueueTime time.Time) bool {
 	if wp.outgoingMsgFilter != nil && len(data) > messageFilterSize && wp.outgoingMsgFilter.CheckDigest(digest, false, false) {
 		//wp.net.log.Debugf("msg drop as outbound dup %s(%d) %v", string(data[:2]), len(data)-2, digest)
 		// peer has notified us it doesn't need this message
@@ -627,7 +715,7 @@ func (wp *wsPeer) writeNonBlock(data []byte, highPrio bool, digest crypto.Digest
 		outchan = wp.sendBufferBulk
 	}
 	select {
-	case outchan <- sendMessage{data: data, enqueued: msgEnqueueTime, peerEnqueued: time.Now()}:
+	case outchan <- sendMessage{data: data, compressed: compressed, enqueued: msgEnqueueTime, peerEnqueued: time.Now()}:
 		return true
 	default:
 	}
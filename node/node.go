@@ -40,6 +40,7 @@ import (
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/data/transactions/verify"
 	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/ledger/snapshot"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/network"
 	"github.com/algorand/go-algorand/node/indexer"
@@ -72,6 +73,8 @@ type StatusReport struct {
 	CatchpointCatchupProcessedAccounts uint64
 	CatchpointCatchupTotalBlocks       uint64
 	CatchpointCatchupAcquiredBlocks    uint64
+	EarliestBlockRound                 basics.Round // the oldest round from which on the node holds all the blocks.
+	BlockPruneTargetRound              basics.Round // the round before which the node is deleting the blocks it no longer keeps.
 }
 
 // TimeSinceLastRound returns the time since the last block was approved (locally), or 0 if no blocks seen
@@ -165,6 +168,19 @@ func MakeFull(log logging.Logger, rootDir string, cfg config.Local, phonebookAdd
 		return nil, err
 	}
 	p2pNode.SetPrioScheme(node)
+	if cfg.EnablePeerIdentity {
+		identity, err := network.LoadIdentity(filepath.Join(rootDir, config.PeerIdentityFilename))
+		if err != nil {
+			log.Errorf("could not load peer identity: %v", err)
+			return nil, err
+		}
+		p2pNode.SetIdentity(identity)
+	}
+	err = p2pNode.LoadPeerBans(filepath.Join(rootDir, config.PeerBansFilename))
+	if err != nil {
+		log.Errorf("could not load peer bans: %v", err)
+		return nil, err
+	}
 	node.net = p2pNode
 	node.accountManager = data.MakeAccountManager(log)
 
@@ -203,7 +219,7 @@ func MakeFull(log logging.Logger, rootDir string, cfg config.Local, phonebookAdd
 		blockListeners = append(blockListeners, &accountListener)
 	}
 	node.ledger.RegisterBlockListeners(blockListeners)
-	node.txHandler = data.MakeTxHandler(node.transactionPool, node.ledger, node.net, node.genesisID, node.genesisHash, node.lowPriorityCryptoVerificationPool)
+	node.txHandler = data.MakeTxHandler(node.transactionPool, node.ledger, node.net, node.genesisID, node.genesisHash, node.lowPriorityCryptoVerificationPool, cfg)
 	node.feeTracker, err = pools.MakeFeeTracker()
 	if err != nil {
 		log.Error(err)
@@ -311,6 +327,56 @@ func bootstrapData(genesis bookkeeping.Genesis, log logging.Logger) (data.Genesi
 	return data.MakeTimestampedGenesisBalances(genalloc, feeSink, rewardsPool, genesis.Timestamp), nil
 }
 
+// VerifyLedger verifies the ledger of the node whose data directory is rootDir,
+// and repairs it if asked to; see ledger.VerifyLedger. The node must not be
+// running.
+func VerifyLedger(log logging.Logger, rootDir string, cfg config.Local, genesis bookkeeping.Genesis, repair bool) (ledger.VerifyReport, error) {
+	genalloc, err := bootstrapData(genesis, log)
+	if err != nil {
+		return ledger.VerifyReport{}, err
+	}
+	genesisInitState, err := data.MakeGenesisInitState(genesis.Proto, genalloc, genesis.ID(), crypto.HashObj(genesis))
+	if err != nil {
+		return ledger.VerifyReport{}, err
+	}
+
+	ledgerPathnamePrefix := filepath.Join(rootDir, genesis.ID(), config.LedgerFilenamePrefix)
+	return ledger.VerifyLedger(log, ledgerPathnamePrefix, genesisInitState, cfg, repair)
+}
+
+// ExportLedger writes a snapshot of the accounts of the node whose data
+// directory is rootDir, at round rnd, to path in the given format; see
+// ledger.SnapshotAccounts. It returns the header of the snapshot and the
+// number of accounts it holds. The node may be running.
+func ExportLedger(rootDir string, genesis bookkeeping.Genesis, rnd basics.Round, path string, format snapshot.Format) (hdr snapshot.Header, accounts uint64, err error) {
+	var w snapshot.Writer
+	begin := func(round basics.Round, totals ledger.AccountTotals) (err error) {
+		hdr = snapshot.Header{
+			Version:      snapshot.Version,
+			GenesisID:    genesis.ID(),
+			GenesisHash:  crypto.HashObj(genesis),
+			Round:        round,
+			RewardsLevel: totals.RewardsLevel,
+		}
+		w, err = snapshot.Create(path, format, hdr)
+		return
+	}
+	visit := func(addr basics.Address, data basics.AccountData) error {
+		accounts++
+		return w.Write(addr, data)
+	}
+
+	ledgerPathnamePrefix := filepath.Join(rootDir, genesis.ID(), config.LedgerFilenamePrefix)
+	err = ledger.SnapshotAccounts(ledgerPathnamePrefix, rnd, begin, visit)
+	if w != nil {
+		closeErr := w.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return
+}
+
 // Config returns a copy of the node's Local configuration
 func (node *AlgorandFullNode) Config() config.Local {
 	return node.config
@@ -464,6 +530,15 @@ func (node *AlgorandFullNode) BroadcastSignedTxGroup(txgroup []transactions.Sign
 		enc = append(enc, protocol.Encode(&tx)...)
 		txids = append(txids, tx.ID())
 	}
+	if node.config.EnableTxnAnnouncements {
+		// the peers that negotiated announcements request the group from our pool once we announce it,
+		// the other ones get it in full below.
+		err = node.net.Broadcast(context.TODO(), protocol.TxnAnnouncementTag, txids[0][:], true, nil)
+		if err != nil {
+			node.log.Infof("failure announcing transaction to network: %v - transaction group was %+v", err, txgroup)
+			return err
+		}
+	}
 	err = node.net.Broadcast(context.TODO(), protocol.TxnTag, enc, true, nil)
 	if err != nil {
 		node.log.Infof("failure broadcasting transaction to network: %v - transaction group was %+v", err, txgroup)
@@ -622,6 +697,9 @@ func (node *AlgorandFullNode) Status() (s StatusReport, err error) {
 
 		s.StoppedAtUnsupportedRound = s.LastRound+1 == s.NextVersionRound && !s.NextVersionSupported
 		s.LastCatchpoint = node.ledger.GetLastCatchpointLabel()
+		retention := node.ledger.BlockRetentionStatus()
+		s.EarliestBlockRound = retention.EarliestRound
+		s.BlockPruneTargetRound = retention.PruneTarget
 		s.SynchronizingTime = node.catchupService.SynchronizingTime()
 		s.CatchupTime = node.catchupService.SynchronizingTime()
 	}
@@ -877,6 +955,14 @@ func (node *AlgorandFullNode) AbortCatchup(catchpoint string) error {
 	return nil
 }
 
+// PeerScores returns the scores of the gossip peers that misbehaved recently.
+func (node *AlgorandFullNode) PeerScores() []network.PeerScore {
+	if wn, ok := node.net.(*network.WebsocketNetwork); ok {
+		return wn.PeerScores()
+	}
+	return nil
+}
+
 // SetCatchpointCatchupMode change the node's operational mode from catchpoint catchup mode and back, it returns a
 // channel which contains the updated node context. This function need to work asyncronisly so that the caller could
 // detect and handle the usecase where the node is being shut down while we're switching to/from catchup mode without
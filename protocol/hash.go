@@ -27,24 +27,26 @@ const (
 	AuctionOutcomes   HashID = "aO"
 	AuctionParams     HashID = "aP"
 	AuctionSettlement HashID = "aS"
+	AppIndex          HashID = "appID"
 
-	AgreementSelector HashID = "AS"
-	BlockHeader       HashID = "BH"
-	BalanceRecord     HashID = "BR"
-	Credential        HashID = "CR"
-	Genesis           HashID = "GE"
-	Message           HashID = "MX"
-	NetPrioResponse   HashID = "NPR"
-	OneTimeSigKey1    HashID = "OT1"
-	OneTimeSigKey2    HashID = "OT2"
-	PaysetFlat        HashID = "PF"
-	Payload           HashID = "PL"
-	Program           HashID = "Program"
-	ProgramData       HashID = "ProgData"
-	ProposerSeed      HashID = "PS"
-	Seed              HashID = "SD"
-	TestHashable      HashID = "TE"
-	TxGroup           HashID = "TG"
-	Transaction       HashID = "TX"
-	Vote              HashID = "VO"
+	AgreementSelector    HashID = "AS"
+	BlockHeader          HashID = "BH"
+	BalanceRecord        HashID = "BR"
+	Credential           HashID = "CR"
+	Genesis              HashID = "GE"
+	Message              HashID = "MX"
+	NetIdentityChallenge HashID = "NIC"
+	NetPrioResponse      HashID = "NPR"
+	OneTimeSigKey1       HashID = "OT1"
+	OneTimeSigKey2       HashID = "OT2"
+	PaysetFlat           HashID = "PF"
+	Payload              HashID = "PL"
+	Program              HashID = "Program"
+	ProgramData          HashID = "ProgData"
+	ProposerSeed         HashID = "PS"
+	Seed                 HashID = "SD"
+	TestHashable         HashID = "TE"
+	TxGroup              HashID = "TG"
+	Transaction          HashID = "TX"
+	Vote                 HashID = "VO"
 )
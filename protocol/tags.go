@@ -22,21 +22,25 @@ type Tag string
 
 // Tags, in lexicographic sort order of tag values to avoid duplicates.
 const (
-	UnknownMsgTag      Tag = "??"
-	AgreementVoteTag   Tag = "AV"
-	MsgDigestSkipTag   Tag = "MS"
-	NetPrioResponseTag Tag = "NP"
-	PingTag            Tag = "pi"
-	PingReplyTag       Tag = "pj"
-	ProposalPayloadTag Tag = "PP"
-	TopicMsgRespTag    Tag = "TS"
-	MsgOfInterestTag   Tag = "MI"
-	TxnTag             Tag = "TX"
-	UniCatchupReqTag   Tag = "UC"
-	UniEnsBlockReqTag  Tag = "UE"
-	UniEnsBlockResTag  Tag = "US"
-	UniCatchupResTag   Tag = "UT"
-	VoteBundleTag      Tag = "VB"
+	UnknownMsgTag              Tag = "??"
+	AgreementVoteTag           Tag = "AV"
+	CompressedMsgTag           Tag = "CM"
+	MsgDigestSkipTag           Tag = "MS"
+	NetIdentityVerificationTag Tag = "NI"
+	NetPrioResponseTag         Tag = "NP"
+	PingTag                    Tag = "pi"
+	PingReplyTag               Tag = "pj"
+	ProposalPayloadTag         Tag = "PP"
+	TxnAnnouncementTag         Tag = "TA"
+	TxnRequestTag              Tag = "TR"
+	TopicMsgRespTag            Tag = "TS"
+	MsgOfInterestTag           Tag = "MI"
+	TxnTag                     Tag = "TX"
+	UniCatchupReqTag           Tag = "UC"
+	UniEnsBlockReqTag          Tag = "UE"
+	UniEnsBlockResTag          Tag = "US"
+	UniCatchupResTag           Tag = "UT"
+	VoteBundleTag              Tag = "VB"
 )
 
 // Complement is a convenience function for returning a corresponding response/request tag
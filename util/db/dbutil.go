@@ -71,6 +71,15 @@ func MakeErasableAccessor(dbfilename string) (Accessor, error) {
 	return makeAccessorImpl(dbfilename, false, false, []string{"_secure_delete=on"})
 }
 
+// MakeIncrementalVacuumAccessor creates a new Accessor with the auto_vacuum pragma set to
+// incremental, which lets the unused pages be freed by the incremental_vacuum pragma;
+// see https://www.sqlite.org/pragma.html#pragma_auto_vacuum
+// The setting only takes effect on a newly created database; EnableIncrementalVacuum
+// converts an existing one.
+func MakeIncrementalVacuumAccessor(dbfilename string, readOnly bool, inMemory bool) (Accessor, error) {
+	return makeAccessorImpl(dbfilename, readOnly, inMemory, []string{"_auto_vacuum=incremental", "_journal_mode=wal"})
+}
+
 func makeAccessorImpl(dbfilename string, readOnly bool, inMemory bool, params []string) (Accessor, error) {
 	var db Accessor
 	db.readOnly = readOnly
@@ -140,6 +149,44 @@ func (db *Accessor) Close() {
 	db.Handle = nil
 }
 
+// EnableIncrementalVacuum switches the auto_vacuum pragma of the database to
+// incremental if it isn't already. The pragma of an existing database can only
+// be changed by a VACUUM, which rebuilds the whole database file and may take a
+// while, so this is meant to run once, when the database is opened.
+// It returns whether the database was rebuilt.
+func (db *Accessor) EnableIncrementalVacuum(ctx context.Context) (vacuumed bool, err error) {
+	if db.readOnly {
+		return false, fmt.Errorf("EnableIncrementalVacuum: database is read-only")
+	}
+
+	// the pragma must be set on the connection that runs the VACUUM.
+	conn, err := db.Handle.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var autoVacuum int
+	err = conn.QueryRowContext(ctx, "PRAGMA auto_vacuum").Scan(&autoVacuum)
+	if err != nil {
+		return false, err
+	}
+	// 2 is the value of auto_vacuum=incremental.
+	if autoVacuum == 2 {
+		return false, nil
+	}
+
+	_, err = conn.ExecContext(ctx, "PRAGMA auto_vacuum=incremental")
+	if err != nil {
+		return false, err
+	}
+	_, err = conn.ExecContext(ctx, "VACUUM")
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // LoggedRetry executes a function repeatedly as long as it returns an error
 // that indicates database contention that warrants a retry.
 // Sends warnings and errors to log.
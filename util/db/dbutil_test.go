@@ -17,6 +17,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -333,3 +334,47 @@ func TestDBConcurrencyRW(t *testing.T) {
 	}
 
 }
+
+func TestEnableIncrementalVacuum(t *testing.T) {
+	fn := fmt.Sprintf("/tmp/%s.%d.sqlite3", t.Name(), crypto.RandUint64())
+	defer cleanupSqliteDb(t, fn)
+
+	autoVacuum := func(acc Accessor) (value int) {
+		err := acc.Atomic(func(tx *sql.Tx) error {
+			return tx.QueryRow("PRAGMA auto_vacuum").Scan(&value)
+		})
+		require.NoError(t, err)
+		return
+	}
+
+	// a database created without incremental vacuum keeps its setting.
+	acc, err := MakeAccessor(fn, false, false)
+	require.NoError(t, err)
+	err = acc.Atomic(func(tx *sql.Tx) error {
+		_, err := tx.Exec("create table Service (data blob)")
+		return err
+	})
+	require.NoError(t, err)
+	acc.Close()
+
+	acc, err = MakeIncrementalVacuumAccessor(fn, false, false)
+	require.NoError(t, err)
+	defer acc.Close()
+	require.Equal(t, 0, autoVacuum(acc))
+
+	vacuumed, err := acc.EnableIncrementalVacuum(context.Background())
+	require.NoError(t, err)
+	require.True(t, vacuumed)
+	require.Equal(t, 2, autoVacuum(acc))
+
+	// the conversion only happens once.
+	vacuumed, err = acc.EnableIncrementalVacuum(context.Background())
+	require.NoError(t, err)
+	require.False(t, vacuumed)
+
+	var nrows int
+	err = acc.Atomic(func(tx *sql.Tx) error {
+		return tx.QueryRow("select count(*) from Service").Scan(&nrows)
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package kvstore
+
+import (
+	"bytes"
+)
+
+// indexMaxLevel bounds the height of the skip list, which is plenty for
+// 2^32 keys.
+const indexMaxLevel = 32
+
+// entry locates the latest value of a key. The value is either held in
+// memory, when seg is nil, or stored in a segment file.
+type entry struct {
+	seg   *segment
+	off   int64
+	size  uint32
+	value []byte
+
+	// recordSize is the number of bytes of the segment the record of the
+	// value takes.
+	recordSize int64
+}
+
+type indexNode struct {
+	key  []byte
+	e    *entry
+	next []*indexNode
+}
+
+// index is a skip list of the entries of the keys, in key order.
+type index struct {
+	head  indexNode
+	level int
+	len   int
+	seed  uint64
+}
+
+func makeIndex() *index {
+	return &index{
+		head:  indexNode{next: make([]*indexNode, indexMaxLevel)},
+		level: 1,
+		seed:  0x9e3779b97f4a7c15,
+	}
+}
+
+func (ix *index) randomLevel() int {
+	// xorshift64
+	ix.seed ^= ix.seed << 13
+	ix.seed ^= ix.seed >> 7
+	ix.seed ^= ix.seed << 17
+	level := 1
+	for r := ix.seed; level < indexMaxLevel && r&3 == 0; r >>= 2 {
+		level++
+	}
+	return level
+}
+
+// findGreaterOrEqual returns the first node whose key is not less than key.
+// If prev is not nil, it receives the last node before it at each level.
+func (ix *index) findGreaterOrEqual(key []byte, prev []*indexNode) *indexNode {
+	x := &ix.head
+	for level := ix.level - 1; level >= 0; level-- {
+		for x.next[level] != nil && bytes.Compare(x.next[level].key, key) < 0 {
+			x = x.next[level]
+		}
+		if prev != nil {
+			prev[level] = x
+		}
+	}
+	return x.next[0]
+}
+
+// findLess returns the last node whose key is less than key, or the last
+// node if key is nil. It returns nil if there is none.
+func (ix *index) findLess(key []byte) *indexNode {
+	x := &ix.head
+	for level := ix.level - 1; level >= 0; level-- {
+		for x.next[level] != nil && (key == nil || bytes.Compare(x.next[level].key, key) < 0) {
+			x = x.next[level]
+		}
+	}
+	if x == &ix.head {
+		return nil
+	}
+	return x
+}
+
+func (ix *index) get(key []byte) *entry {
+	n := ix.findGreaterOrEqual(key, nil)
+	if n != nil && bytes.Equal(n.key, key) {
+		return n.e
+	}
+	return nil
+}
+
+// set makes e the entry of key, and returns the previous one, if any. The
+// index keeps key, which must not be modified afterwards.
+func (ix *index) set(key []byte, e *entry) *entry {
+	var prev [indexMaxLevel]*indexNode
+	n := ix.findGreaterOrEqual(key, prev[:])
+	if n != nil && bytes.Equal(n.key, key) {
+		old := n.e
+		n.e = e
+		return old
+	}
+
+	level := ix.randomLevel()
+	if level > ix.level {
+		for i := ix.level; i < level; i++ {
+			prev[i] = &ix.head
+		}
+		ix.level = level
+	}
+	n = &indexNode{key: key, e: e, next: make([]*indexNode, level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = prev[i].next[i]
+		prev[i].next[i] = n
+	}
+	ix.len++
+	return nil
+}
+
+// delete removes key from the index, and returns its entry, if any.
+func (ix *index) delete(key []byte) *entry {
+	var prev [indexMaxLevel]*indexNode
+	n := ix.findGreaterOrEqual(key, prev[:])
+	if n == nil || !bytes.Equal(n.key, key) {
+		return nil
+	}
+	for i := range n.next {
+		prev[i].next[i] = n.next[i]
+	}
+	for ix.level > 1 && ix.head.next[ix.level-1] == nil {
+		ix.level--
+	}
+	ix.len--
+	return n.e
+}
@@ -0,0 +1,316 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "kvstore")
+	require.NoError(t, err)
+	return dir
+}
+
+func put(t *testing.T, s *Store, kv ...string) {
+	err := s.Update(func(tx *Tx) error {
+		for i := 0; i < len(kv); i += 2 {
+			err := tx.Put([]byte(kv[i]), []byte(kv[i+1]))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// contents returns the keys and values of the store, in key order.
+func contents(t *testing.T, s *Store) (pairs []string) {
+	err := s.View(func(tx *Tx) error {
+		var err error
+		tx.Ascend(nil, nil, func(key []byte) bool {
+			var value []byte
+			value, err = tx.Get(key)
+			pairs = append(pairs, string(key), string(value))
+			return err == nil
+		})
+		return err
+	})
+	require.NoError(t, err)
+	return
+}
+
+func TestStoreBasic(t *testing.T) {
+	for _, inMemory := range []bool{false, true} {
+		t.Run(fmt.Sprintf("inMemory=%v", inMemory), func(t *testing.T) {
+			dir := tempDir(t)
+			defer os.RemoveAll(dir)
+			s, err := Open(dir, Options{InMemory: inMemory})
+			require.NoError(t, err)
+			defer s.Close()
+
+			put(t, s, "b", "2", "a", "1", "d", "4", "c", "3")
+			put(t, s, "c", "three")
+			err = s.Update(func(tx *Tx) error { return tx.Delete([]byte("d")) })
+			require.NoError(t, err)
+			require.Equal(t, []string{"a", "1", "b", "2", "c", "three"}, contents(t, s))
+			require.Equal(t, 3, s.Len())
+
+			err = s.View(func(tx *Tx) error {
+				_, err := tx.Get([]byte("d"))
+				require.Equal(t, ErrNotFound, err)
+				require.Equal(t, ErrReadOnly, tx.Put([]byte("x"), nil))
+
+				key, ok := tx.First([]byte("b"), nil)
+				require.True(t, ok)
+				require.Equal(t, "b", string(key))
+				key, ok = tx.Last([]byte("a"), []byte("c"))
+				require.True(t, ok)
+				require.Equal(t, "b", string(key))
+				key, ok = tx.Last(nil, nil)
+				require.True(t, ok)
+				require.Equal(t, "c", string(key))
+				_, ok = tx.First([]byte("c0"), nil)
+				require.False(t, ok)
+				_, ok = tx.Last([]byte("b0"), []byte("c"))
+				require.False(t, ok)
+				return nil
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestStoreRollback(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	s, err := Open(dir, Options{})
+	require.NoError(t, err)
+	defer s.Close()
+
+	put(t, s, "a", "1", "b", "2")
+	errAbort := errors.New("abort")
+	err = s.Update(func(tx *Tx) error {
+		require.NoError(t, tx.Put([]byte("a"), []byte("changed")))
+		require.NoError(t, tx.Put([]byte("c"), []byte("3")))
+		require.NoError(t, tx.Delete([]byte("b")))
+		value, err := tx.Get([]byte("a"))
+		require.NoError(t, err)
+		require.Equal(t, "changed", string(value))
+		require.False(t, tx.Has([]byte("b")))
+		return errAbort
+	})
+	require.Equal(t, errAbort, err)
+	require.Equal(t, []string{"a", "1", "b", "2"}, contents(t, s))
+
+	require.NoError(t, s.Close())
+	s, err = Open(dir, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "1", "b", "2"}, contents(t, s))
+}
+
+func TestStoreTornBatch(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	s, err := Open(dir, Options{})
+	require.NoError(t, err)
+	put(t, s, "a", "1")
+	put(t, s, "b", "2")
+	_, err = s.active().f.WriteAt([]byte("partial batch"), s.active().size)
+	require.NoError(t, err)
+	path := s.active().path
+	size := s.active().size
+	require.NoError(t, s.Close())
+
+	// a read-only store ignores the torn batch.
+	s, err = Open(dir, Options{ReadOnly: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "1", "b", "2"}, contents(t, s))
+	require.Equal(t, ErrReadOnly, s.Update(func(tx *Tx) error { return nil }))
+	require.NoError(t, s.Close())
+
+	// a writable one removes it.
+	s, err = Open(dir, Options{})
+	require.NoError(t, err)
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, size, fi.Size())
+	put(t, s, "c", "3")
+	require.NoError(t, s.Close())
+
+	// the last batch is lost if any of it is missing.
+	fi, err = os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, fi.Size()-1))
+	s, err = Open(dir, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "1", "b", "2"}, contents(t, s))
+	require.NoError(t, s.Close())
+}
+
+func TestStoreCompact(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	s, err := Open(dir, Options{SegmentSize: 4096, NoSync: true})
+	require.NoError(t, err)
+
+	value := make([]byte, 500)
+	for i := 0; i < 100; i++ {
+		err = s.Update(func(tx *Tx) error {
+			return tx.Put([]byte(fmt.Sprintf("%04d", i)), value)
+		})
+		require.NoError(t, err)
+	}
+	require.True(t, len(s.segments) > 10)
+
+	// the oldest keys go away, and so do the oldest segments.
+	err = s.Update(func(tx *Tx) error {
+		for i := 0; i < 80; i++ {
+			require.NoError(t, tx.Delete([]byte(fmt.Sprintf("%04d", i))))
+		}
+		// keep one of them, which is moved to the active segment.
+		return tx.Put([]byte("0010"), []byte("kept"))
+	})
+	require.NoError(t, err)
+
+	sizeBefore, garbage := s.DiskSize()
+	require.True(t, garbage > 80*500)
+	n, err := s.Compact(1000)
+	require.NoError(t, err)
+	require.True(t, n > 0)
+	sizeAfter, _ := s.DiskSize()
+	require.True(t, sizeAfter < sizeBefore-70*500)
+
+	expected := []string{"0010", "kept"}
+	for i := 80; i < 100; i++ {
+		expected = append(expected, fmt.Sprintf("%04d", i), string(value))
+	}
+	require.Equal(t, expected, contents(t, s))
+	require.NoError(t, s.Close())
+
+	s, err = Open(dir, Options{})
+	require.NoError(t, err)
+	require.Equal(t, expected, contents(t, s))
+	require.NoError(t, s.Close())
+}
+
+// TestStoreRandom runs random transactions against a store and a map, and
+// checks that they match, including after reopening and compacting the store.
+func TestStoreRandom(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	opts := Options{SegmentSize: 8192, NoSync: true}
+	s, err := Open(dir, opts)
+	require.NoError(t, err)
+	defer func() { s.Close() }()
+
+	rnd := rand.New(rand.NewSource(1))
+	model := make(map[string]string)
+	check := func() {
+		var expected []string
+		var keys []string
+		for k := range model {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			expected = append(expected, k, model[k])
+		}
+		require.Equal(t, expected, contents(t, s))
+	}
+
+	for i := 0; i < 500; i++ {
+		abort := rnd.Intn(5) == 0
+		changes := make(map[string]*string)
+		err = s.Update(func(tx *Tx) error {
+			for j := rnd.Intn(10); j >= 0; j-- {
+				key := fmt.Sprintf("k%03d", rnd.Intn(200))
+				if rnd.Intn(3) == 0 {
+					require.NoError(t, tx.Delete([]byte(key)))
+					changes[key] = nil
+				} else {
+					value := fmt.Sprintf("%d-%s", i, make([]byte, rnd.Intn(300)))
+					require.NoError(t, tx.Put([]byte(key), []byte(value)))
+					changes[key] = &value
+				}
+			}
+			if abort {
+				return errors.New("abort")
+			}
+			return nil
+		})
+		require.Equal(t, abort, err != nil)
+		if !abort {
+			for k, v := range changes {
+				if v == nil {
+					delete(model, k)
+				} else {
+					model[k] = *v
+				}
+			}
+		}
+
+		switch i % 100 {
+		case 50:
+			_, err = s.Compact(1000)
+			require.NoError(t, err)
+			check()
+		case 99:
+			require.NoError(t, s.Close())
+			s, err = Open(dir, opts)
+			require.NoError(t, err)
+			check()
+		}
+	}
+	check()
+}
+
+func TestStoreLock(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	s, err := Open(dir, Options{})
+	require.NoError(t, err)
+	put(t, s, "a", "1")
+
+	// a store that is written to can't be opened again.
+	_, err = Open(dir, Options{})
+	require.Equal(t, ErrLocked, err)
+	_, err = Open(dir, Options{ReadOnly: true})
+	require.Equal(t, ErrLocked, err)
+	require.NoError(t, s.Close())
+
+	// but it can be read by more than one reader.
+	r1, err := Open(dir, Options{ReadOnly: true})
+	require.NoError(t, err)
+	defer r1.Close()
+	r2, err := Open(dir, Options{ReadOnly: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "1"}, contents(t, r2))
+	_, err = Open(dir, Options{})
+	require.Equal(t, ErrLocked, err)
+	require.NoError(t, r2.Close())
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package kvstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFileName is the file of the directory of a store that the processes
+// which open it lock.
+const lockFileName = "LOCK"
+
+// ErrLocked is returned when opening a store that another process, or
+// another Store of this process, opened in a conflicting mode.
+var ErrLocked = errors.New("kvstore: store is in use")
+
+// lockDir locks the store of directory dir: shared if it is opened
+// read-only, and exclusive otherwise. A read-only store is not opened while
+// it is written to, since its segments could be compacted away meanwhile.
+// The lock is released by closing the returned file.
+func lockDir(dir string, readOnly bool) (*os.File, error) {
+	flag := os.O_RDWR | os.O_CREATE
+	how := unix.LOCK_EX
+	if readOnly {
+		flag = os.O_RDONLY | os.O_CREATE
+		how = unix.LOCK_SH
+	}
+	f, err := os.OpenFile(filepath.Join(dir, lockFileName), flag, 0600)
+	if err != nil {
+		return nil, err
+	}
+	err = unix.Flock(int(f.Fd()), how|unix.LOCK_NB)
+	if err != nil {
+		f.Close()
+		if err == unix.EWOULDBLOCK {
+			return nil, ErrLocked
+		}
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,237 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package kvstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// A segment file holds a sequence of batches. Each batch starts with a
+// header, followed by its metadata, which lists its operations and their
+// keys, and by its data, the values of its puts one after the other:
+//
+//	magic     uint32
+//	ops       uint32
+//	metaLen   uint32
+//	dataLen   uint64
+//	metaCRC   uint32
+//	dataCRC   uint32
+//	headerCRC uint32
+//
+// An operation of the metadata is an op byte, the uvarint length of the key,
+// the uvarint length of the value for a put, and the key. The integers are
+// little endian, and the checksums are CRC-32C.
+const (
+	batchMagic      = 0x6b766231 // "kvb1"
+	batchHeaderSize = 32
+
+	opPut    = 1
+	opDelete = 2
+)
+
+// segmentSuffix is the file name suffix of the segments.
+const segmentSuffix = ".seg"
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errTornBatch is reported by the scan of a segment that ends with an
+// incomplete or corrupt batch.
+var errTornBatch = errors.New("torn batch")
+
+type segment struct {
+	id   uint32
+	path string
+	f    *os.File
+	// size is the number of bytes of the segment, and live is the number
+	// of them that hold records of the latest values of their keys.
+	size int64
+	live int64
+}
+
+func segmentPath(dir string, id uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("%08d%s", id, segmentSuffix))
+}
+
+// batchOp is an operation of a scanned batch. The value of a put is at
+// offset off of the segment.
+type batchOp struct {
+	op         byte
+	key        []byte
+	off        int64
+	size       uint32
+	recordSize int64
+}
+
+// batchWriter encodes a batch.
+type batchWriter struct {
+	meta []byte
+	data []byte
+	ops  int
+}
+
+func (b *batchWriter) reset() {
+	b.meta = b.meta[:0]
+	b.data = b.data[:0]
+	b.ops = 0
+}
+
+// put adds a put to the batch, and returns the offset of the value from the
+// start of the data and the size of the record.
+func (b *batchWriter) put(key, value []byte) (dataOff int64, recordSize int64) {
+	metaLen := len(b.meta)
+	b.meta = append(b.meta, opPut)
+	b.meta = appendUvarint(b.meta, uint64(len(key)))
+	b.meta = appendUvarint(b.meta, uint64(len(value)))
+	b.meta = append(b.meta, key...)
+	dataOff = int64(len(b.data))
+	b.data = append(b.data, value...)
+	b.ops++
+	return dataOff, int64(len(b.meta)-metaLen) + int64(len(value))
+}
+
+func (b *batchWriter) delete(key []byte) {
+	b.meta = append(b.meta, opDelete)
+	b.meta = appendUvarint(b.meta, uint64(len(key)))
+	b.meta = append(b.meta, key...)
+	b.ops++
+}
+
+// encode returns the batch, and the offset of its data.
+func (b *batchWriter) encode() ([]byte, int64) {
+	buf := make([]byte, batchHeaderSize, batchHeaderSize+len(b.meta)+len(b.data))
+	binary.LittleEndian.PutUint32(buf[0:], batchMagic)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(b.ops))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(len(b.meta)))
+	binary.LittleEndian.PutUint64(buf[12:], uint64(len(b.data)))
+	binary.LittleEndian.PutUint32(buf[20:], crc32.Checksum(b.meta, crcTable))
+	binary.LittleEndian.PutUint32(buf[24:], crc32.Checksum(b.data, crcTable))
+	binary.LittleEndian.PutUint32(buf[28:], crc32.Checksum(buf[:28], crcTable))
+	buf = append(buf, b.meta...)
+	buf = append(buf, b.data...)
+	return buf, int64(batchHeaderSize + len(b.meta))
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+// scanSegment calls fn with the operations of the batches of a segment, one
+// batch at a time, and returns the size of its complete batches. The data
+// of the batches is only checked if checkData is set, since the segments
+// that were sealed were synced before any later write. If the segment ends
+// with a torn batch, it returns its offset along with errTornBatch.
+func scanSegment(f *os.File, size int64, checkData bool, fn func(ops []batchOp)) (int64, error) {
+	var off int64
+	var hdr [batchHeaderSize]byte
+	var ops []batchOp
+	for off < size {
+		if size-off < batchHeaderSize {
+			return off, errTornBatch
+		}
+		_, err := f.ReadAt(hdr[:], off)
+		if err != nil {
+			return off, err
+		}
+		if binary.LittleEndian.Uint32(hdr[0:]) != batchMagic || binary.LittleEndian.Uint32(hdr[28:]) != crc32.Checksum(hdr[:28], crcTable) {
+			return off, errTornBatch
+		}
+		count := binary.LittleEndian.Uint32(hdr[4:])
+		metaLen := int64(binary.LittleEndian.Uint32(hdr[8:]))
+		dataLen := int64(binary.LittleEndian.Uint64(hdr[12:]))
+		end := off + batchHeaderSize + metaLen + dataLen
+		if dataLen < 0 || end > size {
+			return off, errTornBatch
+		}
+
+		meta := make([]byte, metaLen)
+		_, err = f.ReadAt(meta, off+batchHeaderSize)
+		if err != nil {
+			return off, err
+		}
+		if crc32.Checksum(meta, crcTable) != binary.LittleEndian.Uint32(hdr[20:]) {
+			return off, errTornBatch
+		}
+		if checkData {
+			crc := crc32.New(crcTable)
+			_, err = io.Copy(crc, io.NewSectionReader(f, off+batchHeaderSize+metaLen, dataLen))
+			if err != nil {
+				return off, err
+			}
+			if crc.Sum32() != binary.LittleEndian.Uint32(hdr[24:]) {
+				return off, errTornBatch
+			}
+		}
+
+		ops, err = decodeBatchMeta(meta, count, off+batchHeaderSize+metaLen, dataLen, ops[:0])
+		if err != nil {
+			return off, fmt.Errorf("batch at offset %d: %v", off, err)
+		}
+		fn(ops)
+		off = end
+	}
+	return off, nil
+}
+
+func decodeBatchMeta(meta []byte, count uint32, dataOff int64, dataLen int64, ops []batchOp) ([]batchOp, error) {
+	dataEnd := dataOff + dataLen
+	for i := uint32(0); i < count; i++ {
+		if len(meta) == 0 {
+			return nil, fmt.Errorf("truncated metadata")
+		}
+		op := batchOp{op: meta[0]}
+		n := 1
+		keyLen, m := binary.Uvarint(meta[n:])
+		if m <= 0 {
+			return nil, fmt.Errorf("bad key length")
+		}
+		n += m
+		switch op.op {
+		case opPut:
+			valueLen, m := binary.Uvarint(meta[n:])
+			if m <= 0 || valueLen > uint64(dataEnd-dataOff) {
+				return nil, fmt.Errorf("bad value length")
+			}
+			n += m
+			op.off = dataOff
+			op.size = uint32(valueLen)
+			dataOff += int64(valueLen)
+		case opDelete:
+		default:
+			return nil, fmt.Errorf("unknown operation %d", op.op)
+		}
+		if keyLen > uint64(len(meta)-n) {
+			return nil, fmt.Errorf("bad key length")
+		}
+		op.key = meta[n : n+int(keyLen)]
+		n += int(keyLen)
+		op.recordSize = int64(n) + int64(op.size)
+		meta = meta[n:]
+		ops = append(ops, op)
+	}
+	if len(meta) != 0 || dataOff != dataEnd {
+		return nil, fmt.Errorf("metadata doesn't match the batch")
+	}
+	return ops, nil
+}
@@ -0,0 +1,474 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package kvstore implements an embedded, ordered key-value store in pure Go.
+//
+// The store is log-structured: the changes committed by each transaction are
+// appended as a single batch to the latest of a sequence of segment files,
+// and an in-memory index keeps, in key order, where the latest value of each
+// key is stored. The values are read from the segment files when they are
+// looked up, so the memory used by the store is proportional to the number
+// of keys and not to the size of their values. Opening a store replays its
+// segments to rebuild the index; a batch that was not completely written
+// when the process stopped is discarded, so that each transaction is either
+// entirely committed or not at all.
+//
+// The records of the deleted or overwritten values are reclaimed by Compact,
+// which rewrites the oldest segments. Compaction suits data whose oldest
+// keys are the ones that go away, like a log of blocks that gets pruned.
+//
+// A store is opened by a single writer, or by any number of readers: the
+// directory of the store is locked to ensure it, and Open fails with
+// ErrLocked otherwise.
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultSegmentSize is the size from which on a segment is sealed and the
+// batches are appended to a new one.
+const DefaultSegmentSize = 64 << 20
+
+// compactionBatchSize is the size of the batches that copy the live records
+// of a segment being compacted.
+const compactionBatchSize = 4 << 20
+
+var (
+	// ErrNotFound is returned when looking up a key that is not in the store.
+	ErrNotFound = errors.New("kvstore: key not found")
+	// ErrReadOnly is returned when writing to a read-only store or
+	// transaction.
+	ErrReadOnly = errors.New("kvstore: read-only")
+	// ErrClosed is returned when using a closed store.
+	ErrClosed = errors.New("kvstore: store is closed")
+)
+
+// Options are the settings of a Store.
+type Options struct {
+	// ReadOnly opens an existing store, which can only be read.
+	ReadOnly bool
+
+	// InMemory keeps the store in memory; its directory is ignored.
+	InMemory bool
+
+	// SegmentSize overrides DefaultSegmentSize.
+	SegmentSize int64
+
+	// NoSync doesn't sync the segments to disk when committing. The store
+	// stays consistent after a crash of the process, but not after a crash
+	// of the system.
+	NoSync bool
+}
+
+// Store is a key-value store. Its transactions may run concurrently, except
+// for the read-write ones, which run one at a time and exclude any other.
+type Store struct {
+	dir  string
+	opts Options
+
+	// lock holds the lock of the directory of the store.
+	lock *os.File
+
+	mu       sync.RWMutex
+	index    *index
+	segments []*segment
+	closed   bool
+	// err is set when a failed write could not be undone; the store can
+	// only be read afterwards.
+	err error
+}
+
+// Open opens the store of directory dir, creating it if it doesn't exist
+// and the store isn't read-only.
+func Open(dir string, opts Options) (*Store, error) {
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = DefaultSegmentSize
+	}
+	s := &Store{
+		dir:   dir,
+		opts:  opts,
+		index: makeIndex(),
+	}
+	if opts.InMemory {
+		return s, nil
+	}
+
+	if !opts.ReadOnly {
+		err := os.MkdirAll(dir, 0700)
+		if err != nil {
+			return nil, err
+		}
+	}
+	lock, err := lockDir(dir, opts.ReadOnly)
+	if err != nil {
+		return nil, err
+	}
+	s.lock = lock
+
+	ids, err := listSegments(dir)
+	if err != nil {
+		s.closeSegments()
+		return nil, err
+	}
+	for i, id := range ids {
+		err = s.openSegment(id, i == len(ids)-1)
+		if err != nil {
+			s.closeSegments()
+			return nil, err
+		}
+	}
+	if len(s.segments) == 0 && !opts.ReadOnly {
+		err = s.createSegment(1)
+		if err != nil {
+			s.closeSegments()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func listSegments(dir string) ([]uint32, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint32
+	for _, fi := range files {
+		name := fi.Name()
+		if fi.IsDir() || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 32)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint32(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// openSegment opens a segment and applies its batches to the index. The
+// torn batch the last segment may end with is discarded.
+func (s *Store) openSegment(id uint32, last bool) error {
+	seg := &segment{id: id, path: segmentPath(s.dir, id)}
+	flag := os.O_RDWR
+	if s.opts.ReadOnly || !last {
+		flag = os.O_RDONLY
+	}
+	f, err := os.OpenFile(seg.path, flag, 0)
+	if err != nil {
+		return err
+	}
+	seg.f = f
+	s.segments = append(s.segments, seg)
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size, err := scanSegment(f, fi.Size(), last, func(ops []batchOp) {
+		for _, op := range ops {
+			key := append([]byte(nil), op.key...)
+			var prev *entry
+			if op.op == opPut {
+				prev = s.index.set(key, &entry{seg: seg, off: op.off, size: op.size, recordSize: op.recordSize})
+				seg.live += op.recordSize
+			} else {
+				prev = s.index.delete(key)
+			}
+			if prev != nil {
+				prev.seg.live -= prev.recordSize
+			}
+		}
+	})
+	if err == errTornBatch && last {
+		if !s.opts.ReadOnly {
+			err = f.Truncate(size)
+			if err == nil {
+				err = s.sync(f)
+			}
+		} else {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("kvstore: segment %s: %v", seg.path, err)
+	}
+	seg.size = size
+	return nil
+}
+
+// createSegment adds a new segment, to which the batches are appended.
+func (s *Store) createSegment(id uint32) error {
+	path := segmentPath(s.dir, id)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	err = s.syncDir()
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	s.segments = append(s.segments, &segment{id: id, path: path, f: f})
+	return nil
+}
+
+func (s *Store) sync(f *os.File) error {
+	if s.opts.NoSync {
+		return nil
+	}
+	return f.Sync()
+}
+
+func (s *Store) syncDir() error {
+	if s.opts.NoSync {
+		return nil
+	}
+	d, err := os.Open(s.dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// closeSegments closes the files of the store, and releases the lock of its
+// directory.
+func (s *Store) closeSegments() {
+	for _, seg := range s.segments {
+		seg.f.Close()
+	}
+	s.segments = nil
+	if s.lock != nil {
+		s.lock.Close()
+		s.lock = nil
+	}
+}
+
+// Close closes the store, waiting for its transactions to finish.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.closeSegments()
+	s.index = makeIndex()
+	return nil
+}
+
+// View runs fn in a read-only transaction.
+func (s *Store) View(fn func(tx *Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	return fn(&Tx{s: s})
+}
+
+// Update runs fn in a read-write transaction. Its changes are committed if
+// fn returns nil, and discarded otherwise.
+func (s *Store) Update(fn func(tx *Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if s.opts.ReadOnly {
+		return ErrReadOnly
+	}
+	if s.err != nil {
+		return s.err
+	}
+
+	tx := &Tx{s: s, writable: true}
+	err := fn(tx)
+	if err != nil {
+		tx.rollback()
+		return err
+	}
+	return tx.commit()
+}
+
+// Len returns the number of keys of the store.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.len
+}
+
+// DiskSize returns the number of bytes of the segments, and the number of
+// them that Compact could reclaim.
+func (s *Store) DiskSize() (size int64, garbage int64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, seg := range s.segments {
+		size += seg.size
+		garbage += seg.size - seg.live
+	}
+	return
+}
+
+// active returns the segment the batches are appended to.
+func (s *Store) active() *segment {
+	return s.segments[len(s.segments)-1]
+}
+
+// append writes a batch at the end of the active segment, sealing it first
+// if the batch would make it outgrow the segment size. It returns the
+// segment and the offset the batch was written at.
+func (s *Store) append(batch []byte) (*segment, int64, error) {
+	seg := s.active()
+	if seg.size > 0 && seg.size+int64(len(batch)) > s.opts.SegmentSize {
+		err := s.createSegment(seg.id + 1)
+		if err != nil {
+			return nil, 0, err
+		}
+		seg = s.active()
+	}
+
+	off := seg.size
+	_, err := seg.f.WriteAt(batch, off)
+	if err == nil {
+		err = s.sync(seg.f)
+	}
+	if err != nil {
+		// drop what may have been written, so that the next batches
+		// aren't appended after a torn one.
+		terr := seg.f.Truncate(off)
+		if terr != nil {
+			s.err = fmt.Errorf("kvstore: unable to recover from failed write (%v): %v", err, terr)
+		}
+		return nil, 0, err
+	}
+	seg.size += int64(len(batch))
+	return seg, off, nil
+}
+
+// Compact reclaims the space of the deleted and overwritten values of the
+// oldest sealed segments, as long as at least half of a segment is garbage
+// and up to maxSegments of them. The values of a compacted segment that are
+// still live are appended to the active segment. It returns the number of
+// segments that were compacted.
+func (s *Store) Compact(maxSegments int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, ErrClosed
+	}
+	if s.opts.ReadOnly || s.opts.InMemory {
+		return 0, nil
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	compacted := 0
+	for compacted < maxSegments && len(s.segments) > 1 {
+		seg := s.segments[0]
+		if seg.live*2 > seg.size {
+			break
+		}
+		err := s.compactSegment(seg)
+		if err != nil {
+			return compacted, err
+		}
+		compacted++
+	}
+	return compacted, nil
+}
+
+// compactSegment copies the live values of the oldest segment to the active
+// one, and removes it. Its tombstones can be dropped, since the values they
+// deleted were in the same segment or in older ones that are gone already.
+func (s *Store) compactSegment(seg *segment) error {
+	var live []batchOp
+	_, err := scanSegment(seg.f, seg.size, false, func(ops []batchOp) {
+		for _, op := range ops {
+			e := s.index.get(op.key)
+			if op.op == opPut && e != nil && e.seg == seg && e.off == op.off {
+				op.key = append([]byte(nil), op.key...)
+				live = append(live, op)
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("kvstore: compacting segment %s: %v", seg.path, err)
+	}
+
+	var b batchWriter
+	var moved []batchOp
+	flush := func() error {
+		if b.ops == 0 {
+			return nil
+		}
+		buf, dataOff := b.encode()
+		dst, off, err := s.append(buf)
+		if err != nil {
+			return err
+		}
+		for _, op := range moved {
+			e := &entry{seg: dst, off: off + dataOff + op.off, size: op.size, recordSize: op.recordSize}
+			s.index.set(op.key, e)
+			dst.live += op.recordSize
+			seg.live -= op.recordSize
+		}
+		b.reset()
+		moved = moved[:0]
+		return nil
+	}
+	for _, op := range live {
+		value := make([]byte, op.size)
+		_, err = seg.f.ReadAt(value, op.off)
+		if err != nil {
+			return err
+		}
+		// op.off becomes the offset of the value in the data of the batch.
+		op.off, op.recordSize = b.put(op.key, value)
+		moved = append(moved, op)
+		if len(b.data) >= compactionBatchSize {
+			err = flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = flush()
+	if err != nil {
+		return err
+	}
+
+	seg.f.Close()
+	s.segments = s.segments[1:]
+	err = os.Remove(seg.path)
+	if err != nil {
+		return err
+	}
+	return s.syncDir()
+}
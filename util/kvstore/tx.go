@@ -0,0 +1,176 @@
+// Copyright (C) 2019-2020 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package kvstore
+
+import (
+	"bytes"
+)
+
+// Tx is a transaction of a Store. It is only valid during the function it
+// is passed to. The writes of a read-write transaction are visible to its
+// reads right away, and to the other transactions once it commits.
+type Tx struct {
+	s        *Store
+	writable bool
+
+	// ops are the writes of the transaction, in order, along with the
+	// entries they replaced, to undo them if it's rolled back.
+	ops []txOp
+}
+
+type txOp struct {
+	key  []byte
+	e    *entry
+	prev *entry
+}
+
+// Get returns the value of key, or ErrNotFound.
+func (tx *Tx) Get(key []byte) ([]byte, error) {
+	e := tx.s.index.get(key)
+	if e == nil {
+		return nil, ErrNotFound
+	}
+	value := make([]byte, e.size)
+	if e.seg == nil {
+		copy(value, e.value)
+		return value, nil
+	}
+	_, err := e.seg.f.ReadAt(value, e.off)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Has tells whether key is in the store.
+func (tx *Tx) Has(key []byte) bool {
+	return tx.s.index.get(key) != nil
+}
+
+// Put sets the value of key.
+func (tx *Tx) Put(key, value []byte) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+	key = append([]byte(nil), key...)
+	e := &entry{
+		value: append([]byte{}, value...),
+		size:  uint32(len(value)),
+	}
+	prev := tx.s.index.set(key, e)
+	tx.ops = append(tx.ops, txOp{key: key, e: e, prev: prev})
+	return nil
+}
+
+// Delete removes key, if it's in the store.
+func (tx *Tx) Delete(key []byte) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+	prev := tx.s.index.delete(key)
+	if prev != nil {
+		tx.ops = append(tx.ops, txOp{key: append([]byte(nil), key...), prev: prev})
+	}
+	return nil
+}
+
+// Ascend calls fn with the keys in [start, end), in increasing order, until
+// fn returns false. A nil end has no upper bound. The keys must not be
+// modified, and fn must not write to the transaction.
+func (tx *Tx) Ascend(start, end []byte, fn func(key []byte) bool) {
+	for n := tx.s.index.findGreaterOrEqual(start, nil); n != nil; n = n.next[0] {
+		if end != nil && bytes.Compare(n.key, end) >= 0 {
+			return
+		}
+		if !fn(n.key) {
+			return
+		}
+	}
+}
+
+// First returns the smallest key in [start, end), if there is one. A nil
+// end has no upper bound.
+func (tx *Tx) First(start, end []byte) (key []byte, ok bool) {
+	n := tx.s.index.findGreaterOrEqual(start, nil)
+	if n == nil || (end != nil && bytes.Compare(n.key, end) >= 0) {
+		return nil, false
+	}
+	return append([]byte(nil), n.key...), true
+}
+
+// Last returns the largest key in [start, end), if there is one. A nil end
+// has no upper bound.
+func (tx *Tx) Last(start, end []byte) (key []byte, ok bool) {
+	n := tx.s.index.findLess(end)
+	if n == nil || bytes.Compare(n.key, start) < 0 {
+		return nil, false
+	}
+	return append([]byte(nil), n.key...), true
+}
+
+// rollback undoes the writes of the transaction.
+func (tx *Tx) rollback() {
+	for i := len(tx.ops) - 1; i >= 0; i-- {
+		op := tx.ops[i]
+		if op.prev != nil {
+			tx.s.index.set(op.key, op.prev)
+		} else {
+			tx.s.index.delete(op.key)
+		}
+	}
+	tx.ops = nil
+}
+
+// commit appends the writes of the transaction to the active segment as a
+// single batch, and points the entries of its values to the segment.
+func (tx *Tx) commit() error {
+	if len(tx.ops) == 0 || tx.s.opts.InMemory {
+		return nil
+	}
+
+	var b batchWriter
+	dataOffs := make([]int64, len(tx.ops))
+	recordSizes := make([]int64, len(tx.ops))
+	for i, op := range tx.ops {
+		if op.e != nil {
+			dataOffs[i], recordSizes[i] = b.put(op.key, op.e.value)
+		} else {
+			b.delete(op.key)
+		}
+	}
+	buf, dataOff := b.encode()
+	seg, off, err := tx.s.append(buf)
+	if err != nil {
+		tx.rollback()
+		return err
+	}
+
+	for i, op := range tx.ops {
+		if op.prev != nil && op.prev.seg != nil {
+			op.prev.seg.live -= op.prev.recordSize
+		}
+		if op.e != nil {
+			op.e.seg = seg
+			op.e.off = off + dataOff + dataOffs[i]
+			op.e.recordSize = recordSizes[i]
+			op.e.value = nil
+			seg.live += recordSizes[i]
+		}
+	}
+	tx.ops = nil
+	return nil
+}